@@ -3,12 +3,12 @@ package api
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 
 	db "github.com/ankurdas111111/simplebank/db/sqlc"
 	"github.com/ankurdas111111/simplebank/token"
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
 )
 
 
@@ -21,10 +21,27 @@ func (server *Server) createAccount(ctx *gin.Context){
 	var req createAccountRequest
 	err:= ctx.ShouldBindJSON(&req); 
 	if err!=nil{
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
 		return 
 	}
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	// The tenant may offer fewer currencies than the bank supports overall.
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	supported, err := server.settings.CurrencySupported(ctx, user.Tenant, req.Currency)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if !supported {
+		ctx.JSON(errorResponse(http.StatusBadRequest, fmt.Errorf("currency %s is not offered", req.Currency)))
+		return
+	}
+
 	arg := db.CreateAccountParams{
 		Owner: authPayload.Username,
 		Currency: req.Currency,
@@ -32,14 +49,7 @@ func (server *Server) createAccount(ctx *gin.Context){
 	}
 	account, err := server.store.CreateAccount(ctx,arg)
 	if err!= nil{
-		if pqErr, ok := err.(*pq.Error); ok{
-			switch pqErr.Code.Name(){
-			case "foreign_key_violation", "unique_violation":
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
-				return
-			}
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(storeErrorResponse(err))
 		return
 	}
 	ctx.JSON(http.StatusOK,account)
@@ -53,54 +63,70 @@ func (server *Server) getAccount(ctx *gin.Context){
 	var req getAccountRequest
 	err := ctx.ShouldBindUri(&req)
 	if err != nil{
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
 		return
 	}
 	account, err := server.store.GetAccount(ctx, req.ID)
 	if err != nil {
 		if err == sql.ErrNoRows{
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
 		return
 	}
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	if account.Owner != authPayload.Username{
 		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusUnauthorized, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK,account)
+	pots, err := server.store.ListPots(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
 
-}
+	// Polling clients send back the ETag and get an empty 304 until the
+	// account changes. The owner check above must come first.
+	etag := accountETag(account, pots)
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "private, no-cache")
+	if etagMatches(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponse(account, pots))
 
-type listAccountRequest struct{
-	PageID int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
 }
 
 func (server *Server) listAccount(ctx *gin.Context){
-	var req listAccountRequest
+	var req cursorPageRequest
 	err := ctx.ShouldBindQuery(&req)
 	if err != nil{
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	afterID, err := req.lastID()
+	if err != nil{
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
 		return
 	}
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	arg := db.ListAccountsParams{
+	arg := db.ListAccountsAfterParams{
 		Owner: authPayload.Username,
-		Limit: req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		AfterID: afterID,
+		PageLimit: req.limit() + 1,
 	}
-	account, err := server.store.ListAccounts(ctx, arg)
+	accounts, err := server.store.ListAccountsAfter(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK,account)
+	ctx.JSON(http.StatusOK, newListResponse(accounts, req.limit(), func(account db.Account) int64 { return account.ID }))
 
 }
\ No newline at end of file
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+var errSweepAccount = errors.New("sweep_to_account_id must be another account of yours in the same currency")
+
+type closeAccountRequest struct {
+	// Required unless the balance is zero.
+	SweepToAccountID int64 `form:"sweep_to_account_id" binding:"omitempty,min=1"`
+}
+
+// closeAccount moves what is left on an account to another account of the
+// same owner and closes it. Closed accounts disappear from the account list
+// but can still be read, with their entries and transfers.
+func (server *Server) closeAccount(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req closeAccountRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("account doesn't belong to the authenticated user")))
+		return
+	}
+
+	// Sweeping to someone else would be a transfer without the checks
+	// transfers get, such as step-up.
+	if req.SweepToAccountID != 0 {
+		if req.SweepToAccountID == account.ID {
+			ctx.JSON(errorResponse(http.StatusBadRequest, errSweepAccount))
+			return
+		}
+		sweepTo, err := server.store.GetAccount(ctx, req.SweepToAccountID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.JSON(errorResponse(http.StatusNotFound, err))
+				return
+			}
+			ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+			return
+		}
+		if sweepTo.Owner != account.Owner || sweepTo.Currency != account.Currency {
+			ctx.JSON(errorResponse(http.StatusBadRequest, errSweepAccount))
+			return
+		}
+	}
+
+	result, err := server.store.CloseAccountTx(ctx, db.CloseAccountTxParams{
+		AccountID:        account.ID,
+		SweepToAccountID: req.SweepToAccountID,
+		ClosedBy:         authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// eventKeepAlive is how often an idle stream gets a comment line, so proxies
+// and load balancers don't close it.
+const eventKeepAlive = 15 * time.Second
+
+// streamAccountEvents pushes balance changes and incoming transfers of an
+// account as server-sent events until the client disconnects. Only events
+// committed after the client connected are sent; clients should read the
+// account once connected to get a starting balance.
+func (server *Server) streamAccountEvents(ctx *gin.Context) {
+	var req getAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("account doesn't belong to the authenticated user")))
+		return
+	}
+
+	updates, cancel := server.events.Subscribe(account.ID)
+	defer cancel()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// Stops nginx from buffering the stream.
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	// Send the headers now, so the client knows it is subscribed.
+	fmt.Fprint(ctx.Writer, ": connected\n\n")
+	ctx.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case <-server.shutdown:
+			// The client reconnects to another instance.
+			return false
+		case event, ok := <-updates:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Type, event)
+			return true
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			return true
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/events"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamAccountEventsAPI(t *testing.T) {
+	account := randomAccount()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+	server := newTestServer(t, store)
+
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	reqCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(reqCtx, http.MethodGet, fmt.Sprintf("%s/api/accounts/%d/events", httpServer.URL, account.ID), nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, account.Owner, time.Minute)
+
+	response, err := httpServer.Client().Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	// The headers arrive once the stream has subscribed.
+	published := events.Event{Type: events.TransferReceived, AccountID: account.ID, Balance: 150, Amount: 50, TransferID: 3}
+	server.events.Publish(events.Event{Type: events.TransferReceived, AccountID: account.ID + 1})
+	server.events.Publish(published)
+
+	reader := bufio.NewReader(response.Body)
+	var name, data string
+	for data == "" {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			name = line[len("event:"):]
+		case strings.HasPrefix(line, "data:"):
+			data = line[len("data:"):]
+		}
+	}
+	require.Equal(t, events.TransferReceived, name)
+
+	var got events.Event
+	require.NoError(t, json.Unmarshal([]byte(data), &got))
+	require.Equal(t, published.AccountID, got.AccountID)
+	require.Equal(t, published.Balance, got.Balance)
+	require.Equal(t, published.TransferID, got.TransferID)
+}
+
+func TestStreamAccountEventsAPIErrors(t *testing.T) {
+	account := randomAccount()
+
+	testCases := []struct {
+		name       string
+		username   string
+		buildStubs func(store *mockdb.MockStore)
+		wantStatus int
+	}{
+		{
+			name:     "NotFound",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:     "NotOwner",
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts/%d/events", account.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.wantStatus, recorder.Code)
+		})
+	}
+}
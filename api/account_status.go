@@ -0,0 +1,93 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// freezeAccount lets owners stop all money movement on an account, e.g. after
+// losing a device. Only an admin can lift the freeze again.
+func (server *Server) freezeAccount(ctx *gin.Context) {
+	var req getAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("account doesn't belong to the authenticated user")))
+		return
+	}
+
+	server.setAccountStatus(ctx, account, db.AccountFrozen, authPayload.Username)
+}
+
+func (server *Server) unfreezeAccount(ctx *gin.Context) {
+	var req getAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	server.setAccountStatus(ctx, account, db.AccountActive, authPayload.Username)
+}
+
+// setAccountStatus records the change in the account's standing data history
+// and responds with the updated account. Setting the current status again is
+// a no-op. Closed accounts stay closed.
+func (server *Server) setAccountStatus(ctx *gin.Context, account db.Account, status, changedBy string) {
+	if account.Status == db.AccountClosed {
+		ctx.JSON(errorResponse(http.StatusLocked, db.ErrAccountClosed))
+		return
+	}
+	if account.Status == status {
+		ctx.JSON(http.StatusOK, account)
+		return
+	}
+
+	_, err := server.store.UpdateStandingDataTx(ctx, db.UpdateStandingDataTxParams{
+		EntityType: db.StandingDataAccount,
+		EntityID:   db.AccountEntityID(account.ID),
+		Field:      "status",
+		NewValue:   status,
+		ChangedBy:  changedBy,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	account, err = server.store.GetAccount(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	ctx.JSON(http.StatusOK, account)
+}
@@ -13,19 +13,22 @@ import (
 
 	mockdb "github.com/ankurdas111111/simplebank/db/mock"
 	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
 	"github.com/ankurdas111111/simplebank/util"
 	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
 func addAuthorization(
 	t *testing.T,
 	request *http.Request,
-	tokenMaker interface{ CreateToken(username string, duration time.Duration) (string, error) },
+	tokenMaker token.Maker,
 	username string,
 	duration time.Duration,
+	opts ...token.PayloadOption,
 ) {
-	token, err := tokenMaker.CreateToken(username, duration)
+	token, err := tokenMaker.CreateToken(username, duration, opts...)
 	require.NoError(t, err)
 	request.Header.Set("authorization", fmt.Sprintf("Bearer %s", token))
 }
@@ -47,6 +50,9 @@ func TestGetAccountAPI(t *testing.T){
 				store.EXPECT().GetAccount(gomock.Any(),gomock.Eq(account.ID)).
 				Times(1).
 				Return(account,nil)
+				store.EXPECT().ListPots(gomock.Any(),gomock.Eq(account.ID)).
+				Times(1).
+				Return(nil,nil)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
 				addAuthorization(t, request, server.tokenMaker, account.Owner, time.Minute)
@@ -153,4 +159,87 @@ func requireBodyMatchAccount(t *testing.T, body *bytes.Buffer, account db.Accoun
 	err = json.Unmarshal(data, &gotAccount)
 	require.NoError(t,err)
 	require.Equal(t, account, gotAccount)
-}
\ No newline at end of file
+}
+func TestCreateAccountAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := db.Account{ID: 1, Owner: user.Username, Currency: util.INR}
+
+	testCases := []struct {
+		name          string
+		currency      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			currency: util.INR,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Eq(db.CreateAccountParams{
+					Owner:    user.Username,
+					Currency: util.INR,
+				})).
+					Times(1).
+					Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name:     "AlreadyHasCurrency",
+			currency: util.INR,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, &pq.Error{Code: "23505", Constraint: "owner_currency_key", Message: "duplicate key"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+
+				var got apiError
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, errCodeAlreadyExists, got.Code)
+				require.Equal(t, "an account in this currency already exists", got.Message)
+			},
+		},
+		{
+			name:     "InvalidCurrency",
+			currency: "XYZ",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().ListSettings(gomock.Any()).AnyTimes().Return([]db.Setting{}, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(map[string]string{"currency": tc.currency})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/accounts", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// periodLayout is how periods appear in URLs and request bodies, e.g. 2024-03.
+const periodLayout = "2006-01"
+
+type closePeriodRequest struct {
+	Period string `json:"period" binding:"required"`
+}
+
+// closePeriod freezes posting into a month that has ended and returns its
+// period-end report.
+func (server *Server) closePeriod(ctx *gin.Context) {
+	var req closePeriodRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	period, err := time.Parse(periodLayout, req.Period)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.store.ClosePeriodTx(ctx, db.ClosePeriodTxParams{
+		Period:   period,
+		ClosedBy: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrPeriodNotEnded) {
+			ctx.JSON(errorResponse(http.StatusBadRequest, err))
+			return
+		}
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type listPeriodsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listPeriods returns the closed periods, most recent first.
+func (server *Server) listPeriods(ctx *gin.Context) {
+	var req listPeriodsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	periods, err := server.store.ListAccountingPeriods(ctx, db.ListAccountingPeriodsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, periods)
+}
+
+type periodRequest struct {
+	Period string `uri:"period" binding:"required"`
+}
+
+// bindPeriod reads the :period URI parameter and writes the error response if
+// it is missing or malformed.
+func bindPeriod(ctx *gin.Context) (time.Time, bool) {
+	var req periodRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return time.Time{}, false
+	}
+
+	period, err := time.Parse(periodLayout, req.Period)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return time.Time{}, false
+	}
+	return period, true
+}
+
+// getPeriodReport regenerates the period-end report of a closed period.
+func (server *Server) getPeriodReport(ctx *gin.Context) {
+	period, ok := bindPeriod(ctx)
+	if !ok {
+		return
+	}
+
+	_, err := server.store.GetAccountingPeriod(ctx, period)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, db.ErrPeriodNotClosed))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	report, err := server.store.GetPeriodReport(ctx, period)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+type postAdjustmentRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+	// Amount is signed: negative debits the account, positive credits it.
+	Amount int64 `json:"amount" binding:"required"`
+}
+
+// postAdjustment posts a late correction against a closed period.
+func (server *Server) postAdjustment(ctx *gin.Context) {
+	period, ok := bindPeriod(ctx)
+	if !ok {
+		return
+	}
+
+	var req postAdjustmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	result, err := server.store.PostAdjustmentTx(ctx, db.PostAdjustmentTxParams{
+		Period:    period,
+		AccountID: req.AccountID,
+		Amount:    req.Amount,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		if errors.Is(err, db.ErrPeriodNotClosed) || errors.Is(err, db.ErrPeriodClosed) {
+			ctx.JSON(errorResponse(http.StatusConflict, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClosePeriodAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+	period := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"period": "2024-03"},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ClosePeriodTxParams{
+					Period:   period,
+					ClosedBy: admin.Username,
+				}
+				store.EXPECT().ClosePeriodTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ClosePeriodTxResult{
+						Period: db.AccountingPeriod{Period: period, ClosedBy: admin.Username},
+						Report: db.PeriodReport{Period: period},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidPeriod",
+			body: gin.H{"period": "March 2024"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClosePeriodTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotEnded",
+			body: gin.H{"period": "2024-03"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClosePeriodTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ClosePeriodTxResult{}, db.ErrPeriodNotEnded)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyClosed",
+			body: gin.H{"period": "2024-03"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClosePeriodTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ClosePeriodTxResult{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+				Times(1).
+				Return(admin, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/periods", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPostAdjustmentAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+	account := randomAccount()
+	period := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.PostAdjustmentTxParams{
+					Period:    period,
+					AccountID: account.ID,
+					Amount:    -25,
+				}
+				store.EXPECT().PostAdjustmentTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.PostAdjustmentTxResult{Account: account}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PeriodStillOpen",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().PostAdjustmentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PostAdjustmentTxResult{}, db.ErrPeriodNotClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+				Times(1).
+				Return(admin, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"account_id": account.ID, "amount": -25})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/periods/%s/adjustments", period.Format(periodLayout))
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// activityCursor is what an activity cursor encodes. Feed rows come from
+// several tables, so the last row is known by its time, source and ID
+// rather than by an ID alone.
+type activityCursor struct {
+	At     time.Time `json:"at"`
+	Source string    `json:"source"`
+	ID     int64     `json:"id"`
+}
+
+func encodeActivityCursor(row db.ListAccountActivityRow) string {
+	data, _ := json.Marshal(activityCursor{At: row.CreatedAt, Source: row.Source, ID: row.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// activityCursor decodes the cursor. An empty cursor means the first page.
+func (req cursorPageRequest) activityCursor() (activityCursor, error) {
+	var cursor activityCursor
+	if req.Cursor == "" {
+		return cursor, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(req.Cursor)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.At.IsZero() || cursor.Source == "" || cursor.ID <= 0 {
+		return cursor, errInvalidCursor
+	}
+	return cursor, nil
+}
+
+// listAccountActivity is the feed of one of the caller's accounts, newest
+// first: transfers in any status, deposits, withdrawals, interest and the
+// rest, each with its kind. The query merges them, so pages stay full.
+func (server *Server) listAccountActivity(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req cursorPageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	cursor, err := req.activityCursor()
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, ok := server.ownAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	rows, err := server.store.ListAccountActivity(ctx, db.ListAccountActivityParams{
+		AccountID:    account.ID,
+		BeforeTime:   sql.NullTime{Time: cursor.At, Valid: !cursor.At.IsZero()},
+		BeforeSource: cursor.Source,
+		BeforeID:     cursor.ID,
+		PageLimit:    req.limit() + 1,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	// One more row than the limit was fetched, like newListResponse expects,
+	// but the cursor is an activityCursor.
+	rsp := listResponse[db.ListAccountActivityRow]{Items: rows, Page: pageInfo{Limit: req.limit()}}
+	if len(rows) > int(req.limit()) {
+		rsp.Items = rows[:req.limit()]
+		rsp.Page.HasMore = true
+		rsp.NextCursor = encodeActivityCursor(rsp.Items[len(rsp.Items)-1])
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAccountActivityAPI(t *testing.T) {
+	account := randomAccount()
+	at := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	rows := []db.ListAccountActivityRow{
+		{Source: "transfer", ID: 9, Kind: "transfer", Amount: -10, Currency: account.Currency, Status: db.TransferPending, CounterpartyAccountID: 2, CreatedAt: at},
+		{Source: "entry", ID: 30, Kind: db.EntryKindDeposit, Amount: 50, Currency: account.Currency, Status: db.TransferCompleted, Reference: "FT1", CreatedAt: at},
+		{Source: "entry", ID: 12, Kind: db.EntryKindInterest, Amount: 1, Currency: account.Currency, Status: db.TransferCompleted, CreatedAt: at.Add(-time.Hour)},
+	}
+	cursor := encodeActivityCursor(rows[1])
+
+	testCases := []struct {
+		name          string
+		query         string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "FirstPage",
+			query:    "limit=2",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountActivity(gomock.Any(), gomock.Eq(db.ListAccountActivityParams{
+					AccountID: account.ID,
+					PageLimit: 3,
+				})).
+					Times(1).
+					Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got listResponse[db.ListAccountActivityRow]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, rows[:2], got.Items)
+				require.True(t, got.Page.HasMore)
+				require.Equal(t, cursor, got.NextCursor)
+			},
+		},
+		{
+			name:     "NextPage",
+			query:    "limit=2&cursor=" + cursor,
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountActivity(gomock.Any(), gomock.Eq(db.ListAccountActivityParams{
+					AccountID:    account.ID,
+					BeforeTime:   sql.NullTime{Time: at, Valid: true},
+					BeforeSource: "entry",
+					BeforeID:     30,
+					PageLimit:    3,
+				})).
+					Times(1).
+					Return(rows[2:], nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got listResponse[db.ListAccountActivityRow]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, rows[2:], got.Items)
+				require.False(t, got.Page.HasMore)
+				require.Empty(t, got.NextCursor)
+			},
+		},
+		{
+			name:     "InvalidCursor",
+			query:    "cursor=" + encodeCursor(9),
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountActivity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NotOwner",
+			username: "someone-else",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountActivity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts/%d/activity?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errUserSuspended    = errors.New("user is suspended")
+	errSuspendSelf      = errors.New("admins can't suspend themselves")
+	errAdjustmentReason = errors.New("reason must not be blank")
+)
+
+type searchUsersRequest struct {
+	// Matched against the username, email and full name.
+	Query    string `form:"q" binding:"max=100"`
+	Status   string `form:"status" binding:"omitempty,oneof=active suspended"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// searchUsers lists users for support staff, ordered by username.
+func (server *Server) searchUsers(ctx *gin.Context) {
+	var req searchUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	search := ""
+	if req.Query != "" {
+		search = "%" + memoPattern.Replace(req.Query) + "%"
+	}
+	users, err := server.store.SearchUsers(ctx, db.SearchUsersParams{
+		Search:     search,
+		Status:     req.Status,
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	rsp := make([]UserResponse, len(users))
+	for i, user := range users {
+		rsp[i] = newUserResponse(user)
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type adminUserURI struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// suspendUser stops a user from logging in or minting new tokens. Tokens
+// already issued keep working until they expire.
+func (server *Server) suspendUser(ctx *gin.Context) {
+	server.setUserStatus(ctx, db.UserSuspended)
+}
+
+func (server *Server) reactivateUser(ctx *gin.Context) {
+	server.setUserStatus(ctx, db.UserActive)
+}
+
+// setUserStatus records the change in the user's standing data history, like
+// setAccountStatus does for accounts.
+func (server *Server) setUserStatus(ctx *gin.Context, status string) {
+	var uri adminUserURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if status == db.UserSuspended && uri.Username == authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errSuspendSelf))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, uri.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if user.Status == status {
+		ctx.JSON(http.StatusOK, newUserResponse(user))
+		return
+	}
+
+	_, err = server.store.UpdateStandingDataTx(ctx, db.UpdateStandingDataTxParams{
+		EntityType: db.StandingDataUser,
+		EntityID:   user.Username,
+		Field:      "status",
+		NewValue:   status,
+		ChangedBy:  authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	user.Status = status
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+type searchAccountsRequest struct {
+	Owner    string `form:"owner" binding:"omitempty,alphanum"`
+	Currency string `form:"currency" binding:"omitempty,currency"`
+	Status   string `form:"status" binding:"omitempty,oneof=active frozen closed"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// searchAccounts lists accounts of every owner, oldest first.
+func (server *Server) searchAccounts(ctx *gin.Context) {
+	var req searchAccountsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	accounts, err := server.store.SearchAccounts(ctx, db.SearchAccountsParams{
+		Owner:      req.Owner,
+		Currency:   req.Currency,
+		Status:     req.Status,
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if accounts == nil {
+		accounts = []db.Account{}
+	}
+	ctx.JSON(http.StatusOK, accounts)
+}
+
+// adminFreezeAccount suspends an account whoever owns it. Owners can freeze
+// their own accounts, but only admins can unfreeze.
+func (server *Server) adminFreezeAccount(ctx *gin.Context) {
+	var req getAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	server.setAccountStatus(ctx, account, db.AccountFrozen, authPayload.Username)
+}
+
+type setOverdraftLimitRequest struct {
+	// Zero turns the overdraft off. A limit below what is used already only
+	// stops further debits.
+	OverdraftLimit *int64 `json:"overdraft_limit" binding:"required,min=0"`
+}
+
+// setOverdraftLimit changes how far below zero an account may go. The change
+// is kept in the account's standing data history.
+func (server *Server) setOverdraftLimit(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req setOverdraftLimitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if account.Status == db.AccountClosed {
+		ctx.JSON(errorResponse(http.StatusLocked, db.ErrAccountClosed))
+		return
+	}
+	if account.OverdraftLimit == *req.OverdraftLimit {
+		ctx.JSON(http.StatusOK, account)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	_, err = server.store.UpdateStandingDataTx(ctx, db.UpdateStandingDataTxParams{
+		EntityType: db.StandingDataAccount,
+		EntityID:   db.AccountEntityID(account.ID),
+		Field:      "overdraft_limit",
+		NewValue:   strconv.FormatInt(*req.OverdraftLimit, 10),
+		ChangedBy:  authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	account, err = server.store.GetAccount(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	ctx.JSON(http.StatusOK, account)
+}
+
+type adjustBalanceRequest struct {
+	// Added to the balance; negative to take money off.
+	Amount int64  `json:"amount" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// adjustBalance corrects the balance of an account. The reason is stored with
+// the adjustment and the admin who made it.
+func (server *Server) adjustBalance(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req adjustBalanceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errAdjustmentReason))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.store.AdjustBalanceTx(ctx, db.AdjustBalanceTxParams{
+		AccountID:  uri.ID,
+		Amount:     req.Amount,
+		Reason:     reason,
+		AdjustedBy: authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type listBalanceAdjustmentsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listBalanceAdjustments returns the adjustments of an account, newest first.
+func (server *Server) listBalanceAdjustments(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req listBalanceAdjustmentsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	adjustments, err := server.store.ListBalanceAdjustments(ctx, db.ListBalanceAdjustmentsParams{
+		AccountID: uri.ID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if adjustments == nil {
+		adjustments = []db.BalanceAdjustment{}
+	}
+	ctx.JSON(http.StatusOK, adjustments)
+}
+
+type searchTransfersRequest struct {
+	AccountID int64  `form:"account_id" binding:"omitempty,min=1"`
+	Owner     string `form:"owner" binding:"omitempty,alphanum"`
+	// Only transfers made on or between these days, both included.
+	FromDate  string `form:"from_date" binding:"omitempty,datetime=2006-01-02"`
+	ToDate    string `form:"to_date" binding:"omitempty,datetime=2006-01-02"`
+	MinAmount int64  `form:"min_amount" binding:"omitempty,min=1"`
+	PageID    int32  `form:"page_id" binding:"required,min=1"`
+	PageSize  int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// searchTransfers lists transfers between any accounts, newest first.
+func (server *Server) searchTransfers(ctx *gin.Context) {
+	var req searchTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	arg := db.SearchTransfersParams{
+		AccountID:  req.AccountID,
+		Owner:      req.Owner,
+		MinAmount:  req.MinAmount,
+		PageLimit:  req.PageSize,
+		PageOffset: (req.PageID - 1) * req.PageSize,
+	}
+	// Dates are validated by the binding. The range includes to_date.
+	if req.FromDate != "" {
+		from, _ := time.Parse(statementDateLayout, req.FromDate)
+		arg.FromTime = sql.NullTime{Time: from, Valid: true}
+	}
+	if req.ToDate != "" {
+		to, _ := time.Parse(statementDateLayout, req.ToDate)
+		arg.ToTime = sql.NullTime{Time: to.AddDate(0, 0, 1), Valid: true}
+	}
+
+	transfers, err := server.store.SearchTransfers(ctx, arg)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if transfers == nil {
+		transfers = []db.Transfer{}
+	}
+	ctx.JSON(http.StatusOK, transfers)
+}
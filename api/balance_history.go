@@ -0,0 +1,143 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	granularityDaily   = "daily"
+	granularityWeekly  = "weekly"
+	granularityMonthly = "monthly"
+
+	// defaultBalanceHistoryDays is the range shown when no from_date is given.
+	defaultBalanceHistoryDays = 30
+)
+
+type balanceHistoryRequest struct {
+	Granularity string `form:"granularity" binding:"omitempty,oneof=daily weekly monthly"`
+	FromDate    string `form:"from_date" binding:"omitempty,datetime=2006-01-02"`
+	ToDate      string `form:"to_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type balancePoint struct {
+	Date    string `json:"date"`
+	Balance int64  `json:"balance"`
+}
+
+type balanceHistoryResponse struct {
+	AccountID   int64          `json:"account_id"`
+	Currency    string         `json:"currency"`
+	Granularity string         `json:"granularity"`
+	Points      []balancePoint `json:"points"`
+}
+
+// getBalanceHistory returns the closing balance of an account for each day,
+// week or month in a date range, for drawing balance charts. A week or month
+// cut off by the end of the range reports its balance on to_date.
+func (server *Server) getBalanceHistory(ctx *gin.Context) {
+	var uriReq getAccountRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req balanceHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if req.Granularity == "" {
+		req.Granularity = granularityDaily
+	}
+
+	// Dates are validated by the binding. The range includes to_date.
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.ToDate != "" {
+		to, _ = time.Parse(statementDateLayout, req.ToDate)
+	}
+	from := to.AddDate(0, 0, 1-defaultBalanceHistoryDays)
+	if req.FromDate != "" {
+		from, _ = time.Parse(statementDateLayout, req.FromDate)
+	}
+	if to.Before(from) {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errors.New("to_date is before from_date")))
+		return
+	}
+	if to.Sub(from) >= maxStatementDays*24*time.Hour {
+		ctx.JSON(errorResponse(http.StatusBadRequest, fmt.Errorf("balance history covers at most %d days", maxStatementDays)))
+		return
+	}
+	if err := server.checkNotArchived(from); err != nil {
+		ctx.JSON(errorResponse(http.StatusGone, err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uriReq.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("account doesn't belong to the authenticated user")))
+		return
+	}
+
+	days, err := server.store.ListDailyBalances(ctx, db.ListDailyBalancesParams{
+		AccountID: account.ID,
+		FromDay:   from,
+		ToDay:     to,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, balanceHistoryResponse{
+		AccountID:   account.ID,
+		Currency:    account.Currency,
+		Granularity: req.Granularity,
+		Points:      closingBalances(days, req.Granularity),
+	})
+}
+
+// closingBalances keeps the last day of every period, whose balance is the
+// closing balance of the period.
+func closingBalances(days []db.ListDailyBalancesRow, granularity string) []balancePoint {
+	points := []balancePoint{}
+	for i, day := range days {
+		if i+1 < len(days) && samePeriod(day.Day, days[i+1].Day, granularity) {
+			continue
+		}
+		points = append(points, balancePoint{
+			Date:    day.Day.Format(statementDateLayout),
+			Balance: day.Balance,
+		})
+	}
+	return points
+}
+
+func samePeriod(a, b time.Time, granularity string) bool {
+	switch granularity {
+	case granularityWeekly:
+		aYear, aWeek := a.ISOWeek()
+		bYear, bWeek := b.ISOWeek()
+		return aYear == bYear && aWeek == bWeek
+	case granularityMonthly:
+		return a.Year() == b.Year() && a.Month() == b.Month()
+	}
+	return false
+}
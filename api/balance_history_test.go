@@ -0,0 +1,213 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBalanceHistoryAPI(t *testing.T) {
+	account := randomAccount()
+
+	// Thursday 2024-01-25 to Friday 2024-02-02.
+	from := time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC)
+	days := make([]db.ListDailyBalancesRow, 9)
+	for i := range days {
+		days[i] = db.ListDailyBalancesRow{Day: from.AddDate(0, 0, i), Balance: int64(100 + i)}
+	}
+	rangeQuery := "from_date=2024-01-25&to_date=2024-02-02"
+
+	testCases := []struct {
+		name          string
+		query         string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Daily",
+			query:    rangeQuery,
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListDailyBalances(gomock.Any(), gomock.Eq(db.ListDailyBalancesParams{
+					AccountID: account.ID,
+					FromDay:   from,
+					ToDay:     from.AddDate(0, 0, 8),
+				})).
+					Times(1).
+					Return(days, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				got := decodeBalanceHistory(t, recorder)
+				require.Equal(t, granularityDaily, got.Granularity)
+				require.Equal(t, account.Currency, got.Currency)
+				require.Len(t, got.Points, 9)
+				require.Equal(t, balancePoint{Date: "2024-01-25", Balance: 100}, got.Points[0])
+				require.Equal(t, balancePoint{Date: "2024-02-02", Balance: 108}, got.Points[8])
+			},
+		},
+		{
+			name:     "Weekly",
+			query:    rangeQuery + "&granularity=weekly",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListDailyBalances(gomock.Any(), gomock.Any()).Times(1).Return(days, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, []balancePoint{
+					{Date: "2024-01-28", Balance: 103},
+					{Date: "2024-02-02", Balance: 108},
+				}, decodeBalanceHistory(t, recorder).Points)
+			},
+		},
+		{
+			name:     "Monthly",
+			query:    rangeQuery + "&granularity=monthly",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListDailyBalances(gomock.Any(), gomock.Any()).Times(1).Return(days, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, []balancePoint{
+					{Date: "2024-01-31", Balance: 106},
+					{Date: "2024-02-02", Balance: 108},
+				}, decodeBalanceHistory(t, recorder).Points)
+			},
+		},
+		{
+			name:     "DefaultRange",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListDailyBalances(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.ListDailyBalancesParams) ([]db.ListDailyBalancesRow, error) {
+						require.Equal(t, time.Now().UTC().Truncate(24*time.Hour), arg.ToDay)
+						require.Equal(t, arg.ToDay.AddDate(0, 0, 1-defaultBalanceHistoryDays), arg.FromDay)
+						return nil, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, decodeBalanceHistory(t, recorder).Points)
+			},
+		},
+		{
+			name:     "NotOwner",
+			query:    rangeQuery,
+			username: "someone-else",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListDailyBalances(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "AccountNotFound",
+			query:    rangeQuery,
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidGranularity",
+			query:    rangeQuery + "&granularity=hourly",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "RangeTooLong",
+			query:    "from_date=2023-01-01&to_date=2024-01-31",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts/%d/balance_history?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetBalanceHistoryArchivedAPI(t *testing.T) {
+	account := randomAccount()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	server.config.LedgerArchiveMonths = 1
+	recorder := httptest.NewRecorder()
+
+	// Ranges are capped at a year, so one that starts two months ago is
+	// valid but reaches into the archive.
+	from := time.Now().UTC().AddDate(0, -2, 0).Format("2006-01-02")
+	to := time.Now().UTC().Format("2006-01-02")
+	url := fmt.Sprintf("/api/accounts/%d/balance_history?from_date=%s&to_date=%s", account.ID, from, to)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, account.Owner, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusGone, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "ledger_archived")
+}
+
+func decodeBalanceHistory(t *testing.T, recorder *httptest.ResponseRecorder) balanceHistoryResponse {
+	var got balanceHistoryResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	return got
+}
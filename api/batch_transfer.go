@@ -0,0 +1,255 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/limits"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// Outcomes of a transfer in a batch.
+const (
+	batchTransferSucceeded = "succeeded"
+	batchTransferFailed    = "failed"
+)
+
+var errBatchCurrency = errors.New("batch transfers must be between accounts of the same currency")
+
+type batchTransferItem struct {
+	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
+	Amount        int64  `json:"amount" binding:"required,gt=0"`
+	Memo          string `json:"memo" binding:"max=140"`
+}
+
+type batchTransferRequest struct {
+	// With atomic, either every transfer is made or none is. Otherwise a
+	// transfer that fails is rolled back on its own and the response reports
+	// every outcome.
+	Atomic    bool                `json:"atomic"`
+	Transfers []batchTransferItem `json:"transfers" binding:"required,min=1,max=100,dive"`
+}
+
+type batchTransferResult struct {
+	Status   string               `json:"status"`
+	Transfer *db.TransferTxResult `json:"transfer,omitempty"`
+	Error    string               `json:"error,omitempty"`
+}
+
+type batchTransferResponse struct {
+	Atomic  bool                  `json:"atomic"`
+	Results []batchTransferResult `json:"results"`
+}
+
+// createBatchTransfer makes many transfers in one request, e.g. payroll. The
+// whole batch is validated before any money moves. With an Idempotency-Key
+// every transfer is made at most once, so a failed batch can be retried as
+// is.
+func (server *Server) createBatchTransfer(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	idempotency, err := idempotencyKey(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req batchTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	status, err := server.validateBatchTransfer(ctx, authPayload, req.Transfers)
+	if err != nil {
+		ctx.JSON(errorResponse(status, err))
+		return
+	}
+
+	params := make([]db.TransferTxParams, len(req.Transfers))
+	for i, item := range req.Transfers {
+		params[i] = db.TransferTxParams{
+			FromAccountID: item.FromAccountID,
+			ToAccountID:   item.ToAccountID,
+			Amount:        item.Amount,
+			Memo:          item.Memo,
+			Idempotency:   batchItemIdempotency(idempotency, i),
+		}
+	}
+
+	if req.Atomic {
+		server.executeAtomicBatch(ctx, params)
+		return
+	}
+
+	server.executePartialBatch(ctx, params)
+}
+
+// executePartialBatch makes the transfers of a batch in one transaction,
+// each in a savepoint, so the ones that fail don't undo the others.
+func (server *Server) executePartialBatch(ctx *gin.Context, params []db.TransferTxParams) {
+	batch, err := server.store.BatchTransferTx(ctx, db.BatchTransferTxParams{Transfers: params, Partial: true})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	results := make([]batchTransferResult, len(params))
+	for i, arg := range params {
+		result, err := batch.Transfers[i], batch.Errors[i]
+		if errors.Is(err, db.ErrIdempotencyKeyUsed) {
+			result, err = server.previousBatchTransfer(ctx, arg.Idempotency)
+		}
+		if err != nil {
+			results[i] = batchTransferResult{Status: batchTransferFailed, Error: err.Error()}
+			continue
+		}
+		results[i] = batchTransferResult{Status: batchTransferSucceeded, Transfer: &result}
+	}
+	ctx.JSON(http.StatusOK, batchTransferResponse{Results: results})
+}
+
+func (server *Server) executeAtomicBatch(ctx *gin.Context, params []db.TransferTxParams) {
+	batch, err := server.store.BatchTransferTx(ctx, db.BatchTransferTxParams{Transfers: params})
+	if errors.Is(err, db.ErrIdempotencyKeyUsed) {
+		// An earlier attempt committed the whole batch.
+		batch.Transfers = make([]db.TransferTxResult, len(params))
+		for i, arg := range params {
+			batch.Transfers[i], err = server.previousBatchTransfer(ctx, arg.Idempotency)
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		if errors.Is(err, errIdempotencyKeyMismatch) {
+			ctx.JSON(errorResponse(http.StatusUnprocessableEntity, err))
+			return
+		}
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	results := make([]batchTransferResult, len(batch.Transfers))
+	for i := range batch.Transfers {
+		results[i] = batchTransferResult{Status: batchTransferSucceeded, Transfer: &batch.Transfers[i]}
+	}
+	ctx.JSON(http.StatusOK, batchTransferResponse{Atomic: true, Results: results})
+}
+
+// validateBatchTransfer makes the checks of POST /transfers for every item.
+// Large batches need a step-up token even when each transfer is small.
+func (server *Server) validateBatchTransfer(ctx *gin.Context, authPayload *token.Payload, items []batchTransferItem) (int, error) {
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	accounts, err := server.batchAccounts(ctx, items)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	totals := make(map[string]int64)
+	for i, item := range items {
+		fromAccount, ok := accounts[item.FromAccountID]
+		if !ok {
+			return http.StatusNotFound, fmt.Errorf("transfer %d: %w", i, sql.ErrNoRows)
+		}
+		if fromAccount.Owner != authPayload.Username {
+			return http.StatusUnauthorized, fmt.Errorf("transfer %d: from account doesn't belong to the authenticated user", i)
+		}
+		toAccount, ok := accounts[item.ToAccountID]
+		if !ok {
+			return http.StatusNotFound, fmt.Errorf("transfer %d: %w", i, sql.ErrNoRows)
+		}
+		if fromAccount.Currency != toAccount.Currency {
+			return http.StatusBadRequest, fmt.Errorf("transfer %d: %w", i, errBatchCurrency)
+		}
+
+		err = server.limitEngine.CheckTransfer(ctx, limits.Transfer{
+			Tier:     user.KycTier,
+			Tenant:   user.Tenant,
+			Amount:   item.Amount,
+			Currency: fromAccount.Currency,
+			External: toAccount.Owner != authPayload.Username,
+		})
+		if err != nil {
+			if errors.Is(err, limits.ErrNotAllowed) {
+				return http.StatusForbidden, fmt.Errorf("transfer %d: %w", i, err)
+			}
+			return http.StatusInternalServerError, err
+		}
+		totals[fromAccount.Currency] += item.Amount
+	}
+
+	for currency, total := range totals {
+		if server.requiresStepUp(ctx, total, currency) && !authPayload.Elevated {
+			return http.StatusForbidden, errStepUpRequired
+		}
+	}
+	return http.StatusOK, nil
+}
+
+// batchAccounts loads every account of a batch in one query, by ID.
+// Accounts that don't exist are missing from the map.
+func (server *Server) batchAccounts(ctx *gin.Context, items []batchTransferItem) (map[int64]db.Account, error) {
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, item := range items {
+		for _, id := range []int64{item.FromAccountID, item.ToAccountID} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	rows, err := server.store.GetAccountsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make(map[int64]db.Account, len(rows))
+	for _, account := range rows {
+		accounts[account.ID] = account
+	}
+	return accounts, nil
+}
+
+// batchItemIdempotency derives the key of one transfer in a batch. Without
+// a key for the batch, its transfers have none either.
+func batchItemIdempotency(batch db.ClaimIdempotencyKeyParams, index int) db.ClaimIdempotencyKeyParams {
+	if batch.Key == "" {
+		return batch
+	}
+	return db.ClaimIdempotencyKeyParams{
+		Username:    batch.Username,
+		Key:         fmt.Sprintf("%s#%d", batch.Key, index),
+		RequestHash: fmt.Sprintf("%s#%d", batch.RequestHash, index),
+	}
+}
+
+// previousBatchTransfer recovers a transfer an earlier attempt of the batch
+// made.
+func (server *Server) previousBatchTransfer(ctx *gin.Context, key db.ClaimIdempotencyKeyParams) (db.TransferTxResult, error) {
+	stored, err := server.store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Username: key.Username,
+		Key:      key.Key,
+	})
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+	if stored.RequestHash != key.RequestHash {
+		return db.TransferTxResult{}, errIdempotencyKeyMismatch
+	}
+
+	var result db.TransferTxResult
+	if err := json.Unmarshal(stored.Response, &result); err != nil {
+		return db.TransferTxResult{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBatchTransferAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.KycTier = util.KYCTierFull
+	fromAccount := db.Account{ID: 1, Owner: user.Username, Balance: 10_000, Currency: util.INR}
+	toAccount1 := db.Account{ID: 2, Owner: util.RandomOwner(), Currency: util.INR}
+	toAccount2 := db.Account{ID: 3, Owner: util.RandomOwner(), Currency: util.INR}
+	usdAccount := db.Account{ID: 4, Owner: util.RandomOwner(), Currency: util.USD}
+
+	items := []gin.H{
+		{"from_account_id": fromAccount.ID, "to_account_id": toAccount1.ID, "amount": 100},
+		{"from_account_id": fromAccount.ID, "to_account_id": toAccount2.ID, "amount": 200},
+	}
+	params := []db.TransferTxParams{
+		{FromAccountID: fromAccount.ID, ToAccountID: toAccount1.ID, Amount: 100},
+		{FromAccountID: fromAccount.ID, ToAccountID: toAccount2.ID, Amount: 200},
+	}
+	stubAccounts := func(store *mockdb.MockStore) {
+		store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+		store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Eq([]int64{fromAccount.ID, toAccount1.ID, toAccount2.ID})).
+			Times(1).
+			Return([]db.Account{fromAccount, toAccount1, toAccount2}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Atomic",
+			body: gin.H{"atomic": true, "transfers": items},
+			buildStubs: func(store *mockdb.MockStore) {
+				stubAccounts(store)
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Eq(db.BatchTransferTxParams{Transfers: params})).
+					Times(1).
+					Return(db.BatchTransferTxResult{Transfers: []db.TransferTxResult{
+						{Transfer: db.Transfer{ID: 10}},
+						{Transfer: db.Transfer{ID: 11}},
+					}}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got batchTransferResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.True(t, got.Atomic)
+				require.Len(t, got.Results, 2)
+				require.Equal(t, int64(11), got.Results[1].Transfer.Transfer.ID)
+			},
+		},
+		{
+			name: "AtomicFails",
+			body: gin.H{"atomic": true, "transfers": items},
+			buildStubs: func(store *mockdb.MockStore) {
+				stubAccounts(store)
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BatchTransferTxResult{}, fmt.Errorf("transfer 1: %w", db.ErrTransferLimitExceeded))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "transfer 1")
+			},
+		},
+		{
+			name: "Independent",
+			body: gin.H{"transfers": items},
+			buildStubs: func(store *mockdb.MockStore) {
+				stubAccounts(store)
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Eq(db.BatchTransferTxParams{Transfers: params, Partial: true})).
+					Times(1).
+					Return(db.BatchTransferTxResult{
+						Transfers: []db.TransferTxResult{{Transfer: db.Transfer{ID: 10}}, {}},
+						Errors:    []error{nil, errors.New("boom")},
+					}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got batchTransferResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.False(t, got.Atomic)
+				require.Equal(t, batchTransferSucceeded, got.Results[0].Status)
+				require.Equal(t, batchTransferFailed, got.Results[1].Status)
+				require.Equal(t, "boom", got.Results[1].Error)
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			body: gin.H{"transfers": []gin.H{
+				items[0],
+				{"from_account_id": fromAccount.ID, "to_account_id": usdAccount.ID, "amount": 5},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Eq([]int64{fromAccount.ID, toAccount1.ID, usdAccount.ID})).
+					Times(1).
+					Return([]db.Account{fromAccount, toAccount1, usdAccount}, nil)
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "transfer 1")
+			},
+		},
+		{
+			name: "NotOwner",
+			body: gin.H{"transfers": []gin.H{
+				{"from_account_id": toAccount1.ID, "to_account_id": toAccount2.ID, "amount": 5},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Eq([]int64{toAccount1.ID, toAccount2.ID})).
+					Times(1).
+					Return([]db.Account{toAccount1, toAccount2}, nil)
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNotFound",
+			body: gin.H{"transfers": []gin.H{
+				items[0],
+				{"from_account_id": fromAccount.ID, "to_account_id": 99, "amount": 5},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Eq([]int64{fromAccount.ID, toAccount1.ID, 99})).
+					Times(1).
+					Return([]db.Account{fromAccount, toAccount1}, nil)
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "transfer 1")
+			},
+		},
+		{
+			name: "TooMany",
+			body: func() gin.H {
+				transfers := make([]gin.H, 101)
+				for i := range transfers {
+					transfers[i] = items[0]
+				}
+				return gin.H{"transfers": transfers}
+			}(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().ListSettings(gomock.Any()).AnyTimes().Return([]db.Setting{}, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/transfers/batch", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestBatchTransferRetryRecoversTransfers(t *testing.T) {
+	user, _ := randomUser(t)
+	user.KycTier = util.KYCTierFull
+	fromAccount := db.Account{ID: 1, Owner: user.Username, Balance: 10_000, Currency: util.INR}
+	toAccount := db.Account{ID: 2, Owner: util.RandomOwner(), Currency: util.INR}
+	previous := db.TransferTxResult{Transfer: db.Transfer{ID: 10, FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: 100, Status: db.TransferCompleted}}
+
+	data, err := json.Marshal(gin.H{
+		"atomic":    true,
+		"transfers": []gin.H{{"from_account_id": fromAccount.ID, "to_account_id": toAccount.ID, "amount": 100}},
+	})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListSettings(gomock.Any()).AnyTimes().Return([]db.Setting{}, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Eq([]int64{fromAccount.ID, toAccount.ID})).
+		Times(1).
+		Return([]db.Account{fromAccount, toAccount}, nil)
+	store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.BatchTransferTxResult{}, fmt.Errorf("transfer 0: %w", db.ErrIdempotencyKeyUsed))
+
+	var storedHash string
+	store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(db.GetIdempotencyKeyParams{Username: user.Username, Key: "payroll-1#0"})).
+		Times(1).
+		DoAndReturn(func(_ interface{}, _ db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+			response, err := json.Marshal(previous)
+			require.NoError(t, err)
+			return db.IdempotencyKey{RequestHash: storedHash, Response: response}, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, "/api/transfers/batch", bytes.NewReader(data))
+	require.NoError(t, err)
+	request.Header.Set(idempotencyKeyHeader, "payroll-1")
+
+	key := db.ClaimIdempotencyKeyParams{
+		Username:    user.Username,
+		Key:         "payroll-1",
+		RequestHash: requestHash(http.MethodPost, "/api/transfers/batch", nil, data),
+	}
+	storedHash = batchItemIdempotency(key, 0).RequestHash
+
+	addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got batchTransferResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	require.Equal(t, previous.Transfer, got.Results[0].Transfer.Transfer)
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+var errBeneficiaryNotOwned = errors.New("beneficiary doesn't belong to the authenticated user")
+
+type createBeneficiaryRequest struct {
+	AccountID int64  `json:"account_id" binding:"required,min=1"`
+	Nickname  string `json:"nickname" binding:"required,max=64"`
+	// Optional: checked against the account owner, like to_username on
+	// transfers, so a mistyped account ID is not saved.
+	Owner string `json:"owner"`
+}
+
+type beneficiaryResponse struct {
+	ID           int64     `json:"id"`
+	AccountID    int64     `json:"account_id"`
+	Nickname     string    `json:"nickname"`
+	AccountOwner string    `json:"account_owner"`
+	Currency     string    `json:"currency"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// createBeneficiary saves an account as a payee of the caller. Transfers can
+// then name it by beneficiary_id.
+func (server *Server) createBeneficiary(ctx *gin.Context) {
+	var req createBeneficiaryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if req.Owner != "" && account.Owner != req.Owner {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errors.New("owner does not match the account")))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	beneficiary, err := server.store.CreateBeneficiary(ctx, db.CreateBeneficiaryParams{
+		Owner:     authPayload.Username,
+		AccountID: account.ID,
+		Nickname:  req.Nickname,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, beneficiaryResponse{
+		ID:           beneficiary.ID,
+		AccountID:    account.ID,
+		Nickname:     beneficiary.Nickname,
+		AccountOwner: account.Owner,
+		Currency:     account.Currency,
+		CreatedAt:    beneficiary.CreatedAt,
+	})
+}
+
+func (server *Server) listBeneficiaries(ctx *gin.Context) {
+	var req cursorPageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	afterID, err := req.lastID()
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	rows, err := server.store.ListBeneficiaries(ctx, db.ListBeneficiariesParams{
+		Owner:     authPayload.Username,
+		AfterID:   afterID,
+		PageLimit: req.limit() + 1,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	page := newListResponse(rows, req.limit(), func(row db.ListBeneficiariesRow) int64 { return row.ID })
+	items := make([]beneficiaryResponse, len(page.Items))
+	for i, row := range page.Items {
+		items[i] = beneficiaryResponse(row)
+	}
+	ctx.JSON(http.StatusOK, withItems(page, items))
+}
+
+type beneficiaryURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) deleteBeneficiary(ctx *gin.Context) {
+	var uri beneficiaryURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if _, err := server.ownBeneficiary(ctx, uri.ID, authPayload.Username); err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+		case errors.Is(err, errBeneficiaryNotOwned):
+			ctx.JSON(errorResponse(http.StatusUnauthorized, err))
+		default:
+			ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		}
+		return
+	}
+
+	if err := server.store.DeleteBeneficiary(ctx, uri.ID); err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ownBeneficiary loads a beneficiary saved by username.
+func (server *Server) ownBeneficiary(ctx *gin.Context, id int64, username string) (db.Beneficiary, error) {
+	beneficiary, err := server.store.GetBeneficiary(ctx, id)
+	if err != nil {
+		return db.Beneficiary{}, err
+	}
+	if beneficiary.Owner != username {
+		return db.Beneficiary{}, errBeneficiaryNotOwned
+	}
+	return beneficiary, nil
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type currencyResponse struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Exponent int32  `json:"exponent"`
+}
+
+func newCurrencyResponse(currency db.Currency) currencyResponse {
+	return currencyResponse{
+		Code:     currency.Code,
+		Name:     currency.Name,
+		Exponent: currency.Exponent,
+	}
+}
+
+// listCurrencies is public so apps can fill currency pickers before login.
+// Amounts in the API are in minor units; Exponent says where the decimal
+// point goes when showing them.
+func (server *Server) listCurrencies(ctx *gin.Context) {
+	currencies, err := server.store.ListCurrencies(ctx)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	rsp := make([]currencyResponse, len(currencies))
+	for i, currency := range currencies {
+		rsp[i] = newCurrencyResponse(currency)
+	}
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, rsp)
+}
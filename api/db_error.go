@@ -0,0 +1,88 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/lib/pq"
+)
+
+// Codes of constraint violations, see apiError.
+const (
+	errCodeAlreadyExists    = "already_exists"
+	errCodeInvalidReference = "invalid_reference"
+	errCodeInvalidValue     = "invalid_value"
+)
+
+// constraintMessages replaces Postgres' wording for the constraints users
+// commonly run into.
+var constraintMessages = map[string]string{
+	"owner_currency_key":           "an account in this currency already exists",
+	"users_pkey":                   "username is already taken",
+	"users_email_key":              "email is already registered",
+	"accounting_periods_pkey":      "accounting period is already closed",
+	"owner_account_key":            "account is already a saved beneficiary",
+	"owner_nickname_key":           "nickname is already used by another beneficiary",
+	"accounts_owner_fkey":          "owner does not exist",
+	"account_pot_name_key":         "a pot with this name already exists",
+	"fx_rates_pair_valid_from_key": "a rate of this pair already takes over at this time",
+}
+
+// storeErrorStatuses are the statuses of the errors a store transaction
+// returns when the request can't be carried out, whichever handler made it.
+var storeErrorStatuses = []struct {
+	err    error
+	status int
+}{
+	{sql.ErrNoRows, http.StatusNotFound},
+	{db.ErrPotMoveToSelf, http.StatusBadRequest},
+	{db.ErrTransferLimitExceeded, http.StatusForbidden},
+	{db.ErrPeriodClosed, http.StatusConflict},
+	{db.ErrDepositReferenceUsed, http.StatusConflict},
+	{db.ErrAccountNotEmpty, http.StatusConflict},
+	{db.ErrPotsNotEmpty, http.StatusConflict},
+	{db.ErrIdempotencyKeyUsed, http.StatusConflict},
+	{db.ErrVersionConflict, http.StatusConflict},
+	{db.ErrPaymentRequestAnswered, http.StatusConflict},
+	{db.ErrInsufficientFunds, http.StatusUnprocessableEntity},
+	{db.ErrAccountFrozen, http.StatusLocked},
+	{db.ErrAccountClosed, http.StatusLocked},
+}
+
+// storeErrorResponse translates an error returned by the store into a status
+// and body. The errors of storeErrorStatuses get their status. Unique
+// violations become 409, foreign key violations 403 and check violations
+// 422, all with a stable code; anything else is a 500.
+func storeErrorResponse(err error) (int, apiError) {
+	for _, known := range storeErrorStatuses {
+		if errors.Is(err, known.err) {
+			return errorResponse(known.status, err)
+		}
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return errorResponse(http.StatusInternalServerError, err)
+	}
+
+	var status int
+	var code string
+	switch pqErr.Code.Name() {
+	case "unique_violation":
+		status, code = http.StatusConflict, errCodeAlreadyExists
+	case "foreign_key_violation":
+		status, code = http.StatusForbidden, errCodeInvalidReference
+	case "check_violation":
+		status, code = http.StatusUnprocessableEntity, errCodeInvalidValue
+	default:
+		return errorResponse(http.StatusInternalServerError, err)
+	}
+
+	message, ok := constraintMessages[pqErr.Constraint]
+	if !ok {
+		message = pqErr.Message
+	}
+	return status, apiError{Code: code, Message: message}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreErrorResponse(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   apiError
+	}{
+		{
+			name:       "KnownUniqueConstraint",
+			err:        &pq.Error{Code: "23505", Constraint: "owner_currency_key", Message: "duplicate key"},
+			wantStatus: http.StatusConflict,
+			wantBody:   apiError{Code: errCodeAlreadyExists, Message: "an account in this currency already exists"},
+		},
+		{
+			name:       "OtherUniqueConstraint",
+			err:        &pq.Error{Code: "23505", Constraint: "something_key", Message: "duplicate key"},
+			wantStatus: http.StatusConflict,
+			wantBody:   apiError{Code: errCodeAlreadyExists, Message: "duplicate key"},
+		},
+		{
+			name:       "ForeignKey",
+			err:        fmt.Errorf("tx err: %w", &pq.Error{Code: "23503", Constraint: "accounts_owner_fkey"}),
+			wantStatus: http.StatusForbidden,
+			wantBody:   apiError{Code: errCodeInvalidReference, Message: "owner does not exist"},
+		},
+		{
+			name:       "CheckConstraint",
+			err:        &pq.Error{Code: "23514", Constraint: "transfers_fx_check", Message: "new row violates check constraint"},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   apiError{Code: errCodeInvalidValue, Message: "new row violates check constraint"},
+		},
+		{
+			name:       "InsufficientFunds",
+			err:        fmt.Errorf("%w: account 1", db.ErrInsufficientFunds),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   apiError{Code: "insufficient_funds", Message: "insufficient funds: account 1"},
+		},
+		{
+			name:       "PeriodClosed",
+			err:        fmt.Errorf("transfer: %w", db.ErrPeriodClosed),
+			wantStatus: http.StatusConflict,
+			wantBody:   apiError{Code: "period_closed", Message: "transfer: accounting period is closed for posting"},
+		},
+		{
+			name:       "TransferLimitExceeded",
+			err:        db.ErrTransferLimitExceeded,
+			wantStatus: http.StatusForbidden,
+			wantBody:   apiError{Code: "transfer_limit_exceeded", Message: "transfer limit exceeded"},
+		},
+		{
+			name:       "AccountFrozen",
+			err:        db.ErrAccountFrozen,
+			wantStatus: http.StatusLocked,
+			wantBody:   apiError{Code: "account_frozen", Message: "account is frozen"},
+		},
+		{
+			name:       "AccountClosed",
+			err:        db.ErrAccountClosed,
+			wantStatus: http.StatusLocked,
+			wantBody:   apiError{Code: "account_closed", Message: "account is closed"},
+		},
+		{
+			name:       "OtherPostgresError",
+			err:        &pq.Error{Code: "40001", Message: "could not serialize access"},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   apiError{Code: "internal_server_error", Message: "pq: could not serialize access"},
+		},
+		{
+			name:       "NotPostgres",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   apiError{Code: "internal_server_error", Message: "boom"},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			status, body := storeErrorResponse(tc.err)
+			require.Equal(t, tc.wantStatus, status)
+			require.Equal(t, tc.wantBody, body)
+		})
+	}
+}
@@ -20,7 +20,7 @@ func (server *Server) deposit(ctx *gin.Context) {
 		ID int64 `uri:"id" binding:"required,min=1"`
 	}
 	if err := ctx.ShouldBindUri(&uriReq); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
 		return
 	}
 
@@ -28,7 +28,7 @@ func (server *Server) deposit(ctx *gin.Context) {
 		Amount int64 `json:"amount" binding:"required,gt=0"`
 	}
 	if err := ctx.ShouldBindJSON(&bodyReq); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
 		return
 	}
 
@@ -36,29 +36,29 @@ func (server *Server) deposit(ctx *gin.Context) {
 	account, err := server.store.GetAccount(ctx, uriReq.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	if account.Owner != authPayload.Username {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("account doesn't belong to the authenticated user")))
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("account doesn't belong to the authenticated user")))
 		return
 	}
 
-	updated, err := server.store.UpdateAccountBalance(ctx, db.UpdateAccountBalanceParams{
-		ID:      uriReq.ID,
-		Balance: bodyReq.Amount,
+	result, err := server.store.DepositTx(ctx, db.DepositTxParams{
+		AccountID: uriReq.ID,
+		Amount:    bodyReq.Amount,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(storeErrorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, updated)
+	ctx.JSON(http.StatusOK, result)
 }
 
 
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type listEntriesRequest struct {
+	cursorPageRequest
+	// Only entries the caller tagged with this.
+	Tag string `form:"tag" binding:"omitempty,alphanum,max=32"`
+}
+
+// listEntries returns the ledger entries of one of the caller's accounts,
+// oldest first.
+func (server *Server) listEntries(ctx *gin.Context) {
+	var uriReq getAccountRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req listEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	afterID, err := req.lastID()
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uriReq.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("account doesn't belong to the authenticated user")))
+		return
+	}
+
+	entries, err := server.store.ListEntriesAfter(ctx, db.ListEntriesAfterParams{
+		AccountID: account.ID,
+		AfterID:   afterID,
+		Tag:       normalizeTag(req.Tag),
+		Owner:     authPayload.Username,
+		PageLimit: req.limit() + 1,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newListResponse(entries, req.limit(), func(entry db.Entry) int64 { return entry.ID }))
+}
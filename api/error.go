@@ -0,0 +1,222 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/limits"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/go-playground/validator/v10"
+)
+
+// apiError is the body of every error response. Clients branch on Code, which
+// is stable; Message is meant for people and may change.
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Set when the request failed validation, one entry per invalid field.
+	FieldErrors []fieldError `json:"field_errors,omitempty"`
+}
+
+// fieldError explains why one field of a request was rejected. Field is the
+// name the client sent, e.g. transfers[0].amount, Code the rule it broke and
+// Param the parameter of the rule, if it has one.
+type fieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+const (
+	errCodeValidation = "validation_failed"
+	// The request body has a value of the wrong JSON type.
+	errCodeType = "type"
+)
+
+// errorCodes names the errors clients need to tell apart from others with the
+// same status. Any other error gets the code of its status, e.g. not_found.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{sql.ErrNoRows, "not_found"},
+	{db.ErrInsufficientFunds, "insufficient_funds"},
+	{db.ErrAccountFrozen, "account_frozen"},
+	{db.ErrAccountClosed, "account_closed"},
+	{db.ErrAccountNotEmpty, "account_not_empty"},
+	{db.ErrPotsNotEmpty, "pots_not_empty"},
+	{db.ErrPotMoveToSelf, "pot_move_to_self"},
+	{db.ErrDepositReferenceUsed, "deposit_reference_used"},
+	{db.ErrPeriodClosed, "period_closed"},
+	{db.ErrTransferLimitExceeded, "transfer_limit_exceeded"},
+	{db.ErrIdempotencyKeyUsed, "idempotency_key_used"},
+	{db.ErrVersionConflict, "version_conflict"},
+	{db.ErrPaymentRequestAnswered, "payment_request_answered"},
+	{db.ErrKycDocumentReviewed, "kyc_document_reviewed"},
+	{db.ErrStandingDataSuperseded, "standing_data_superseded"},
+	{db.ErrEmailRateLimited, "rate_limited"},
+	{db.ErrTimeout, "timeout"},
+	{errRateLimited, "rate_limited"},
+	{limits.ErrNotAllowed, "kyc_tier_too_low"},
+	{token.ErrExpiredToken, "token_expired"},
+	{token.ErrInvalidToken, "token_invalid"},
+	{token.ErrDeviceMismatch, "token_device_mismatch"},
+	{util.ErrPasswordMismatch, "invalid_credentials"},
+	{errIdempotencyKeyMismatch, "idempotency_key_mismatch"},
+	{errIdempotentRequestInProgress, "idempotent_request_in_progress"},
+	{errInvalidCursor, "invalid_cursor"},
+	{errStepUpRequired, "step_up_required"},
+	{errPasskeyChallengeExpired, "passkey_challenge_expired"},
+	{errUserSuspended, "user_suspended"},
+	{errQuoteInvalid, "quote_invalid"},
+	{errQuoteExpired, "quote_expired"},
+	{errQuoteMismatch, "quote_mismatch"},
+	{errPaymentQRInvalid, "payment_qr_invalid"},
+	{errPaymentQRStale, "payment_qr_stale"},
+	{errLedgerArchived, "ledger_archived"},
+}
+
+// errorResponse builds the status and body of an error response. Validation
+// errors from request binding are broken down by field. Messages are in
+// English, localizeErrorsMiddleware translates them for other languages.
+//
+// A store call that timed out means the database is overloaded rather than
+// the server broken, so it is a 503 where the handler says 500.
+func errorResponse(status int, err error) (int, apiError) {
+	if status == http.StatusInternalServerError && errors.Is(err, db.ErrTimeout) {
+		status = http.StatusServiceUnavailable
+	}
+	rsp := apiError{Code: errorCode(status, err), Message: err.Error()}
+
+	var validationErrors validator.ValidationErrors
+	var typeError *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &validationErrors):
+		messages := make([]string, len(validationErrors))
+		for i, fe := range validationErrors {
+			rsp.FieldErrors = append(rsp.FieldErrors, newFieldError(fe))
+			messages[i] = rsp.FieldErrors[i].Field + " " + rsp.FieldErrors[i].Message
+		}
+		rsp.Code = errCodeValidation
+		rsp.Message = strings.Join(messages, ", ")
+	case errors.As(err, &typeError) && typeError.Field != "":
+		rsp.Code = errCodeValidation
+		rsp.FieldErrors = []fieldError{{
+			Field:   typeError.Field,
+			Code:    errCodeType,
+			Message: "must be " + jsonTypeName(typeError.Type),
+		}}
+		rsp.Message = typeError.Field + " " + rsp.FieldErrors[0].Message
+	}
+	return status, rsp
+}
+
+func errorCode(status int, err error) string {
+	for _, known := range errorCodes {
+		if errors.Is(err, known.err) {
+			return known.code
+		}
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+func newFieldError(fe validator.FieldError) fieldError {
+	// The namespace starts with the request type, which means nothing to
+	// clients. What follows uses the names registered by requestFieldName.
+	field := fe.Field()
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		field = path
+	}
+	return fieldError{Field: field, Code: fe.Tag(), Param: fe.Param(), Message: fieldErrorMessage(fe)}
+}
+
+// fieldErrorMessage describes a failed rule. The field name is left out so
+// clients can show the message next to the field.
+func fieldErrorMessage(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required", "required_without", "required_with":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "e164":
+		return "must be a phone number in E.164 format, e.g. +14155552671"
+	case "url":
+		return "must be a valid URL"
+	case "uuid":
+		return "must be a UUID"
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "currency":
+		return "must be a supported currency"
+	case "password":
+		return "does not meet the password policy"
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "datetime":
+		return "must be formatted as " + param
+	case "min", "gte":
+		return "must be at least " + sizeOf(fe.Kind(), param)
+	case "max", "lte":
+		return "must be at most " + sizeOf(fe.Kind(), param)
+	case "gt":
+		return "must be greater than " + sizeOf(fe.Kind(), param)
+	case "lt":
+		return "must be less than " + sizeOf(fe.Kind(), param)
+	case "len":
+		return "must be exactly " + sizeOf(fe.Kind(), param)
+	case "nefield":
+		return "must differ from " + param
+	}
+	return fmt.Sprintf("failed the %s check", fe.Tag())
+}
+
+// sizeOf words a limit the way the validator applies it: to the length of
+// strings, the number of items of slices and maps, and the value of numbers.
+func sizeOf(kind reflect.Kind, param string) string {
+	switch kind {
+	case reflect.String:
+		return param + " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return param + " items"
+	}
+	return param
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
+
+// requestFieldName makes the validator report fields by the name clients use:
+// the JSON key, or the query, form or path parameter.
+func requestFieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form", "uri"} {
+		// "-" would make the validator skip the field altogether.
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
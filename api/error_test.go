@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponseCode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		err      error
+		wantCode string
+	}{
+		{
+			name:     "KnownError",
+			status:   http.StatusUnprocessableEntity,
+			err:      db.ErrInsufficientFunds,
+			wantCode: "insufficient_funds",
+		},
+		{
+			name:     "WrappedKnownError",
+			status:   http.StatusLocked,
+			err:      fmt.Errorf("transfer tx: %w", db.ErrAccountFrozen),
+			wantCode: "account_frozen",
+		},
+		{
+			name:     "FromStatus",
+			status:   http.StatusUnauthorized,
+			err:      errors.New("account doesn't belong to the authenticated user"),
+			wantCode: "unauthorized",
+		},
+		{
+			name:     "InternalError",
+			status:   http.StatusInternalServerError,
+			err:      errors.New("boom"),
+			wantCode: "internal_server_error",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			status, rsp := errorResponse(tc.status, tc.err)
+			require.Equal(t, tc.status, status)
+			require.Equal(t, tc.wantCode, rsp.Code)
+			require.Equal(t, tc.err.Error(), rsp.Message)
+			require.Empty(t, rsp.FieldErrors)
+		})
+	}
+}
+
+func TestTimeoutErrorResponse(t *testing.T) {
+	err := fmt.Errorf("%w: %w", db.ErrTimeout, errors.New("pq: canceling statement due to user request"))
+
+	status, rsp := errorResponse(http.StatusInternalServerError, err)
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, "timeout", rsp.Code)
+
+	// Handlers that chose another status keep it.
+	status, _ = errorResponse(http.StatusBadGateway, err)
+	require.Equal(t, http.StatusBadGateway, status)
+}
+
+func TestValidationErrorResponse(t *testing.T) {
+	testCases := []struct {
+		name            string
+		url             string
+		body            string
+		wantMessage     string
+		wantFieldErrors []fieldError
+	}{
+		{
+			name:        "InvalidFields",
+			url:         "/users",
+			body:        `{"username": "bad name", "password": "secret-Passw0rd", "email": "nope"}`,
+			wantMessage: "username must contain only letters and digits, full_name is required, email must be a valid email address",
+			wantFieldErrors: []fieldError{
+				{Field: "username", Code: "alphanum", Message: "must contain only letters and digits"},
+				{Field: "full_name", Code: "required", Message: "is required"},
+				{Field: "email", Code: "email", Message: "must be a valid email address"},
+			},
+		},
+		{
+			name:        "WrongType",
+			url:         "/transfers",
+			body:        `{"from_account_id": 1, "to_account_id": 2, "amount": "10", "currency": "USD"}`,
+			wantMessage: "amount must be an integer",
+			wantFieldErrors: []fieldError{
+				{Field: "amount", Code: errCodeType, Message: "must be an integer"},
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := newTestServer(t, mockdb.NewMockStore(ctrl))
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, tc.url, bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, "someone", time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+			var rsp apiError
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.Equal(t, errCodeValidation, rsp.Code)
+			require.Equal(t, tc.wantMessage, rsp.Message)
+			require.Equal(t, tc.wantFieldErrors, rsp.FieldErrors)
+		})
+	}
+}
+
+func TestNestedFieldErrorName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"transfers": []gin.H{
+		{"from_account_id": 1, "to_account_id": 2, "amount": 0},
+	}})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/transfers/batch", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, "someone", time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	var rsp apiError
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, []fieldError{{Field: "transfers[0].amount", Code: "required", Message: "is required"}}, rsp.FieldErrors)
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+)
+
+// accountETag changes whenever the account or one of its pots does: every
+// update of an account bumps updated_at, and the balance is included in case
+// two updates land in the same microsecond. Moves between pots leave the
+// account alone, so the pot balances are hashed in.
+func accountETag(account db.Account, pots []db.Pot) string {
+	h := fnv.New64a()
+	for _, pot := range pots {
+		fmt.Fprintf(h, "%d:%d;", pot.ID, pot.Balance)
+	}
+	return fmt.Sprintf(`"%d-%d-%d-%x"`, account.ID, account.Balance, account.UpdatedAt.UnixMicro(), h.Sum64())
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Weak
+// validators are compared by their opaque tag, as RFC 9110 asks for GET.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountETag(t *testing.T) {
+	account := randomAccount()
+	account.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	changed := account
+	changed.Balance++
+
+	testCases := []struct {
+		name          string
+		account       db.Account
+		username      string
+		ifNoneMatch   string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "NoHeader",
+			account:  account,
+			username: account.Owner,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, accountETag(account, nil), recorder.Header().Get("ETag"))
+			},
+		},
+		{
+			name:        "NotModified",
+			account:     account,
+			username:    account.Owner,
+			ifNoneMatch: `"stale", ` + accountETag(account, nil),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, recorder.Code)
+				require.Equal(t, accountETag(account, nil), recorder.Header().Get("ETag"))
+				require.Zero(t, recorder.Body.Len())
+			},
+		},
+		{
+			name:        "WeakValidator",
+			account:     account,
+			username:    account.Owner,
+			ifNoneMatch: "W/" + accountETag(account, nil),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, recorder.Code)
+			},
+		},
+		{
+			name:        "Changed",
+			account:     changed,
+			username:    account.Owner,
+			ifNoneMatch: accountETag(account, nil),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, accountETag(changed, nil), recorder.Header().Get("ETag"))
+				requireBodyMatchAccount(t, recorder.Body, changed)
+			},
+		},
+		{
+			name:        "OtherUser",
+			account:     account,
+			username:    "unauthorized_user",
+			ifNoneMatch: accountETag(account, nil),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Empty(t, recorder.Header().Get("ETag"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+				Times(1).
+				Return(tc.account, nil)
+			store.EXPECT().ListPots(gomock.Any(), gomock.Eq(account.ID)).
+				AnyTimes().
+				Return(nil, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/accounts/"+db.AccountEntityID(account.ID), nil)
+			require.NoError(t, err)
+			if tc.ifNoneMatch != "" {
+				request.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
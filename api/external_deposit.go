@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type externalDepositRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+	Amount    int64 `json:"amount" binding:"required,gt=0"`
+	// The reference of the sending bank. A wire is credited once per
+	// reference, however often it is reported.
+	Reference string `json:"reference" binding:"required,printascii,max=64"`
+}
+
+// createExternalDeposit simulates an inbound wire into an account of the
+// user. Reporting the same wire again returns the first deposit with
+// replayed set, instead of crediting it twice.
+func (server *Server) createExternalDeposit(ctx *gin.Context) {
+	var req externalDepositRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, ok := server.ownAccount(ctx, req.AccountID)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.ExternalDepositTx(ctx, db.ExternalDepositTxParams{
+		Reference: req.Reference,
+		AccountID: account.ID,
+		Amount:    req.Amount,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalDepositAPI(t *testing.T) {
+	account := randomAccount()
+	amount := int64(250)
+	reference := "FT24071HX9Q2"
+	arg := db.ExternalDepositTxParams{Reference: reference, AccountID: account.ID, Amount: amount}
+	deposit := db.ExternalDepositTxResult{
+		Deposit: db.ExternalDeposit{ID: 3, Reference: reference, AccountID: account.ID, Amount: amount},
+		Account: db.Account{ID: account.ID, Owner: account.Owner, Balance: account.Balance + amount, Currency: account.Currency},
+		Entry:   db.Entry{ID: 8, AccountID: account.ID, Amount: amount, Kind: db.EntryKindDeposit},
+	}
+	replayed := deposit
+	replayed.Replayed = true
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			body:     gin.H{"account_id": account.ID, "amount": amount, "reference": reference},
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ExternalDepositTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(deposit, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.ExternalDepositTxResult
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.False(t, got.Replayed)
+				require.Equal(t, reference, got.Deposit.Reference)
+				require.Equal(t, account.Balance+amount, got.Account.Balance)
+			},
+		},
+		{
+			name:     "Replayed",
+			body:     gin.H{"account_id": account.ID, "amount": amount, "reference": reference},
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ExternalDepositTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(replayed, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.ExternalDepositTxResult
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.True(t, got.Replayed)
+				require.Equal(t, deposit.Entry.ID, got.Entry.ID)
+			},
+		},
+		{
+			name:     "ReferenceUsed",
+			body:     gin.H{"account_id": account.ID, "amount": amount + 1, "reference": reference},
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ExternalDepositTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ExternalDepositTxResult{}, db.ErrDepositReferenceUsed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "NotOwner",
+			body:     gin.H{"account_id": account.ID, "amount": amount, "reference": reference},
+			username: "someone-else",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ExternalDepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "MissingReference",
+			body:     gin.H{"account_id": account.ID, "amount": amount},
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ExternalDepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/deposits/external", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/fx"
+	"github.com/ankurdas111111/simplebank/settings"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// fxQuoteTTL is how long a quoted rate can be used for a transfer.
+	fxQuoteTTL = 30 * time.Second
+
+	fxQuotePurpose = "fx-quote"
+)
+
+var (
+	errFXAmountTooSmall = errors.New("amount too small for conversion")
+	errFXUnsupported    = errors.New("unsupported currency conversion")
+	errQuoteInvalid     = errors.New("invalid quote_id")
+	errQuoteExpired     = errors.New("quote has expired, ask for a new one")
+	errQuoteMismatch    = errors.New("quote is for different currencies or another amount")
+)
+
+// fxQuote is what a conversion costs. The fee is taken in the source currency
+// before converting, so ConvertedAmount is what the recipient gets.
+type fxQuote struct {
+	From            string    `json:"from"`
+	To              string    `json:"to"`
+	Amount          int64     `json:"amount"`
+	Fee             int64     `json:"fee"`
+	ConvertedAmount int64     `json:"converted_amount"`
+	Rate            float64   `json:"rate"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+// signedQuote is the payload of a quote ID. It names the user so a quote
+// can't be handed to someone else.
+type signedQuote struct {
+	fxQuote
+	Username string `json:"username"`
+}
+
+type fxQuoteRequest struct {
+	From   string `form:"from" binding:"required,currency"`
+	To     string `form:"to" binding:"required,currency,nefield=From"`
+	Amount int64  `form:"amount" binding:"required,gt=0"`
+}
+
+type fxQuoteResponse struct {
+	QuoteID string `json:"quote_id"`
+	fxQuote
+}
+
+// getFXQuote prices a conversion. Passing the quote ID to POST /transfers
+// within fxQuoteTTL gets the transfer exactly this rate and fee.
+func (server *Server) getFXQuote(ctx *gin.Context) {
+	var req fxQuoteRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	quote, err := server.quoteFX(ctx, user, req.From, req.To, req.Amount)
+	if err != nil {
+		ctx.JSON(fxQuoteErrorResponse(err))
+		return
+	}
+	quoteID, err := server.signQuote(signedQuote{fxQuote: quote, Username: user.Username})
+	if err != nil {
+		ctx.JSON(fxQuoteErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, fxQuoteResponse{QuoteID: quoteID, fxQuote: quote})
+}
+
+// quoteFX prices converting amount at today's rate, less the FX fee of the
+// user's tenant and the source currency.
+func (server *Server) quoteFX(ctx *gin.Context, user db.User, from, to string, amount int64) (fxQuote, error) {
+	feeBps, err := server.settings.Int64(ctx, user.Tenant, from, settings.KeyFXFeeBps, 0)
+	if err != nil {
+		return fxQuote{}, err
+	}
+	fee := amount * feeBps / 10_000
+
+	converted, rate, err := server.rates.Convert(ctx, amount-fee, from, to)
+	if err != nil {
+		if errors.Is(err, fx.ErrNoRate) {
+			return fxQuote{}, errFXUnsupported
+		}
+		return fxQuote{}, err
+	}
+	if converted <= 0 {
+		return fxQuote{}, errFXAmountTooSmall
+	}
+
+	return fxQuote{
+		From:            from,
+		To:              to,
+		Amount:          amount,
+		Fee:             fee,
+		ConvertedAmount: converted,
+		Rate:            rate,
+		ExpiresAt:       time.Now().Add(fxQuoteTTL).UTC().Truncate(time.Second),
+	}, nil
+}
+
+// quoteFor checks that a quote ID was issued to username, is still valid and
+// prices this conversion.
+func (server *Server) quoteFor(quoteID, username, from, to string, amount int64) (fxQuote, error) {
+	quote, err := server.verifyQuote(quoteID)
+	if err != nil {
+		return fxQuote{}, err
+	}
+	if quote.Username != username {
+		return fxQuote{}, errQuoteInvalid
+	}
+	if time.Now().After(quote.ExpiresAt) {
+		return fxQuote{}, errQuoteExpired
+	}
+	if quote.From != from || quote.To != to || quote.Amount != amount {
+		return fxQuote{}, errQuoteMismatch
+	}
+	return quote.fxQuote, nil
+}
+
+// A quote ID is a signed quote, so quotes don't need to be stored.
+func (server *Server) signQuote(quote signedQuote) (string, error) {
+	return server.signPayload(fxQuotePurpose, quote)
+}
+
+func (server *Server) verifyQuote(quoteID string) (signedQuote, error) {
+	var quote signedQuote
+	err := server.verifyPayload(fxQuotePurpose, quoteID, &quote)
+	if errors.Is(err, errBadSignature) {
+		return quote, errQuoteInvalid
+	}
+	return quote, err
+}
+
+func fxQuoteErrorResponse(err error) (int, apiError) {
+	switch {
+	case errors.Is(err, errFXUnsupported), errors.Is(err, errFXAmountTooSmall),
+		errors.Is(err, errQuoteInvalid), errors.Is(err, errQuoteMismatch):
+		return errorResponse(http.StatusBadRequest, err)
+	case errors.Is(err, errQuoteExpired):
+		return errorResponse(http.StatusGone, err)
+	case errors.Is(err, errSigningDisabled):
+		return errorResponse(http.StatusServiceUnavailable, err)
+	}
+	return errorResponse(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/settings"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// testFxRates are the rates of the fx_rates migration.
+var testFxRates = []db.FxRate{
+	{Base: util.USD, Quote: util.INR, Rate: 83},
+	{Base: util.EUR, Quote: util.INR, Rate: 90},
+}
+
+func TestGetFXQuoteAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	fxFee := []db.Setting{{Key: settings.KeyFXFeeBps, Value: "100"}}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "from=INR&to=USD&amount=8300",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListSettings(gomock.Any()).AnyTimes().Return(fxFee, nil)
+				store.EXPECT().ListFxRates(gomock.Any(), gomock.Any()).Times(1).Return(testFxRates, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got fxQuoteResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, int64(8300), got.Amount)
+				require.Equal(t, int64(83), got.Fee)
+				// (8300 - 83) / 83 = 99.
+				require.Equal(t, int64(99), got.ConvertedAmount)
+				require.WithinDuration(t, time.Now().Add(fxQuoteTTL), got.ExpiresAt, 2*time.Second)
+
+				quote, err := server.quoteFor(got.QuoteID, user.Username, util.INR, util.USD, 8300)
+				require.NoError(t, err)
+				require.Equal(t, got.fxQuote, quote)
+			},
+		},
+		{
+			name:  "SameCurrency",
+			query: "from=USD&to=USD&amount=100",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "AmountTooSmall",
+			query: "from=INR&to=USD&amount=1",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListSettings(gomock.Any()).AnyTimes().Return([]db.Setting{}, nil)
+				store.EXPECT().ListFxRates(gomock.Any(), gomock.Any()).Times(1).Return(testFxRates, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "NoRate",
+			query: "from=INR&to=USD&amount=8300",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListSettings(gomock.Any()).AnyTimes().Return([]db.Setting{}, nil)
+				store.EXPECT().ListFxRates(gomock.Any(), gomock.Any()).Times(1).Return([]db.FxRate{}, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errFXUnsupported.Error())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/fx/quote?"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestCreateTransferWithFXQuoteAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.KycTier = util.KYCTierFull
+	fromAccount := db.Account{ID: 1, Owner: user.Username, Balance: 10_000, Currency: util.INR}
+	toAccount := db.Account{ID: 2, Owner: user.Username, Balance: 0, Currency: util.USD}
+
+	// The quote is better than today's rate, so a transfer that used today's
+	// rate would be caught.
+	quote := fxQuote{
+		From:            util.INR,
+		To:              util.USD,
+		Amount:          830,
+		Fee:             30,
+		ConvertedAmount: 11,
+		Rate:            0.0125,
+		ExpiresAt:       time.Now().Add(fxQuoteTTL).UTC().Truncate(time.Second),
+	}
+	expired := quote
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+
+	testCases := []struct {
+		name          string
+		quote         func(server *Server) string
+		amount        int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			quote: func(server *Server) string {
+				quoteID, err := server.signQuote(signedQuote{fxQuote: quote, Username: user.Username})
+				require.NoError(t, err)
+				return quoteID
+			},
+			amount: quote.Amount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferTxFX(gomock.Any(), gomock.Eq(db.TransferTxFXParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					FromAmount:    quote.Amount,
+					ToAmount:      quote.ConvertedAmount,
+					Rate:          quote.Rate,
+					Fee:           quote.Fee,
+				})).
+					Times(1).
+					Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			quote: func(server *Server) string {
+				quoteID, err := server.signQuote(signedQuote{fxQuote: expired, Username: user.Username})
+				require.NoError(t, err)
+				return quoteID
+			},
+			amount: quote.Amount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferTxFX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusGone, recorder.Code)
+			},
+		},
+		{
+			name: "OtherAmount",
+			quote: func(server *Server) string {
+				quoteID, err := server.signQuote(signedQuote{fxQuote: quote, Username: user.Username})
+				require.NoError(t, err)
+				return quoteID
+			},
+			amount: quote.Amount + 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferTxFX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUser",
+			quote: func(server *Server) string {
+				quoteID, err := server.signQuote(signedQuote{fxQuote: quote, Username: "someone-else"})
+				require.NoError(t, err)
+				return quoteID
+			},
+			amount: quote.Amount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferTxFX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Forged",
+			quote: func(server *Server) string {
+				other := newTestServer(t, nil)
+				quoteID, err := other.signQuote(signedQuote{fxQuote: quote, Username: user.Username})
+				require.NoError(t, err)
+				return quoteID
+			},
+			amount: quote.Amount,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferTxFX(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).AnyTimes().Return(fromAccount, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).AnyTimes().Return(toAccount, nil)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).AnyTimes().Return(user, nil)
+			store.EXPECT().ListSettings(gomock.Any()).AnyTimes().Return([]db.Setting{}, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          tc.amount,
+				"quote_id":        tc.quote(server),
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+var errFxRateInPast = errors.New("valid_from must not be in the past, conversions already made used the rates in force then")
+
+type createFxRateRequest struct {
+	Base  string  `json:"base" binding:"required,currency"`
+	Quote string  `json:"quote" binding:"required,currency,nefield=Base"`
+	Rate  float64 `json:"rate" binding:"required,gt=0"`
+	// When the rate takes over from the current one, now if empty.
+	ValidFrom *time.Time `json:"valid_from"`
+}
+
+// createFxRate adds a rate for a pair of currencies. Rates are never edited,
+// so the rate of any past conversion can still be looked up.
+func (server *Server) createFxRate(ctx *gin.Context) {
+	var req createFxRateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	validFrom := time.Now()
+	if req.ValidFrom != nil {
+		if req.ValidFrom.Before(validFrom) {
+			ctx.JSON(errorResponse(http.StatusBadRequest, errFxRateInPast))
+			return
+		}
+		validFrom = *req.ValidFrom
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	rate, err := server.store.CreateFxRate(ctx, db.CreateFxRateParams{
+		Base:      req.Base,
+		Quote:     req.Quote,
+		Rate:      req.Rate,
+		ValidFrom: validFrom,
+		CreatedBy: authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	server.rates.Invalidate()
+	ctx.JSON(http.StatusOK, rate)
+}
+
+type listFxRateHistoryRequest struct {
+	Base     string `form:"base" binding:"required,currency"`
+	Quote    string `form:"quote" binding:"required,currency"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listFxRateHistory returns the rates of a pair, latest first, including the
+// ones that have yet to take over.
+func (server *Server) listFxRateHistory(ctx *gin.Context) {
+	var req listFxRateHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	rates, err := server.store.ListFxRateHistory(ctx, db.ListFxRateHistoryParams{
+		Base:   req.Base,
+		Quote:  req.Quote,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rates)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateFxRateAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+	validFrom := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"base": util.USD, "quote": util.INR, "rate": 84.5, "valid_from": validFrom},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateFxRateParams{
+					Base:      util.USD,
+					Quote:     util.INR,
+					Rate:      84.5,
+					ValidFrom: validFrom,
+					CreatedBy: admin.Username,
+				}
+				store.EXPECT().CreateFxRate(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.FxRate{ID: 3, Base: arg.Base, Quote: arg.Quote, Rate: arg.Rate, ValidFrom: arg.ValidFrom}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.FxRate
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, 84.5, got.Rate)
+			},
+		},
+		{
+			name: "InPast",
+			body: gin.H{"base": util.USD, "quote": util.INR, "rate": 84.5, "valid_from": time.Now().Add(-time.Hour)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFxRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SamePair",
+			body: gin.H{"base": util.USD, "quote": util.USD, "rate": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFxRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotPositive",
+			body: gin.H{"base": util.USD, "quote": util.INR, "rate": -1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFxRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+				Times(1).
+				Return(admin, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/fx_rates", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
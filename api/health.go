@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/db/migration"
+	"github.com/gin-gonic/gin"
+)
+
+// States reported by GET /healthz and GET /readyz.
+const (
+	healthOK          = "ok"
+	healthUnavailable = "unavailable"
+)
+
+// readinessProbeUser is the subject of the token minted to check the maker.
+// It is verified at once and never handed out.
+const readinessProbeUser = "readyz"
+
+type healthCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Version and Expected are only set by the migrations check.
+	Version  *int64 `json:"version,omitempty"`
+	Expected *int64 `json:"expected,omitempty"`
+}
+
+type readinessResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]healthCheck `json:"checks"`
+}
+
+// healthz is the liveness probe: it answers as long as the process can serve
+// requests and checks no dependencies, so a database outage doesn't get the
+// pod restarted.
+func (server *Server) healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": healthOK})
+}
+
+// readyz is the readiness probe for the load balancer. Unlike the public
+// status page it is meant for operators and reports why a check failed.
+func (server *Server) readyz(ctx *gin.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, statusCheckTimeout)
+	defer cancel()
+
+	rsp := readinessResponse{
+		Status: healthOK,
+		Checks: map[string]healthCheck{
+			"database":    newHealthCheck(server.store.Ping(checkCtx)),
+			"token_maker": newHealthCheck(server.checkTokenMaker()),
+			"migrations":  server.checkMigrations(checkCtx),
+		},
+	}
+
+	code := http.StatusOK
+	for _, check := range rsp.Checks {
+		if check.Status != healthOK {
+			rsp.Status = healthUnavailable
+			code = http.StatusServiceUnavailable
+		}
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(code, rsp)
+}
+
+func newHealthCheck(err error) healthCheck {
+	if err != nil {
+		return healthCheck{Status: healthUnavailable, Error: err.Error()}
+	}
+	return healthCheck{Status: healthOK}
+}
+
+// checkTokenMaker mints and verifies a short-lived token, which fails if the
+// maker was built without usable keys.
+func (server *Server) checkTokenMaker() error {
+	if server.tokenMaker == nil {
+		return errors.New("token maker is not initialized")
+	}
+	accessToken, err := server.tokenMaker.CreateToken(readinessProbeUser, time.Minute)
+	if err != nil {
+		return err
+	}
+	_, err = server.tokenMaker.VerifyToken(accessToken)
+	return err
+}
+
+// checkMigrations fails while a migration is dirty or the database is behind
+// the migrations this binary was built with. A newer schema is fine: it is
+// what a rolling deploy looks like from the old pods.
+func (server *Server) checkMigrations(ctx context.Context) healthCheck {
+	expected, err := migration.LatestVersion()
+	if err != nil {
+		return newHealthCheck(err)
+	}
+	current, err := server.store.SchemaVersion(ctx)
+	if err != nil {
+		check := newHealthCheck(err)
+		check.Expected = &expected
+		return check
+	}
+
+	check := healthCheck{Status: healthOK, Version: &current.Version, Expected: &expected}
+	switch {
+	case current.Dirty:
+		check.Status = healthUnavailable
+		check.Error = fmt.Sprintf("migration %d is dirty", current.Version)
+	case current.Version < expected:
+		check.Status = healthUnavailable
+		check.Error = fmt.Sprintf("schema version %d is behind %d", current.Version, expected)
+	}
+	return check
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankurdas111111/simplebank/db/migration"
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthzAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Liveness must not touch the database.
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"status":"ok"}`, recorder.Body.String())
+}
+
+func TestReadyzAPI(t *testing.T) {
+	latest, err := migration.LatestVersion()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, rsp readinessResponse)
+	}{
+		{
+			name: "Ready",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().SchemaVersion(gomock.Any()).Times(1).Return(db.SchemaVersion{Version: latest}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp readinessResponse) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, healthOK, rsp.Status)
+				require.Len(t, rsp.Checks, 3)
+				for name, check := range rsp.Checks {
+					require.Equal(t, healthOK, check.Status, name)
+				}
+				require.Equal(t, latest, *rsp.Checks["migrations"].Version)
+			},
+		},
+		{
+			name: "SchemaAhead",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().SchemaVersion(gomock.Any()).Times(1).Return(db.SchemaVersion{Version: latest + 1}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp readinessResponse) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, healthOK, rsp.Checks["migrations"].Status)
+			},
+		},
+		{
+			name: "DatabaseDown",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(errors.New("connection refused"))
+				store.EXPECT().SchemaVersion(gomock.Any()).Times(1).Return(db.SchemaVersion{}, errors.New("connection refused"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp readinessResponse) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+				require.Equal(t, healthUnavailable, rsp.Status)
+				require.Equal(t, healthCheck{Status: healthUnavailable, Error: "connection refused"}, rsp.Checks["database"])
+				require.Equal(t, healthOK, rsp.Checks["token_maker"].Status)
+				require.Equal(t, healthUnavailable, rsp.Checks["migrations"].Status)
+			},
+		},
+		{
+			name: "DirtyMigration",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().SchemaVersion(gomock.Any()).Times(1).Return(db.SchemaVersion{Version: latest, Dirty: true}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp readinessResponse) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+				require.Equal(t, healthUnavailable, rsp.Checks["migrations"].Status)
+				require.Contains(t, rsp.Checks["migrations"].Error, "dirty")
+			},
+		},
+		{
+			name: "SchemaBehind",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().SchemaVersion(gomock.Any()).Times(1).Return(db.SchemaVersion{Version: latest - 1}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp readinessResponse) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+				require.Equal(t, latest-1, *rsp.Checks["migrations"].Version)
+				require.Equal(t, latest, *rsp.Checks["migrations"].Expected)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/readyz", nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+
+			var rsp readinessResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			tc.checkResponse(t, recorder, rsp)
+		})
+	}
+}
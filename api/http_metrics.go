@@ -0,0 +1,23 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests no route matched, so probing for paths
+// can't create new series.
+const unmatchedRoute = "unmatched"
+
+// measureRequests records every request in the server's HTTP metrics. It is
+// the first middleware, so the duration includes the others.
+func (server *Server) measureRequests() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		done := server.httpMetrics.Start(route, ctx.Request.Method)
+		ctx.Next()
+		done(ctx.Writer.Status())
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	"github.com/ankurdas111111/simplebank/metrics"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasureRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	httpMetrics := metrics.NewHTTPMetrics()
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	WithHTTPMetrics(httpMetrics)(server)
+	// The middleware is only installed when the router is set up.
+	server.setupRouter()
+
+	for _, path := range []string{"/healthz", "/healthz", "/no-such-path/42"} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		server.router.ServeHTTP(recorder, request)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(httpMetrics.Collectors()...)
+	recorder := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).
+		ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := recorder.Body.String()
+	require.Contains(t, out, `simplebank_http_requests_total{method="GET",route="/healthz",status="200"} 2`)
+	// Paths no route matched share one series.
+	require.Contains(t, out, `simplebank_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	require.Contains(t, out, `simplebank_http_requests_in_flight{method="GET",route="/healthz"} 0`)
+}
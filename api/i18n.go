@@ -0,0 +1,222 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	languageEnglish = "en"
+	languageHindi   = "hi"
+)
+
+// errorMessages translates the messages of errors by their code. Messages are
+// written in English, so English needs no catalog. Codes of a bare status,
+// e.g. bad_request, are left out: their message is the only thing that says
+// what went wrong, so it's kept in English rather than replaced.
+var errorMessages = map[string]map[string]string{
+	languageHindi: {
+		"not_found":                      "अनुरोधित संसाधन नहीं मिला",
+		"insufficient_funds":             "खाते में पर्याप्त राशि नहीं है",
+		"account_frozen":                 "खाता फ़्रीज़ है",
+		"account_closed":                 "खाता बंद है",
+		"account_not_empty":              "खाते में राशि बाकी है, उसे भेजने के लिए कोई खाता बताएं",
+		"pots_not_empty":                 "खाते के पॉट में राशि है, पहले उसे बैलेंस में वापस डालें",
+		"pot_move_to_self":               "राशि दो अलग-अलग पॉट के बीच ही भेजी जा सकती है",
+		"deposit_reference_used":         "यह बैंक संदर्भ पहले ही किसी दूसरी जमा के लिए इस्तेमाल हो चुका है",
+		"period_closed":                  "यह लेखा अवधि पोस्टिंग के लिए बंद है",
+		"transfer_limit_exceeded":        "ट्रांसफ़र सीमा पार हो गई है",
+		"idempotency_key_used":           "यह idempotency key पहले ही इस्तेमाल हो चुकी है",
+		"version_conflict":               "खाता अपडेट के दौरान बदलता रहा, कृपया फिर से कोशिश करें",
+		"idempotency_key_mismatch":       "यह idempotency key किसी दूसरे अनुरोध के लिए इस्तेमाल हो चुकी है",
+		"idempotent_request_in_progress": "इस idempotency key वाला अनुरोध अभी चल रहा है, थोड़ी देर बाद फिर से कोशिश करें",
+		"payment_request_answered":       "इस भुगतान अनुरोध का जवाब पहले ही दिया जा चुका है",
+		"kyc_document_reviewed":          "इस KYC दस्तावेज़ की समीक्षा पहले ही हो चुकी है",
+		"kyc_tier_too_low":               "आपके KYC स्तर पर इसकी अनुमति नहीं है",
+		"standing_data_superseded":       "इस बदलाव की जगह बाद में किया गया बदलाव लागू हो चुका है",
+		"rate_limited":                   "बहुत अधिक अनुरोध, कृपया थोड़ी देर बाद फिर से कोशिश करें",
+		"timeout":                        "डेटाबेस ने समय पर जवाब नहीं दिया, कृपया थोड़ी देर बाद फिर से कोशिश करें",
+		"token_expired":                  "टोकन की समय-सीमा समाप्त हो गई है",
+		"token_invalid":                  "टोकन अमान्य है",
+		"token_device_mismatch":          "टोकन किसी दूसरे डिवाइस को जारी किया गया था",
+		"invalid_credentials":            "उपयोगकर्ता नाम या पासवर्ड गलत है",
+		"invalid_cursor":                 "पेज कर्सर अमान्य है",
+		"step_up_required":               "इस राशि के ट्रांसफ़र के लिए दोबारा पुष्टि ज़रूरी है, पहले /users/elevate कॉल करें",
+		"passkey_challenge_expired":      "पासकी चुनौती की समय-सीमा समाप्त हो गई है, फिर से शुरू करें",
+		"user_suspended":                 "उपयोगकर्ता निलंबित है",
+		"quote_invalid":                  "quote_id अमान्य है",
+		"quote_expired":                  "कोटेशन की समय-सीमा समाप्त हो गई है, नया कोटेशन लें",
+		"quote_mismatch":                 "कोटेशन दूसरी मुद्राओं या किसी दूसरी राशि के लिए है",
+		"payment_qr_invalid":             "payment_qr अमान्य है",
+		"payment_qr_stale":               "payment_qr अब इस खाते से मेल नहीं खाता, प्राप्तकर्ता से नया कोड मांगें",
+		"ledger_archived":                "इतनी पुरानी प्रविष्टियां संग्रहीत की जा चुकी हैं",
+	},
+}
+
+// fieldMessages translates field errors by the rule they broke. %s is the
+// parameter of the rule.
+var fieldMessages = map[string]map[string]string{
+	languageHindi: {
+		"required":         "आवश्यक है",
+		"required_with":    "आवश्यक है",
+		"required_without": "आवश्यक है",
+		"email":            "मान्य ईमेल पता होना चाहिए",
+		"e164":             "E.164 प्रारूप में फ़ोन नंबर होना चाहिए, जैसे +14155552671",
+		"url":              "मान्य URL होना चाहिए",
+		"uuid":             "UUID होना चाहिए",
+		"alphanum":         "में केवल अक्षर और अंक होने चाहिए",
+		"currency":         "समर्थित मुद्रा होनी चाहिए",
+		"password":         "पासवर्ड नीति के अनुरूप नहीं है",
+		"oneof":            "इनमें से एक होना चाहिए: %s",
+		"datetime":         "%s प्रारूप में होना चाहिए",
+		"min":              "कम से कम %s होना चाहिए",
+		"gte":              "कम से कम %s होना चाहिए",
+		"max":              "अधिकतम %s होना चाहिए",
+		"lte":              "अधिकतम %s होना चाहिए",
+		"gt":               "%s से अधिक होना चाहिए",
+		"lt":               "%s से कम होना चाहिए",
+		"len":              "ठीक %s होना चाहिए",
+		"nefield":          "%s से अलग होना चाहिए",
+		errCodeType:        "का प्रकार गलत है",
+	},
+}
+
+// sizeUnits translates the units sizeOf adds to a limit.
+var sizeUnits = map[string][]struct{ english, local string }{
+	languageHindi: {
+		{" characters long", " अक्षर"},
+		{" items", " आइटम"},
+	},
+}
+
+// negotiateLanguage picks the supported language the client prefers most from
+// an Accept-Language header, English when it names none of them.
+func negotiateLanguage(header string) string {
+	type preference struct {
+		language string
+		weight   float64
+	}
+	var preferences []preference
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		// Regional variants, e.g. hi-IN, get the messages of their language.
+		language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if language != languageEnglish && errorMessages[language] == nil {
+			continue
+		}
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			w, err := strconv.ParseFloat(q, 64)
+			if err != nil || w <= 0 {
+				continue
+			}
+			weight = w
+		}
+		preferences = append(preferences, preference{language, weight})
+	}
+	if len(preferences) == 0 {
+		return languageEnglish
+	}
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].weight > preferences[j].weight })
+	return preferences[0].language
+}
+
+// localizeError translates an error response built by errorResponse. Codes
+// are never translated, and anything without a translation stays in English.
+func localizeError(rsp apiError, language string) apiError {
+	if messages, ok := errorMessages[language]; ok {
+		if message, ok := messages[rsp.Code]; ok {
+			rsp.Message = message
+		}
+	}
+	if len(rsp.FieldErrors) == 0 {
+		return rsp
+	}
+
+	fieldErrors := make([]fieldError, len(rsp.FieldErrors))
+	messages := make([]string, len(rsp.FieldErrors))
+	for i, fe := range rsp.FieldErrors {
+		fieldErrors[i] = localizeFieldError(fe, language)
+		messages[i] = fieldErrors[i].Field + " " + fieldErrors[i].Message
+	}
+	rsp.FieldErrors = fieldErrors
+	if rsp.Code == errCodeValidation {
+		rsp.Message = strings.Join(messages, ", ")
+	}
+	return rsp
+}
+
+func localizeFieldError(fe fieldError, language string) fieldError {
+	format, ok := fieldMessages[language][fe.Code]
+	if !ok {
+		return fe
+	}
+	if !strings.Contains(format, "%s") {
+		fe.Message = format
+		return fe
+	}
+
+	param := fe.Param
+	switch fe.Code {
+	case "oneof":
+		param = strings.Join(strings.Fields(param), ", ")
+	case "min", "gte", "max", "lte", "gt", "lt", "len":
+		// The English message says whether the limit is a length, a count or
+		// a value.
+		for _, unit := range sizeUnits[language] {
+			if strings.HasSuffix(fe.Message, unit.english) {
+				param += unit.local
+				break
+			}
+		}
+	}
+	fe.Message = fmt.Sprintf(format, param)
+	return fe
+}
+
+// localizeErrorsMiddleware translates the error responses of a request into
+// the language of its Accept-Language header.
+func localizeErrorsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Vary", "Accept-Language")
+		language := negotiateLanguage(ctx.GetHeader("Accept-Language"))
+		if language != languageEnglish {
+			ctx.Writer = &localizingWriter{ResponseWriter: ctx.Writer, language: language}
+		}
+		ctx.Next()
+	}
+}
+
+// localizingWriter rewrites JSON error bodies as they are written. Handlers
+// write a whole response at once, so every write is a complete body.
+type localizingWriter struct {
+	gin.ResponseWriter
+	language string
+}
+
+func (w *localizingWriter) Write(body []byte) (int, error) {
+	if w.Status() < 400 || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		return w.ResponseWriter.Write(body)
+	}
+
+	var rsp apiError
+	if err := json.Unmarshal(body, &rsp); err != nil || rsp.Code == "" {
+		return w.ResponseWriter.Write(body)
+	}
+	localized, err := json.Marshal(localizeError(rsp, w.language))
+	if err != nil {
+		return w.ResponseWriter.Write(body)
+	}
+
+	w.Header().Set("Content-Language", w.language)
+	if _, err := w.ResponseWriter.Write(localized); err != nil {
+		return 0, err
+	}
+	// Callers check that their whole body was written.
+	return len(body), nil
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	testCases := []struct {
+		header string
+		want   string
+	}{
+		{"", languageEnglish},
+		{"hi", languageHindi},
+		{"hi-IN", languageHindi},
+		{"fr-FR, hi;q=0.8, en;q=0.5", languageHindi},
+		{"en;q=0.9, hi;q=0.4", languageEnglish},
+		{"hi;q=0, en", languageEnglish},
+		{"fr, de", languageEnglish},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.want, negotiateLanguage(tc.header), tc.header)
+	}
+}
+
+// Every error code clients branch on has a message in every language.
+func TestErrorCodesTranslated(t *testing.T) {
+	for language, messages := range errorMessages {
+		for _, known := range errorCodes {
+			require.Contains(t, messages, known.code, language)
+		}
+	}
+}
+
+func TestLocalizedErrorResponse(t *testing.T) {
+	testCases := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		acceptLanguage string
+		wantMessage    string
+		wantFields     []fieldError
+	}{
+		{
+			name:           "Hindi",
+			method:         http.MethodPost,
+			url:            "/users",
+			body:           `{"username": "bad name", "password": "secret-Passw0rd", "full_name": "A", "email": "a@example.com"}`,
+			acceptLanguage: "hi-IN,hi;q=0.9,en;q=0.8",
+			wantMessage:    "username में केवल अक्षर और अंक होने चाहिए",
+			wantFields: []fieldError{
+				{Field: "username", Code: "alphanum", Message: "में केवल अक्षर और अंक होने चाहिए"},
+			},
+		},
+		{
+			name:           "HindiWithParam",
+			method:         http.MethodGet,
+			url:            "/users/me/insights/counterparties?limit=1000",
+			acceptLanguage: "hi",
+			wantMessage:    "limit अधिकतम 100 होना चाहिए",
+			wantFields: []fieldError{
+				{Field: "limit", Code: "max", Param: "100", Message: "अधिकतम 100 होना चाहिए"},
+			},
+		},
+		{
+			name:           "English",
+			method:         http.MethodGet,
+			url:            "/users/me/insights/counterparties?limit=1000",
+			acceptLanguage: "fr, en;q=0.5",
+			wantMessage:    "limit must be at most 100",
+			wantFields: []fieldError{
+				{Field: "limit", Code: "max", Param: "100", Message: "must be at most 100"},
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := newTestServer(t, mockdb.NewMockStore(ctrl))
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+			request.Header.Set("Accept-Language", tc.acceptLanguage)
+			addAuthorization(t, request, server.tokenMaker, "someone", time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+			require.Equal(t, "Accept-Language", recorder.Header().Get("Vary"))
+
+			var rsp apiError
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.Equal(t, errCodeValidation, rsp.Code)
+			require.Equal(t, tc.wantMessage, rsp.Message)
+			require.Equal(t, tc.wantFields, rsp.FieldErrors)
+		})
+	}
+}
+
+func TestLocalizeFieldErrorUnits(t *testing.T) {
+	fe := localizeFieldError(fieldError{
+		Field:   "reference",
+		Code:    "max",
+		Param:   "64",
+		Message: "must be at most 64 characters long",
+	}, languageHindi)
+	require.Equal(t, "अधिकतम 64 अक्षर होना चाहिए", fe.Message)
+
+	fe = localizeFieldError(fieldError{
+		Field:   "granularity",
+		Code:    "oneof",
+		Param:   "daily weekly monthly",
+		Message: "must be one of daily, weekly, monthly",
+	}, languageHindi)
+	require.Equal(t, "इनमें से एक होना चाहिए: daily, weekly, monthly", fe.Message)
+}
+
+func TestLocalizedErrorCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/accounts", nil)
+	require.NoError(t, err)
+	request.Header.Set("Accept-Language", "hi")
+	addAuthorization(t, request, server.tokenMaker, "someone", -time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Equal(t, languageHindi, recorder.Header().Get("Content-Language"))
+
+	var rsp apiError
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	// The code stays the same whatever the language.
+	require.Equal(t, "token_expired", rsp.Code)
+	require.Equal(t, errorMessages[languageHindi]["token_expired"], rsp.Message)
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+
+	// idempotentRequestTimeout is how long a request claimed by idempotent
+	// can stay in progress before a retry takes its key over. It is well
+	// past the time any request runs.
+	idempotentRequestTimeout = 5 * time.Minute
+)
+
+var (
+	errIdempotencyKeyMismatch      = errors.New("idempotency key was already used for a different request")
+	errIdempotentRequestInProgress = errors.New("a request with this idempotency key is still in progress, retry later")
+)
+
+// idempotencyKey reads the Idempotency-Key header and hashes the request,
+// whose body stays readable for binding. Without the header it returns a zero
+// key, which the store ignores.
+func idempotencyKey(ctx *gin.Context, username string) (db.ClaimIdempotencyKeyParams, error) {
+	key := ctx.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		return db.ClaimIdempotencyKeyParams{}, nil
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return db.ClaimIdempotencyKeyParams{}, errors.New("idempotency key is too long")
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return db.ClaimIdempotencyKeyParams{}, err
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	return db.ClaimIdempotencyKeyParams{
+		Username:    username,
+		Key:         key,
+		RequestHash: requestHash(ctx.Request.Method, ctx.FullPath(), ctx.Params, body),
+	}, nil
+}
+
+// requestHash fingerprints a request by its route, path parameters and body,
+// so that a key reused with the same body for another route or account is
+// told apart from a retry. The route is taken without the /api prefix, like
+// routeScopes, since either path reaches it.
+func requestHash(method, fullPath string, params gin.Params, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%q\n", routeScopeKey(method, fullPath))
+	for _, param := range params {
+		fmt.Fprintf(h, "%q=%q\n", param.Key, param.Value)
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replayIdempotentRequest answers a retried request with the stored response
+// and reports whether it did. Reusing a key for a different request is
+// rejected with 422.
+func (server *Server) replayIdempotentRequest(ctx *gin.Context, key db.ClaimIdempotencyKeyParams) bool {
+	if key.Key == "" {
+		return false
+	}
+
+	stored, err := server.store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Username: key.Username,
+		Key:      key.Key,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return true
+	}
+
+	if stored.RequestHash != key.RequestHash {
+		ctx.JSON(errorResponse(http.StatusUnprocessableEntity, errIdempotencyKeyMismatch))
+		return true
+	}
+	if stored.Status == db.IdempotencyInProgress {
+		ctx.JSON(errorResponse(http.StatusConflict, errIdempotentRequestInProgress))
+		return true
+	}
+
+	ctx.Header(idempotentReplayedHeader, "true")
+	ctx.Data(int(stored.ResponseStatus), "application/json; charset=utf-8", stored.Response)
+	return true
+}
+
+// replayConcurrentRequest handles a claim that lost to a concurrent request
+// with the same key, which has committed by the time the claim fails.
+func (server *Server) replayConcurrentRequest(ctx *gin.Context, key db.ClaimIdempotencyKeyParams, err error) {
+	if !server.replayIdempotentRequest(ctx, key) {
+		ctx.JSON(errorResponse(http.StatusConflict, err))
+	}
+}
+
+// idempotent makes the handlers after it idempotent by Idempotency-Key, for
+// routes that don't claim the key in their own transaction. The key is
+// claimed before the request runs and completed with its response, which
+// retries are answered with. A request that fails with a server error may not
+// have happened, so its key is released for a retry.
+func (server *Server) idempotent() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		key, err := idempotencyKey(ctx, authPayload.Username)
+		if err != nil {
+			ctx.AbortWithStatusJSON(errorResponse(http.StatusBadRequest, err))
+			return
+		}
+		if key.Key == "" {
+			ctx.Next()
+			return
+		}
+		if server.replayIdempotentRequest(ctx, key) {
+			ctx.Abort()
+			return
+		}
+
+		_, err = server.store.StartIdempotentRequest(ctx, db.StartIdempotentRequestParams{
+			Username:    key.Username,
+			Key:         key.Key,
+			RequestHash: key.RequestHash,
+			StaleBefore: time.Now().Add(-idempotentRequestTimeout),
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				server.replayConcurrentRequest(ctx, key, db.ErrIdempotencyKeyUsed)
+			} else {
+				ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+			}
+			ctx.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+
+		// The key is settled even if the client has gone, as its retry will
+		// come.
+		settleCtx := context.WithoutCancel(ctx.Request.Context())
+		if writer.Status() >= http.StatusInternalServerError || !json.Valid(writer.body.Bytes()) {
+			err = server.store.DeleteIdempotentRequest(settleCtx, db.DeleteIdempotentRequestParams{
+				Username: key.Username,
+				Key:      key.Key,
+			})
+		} else {
+			err = server.store.FinishIdempotentRequest(settleCtx, db.FinishIdempotentRequestParams{
+				Username:       key.Username,
+				Key:            key.Key,
+				ResponseStatus: int32(writer.Status()),
+				Response:       writer.body.Bytes(),
+			})
+		}
+		if err != nil {
+			// The response is already on its way; retries will be told the
+			// request is in progress until the claim times out.
+			server.logger.Error("idempotency key not completed", "idempotency_key", key.Key, "user", key.Username, "error", err)
+		}
+	}
+}
+
+// recordingWriter keeps a copy of the response body for idempotent.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,367 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferIdempotency(t *testing.T) {
+	user, _ := randomUser(t)
+	user.KycTier = util.KYCTierFull
+	fromAccount := db.Account{ID: 1, Owner: user.Username, Balance: 10_000, Currency: util.INR}
+	toAccount := db.Account{ID: 2, Owner: user.Username, Balance: 0, Currency: util.INR}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"from_account_id": fromAccount.ID,
+		"to_account_id":   toAccount.ID,
+		"amount":          50,
+	})
+	require.NoError(t, err)
+	key := db.ClaimIdempotencyKeyParams{
+		Username:    user.Username,
+		Key:         "retry-me",
+		RequestHash: requestHash(http.MethodPost, "/api/transfers", nil, body),
+	}
+	getKey := db.GetIdempotencyKeyParams{Username: key.Username, Key: key.Key}
+	stored := db.IdempotencyKey{
+		Username:       key.Username,
+		Key:            key.Key,
+		RequestHash:    key.RequestHash,
+		Response:       json.RawMessage(`{"transfer":{"id":7}}`),
+		Status:         db.IdempotencyCompleted,
+		ResponseStatus: http.StatusOK,
+	}
+	result := db.TransferTxResult{Transfer: db.Transfer{ID: 7, FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: 50}}
+
+	transferStubs := func(store *mockdb.MockStore) {
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+		store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+		store.EXPECT().ListSettings(gomock.Any()).AnyTimes().Return([]db.Setting{}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		key           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FirstRequest",
+			key:  key.Key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKey)).Times(1).Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				transferStubs(store)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(db.TransferTxParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        50,
+					Idempotency:   key,
+				})).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get(idempotentReplayedHeader))
+			},
+		},
+		{
+			name: "Retry",
+			key:  key.Key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKey)).Times(1).Return(stored, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+				require.JSONEq(t, string(stored.Response), recorder.Body.String())
+			},
+		},
+		{
+			name: "ConcurrentRetry",
+			key:  key.Key,
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKey)).Times(1).Return(db.IdempotencyKey{}, sql.ErrNoRows),
+					store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKey)).Times(1).Return(stored, nil),
+				)
+				transferStubs(store)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrIdempotencyKeyUsed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+			},
+		},
+		{
+			name: "DifferentRequest",
+			key:  key.Key,
+			buildStubs: func(store *mockdb.MockStore) {
+				other := stored
+				other.RequestHash = "something-else"
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKey)).Times(1).Return(other, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "NoKey",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Any()).Times(0)
+				transferStubs(store)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(db.TransferTxParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        50,
+				})).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "KeyTooLong",
+			key:  strings.Repeat("k", maxIdempotencyKeyLength+1),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/api/transfers", bytes.NewReader(body))
+			require.NoError(t, err)
+			if tc.key != "" {
+				request.Header.Set(idempotencyKeyHeader, tc.key)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestIdempotentMiddleware(t *testing.T) {
+	account := randomAccount()
+	body := []byte(`{"amount":10}`)
+	params := gin.Params{{Key: "id", Value: fmt.Sprint(account.ID)}}
+	start := db.StartIdempotentRequestParams{
+		Username:    account.Owner,
+		Key:         "deposit-once",
+		RequestHash: requestHash(http.MethodPost, "/api/accounts/:id/deposit", params, body),
+	}
+	getKey := db.GetIdempotencyKeyParams{Username: start.Username, Key: start.Key}
+	stored := db.IdempotencyKey{
+		Username:       start.Username,
+		Key:            start.Key,
+		RequestHash:    start.RequestHash,
+		Response:       json.RawMessage(`{"entry":{"id":3}}`),
+		Status:         db.IdempotencyCompleted,
+		ResponseStatus: http.StatusOK,
+	}
+	result := db.DepositTxResult{
+		Account: account,
+		Entry:   db.Entry{ID: 3, AccountID: account.ID, Amount: 10, Kind: db.EntryKindDeposit},
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FirstRequest",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKey)).Times(1).Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().StartIdempotentRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.StartIdempotentRequestParams) (db.IdempotencyKey, error) {
+						require.Equal(t, start.RequestHash, arg.RequestHash)
+						// Claims older than the timeout are taken over.
+						require.WithinDuration(t, time.Now().Add(-idempotentRequestTimeout), arg.StaleBefore, time.Minute)
+						return db.IdempotencyKey{}, nil
+					})
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+				store.EXPECT().FinishIdempotentRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.FinishIdempotentRequestParams) error {
+						require.Equal(t, start.Key, arg.Key)
+						require.Equal(t, int32(http.StatusOK), arg.ResponseStatus)
+						var got db.DepositTxResult
+						require.NoError(t, json.Unmarshal(arg.Response, &got))
+						require.Equal(t, result.Entry.ID, got.Entry.ID)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get(idempotentReplayedHeader))
+			},
+		},
+		{
+			name: "Retry",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKey)).Times(1).Return(stored, nil)
+				store.EXPECT().StartIdempotentRequest(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+				require.JSONEq(t, string(stored.Response), recorder.Body.String())
+			},
+		},
+		{
+			name: "InProgress",
+			buildStubs: func(store *mockdb.MockStore) {
+				running := stored
+				running.Status = db.IdempotencyInProgress
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKey)).Times(1).Return(running, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "idempotent_request_in_progress")
+			},
+		},
+		{
+			name: "ConcurrentClaim",
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKey)).Times(1).Return(db.IdempotencyKey{}, sql.ErrNoRows),
+					store.EXPECT().StartIdempotentRequest(gomock.Any(), gomock.Any()).Times(1).Return(db.IdempotencyKey{}, sql.ErrNoRows),
+					store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKey)).Times(1).Return(stored, nil),
+				)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+			},
+		},
+		{
+			name: "ServerError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKey)).Times(1).Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().StartIdempotentRequest(gomock.Any(), gomock.Any()).Times(1).Return(db.IdempotencyKey{}, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(1).Return(db.DepositTxResult{}, sql.ErrConnDone)
+				store.EXPECT().DeleteIdempotentRequest(gomock.Any(), gomock.Eq(db.DeleteIdempotentRequestParams{
+					Username: start.Username,
+					Key:      start.Key,
+				})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().FinishIdempotentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts/%d/deposit", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set(idempotencyKeyHeader, start.Key)
+
+			addAuthorization(t, request, server.tokenMaker, account.Owner, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestIdempotencyKeyReusedElsewhere(t *testing.T) {
+	account := randomAccount()
+	body := []byte(`{"amount":100}`)
+	params := gin.Params{{Key: "id", Value: fmt.Sprint(account.ID)}}
+	stored := db.IdempotencyKey{
+		Username:       account.Owner,
+		Key:            "reused",
+		RequestHash:    requestHash(http.MethodPost, "/api/accounts/:id/deposit", params, body),
+		Response:       json.RawMessage(`{"entry":{"id":3}}`),
+		Status:         db.IdempotencyCompleted,
+		ResponseStatus: http.StatusOK,
+	}
+
+	// The same key and body as a deposit to the account, sent elsewhere.
+	for _, url := range []string{
+		fmt.Sprintf("/api/accounts/%d/withdraw", account.ID),
+		fmt.Sprintf("/api/accounts/%d/deposit", account.ID+1),
+	} {
+		t.Run(url, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(db.GetIdempotencyKeyParams{Username: stored.Username, Key: stored.Key})).
+				Times(1).
+				Return(stored, nil)
+			store.EXPECT().StartIdempotentRequest(gomock.Any(), gomock.Any()).Times(0)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set(idempotencyKeyHeader, stored.Key)
+
+			addAuthorization(t, request, server.tokenMaker, account.Owner, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			require.Empty(t, recorder.Header().Get(idempotentReplayedHeader))
+		})
+	}
+}
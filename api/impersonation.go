@@ -0,0 +1,204 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// impersonationScopes is what an admin can do as a customer: everything the
+// customer can, but not mint further tokens or reach admin routes.
+func impersonationScopes() []string {
+	var scopes []string
+	for _, scope := range token.AllScopes() {
+		if isDelegable(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+type createImpersonationRequest struct {
+	Username        string `json:"username" binding:"required,alphanum"`
+	DurationMinutes int32  `json:"duration_minutes" binding:"required,min=1,max=60"`
+}
+
+type createImpersonationResponse struct {
+	AccessToken string       `json:"access_token"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+	User        UserResponse `json:"user"`
+}
+
+// createImpersonation mints a short-lived token for support staff to act as
+// a customer. The token names the admin, and both the grant and every request
+// made with it are audited.
+func (server *Server) createImpersonation(ctx *gin.Context) {
+	var req createImpersonationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	info := requestAuditInfo(ctx)
+	_, err = server.store.CreateAuditLog(ctx, db.CreateAuditLogParams{
+		Actor:     authPayload.Username,
+		Action:    "impersonate",
+		Resource:  "users/" + user.Username,
+		ClientIp:  info.ClientIP,
+		RequestID: info.RequestID,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	accessToken, err := server.tokenMaker.CreateToken(
+		user.Username,
+		time.Duration(req.DurationMinutes)*time.Minute,
+		token.WithScopes(impersonationScopes()...),
+		token.WithImpersonator(authPayload.Username),
+	)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(accessToken)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createImpersonationResponse{
+		AccessToken: accessToken,
+		ExpiresAt:   payload.ExpiredAt,
+		User:        newUserResponse(user),
+	})
+}
+
+// auditMiddleware names the user, and the admin impersonating them, in the
+// audit info writes of the request are audited with. Every request made with
+// an impersonation token is also written to the audit log before it is
+// handled. If the entry cannot be written the request is refused. It must run
+// after authMiddleware.
+func auditMiddleware(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		info := requestAuditInfo(ctx)
+		info.Actor = authPayload.Username
+		info.Impersonator = authPayload.ImpersonatedBy
+		ctx.Set(db.AuditInfoKey, info)
+		if !authPayload.IsImpersonation() {
+			ctx.Next()
+			return
+		}
+
+		_, err := store.CreateAuditLog(ctx, db.CreateAuditLogParams{
+			Actor:        authPayload.Username,
+			Impersonator: sql.NullString{String: authPayload.ImpersonatedBy, Valid: true},
+			Action:       routeScopeKey(ctx.Request.Method, ctx.FullPath()),
+			Resource:     ctx.Request.URL.Path,
+			ClientIp:     info.ClientIP,
+			RequestID:    info.RequestID,
+		})
+		if err != nil {
+			ctx.AbortWithStatusJSON(errorResponse(http.StatusInternalServerError, err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+type auditLogsRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type listAuditLogsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listAuditLogs returns the audit trail of a user, including actions taken
+// by admins impersonating them and by them as an impersonator.
+func (server *Server) listAuditLogs(ctx *gin.Context) {
+	var uriReq auditLogsRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req listAuditLogsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	logs, err := server.store.ListAuditLogs(ctx, db.ListAuditLogsParams{
+		Actor:  uriReq.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, logs)
+}
+
+type searchAuditLogsRequest struct {
+	cursorPageRequest
+	// Only entries made by this user, or by an admin impersonating them.
+	Actor  string `form:"actor" binding:"omitempty,max=64"`
+	Action string `form:"action" binding:"omitempty,max=64"`
+	// Only entries about this resource, e.g. accounts/42.
+	Resource  string `form:"resource" binding:"omitempty,max=200"`
+	RequestID string `form:"request_id" binding:"omitempty,max=128"`
+}
+
+// searchAuditLogs lists the audit log, newest first. The filters narrow it to
+// a user, an action, a resource or one request.
+func (server *Server) searchAuditLogs(ctx *gin.Context) {
+	var req searchAuditLogsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	beforeID, err := req.lastID()
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	logs, err := server.store.SearchAuditLogs(ctx, db.SearchAuditLogsParams{
+		BeforeID:  beforeID,
+		Actor:     req.Actor,
+		Action:    req.Action,
+		Resource:  req.Resource,
+		RequestID: req.RequestID,
+		PageLimit: req.limit() + 1,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newListResponse(logs, req.limit(), func(entry db.AuditLog) int64 { return entry.ID }))
+}
@@ -0,0 +1,297 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateImpersonationAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+	customer, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"username": customer.Username, "duration_minutes": 15},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(customer.Username)).
+					Times(1).
+					Return(customer, nil)
+				arg := db.CreateAuditLogParams{
+					Actor:     admin.Username,
+					Action:    "impersonate",
+					Resource:  "users/" + customer.Username,
+					ClientIp:  "",
+					RequestID: "request-1",
+				}
+				store.EXPECT().CreateAuditLog(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AuditLog{}, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp createImpersonationResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				payload, err := server.tokenMaker.VerifyToken(rsp.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, customer.Username, payload.Username)
+				require.Equal(t, admin.Username, payload.ImpersonatedBy)
+				require.False(t, payload.HasScope(token.ScopeAdmin))
+				require.False(t, payload.HasScope(token.ScopeTokensWrite))
+				require.True(t, payload.HasScope(token.ScopeAccountsRead))
+				require.WithinDuration(t, time.Now().Add(15*time.Minute), payload.ExpiredAt, time.Minute)
+			},
+		},
+		{
+			name: "UserNotFound",
+			body: gin.H{"username": customer.Username, "duration_minutes": 15},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(customer.Username)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "TooLong",
+			body: gin.H{"username": customer.Username, "duration_minutes": 600},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(customer.Username)).Times(0)
+				store.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+				Times(1).
+				Return(admin, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/impersonations", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			request.Header.Set(requestIDHeader, "request-1")
+			addAuthorization(t, request, server.tokenMaker, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestImpersonatedRequestIsAudited(t *testing.T) {
+	admin, _ := randomUser(t)
+	customer, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		opts          []token.PayloadOption
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Impersonated",
+			opts: []token.PayloadOption{token.WithImpersonator(admin.Username)},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateAuditLogParams{
+					Actor:        customer.Username,
+					Impersonator: sql.NullString{String: admin.Username, Valid: true},
+					Action:       "GET /accounts",
+					Resource:     "/api/accounts",
+					RequestID:    "request-1",
+				}
+				store.EXPECT().CreateAuditLog(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AuditLog{}, nil)
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Account{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotImpersonated",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, _ db.ListAccountsAfterParams) ([]db.Account, error) {
+						// Writes made by the handler would be audited with this.
+						require.Equal(t, db.AuditInfo{Actor: customer.Username, RequestID: "request-1"}, ctx.Value(db.AuditInfoKey))
+						return []db.Account{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "AuditFailure",
+			opts: []token.PayloadOption{token.WithImpersonator(admin.Username)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AuditLog{}, sql.ErrConnDone)
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts?limit=%d", 5)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			request.Header.Set(requestIDHeader, "request-1")
+			addAuthorization(t, request, server.tokenMaker, customer.Username, time.Minute, tc.opts...)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSearchAuditLogsAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	entries := make([]db.AuditLog, 3)
+	for i := range entries {
+		entries[i] = db.AuditLog{
+			ID:        int64(30 - i),
+			Actor:     admin.Username,
+			Action:    "account.adjust_balance",
+			Resource:  "accounts/7",
+			RequestID: "request-1",
+			Before:    json.RawMessage(`{"balance":100}`),
+			After:     json.RawMessage(`{"balance":150}`),
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?resource=accounts/7&request_id=request-1&limit=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+					Times(1).
+					Return(admin, nil)
+				arg := db.SearchAuditLogsParams{
+					Resource:  "accounts/7",
+					RequestID: "request-1",
+					PageLimit: 3,
+				}
+				store.EXPECT().SearchAuditLogs(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listResponse[db.AuditLog]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 2)
+				require.True(t, rsp.Page.HasMore)
+				require.Equal(t, encodeCursor(entries[1].ID), rsp.NextCursor)
+				require.JSONEq(t, `{"balance":150}`, string(rsp.Items[0].After))
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: "?cursor=nope",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+					Times(1).
+					Return(admin, nil)
+				store.EXPECT().SearchAuditLogs(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/audit-logs"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
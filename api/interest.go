@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// accruedInterestResponse is the interest an account has earned that is not
+// on its balance yet. Amount is what would be posted now; the fraction of a
+// unit in AmountMicros carries over.
+type accruedInterestResponse struct {
+	AccountID    int64            `json:"account_id"`
+	Amount       int64            `json:"amount"`
+	AmountMicros int64            `json:"amount_micros"`
+	Days         []accruedDayItem `json:"days"`
+}
+
+type accruedDayItem struct {
+	Date         string `json:"date"`
+	Balance      int64  `json:"balance"`
+	RateBps      int64  `json:"rate_bps"`
+	AmountMicros int64  `json:"amount_micros"`
+}
+
+// getAccruedInterest lists the days of interest accrued since it was last
+// posted to the account.
+func (server *Server) getAccruedInterest(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, ok := server.ownAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	accruals, err := server.store.ListUnpostedInterestAccruals(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	rsp := accruedInterestResponse{AccountID: account.ID, Days: make([]accruedDayItem, len(accruals))}
+	for i, accrual := range accruals {
+		rsp.AmountMicros += accrual.AmountMicros
+		rsp.Days[i] = accruedDayItem{
+			Date:         accrual.AccrualDate.Format(statementDateLayout),
+			Balance:      accrual.Balance,
+			RateBps:      accrual.RateBps,
+			AmountMicros: accrual.AmountMicros,
+		}
+	}
+	rsp.Amount = rsp.AmountMicros / db.MicrosPerUnit
+
+	ctx.JSON(http.StatusOK, rsp)
+}
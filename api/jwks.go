@@ -0,0 +1,17 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// getJWKS serves the token verification keys. Verifiers may cache them for a
+// few minutes; a new key only appears after a restart with a new key file.
+func getJWKS(keySet token.KeySetProvider) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Cache-Control", "public, max-age=300")
+		ctx.JSON(http.StatusOK, keySet.JWKS())
+	}
+}
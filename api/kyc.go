@@ -0,0 +1,214 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// maxKycDocumentSize is the largest file accepted by uploadKycDocument.
+const maxKycDocumentSize = 5 << 20
+
+// kycContentTypes lists the document formats reviewers can open.
+var kycContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+type uploadKycDocumentRequest struct {
+	DocumentType  string `form:"document_type" binding:"required,oneof=passport national_id drivers_license proof_of_address"`
+	RequestedTier string `form:"requested_tier" binding:"required,oneof=verified full"`
+}
+
+// uploadKycDocument stores a document for admin review. The user's tier only
+// changes once the document is approved.
+func (server *Server) uploadKycDocument(ctx *gin.Context) {
+	var req uploadKycDocumentRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if fileHeader.Size > maxKycDocumentSize {
+		ctx.JSON(errorResponse(http.StatusBadRequest, fmt.Errorf("file is larger than %d bytes", maxKycDocumentSize)))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, maxKycDocumentSize))
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	// Trust the bytes, not the client-supplied header.
+	contentType := http.DetectContentType(content)
+	if !kycContentTypes[contentType] {
+		ctx.JSON(errorResponse(http.StatusBadRequest, fmt.Errorf("unsupported document format %s", contentType)))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	doc, err := server.store.CreateKycDocument(ctx, db.CreateKycDocumentParams{
+		Username:      authPayload.Username,
+		DocumentType:  req.DocumentType,
+		RequestedTier: req.RequestedTier,
+		FileName:      fileHeader.Filename,
+		ContentType:   contentType,
+		Content:       content,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, doc)
+}
+
+type listKycDocumentsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listKycDocuments returns the documents uploaded by the authenticated user.
+func (server *Server) listKycDocuments(ctx *gin.Context) {
+	var req listKycDocumentsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	docs, err := server.store.ListKycDocuments(ctx, db.ListKycDocumentsParams{
+		Username: authPayload.Username,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, docs)
+}
+
+type listPendingKycDocumentsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listPendingKycDocuments is the admin review queue. It defaults to pending
+// documents, oldest first.
+func (server *Server) listPendingKycDocuments(ctx *gin.Context) {
+	var req listPendingKycDocumentsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if req.Status == "" {
+		req.Status = db.KycDocumentPending
+	}
+
+	docs, err := server.store.ListKycDocumentsByStatus(ctx, db.ListKycDocumentsByStatusParams{
+		Status: req.Status,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, docs)
+}
+
+type kycDocumentRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getKycDocumentFile serves the uploaded file so an admin can review it.
+func (server *Server) getKycDocumentFile(ctx *gin.Context) {
+	var req kycDocumentRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	doc, err := server.store.GetKycDocument(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.FileName))
+	ctx.Data(http.StatusOK, doc.ContentType, doc.Content)
+}
+
+type reviewKycDocumentRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject"`
+	Note     string `json:"note"`
+}
+
+// reviewKycDocument approves or rejects a pending document. Approving raises
+// the owner to the requested tier.
+func (server *Server) reviewKycDocument(ctx *gin.Context) {
+	var uriReq kycDocumentRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req reviewKycDocumentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.store.ReviewKycDocumentTx(ctx, db.ReviewKycDocumentTxParams{
+		DocumentID: uriReq.ID,
+		Approve:    req.Decision == "approve",
+		ReviewedBy: authPayload.Username,
+		Note:       req.Note,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		if errors.Is(err, db.ErrKycDocumentReviewed) {
+			ctx.JSON(errorResponse(http.StatusConflict, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"document": result.Document,
+		"user":     newUserResponse(result.User),
+	})
+}
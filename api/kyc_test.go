@@ -0,0 +1,221 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadKycDocumentAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	pdf := []byte("%PDF-1.4\n%test document\n")
+
+	testCases := []struct {
+		name          string
+		fields        map[string]string
+		content       []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			fields:  map[string]string{"document_type": "passport", "requested_tier": util.KYCTierVerified},
+			content: pdf,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateKycDocumentParams{
+					Username:      user.Username,
+					DocumentType:  "passport",
+					RequestedTier: util.KYCTierVerified,
+					FileName:      "passport.pdf",
+					ContentType:   "application/pdf",
+					Content:       pdf,
+				}
+				store.EXPECT().CreateKycDocument(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CreateKycDocumentRow{ID: 1, Username: user.Username, Status: db.KycDocumentPending}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidTier",
+			fields:  map[string]string{"document_type": "passport", "requested_tier": util.KYCTierBasic},
+			content: pdf,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateKycDocument(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "UnsupportedFormat",
+			fields:  map[string]string{"document_type": "passport", "requested_tier": util.KYCTierVerified},
+			content: []byte("just some text"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateKycDocument(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			for key, value := range tc.fields {
+				require.NoError(t, writer.WriteField(key, value))
+			}
+			part, err := writer.CreateFormFile("file", "passport.pdf")
+			require.NoError(t, err)
+			_, err = part.Write(tc.content)
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			request, err := http.NewRequest(http.MethodPost, "/kyc/documents", body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", writer.FormDataContentType())
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReviewKycDocumentAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+	customer, _ := randomUser(t)
+	documentID := util.RandomInt(1, 1000)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Approve",
+			body: gin.H{"decision": "approve", "note": "looks good"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+					Times(1).
+					Return(admin, nil)
+				arg := db.ReviewKycDocumentTxParams{
+					DocumentID: documentID,
+					Approve:    true,
+					ReviewedBy: admin.Username,
+					Note:       "looks good",
+				}
+				upgraded := customer
+				upgraded.KycTier = util.KYCTierVerified
+				store.EXPECT().ReviewKycDocumentTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ReviewKycDocumentTxResult{
+						Document: db.ReviewKycDocumentRow{ID: documentID, Status: db.KycDocumentApproved},
+						User:     upgraded,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidDecision",
+			body: gin.H{"decision": "maybe"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+					Times(1).
+					Return(admin, nil)
+				store.EXPECT().ReviewKycDocumentTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyReviewed",
+			body: gin.H{"decision": "reject"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+					Times(1).
+					Return(admin, nil)
+				store.EXPECT().ReviewKycDocumentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReviewKycDocumentTxResult{}, db.ErrKycDocumentReviewed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"decision": "reject"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+					Times(1).
+					Return(admin, nil)
+				store.EXPECT().ReviewKycDocumentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReviewKycDocumentTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/kyc/documents/%d/review", documentID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -15,6 +15,8 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
 		TokenSymmetricKey: util.RandomString(32),
 		AccessTokenDuration: time.Minute,
+		WebAuthnRPID: "bank.example.com",
+		WebAuthnOrigin: "https://bank.example.com",
 	}
 	server, err := NewServer(config, store)
 	require.NoError(t, err)
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 	"strings"
 
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
 	"github.com/ankurdas111111/simplebank/token"
+	"github.com/ankurdas111111/simplebank/util"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,6 +16,9 @@ const (
 	authorizationHeaderKey  = "authorization"
 	authorizationTypeBearer = "bearer"
 	authorizationPayloadKey = "authorization_payload"
+	// deviceIDHeaderKey carries the client's device fingerprint. Tokens issued
+	// at login are bound to it.
+	deviceIDHeaderKey = "x-device-id"
 )
 
 func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
@@ -19,21 +26,21 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
 			err := token.ErrInvalidToken
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			ctx.AbortWithStatusJSON(errorResponse(http.StatusUnauthorized, err))
 			return
 		}
 
 		fields := strings.Fields(authorizationHeader)
 		if len(fields) != 2 {
 			err := token.ErrInvalidToken
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			ctx.AbortWithStatusJSON(errorResponse(http.StatusUnauthorized, err))
 			return
 		}
 
 		authorizationType := strings.ToLower(fields[0])
 		if authorizationType != authorizationTypeBearer {
 			err := token.ErrInvalidToken
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			ctx.AbortWithStatusJSON(errorResponse(http.StatusUnauthorized, err))
 			return
 		}
 
@@ -41,7 +48,12 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
 			status := http.StatusUnauthorized
-			ctx.AbortWithStatusJSON(status, errorResponse(err))
+			ctx.AbortWithStatusJSON(errorResponse(status, err))
+			return
+		}
+
+		if !payload.MatchesDevice(ctx.GetHeader(deviceIDHeaderKey)) {
+			ctx.AbortWithStatusJSON(errorResponse(http.StatusUnauthorized, token.ErrDeviceMismatch))
 			return
 		}
 
@@ -50,4 +62,28 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	}
 }
 
+// adminMiddleware only lets admins through. It must run after authMiddleware.
+// The role is read from the database rather than the token so that revoking
+// admin rights takes effect immediately.
+func adminMiddleware(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		user, err := store.GetUser(ctx, authPayload.Username)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.AbortWithStatusJSON(errorResponse(http.StatusForbidden, errors.New("admin access required")))
+				return
+			}
+			ctx.AbortWithStatusJSON(errorResponse(http.StatusInternalServerError, err))
+			return
+		}
+
+		if user.Role != util.AdminRole {
+			ctx.AbortWithStatusJSON(errorResponse(http.StatusForbidden, errors.New("admin access required")))
+			return
+		}
 
+		ctx.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// notificationResponse is an in-app notification as it was sent on /ws.
+type notificationResponse struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func newNotificationResponse(notification db.Notification) notificationResponse {
+	return notificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Data:      notification.Payload,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+// listNotifications is the caller's in-app inbox, newest first, so clients
+// that weren't connected can catch up on what they missed.
+func (server *Server) listNotifications(ctx *gin.Context) {
+	var req cursorPageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	beforeID, err := req.lastID()
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	notifications, err := server.store.ListNotifications(ctx, db.ListNotificationsParams{
+		Username:  authPayload.Username,
+		BeforeID:  beforeID,
+		PageLimit: req.limit() + 1,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	page := newListResponse(notifications, req.limit(), func(notification db.Notification) int64 { return notification.ID })
+	items := make([]notificationResponse, len(page.Items))
+	for i, notification := range page.Items {
+		items[i] = newNotificationResponse(notification)
+	}
+	ctx.JSON(http.StatusOK, withItems(page, items))
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type notificationPreference struct {
+	Channel   string `json:"channel" binding:"required,oneof=in_app sms"`
+	EventType string `json:"event_type" binding:"required"`
+	Enabled   *bool  `json:"enabled" binding:"required"`
+}
+
+type notificationPreferencesResponse struct {
+	Preferences []notificationPreference `json:"preferences"`
+}
+
+type updateNotificationPreferencesRequest struct {
+	Preferences []notificationPreference `json:"preferences" binding:"required,min=1,dive"`
+}
+
+// newNotificationPreferencesResponse lists every channel and notification
+// type, with the user's choice where they made one.
+func newNotificationPreferencesResponse(stored []db.NotificationPreference) notificationPreferencesResponse {
+	disabled := make(map[[2]string]bool, len(stored))
+	for _, preference := range stored {
+		disabled[[2]string{preference.Channel, preference.EventType}] = !preference.Enabled
+	}
+
+	rsp := notificationPreferencesResponse{Preferences: []notificationPreference{}}
+	for _, channel := range notify.Channels() {
+		for _, kind := range notify.ChannelTypes(channel) {
+			enabled := !disabled[[2]string{channel, kind}]
+			rsp.Preferences = append(rsp.Preferences, notificationPreference{
+				Channel:   channel,
+				EventType: kind,
+				Enabled:   &enabled,
+			})
+		}
+	}
+	return rsp
+}
+
+func (server *Server) listNotificationPreferences(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	stored, err := server.store.ListNotificationPreferences(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newNotificationPreferencesResponse(stored))
+}
+
+// updateNotificationPreferences turns notifications on or off. Preferences
+// that are not in the request keep their value.
+func (server *Server) updateNotificationPreferences(ctx *gin.Context) {
+	var req updateNotificationPreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	changes := make([]db.NotificationPreferenceChange, len(req.Preferences))
+	for i, preference := range req.Preferences {
+		if !notify.Delivers(preference.Channel, preference.EventType) {
+			ctx.JSON(errorResponse(http.StatusBadRequest, fmt.Errorf("channel %s doesn't deliver %s notifications", preference.Channel, preference.EventType)))
+			return
+		}
+		changes[i] = db.NotificationPreferenceChange{
+			Channel:   preference.Channel,
+			EventType: preference.EventType,
+			Enabled:   *preference.Enabled,
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	stored, err := server.store.UpdateNotificationPreferencesTx(ctx, db.UpdateNotificationPreferencesTxParams{
+		Username:    authPayload.Username,
+		Preferences: changes,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newNotificationPreferencesResponse(stored))
+}
+
+// notificationEnabled reports whether username wants notifications of the
+// given type on channel. When the preference can't be read the notification
+// is sent: an unwanted alert is better than a missed one.
+func (server *Server) notificationEnabled(ctx context.Context, username, channel, kind string) bool {
+	preference, err := server.store.GetNotificationPreference(ctx, db.GetNotificationPreferenceParams{
+		Username:  username,
+		Channel:   channel,
+		EventType: kind,
+	})
+	if err != nil {
+		if err != sql.ErrNoRows {
+			server.logger.Warn("cannot read notification preference", "channel", channel, "user", username, "error", err)
+		}
+		return true
+	}
+	return preference.Enabled
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func preferenceMap(t *testing.T, recorder *httptest.ResponseRecorder) map[string]bool {
+	var rsp notificationPreferencesResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+
+	preferences := make(map[string]bool, len(rsp.Preferences))
+	for _, preference := range rsp.Preferences {
+		preferences[preference.Channel+" "+preference.EventType] = *preference.Enabled
+	}
+	return preferences
+}
+
+func TestListNotificationPreferencesAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListNotificationPreferences(gomock.Any(), gomock.Eq(username)).
+		Times(1).
+		Return([]db.NotificationPreference{{
+			Username:  username,
+			Channel:   notify.ChannelSMS,
+			EventType: notify.LargeTransfer,
+			Enabled:   false,
+		}}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/users/me/notification-preferences", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// Every preference is listed; those never set are on.
+	require.Equal(t, map[string]bool{
+		"in_app transfer.received": true,
+		"in_app balance.low":       true,
+		"in_app security.alert":    true,
+		"sms security.alert":       true,
+		"sms transfer.large":       false,
+	}, preferenceMap(t, recorder))
+}
+
+func TestUpdateNotificationPreferencesAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"preferences": []gin.H{
+				{"channel": notify.ChannelInApp, "event_type": notify.LowBalance, "enabled": false},
+				{"channel": notify.ChannelSMS, "event_type": notify.LargeTransfer, "enabled": true},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateNotificationPreferencesTx(gomock.Any(), gomock.Eq(db.UpdateNotificationPreferencesTxParams{
+					Username: username,
+					Preferences: []db.NotificationPreferenceChange{
+						{Channel: notify.ChannelInApp, EventType: notify.LowBalance, Enabled: false},
+						{Channel: notify.ChannelSMS, EventType: notify.LargeTransfer, Enabled: true},
+					},
+				})).
+					Times(1).
+					Return([]db.NotificationPreference{
+						{Username: username, Channel: notify.ChannelInApp, EventType: notify.LowBalance, Enabled: false},
+						{Username: username, Channel: notify.ChannelSMS, EventType: notify.LargeTransfer, Enabled: true},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				preferences := preferenceMap(t, recorder)
+				require.False(t, preferences["in_app balance.low"])
+				require.True(t, preferences["sms transfer.large"])
+			},
+		},
+		{
+			name: "UnknownChannel",
+			body: gin.H{"preferences": []gin.H{
+				{"channel": "pigeon", "event_type": notify.LowBalance, "enabled": false},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateNotificationPreferencesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotOnChannel",
+			body: gin.H{"preferences": []gin.H{
+				{"channel": notify.ChannelSMS, "event_type": notify.LowBalance, "enabled": false},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateNotificationPreferencesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingEnabled",
+			body: gin.H{"preferences": []gin.H{
+				{"channel": notify.ChannelInApp, "event_type": notify.LowBalance},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateNotificationPreferencesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/api/users/me/notification-preferences", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListNotificationsAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	notifications := make([]db.Notification, 3)
+	for i := range notifications {
+		notifications[i] = db.Notification{
+			ID:       int64(20 - i),
+			Username: username,
+			Channel:  notify.ChannelInApp,
+			Type:     notify.SecurityAlert,
+			Payload:  json.RawMessage(`{"event":"login"}`),
+			Status:   db.NotificationSent,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?limit=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListNotificationsParams{
+					Username:  username,
+					PageLimit: 3,
+				}
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(notifications, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listResponse[notificationResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 2)
+				require.True(t, rsp.Page.HasMore)
+				require.Equal(t, encodeCursor(notifications[1].ID), rsp.NextCursor)
+				require.Equal(t, notify.SecurityAlert, rsp.Items[0].Type)
+				require.JSONEq(t, `{"event":"login"}`, string(rsp.Items[0].Data))
+			},
+		},
+		{
+			name:  "NextPage",
+			query: "?cursor=" + encodeCursor(notifications[1].ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListNotificationsParams{
+					Username:  username,
+					BeforeID:  notifications[1].ID,
+					PageLimit: defaultPageLimit + 1,
+				}
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(notifications[2:], nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listResponse[notificationResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Items, 1)
+				require.False(t, rsp.Page.HasMore)
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: "?cursor=nope",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/notifications"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
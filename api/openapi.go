@@ -0,0 +1,382 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// routeDoc describes one route for the OpenAPI document. Request and response
+// shapes are given as zero values of the types the handler binds and
+// returns; their schemas are generated from the struct and binding tags.
+type routeDoc struct {
+	Summary string
+	// Query is the struct bound with ShouldBindQuery.
+	Query any
+	// Body is the struct bound with ShouldBindJSON, or with ShouldBind for a
+	// multipart upload.
+	Body any
+	// Upload names the file field of a multipart body. Its other fields are
+	// the form fields of Body.
+	Upload string
+	// Response is the success body; nil when there is none.
+	Response any
+	// Status is the success status, 200 if unset.
+	Status int
+	// ContentType is set for responses that aren't JSON.
+	ContentType string
+}
+
+// acceptPaymentRequestResponse documents the body acceptPaymentRequest builds.
+type acceptPaymentRequestResponse struct {
+	PaymentRequest paymentRequestResponse `json:"payment_request"`
+	Transfer       db.TransferTxResult    `json:"transfer"`
+}
+
+// reviewKycDocumentResponse documents the body reviewKycDocument builds.
+type reviewKycDocumentResponse struct {
+	Document db.ReviewKycDocumentRow `json:"document"`
+	User     UserResponse            `json:"user"`
+}
+
+type healthzResponse struct {
+	Status string `json:"status"`
+}
+
+// routeDocs documents every API route, keyed like routeScopes. Routes missing
+// from this table are left out of /swagger.json, so new routes must be added
+// here.
+var routeDocs = map[string]routeDoc{
+	"GET /status":                {Summary: "Public status of the service and its dependencies", Response: statusResponse{}},
+	"GET /healthz":               {Summary: "Liveness probe", Response: healthzResponse{}},
+	"GET /readyz":                {Summary: "Readiness probe with the state of every dependency", Response: readinessResponse{}},
+	"GET /currencies":            {Summary: "Supported currencies with their names and minor-unit exponents", Response: []currencyResponse{}},
+	"GET /.well-known/jwks.json": {Summary: "Public keys that verify access tokens", Response: token.JWKSet{}},
+
+	"POST /users":                       {Summary: "Sign up", Body: createUserRequest{}, Response: UserResponse{}},
+	"POST /users/login":                 {Summary: "Log in with a password", Body: loginUserRequest{}, Response: loginUserResponse{}},
+	"POST /users/webauthn/login/begin":  {Summary: "Start a passkey login", Body: beginPasskeyLoginRequest{}, Response: beginPasskeyLoginResponse{}},
+	"POST /users/webauthn/login/finish": {Summary: "Finish a passkey login", Body: finishPasskeyLoginRequest{}, Response: loginUserResponse{}},
+	"GET /ws": {
+		Summary: "WebSocket of notifications for the authenticated user. The token may be passed in the access_token query parameter",
+		Status:  http.StatusSwitchingProtocols,
+	},
+
+	"POST /accounts":                     {Summary: "Open an account", Body: createAccountRequest{}, Response: db.Account{}},
+	"GET /accounts/:id":                  {Summary: "Get an account with its pots", Response: accountResponse{}},
+	"GET /accounts":                      {Summary: "List the caller's accounts", Query: cursorPageRequest{}, Response: listResponse[db.Account]{}},
+	"POST /accounts/:id/deposit":         {Summary: "Deposit into an account", Body: depositRequest{}, Response: db.DepositTxResult{}},
+	"POST /deposits/external":            {Summary: "Record an inbound wire by its bank reference, crediting it once", Body: externalDepositRequest{}, Response: db.ExternalDepositTxResult{}},
+	"POST /accounts/:id/withdraw":        {Summary: "Withdraw from an account", Body: withdrawRequest{}, Response: db.WithdrawTxResult{}},
+	"POST /accounts/:id/freeze":          {Summary: "Freeze an account", Response: db.Account{}},
+	"DELETE /accounts/:id":               {Summary: "Close an account, sweeping its balance to another account", Query: closeAccountRequest{}, Response: db.CloseAccountTxResult{}},
+	"GET /accounts/:id/payment_qr":       {Summary: "Signed payment code of an account, to pay it with POST /transfers", Response: paymentQRResponse{}},
+	"GET /accounts/:id/payment_qr.png":   {Summary: "Payment code of an account as a QR code", ContentType: "image/png"},
+	"GET /accounts/:id/summary":          {Summary: "Month in review of an account: inflow, outflow, transfers and top counterparties", Query: accountSummaryRequest{}, Response: accountSummaryResponse{}},
+	"GET /accounts/:id/activity":         {Summary: "Transfers, deposits, interest and other activity of an account, newest first", Query: cursorPageRequest{}, Response: listResponse[db.ListAccountActivityRow]{}},
+	"GET /accounts/:id/entries":          {Summary: "List the ledger entries of an account", Query: listEntriesRequest{}, Response: listResponse[db.Entry]{}},
+	"GET /accounts/:id/balance_history":  {Summary: "Balance of an account over time", Query: balanceHistoryRequest{}, Response: balanceHistoryResponse{}},
+	"GET /accounts/:id/lookup":           {Summary: "Look up the holder of an account before paying it", Response: lookupAccountResponse{}},
+	"POST /accounts/:id/statement/email": {Summary: "Email a statement of an account", Body: emailStatementRequest{}, Response: emailStatementResponse{}, Status: http.StatusAccepted},
+	"GET /accounts/:id/statement.pdf":    {Summary: "Monthly statement of an account as a PDF", Query: statementPDFRequest{}, ContentType: "application/pdf"},
+	"GET /accounts/:id/events":           {Summary: "Server-sent events of balance changes and incoming transfers", ContentType: "text/event-stream"},
+	"POST /accounts/:id/pots":            {Summary: "Create a pot to set money aside in", Body: createPotRequest{}, Response: db.Pot{}},
+	"POST /accounts/:id/pots/moves":      {Summary: "Move money between an account and its pots", Body: movePotMoneyRequest{}, Response: db.MovePotMoneyTxResult{}},
+	"GET /accounts/:id/interest":         {Summary: "Interest accrued on an account and not posted yet", Response: accruedInterestResponse{}},
+
+	"GET /fx/quote":                   {Summary: "Quote a currency conversion, valid for 30 seconds", Query: fxQuoteRequest{}, Response: fxQuoteResponse{}},
+	"POST /transfers":                 {Summary: "Transfer money between accounts", Body: transferRequest{}, Response: db.TransferTxResult{}},
+	"GET /transfers":                  {Summary: "List the caller's transfers", Query: listTransfersRequest{}, Response: listResponse[transferHistoryItem]{}},
+	"GET /transfers/export":           {Summary: "Export the caller's transfers as CSV", Query: exportTransfersRequest{}, ContentType: "text/csv"},
+	"POST /transfers/batch":           {Summary: "Make several transfers at once", Body: batchTransferRequest{}, Response: batchTransferResponse{}},
+	"POST /transfers/scheduled":       {Summary: "Schedule a transfer", Body: createScheduledTransferRequest{}, Response: scheduledTransferResponse{}},
+	"GET /transfers/scheduled":        {Summary: "List scheduled transfers", Query: cursorPageRequest{}, Response: listResponse[scheduledTransferResponse]{}},
+	"GET /transfers/scheduled/:id":    {Summary: "Get a scheduled transfer", Response: scheduledTransferResponse{}},
+	"DELETE /transfers/scheduled/:id": {Summary: "Cancel a scheduled transfer", Response: scheduledTransferResponse{}},
+	"POST /transfers/:id/tags":        {Summary: "Tag a transfer", Body: tagRequest{}, Response: tagsResponse{}},
+	"GET /transfers/:id/tags":         {Summary: "List the tags of a transfer", Response: tagsResponse{}},
+	"DELETE /transfers/:id/tags/:tag": {Summary: "Remove a tag from a transfer", Response: tagsResponse{}},
+	"POST /entries/:id/tags":          {Summary: "Tag a ledger entry", Body: tagRequest{}, Response: tagsResponse{}},
+	"GET /entries/:id/tags":           {Summary: "List the tags of a ledger entry", Response: tagsResponse{}},
+	"DELETE /entries/:id/tags/:tag":   {Summary: "Remove a tag from a ledger entry", Response: tagsResponse{}},
+	"GET /tags/spending":              {Summary: "Spending per tag", Response: []db.ListTagSpendingRow{}},
+
+	"POST /beneficiaries":       {Summary: "Save a beneficiary", Body: createBeneficiaryRequest{}, Response: beneficiaryResponse{}},
+	"GET /beneficiaries":        {Summary: "List saved beneficiaries", Query: cursorPageRequest{}, Response: listResponse[beneficiaryResponse]{}},
+	"DELETE /beneficiaries/:id": {Summary: "Delete a beneficiary", Status: http.StatusNoContent},
+
+	"POST /payment-requests":             {Summary: "Request money from another user", Body: createPaymentRequestRequest{}, Response: paymentRequestResponse{}},
+	"GET /payment-requests":              {Summary: "List incoming or outgoing payment requests", Query: listPaymentRequestsRequest{}, Response: listResponse[paymentRequestResponse]{}},
+	"GET /payment-requests/:id":          {Summary: "Get a payment request", Response: paymentRequestResponse{}},
+	"POST /payment-requests/:id/accept":  {Summary: "Pay a payment request", Body: acceptPaymentRequestRequest{}, Response: acceptPaymentRequestResponse{}},
+	"POST /payment-requests/:id/decline": {Summary: "Decline a payment request", Response: paymentRequestResponse{}},
+
+	"POST /webhooks":               {Summary: "Subscribe a URL to account events", Body: createWebhookRequest{}, Response: createWebhookResponse{}},
+	"GET /webhooks":                {Summary: "List webhook subscriptions", Response: []webhookResponse{}},
+	"DELETE /webhooks/:id":         {Summary: "Delete a webhook subscription", Status: http.StatusNoContent},
+	"GET /webhooks/:id/deliveries": {Summary: "Delivery log of a webhook subscription", Query: cursorPageRequest{}, Response: listResponse[webhookDeliveryResponse]{}},
+
+	"POST /kyc/documents": {Summary: "Upload a KYC document", Body: uploadKycDocumentRequest{}, Upload: "file", Response: db.CreateKycDocumentRow{}},
+	"GET /kyc/documents":  {Summary: "List the caller's KYC documents", Query: listKycDocumentsRequest{}, Response: []db.ListKycDocumentsRow{}},
+
+	"POST /tokens":                           {Summary: "Create a token with fewer scopes", Body: createTokenRequest{}, Response: createTokenResponse{}},
+	"POST /users/elevate":                    {Summary: "Get a short-lived elevated token for large transfers", Body: elevateRequest{}, Response: elevateResponse{}},
+	"POST /users/totp":                       {Summary: "Enroll an authenticator app", Body: enrollTotpRequest{}, Response: enrollTotpResponse{}},
+	"PATCH /users/:username":                 {Summary: "Update the caller's profile", Body: updateUserRequest{}, Response: UserResponse{}},
+	"GET /users/me":                          {Summary: "Get the caller's profile", Response: UserResponse{}},
+	"GET /users/me/insights/counterparties":  {Summary: "Who the caller sent the most money to in a period, per user and currency", Query: counterpartyInsightsRequest{}, Response: counterpartyInsightsResponse{}},
+	"GET /users/me/limits":                   {Summary: "The caller's transfer limits and usage", Response: []db.ListTransferLimitsRow{}},
+	"GET /users/me/notification-preferences": {Summary: "The caller's notification preferences", Response: notificationPreferencesResponse{}},
+	"PUT /users/me/notification-preferences": {Summary: "Turn notifications on or off", Body: updateNotificationPreferencesRequest{}, Response: notificationPreferencesResponse{}},
+	"GET /notifications":                     {Summary: "The caller's in-app notifications, newest first", Query: cursorPageRequest{}, Response: listResponse[notificationResponse]{}},
+
+	"POST /users/webauthn/register/begin":  {Summary: "Start registering a passkey", Body: beginPasskeyRegistrationRequest{}, Response: beginPasskeyRegistrationResponse{}},
+	"POST /users/webauthn/register/finish": {Summary: "Finish registering a passkey", Body: finishPasskeyRegistrationRequest{}, Response: passkeyResponse{}},
+
+	"GET /admin/users":                        {Summary: "Search users", Query: searchUsersRequest{}, Response: []UserResponse{}},
+	"POST /admin/users/:username/suspend":     {Summary: "Suspend a user", Response: UserResponse{}},
+	"POST /admin/users/:username/reactivate":  {Summary: "Reactivate a suspended user", Response: UserResponse{}},
+	"GET /admin/accounts":                     {Summary: "Search accounts of every owner", Query: searchAccountsRequest{}, Response: []db.Account{}},
+	"POST /admin/accounts/:id/freeze":         {Summary: "Freeze any account", Response: db.Account{}},
+	"PUT /admin/accounts/:id/overdraft":       {Summary: "Set how far below zero an account may go", Body: setOverdraftLimitRequest{}, Response: db.Account{}},
+	"POST /admin/accounts/:id/adjustments":    {Summary: "Correct the balance of an account", Body: adjustBalanceRequest{}, Response: db.AdjustBalanceTxResult{}},
+	"GET /admin/accounts/:id/adjustments":     {Summary: "Balance adjustments of an account", Query: listBalanceAdjustmentsRequest{}, Response: []db.BalanceAdjustment{}},
+	"GET /admin/transfers":                    {Summary: "Search transfers between any accounts", Query: searchTransfersRequest{}, Response: []db.Transfer{}},
+	"GET /admin/users/:username/history":      {Summary: "Standing data history of a user", Query: standingDataHistoryRequest{}, Response: []db.StandingDataChange{}},
+	"GET /admin/accounts/:id/history":         {Summary: "Standing data history of an account", Query: standingDataHistoryRequest{}, Response: []db.StandingDataChange{}},
+	"POST /admin/accounts/:id/unfreeze":       {Summary: "Unfreeze an account", Response: db.Account{}},
+	"POST /admin/history/:id/revert":          {Summary: "Revert a standing data change", Response: db.StandingDataChange{}},
+	"POST /admin/impersonations":              {Summary: "Get a token to act as a user", Body: createImpersonationRequest{}, Response: createImpersonationResponse{}},
+	"GET /admin/users/:username/audit-logs":   {Summary: "Audit trail of a user", Query: listAuditLogsRequest{}, Response: []db.AuditLog{}},
+	"GET /admin/audit-logs":                   {Summary: "Search the audit log", Query: searchAuditLogsRequest{}, Response: listResponse[db.AuditLog]{}},
+	"GET /admin/users/:username/limits":       {Summary: "Transfer limits and usage of a user", Response: []db.ListTransferLimitsRow{}},
+	"PUT /admin/users/:username/limits":       {Summary: "Set a transfer limit of a user", Body: upsertTransferLimitRequest{}, Response: db.TransferLimit{}},
+	"GET /admin/kyc/documents":                {Summary: "KYC review queue", Query: listPendingKycDocumentsRequest{}, Response: []db.ListKycDocumentsByStatusRow{}},
+	"GET /admin/kyc/documents/:id/file":       {Summary: "Download a KYC document", ContentType: "application/octet-stream"},
+	"POST /admin/kyc/documents/:id/review":    {Summary: "Approve or reject a KYC document", Body: reviewKycDocumentRequest{}, Response: reviewKycDocumentResponse{}},
+	"POST /admin/periods":                     {Summary: "Close an accounting period", Body: closePeriodRequest{}, Response: db.ClosePeriodTxResult{}},
+	"GET /admin/periods":                      {Summary: "List closed accounting periods", Query: listPeriodsRequest{}, Response: []db.AccountingPeriod{}},
+	"GET /admin/periods/:period/report":       {Summary: "Trial balance of a closed period", Response: db.PeriodReport{}},
+	"POST /admin/periods/:period/adjustments": {Summary: "Post an adjustment to a closed period", Body: postAdjustmentRequest{}, Response: db.PostAdjustmentTxResult{}},
+	"GET /admin/fx_rates":                     {Summary: "List the rates of a currency pair", Query: listFxRateHistoryRequest{}, Response: []db.FxRate{}},
+	"POST /admin/fx_rates":                    {Summary: "Add an exchange rate", Body: createFxRateRequest{}, Response: db.FxRate{}},
+	"GET /admin/settings":                     {Summary: "List runtime settings", Response: []db.Setting{}},
+	"PUT /admin/settings":                     {Summary: "Change a runtime setting", Body: upsertSettingRequest{}, Response: db.Setting{}},
+	"DELETE /admin/settings/:id":              {Summary: "Reset a runtime setting to its default", Status: http.StatusNoContent},
+	"GET /admin/retention/rules":              {Summary: "List data retention rules", Response: []db.RetentionRule{}},
+	"PUT /admin/retention/rules":              {Summary: "Set a data retention rule", Body: upsertRetentionRuleRequest{}, Response: db.RetentionRule{}},
+	"POST /admin/retention/runs":              {Summary: "Purge data past its retention period", Body: runRetentionRequest{}, Response: db.RetentionReport{}},
+	"GET /admin/retention/runs":               {Summary: "List retention runs", Query: listRetentionRunsRequest{}, Response: []db.RetentionRun{}},
+	"GET /admin/retention/runs/:id":           {Summary: "Report of a retention run", Response: db.RetentionReport{}},
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       openAPIInfo                     `json:"info"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components openAPIComponents               `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*schema        `json:"schemas"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+}
+
+type securityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+type operation struct {
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []parameter           `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+const bearerAuth = "bearerAuth"
+
+// newOpenAPIDocument describes the registered routes. A route mounted both
+// at the root and under /api is documented once, under /api.
+func newOpenAPIDocument(routes gin.RoutesInfo) *openAPIDocument {
+	registry := newSchemaRegistry()
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       "SimpleBank API",
+			Description: "Authenticated routes take a bearer token from /api/users/login. Most of them are also served without the /api prefix for older clients.",
+			Version:     "1.0.0",
+		},
+		Paths: make(map[string]map[string]operation),
+	}
+
+	registered := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	for _, route := range routes {
+		path := route.Path
+		if strings.HasPrefix(path, "/api/") {
+			path = strings.TrimPrefix(path, "/api")
+		} else if registered[route.Method+" /api"+path] {
+			continue
+		}
+		key := route.Method + " " + path
+		routeDoc, ok := routeDocs[key]
+		if !ok {
+			continue
+		}
+
+		openAPIPath := openAPIPathOf(route.Path)
+		if doc.Paths[openAPIPath] == nil {
+			doc.Paths[openAPIPath] = make(map[string]operation)
+		}
+		doc.Paths[openAPIPath][strings.ToLower(route.Method)] = routeDoc.operation(registry, key, route.Path)
+	}
+
+	doc.Components = openAPIComponents{
+		Schemas: registry.components,
+		SecuritySchemes: map[string]securityScheme{
+			bearerAuth: {Type: "http", Scheme: "bearer"},
+		},
+	}
+	return doc
+}
+
+func (routeDoc routeDoc) operation(registry *schemaRegistry, key, path string) operation {
+	op := operation{
+		Summary: routeDoc.Summary,
+		Tags:    []string{operationTag(key)},
+	}
+
+	if scope, ok := routeScopes[key]; ok {
+		op.Description = "Requires the `" + scope + "` scope."
+		op.Security = []map[string][]string{{bearerAuth: {}}}
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			s := &schema{Type: "string"}
+			if name == "id" {
+				s = &schema{Type: "integer", Format: "int64"}
+			}
+			op.Parameters = append(op.Parameters, parameter{Name: name, In: "path", Required: true, Schema: s})
+		}
+	}
+	if routeDoc.Query != nil {
+		op.Parameters = append(op.Parameters, registry.parametersOf(reflect.TypeOf(routeDoc.Query), "query")...)
+	}
+
+	switch {
+	case routeDoc.Upload != "":
+		form := &schema{Type: "object", Properties: map[string]*schema{
+			routeDoc.Upload: {Type: "string", Format: "binary"},
+		}, Required: []string{routeDoc.Upload}}
+		for _, param := range registry.parametersOf(reflect.TypeOf(routeDoc.Body), "formData") {
+			form.Properties[param.Name] = param.Schema
+			if param.Required {
+				form.Required = append(form.Required, param.Name)
+			}
+		}
+		sort.Strings(form.Required)
+		op.RequestBody = &requestBody{Required: true, Content: map[string]mediaType{"multipart/form-data": {Schema: form}}}
+	case routeDoc.Body != nil:
+		body := registry.schemaOf(reflect.TypeOf(routeDoc.Body))
+		op.RequestBody = &requestBody{Required: true, Content: map[string]mediaType{"application/json": {Schema: body}}}
+	}
+
+	status := routeDoc.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := response{Description: http.StatusText(status)}
+	switch {
+	case routeDoc.ContentType != "":
+		success.Content = map[string]mediaType{routeDoc.ContentType: {Schema: &schema{Type: "string", Format: "binary"}}}
+	case routeDoc.Response != nil:
+		success.Content = map[string]mediaType{"application/json": {Schema: registry.schemaOf(reflect.TypeOf(routeDoc.Response))}}
+	}
+	op.Responses = map[string]response{
+		strconv.Itoa(status): success,
+		"default": {
+			Description: "Error",
+			Content:     map[string]mediaType{"application/json": {Schema: registry.schemaOf(reflect.TypeOf(apiError{}))}},
+		},
+	}
+	return op
+}
+
+// operationTag groups operations by their first path segment, and admin
+// operations together.
+func operationTag(key string) string {
+	_, path, _ := strings.Cut(key, " ")
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	return segments[0]
+}
+
+// openAPIPathOf turns gin's :param segments into {param}.
+func openAPIPathOf(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// swaggerUI is the Swagger UI page. It loads the UI itself from a CDN and
+// points it at /swagger.json.
+//
+//go:embed swagger.html
+var swaggerUI []byte
+
+// serveOpenAPI serves the document and the UI. The document is built once,
+// from the routes registered so far.
+func serveOpenAPI(router *gin.Engine) {
+	doc := newOpenAPIDocument(router.Routes())
+	router.GET("/swagger.json", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, doc)
+	})
+	router.GET("/swagger", func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", swaggerUI)
+	})
+}
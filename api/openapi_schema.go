@@ -0,0 +1,241 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/google/uuid"
+)
+
+// schema is the subset of the OpenAPI 3.0 schema object the generator emits.
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     bool               `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int64             `json:"minLength,omitempty"`
+	MaxLength            *int64             `json:"maxLength,omitempty"`
+	MinItems             *int64             `json:"minItems,omitempty"`
+	MaxItems             *int64             `json:"maxItems,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	uuidType          = reflect.TypeOf(uuid.UUID{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaRegistry turns Go types into schemas the way encoding/json and the
+// gin validator see them. Named structs become components so that shared
+// types such as db.Account are described once.
+type schemaRegistry struct {
+	components map[string]*schema
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{components: make(map[string]*schema)}
+}
+
+func (registry *schemaRegistry) schemaOf(t reflect.Type) *schema {
+	switch {
+	case t.Kind() == reflect.Pointer:
+		s := registry.schemaOf(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	case t == timeType:
+		return &schema{Type: "string", Format: "date-time"}
+	case t == uuidType:
+		return &schema{Type: "string", Format: "uuid"}
+	case t == rawMessageType:
+		return &schema{}
+	case t.Implements(textMarshalerType):
+		return &schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &schema{Type: "number"}
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &schema{Type: "string", Format: "byte"}
+		}
+		return &schema{Type: "array", Items: registry.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: registry.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return registry.structSchema(t)
+		}
+		name := componentName(t)
+		if _, ok := registry.components[name]; !ok {
+			// Reserve the name first so recursive types terminate.
+			registry.components[name] = &schema{}
+			*registry.components[name] = *registry.structSchema(t)
+		}
+		return &schema{Ref: "#/components/schemas/" + name}
+	}
+	return &schema{}
+}
+
+func (registry *schemaRegistry) structSchema(t reflect.Type) *schema {
+	s := &schema{Type: "object", Properties: make(map[string]*schema)}
+	registry.addFields(s, t)
+	return s
+}
+
+// addFields adds the JSON fields of t to s, promoting the fields of embedded
+// structs as encoding/json does.
+func (registry *schemaRegistry) addFields(s *schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			registry.addFields(s, field.Type)
+			continue
+		}
+		// Path and form fields are bound from elsewhere.
+		if !field.IsExported() || (tag == "" && (field.Tag.Get("uri") != "" || field.Tag.Get("form") != "")) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := registry.schemaOf(field.Type)
+		if applyBinding(property, field.Tag.Get("binding")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = property
+	}
+}
+
+// parametersOf describes the fields of a struct bound from the query string
+// or a form.
+func (registry *schemaRegistry) parametersOf(t reflect.Type, in string) []parameter {
+	var params []parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			params = append(params, registry.parametersOf(field.Type, in)...)
+			continue
+		}
+		name := field.Tag.Get("form")
+		if name == "" || name == "-" {
+			continue
+		}
+		s := registry.schemaOf(field.Type)
+		required := applyBinding(s, field.Tag.Get("binding"))
+		params = append(params, parameter{Name: name, In: in, Required: required, Schema: s})
+	}
+	return params
+}
+
+// applyBinding copies the constraints of a gin binding tag onto s and reports
+// whether the field is required. Rules after "dive" apply to the elements.
+func applyBinding(s *schema, binding string) bool {
+	required := false
+	target := s
+	for _, rule := range strings.Split(binding, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "dive":
+			if target.Items == nil {
+				return required
+			}
+			target = target.Items
+		case "oneof":
+			target.Enum = strings.Fields(arg)
+		case "currency":
+			target.Enum = []string{util.USD, util.EUR, util.INR}
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "uuid":
+			target.Format = "uuid"
+		case "datetime":
+			if arg == "2006-01-02" {
+				target.Format = "date"
+			}
+		case "min", "gte", "gt":
+			setBound(target, arg, true, name == "gt")
+		case "max", "lte", "lt":
+			setBound(target, arg, false, name == "lt")
+		case "len":
+			setBound(target, arg, true, false)
+			setBound(target, arg, false, false)
+		}
+	}
+	return required
+}
+
+// setBound sets a length, size or value limit depending on what s describes.
+func setBound(s *schema, arg string, lower, exclusive bool) {
+	n, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return
+	}
+	switch s.Type {
+	case "string":
+		if lower {
+			s.MinLength = &n
+		} else {
+			s.MaxLength = &n
+		}
+	case "array":
+		if lower {
+			s.MinItems = &n
+		} else {
+			s.MaxItems = &n
+		}
+	case "integer", "number":
+		f := float64(n)
+		if lower {
+			s.Minimum, s.ExclusiveMinimum = &f, exclusive
+		} else {
+			s.Maximum, s.ExclusiveMaximum = &f, exclusive
+		}
+	}
+}
+
+var packagePath = regexp.MustCompile(`[\w.-]+/`)
+
+// componentName is the package-qualified type name, e.g. db.Account, with
+// the import paths of generic type arguments dropped.
+func componentName(t reflect.Type) string {
+	name := packagePath.ReplaceAllString(t.String(), "")
+	return strings.NewReplacer("[", "_", "]", "", ",", "_", "*", "").Replace(name)
+}
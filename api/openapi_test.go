@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	// The documentation itself, and the web UI.
+	undocumented := map[string]bool{
+		"GET /swagger":      true,
+		"GET /swagger.json": true,
+		"GET /":             true,
+	}
+	webAssets := map[string]bool{".html": true, ".js": true, ".css": true, ".ico": true}
+
+	for _, route := range server.router.Routes() {
+		key := route.Method + " " + strings.TrimPrefix(route.Path, "/api")
+		if undocumented[key] || webAssets[path.Ext(route.Path)] {
+			continue
+		}
+		_, ok := routeDocs[key]
+		require.True(t, ok, "route %s is not documented", key)
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/swagger.json", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var doc openAPIDocument
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &doc))
+	require.Equal(t, "3.0.3", doc.OpenAPI)
+
+	// Routes served at the root and under /api are documented once.
+	require.Contains(t, doc.Paths, "/api/transfers")
+	require.NotContains(t, doc.Paths, "/transfers")
+	require.Contains(t, doc.Paths, "/healthz")
+
+	getAccount := doc.Paths["/api/accounts/{id}"]["get"]
+	require.Equal(t, []map[string][]string{{bearerAuth: {}}}, getAccount.Security)
+	require.Equal(t, []parameter{{Name: "id", In: "path", Required: true, Schema: &schema{Type: "integer", Format: "int64"}}}, getAccount.Parameters)
+	require.Equal(t, "#/components/schemas/api.accountResponse", getAccount.Responses["200"].Content["application/json"].Schema.Ref)
+
+	login := doc.Paths["/api/users/login"]["post"]
+	require.Empty(t, login.Security)
+	require.Equal(t, "#/components/schemas/api.loginUserRequest", login.RequestBody.Content["application/json"].Schema.Ref)
+
+	transferRequest := doc.Components.Schemas["api.transferRequest"]
+	require.NotNil(t, transferRequest)
+	require.ElementsMatch(t, []string{"from_account_id", "amount"}, transferRequest.Required)
+	require.Equal(t, []string{"USD", "EUR", "INR"}, transferRequest.Properties["currency"].Enum)
+	require.True(t, transferRequest.Properties["amount"].ExclusiveMinimum)
+	require.Equal(t, int64(140), *transferRequest.Properties["memo"].MaxLength)
+
+	listTransfers := doc.Paths["/api/transfers"]["get"]
+	var queryNames []string
+	for _, param := range listTransfers.Parameters {
+		require.Equal(t, "query", param.In)
+		queryNames = append(queryNames, param.Name)
+	}
+	require.Subset(t, queryNames, []string{"cursor", "limit", "q", "tag"})
+
+	upload := doc.Paths["/api/kyc/documents"]["post"].RequestBody.Content["multipart/form-data"].Schema
+	require.Equal(t, []string{"document_type", "file", "requested_tier"}, upload.Required)
+
+	require.Contains(t, doc.Paths["/api/beneficiaries/{id}"]["delete"].Responses, "204")
+
+	// Every reference resolves.
+	var refs []string
+	collectRefs(recorder.Body.Bytes(), &refs)
+	require.NotEmpty(t, refs)
+	for _, ref := range refs {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		require.Contains(t, doc.Components.Schemas, name, ref)
+	}
+}
+
+func collectRefs(raw json.RawMessage, refs *[]string) {
+	var object map[string]json.RawMessage
+	if json.Unmarshal(raw, &object) == nil {
+		for key, value := range object {
+			var ref string
+			if key == "$ref" && json.Unmarshal(value, &ref) == nil {
+				*refs = append(*refs, ref)
+				continue
+			}
+			collectRefs(value, refs)
+		}
+		return
+	}
+	var array []json.RawMessage
+	if json.Unmarshal(raw, &array) == nil {
+		for _, value := range array {
+			collectRefs(value, refs)
+		}
+	}
+}
+
+func TestSwaggerUI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/swagger", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+	require.Contains(t, recorder.Body.String(), `url: "/swagger.json"`)
+}
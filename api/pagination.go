@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// defaultPageLimit is the page size when a list request gives no limit.
+const defaultPageLimit = 10
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// cursorPageRequest is the query of every keyset-paginated list. The cursor
+// is opaque to clients: they pass back the next_cursor of the previous page.
+type cursorPageRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// pageCursor is what a cursor encodes: the ID of the last row returned.
+type pageCursor struct {
+	ID int64 `json:"id"`
+}
+
+// lastID decodes the cursor. An empty cursor means the first page.
+func (req cursorPageRequest) lastID() (int64, error) {
+	if req.Cursor == "" {
+		return 0, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(req.Cursor)
+	if err != nil {
+		return 0, errInvalidCursor
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 {
+		return 0, errInvalidCursor
+	}
+	return cursor.ID, nil
+}
+
+func (req cursorPageRequest) limit() int32 {
+	if req.Limit == 0 {
+		return defaultPageLimit
+	}
+	return req.Limit
+}
+
+func encodeCursor(id int64) string {
+	data, _ := json.Marshal(pageCursor{ID: id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// pageInfo describes the page that was returned, so clients can render
+// pagination controls without looking at the cursor.
+type pageInfo struct {
+	Limit   int32 `json:"limit"`
+	HasMore bool  `json:"has_more"`
+}
+
+// listResponse is the envelope of every cursor-paginated list. NextCursor is
+// empty on the last page. There is no total count: keyset pages exist so that
+// long lists never need a COUNT.
+type listResponse[T any] struct {
+	Items      []T      `json:"items"`
+	Page       pageInfo `json:"page"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// newListResponse builds the page from rows fetched with one more than the
+// limit, which tells whether another page follows without a COUNT.
+func newListResponse[T any](rows []T, limit int32, id func(T) int64) listResponse[T] {
+	if rows == nil {
+		rows = []T{}
+	}
+	if len(rows) <= int(limit) {
+		return listResponse[T]{Items: rows, Page: pageInfo{Limit: limit}}
+	}
+	rows = rows[:limit]
+	return listResponse[T]{
+		Items:      rows,
+		Page:       pageInfo{Limit: limit, HasMore: true},
+		NextCursor: encodeCursor(id(rows[len(rows)-1])),
+	}
+}
+
+// withItems keeps the page metadata of a list while replacing its rows by
+// their response form.
+func withItems[T, U any](page listResponse[T], items []U) listResponse[U] {
+	return listResponse[U]{Items: items, Page: page.Page, NextCursor: page.NextCursor}
+}
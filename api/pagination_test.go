@@ -0,0 +1,328 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id, err := cursorPageRequest{Cursor: encodeCursor(42)}.lastID()
+	require.NoError(t, err)
+	require.Equal(t, int64(42), id)
+
+	id, err = cursorPageRequest{}.lastID()
+	require.NoError(t, err)
+	require.Zero(t, id)
+
+	for _, cursor := range []string{"%%%", "bm90LWpzb24", encodeCursor(0), encodeCursor(-1)} {
+		_, err = cursorPageRequest{Cursor: cursor}.lastID()
+		require.ErrorIs(t, err, errInvalidCursor, cursor)
+	}
+}
+
+func TestListAccountsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	accounts := []db.Account{
+		{ID: 3, Owner: user.Username, Currency: "USD"},
+		{ID: 5, Owner: user.Username, Currency: "EUR"},
+		{ID: 8, Owner: user.Username, Currency: "INR"},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "FirstPage",
+			query: "limit=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Eq(db.ListAccountsAfterParams{
+					Owner:     user.Username,
+					AfterID:   0,
+					PageLimit: 3,
+				})).
+					Times(1).
+					Return(accounts, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got listResponse[db.Account]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, accounts[:2], got.Items)
+				require.Equal(t, pageInfo{Limit: 2, HasMore: true}, got.Page)
+				require.Equal(t, encodeCursor(5), got.NextCursor)
+			},
+		},
+		{
+			name:  "LastPage",
+			query: "limit=2&cursor=" + encodeCursor(5),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Eq(db.ListAccountsAfterParams{
+					Owner:     user.Username,
+					AfterID:   5,
+					PageLimit: 3,
+				})).
+					Times(1).
+					Return(accounts[2:], nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got listResponse[db.Account]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, accounts[2:], got.Items)
+				require.Equal(t, pageInfo{Limit: 2}, got.Page)
+				require.Empty(t, got.NextCursor)
+			},
+		},
+		{
+			name: "DefaultLimit",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Eq(db.ListAccountsAfterParams{
+					Owner:     user.Username,
+					AfterID:   0,
+					PageLimit: defaultPageLimit + 1,
+				})).
+					Times(1).
+					Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"items":[],"page":{"limit":10,"has_more":false}}`, recorder.Body.String())
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: "cursor=bogus",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: "limit=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/accounts?"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListTransfersAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	transfers := []db.ListOwnerTransfersRow{
+		{ID: 9, FromAccountID: 1, ToAccountID: 2, Amount: 10, FromCurrency: "USD", ToCurrency: "USD", Status: db.TransferCompleted},
+		{ID: 4, FromAccountID: 2, ToAccountID: 1, Amount: 20, FromCurrency: "USD", ToCurrency: "USD", Status: db.TransferFailed},
+	}
+	completed := db.TransferStatusChange{ID: 1, TransferID: 9, FromStatus: db.TransferPending, ToStatus: db.TransferCompleted}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "FirstPage",
+			query: "limit=1",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Eq(db.ListOwnerTransfersParams{
+					Owner:     user.Username,
+					BeforeID:  0,
+					PageLimit: 2,
+				})).
+					Times(1).
+					Return(transfers, nil)
+				store.EXPECT().ListTransferStatusChanges(gomock.Any(), gomock.Eq([]int64{9})).
+					Times(1).
+					Return([]db.TransferStatusChange{completed}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got listResponse[transferHistoryItem]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, []transferHistoryItem{
+					{ListOwnerTransfersRow: transfers[0], StatusChanges: []db.TransferStatusChange{completed}},
+				}, got.Items)
+				require.Equal(t, encodeCursor(9), got.NextCursor)
+			},
+		},
+		{
+			name:  "NextPage",
+			query: "limit=1&cursor=" + encodeCursor(9),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Eq(db.ListOwnerTransfersParams{
+					Owner:     user.Username,
+					BeforeID:  9,
+					PageLimit: 2,
+				})).
+					Times(1).
+					Return(transfers[1:], nil)
+				store.EXPECT().ListTransferStatusChanges(gomock.Any(), gomock.Eq([]int64{4})).
+					Times(1).
+					Return([]db.TransferStatusChange{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got listResponse[transferHistoryItem]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, []transferHistoryItem{
+					{ListOwnerTransfersRow: transfers[1], StatusChanges: []db.TransferStatusChange{}},
+				}, got.Items)
+				require.Empty(t, got.NextCursor)
+			},
+		},
+		{
+			name:  "SearchMemo",
+			query: "q=inv_1%25",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Eq(db.ListOwnerTransfersParams{
+					Owner:     user.Username,
+					Search:    `%inv\_1\%%`,
+					PageLimit: defaultPageLimit + 1,
+				})).
+					Times(1).
+					Return(transfers[:1], nil)
+				store.EXPECT().ListTransferStatusChanges(gomock.Any(), gomock.Any()).Times(1).Return([]db.TransferStatusChange{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "FilterTag",
+			query: "tag=Food",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Eq(db.ListOwnerTransfersParams{
+					Owner:     user.Username,
+					Tag:       "food",
+					PageLimit: defaultPageLimit + 1,
+				})).
+					Times(1).
+					Return(transfers[:1], nil)
+				store.EXPECT().ListTransferStatusChanges(gomock.Any(), gomock.Any()).Times(1).Return([]db.TransferStatusChange{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "Filters",
+			query: "from_date=2024-03-01&to_date=2024-03-31&min_amount=10&max_amount=500&direction=sent&counterparty_account_id=2&currency=USD",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Eq(db.ListOwnerTransfersParams{
+					Owner:                 user.Username,
+					FromTime:              sql.NullTime{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					ToTime:                sql.NullTime{Time: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					MinAmount:             10,
+					MaxAmount:             500,
+					Direction:             db.TransferDirectionSent,
+					CounterpartyAccountID: 2,
+					Currency:              util.USD,
+					PageLimit:             defaultPageLimit + 1,
+				})).
+					Times(1).
+					Return(transfers[:1], nil)
+				store.EXPECT().ListTransferStatusChanges(gomock.Any(), gomock.Any()).Times(1).Return([]db.TransferStatusChange{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "AmountRangeInverted",
+			query: "min_amount=500&max_amount=10",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidDirection",
+			query: "direction=sideways",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "LimitTooLarge",
+			query: "limit=51",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/transfers?"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
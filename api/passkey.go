@@ -0,0 +1,421 @@
+package api
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/ankurdas111111/simplebank/webauthn"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebAuthn ceremonies a challenge can be used for.
+const (
+	ceremonyRegistration = "registration"
+	ceremonyLogin        = "login"
+)
+
+// passkeyChallengeDuration is how long the browser has to answer a challenge.
+const passkeyChallengeDuration = 5 * time.Minute
+
+var (
+	errPasskeyChallengeExpired = errors.New("passkey challenge expired, start again")
+	errNoPasskeys              = errors.New("user has no passkeys registered")
+	errUnknownPasskey          = errors.New("passkey is not registered for this user")
+)
+
+// Binary WebAuthn fields travel as unpadded base64url, as in the browser's
+// PublicKeyCredential JSON encoding.
+func encodePasskeyBytes(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodePasskeyBytes(field, s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not base64url: %w", field, err)
+	}
+	return b, nil
+}
+
+// passkeyUserHandle identifies the user to authenticators without handing
+// them the username.
+func passkeyUserHandle(username string) string {
+	sum := sha256.Sum256([]byte(username))
+	return encodePasskeyBytes(sum[:])
+}
+
+type passkeyCredentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+func passkeyDescriptors(credentials []db.WebauthnCredential) []passkeyCredentialDescriptor {
+	descriptors := make([]passkeyCredentialDescriptor, len(credentials))
+	for i, credential := range credentials {
+		descriptors[i] = passkeyCredentialDescriptor{Type: "public-key", ID: encodePasskeyBytes(credential.CredentialID)}
+	}
+	return descriptors
+}
+
+// createPasskeyChallenge stores a fresh challenge for one ceremony.
+func (server *Server) createPasskeyChallenge(ctx *gin.Context, username, ceremony string) (db.WebauthnChallenge, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return db.WebauthnChallenge{}, err
+	}
+	return server.store.CreateWebauthnChallenge(ctx, db.CreateWebauthnChallengeParams{
+		ID:        uuid.New(),
+		Username:  username,
+		Ceremony:  ceremony,
+		Challenge: challenge,
+		ExpiresAt: time.Now().Add(passkeyChallengeDuration),
+	})
+}
+
+// consumePasskeyChallenge fetches and deletes a challenge, writing the error
+// response if it cannot be used.
+func (server *Server) consumePasskeyChallenge(ctx *gin.Context, id uuid.UUID, ceremony string) (db.WebauthnChallenge, bool) {
+	challenge, err := server.store.ConsumeWebauthnChallenge(ctx, db.ConsumeWebauthnChallengeParams{
+		ID:       id,
+		Ceremony: ceremony,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return challenge, false
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return challenge, false
+	}
+	if time.Now().After(challenge.ExpiresAt) {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errPasskeyChallengeExpired))
+		return challenge, false
+	}
+	return challenge, true
+}
+
+type beginPasskeyRegistrationRequest struct {
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type passkeyRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type passkeyUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type passkeyCredentialParameter struct {
+	Type      string `json:"type"`
+	Algorithm int64  `json:"alg"`
+}
+
+type passkeyAuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// passkeyCreationOptions is passed by the client to navigator.credentials.create().
+type passkeyCreationOptions struct {
+	Challenge              string                        `json:"challenge"`
+	RP                     passkeyRelyingParty           `json:"rp"`
+	User                   passkeyUser                   `json:"user"`
+	PubKeyCredParams       []passkeyCredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                         `json:"timeout"`
+	Attestation            string                        `json:"attestation"`
+	AuthenticatorSelection passkeyAuthenticatorSelection `json:"authenticatorSelection"`
+	ExcludeCredentials     []passkeyCredentialDescriptor `json:"excludeCredentials"`
+}
+
+type beginPasskeyRegistrationResponse struct {
+	ChallengeID uuid.UUID              `json:"challenge_id"`
+	PublicKey   passkeyCreationOptions `json:"public_key"`
+}
+
+// beginPasskeyRegistration starts adding a passkey. Like enrolling TOTP it
+// asks for the password, so a stolen access token can't plant a passkey.
+func (server *Server) beginPasskeyRegistration(ctx *gin.Context) {
+	var req beginPasskeyRegistrationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	if err := util.CheckPassword(req.Password, user.HashedPassword); err != nil {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, err))
+		return
+	}
+
+	credentials, err := server.store.ListWebauthnCredentials(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	challenge, err := server.createPasskeyChallenge(ctx, user.Username, ceremonyRegistration)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	params := make([]passkeyCredentialParameter, len(webauthn.SupportedAlgorithms))
+	for i, alg := range webauthn.SupportedAlgorithms {
+		params[i] = passkeyCredentialParameter{Type: "public-key", Algorithm: alg}
+	}
+
+	ctx.JSON(http.StatusOK, beginPasskeyRegistrationResponse{
+		ChallengeID: challenge.ID,
+		PublicKey: passkeyCreationOptions{
+			Challenge: encodePasskeyBytes(challenge.Challenge),
+			RP: passkeyRelyingParty{
+				ID:   server.relyingParty.ID,
+				Name: server.relyingParty.Name,
+			},
+			User: passkeyUser{
+				ID:          passkeyUserHandle(user.Username),
+				Name:        user.Username,
+				DisplayName: user.FullName,
+			},
+			PubKeyCredParams: params,
+			Timeout:          passkeyChallengeDuration.Milliseconds(),
+			Attestation:      "none",
+			AuthenticatorSelection: passkeyAuthenticatorSelection{
+				ResidentKey:      "preferred",
+				UserVerification: "required",
+			},
+			ExcludeCredentials: passkeyDescriptors(credentials),
+		},
+	})
+}
+
+type finishPasskeyRegistrationRequest struct {
+	ChallengeID       string `json:"challenge_id" binding:"required,uuid"`
+	Name              string `json:"name" binding:"required,max=64"`
+	ClientDataJSON    string `json:"client_data_json" binding:"required"`
+	AttestationObject string `json:"attestation_object" binding:"required"`
+}
+
+type passkeyResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// finishPasskeyRegistration verifies the authenticator's response and stores
+// the new credential.
+func (server *Server) finishPasskeyRegistration(ctx *gin.Context) {
+	var req finishPasskeyRegistrationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	clientDataJSON, err := decodePasskeyBytes("client_data_json", req.ClientDataJSON)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	attestationObject, err := decodePasskeyBytes("attestation_object", req.AttestationObject)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	challenge, ok := server.consumePasskeyChallenge(ctx, uuid.MustParse(req.ChallengeID), ceremonyRegistration)
+	if !ok {
+		return
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if challenge.Username != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("challenge was issued to another user")))
+		return
+	}
+
+	credential, err := server.relyingParty.VerifyRegistration(challenge.Challenge, clientDataJSON, attestationObject)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	stored, err := server.store.CreateWebauthnCredential(ctx, db.CreateWebauthnCredentialParams{
+		Username:     challenge.Username,
+		CredentialID: credential.ID,
+		PublicKey:    credential.PublicKey,
+		SignCount:    int64(credential.SignCount),
+		Name:         req.Name,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+	server.securityAlert(ctx, challenge.Username, notify.SecurityPasskeyAdded)
+
+	ctx.JSON(http.StatusOK, passkeyResponse{
+		ID:        stored.ID,
+		Name:      stored.Name,
+		CreatedAt: stored.CreatedAt,
+	})
+}
+
+type beginPasskeyLoginRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+}
+
+// passkeyRequestOptions is passed by the client to navigator.credentials.get().
+type passkeyRequestOptions struct {
+	Challenge        string                        `json:"challenge"`
+	RPID             string                        `json:"rpId"`
+	Timeout          int64                         `json:"timeout"`
+	UserVerification string                        `json:"userVerification"`
+	AllowCredentials []passkeyCredentialDescriptor `json:"allowCredentials"`
+}
+
+type beginPasskeyLoginResponse struct {
+	ChallengeID uuid.UUID             `json:"challenge_id"`
+	PublicKey   passkeyRequestOptions `json:"public_key"`
+}
+
+func (server *Server) beginPasskeyLogin(ctx *gin.Context) {
+	var req beginPasskeyLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, req.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	credentials, err := server.store.ListWebauthnCredentials(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if len(credentials) == 0 {
+		ctx.JSON(errorResponse(http.StatusNotFound, errNoPasskeys))
+		return
+	}
+
+	challenge, err := server.createPasskeyChallenge(ctx, user.Username, ceremonyLogin)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, beginPasskeyLoginResponse{
+		ChallengeID: challenge.ID,
+		PublicKey: passkeyRequestOptions{
+			Challenge:        encodePasskeyBytes(challenge.Challenge),
+			RPID:             server.relyingParty.ID,
+			Timeout:          passkeyChallengeDuration.Milliseconds(),
+			UserVerification: "required",
+			AllowCredentials: passkeyDescriptors(credentials),
+		},
+	})
+}
+
+type finishPasskeyLoginRequest struct {
+	ChallengeID       string `json:"challenge_id" binding:"required,uuid"`
+	CredentialID      string `json:"credential_id" binding:"required"`
+	ClientDataJSON    string `json:"client_data_json" binding:"required"`
+	AuthenticatorData string `json:"authenticator_data" binding:"required"`
+	Signature         string `json:"signature" binding:"required"`
+}
+
+// finishPasskeyLogin verifies the assertion and logs the user in exactly as
+// a password login would.
+func (server *Server) finishPasskeyLogin(ctx *gin.Context) {
+	var req finishPasskeyLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var fields [4][]byte
+	for i, field := range []struct{ name, value string }{
+		{"credential_id", req.CredentialID},
+		{"client_data_json", req.ClientDataJSON},
+		{"authenticator_data", req.AuthenticatorData},
+		{"signature", req.Signature},
+	} {
+		b, err := decodePasskeyBytes(field.name, field.value)
+		if err != nil {
+			ctx.JSON(errorResponse(http.StatusBadRequest, err))
+			return
+		}
+		fields[i] = b
+	}
+	credentialID, clientDataJSON, authenticatorData, signature := fields[0], fields[1], fields[2], fields[3]
+
+	challenge, ok := server.consumePasskeyChallenge(ctx, uuid.MustParse(req.ChallengeID), ceremonyLogin)
+	if !ok {
+		return
+	}
+
+	credential, err := server.store.GetWebauthnCredential(ctx, credentialID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusUnauthorized, errUnknownPasskey))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if credential.Username != challenge.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errUnknownPasskey))
+		return
+	}
+
+	signCount, err := server.relyingParty.VerifyAssertion(challenge.Challenge, webauthn.Credential{
+		ID:        credential.CredentialID,
+		PublicKey: credential.PublicKey,
+		SignCount: uint32(credential.SignCount),
+	}, clientDataJSON, authenticatorData, signature)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, err))
+		return
+	}
+
+	err = server.store.UpdateWebauthnCredentialSignCount(ctx, db.UpdateWebauthnCredentialSignCountParams{
+		ID:        credential.ID,
+		SignCount: int64(signCount),
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, credential.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	server.completeLogin(ctx, user)
+}
@@ -0,0 +1,409 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/webauthn"
+	"github.com/ankurdas111111/simplebank/webauthn/webauthntest"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuthenticator(t *testing.T, server *Server) *webauthntest.Authenticator {
+	authenticator, err := webauthntest.NewAuthenticator(server.relyingParty.ID, server.relyingParty.Origin)
+	require.NoError(t, err)
+	return authenticator
+}
+
+func randomPasskeyChallenge(t *testing.T, username, ceremony string) db.WebauthnChallenge {
+	challenge, err := webauthn.NewChallenge()
+	require.NoError(t, err)
+	return db.WebauthnChallenge{
+		ID:        uuid.New(),
+		Username:  username,
+		Ceremony:  ceremony,
+		Challenge: challenge,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+}
+
+func servePasskeyRequest(t *testing.T, server *Server, url string, body gin.H, setupAuth func(request *http.Request)) *httptest.ResponseRecorder {
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	require.NoError(t, err)
+	if setupAuth != nil {
+		setupAuth(request)
+	}
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestBeginPasskeyRegistrationAPI(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		password      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListWebauthnCredentials(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return([]db.WebauthnCredential{{CredentialID: []byte{1, 2, 3}}}, nil)
+				store.EXPECT().CreateWebauthnChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateWebauthnChallengeParams) (db.WebauthnChallenge, error) {
+						require.Equal(t, ceremonyRegistration, arg.Ceremony)
+						require.Len(t, arg.Challenge, webauthn.ChallengeSize)
+						return db.WebauthnChallenge{ID: arg.ID, Username: arg.Username, Ceremony: arg.Ceremony, Challenge: arg.Challenge, ExpiresAt: arg.ExpiresAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp beginPasskeyRegistrationResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, "bank.example.com", rsp.PublicKey.RP.ID)
+				require.Equal(t, user.Username, rsp.PublicKey.User.Name)
+				require.NotContains(t, rsp.PublicKey.User.ID, user.Username)
+				require.Equal(t, "AQID", rsp.PublicKey.ExcludeCredentials[0].ID)
+			},
+		},
+		{
+			name:     "WrongPassword",
+			password: "wrong-password",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateWebauthnChallenge(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := newTestServer(t, store)
+
+			recorder := servePasskeyRequest(t, server, "/api/users/webauthn/register/begin", gin.H{"password": tc.password}, func(request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			})
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestFinishPasskeyRegistrationAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		challenge     func(t *testing.T) db.WebauthnChallenge
+		answer        func(challenge db.WebauthnChallenge) []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			challenge: func(t *testing.T) db.WebauthnChallenge {
+				return randomPasskeyChallenge(t, user.Username, ceremonyRegistration)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebauthnCredential(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateWebauthnCredentialParams) (db.WebauthnCredential, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, "laptop", arg.Name)
+						require.NotEmpty(t, arg.PublicKey)
+						return db.WebauthnCredential{ID: 1, Name: arg.Name}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUsersChallenge",
+			challenge: func(t *testing.T) db.WebauthnChallenge {
+				return randomPasskeyChallenge(t, "someone-else", ceremonyRegistration)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebauthnCredential(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			challenge: func(t *testing.T) db.WebauthnChallenge {
+				challenge := randomPasskeyChallenge(t, user.Username, ceremonyRegistration)
+				challenge.ExpiresAt = time.Now().Add(-time.Second)
+				return challenge
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebauthnCredential(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AnswersAnotherChallenge",
+			challenge: func(t *testing.T) db.WebauthnChallenge {
+				return randomPasskeyChallenge(t, user.Username, ceremonyRegistration)
+			},
+			answer: func(challenge db.WebauthnChallenge) []byte {
+				return append([]byte{0}, challenge.Challenge[1:]...)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebauthnCredential(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			challenge := tc.challenge(t)
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().ConsumeWebauthnChallenge(gomock.Any(), gomock.Eq(db.ConsumeWebauthnChallengeParams{
+				ID:       challenge.ID,
+				Ceremony: ceremonyRegistration,
+			})).
+				Times(1).
+				Return(challenge, nil)
+			tc.buildStubs(store)
+			server := newTestServer(t, store)
+
+			answered := challenge.Challenge
+			if tc.answer != nil {
+				answered = tc.answer(challenge)
+			}
+			clientDataJSON, attestationObject := newTestAuthenticator(t, server).Register(answered)
+
+			recorder := servePasskeyRequest(t, server, "/api/users/webauthn/register/finish", gin.H{
+				"challenge_id":       challenge.ID,
+				"name":               "laptop",
+				"client_data_json":   encodePasskeyBytes(clientDataJSON),
+				"attestation_object": encodePasskeyBytes(attestationObject),
+			}, func(request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			})
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestBeginPasskeyLoginAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListWebauthnCredentials(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return([]db.WebauthnCredential{{CredentialID: []byte{1, 2, 3}}}, nil)
+				store.EXPECT().CreateWebauthnChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateWebauthnChallengeParams) (db.WebauthnChallenge, error) {
+						require.Equal(t, ceremonyLogin, arg.Ceremony)
+						return db.WebauthnChallenge{ID: arg.ID, Username: arg.Username, Ceremony: arg.Ceremony, Challenge: arg.Challenge, ExpiresAt: arg.ExpiresAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp beginPasskeyLoginResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, "bank.example.com", rsp.PublicKey.RPID)
+				require.Len(t, rsp.PublicKey.AllowCredentials, 1)
+			},
+		},
+		{
+			name: "NoPasskeys",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListWebauthnCredentials(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return([]db.WebauthnCredential{}, nil)
+				store.EXPECT().CreateWebauthnChallenge(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "UserNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := newTestServer(t, store)
+
+			recorder := servePasskeyRequest(t, server, "/api/users/webauthn/login/begin", gin.H{"username": user.Username}, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestFinishPasskeyLoginAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		owner         string
+		signCount     int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			owner: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateWebauthnCredentialSignCount(gomock.Any(), gomock.Eq(db.UpdateWebauthnCredentialSignCountParams{
+					ID:        1,
+					SignCount: 1,
+				})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginEvent(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginEvent{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp loginUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.AccessToken)
+				require.Equal(t, user.Username, rsp.User.Username)
+			},
+		},
+		{
+			name:  "OtherUsersPasskey",
+			owner: "someone-else",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateWebauthnCredentialSignCount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "CloneDetected",
+			owner:     user.Username,
+			signCount: 5,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateWebauthnCredentialSignCount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+			authenticator := newTestAuthenticator(t, server)
+
+			// Register through the relying party to get the stored form of the key.
+			registration := randomPasskeyChallenge(t, user.Username, ceremonyRegistration)
+			clientDataJSON, attestationObject := authenticator.Register(registration.Challenge)
+			credential, err := server.relyingParty.VerifyRegistration(registration.Challenge, clientDataJSON, attestationObject)
+			require.NoError(t, err)
+
+			challenge := randomPasskeyChallenge(t, user.Username, ceremonyLogin)
+			store.EXPECT().ConsumeWebauthnChallenge(gomock.Any(), gomock.Eq(db.ConsumeWebauthnChallengeParams{
+				ID:       challenge.ID,
+				Ceremony: ceremonyLogin,
+			})).
+				Times(1).
+				Return(challenge, nil)
+			store.EXPECT().GetWebauthnCredential(gomock.Any(), gomock.Eq(credential.ID)).
+				Times(1).
+				Return(db.WebauthnCredential{
+					ID:           1,
+					Username:     tc.owner,
+					CredentialID: credential.ID,
+					PublicKey:    credential.PublicKey,
+					SignCount:    tc.signCount,
+				}, nil)
+			tc.buildStubs(store)
+
+			clientDataJSON, authenticatorData, signature, err := authenticator.Assert(challenge.Challenge)
+			require.NoError(t, err)
+
+			recorder := servePasskeyRequest(t, server, "/api/users/webauthn/login/finish", gin.H{
+				"challenge_id":       challenge.ID,
+				"credential_id":      encodePasskeyBytes(credential.ID),
+				"client_data_json":   encodePasskeyBytes(clientDataJSON),
+				"authenticator_data": encodePasskeyBytes(authenticatorData),
+				"signature":          encodePasskeyBytes(signature),
+			}, nil)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/png"
+	"net/http"
+	"strings"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	paymentQRPurpose = "payment-qr"
+	// paymentQRScheme starts every payment code, so scanning apps can tell
+	// them from other QR codes.
+	paymentQRScheme = "simplebank:pay:"
+	// paymentQRSize is the width and height of the PNG, in pixels.
+	paymentQRSize = 320
+)
+
+var (
+	errPaymentQRInvalid = errors.New("invalid payment_qr")
+	errPaymentQRStale   = errors.New("payment_qr no longer matches the account, ask the payee for a new one")
+)
+
+// paymentQR is what a payment code carries. The owner and currency are
+// checked against the account when paying, so a code of an account that
+// changed hands is refused rather than paying someone else.
+type paymentQR struct {
+	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
+	Currency  string `json:"currency"`
+}
+
+type paymentQRResponse struct {
+	// Pass as payment_qr to POST /transfers.
+	Payload string `json:"payload"`
+	paymentQR
+}
+
+// getPaymentQR hands out the payment code of one of the caller's accounts.
+// Codes don't expire, so they can be printed.
+func (server *Server) getPaymentQR(ctx *gin.Context) {
+	payload, ok := server.paymentQRPayload(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, payload)
+}
+
+// getPaymentQRImage is getPaymentQR as a QR code.
+func (server *Server) getPaymentQRImage(ctx *gin.Context) {
+	payload, ok := server.paymentQRPayload(ctx)
+	if !ok {
+		return
+	}
+
+	code, err := qr.Encode(payload.Payload, qr.M, qr.Auto)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	code, err = barcode.Scale(code, paymentQRSize, paymentQRSize)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	var image bytes.Buffer
+	if err := png.Encode(&image, code); err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`inline; filename="payment-%d.png"`, payload.AccountID))
+	ctx.Data(http.StatusOK, "image/png", image.Bytes())
+}
+
+func (server *Server) paymentQRPayload(ctx *gin.Context) (paymentQRResponse, bool) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return paymentQRResponse{}, false
+	}
+	account, ok := server.ownAccount(ctx, uri.ID)
+	if !ok {
+		return paymentQRResponse{}, false
+	}
+
+	code := paymentQR{AccountID: account.ID, Owner: account.Owner, Currency: account.Currency}
+	signed, err := server.signPayload(paymentQRPurpose, code)
+	if err != nil {
+		ctx.JSON(paymentQRErrorResponse(err))
+		return paymentQRResponse{}, false
+	}
+	return paymentQRResponse{Payload: paymentQRScheme + signed, paymentQR: code}, true
+}
+
+// paymentQRAccount looks up the account a payment code was issued for.
+func (server *Server) paymentQRAccount(ctx *gin.Context, payload string) (db.Account, error) {
+	signed, ok := strings.CutPrefix(payload, paymentQRScheme)
+	if !ok {
+		return db.Account{}, errPaymentQRInvalid
+	}
+	var code paymentQR
+	if err := server.verifyPayload(paymentQRPurpose, signed, &code); err != nil {
+		if errors.Is(err, errBadSignature) {
+			return db.Account{}, errPaymentQRInvalid
+		}
+		return db.Account{}, err
+	}
+
+	account, err := server.store.GetAccount(ctx, code.AccountID)
+	if err != nil {
+		return account, err
+	}
+	if account.Owner != code.Owner || account.Currency != code.Currency {
+		return account, errPaymentQRStale
+	}
+	return account, nil
+}
+
+func paymentQRErrorResponse(err error) (int, apiError) {
+	switch {
+	case errors.Is(err, errPaymentQRInvalid), errors.Is(err, errPaymentQRStale):
+		return errorResponse(http.StatusBadRequest, err)
+	case errors.Is(err, errSigningDisabled):
+		return errorResponse(http.StatusServiceUnavailable, err)
+	}
+	return errorResponse(http.StatusInternalServerError, err)
+}
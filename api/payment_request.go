@@ -0,0 +1,333 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/limits"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errRequestFromSelf        = errors.New("cannot request money from yourself")
+	errPaymentRequestCurrency = errors.New("payment must come from an account in the requested currency")
+	errPaymentRequestNotParty = errors.New("payment request doesn't involve the authenticated user")
+	errPaymentRequestNotPayer = errors.New("only the payer can answer a payment request")
+)
+
+// Directions of GET /payment-requests.
+const (
+	paymentRequestIncoming = "incoming"
+	paymentRequestOutgoing = "outgoing"
+)
+
+type createPaymentRequestRequest struct {
+	Payer       string `json:"payer" binding:"required,alphanum"`
+	ToAccountID int64  `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64  `json:"amount" binding:"required,gt=0"`
+	Note        string `json:"note" binding:"max=140"`
+}
+
+type paymentRequestResponse struct {
+	ID          int64      `json:"id"`
+	Requester   string     `json:"requester"`
+	Payer       string     `json:"payer"`
+	ToAccountID int64      `json:"to_account_id"`
+	Amount      int64      `json:"amount"`
+	Note        string     `json:"note"`
+	Status      string     `json:"status"`
+	TransferID  *int64     `json:"transfer_id,omitempty"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func newPaymentRequestResponse(request db.PaymentRequest) paymentRequestResponse {
+	rsp := paymentRequestResponse{
+		ID:          request.ID,
+		Requester:   request.Requester,
+		Payer:       request.Payer,
+		ToAccountID: request.ToAccountID,
+		Amount:      request.Amount,
+		Note:        request.Note,
+		Status:      request.Status,
+		CreatedAt:   request.CreatedAt,
+	}
+	if request.TransferID.Valid {
+		rsp.TransferID = &request.TransferID.Int64
+	}
+	if request.RespondedAt.Valid {
+		rsp.RespondedAt = &request.RespondedAt.Time
+	}
+	return rsp
+}
+
+// createPaymentRequest asks another user to pay into one of the caller's
+// accounts. The amount is in that account's currency.
+func (server *Server) createPaymentRequest(ctx *gin.Context) {
+	var req createPaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.Payer == authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errRequestFromSelf))
+		return
+	}
+
+	toAccount, err := server.store.GetAccount(ctx, req.ToAccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if toAccount.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("account doesn't belong to the authenticated user")))
+		return
+	}
+
+	if _, err := server.store.GetUser(ctx, req.Payer); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	request, err := server.store.CreatePaymentRequest(ctx, db.CreatePaymentRequestParams{
+		Requester:   authPayload.Username,
+		Payer:       req.Payer,
+		ToAccountID: toAccount.ID,
+		Amount:      req.Amount,
+		Note:        req.Note,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPaymentRequestResponse(request))
+}
+
+type listPaymentRequestsRequest struct {
+	cursorPageRequest
+	Direction string `form:"direction" binding:"omitempty,oneof=incoming outgoing"`
+}
+
+// listPaymentRequests returns the requests the caller has to answer or, with
+// direction=outgoing, the ones they made. Oldest first.
+func (server *Server) listPaymentRequests(ctx *gin.Context) {
+	var req listPaymentRequestsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	afterID, err := req.lastID()
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	var rows []db.PaymentRequest
+	if req.Direction == paymentRequestOutgoing {
+		rows, err = server.store.ListOutgoingPaymentRequests(ctx, db.ListOutgoingPaymentRequestsParams{
+			Requester: authPayload.Username,
+			AfterID:   afterID,
+			PageLimit: req.limit() + 1,
+		})
+	} else {
+		rows, err = server.store.ListIncomingPaymentRequests(ctx, db.ListIncomingPaymentRequestsParams{
+			Payer:     authPayload.Username,
+			AfterID:   afterID,
+			PageLimit: req.limit() + 1,
+		})
+	}
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	page := newListResponse(rows, req.limit(), func(request db.PaymentRequest) int64 { return request.ID })
+	items := make([]paymentRequestResponse, len(page.Items))
+	for i, request := range page.Items {
+		items[i] = newPaymentRequestResponse(request)
+	}
+	ctx.JSON(http.StatusOK, withItems(page, items))
+}
+
+type paymentRequestURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getPaymentRequest(ctx *gin.Context) {
+	request, ok := server.loadPaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if request.Requester != authPayload.Username && request.Payer != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errPaymentRequestNotParty))
+		return
+	}
+	ctx.JSON(http.StatusOK, newPaymentRequestResponse(request))
+}
+
+type acceptPaymentRequestRequest struct {
+	// Without it, the payer's account in the requested currency is used.
+	FromAccountID int64 `json:"from_account_id" binding:"omitempty,min=1"`
+}
+
+// acceptPaymentRequest pays a pending request. The transfer gets the same
+// checks as one made with POST /transfers.
+func (server *Server) acceptPaymentRequest(ctx *gin.Context) {
+	request, ok := server.payerPaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	// The body is optional.
+	var req acceptPaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	toAccount, err := server.store.GetAccount(ctx, request.ToAccountID)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	var fromAccount db.Account
+	if req.FromAccountID != 0 {
+		fromAccount, err = server.store.GetAccount(ctx, req.FromAccountID)
+	} else {
+		fromAccount, err = server.store.GetAccountByOwnerAndCurrency(ctx, db.GetAccountByOwnerAndCurrencyParams{
+			Owner:    request.Payer,
+			Currency: toAccount.Currency,
+		})
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if fromAccount.Owner != request.Payer {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("from account doesn't belong to the authenticated user")))
+		return
+	}
+	if fromAccount.Currency != toAccount.Currency {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errPaymentRequestCurrency))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, request.Payer)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	err = server.limitEngine.CheckTransfer(ctx, limits.Transfer{
+		Tier:     user.KycTier,
+		Tenant:   user.Tenant,
+		Amount:   request.Amount,
+		Currency: fromAccount.Currency,
+		External: true,
+	})
+	if err != nil {
+		if errors.Is(err, limits.ErrNotAllowed) {
+			ctx.JSON(errorResponse(http.StatusForbidden, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if server.requiresStepUp(ctx, request.Amount, fromAccount.Currency) && !authPayload.Elevated {
+		ctx.JSON(errorResponse(http.StatusForbidden, errStepUpRequired))
+		return
+	}
+
+	result, err := server.store.AcceptPaymentRequestTx(ctx, db.AcceptPaymentRequestTxParams{
+		ID:            request.ID,
+		FromAccountID: fromAccount.ID,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"payment_request": newPaymentRequestResponse(result.PaymentRequest),
+		"transfer":        result.Transfer,
+	})
+}
+
+func (server *Server) declinePaymentRequest(ctx *gin.Context) {
+	request, ok := server.payerPaymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	request, err := server.store.DeclinePaymentRequest(ctx, request.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusConflict, db.ErrPaymentRequestAnswered))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPaymentRequestResponse(request))
+}
+
+// payerPaymentRequest loads the payment request in the URI and checks that
+// the caller is the one asked to pay. It writes the error response itself.
+func (server *Server) payerPaymentRequest(ctx *gin.Context) (db.PaymentRequest, bool) {
+	request, ok := server.loadPaymentRequest(ctx)
+	if !ok {
+		return db.PaymentRequest{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if request.Payer != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errPaymentRequestNotPayer))
+		return db.PaymentRequest{}, false
+	}
+	return request, true
+}
+
+func (server *Server) loadPaymentRequest(ctx *gin.Context) (db.PaymentRequest, bool) {
+	var uri paymentRequestURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return db.PaymentRequest{}, false
+	}
+
+	request, err := server.store.GetPaymentRequest(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return db.PaymentRequest{}, false
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return db.PaymentRequest{}, false
+	}
+	return request, true
+}
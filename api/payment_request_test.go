@@ -0,0 +1,314 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePaymentRequestAPI(t *testing.T) {
+	requester, _ := randomUser(t)
+	payer, _ := randomUser(t)
+	toAccount := db.Account{ID: 1, Owner: requester.Username, Currency: util.INR}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"payer": payer.Username, "to_account_id": toAccount.ID, "amount": 250, "note": "dinner"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(payer, nil)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Eq(db.CreatePaymentRequestParams{
+					Requester:   requester.Username,
+					Payer:       payer.Username,
+					ToAccountID: toAccount.ID,
+					Amount:      250,
+					Note:        "dinner",
+				})).
+					Times(1).
+					Return(db.PaymentRequest{ID: 5, Requester: requester.Username, Payer: payer.Username, ToAccountID: toAccount.ID, Amount: 250, Status: db.PaymentRequestPending}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got paymentRequestResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, int64(5), got.ID)
+				require.Equal(t, db.PaymentRequestPending, got.Status)
+			},
+		},
+		{
+			name: "FromSelf",
+			body: gin.H{"payer": requester.Username, "to_account_id": toAccount.ID, "amount": 250},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNotOwned",
+			body: gin.H{"payer": payer.Username, "to_account_id": toAccount.ID, "amount": 250},
+			buildStubs: func(store *mockdb.MockStore) {
+				other := toAccount
+				other.Owner = payer.Username
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(other, nil)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "PayerNotFound",
+			body: gin.H{"payer": payer.Username, "to_account_id": toAccount.ID, "amount": 250},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/payment-requests", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, requester.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAcceptPaymentRequestAPI(t *testing.T) {
+	payer, _ := randomUser(t)
+	payer.KycTier = util.KYCTierFull
+	requester, _ := randomUser(t)
+	toAccount := db.Account{ID: 1, Owner: requester.Username, Currency: util.INR}
+	fromAccount := db.Account{ID: 2, Owner: payer.Username, Balance: 10_000, Currency: util.INR}
+	usdAccount := db.Account{ID: 3, Owner: payer.Username, Currency: util.USD}
+	paymentRequest := db.PaymentRequest{
+		ID:          7,
+		Requester:   requester.Username,
+		Payer:       payer.Username,
+		ToAccountID: toAccount.ID,
+		Amount:      250,
+		Status:      db.PaymentRequestPending,
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccountByOwnerAndCurrency(gomock.Any(), gomock.Eq(db.GetAccountByOwnerAndCurrencyParams{
+					Owner:    payer.Username,
+					Currency: util.INR,
+				})).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(payer, nil)
+				accepted := paymentRequest
+				accepted.Status = db.PaymentRequestAccepted
+				accepted.TransferID = sql.NullInt64{Int64: 11, Valid: true}
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Eq(db.AcceptPaymentRequestTxParams{
+					ID:            paymentRequest.ID,
+					FromAccountID: fromAccount.ID,
+				})).
+					Times(1).
+					Return(db.AcceptPaymentRequestTxResult{PaymentRequest: accepted, Transfer: db.TransferTxResult{Transfer: db.Transfer{ID: 11}}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got struct {
+					PaymentRequest paymentRequestResponse `json:"payment_request"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, db.PaymentRequestAccepted, got.PaymentRequest.Status)
+				require.Equal(t, int64(11), *got.PaymentRequest.TransferID)
+			},
+		},
+		{
+			name:     "NotPayer",
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "CurrencyMismatch",
+			username: payer.Username,
+			body:     gin.H{"from_account_id": usdAccount.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(usdAccount.ID)).Times(1).Return(usdAccount, nil)
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyAnswered",
+			username: payer.Username,
+			body:     gin.H{"from_account_id": fromAccount.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(payer, nil)
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AcceptPaymentRequestTxResult{}, db.ErrPaymentRequestAnswered)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().ListSettings(gomock.Any()).AnyTimes().Return([]db.Setting{}, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+
+			url := fmt.Sprintf("/api/payment-requests/%d/accept", paymentRequest.ID)
+			request, err := http.NewRequest(http.MethodPost, url, &body)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeclinePaymentRequestAPI(t *testing.T) {
+	paymentRequest := db.PaymentRequest{
+		ID:          7,
+		Requester:   util.RandomOwner(),
+		Payer:       util.RandomOwner(),
+		ToAccountID: 1,
+		Amount:      250,
+		Status:      db.PaymentRequestPending,
+	}
+	declined := paymentRequest
+	declined.Status = db.PaymentRequestDeclined
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().DeclinePaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(declined, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got paymentRequestResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, db.PaymentRequestDeclined, got.Status)
+			},
+		},
+		{
+			name: "AlreadyAnswered",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().DeclinePaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(db.PaymentRequest{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/payment-requests/%d/decline", paymentRequest.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, paymentRequest.Payer, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// accountResponse is an account with its pots and overdraft. AvailableBalance
+// is the balance less what holds set aside; Available is what can be spent,
+// including the overdraft left; TotalBalance adds what is set aside in pots to
+// the balance.
+type accountResponse struct {
+	db.Account
+	Pots             []db.Pot `json:"pots"`
+	TotalBalance     int64    `json:"total_balance"`
+	OverdraftUsed    int64    `json:"overdraft_used"`
+	AvailableBalance int64    `json:"available_balance"`
+	Available        int64    `json:"available"`
+}
+
+func newAccountResponse(account db.Account, pots []db.Pot) accountResponse {
+	rsp := accountResponse{
+		Account:          account,
+		Pots:             pots,
+		TotalBalance:     account.Balance,
+		OverdraftUsed:    max(0, -account.Balance),
+		AvailableBalance: account.AvailableBalance(),
+		Available:        max(0, account.Available()),
+	}
+	if rsp.Pots == nil {
+		rsp.Pots = []db.Pot{}
+	}
+	for _, pot := range pots {
+		rsp.TotalBalance += pot.Balance
+	}
+	return rsp
+}
+
+type createPotRequest struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// createPot adds an empty pot to an account of the user.
+func (server *Server) createPot(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req createPotRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, ok := server.ownAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+	if account.Status == db.AccountClosed {
+		ctx.JSON(errorResponse(http.StatusLocked, db.ErrAccountClosed))
+		return
+	}
+
+	pot, err := server.store.CreatePot(ctx, db.CreatePotParams{
+		AccountID: account.ID,
+		Name:      req.Name,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pot)
+}
+
+type movePotMoneyRequest struct {
+	// Leave out either side to move from or to the balance of the account.
+	FromPotID int64 `json:"from_pot_id" binding:"omitempty,min=1"`
+	ToPotID   int64 `json:"to_pot_id" binding:"omitempty,min=1,nefield=FromPotID"`
+	Amount    int64 `json:"amount" binding:"required,gt=0"`
+}
+
+// movePotMoney moves money between the balance of an account and its pots.
+// The money stays on the account, so no transfer checks apply.
+func (server *Server) movePotMoney(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req movePotMoneyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	account, ok := server.ownAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.MovePotMoneyTx(ctx, db.MovePotMoneyTxParams{
+		AccountID: account.ID,
+		FromPotID: req.FromPotID,
+		ToPotID:   req.ToPotID,
+		Amount:    req.Amount,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ownAccount loads an account and checks that it belongs to the user,
+// writing the error response if not.
+func (server *Server) ownAccount(ctx *gin.Context, id int64) (db.Account, bool) {
+	account, err := server.store.GetAccount(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return account, false
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return account, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("account doesn't belong to the authenticated user")))
+		return account, false
+	}
+	return account, true
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountWithPots(t *testing.T) {
+	account := randomAccount()
+	pots := []db.Pot{
+		{ID: 1, AccountID: account.ID, Name: "holiday", Balance: 50},
+		{ID: 2, AccountID: account.ID, Name: "rainy day", Balance: 25},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().ListPots(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(pots, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/accounts/%d", account.ID), nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, account.Owner, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp accountResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, account, rsp.Account)
+	require.Equal(t, pots, rsp.Pots)
+	require.Equal(t, account.Balance+75, rsp.TotalBalance)
+	require.Zero(t, rsp.OverdraftUsed)
+	require.Equal(t, account.Balance, rsp.Available)
+
+	// A move between pots changes the ETag even though the account didn't.
+	moved := []db.Pot{pots[0], pots[1]}
+	moved[0].Balance, moved[1].Balance = 25, 50
+	require.NotEqual(t, accountETag(account, pots), accountETag(account, moved))
+}
+
+func TestAccountResponseOverdraft(t *testing.T) {
+	account := randomAccount()
+	account.Balance = -30
+	account.OverdraftLimit = 100
+
+	rsp := newAccountResponse(account, nil)
+	require.Equal(t, int64(30), rsp.OverdraftUsed)
+	require.Equal(t, int64(70), rsp.Available)
+	require.Equal(t, []db.Pot{}, rsp.Pots)
+}
+
+func TestAccountResponseHolds(t *testing.T) {
+	account := randomAccount()
+	account.Balance = 100
+	account.Held = 40
+	account.OverdraftLimit = 10
+
+	rsp := newAccountResponse(account, nil)
+	require.Equal(t, int64(100), rsp.TotalBalance)
+	require.Equal(t, int64(60), rsp.AvailableBalance)
+	require.Equal(t, int64(70), rsp.Available)
+}
+
+func TestMovePotMoneyAPI(t *testing.T) {
+	account := randomAccount()
+	pot := db.Pot{ID: 7, AccountID: account.ID, Name: "holiday"}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "IntoPot",
+			body:     gin.H{"to_pot_id": pot.ID, "amount": 10},
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().MovePotMoneyTx(gomock.Any(), gomock.Eq(db.MovePotMoneyTxParams{
+					AccountID: account.ID,
+					ToPotID:   pot.ID,
+					Amount:    10,
+				})).Times(1).Return(db.MovePotMoneyTxResult{Account: account, ToPot: &pot}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "SamePot",
+			body:     gin.H{"from_pot_id": pot.ID, "to_pot_id": pot.ID, "amount": 10},
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "PotNotFound",
+			body:     gin.H{"from_pot_id": pot.ID, "amount": 10},
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().MovePotMoneyTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MovePotMoneyTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "InsufficientFunds",
+			body:     gin.H{"from_pot_id": pot.ID, "amount": 10},
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().MovePotMoneyTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MovePotMoneyTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:     "OtherUser",
+			body:     gin.H{"to_pot_id": pot.ID, "amount": 10},
+			username: "unauthorized_user",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().MovePotMoneyTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/accounts/%d/pots/moves", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRateLimitWindow is used when a request limit is set without a window.
+const defaultRateLimitWindow = time.Minute
+
+var errRateLimited = errors.New("too many requests, try again later")
+
+// rateLimiter counts requests per key in fixed windows. Counts are kept in
+// memory, so every instance enforces the limit on its own.
+type rateLimiter struct {
+	limit  int64
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+	// Ended windows are dropped by the first request from this time on.
+	sweepAt time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int64
+}
+
+func newRateLimiter(limit int64, window time.Duration) *rateLimiter {
+	if window <= 0 {
+		window = defaultRateLimitWindow
+	}
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		now:     time.Now,
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// take counts a request for key. It returns how many requests are left in the
+// current window and when the window ends, and false once the limit is used up.
+func (l *rateLimiter) take(key string) (remaining int64, reset time.Time, ok bool) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !now.Before(l.sweepAt) {
+		for k, w := range l.windows {
+			if !now.Before(w.start.Add(l.window)) {
+				delete(l.windows, k)
+			}
+		}
+		l.sweepAt = now.Add(l.window)
+	}
+
+	w, found := l.windows[key]
+	if !found || !now.Before(w.start.Add(l.window)) {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+	reset = w.start.Add(l.window)
+	if w.count >= l.limit {
+		return 0, reset, false
+	}
+	w.count++
+	return l.limit - w.count, reset, true
+}
+
+// rateLimitMiddleware limits the requests of each user, or of each client IP
+// on routes without a token. Every response says how much of the limit is
+// left and in how many seconds it resets, so clients can slow down before
+// they are turned away.
+func rateLimitMiddleware(limiter *rateLimiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := "ip:" + ctx.ClientIP()
+		if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+			key = "user:" + payload.(*token.Payload).Username
+		}
+
+		remaining, reset, ok := limiter.take(key)
+		wait := reset.Sub(limiter.now())
+		ctx.Header("X-RateLimit-Limit", strconv.FormatInt(limiter.limit, 10))
+		ctx.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		ctx.Header("X-RateLimit-Reset", strconv.FormatInt(seconds(wait), 10))
+		if !ok {
+			setRetryAfter(ctx, wait)
+			ctx.AbortWithStatusJSON(errorResponse(http.StatusTooManyRequests, errRateLimited))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// rateLimit is rateLimitMiddleware, or a no-op while rate limiting is disabled.
+func (server *Server) rateLimit() gin.HandlerFunc {
+	if server.rateLimiter == nil {
+		return func(ctx *gin.Context) { ctx.Next() }
+	}
+	return rateLimitMiddleware(server.rateLimiter)
+}
+
+// setRetryAfter tells the client how long to wait, in whole seconds.
+func setRetryAfter(ctx *gin.Context, wait time.Duration) {
+	ctx.Header("Retry-After", strconv.FormatInt(seconds(wait), 10))
+}
+
+// seconds rounds wait up to whole seconds, at least one, so a client waiting
+// that long is past it.
+func seconds(wait time.Duration) int64 {
+	return max(int64(math.Ceil(wait.Seconds())), 1)
+}
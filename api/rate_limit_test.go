@@ -0,0 +1,140 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(2, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	remaining, reset, ok := limiter.take("alice")
+	require.True(t, ok)
+	require.Equal(t, int64(1), remaining)
+	require.Equal(t, now.Add(time.Minute), reset)
+
+	remaining, _, ok = limiter.take("alice")
+	require.True(t, ok)
+	require.Zero(t, remaining)
+
+	_, _, ok = limiter.take("alice")
+	require.False(t, ok)
+
+	// Other keys have a limit of their own.
+	_, _, ok = limiter.take("bob")
+	require.True(t, ok)
+
+	// The next window starts afresh, and the ended ones are dropped.
+	now = now.Add(time.Minute)
+	remaining, _, ok = limiter.take("alice")
+	require.True(t, ok)
+	require.Equal(t, int64(1), remaining)
+	require.Len(t, limiter.windows, 1)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server, err := NewServer(util.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		RateLimitRequests:   2,
+		RateLimitWindow:     time.Hour,
+	}, mockdb.NewMockStore(ctrl))
+	require.NoError(t, err)
+
+	send := func(username string) *httptest.ResponseRecorder {
+		// Fails validation before reaching the store.
+		request, err := http.NewRequest(http.MethodGet, "/api/fx/quote", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, username, time.Minute)
+
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := send("alice")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, "2", recorder.Header().Get("X-RateLimit-Limit"))
+	require.Equal(t, "1", recorder.Header().Get("X-RateLimit-Remaining"))
+	require.Equal(t, "3600", recorder.Header().Get("X-RateLimit-Reset"))
+	require.Empty(t, recorder.Header().Get("Retry-After"))
+
+	recorder = send("alice")
+	require.Equal(t, "0", recorder.Header().Get("X-RateLimit-Remaining"))
+
+	recorder = send("alice")
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "0", recorder.Header().Get("X-RateLimit-Remaining"))
+	require.Equal(t, "3600", recorder.Header().Get("Retry-After"))
+	require.Contains(t, recorder.Body.String(), `"code":"rate_limited"`)
+
+	// Users are limited separately.
+	recorder = send("bob")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, "1", recorder.Header().Get("X-RateLimit-Remaining"))
+
+	// Before login, clients are told apart by IP.
+	request, err := http.NewRequest(http.MethodPost, "/api/users/login", nil)
+	require.NoError(t, err)
+	recorder = httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, "1", recorder.Header().Get("X-RateLimit-Remaining"))
+}
+
+func TestRateLimitPublicRoute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListCurrencies(gomock.Any()).
+		Times(1).
+		Return([]db.Currency{}, nil)
+
+	server, err := NewServer(util.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		RateLimitRequests:   2,
+		RateLimitWindow:     time.Hour,
+	}, store)
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodGet, "/currencies", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "2", recorder.Header().Get("X-RateLimit-Limit"))
+	require.Equal(t, "1", recorder.Header().Get("X-RateLimit-Remaining"))
+	require.Equal(t, "3600", recorder.Header().Get("X-RateLimit-Reset"))
+	require.Empty(t, recorder.Header().Get("Retry-After"))
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	request, err := http.NewRequest(http.MethodGet, "/api/fx/quote", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, "alice", time.Minute)
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	require.Empty(t, recorder.Header().Get("X-RateLimit-Limit"))
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"regexp"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// requestIDPattern is what a client's own request ID must look like to be
+// kept, so it can't inject anything into logs.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestIDMiddleware gives every request an ID, the one the client sent in
+// X-Request-ID if it has one, and returns it in the same header. Writes made
+// while handling the request are audited with it; auditMiddleware adds who
+// made them once the request is authenticated.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(requestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+		ctx.Header(requestIDHeader, id)
+		ctx.Set(db.AuditInfoKey, db.AuditInfo{ClientIP: ctx.ClientIP(), RequestID: id})
+		ctx.Next()
+	}
+}
+
+// requestAuditInfo is the audit info of the request, with its client IP when
+// requestIDMiddleware didn't run.
+func requestAuditInfo(ctx *gin.Context) db.AuditInfo {
+	info, ok := ctx.Value(db.AuditInfoKey).(db.AuditInfo)
+	if !ok {
+		info.ClientIP = ctx.ClientIP()
+	}
+	return info
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		name   string
+		header string
+		check  func(t *testing.T, id string)
+	}{
+		{
+			name:   "FromClient",
+			header: "checkout-42.retry:1",
+			check: func(t *testing.T, id string) {
+				require.Equal(t, "checkout-42.retry:1", id)
+			},
+		},
+		{
+			name: "Generated",
+			check: func(t *testing.T, id string) {
+				_, err := uuid.Parse(id)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "Unusable",
+			header: "line\nbreak",
+			check: func(t *testing.T, id string) {
+				_, err := uuid.Parse(id)
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := newTestServer(t, mockdb.NewMockStore(ctrl))
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+			require.NoError(t, err)
+			if tc.header != "" {
+				request.Header.Set(requestIDHeader, tc.header)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			tc.check(t, recorder.Header().Get(requestIDHeader))
+		})
+	}
+}
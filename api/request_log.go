@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// probePaths are polled every few seconds by the orchestrator, so their
+// requests are only logged at debug level.
+var probePaths = map[string]bool{"/healthz": true, "/readyz": true}
+
+// logRequests logs one line per request once it is handled. Server errors
+// are logged at error level and client errors at warn level, with the code
+// and message of the error response. The query string is left out, since it
+// may carry a token.
+func (server *Server) logRequests() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		writer := &errorRecordingWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		case probePaths[ctx.Request.URL.Path]:
+			level = slog.LevelDebug
+		}
+		if !server.logger.Enabled(ctx, level) {
+			return
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("request_id", requestAuditInfo(ctx).RequestID),
+			slog.String("client_ip", ctx.ClientIP()),
+		}
+		if payload, ok := ctx.Value(authorizationPayloadKey).(*token.Payload); ok {
+			attrs = append(attrs, slog.String("user", payload.Username))
+			if payload.ImpersonatedBy != "" {
+				attrs = append(attrs, slog.String("impersonated_by", payload.ImpersonatedBy))
+			}
+		}
+		if writer.err.Code != "" {
+			attrs = append(attrs, slog.Group("error",
+				slog.String("code", writer.err.Code),
+				slog.String("message", writer.err.Message),
+			))
+		}
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", strings.TrimSpace(ctx.Errors.String())))
+		}
+		server.logger.LogAttrs(ctx, level, "request", attrs...)
+	}
+}
+
+// errorRecordingWriter keeps the error response written by errorResponse, so
+// it can be logged. Like localizingWriter, it relies on handlers writing a
+// whole error body at once.
+type errorRecordingWriter struct {
+	gin.ResponseWriter
+	err apiError
+}
+
+func (w *errorRecordingWriter) Write(body []byte) (int, error) {
+	if w.err.Code == "" && w.Status() >= 400 && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		// Bodies that aren't an apiError leave err empty.
+		_ = json.Unmarshal(body, &w.err)
+	}
+	return w.ResponseWriter.Write(body)
+}
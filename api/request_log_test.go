@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/logging"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRequests(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		level      string
+		buildStubs func(store *mockdb.MockStore)
+		request    func(t *testing.T, server *Server) *http.Request
+		checkLog   func(t *testing.T, lines []map[string]interface{})
+	}{
+		{
+			name: "ClientError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(db.Account{}, sql.ErrNoRows)
+			},
+			request: func(t *testing.T, server *Server) *http.Request {
+				request, err := http.NewRequest(http.MethodGet, "/accounts/7?access_token=secret", nil)
+				require.NoError(t, err)
+				request.Header.Set(requestIDHeader, "request-1")
+				// Errors are logged in English whatever the client reads.
+				request.Header.Set("Accept-Language", "hi")
+				addAuthorization(t, request, server.tokenMaker, username, time.Minute)
+				return request
+			},
+			checkLog: func(t *testing.T, lines []map[string]interface{}) {
+				require.Len(t, lines, 1)
+				line := lines[0]
+				require.Equal(t, "WARN", line["level"])
+				require.Equal(t, "request", line["msg"])
+				require.Equal(t, http.MethodGet, line["method"])
+				require.Equal(t, "/accounts/7", line["path"])
+				require.Equal(t, "/accounts/:id", line["route"])
+				require.Equal(t, float64(http.StatusNotFound), line["status"])
+				require.Equal(t, "request-1", line["request_id"])
+				require.Equal(t, username, line["user"])
+				require.Contains(t, line, "latency")
+				require.Equal(t, map[string]interface{}{
+					"code":    "not_found",
+					"message": sql.ErrNoRows.Error(),
+				}, line["error"])
+			},
+		},
+		{
+			name:  "Panic",
+			level: "error",
+			request: func(t *testing.T, server *Server) *http.Request {
+				server.router.GET("/panic", func(ctx *gin.Context) { panic("boom") })
+				request, err := http.NewRequest(http.MethodGet, "/panic", nil)
+				require.NoError(t, err)
+				return request
+			},
+			checkLog: func(t *testing.T, lines []map[string]interface{}) {
+				require.Len(t, lines, 1)
+				require.Equal(t, "ERROR", lines[0]["level"])
+				require.Equal(t, float64(http.StatusInternalServerError), lines[0]["status"])
+				require.NotContains(t, lines[0], "user")
+			},
+		},
+		{
+			name:  "BelowLevel",
+			level: "warn",
+			request: func(t *testing.T, server *Server) *http.Request {
+				server.router.GET("/ok", func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) })
+				request, err := http.NewRequest(http.MethodGet, "/ok", nil)
+				require.NoError(t, err)
+				return request
+			},
+			checkLog: func(t *testing.T, lines []map[string]interface{}) {
+				require.Empty(t, lines)
+			},
+		},
+		{
+			name: "Probe",
+			request: func(t *testing.T, server *Server) *http.Request {
+				request, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+				require.NoError(t, err)
+				return request
+			},
+			checkLog: func(t *testing.T, lines []map[string]interface{}) {
+				require.Empty(t, lines)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			if tc.buildStubs != nil {
+				tc.buildStubs(store)
+			}
+
+			server := newTestServer(t, store)
+			var output bytes.Buffer
+			logger, err := logging.New(&output, tc.level)
+			require.NoError(t, err)
+			server.logger = logger
+
+			recorder := httptest.NewRecorder()
+			server.router.ServeHTTP(recorder, tc.request(t, server))
+
+			var lines []map[string]interface{}
+			decoder := json.NewDecoder(&output)
+			for decoder.More() {
+				var line map[string]interface{}
+				require.NoError(t, decoder.Decode(&line))
+				lines = append(lines, line)
+			}
+			tc.checkLog(t, lines)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/retention"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// listRetentionRules returns the retention period of every data set.
+func (server *Server) listRetentionRules(ctx *gin.Context) {
+	rules, err := server.store.ListRetentionRules(ctx)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rules)
+}
+
+type upsertRetentionRuleRequest struct {
+	Target     string `json:"target" binding:"required"`
+	RetainDays int32  `json:"retain_days" binding:"required,min=1"`
+	Enabled    bool   `json:"enabled"`
+}
+
+// upsertRetentionRule sets how long a data set is kept. Disabled rules are
+// kept but skipped by the purge job.
+func (server *Server) upsertRetentionRule(ctx *gin.Context) {
+	var req upsertRetentionRuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	if !db.IsRetentionTarget(req.Target) {
+		ctx.JSON(errorResponse(http.StatusBadRequest, fmt.Errorf("%w: %s", db.ErrUnknownRetentionTarget, req.Target)))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	rule, err := server.store.UpsertRetentionRule(ctx, db.UpsertRetentionRuleParams{
+		Target:     req.Target,
+		RetainDays: req.RetainDays,
+		Enabled:    req.Enabled,
+		UpdatedBy:  authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rule)
+}
+
+type runRetentionRequest struct {
+	DryRun bool `json:"dry_run"`
+}
+
+// runRetention applies the retention rules now instead of waiting for the
+// scheduled job. With dry_run nothing is removed, the report only shows what
+// would be.
+func (server *Server) runRetention(ctx *gin.Context) {
+	var req runRetentionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(db.WithoutTimeouts(ctx), retention.RunTimeout)
+	defer cancel()
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	report, err := server.store.PurgeRetentionTx(runCtx, db.PurgeRetentionTxParams{
+		Now:    time.Now(),
+		DryRun: req.DryRun,
+		RunBy:  authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+type listRetentionRunsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listRetentionRuns returns past runs, most recent first.
+func (server *Server) listRetentionRuns(ctx *gin.Context) {
+	var req listRetentionRunsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	runs, err := server.store.ListRetentionRuns(ctx, db.ListRetentionRunsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runs)
+}
+
+type getRetentionRunRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getRetentionRun returns the report of one run.
+func (server *Server) getRetentionRun(ctx *gin.Context) {
+	var req getRetentionRunRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	report, err := server.store.GetRetentionReport(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
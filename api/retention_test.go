@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertRetentionRuleAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"target": db.RetentionLoginEvents, "retain_days": 365, "enabled": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpsertRetentionRuleParams{
+					Target:     db.RetentionLoginEvents,
+					RetainDays: 365,
+					Enabled:    true,
+					UpdatedBy:  admin.Username,
+				}
+				store.EXPECT().UpsertRetentionRule(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.RetentionRule{Target: arg.Target, RetainDays: arg.RetainDays, Enabled: true}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownTarget",
+			body: gin.H{"target": "transfers", "retain_days": 365, "enabled": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertRetentionRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRetainDays",
+			body: gin.H{"target": db.RetentionSessions, "retain_days": 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertRetentionRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+				Times(1).
+				Return(admin, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/admin/retention/rules", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRunRetentionAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+		Times(1).
+		Return(admin, nil)
+	store.EXPECT().PurgeRetentionTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.PurgeRetentionTxParams) (db.RetentionReport, error) {
+			require.True(t, arg.DryRun)
+			require.Equal(t, admin.Username, arg.RunBy)
+			return db.RetentionReport{
+				Run: db.RetentionRun{ID: 1, DryRun: true, RunBy: admin.Username},
+				Items: []db.RetentionRunItem{
+					{RunID: 1, Target: db.RetentionLoginEvents, Action: db.RetentionPurge, Affected: 4},
+				},
+			}, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, "/admin/retention/runs", bytes.NewReader([]byte(`{"dry_run":true}`)))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, admin.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var report db.RetentionReport
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	require.True(t, report.Run.DryRun)
+	require.Equal(t, int64(4), report.Items[0].Affected)
+}
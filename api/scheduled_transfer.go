@@ -0,0 +1,241 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/limits"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errScheduleInPast           = errors.New("execute_at must be in the future")
+	errScheduledCurrency        = errors.New("scheduled transfers must be between accounts of the same currency")
+	errScheduledTransferNotOpen = errors.New("scheduled transfer is no longer pending")
+)
+
+type createScheduledTransferRequest struct {
+	FromAccountID int64     `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64     `json:"to_account_id" binding:"required,min=1"`
+	Amount        int64     `json:"amount" binding:"required,gt=0"`
+	ExecuteAt     time.Time `json:"execute_at" binding:"required"`
+}
+
+type scheduledTransferResponse struct {
+	ID            int64      `json:"id"`
+	FromAccountID int64      `json:"from_account_id"`
+	ToAccountID   int64      `json:"to_account_id"`
+	Amount        int64      `json:"amount"`
+	ExecuteAt     time.Time  `json:"execute_at"`
+	Status        string     `json:"status"`
+	Attempts      int32      `json:"attempts"`
+	TransferID    *int64     `json:"transfer_id,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	ExecutedAt    *time.Time `json:"executed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func newScheduledTransferResponse(scheduled db.ScheduledTransfer) scheduledTransferResponse {
+	rsp := scheduledTransferResponse{
+		ID:            scheduled.ID,
+		FromAccountID: scheduled.FromAccountID,
+		ToAccountID:   scheduled.ToAccountID,
+		Amount:        scheduled.Amount,
+		ExecuteAt:     scheduled.ExecuteAt,
+		Status:        scheduled.Status,
+		Attempts:      scheduled.Attempts,
+		LastError:     scheduled.LastError.String,
+		CreatedAt:     scheduled.CreatedAt,
+	}
+	if scheduled.TransferID.Valid {
+		rsp.TransferID = &scheduled.TransferID.Int64
+	}
+	if scheduled.ExecutedAt.Valid {
+		rsp.ExecutedAt = &scheduled.ExecutedAt.Time
+	}
+	return rsp
+}
+
+// createScheduledTransfer books a transfer for later. The checks of an
+// immediate transfer are made now; the scheduler checks the balance again
+// when the transfer is due.
+func (server *Server) createScheduledTransfer(ctx *gin.Context) {
+	var req createScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if !req.ExecuteAt.After(time.Now()) {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errScheduleInPast))
+		return
+	}
+
+	fromAccount, err := server.store.GetAccount(ctx, req.FromAccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("from account doesn't belong to the authenticated user")))
+		return
+	}
+
+	toAccount, err := server.store.GetAccount(ctx, req.ToAccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	// The rate a cross-currency transfer would get is only known when it runs.
+	if fromAccount.Currency != toAccount.Currency {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errScheduledCurrency))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	err = server.limitEngine.CheckTransfer(ctx, limits.Transfer{
+		Tier:     user.KycTier,
+		Tenant:   user.Tenant,
+		Amount:   req.Amount,
+		Currency: fromAccount.Currency,
+		External: toAccount.Owner != authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, limits.ErrNotAllowed) {
+			ctx.JSON(errorResponse(http.StatusForbidden, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	if server.requiresStepUp(ctx, req.Amount, fromAccount.Currency) && !authPayload.Elevated {
+		ctx.JSON(errorResponse(http.StatusForbidden, errStepUpRequired))
+		return
+	}
+
+	scheduled, err := server.store.CreateScheduledTransfer(ctx, db.CreateScheduledTransferParams{
+		Owner:         authPayload.Username,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        req.Amount,
+		ExecuteAt:     req.ExecuteAt,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newScheduledTransferResponse(scheduled))
+}
+
+// listScheduledTransfers returns the caller's scheduled transfers, oldest
+// first, whatever their status.
+func (server *Server) listScheduledTransfers(ctx *gin.Context) {
+	var req cursorPageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	afterID, err := req.lastID()
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	rows, err := server.store.ListScheduledTransfers(ctx, db.ListScheduledTransfersParams{
+		Owner:     authPayload.Username,
+		AfterID:   afterID,
+		PageLimit: req.limit() + 1,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	page := newListResponse(rows, req.limit(), func(scheduled db.ScheduledTransfer) int64 { return scheduled.ID })
+	items := make([]scheduledTransferResponse, len(page.Items))
+	for i, scheduled := range page.Items {
+		items[i] = newScheduledTransferResponse(scheduled)
+	}
+	ctx.JSON(http.StatusOK, withItems(page, items))
+}
+
+type scheduledTransferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getScheduledTransfer(ctx *gin.Context) {
+	scheduled, ok := server.ownScheduledTransfer(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, newScheduledTransferResponse(scheduled))
+}
+
+// cancelScheduledTransfer stops a pending transfer from running. Transfers
+// that already ran, or are running right now, cannot be cancelled.
+func (server *Server) cancelScheduledTransfer(ctx *gin.Context) {
+	scheduled, ok := server.ownScheduledTransfer(ctx)
+	if !ok {
+		return
+	}
+
+	scheduled, err := server.store.CancelScheduledTransfer(ctx, scheduled.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusConflict, errScheduledTransferNotOpen))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newScheduledTransferResponse(scheduled))
+}
+
+// ownScheduledTransfer loads the scheduled transfer in the URI and checks
+// that it belongs to the caller. It writes the error response itself.
+func (server *Server) ownScheduledTransfer(ctx *gin.Context) (db.ScheduledTransfer, bool) {
+	var uri scheduledTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return db.ScheduledTransfer{}, false
+	}
+
+	scheduled, err := server.store.GetScheduledTransfer(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return db.ScheduledTransfer{}, false
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return db.ScheduledTransfer{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if scheduled.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("scheduled transfer doesn't belong to the authenticated user")))
+		return db.ScheduledTransfer{}, false
+	}
+	return scheduled, true
+}
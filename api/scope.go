@@ -0,0 +1,224 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// routeScopes maps every authenticated route to the scope a token needs to
+// call it. Routes are keyed without the /api prefix. A route missing from this
+// table is rejected, so new routes must be added here.
+var routeScopes = map[string]string{
+	"POST /accounts":                     token.ScopeAccountsWrite,
+	"GET /accounts/:id":                  token.ScopeAccountsRead,
+	"GET /accounts":                      token.ScopeAccountsRead,
+	"POST /accounts/:id/deposit":         token.ScopeAccountsWrite,
+	"POST /deposits/external":            token.ScopeAccountsWrite,
+	"POST /accounts/:id/withdraw":        token.ScopeAccountsWrite,
+	"POST /accounts/:id/freeze":          token.ScopeAccountsWrite,
+	"DELETE /accounts/:id":               token.ScopeAccountsWrite,
+	"GET /accounts/:id/entries":          token.ScopeAccountsRead,
+	"GET /accounts/:id/payment_qr":       token.ScopeAccountsRead,
+	"GET /accounts/:id/payment_qr.png":   token.ScopeAccountsRead,
+	"GET /accounts/:id/summary":          token.ScopeAccountsRead,
+	"GET /accounts/:id/activity":         token.ScopeAccountsRead,
+	"GET /accounts/:id/balance_history":  token.ScopeAccountsRead,
+	"GET /accounts/:id/lookup":           token.ScopeAccountsRead,
+	"POST /accounts/:id/statement/email": token.ScopeAccountsRead,
+	"GET /accounts/:id/statement.pdf":    token.ScopeAccountsRead,
+	"GET /accounts/:id/events":           token.ScopeAccountsRead,
+	"POST /accounts/:id/pots":            token.ScopeAccountsWrite,
+	"POST /accounts/:id/pots/moves":      token.ScopeAccountsWrite,
+	"GET /accounts/:id/interest":         token.ScopeAccountsRead,
+	"GET /ws":                            token.ScopeAccountsRead,
+
+	"GET /fx/quote":                   token.ScopeTransfersRead,
+	"POST /transfers":                 token.ScopeTransfersWrite,
+	"GET /transfers":                  token.ScopeTransfersRead,
+	"GET /transfers/export":           token.ScopeTransfersRead,
+	"POST /transfers/batch":           token.ScopeTransfersWrite,
+	"POST /transfers/scheduled":       token.ScopeTransfersWrite,
+	"GET /transfers/scheduled":        token.ScopeTransfersRead,
+	"GET /transfers/scheduled/:id":    token.ScopeTransfersRead,
+	"DELETE /transfers/scheduled/:id": token.ScopeTransfersWrite,
+	"POST /transfers/:id/tags":        token.ScopeTransfersWrite,
+	"GET /transfers/:id/tags":         token.ScopeTransfersRead,
+	"DELETE /transfers/:id/tags/:tag": token.ScopeTransfersWrite,
+	"POST /entries/:id/tags":          token.ScopeAccountsWrite,
+	"GET /entries/:id/tags":           token.ScopeAccountsRead,
+	"DELETE /entries/:id/tags/:tag":   token.ScopeAccountsWrite,
+	"GET /tags/spending":              token.ScopeTransfersRead,
+
+	"POST /beneficiaries":       token.ScopeTransfersWrite,
+	"GET /beneficiaries":        token.ScopeTransfersRead,
+	"DELETE /beneficiaries/:id": token.ScopeTransfersWrite,
+
+	"POST /payment-requests":             token.ScopeTransfersWrite,
+	"GET /payment-requests":              token.ScopeTransfersRead,
+	"GET /payment-requests/:id":          token.ScopeTransfersRead,
+	"POST /payment-requests/:id/accept":  token.ScopeTransfersWrite,
+	"POST /payment-requests/:id/decline": token.ScopeTransfersWrite,
+
+	"POST /webhooks":               token.ScopeAccountsWrite,
+	"GET /webhooks":                token.ScopeAccountsRead,
+	"DELETE /webhooks/:id":         token.ScopeAccountsWrite,
+	"GET /webhooks/:id/deliveries": token.ScopeAccountsRead,
+	"POST /kyc/documents":          token.ScopeKYCWrite,
+	"GET /kyc/documents":           token.ScopeKYCRead,
+
+	"POST /tokens":                           token.ScopeTokensWrite,
+	"POST /users/elevate":                    token.ScopeTokensWrite,
+	"POST /users/totp":                       token.ScopeTokensWrite,
+	"PATCH /users/:username":                 token.ScopeTokensWrite,
+	"GET /users/me":                          token.ScopeAccountsRead,
+	"GET /users/me/insights/counterparties":  token.ScopeTransfersRead,
+	"GET /users/me/limits":                   token.ScopeTransfersRead,
+	"GET /users/me/notification-preferences": token.ScopeAccountsRead,
+	"GET /notifications":                     token.ScopeAccountsRead,
+	"PUT /users/me/notification-preferences": token.ScopeTokensWrite,
+
+	"POST /users/webauthn/register/begin":  token.ScopeTokensWrite,
+	"POST /users/webauthn/register/finish": token.ScopeTokensWrite,
+
+	"GET /admin/users":                        token.ScopeAdmin,
+	"POST /admin/users/:username/suspend":     token.ScopeAdmin,
+	"POST /admin/users/:username/reactivate":  token.ScopeAdmin,
+	"GET /admin/accounts":                     token.ScopeAdmin,
+	"POST /admin/accounts/:id/freeze":         token.ScopeAdmin,
+	"PUT /admin/accounts/:id/overdraft":       token.ScopeAdmin,
+	"POST /admin/accounts/:id/adjustments":    token.ScopeAdmin,
+	"GET /admin/accounts/:id/adjustments":     token.ScopeAdmin,
+	"GET /admin/transfers":                    token.ScopeAdmin,
+	"GET /admin/users/:username/history":      token.ScopeAdmin,
+	"GET /admin/accounts/:id/history":         token.ScopeAdmin,
+	"POST /admin/accounts/:id/unfreeze":       token.ScopeAdmin,
+	"POST /admin/history/:id/revert":          token.ScopeAdmin,
+	"POST /admin/impersonations":              token.ScopeAdmin,
+	"GET /admin/users/:username/audit-logs":   token.ScopeAdmin,
+	"GET /admin/audit-logs":                   token.ScopeAdmin,
+	"GET /admin/users/:username/limits":       token.ScopeAdmin,
+	"PUT /admin/users/:username/limits":       token.ScopeAdmin,
+	"GET /admin/kyc/documents":                token.ScopeAdmin,
+	"GET /admin/kyc/documents/:id/file":       token.ScopeAdmin,
+	"POST /admin/kyc/documents/:id/review":    token.ScopeAdmin,
+	"POST /admin/periods":                     token.ScopeAdmin,
+	"GET /admin/periods":                      token.ScopeAdmin,
+	"GET /admin/periods/:period/report":       token.ScopeAdmin,
+	"POST /admin/periods/:period/adjustments": token.ScopeAdmin,
+	"GET /admin/fx_rates":                     token.ScopeAdmin,
+	"POST /admin/fx_rates":                    token.ScopeAdmin,
+	"GET /admin/settings":                     token.ScopeAdmin,
+	"PUT /admin/settings":                     token.ScopeAdmin,
+	"DELETE /admin/settings/:id":              token.ScopeAdmin,
+	"GET /admin/retention/rules":              token.ScopeAdmin,
+	"PUT /admin/retention/rules":              token.ScopeAdmin,
+	"POST /admin/retention/runs":              token.ScopeAdmin,
+	"GET /admin/retention/runs":               token.ScopeAdmin,
+	"GET /admin/retention/runs/:id":           token.ScopeAdmin,
+}
+
+func routeScopeKey(method, fullPath string) string {
+	return method + " " + strings.TrimPrefix(fullPath, "/api")
+}
+
+// scopeMiddleware rejects tokens that lack the scope of the matched route. It
+// must run after authMiddleware.
+func scopeMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		scope, ok := routeScopes[routeScopeKey(ctx.Request.Method, ctx.FullPath())]
+		if !ok {
+			ctx.AbortWithStatusJSON(errorResponse(http.StatusForbidden, errors.New("route has no scope assigned")))
+			return
+		}
+
+		if !authPayload.HasScope(scope) {
+			ctx.AbortWithStatusJSON(errorResponse(http.StatusForbidden, fmt.Errorf("token is missing scope %s", scope)))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// isDelegable reports whether a token minted for someone else, a scoped
+// token or an impersonation, may carry scope. One that could mint tokens or
+// reach admin routes would let its holder outlive the token it came from.
+func isDelegable(scope string) bool {
+	return scope != token.ScopeAdmin && scope != token.ScopeTokensWrite
+}
+
+type createTokenRequest struct {
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	DurationHours int32    `json:"duration_hours" binding:"required,min=1,max=720"`
+}
+
+type createTokenResponse struct {
+	AccessToken string    `json:"access_token"`
+	Scopes      []string  `json:"scopes"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// createScopedToken issues a token limited to some scopes, e.g. a read-only
+// token for a dashboard. A token can never grant more than it holds itself,
+// nor outlive it. Scoped tokens are not device bound since they are meant for
+// other systems.
+func (server *Server) createScopedToken(ctx *gin.Context) {
+	var req createTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	for _, scope := range req.Scopes {
+		if !token.IsValidScope(scope) {
+			ctx.JSON(errorResponse(http.StatusBadRequest, fmt.Errorf("unknown scope %s", scope)))
+			return
+		}
+		if !isDelegable(scope) {
+			ctx.JSON(errorResponse(http.StatusForbidden, fmt.Errorf("scope %s cannot be granted to a scoped token", scope)))
+			return
+		}
+		if !authPayload.HasScope(scope) {
+			ctx.JSON(errorResponse(http.StatusForbidden, fmt.Errorf("token is missing scope %s", scope)))
+			return
+		}
+	}
+
+	// Suspended users keep the tokens they have until they expire, but must not
+	// be able to mint new ones from them.
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if user.Status == db.UserSuspended {
+		ctx.JSON(errorResponse(http.StatusForbidden, errUserSuspended))
+		return
+	}
+
+	duration := time.Duration(req.DurationHours) * time.Hour
+	if remaining := time.Until(authPayload.ExpiredAt); duration > remaining {
+		duration = remaining
+	}
+	accessToken, err := server.tokenMaker.CreateToken(authPayload.Username, duration, token.WithScopes(req.Scopes...))
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createTokenResponse{
+		AccessToken: accessToken,
+		Scopes:      req.Scopes,
+		ExpiresAt:   time.Now().Add(duration),
+	})
+}
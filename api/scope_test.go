@@ -0,0 +1,238 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEveryAuthRouteHasScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	public := map[string]bool{
+		"POST /users":       true,
+		"POST /users/login": true,
+		"GET /status":       true,
+		"GET /currencies":   true,
+		"GET /healthz":      true,
+		"GET /readyz":       true,
+		"GET /swagger":      true,
+
+		"POST /users/webauthn/login/begin":  true,
+		"POST /users/webauthn/login/finish": true,
+	}
+
+	for _, route := range server.router.Routes() {
+		key := routeScopeKey(route.Method, route.Path)
+		// Static UI files are served without authentication.
+		if public[key] || route.Path == "/" || path.Ext(route.Path) != "" {
+			continue
+		}
+		_, ok := routeScopes[key]
+		require.True(t, ok, "route %s has no scope", key)
+	}
+}
+
+func TestScopeMiddleware(t *testing.T) {
+	account := randomAccount()
+
+	testCases := []struct {
+		name          string
+		scopes        []string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "ReadScope",
+			scopes: []string{token.ScopeAccountsRead},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().ListPots(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingScope",
+			scopes: []string{token.ScopeTransfersRead},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/accounts/"+db.AccountEntityID(account.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, account.Owner, time.Minute, token.WithScopes(tc.scopes...))
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateScopedTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	suspended := user
+	suspended.Status = db.UserSuspended
+
+	testCases := []struct {
+		name          string
+		tokenScopes   []string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			tokenScopes: token.AllScopes(),
+			body:        gin.H{"scopes": []string{token.ScopeAccountsRead}, "duration_hours": 24},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp createTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+
+				payload, err := server.tokenMaker.VerifyToken(rsp.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, payload.Username)
+				require.Equal(t, []string{token.ScopeAccountsRead}, payload.Scopes)
+				// It expires with the token that made it.
+				require.WithinDuration(t, time.Now().Add(time.Minute), payload.ExpiredAt, time.Second)
+				require.WithinDuration(t, payload.ExpiredAt, rsp.ExpiresAt, time.Second)
+			},
+		},
+		{
+			name:        "TokensWriteScope",
+			tokenScopes: token.AllScopes(),
+			body:        gin.H{"scopes": []string{token.ScopeAccountsRead, token.ScopeTokensWrite}, "duration_hours": 24},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "AdminScope",
+			tokenScopes: token.AllScopes(),
+			body:        gin.H{"scopes": []string{token.ScopeAdmin}, "duration_hours": 24},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "UnknownScope",
+			tokenScopes: token.AllScopes(),
+			body:        gin.H{"scopes": []string{"everything"}, "duration_hours": 24},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "Escalation",
+			tokenScopes: []string{token.ScopeTokensWrite, token.ScopeAccountsRead},
+			body:        gin.H{"scopes": []string{token.ScopeTransfersWrite}, "duration_hours": 24},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "NoTokensScope",
+			tokenScopes: []string{token.ScopeAccountsRead},
+			body:        gin.H{"scopes": []string{token.ScopeAccountsRead}, "duration_hours": 24},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "Suspended",
+			tokenScopes: token.AllScopes(),
+			body:        gin.H{"scopes": []string{token.ScopeAccountsRead}, "duration_hours": 24},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(suspended, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/tokens", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute, token.WithScopes(tc.tokenScopes...))
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
@@ -1,12 +1,27 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"time"
 
 	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/events"
+	"github.com/ankurdas111111/simplebank/fx"
+	"github.com/ankurdas111111/simplebank/limits"
+	"github.com/ankurdas111111/simplebank/logging"
+	"github.com/ankurdas111111/simplebank/metrics"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/settings"
+	"github.com/ankurdas111111/simplebank/sms"
 	"github.com/ankurdas111111/simplebank/token"
 	"github.com/ankurdas111111/simplebank/util"
+	"github.com/ankurdas111111/simplebank/webauthn"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
@@ -16,60 +31,234 @@ type Server struct {
 	config util.Config
 	store db.Store
 	tokenMaker token.Maker
+	settings *settings.Resolver
+	rates *fx.Converter
+	limitEngine *limits.Engine
+	// nil while passkeys are not configured
+	relyingParty *webauthn.RelyingParty
+	status statusCache
+	events *events.Broker
+	notifications *notify.Hub
+	// nil while security alerts are not texted
+	sms sms.Sender
+	// nil while rate limiting is disabled
+	rateLimiter *rateLimiter
+	// closed when the server starts shutting down, to end streams that
+	// would otherwise keep it waiting
+	shutdown chan struct{}
+	// logs requests and what goes wrong handling them
+	logger *slog.Logger
+	// nil while requests are not measured
+	httpMetrics *metrics.HTTPMetrics
+	// nil while requests are not traced
+	tracer db.Tracer
+	// resolves the hosts of webhook URLs, to check they are public
+	lookupIP func(ctx context.Context, host string) ([]net.IP, error)
 	router *gin.Engine
 }
 
-func NewServer(config util.Config, store db.Store) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
-	// tokenMaker, err := token.NewJWTMaker(config.TokenSymmetricKey)
+// ServerOption configures optional Server behaviour.
+type ServerOption func(server *Server)
+
+// WithEventBroker streams the account events published to broker. Without it
+// the server has a broker of its own that nothing publishes to.
+func WithEventBroker(broker *events.Broker) ServerOption {
+	return func(server *Server) {
+		server.events = broker
+	}
+}
+
+// WithNotificationHub sends the notifications of hub to WebSocket clients.
+// Without it the server has a hub of its own that only the API notifies.
+func WithNotificationHub(hub *notify.Hub) ServerOption {
+	return func(server *Server) {
+		server.notifications = hub
+	}
+}
+
+// WithLogger logs through logger. Without it the server logs to stdout at
+// the level of LOG_LEVEL.
+func WithLogger(logger *slog.Logger) ServerOption {
+	return func(server *Server) {
+		server.logger = logger
+	}
+}
+
+// WithHTTPMetrics records the count, duration and status of every request,
+// and how many are in flight, in httpMetrics.
+func WithHTTPMetrics(httpMetrics *metrics.HTTPMetrics) ServerOption {
+	return func(server *Server) {
+		server.httpMetrics = httpMetrics
+	}
+}
+
+// WithTracer starts a span for every request, continuing the trace of the
+// caller when it sends a W3C traceparent header. Give the store the same
+// tracer for its calls to be children of the request.
+func WithTracer(tracer db.Tracer) ServerOption {
+	return func(server *Server) {
+		server.tracer = tracer
+	}
+}
+
+// WithSMSSender texts security alerts to users with a phone number.
+func WithSMSSender(sender sms.Sender) ServerOption {
+	return func(server *Server) {
+		server.sms = sender
+	}
+}
+
+func NewServer(config util.Config, store db.Store, opts ...ServerOption) (*Server, error) {
+	tokenMaker, err := newTokenMaker(config)
 	if err != nil{
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
+	if config.PasswordHashAlgorithm == "" {
+		config.PasswordHashAlgorithm = util.DefaultPasswordAlgorithm
+	}
+	if !util.IsPasswordAlgorithm(config.PasswordHashAlgorithm) {
+		return nil, fmt.Errorf("%w: %s", util.ErrUnknownPasswordAlgorithm, config.PasswordHashAlgorithm)
+	}
+	if config.StatementEmailLimit <= 0 {
+		config.StatementEmailLimit = defaultStatementEmailLimit
+	}
+	if config.StatementEmailWindow <= 0 {
+		config.StatementEmailWindow = defaultStatementEmailWindow
+	}
+	logger, err := logging.New(os.Stdout, config.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = defaultShutdownTimeout
+	}
+	resolver := settings.NewResolver(store)
+	rates := fx.NewConverter(store)
 	server := &Server{
 		config: config,
 		store: store,
 		tokenMaker: tokenMaker,
+		settings: resolver,
+		rates: rates,
+		limitEngine: limits.NewEngine(resolver, rates),
+		events: events.NewBroker(),
+		notifications: notify.NewHub(config.LowBalanceThreshold),
+		shutdown: make(chan struct{}),
+		logger: logger,
+		lookupIP: func(ctx context.Context, host string) ([]net.IP, error) {
+			return net.DefaultResolver.LookupIP(ctx, "ip", host)
+		},
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	if config.RateLimitRequests > 0 {
+		server.rateLimiter = newRateLimiter(config.RateLimitRequests, config.RateLimitWindow)
+	}
+	
+	if config.WebAuthnRPID != "" {
+		server.relyingParty = &webauthn.RelyingParty{
+			ID: config.WebAuthnRPID,
+			Name: config.WebAuthnRPName,
+			Origin: config.WebAuthnOrigin,
+		}
+		if server.relyingParty.Name == "" {
+			server.relyingParty.Name = "SimpleBank"
+		}
 	}
 	
 	if v,ok := binding.Validator.Engine().(*validator.Validate); ok{
 		v.RegisterValidation("currency",validCurrency)
+		v.RegisterValidation("password", newPasswordValidator(config.PasswordPolicy()))
+		v.RegisterTagNameFunc(requestFieldName)
 	}
 
 	server.setupRouter()
 	return server, nil
 }
 
+// newTokenMaker uses the asymmetric maker when a private key is configured,
+// so other services can verify tokens, and the PASETO maker otherwise.
+func newTokenMaker(config util.Config) (token.Maker, error) {
+	opts := []token.MakerOption{
+		token.WithIssuer(config.TokenIssuer),
+		token.WithAudience(config.TokenAudience),
+	}
+
+	if config.TokenPrivateKeyFile != "" {
+		privateKeyPEM, err := os.ReadFile(config.TokenPrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return token.NewAsymmetricJWTMaker(privateKeyPEM, opts...)
+	}
+
+	return token.NewPasetoMaker(config.TokenSymmetricKey, opts...)
+	// return token.NewJWTMaker(config.TokenSymmetricKey, opts...)
+}
+
 func (server *Server) setupRouter() {
-	router := gin.Default()
+	router := gin.New()
+	if server.httpMetrics != nil {
+		router.Use(server.measureRequests())
+	}
+	if server.tracer != nil {
+		router.Use(server.traceRequests())
+	}
+	// The logger comes after localizeErrorsMiddleware to log error messages
+	// in English, and before gin.Recovery to log the 500 of a panic.
+	router.Use(requestIDMiddleware(), localizeErrorsMiddleware(), server.logRequests(), gin.Recovery())
+
+	// Public routes are limited by client IP, like login.
+
+	// Public keys for services that verify our tokens themselves.
+	if keySet, ok := server.tokenMaker.(token.KeySetProvider); ok {
+		router.GET("/.well-known/jwks.json", server.rateLimit(), getJWKS(keySet))
+	}
+
+	// Public status page feed, for uptime monitors and the status page.
+	router.GET("/status", server.rateLimit(), server.getStatus)
+	router.GET("/api/status", server.rateLimit(), server.getStatus)
+
+	// Currencies for pickers, needed before the user has logged in.
+	router.GET("/currencies", server.rateLimit(), server.listCurrencies)
+	router.GET("/api/currencies", server.rateLimit(), server.listCurrencies)
+
+	// Probes for the orchestrator and load balancer.
+	router.GET("/healthz", server.rateLimit(), server.healthz)
+	router.GET("/readyz", server.rateLimit(), server.readyz)
 
 	// API (preferred): /api/*
 	apiRoutes := router.Group("/api")
-	apiRoutes.POST("/users", server.createUser)
-	apiRoutes.POST("/users/login", server.loginUser)
+	apiRoutes.POST("/users", server.rateLimit(), server.createUser)
+	apiRoutes.POST("/users/login", server.rateLimit(), server.loginUser)
 
 	// Backward-compatible routes (older clients): keep these too.
-	router.POST("/users", server.createUser)
-	router.POST("/users/login", server.loginUser)
+	router.POST("/users", server.rateLimit(), server.createUser)
+	router.POST("/users/login", server.rateLimit(), server.loginUser)
 
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
-	apiAuthRoutes := router.Group("/api").Use(authMiddleware(server.tokenMaker))
+	if server.relyingParty != nil {
+		for _, routes := range []gin.IRoutes{router, apiRoutes} {
+			routes.POST("/users/webauthn/login/begin", server.rateLimit(), server.beginPasskeyLogin)
+			routes.POST("/users/webauthn/login/finish", server.rateLimit(), server.finishPasskeyLogin)
+		}
+	}
 
-	authRoutes.POST("/accounts", server.createAccount)
-	authRoutes.GET("/accounts/:id", server.getAccount)
-	authRoutes.GET("/accounts", server.listAccount)
-	authRoutes.POST("/accounts/:id/deposit", server.deposit)
-	authRoutes.GET("/accounts/:id/lookup", server.lookupAccount)
+	// Browsers can't set headers on a WebSocket handshake, so the token may
+	// also come in the query string.
+	for _, routes := range []gin.IRoutes{router, apiRoutes} {
+		routes.GET("/ws", webSocketTokenMiddleware(), authMiddleware(server.tokenMaker), server.rateLimit(), scopeMiddleware(), auditMiddleware(server.store), server.serveNotifications)
+	}
 
-	authRoutes.POST("/transfers", server.createTransfer)
-	authRoutes.GET("/transfers", server.listTransfers)
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), server.rateLimit(), scopeMiddleware(), auditMiddleware(server.store))
+	apiAuthRoutes := router.Group("/api").Use(authMiddleware(server.tokenMaker), server.rateLimit(), scopeMiddleware(), auditMiddleware(server.store))
+	server.addAuthRoutes(authRoutes)
+	server.addAuthRoutes(apiAuthRoutes)
 
-	apiAuthRoutes.POST("/accounts", server.createAccount)
-	apiAuthRoutes.GET("/accounts/:id", server.getAccount)
-	apiAuthRoutes.GET("/accounts", server.listAccount)
-	apiAuthRoutes.POST("/accounts/:id/deposit", server.deposit)
-	apiAuthRoutes.GET("/accounts/:id/lookup", server.lookupAccount)
-	apiAuthRoutes.POST("/transfers", server.createTransfer)
-	apiAuthRoutes.GET("/transfers", server.listTransfers)
+	adminRoutes := router.Group("/admin").Use(authMiddleware(server.tokenMaker), server.rateLimit(), scopeMiddleware(), auditMiddleware(server.store), adminMiddleware(server.store))
+	apiAdminRoutes := router.Group("/api/admin").Use(authMiddleware(server.tokenMaker), server.rateLimit(), scopeMiddleware(), auditMiddleware(server.store), adminMiddleware(server.store))
+	server.addAdminRoutes(adminRoutes)
+	server.addAdminRoutes(apiAdminRoutes)
 
 	// UI (served by backend for single-service deploy)
 	router.GET("/", func(ctx *gin.Context) { ctx.File("./web/index.html") })
@@ -82,14 +271,181 @@ func (server *Server) setupRouter() {
 	router.GET("/styles.css", func(ctx *gin.Context) { ctx.File("./web/styles.css") })
 	router.GET("/favicon.ico", func(ctx *gin.Context) { ctx.Status(http.StatusNoContent) })
 
+	// API documentation, generated from the routes above.
+	serveOpenAPI(router)
+
 	server.router = router
 }
 
-// start runs the server on a specific address 
-func (server *Server) Start(address string) error{
-	return server.router.Run(address) 
+// addAuthRoutes registers the routes that require an authenticated user. They
+// are mounted both at the root (older clients) and under /api.
+func (server *Server) addAuthRoutes(routes gin.IRoutes) {
+	routes.POST("/accounts", server.createAccount)
+	routes.GET("/accounts/:id", server.getAccount)
+	routes.GET("/accounts", server.listAccount)
+	routes.POST("/accounts/:id/deposit", server.idempotent(), server.deposit)
+	routes.POST("/deposits/external", server.createExternalDeposit)
+	routes.POST("/accounts/:id/withdraw", server.idempotent(), server.withdraw)
+	routes.POST("/accounts/:id/freeze", server.freezeAccount)
+	routes.DELETE("/accounts/:id", server.closeAccount)
+	routes.GET("/accounts/:id/entries", server.listEntries)
+	routes.GET("/accounts/:id/activity", server.listAccountActivity)
+	routes.GET("/accounts/:id/summary", server.getAccountSummary)
+	routes.GET("/accounts/:id/payment_qr", server.getPaymentQR)
+	routes.GET("/accounts/:id/payment_qr.png", server.getPaymentQRImage)
+	routes.GET("/accounts/:id/balance_history", server.getBalanceHistory)
+	routes.GET("/accounts/:id/lookup", server.lookupAccount)
+	routes.POST("/accounts/:id/statement/email", server.emailStatement)
+	routes.GET("/accounts/:id/statement.pdf", server.getStatementPDF)
+	routes.GET("/accounts/:id/events", server.streamAccountEvents)
+	routes.POST("/accounts/:id/pots", server.createPot)
+	routes.POST("/accounts/:id/pots/moves", server.idempotent(), server.movePotMoney)
+	routes.GET("/accounts/:id/interest", server.getAccruedInterest)
+
+	routes.GET("/fx/quote", server.getFXQuote)
+	routes.POST("/transfers", server.createTransfer)
+	routes.GET("/transfers", server.listTransfers)
+	routes.POST("/transfers/batch", server.createBatchTransfer)
+	routes.GET("/transfers/export", server.exportTransfers)
+	routes.POST("/transfers/scheduled", server.createScheduledTransfer)
+	routes.GET("/transfers/scheduled", server.listScheduledTransfers)
+	routes.GET("/transfers/scheduled/:id", server.getScheduledTransfer)
+	routes.DELETE("/transfers/scheduled/:id", server.cancelScheduledTransfer)
+	routes.POST("/transfers/:id/tags", server.tagTransfer)
+	routes.GET("/transfers/:id/tags", server.listTransferTags)
+	routes.DELETE("/transfers/:id/tags/:tag", server.untagTransfer)
+
+	routes.POST("/entries/:id/tags", server.tagEntry)
+	routes.GET("/entries/:id/tags", server.listEntryTags)
+	routes.DELETE("/entries/:id/tags/:tag", server.untagEntry)
+	routes.GET("/tags/spending", server.listTagSpending)
+
+	routes.POST("/beneficiaries", server.createBeneficiary)
+	routes.GET("/beneficiaries", server.listBeneficiaries)
+	routes.DELETE("/beneficiaries/:id", server.deleteBeneficiary)
+
+	routes.POST("/payment-requests", server.createPaymentRequest)
+	routes.GET("/payment-requests", server.listPaymentRequests)
+	routes.GET("/payment-requests/:id", server.getPaymentRequest)
+	routes.POST("/payment-requests/:id/accept", server.idempotent(), server.acceptPaymentRequest)
+	routes.POST("/payment-requests/:id/decline", server.declinePaymentRequest)
+
+	routes.POST("/webhooks", server.createWebhook)
+	routes.GET("/webhooks", server.listWebhooks)
+	routes.DELETE("/webhooks/:id", server.deleteWebhook)
+	routes.GET("/webhooks/:id/deliveries", server.listWebhookDeliveries)
+
+	routes.POST("/kyc/documents", server.uploadKycDocument)
+	routes.GET("/kyc/documents", server.listKycDocuments)
+
+	routes.POST("/tokens", server.createScopedToken)
+	routes.POST("/users/elevate", server.elevateToken)
+	routes.POST("/users/totp", server.enrollTotp)
+	routes.GET("/users/me", server.getCurrentUser)
+	routes.GET("/users/me/limits", server.listMyTransferLimits)
+	routes.GET("/users/me/insights/counterparties", server.listCounterpartyInsights)
+	routes.GET("/users/me/notification-preferences", server.listNotificationPreferences)
+	routes.GET("/notifications", server.listNotifications)
+	routes.PUT("/users/me/notification-preferences", server.updateNotificationPreferences)
+	routes.PATCH("/users/:username", server.updateUser)
+
+	if server.relyingParty != nil {
+		routes.POST("/users/webauthn/register/begin", server.beginPasskeyRegistration)
+		routes.POST("/users/webauthn/register/finish", server.finishPasskeyRegistration)
+	}
 }
 
-func errorResponse(err error) gin.H{
-	return gin.H{"error": err.Error()}
+// addAdminRoutes registers the routes restricted to admins.
+func (server *Server) addAdminRoutes(routes gin.IRoutes) {
+	routes.GET("/users", server.searchUsers)
+	routes.POST("/users/:username/suspend", server.suspendUser)
+	routes.POST("/users/:username/reactivate", server.reactivateUser)
+	routes.GET("/accounts", server.searchAccounts)
+	routes.POST("/accounts/:id/freeze", server.adminFreezeAccount)
+	routes.PUT("/accounts/:id/overdraft", server.setOverdraftLimit)
+	routes.POST("/accounts/:id/adjustments", server.adjustBalance)
+	routes.GET("/accounts/:id/adjustments", server.listBalanceAdjustments)
+	routes.GET("/transfers", server.searchTransfers)
+
+	routes.GET("/users/:username/history", server.listUserHistory)
+	routes.GET("/accounts/:id/history", server.listAccountHistory)
+	routes.POST("/accounts/:id/unfreeze", server.unfreezeAccount)
+	routes.POST("/history/:id/revert", server.revertStandingDataChange)
+
+	routes.POST("/impersonations", server.createImpersonation)
+	routes.GET("/users/:username/audit-logs", server.listAuditLogs)
+	routes.GET("/audit-logs", server.searchAuditLogs)
+	routes.GET("/users/:username/limits", server.listTransferLimits)
+	routes.PUT("/users/:username/limits", server.upsertTransferLimit)
+
+	routes.GET("/kyc/documents", server.listPendingKycDocuments)
+	routes.GET("/kyc/documents/:id/file", server.getKycDocumentFile)
+	routes.POST("/kyc/documents/:id/review", server.reviewKycDocument)
+
+	routes.POST("/periods", server.closePeriod)
+	routes.GET("/periods", server.listPeriods)
+	routes.GET("/periods/:period/report", server.getPeriodReport)
+	routes.POST("/periods/:period/adjustments", server.postAdjustment)
+
+	routes.GET("/fx_rates", server.listFxRateHistory)
+	routes.POST("/fx_rates", server.createFxRate)
+
+	routes.GET("/settings", server.listSettings)
+	routes.PUT("/settings", server.upsertSetting)
+	routes.DELETE("/settings/:id", server.deleteSetting)
+
+	routes.GET("/retention/rules", server.listRetentionRules)
+	routes.PUT("/retention/rules", server.upsertRetentionRule)
+	routes.POST("/retention/runs", server.runRetention)
+	routes.GET("/retention/runs", server.listRetentionRuns)
+	routes.GET("/retention/runs/:id", server.getRetentionRun)
+}
+
+// Handler exposes the router, e.g. to serve it from httptest in end-to-end tests.
+func (server *Server) Handler() http.Handler {
+	return server.router
+}
+
+// defaultShutdownTimeout is how long in-flight requests may take to finish
+// when SHUTDOWN_TIMEOUT isn't set.
+const defaultShutdownTimeout = 30 * time.Second
+
+// Start serves on address until ctx is done, then shuts down gracefully.
+func (server *Server) Start(ctx context.Context, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return server.serve(ctx, listener)
+}
+
+// serve stops accepting connections once ctx is done and waits up to the
+// shutdown timeout for in-flight requests to finish, then closes the
+// connections of those that haven't.
+func (server *Server) serve(ctx context.Context, listener net.Listener) error {
+	httpServer := &http.Server{Handler: server.router}
+	httpServer.RegisterOnShutdown(func() { close(server.shutdown) })
+
+	served := make(chan error, 1)
+	go func() {
+		served <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	server.logger.Info("shutting down, waiting for in-flight requests", "timeout", server.config.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), server.config.ShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		server.logger.Warn("requests still in flight after the shutdown timeout, closing their connections", "timeout", server.config.ShutdownTimeout)
+		return httpServer.Close()
+	}
+	return nil
 }
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// startTestServer serves server on a free port until ctx is done. It returns
+// the base URL and the error serve returned.
+func startTestServer(t *testing.T, ctx context.Context, server *Server) (string, <-chan error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- server.serve(ctx, listener)
+	}()
+	return "http://" + listener.Addr().String(), stopped
+}
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server.router.GET("/slow", func(ctx *gin.Context) {
+		close(started)
+		<-release
+		ctx.String(http.StatusOK, "done")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	url, stopped := startTestServer(t, ctx, server)
+
+	responses := make(chan *http.Response, 1)
+	go func() {
+		rsp, err := http.Get(url + "/slow")
+		if err != nil {
+			close(responses)
+			return
+		}
+		responses <- rsp
+	}()
+	<-started
+
+	cancel()
+	select {
+	case err := <-stopped:
+		require.FailNow(t, "server stopped with a request in flight", "%v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	// New connections are refused while the request drains.
+	_, err := http.Get(url + "/slow")
+	require.Error(t, err)
+
+	close(release)
+	rsp, ok := <-responses
+	require.True(t, ok)
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(rsp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+	require.Equal(t, "done", string(body))
+	require.NoError(t, <-stopped)
+}
+
+func TestServeClosesRequestsAfterShutdownTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	server.config.ShutdownTimeout = 50 * time.Millisecond
+	started := make(chan struct{})
+	server.router.GET("/stuck", func(ctx *gin.Context) {
+		close(started)
+		<-ctx.Request.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	url, stopped := startTestServer(t, ctx, server)
+
+	failed := make(chan error, 1)
+	go func() {
+		rsp, err := http.Get(url + "/stuck")
+		if err == nil {
+			rsp.Body.Close()
+		}
+		failed <- err
+	}()
+	<-started
+
+	cancel()
+	select {
+	case err := <-stopped:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		require.FailNow(t, "server didn't stop after the shutdown timeout")
+	}
+	require.Error(t, <-failed)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/settings"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// listSettings returns every stored override.
+func (server *Server) listSettings(ctx *gin.Context) {
+	rows, err := server.store.ListSettings(ctx)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rows)
+}
+
+type upsertSettingRequest struct {
+	Tenant   string `json:"tenant"`
+	Currency string `json:"currency" binding:"omitempty,currency"`
+	Key      string `json:"key" binding:"required"`
+	Value    string `json:"value" binding:"required"`
+}
+
+// upsertSetting sets an override. Leave tenant or currency empty to apply it
+// to all of them.
+func (server *Server) upsertSetting(ctx *gin.Context) {
+	var req upsertSettingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := settings.Validate(req.Key, req.Value); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	setting, err := server.store.UpsertSetting(ctx, db.UpsertSettingParams{
+		Tenant:    req.Tenant,
+		Currency:  req.Currency,
+		Key:       req.Key,
+		Value:     req.Value,
+		UpdatedBy: authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	server.settings.Invalidate()
+	ctx.JSON(http.StatusOK, setting)
+}
+
+type deleteSettingRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// deleteSetting removes an override so the next layer applies again.
+func (server *Server) deleteSetting(ctx *gin.Context) {
+	var req deleteSettingRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := server.store.DeleteSetting(ctx, req.ID); err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	server.settings.Invalidate()
+	ctx.Status(http.StatusNoContent)
+}
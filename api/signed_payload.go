@@ -0,0 +1,70 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var (
+	errSigningDisabled = errors.New("fx quotes and payment codes need TOKEN_SYMMETRIC_KEY to be set")
+	errBadSignature    = errors.New("invalid signed payload")
+)
+
+// signPayload encodes v as base64 JSON followed by its HMAC, so the server
+// doesn't need to store what it hands out and any instance can check it.
+// The purpose keeps a payload signed for one use from passing for another.
+func (server *Server) signPayload(purpose string, v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	mac, err := server.payloadMAC(purpose, payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac), nil
+}
+
+// verifyPayload decodes a payload from signPayload into v. Anything that
+// wasn't signed by us for this purpose is errBadSignature.
+func (server *Server) verifyPayload(purpose, signed string, v any) error {
+	encodedPayload, encodedMAC, ok := strings.Cut(signed, ".")
+	if !ok {
+		return errBadSignature
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return errBadSignature
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil {
+		return errBadSignature
+	}
+
+	want, err := server.payloadMAC(purpose, payload)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(mac, want) {
+		return errBadSignature
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return errBadSignature
+	}
+	return nil
+}
+
+// payloadMAC keys payloads with the token key, which every instance shares.
+func (server *Server) payloadMAC(purpose string, payload []byte) ([]byte, error) {
+	if server.config.TokenSymmetricKey == "" {
+		return nil, errSigningDisabled
+	}
+	mac := hmac.New(sha256.New, []byte(server.config.TokenSymmetricKey))
+	mac.Write([]byte(purpose + "."))
+	mac.Write(payload)
+	return mac.Sum(nil), nil
+}
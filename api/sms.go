@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/sms"
+	"github.com/ankurdas111111/simplebank/util"
+)
+
+// smsTimeout bounds how long sending one alert may take.
+const smsTimeout = 10 * time.Second
+
+// textUser texts an alert of the given notification type to the user's
+// phone number, if they have one and haven't turned the alert off. It sends
+// in the background so the provider never slows the request down. The alert
+// is recorded in the notifications table first, and a failed send is left
+// pending there for the notification worker to retry.
+func (server *Server) textUser(user db.User, kind string, body string) {
+	if server.sms == nil || user.PhoneNumber == "" {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), smsTimeout)
+		defer cancel()
+		if !server.notificationEnabled(ctx, user.Username, notify.ChannelSMS, kind) {
+			return
+		}
+
+		msg := sms.Message{To: user.PhoneNumber, Body: body}
+		notification, recorded := server.recordText(ctx, user.Username, kind, msg)
+		sendErr := server.sms.Send(ctx, msg)
+		if sendErr != nil {
+			server.logger.Warn("cannot text alert", "user", user.Username, "type", kind, "error", sendErr)
+		}
+		if !recorded {
+			return
+		}
+
+		// The send may have used up ctx.
+		ctx = context.WithoutCancel(ctx)
+		var err error
+		if sendErr == nil {
+			err = server.store.MarkNotificationSent(ctx, notification.ID)
+		} else {
+			err = server.store.MarkNotificationFailed(ctx, db.MarkNotificationFailedParams{
+				Status:    db.NotificationPending,
+				LastError: sql.NullString{String: sendErr.Error(), Valid: true},
+				RetryAt:   time.Now(),
+				ID:        notification.ID,
+			})
+		}
+		if err != nil {
+			server.logger.Error("cannot record delivery of notification", "notification_id", notification.ID, "error", err)
+		}
+	}()
+}
+
+// recordText records a text alert about to be sent. It counts as the first
+// attempt, and stays locked past the send so the worker doesn't pick it up
+// meanwhile. The alert is sent even when it can't be recorded.
+func (server *Server) recordText(ctx context.Context, username, kind string, msg sms.Message) (db.Notification, bool) {
+	payload, err := json.Marshal(db.SMSNotificationPayload{To: msg.To, Body: msg.Body})
+	if err == nil {
+		var notification db.Notification
+		notification, err = server.store.CreateNotification(ctx, db.CreateNotificationParams{
+			Username:    username,
+			Channel:     notify.ChannelSMS,
+			Type:        kind,
+			Payload:     payload,
+			Status:      db.NotificationPending,
+			Attempts:    1,
+			LockedUntil: time.Now().Add(2 * smsTimeout),
+		})
+		if err == nil {
+			return notification, true
+		}
+	}
+	server.logger.Error("cannot record text alert", "user", username, "type", kind, "error", err)
+	return db.Notification{}, false
+}
+
+func (server *Server) textPasswordChanged(user db.User) {
+	server.textUser(user, notify.SecurityAlert, fmt.Sprintf(
+		"SimpleBank: the password of %s was changed at %s. If this wasn't you, contact us immediately.",
+		user.Username, user.PasswordChangedAt.UTC().Format("2006-01-02 15:04 MST"),
+	))
+}
+
+// textLargeTransfer alerts the sender of a transfer worth at least
+// SMSTransferThreshold in INR.
+func (server *Server) textLargeTransfer(ctx context.Context, user db.User, transfer db.Transfer, currency string) {
+	threshold := server.config.SMSTransferThreshold
+	if threshold <= 0 {
+		return
+	}
+	amountINR, _, err := server.rates.Convert(ctx, transfer.Amount, currency, util.INR)
+	if err == nil && amountINR < threshold {
+		return
+	}
+	server.textUser(user, notify.LargeTransfer, fmt.Sprintf(
+		"SimpleBank: %d %s was sent from account %d to account %d. If this wasn't you, contact us immediately.",
+		transfer.Amount, currency, transfer.FromAccountID, transfer.ToAccountID,
+	))
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/sms"
+	mocksms "github.com/ankurdas111111/simplebank/sms/mock"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// expectText returns a channel that receives the message once the mock
+// sender was called and the alert recorded as sent. Alerts are sent in the
+// background.
+func expectText(store *mockdb.MockStore, sender *mocksms.MockSender) <-chan sms.Message {
+	const notificationID = 11
+	var msg sms.Message
+	sent := make(chan sms.Message, 1)
+	store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateNotificationParams) (db.Notification, error) {
+			return db.Notification{ID: notificationID, Channel: arg.Channel, Payload: arg.Payload, Attempts: arg.Attempts}, nil
+		})
+	sender.EXPECT().Send(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, m sms.Message) error {
+			msg = m
+			return nil
+		})
+	store.EXPECT().MarkNotificationSent(gomock.Any(), gomock.Eq(int64(notificationID))).
+		Times(1).
+		DoAndReturn(func(context.Context, int64) error {
+			sent <- msg
+			return nil
+		})
+	return sent
+}
+
+func receiveText(t *testing.T, sent <-chan sms.Message) sms.Message {
+	select {
+	case msg := <-sent:
+		return msg
+	case <-time.After(time.Second):
+		require.FailNow(t, "no text message sent")
+		return sms.Message{}
+	}
+}
+
+func TestPasswordChangeTextsUser(t *testing.T) {
+	user, password := randomUser(t)
+	user.PhoneNumber = "+15551234567"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().UpdateUserTx(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+	store.EXPECT().GetNotificationPreference(gomock.Any(), gomock.Eq(db.GetNotificationPreferenceParams{
+		Username:  user.Username,
+		Channel:   notify.ChannelSMS,
+		EventType: notify.SecurityAlert,
+	})).
+		Times(1).
+		Return(db.NotificationPreference{}, sql.ErrNoRows)
+
+	sender := mocksms.NewMockSender(ctrl)
+	sent := expectText(store, sender)
+
+	server := newTestServer(t, store)
+	WithSMSSender(sender)(server)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"password": "Fresh-passphrase-" + util.RandomString(6), "current_password": password})
+	require.NoError(t, err)
+
+	url := fmt.Sprintf("/api/users/%s", user.Username)
+	request, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(data))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	msg := receiveText(t, sent)
+	require.Equal(t, user.PhoneNumber, msg.To)
+	require.Contains(t, msg.Body, "password")
+}
+
+func TestTextLargeTransfer(t *testing.T) {
+	user, _ := randomUser(t)
+	user.PhoneNumber = "+15551234567"
+	transfer := db.Transfer{ID: 5, FromAccountID: 1, ToAccountID: 2}
+
+	testCases := []struct {
+		name       string
+		amount     int64
+		threshold  int64
+		phone      string
+		preference *bool
+		wantText   bool
+	}{
+		{name: "AtThreshold", amount: 1000, threshold: 1000, phone: user.PhoneNumber, wantText: true},
+		{name: "TurnedOn", amount: 1000, threshold: 1000, phone: user.PhoneNumber, preference: &[]bool{true}[0], wantText: true},
+		{name: "TurnedOff", amount: 1000, threshold: 1000, phone: user.PhoneNumber, preference: &[]bool{false}[0]},
+		{name: "BelowThreshold", amount: 999, threshold: 1000, phone: user.PhoneNumber},
+		{name: "Disabled", amount: 1000000, phone: user.PhoneNumber},
+		{name: "NoPhoneNumber", amount: 1000, threshold: 1000},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// The preference is read in the background too.
+			looked := make(chan struct{})
+			store := mockdb.NewMockStore(ctrl)
+			lookup := store.EXPECT().GetNotificationPreference(gomock.Any(), gomock.Eq(db.GetNotificationPreferenceParams{
+				Username:  user.Username,
+				Channel:   notify.ChannelSMS,
+				EventType: notify.LargeTransfer,
+			}))
+			switch {
+			case tc.preference != nil:
+				lookup.Times(1).DoAndReturn(func(context.Context, db.GetNotificationPreferenceParams) (db.NotificationPreference, error) {
+					close(looked)
+					return db.NotificationPreference{Enabled: *tc.preference}, nil
+				})
+			case tc.wantText:
+				lookup.Times(1).Return(db.NotificationPreference{}, sql.ErrNoRows)
+				close(looked)
+			default:
+				lookup.Times(0)
+				close(looked)
+			}
+
+			sender := mocksms.NewMockSender(ctrl)
+			var sent <-chan sms.Message
+			if tc.wantText {
+				sent = expectText(store, sender)
+			} else {
+				sender.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			}
+
+			server := newTestServer(t, store)
+			WithSMSSender(sender)(server)
+			server.config.SMSTransferThreshold = tc.threshold
+
+			recipient := user
+			recipient.PhoneNumber = tc.phone
+			transfer.Amount = tc.amount
+			server.textLargeTransfer(context.Background(), recipient, transfer, util.INR)
+
+			<-looked
+			if tc.wantText {
+				msg := receiveText(t, sent)
+				require.Equal(t, user.PhoneNumber, msg.To)
+				require.Contains(t, msg.Body, fmt.Sprintf("%d %s", tc.amount, util.INR))
+			}
+		})
+	}
+}
+
+func TestTextUserFailureLeftForRetry(t *testing.T) {
+	user, _ := randomUser(t)
+	user.PhoneNumber = "+15551234567"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failed := make(chan db.MarkNotificationFailedParams, 1)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetNotificationPreference(gomock.Any(), gomock.Any()).Times(1).Return(db.NotificationPreference{}, sql.ErrNoRows)
+	store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateNotificationParams) (db.Notification, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.Equal(t, notify.ChannelSMS, arg.Channel)
+			require.Equal(t, notify.SecurityAlert, arg.Type)
+			require.Equal(t, db.NotificationPending, arg.Status)
+			require.Equal(t, int32(1), arg.Attempts)
+			require.JSONEq(t, `{"to":"+15551234567","body":"alert"}`, string(arg.Payload))
+			return db.Notification{ID: 3}, nil
+		})
+	store.EXPECT().MarkNotificationSent(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().MarkNotificationFailed(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.MarkNotificationFailedParams) error {
+			failed <- arg
+			return nil
+		})
+
+	sender := mocksms.NewMockSender(ctrl)
+	sender.EXPECT().Send(gomock.Any(), gomock.Any()).Times(1).Return(errors.New("provider unavailable"))
+
+	server := newTestServer(t, store)
+	WithSMSSender(sender)(server)
+	server.textUser(user, notify.SecurityAlert, "alert")
+
+	select {
+	case arg := <-failed:
+		require.Equal(t, int64(3), arg.ID)
+		require.Equal(t, db.NotificationPending, arg.Status)
+		require.Equal(t, "provider unavailable", arg.LastError.String)
+	case <-time.After(time.Second):
+		require.FailNow(t, "failure not recorded")
+	}
+}
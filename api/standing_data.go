@@ -0,0 +1,101 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type standingDataHistoryRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+type userHistoryRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// listUserHistory returns the timeline of standing data changes for a user.
+func (server *Server) listUserHistory(ctx *gin.Context) {
+	var uriReq userHistoryRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	server.listStandingDataHistory(ctx, db.StandingDataUser, uriReq.Username)
+}
+
+type accountHistoryRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// listAccountHistory returns the timeline of standing data changes for an account.
+func (server *Server) listAccountHistory(ctx *gin.Context) {
+	var uriReq accountHistoryRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	server.listStandingDataHistory(ctx, db.StandingDataAccount, db.AccountEntityID(uriReq.ID))
+}
+
+func (server *Server) listStandingDataHistory(ctx *gin.Context, entityType, entityID string) {
+	var req standingDataHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	changes, err := server.store.ListStandingDataChanges(ctx, db.ListStandingDataChangesParams{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Limit:      req.PageSize,
+		Offset:     (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, changes)
+}
+
+type revertStandingDataChangeRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// revertStandingDataChange rolls a single standing data change back to the
+// value it replaced. The revert shows up as a new entry in the timeline.
+func (server *Server) revertStandingDataChange(ctx *gin.Context) {
+	var req revertStandingDataChangeRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	change, err := server.store.RevertStandingDataChangeTx(ctx, db.RevertStandingDataChangeTxParams{
+		ChangeID:  req.ID,
+		ChangedBy: authPayload.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		if errors.Is(err, db.ErrStandingDataSuperseded) {
+			ctx.JSON(errorResponse(http.StatusConflict, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, change)
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevertStandingDataChangeAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+	customer, _ := randomUser(t)
+
+	change := db.StandingDataChange{
+		ID:         util.RandomInt(1, 1000),
+		EntityType: db.StandingDataUser,
+		EntityID:   customer.Username,
+		Field:      "email",
+		OldValue:   customer.Email,
+		NewValue:   util.RandomEmail(),
+		ChangedBy:  admin.Username,
+	}
+
+	testCases := []struct {
+		name          string
+		changeID      int64
+		setupAuth     func(t *testing.T, request *http.Request, server *Server)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			changeID: change.ID,
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenMaker, admin.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+					Times(1).
+					Return(admin, nil)
+				arg := db.RevertStandingDataChangeTxParams{
+					ChangeID:  change.ID,
+					ChangedBy: admin.Username,
+				}
+				store.EXPECT().RevertStandingDataChangeTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.StandingDataChange{
+						ID:              change.ID + 1,
+						EntityType:      change.EntityType,
+						EntityID:        change.EntityID,
+						Field:           change.Field,
+						OldValue:        change.NewValue,
+						NewValue:        change.OldValue,
+						ChangedBy:       admin.Username,
+						RevertsChangeID: sql.NullInt64{Int64: change.ID, Valid: true},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "NotAdmin",
+			changeID: change.ID,
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenMaker, customer.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(customer.Username)).
+					Times(1).
+					Return(customer, nil)
+				store.EXPECT().RevertStandingDataChangeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			changeID:  change.ID,
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RevertStandingDataChangeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "Superseded",
+			changeID: change.ID,
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenMaker, admin.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+					Times(1).
+					Return(admin, nil)
+				store.EXPECT().RevertStandingDataChangeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.StandingDataChange{}, db.ErrStandingDataSuperseded)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			changeID: change.ID,
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenMaker, admin.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+					Times(1).
+					Return(admin, nil)
+				store.EXPECT().RevertStandingDataChangeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.StandingDataChange{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/history/%d/revert", tc.changeID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
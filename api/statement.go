@@ -0,0 +1,282 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/archive"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/statement"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	statementDateLayout = "2006-01-02"
+	// maxStatementDays bounds how many entries one email can carry.
+	maxStatementDays = 366
+
+	defaultStatementEmailLimit  = 3
+	defaultStatementEmailWindow = time.Hour
+)
+
+var errLedgerArchived = errors.New("entries that old have been archived")
+
+// checkNotArchived refuses date ranges that start before the ledger archive
+// cutoff, as the entries they need are no longer in the live tables.
+func (server *Server) checkNotArchived(from time.Time) error {
+	cutoff := archive.Cutoff(time.Now(), server.config.LedgerArchiveMonths)
+	if from.Before(cutoff) {
+		return fmt.Errorf("%w, the earliest date available is %s", errLedgerArchived, cutoff.Format(statementDateLayout))
+	}
+	return nil
+}
+
+type emailStatementRequest struct {
+	FromDate string `json:"from_date" binding:"required,datetime=2006-01-02"`
+	ToDate   string `json:"to_date" binding:"required,datetime=2006-01-02"`
+}
+
+type emailStatementResponse struct {
+	JobID     int64     `json:"job_id"`
+	Recipient string    `json:"recipient"`
+	Entries   int       `json:"entries"`
+	QueuedAt  time.Time `json:"queued_at"`
+}
+
+// emailStatement renders the statement of an account for a date range and
+// queues it for the worker. It always goes to the address on file, never to
+// one given in the request, and is rate limited per user.
+func (server *Server) emailStatement(ctx *gin.Context) {
+	var uriReq getAccountRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req emailStatementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	// Both dates are validated by the binding. The range includes to_date.
+	from, _ := time.Parse(statementDateLayout, req.FromDate)
+	to, _ := time.Parse(statementDateLayout, req.ToDate)
+	if to.Before(from) {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errors.New("to_date is before from_date")))
+		return
+	}
+	if to.Sub(from) >= maxStatementDays*24*time.Hour {
+		ctx.JSON(errorResponse(http.StatusBadRequest, fmt.Errorf("statements cover at most %d days", maxStatementDays)))
+		return
+	}
+	if err := server.checkNotArchived(from); err != nil {
+		ctx.JSON(errorResponse(http.StatusGone, err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uriReq.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("account doesn't belong to the authenticated user")))
+		return
+	}
+
+	entries, err := server.store.ListEntriesBetween(ctx, db.ListEntriesBetweenParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	job, err := server.store.EnqueueEmailTx(ctx, db.EnqueueEmailTxParams{
+		Username: authPayload.Username,
+		Kind:     db.EmailKindStatement,
+		Subject:  fmt.Sprintf("Statement for account %d, %s to %s", account.ID, req.FromDate, req.ToDate),
+		Body:     renderStatement(account, entries, req.FromDate, req.ToDate),
+		Since:    time.Now().Add(-server.config.StatementEmailWindow),
+		Limit:    server.config.StatementEmailLimit,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrEmailRateLimited) {
+			setRetryAfter(ctx, server.config.StatementEmailWindow)
+			ctx.JSON(errorResponse(http.StatusTooManyRequests, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, emailStatementResponse{
+		JobID:     job.ID,
+		Recipient: job.Recipient,
+		Entries:   len(entries),
+		QueuedAt:  job.CreatedAt,
+	})
+}
+
+// renderStatement formats a plain text statement. Amounts are in the minor
+// units the account stores.
+func renderStatement(account db.Account, entries []db.Entry, fromDate, toDate string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Statement for account %d (%s)\n", account.ID, account.Currency)
+	fmt.Fprintf(&b, "Period: %s to %s\n\n", fromDate, toDate)
+
+	var credits, debits int64
+	if len(entries) == 0 {
+		b.WriteString("No entries in this period.\n")
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "%s  %-10s  %12d\n", entry.CreatedAt.UTC().Format(time.RFC3339), entry.Kind, entry.Amount)
+		if entry.Amount >= 0 {
+			credits += entry.Amount
+		} else {
+			debits -= entry.Amount
+		}
+	}
+
+	fmt.Fprintf(&b, "\nCredits: %d %s\n", credits, account.Currency)
+	fmt.Fprintf(&b, "Debits:  %d %s\n", debits, account.Currency)
+	fmt.Fprintf(&b, "Net:     %d %s\n", credits-debits, account.Currency)
+	fmt.Fprintf(&b, "Current balance: %d %s\n", account.Balance, account.Currency)
+	return b.String()
+}
+
+type statementPDFRequest struct {
+	Month string `form:"month" binding:"required,datetime=2006-01"`
+}
+
+// getStatementPDF renders the statement of an account for one calendar
+// month as a PDF: opening and closing balances, every entry with the running
+// balance, and every transfer with its memo.
+func (server *Server) getStatementPDF(ctx *gin.Context) {
+	var uriReq getAccountRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req statementPDFRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	// The month is validated by the binding.
+	from, _ := time.Parse("2006-01", req.Month)
+	to := from.AddDate(0, 1, 0)
+	now := time.Now()
+	if from.After(now) {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errors.New("month hasn't started yet")))
+		return
+	}
+	if err := server.checkNotArchived(from); err != nil {
+		ctx.JSON(errorResponse(http.StatusGone, err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uriReq.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("account doesn't belong to the authenticated user")))
+		return
+	}
+
+	balances, err := server.store.GetStatementBalances(ctx, db.GetStatementBalancesParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	entries, err := server.store.ListEntriesBetween(ctx, db.ListEntriesBetweenParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	transfers, err := server.accountTransfersBetween(ctx, account, from, to)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	pdf := statement.Render(statement.Statement{
+		Account:        account,
+		Month:          from,
+		OpeningBalance: balances.OpeningBalance,
+		ClosingBalance: balances.ClosingBalance,
+		Entries:        entries,
+		Transfers:      transfers,
+		GeneratedAt:    now,
+	})
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%d-%s.pdf"`, account.ID, req.Month))
+	ctx.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// accountTransfersBetween returns every completed transfer from or to the
+// account in [from, to), oldest first.
+func (server *Server) accountTransfersBetween(ctx *gin.Context, account db.Account, from, to time.Time) ([]db.ListOwnerTransfersRow, error) {
+	arg := db.ListOwnerTransfersParams{
+		Owner:                 account.Owner,
+		FromTime:              sql.NullTime{Time: from, Valid: true},
+		ToTime:                sql.NullTime{Time: to, Valid: true},
+		CounterpartyAccountID: account.ID,
+		PageLimit:             exportPageSize,
+	}
+	var transfers []db.ListOwnerTransfersRow
+	for {
+		page, err := server.store.ListOwnerTransfers(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+		// A statement shows money that moved.
+		for _, transfer := range page {
+			if transfer.Status == db.TransferCompleted {
+				transfers = append(transfers, transfer)
+			}
+		}
+		if len(page) < exportPageSize {
+			break
+		}
+		arg.BeforeID = page[len(page)-1].ID
+	}
+
+	// Pages come newest first.
+	for i, j := 0, len(transfers)-1; i < j; i, j = i+1, j-1 {
+		transfers[i], transfers[j] = transfers[j], transfers[i]
+	}
+	return transfers, nil
+}
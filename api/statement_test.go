@@ -0,0 +1,281 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailStatementAPI(t *testing.T) {
+	account := randomAccount()
+	entries := []db.Entry{
+		{ID: 1, AccountID: account.ID, Amount: 500, Kind: "transfer", CreatedAt: time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)},
+		{ID: 2, AccountID: account.ID, Amount: -200, Kind: "transfer", CreatedAt: time.Date(2024, 1, 31, 23, 0, 0, 0, time.UTC)},
+	}
+	validBody := gin.H{"from_date": "2024-01-01", "to_date": "2024-01-31"}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			body:     validBody,
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListEntriesBetween(gomock.Any(), gomock.Eq(db.ListEntriesBetweenParams{
+					AccountID: account.ID,
+					FromTime:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					ToTime:    time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				})).
+					Times(1).
+					Return(entries, nil)
+				store.EXPECT().EnqueueEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.EnqueueEmailTxParams) (db.EmailJob, error) {
+						require.Equal(t, account.Owner, arg.Username)
+						require.Equal(t, db.EmailKindStatement, arg.Kind)
+						require.Equal(t, int64(defaultStatementEmailLimit), arg.Limit)
+						require.WithinDuration(t, time.Now().Add(-defaultStatementEmailWindow), arg.Since, time.Minute)
+						require.Contains(t, arg.Body, "Credits: 500")
+						require.Contains(t, arg.Body, "Debits:  200")
+						return db.EmailJob{ID: 9, Username: arg.Username, Recipient: "owner@example.com"}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var got emailStatementResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, int64(9), got.JobID)
+				require.Equal(t, "owner@example.com", got.Recipient)
+				require.Equal(t, 2, got.Entries)
+			},
+		},
+		{
+			name:     "RateLimited",
+			body:     validBody,
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListEntriesBetween(gomock.Any(), gomock.Any()).Times(1).Return(entries, nil)
+				store.EXPECT().EnqueueEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EmailJob{}, db.ErrEmailRateLimited)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Equal(t, "3600", recorder.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name:     "NotOwner",
+			body:     validBody,
+			username: "someone-else",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListEntriesBetween(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().EnqueueEmailTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "AccountNotFound",
+			body:     validBody,
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().EnqueueEmailTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "ToBeforeFrom",
+			body:     gin.H{"from_date": "2024-02-01", "to_date": "2024-01-31"},
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "RangeTooLong",
+			body:     gin.H{"from_date": "2023-01-01", "to_date": "2024-01-31"},
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidDate",
+			body:     gin.H{"from_date": "01/01/2024", "to_date": "2024-01-31"},
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/accounts/%d/statement/email", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetStatementPDFAPI(t *testing.T) {
+	account := randomAccount()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		query         string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			query:    "month=2024-01",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetStatementBalances(gomock.Any(), gomock.Eq(db.GetStatementBalancesParams{
+					AccountID: account.ID,
+					FromTime:  from,
+					ToTime:    to,
+				})).
+					Times(1).
+					Return(db.GetStatementBalancesRow{OpeningBalance: 100, ClosingBalance: 400}, nil)
+				store.EXPECT().ListEntriesBetween(gomock.Any(), gomock.Eq(db.ListEntriesBetweenParams{
+					AccountID: account.ID,
+					FromTime:  from,
+					ToTime:    to,
+				})).
+					Times(1).
+					Return([]db.Entry{{ID: 1, AccountID: account.ID, Amount: 300, Kind: "transfer", CreatedAt: from}}, nil)
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Eq(db.ListOwnerTransfersParams{
+					Owner:                 account.Owner,
+					FromTime:              sql.NullTime{Time: from, Valid: true},
+					ToTime:                sql.NullTime{Time: to, Valid: true},
+					CounterpartyAccountID: account.ID,
+					PageLimit:             exportPageSize,
+				})).
+					Times(1).
+					Return([]db.ListOwnerTransfersRow{{ID: 9, FromAccountID: 2, ToAccountID: account.ID, Amount: 300, Memo: "Invoice 7", Status: db.TransferCompleted, CreatedAt: from}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/pdf", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), "2024-01.pdf")
+				require.True(t, bytes.HasPrefix(recorder.Body.Bytes(), []byte("%PDF-")))
+				require.Contains(t, recorder.Body.String(), "(Invoice 7)")
+			},
+		},
+		{
+			name:     "InvalidMonth",
+			query:    "month=2024-13",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "FutureMonth",
+			query:    "month=" + time.Now().AddDate(0, 2, 0).Format("2006-01"),
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NotOwner",
+			query:    "month=2024-01",
+			username: "someoneelse",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetStatementBalances(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts/%d/statement.pdf?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/settings"
+	"github.com/gin-gonic/gin"
+)
+
+// Component and overall states reported by GET /status.
+const (
+	statusOperational = "operational"
+	statusDegraded    = "degraded"
+	statusOutage      = "outage"
+	statusUnknown     = "unknown"
+	statusMaintenance = "maintenance"
+)
+
+const (
+	// statusCacheTTL keeps the public endpoint from putting load on the database.
+	statusCacheTTL = 10 * time.Second
+	// statusCheckTimeout bounds each dependency check.
+	statusCheckTimeout = 2 * time.Second
+	// workerStaleAfter is how long a due email may wait before the worker is
+	// considered stuck.
+	workerStaleAfter = 5 * time.Minute
+)
+
+type componentStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+type statusResponse struct {
+	Status      string                `json:"status"`
+	Maintenance *settings.Maintenance `json:"maintenance"`
+	Components  []componentStatus     `json:"components"`
+	CheckedAt   time.Time             `json:"checked_at"`
+}
+
+// statusCache holds the last report so that every caller within the TTL
+// shares one round of checks.
+type statusCache struct {
+	mu     sync.Mutex
+	report statusResponse
+}
+
+// getStatus is the public status page feed. Unlike internal health checks it
+// never exposes error details, only the state of each component.
+func (server *Server) getStatus(ctx *gin.Context) {
+	server.status.mu.Lock()
+	if time.Since(server.status.report.CheckedAt) >= statusCacheTTL {
+		server.status.report = server.checkStatus(ctx)
+	}
+	report := server.status.report
+	server.status.mu.Unlock()
+
+	code := http.StatusOK
+	if report.Status == statusOutage {
+		code = http.StatusServiceUnavailable
+	}
+	ctx.Header("Cache-Control", "public, max-age=10")
+	ctx.JSON(code, report)
+}
+
+func (server *Server) checkStatus(parent context.Context) statusResponse {
+	ctx, cancel := context.WithTimeout(parent, statusCheckTimeout)
+	defer cancel()
+
+	database := statusOperational
+	if err := server.store.Ping(ctx); err != nil {
+		database = statusOutage
+	}
+
+	// The email queue lives in the database, so it doubles as the broker, and
+	// due jobs nobody picked up mean the worker is not polling.
+	broker, worker := statusOperational, statusOperational
+	queue, err := server.store.GetEmailQueueHealth(ctx)
+	if err != nil {
+		broker, worker = statusOutage, statusUnknown
+	} else if time.Duration(queue.OldestDueSeconds)*time.Second > workerStaleAfter {
+		worker = statusDegraded
+	}
+
+	report := statusResponse{
+		Status: statusOperational,
+		Components: []componentStatus{
+			{Name: "api", Status: statusOperational},
+			{Name: "database", Status: database},
+			{Name: "worker", Status: worker},
+			{Name: "broker", Status: broker},
+		},
+		CheckedAt: time.Now(),
+	}
+
+	// Without the settings the maintenance state is unknown and left out.
+	if maintenance, err := server.settings.Maintenance(ctx); err == nil {
+		report.Maintenance = &maintenance
+	}
+
+	switch {
+	case report.Maintenance != nil && report.Maintenance.Enabled:
+		report.Status = statusMaintenance
+	case database == statusOutage:
+		report.Status = statusOutage
+	case worker != statusOperational || broker != statusOperational:
+		report.Status = statusDegraded
+	}
+	return report
+}
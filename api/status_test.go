@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/settings"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatusAPI(t *testing.T) {
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, rsp statusResponse)
+	}{
+		{
+			name: "Operational",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetEmailQueueHealth(gomock.Any()).Times(1).Return(db.GetEmailQueueHealthRow{DueJobs: 2, OldestDueSeconds: 3}, nil)
+				store.EXPECT().ListSettings(gomock.Any()).Times(1).Return([]db.Setting{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp statusResponse) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, statusOperational, rsp.Status)
+				require.Equal(t, &settings.Maintenance{}, rsp.Maintenance)
+				for _, component := range rsp.Components {
+					require.Equal(t, statusOperational, component.Status, component.Name)
+				}
+			},
+		},
+		{
+			name: "Maintenance",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetEmailQueueHealth(gomock.Any()).Times(1).Return(db.GetEmailQueueHealthRow{}, nil)
+				store.EXPECT().ListSettings(gomock.Any()).Times(1).Return([]db.Setting{
+					{Key: settings.KeyMaintenanceEnabled, Value: "true"},
+					{Key: settings.KeyMaintenanceMessage, Value: "Upgrading the ledger"},
+				}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp statusResponse) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, statusMaintenance, rsp.Status)
+				require.Equal(t, &settings.Maintenance{Enabled: true, Message: "Upgrading the ledger"}, rsp.Maintenance)
+			},
+		},
+		{
+			name: "WorkerStuck",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetEmailQueueHealth(gomock.Any()).
+					Times(1).
+					Return(db.GetEmailQueueHealthRow{DueJobs: 40, OldestDueSeconds: int64(workerStaleAfter.Seconds()) + 1}, nil)
+				store.EXPECT().ListSettings(gomock.Any()).Times(1).Return([]db.Setting{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp statusResponse) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, statusDegraded, rsp.Status)
+				require.Contains(t, rsp.Components, componentStatus{Name: "worker", Status: statusDegraded})
+			},
+		},
+		{
+			name: "DatabaseDown",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(errors.New("connection refused"))
+				store.EXPECT().GetEmailQueueHealth(gomock.Any()).Times(1).Return(db.GetEmailQueueHealthRow{}, errors.New("connection refused"))
+				store.EXPECT().ListSettings(gomock.Any()).Times(1).Return(nil, errors.New("connection refused"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp statusResponse) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+				require.Equal(t, statusOutage, rsp.Status)
+				require.Nil(t, rsp.Maintenance)
+				require.Contains(t, rsp.Components, componentStatus{Name: "worker", Status: statusUnknown})
+				require.NotContains(t, recorder.Body.String(), "connection refused")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := newTestServer(t, store)
+
+			// The second request is answered from the cache.
+			for i := 0; i < 2; i++ {
+				recorder := httptest.NewRecorder()
+				request, err := http.NewRequest(http.MethodGet, "/status", nil)
+				require.NoError(t, err)
+				server.router.ServeHTTP(recorder, request)
+
+				var rsp statusResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				tc.checkResponse(t, recorder, rsp)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// defaultElevatedTokenDuration is used when ELEVATED_TOKEN_DURATION is unset.
+const defaultElevatedTokenDuration = 5 * time.Minute
+
+// totpPeriod is how long a TOTP code is current for, the default of
+// totp.Generate.
+const totpPeriod = 30 * time.Second
+
+var errStepUpRequired = errors.New("transfer amount requires step-up authentication, call /users/elevate first")
+
+// requiresStepUp returns true if a transfer of amount needs an elevated token.
+// Amounts that can't be converted need one too.
+func (server *Server) requiresStepUp(ctx context.Context, amount int64, currency string) bool {
+	threshold := server.config.ElevatedTransferThreshold
+	if threshold <= 0 {
+		return false
+	}
+	amountINR, _, err := server.rates.Convert(ctx, amount, currency, util.INR)
+	return err != nil || amountINR > threshold
+}
+
+type enrollTotpRequest struct {
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type enrollTotpResponse struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+}
+
+// enrollTotp creates a TOTP secret for the user's authenticator app. It asks
+// for the password so a stolen access token can't swap the authenticator.
+func (server *Server) enrollTotp(ctx *gin.Context) {
+	var req enrollTotpRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	if err := util.CheckPassword(req.Password, user.HashedPassword); err != nil {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, err))
+		return
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      "SimpleBank",
+		AccountName: user.Username,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	_, err = server.store.UpdateUserTotpSecret(ctx, db.UpdateUserTotpSecretParams{
+		Username:   user.Username,
+		TotpSecret: key.Secret(),
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	server.securityAlert(ctx, user.Username, notify.SecurityTotpEnrolled)
+
+	ctx.JSON(http.StatusOK, enrollTotpResponse{
+		Secret: key.Secret(),
+		URL:    key.URL(),
+	})
+}
+
+type elevateRequest struct {
+	Password string `json:"password" binding:"required_without=TotpCode"`
+	TotpCode string `json:"totp_code" binding:"required_without=Password,omitempty,len=6,numeric"`
+}
+
+type elevateResponse struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// elevateToken re-authenticates the user with their password or a TOTP code
+// and issues a short-lived elevated token with the same scopes and device
+// binding as the current one.
+func (server *Server) elevateToken(ctx *gin.Context) {
+	var req elevateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if user.Status == db.UserSuspended {
+		ctx.JSON(errorResponse(http.StatusForbidden, errUserSuspended))
+		return
+	}
+
+	if req.TotpCode != "" {
+		step, ok := totpStep(req.TotpCode, user.TotpSecret, time.Now())
+		if user.TotpSecret == "" || !ok {
+			ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("invalid totp code")))
+			return
+		}
+		// Someone who saw the code mustn't mint a token of their own with
+		// it while it is still accepted.
+		used, err := server.store.UseUserTotpStep(ctx, db.UseUserTotpStepParams{
+			Username: user.Username,
+			Step:     step,
+		})
+		if err != nil {
+			ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+			return
+		}
+		if used == 0 {
+			ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("totp code already used")))
+			return
+		}
+	} else if err := util.CheckPassword(req.Password, user.HashedPassword); err != nil {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, err))
+		return
+	}
+
+	opts := []token.PayloadOption{token.WithElevation(), token.WithScopes(authPayload.Scopes...)}
+	if authPayload.DeviceHash != "" {
+		opts = append(opts, token.WithDevice(ctx.GetHeader(deviceIDHeaderKey)))
+	}
+
+	duration := server.config.ElevatedTokenDuration
+	if duration <= 0 {
+		duration = defaultElevatedTokenDuration
+	}
+
+	accessToken, err := server.tokenMaker.CreateToken(user.Username, duration, opts...)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, elevateResponse{
+		AccessToken: accessToken,
+		ExpiresAt:   time.Now().Add(duration),
+	})
+}
+
+// totpStep returns the time step code was generated for, if it is one of the
+// steps totp.Validate accepts at now: the current one, or the one before or
+// after it for clocks that are a little off.
+func totpStep(code, secret string, now time.Time) (int64, bool) {
+	for _, skew := range []time.Duration{0, -totpPeriod, totpPeriod} {
+		at := now.Add(skew)
+		ok, err := totp.ValidateCustom(code, secret, at, totp.ValidateOpts{
+			Period:    uint(totpPeriod / time.Second),
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err == nil && ok {
+			return at.Unix() / int64(totpPeriod/time.Second), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElevateTokenAPI(t *testing.T) {
+	user, password := randomUser(t)
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "SimpleBank", AccountName: user.Username})
+	require.NoError(t, err)
+	user.TotpSecret = key.Secret()
+
+	now := time.Now()
+	code, err := totp.GenerateCode(key.Secret(), now)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Password",
+			body: gin.H{"password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp elevateResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+
+				payload, err := server.tokenMaker.VerifyToken(rsp.AccessToken)
+				require.NoError(t, err)
+				require.True(t, payload.Elevated)
+				require.Equal(t, []string{token.ScopeTransfersWrite, token.ScopeTokensWrite}, payload.Scopes)
+			},
+		},
+		{
+			name: "TotpCode",
+			body: gin.H{"totp_code": code},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UseUserTotpStepParams{
+					Username: user.Username,
+					Step:     now.Unix() / 30,
+				}
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ReplayedTotpCode",
+			body: gin.H{"totp_code": code},
+			buildStubs: func(store *mockdb.MockStore) {
+				// The step was used before, or a later one was.
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: gin.H{"password": "wrong-password"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "WrongTotpCode",
+			body: gin.H{"totp_code": "000000"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UseUserTotpStep(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/elevate", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute,
+				token.WithScopes(token.ScopeTransfersWrite, token.ScopeTokensWrite))
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestCreateTransferStepUp(t *testing.T) {
+	user, _ := randomUser(t)
+	user.KycTier = util.KYCTierFull
+	fromAccount := db.Account{ID: 1, Owner: user.Username, Balance: 10_000, Currency: util.INR}
+	toAccount := db.Account{ID: 2, Owner: user.Username, Balance: 0, Currency: util.INR}
+
+	testCases := []struct {
+		name          string
+		tokenOpts     []token.PayloadOption
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Elevated",
+			tokenOpts: []token.PayloadOption{token.WithElevation()},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotElevated",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			store.EXPECT().ListSettings(gomock.Any()).AnyTimes().Return([]db.Setting{}, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.ElevatedTransferThreshold = 100
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          500,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute, tc.tokenOpts...)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTotpStep(t *testing.T) {
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "SimpleBank", AccountName: "user"})
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 0, 0, 45, 0, time.UTC)
+	current := now.Unix() / 30
+
+	for _, skew := range []int64{-1, 0, 1} {
+		code, err := totp.GenerateCode(key.Secret(), now.Add(time.Duration(skew)*totpPeriod))
+		require.NoError(t, err)
+		step, ok := totpStep(code, key.Secret(), now)
+		require.True(t, ok)
+		require.Equal(t, current+skew, step)
+	}
+
+	code, err := totp.GenerateCode(key.Secret(), now.Add(-2*totpPeriod))
+	require.NoError(t, err)
+	_, ok := totpStep(code, key.Secret(), now)
+	require.False(t, ok)
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// topCounterpartyCount is how many counterparties a summary lists.
+const topCounterpartyCount = 5
+
+type accountSummaryRequest struct {
+	// The current month when left out.
+	Month string `form:"month" binding:"omitempty,datetime=2006-01"`
+}
+
+type accountSummaryResponse struct {
+	AccountID         int64                         `json:"account_id"`
+	Currency          string                        `json:"currency"`
+	Month             string                        `json:"month"`
+	Inflow            int64                         `json:"inflow"`
+	Outflow           int64                         `json:"outflow"`
+	NetChange         int64                         `json:"net_change"`
+	TransferCount     int64                         `json:"transfer_count"`
+	TopCounterparties []db.ListTopCounterpartiesRow `json:"top_counterparties"`
+}
+
+// getAccountSummary is the month in review of one of the caller's accounts:
+// money in and out, the number of transfers and who they were with.
+func (server *Server) getAccountSummary(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req accountSummaryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if req.Month != "" {
+		// The month is validated by the binding.
+		from, _ = time.Parse("2006-01", req.Month)
+	}
+	if from.After(now) {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errors.New("month hasn't started yet")))
+		return
+	}
+	to := from.AddDate(0, 1, 0)
+
+	account, ok := server.ownAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	summary, err := server.store.GetAccountSummary(ctx, db.GetAccountSummaryParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	counterparties, err := server.store.ListTopCounterparties(ctx, db.ListTopCounterpartiesParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+		Top:       topCounterpartyCount,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, accountSummaryResponse{
+		AccountID:         account.ID,
+		Currency:          account.Currency,
+		Month:             from.Format("2006-01"),
+		Inflow:            summary.Inflow,
+		Outflow:           summary.Outflow,
+		NetChange:         summary.NetChange,
+		TransferCount:     summary.TransferCount,
+		TopCounterparties: counterparties,
+	})
+}
+
+const (
+	// defaultInsightDays is the period covered when no from_date is given.
+	defaultInsightDays = 30
+
+	defaultCounterpartyInsights = 10
+)
+
+type counterpartyInsightsRequest struct {
+	FromDate string `form:"from_date" binding:"omitempty,datetime=2006-01-02"`
+	ToDate   string `form:"to_date" binding:"omitempty,datetime=2006-01-02"`
+	Limit    int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type counterpartyInsightsResponse struct {
+	FromDate       string                             `json:"from_date"`
+	ToDate         string                             `json:"to_date"`
+	Counterparties []db.ListSpendingByCounterpartyRow `json:"counterparties"`
+}
+
+// listCounterpartyInsights shows who the caller sends the most money to.
+// Totals are per receiving user and per currency, since amounts in different
+// currencies can't be added up.
+func (server *Server) listCounterpartyInsights(ctx *gin.Context) {
+	var req counterpartyInsightsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultCounterpartyInsights
+	}
+
+	// Dates are validated by the binding. The range includes to_date.
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.ToDate != "" {
+		to, _ = time.Parse(statementDateLayout, req.ToDate)
+	}
+	from := to.AddDate(0, 0, 1-defaultInsightDays)
+	if req.FromDate != "" {
+		from, _ = time.Parse(statementDateLayout, req.FromDate)
+	}
+	if to.Before(from) {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errors.New("to_date is before from_date")))
+		return
+	}
+	if to.Sub(from) >= maxStatementDays*24*time.Hour {
+		ctx.JSON(errorResponse(http.StatusBadRequest, fmt.Errorf("insights cover at most %d days", maxStatementDays)))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	rows, err := server.store.ListSpendingByCounterparty(ctx, db.ListSpendingByCounterpartyParams{
+		Owner:    authPayload.Username,
+		FromTime: from,
+		ToTime:   to.AddDate(0, 0, 1),
+		Top:      req.Limit,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, counterpartyInsightsResponse{
+		FromDate:       from.Format(statementDateLayout),
+		ToDate:         to.Format(statementDateLayout),
+		Counterparties: rows,
+	})
+}
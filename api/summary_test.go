@@ -0,0 +1,269 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountSummaryAPI(t *testing.T) {
+	account := randomAccount()
+	from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	summary := db.GetAccountSummaryRow{Inflow: 120, Outflow: 45, NetChange: 75, TransferCount: 3}
+	counterparties := []db.ListTopCounterpartiesRow{
+		{AccountID: 7, Owner: "alice", Currency: account.Currency, TransferCount: 2, Sent: 45, Received: 20},
+		{AccountID: 9, Owner: "bob", Currency: account.Currency, TransferCount: 1, Received: 10},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			query:    "month=2024-02",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountSummary(gomock.Any(), gomock.Eq(db.GetAccountSummaryParams{
+					AccountID: account.ID,
+					FromTime:  from,
+					ToTime:    to,
+				})).
+					Times(1).
+					Return(summary, nil)
+				store.EXPECT().ListTopCounterparties(gomock.Any(), gomock.Eq(db.ListTopCounterpartiesParams{
+					AccountID: account.ID,
+					FromTime:  from,
+					ToTime:    to,
+					Top:       topCounterpartyCount,
+				})).
+					Times(1).
+					Return(counterparties, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got accountSummaryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, accountSummaryResponse{
+					AccountID:         account.ID,
+					Currency:          account.Currency,
+					Month:             "2024-02",
+					Inflow:            120,
+					Outflow:           45,
+					NetChange:         75,
+					TransferCount:     3,
+					TopCounterparties: counterparties,
+				}, got)
+			},
+		},
+		{
+			name:     "DefaultsToCurrentMonth",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				now := time.Now().UTC()
+				start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountSummary(gomock.Any(), gomock.Eq(db.GetAccountSummaryParams{
+					AccountID: account.ID,
+					FromTime:  start,
+					ToTime:    start.AddDate(0, 1, 0),
+				})).
+					Times(1).
+					Return(db.GetAccountSummaryRow{}, nil)
+				store.EXPECT().ListTopCounterparties(gomock.Any(), gomock.Any()).Times(1).Return([]db.ListTopCounterpartiesRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got accountSummaryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, time.Now().UTC().Format("2006-01"), got.Month)
+				require.Empty(t, got.TopCounterparties)
+			},
+		},
+		{
+			name:     "FutureMonth",
+			query:    "month=" + time.Now().UTC().AddDate(0, 2, 0).Format("2006-01"),
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccountSummary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidMonth",
+			query:    "month=2024-13",
+			username: account.Owner,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccountSummary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NotOwner",
+			query:    "month=2024-02",
+			username: "someone-else",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountSummary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts/%d/summary?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListCounterpartyInsightsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	rows := []db.ListSpendingByCounterpartyRow{
+		{Counterparty: "alice", Currency: "USD", TransferCount: 3, Total: 900},
+		{Counterparty: "bob", Currency: "EUR", TransferCount: 1, Total: 40},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "from_date=2024-01-01&to_date=2024-01-31&limit=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListSpendingByCounterparty(gomock.Any(), gomock.Eq(db.ListSpendingByCounterpartyParams{
+					Owner:    user.Username,
+					FromTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					ToTime:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+					Top:      2,
+				})).
+					Times(1).
+					Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got counterpartyInsightsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, counterpartyInsightsResponse{
+					FromDate:       "2024-01-01",
+					ToDate:         "2024-01-31",
+					Counterparties: rows,
+				}, got)
+			},
+		},
+		{
+			name: "Defaults",
+			buildStubs: func(store *mockdb.MockStore) {
+				today := time.Now().UTC().Truncate(24 * time.Hour)
+				store.EXPECT().ListSpendingByCounterparty(gomock.Any(), gomock.Eq(db.ListSpendingByCounterpartyParams{
+					Owner:    user.Username,
+					FromTime: today.AddDate(0, 0, 1-defaultInsightDays),
+					ToTime:   today.AddDate(0, 0, 1),
+					Top:      defaultCounterpartyInsights,
+				})).
+					Times(1).
+					Return([]db.ListSpendingByCounterpartyRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "ToBeforeFrom",
+			query: "from_date=2024-02-01&to_date=2024-01-31",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListSpendingByCounterparty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "RangeTooLong",
+			query: "from_date=2022-01-01&to_date=2024-01-31",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListSpendingByCounterparty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidLimit",
+			query: "limit=1000",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListSpendingByCounterparty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/users/me/insights/counterparties?"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
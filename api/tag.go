@@ -0,0 +1,259 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errTransferNotParty = errors.New("transfer doesn't involve the authenticated user")
+	errEntryNotOwned    = errors.New("entry doesn't belong to the authenticated user")
+)
+
+type tagRequest struct {
+	Tag string `json:"tag" binding:"required,alphanum,max=32"`
+}
+
+type taggedURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type tagURI struct {
+	ID  int64  `uri:"id" binding:"required,min=1"`
+	Tag string `uri:"tag" binding:"required,alphanum,max=32"`
+}
+
+// tagsResponse lists the caller's tags on one transfer or entry. Tags are
+// private: the other side of a transfer never sees them.
+type tagsResponse struct {
+	Tags []string `json:"tags"`
+}
+
+// normalizeTag makes "Rent" and "rent" the same category.
+func normalizeTag(tag string) string {
+	return strings.ToLower(tag)
+}
+
+func (server *Server) tagTransfer(ctx *gin.Context) {
+	var uri taggedURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req tagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if !server.transferParty(ctx, uri.ID) {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	_, err := server.store.AddTransferTag(ctx, db.AddTransferTagParams{
+		Owner:      authPayload.Username,
+		Tag:        normalizeTag(req.Tag),
+		TransferID: uri.ID,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+	server.respondTransferTags(ctx, uri.ID)
+}
+
+func (server *Server) untagTransfer(ctx *gin.Context) {
+	var uri tagURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if !server.transferParty(ctx, uri.ID) {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	_, err := server.store.DeleteTransferTag(ctx, db.DeleteTransferTagParams{
+		Owner:      authPayload.Username,
+		TransferID: uri.ID,
+		Tag:        normalizeTag(uri.Tag),
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	server.respondTransferTags(ctx, uri.ID)
+}
+
+func (server *Server) listTransferTags(ctx *gin.Context) {
+	var uri taggedURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if !server.transferParty(ctx, uri.ID) {
+		return
+	}
+	server.respondTransferTags(ctx, uri.ID)
+}
+
+func (server *Server) respondTransferTags(ctx *gin.Context, transferID int64) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	tags, err := server.store.ListTransferTags(ctx, db.ListTransferTagsParams{
+		Owner:      authPayload.Username,
+		TransferID: transferID,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	ctx.JSON(http.StatusOK, tagsResponse{Tags: tags})
+}
+
+// transferParty checks that the caller owns the sending or the receiving
+// account of the transfer. It writes the error response itself.
+func (server *Server) transferParty(ctx *gin.Context, transferID int64) bool {
+	transfer, err := server.store.GetTransfer(ctx, transferID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return false
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	accounts, err := server.store.GetAccountsByIDs(ctx, []int64{transfer.FromAccountID, transfer.ToAccountID})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return false
+	}
+	for _, account := range accounts {
+		if account.Owner == authPayload.Username {
+			return true
+		}
+	}
+	ctx.JSON(errorResponse(http.StatusUnauthorized, errTransferNotParty))
+	return false
+}
+
+func (server *Server) tagEntry(ctx *gin.Context) {
+	var uri taggedURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req tagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if !server.entryOwner(ctx, uri.ID) {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	_, err := server.store.AddEntryTag(ctx, db.AddEntryTagParams{
+		Owner:   authPayload.Username,
+		Tag:     normalizeTag(req.Tag),
+		EntryID: uri.ID,
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+	server.respondEntryTags(ctx, uri.ID)
+}
+
+func (server *Server) untagEntry(ctx *gin.Context) {
+	var uri tagURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if !server.entryOwner(ctx, uri.ID) {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	_, err := server.store.DeleteEntryTag(ctx, db.DeleteEntryTagParams{
+		Owner:   authPayload.Username,
+		EntryID: uri.ID,
+		Tag:     normalizeTag(uri.Tag),
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	server.respondEntryTags(ctx, uri.ID)
+}
+
+func (server *Server) listEntryTags(ctx *gin.Context) {
+	var uri taggedURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if !server.entryOwner(ctx, uri.ID) {
+		return
+	}
+	server.respondEntryTags(ctx, uri.ID)
+}
+
+func (server *Server) respondEntryTags(ctx *gin.Context, entryID int64) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	tags, err := server.store.ListEntryTags(ctx, db.ListEntryTagsParams{
+		Owner:   authPayload.Username,
+		EntryID: entryID,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	ctx.JSON(http.StatusOK, tagsResponse{Tags: tags})
+}
+
+// entryOwner checks that the entry is on one of the caller's accounts. It
+// writes the error response itself.
+func (server *Server) entryOwner(ctx *gin.Context, entryID int64) bool {
+	entry, err := server.store.GetEntry(ctx, entryID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return false
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return false
+	}
+
+	account, err := server.store.GetAccount(ctx, entry.AccountID)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errEntryNotOwned))
+		return false
+	}
+	return true
+}
+
+// listTagSpending breaks the caller's spending down by tag and currency.
+func (server *Server) listTagSpending(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	rows, err := server.store.ListTagSpending(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	ctx.JSON(http.StatusOK, rows)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ankurdas111111/simplebank/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// traceRequests starts a span for every request, named after its route,
+// that continues the caller's trace when it sends a traceparent header.
+// Handlers pass their gin context to the store, so the span is also put
+// among its keys, making the store calls of a request its children.
+func (server *Server) traceRequests() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Probes would make most of the traces.
+		if probePaths[ctx.Request.URL.Path] {
+			ctx.Next()
+			return
+		}
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		parent := tracing.Extract(ctx.Request.Context(), ctx.GetHeader(tracing.TraceparentHeader))
+		spanCtx, span := server.tracer.Start(parent, ctx.Request.Method+" "+route)
+		ctx.Request = ctx.Request.WithContext(spanCtx)
+		ctx.Set(tracing.SpanKey, spanCtx.Value(tracing.SpanKey))
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttribute("http.method", ctx.Request.Method)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("http.status_code", status)
+		span.SetAttribute("http.request_id", requestAuditInfo(ctx).RequestID)
+		var err error
+		if status >= 500 {
+			err = errors.New(http.StatusText(status))
+		}
+		span.End(err)
+	}
+}
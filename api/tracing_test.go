@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	"github.com/ankurdas111111/simplebank/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTraceRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := tracing.NewTracer(provider)
+
+	store := mockdb.NewMockStore(ctrl)
+	// Stands in for the traced store.
+	store.EXPECT().Ping(gomock.Any()).Times(1).DoAndReturn(func(ctx context.Context) error {
+		_, span := tracer.Start(ctx, "Ping")
+		span.End(nil)
+		return nil
+	})
+	server := newTestServer(t, store)
+	WithTracer(tracer)(server)
+	// The middleware is only installed when the router is set up.
+	server.setupRouter()
+	server.router.GET("/ping-store/:id", func(ctx *gin.Context) {
+		if err := server.store.Ping(ctx); err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		ctx.Status(http.StatusBadGateway)
+	})
+
+	for _, path := range []string{"/healthz", "/ping-store/42"} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		request.Header.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		server.router.ServeHTTP(recorder, request)
+	}
+
+	// Probes aren't traced.
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	storeSpan, requestSpan := spans[0], spans[1]
+	require.Equal(t, "GET /ping-store/:id", requestSpan.Name)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", requestSpan.SpanContext.TraceID().String())
+	require.Equal(t, "00f067aa0ba902b7", requestSpan.Parent.SpanID().String())
+	require.Equal(t, codes.Error, requestSpan.Status.Code)
+	require.Equal(t, "Ping", storeSpan.Name)
+	require.Equal(t, requestSpan.SpanContext.TraceID(), storeSpan.SpanContext.TraceID())
+	require.Equal(t, requestSpan.SpanContext.SpanID(), storeSpan.Parent.SpanID())
+}
@@ -7,8 +7,8 @@ import (
 	"net/http"
 
 	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/limits"
 	"github.com/ankurdas111111/simplebank/token"
-	"github.com/ankurdas111111/simplebank/util"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,7 +16,9 @@ import (
 
 type transferRequest struct{
 	FromAccountID   int64 `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   	int64 `json:"to_account_id" binding:"required,min=1"`
+	// Either ToAccountID, BeneficiaryID, PaymentQR, or ToUsername and
+	// ToCurrency name the recipient.
+	ToAccountID   	int64 `json:"to_account_id" binding:"omitempty,min=1"`
 	Amount   		int64 `json:"amount" binding:"required,gt=0"`
 	// Optional: kept for backward compatibility. If provided, it must match the
 	// source account currency.
@@ -24,14 +26,37 @@ type transferRequest struct{
 	// Optional: for transfers to other users, UI can send a recipient username
 	// to validate account_id + username match.
 	ToUsername 		string `json:"to_username" binding:"omitempty"`
+	// Without to_account_id, the recipient's account in this currency is used.
+	ToCurrency 		string `json:"to_currency" binding:"omitempty,currency"`
+	// A payee saved with POST /beneficiaries.
+	BeneficiaryID 	int64 `json:"beneficiary_id" binding:"omitempty,min=1"`
+	// Shown to both sides in the transfer history, e.g. an invoice number.
+	Memo 			string `json:"memo" binding:"max=140"`
+	// From GET /fx/quote: a cross-currency transfer then gets the quoted
+	// rate and fee, if the quote is still valid.
+	QuoteID 		string `json:"quote_id" binding:"omitempty,max=1024"`
+	// The payload of a payee's GET /accounts/:id/payment_qr, as scanned.
+	PaymentQR 		string `json:"payment_qr" binding:"omitempty,max=1024"`
 }
 
+var errRecipientRequired = errors.New("to_account_id, beneficiary_id, payment_qr, or to_username and to_currency, is required")
+
 
 func (server *Server) createTransfer(ctx *gin.Context){
+	// A retry with the same Idempotency-Key gets the original result back.
+	idempotency, err := idempotencyKey(ctx, ctx.MustGet(authorizationPayloadKey).(*token.Payload).Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if server.replayIdempotentRequest(ctx, idempotency) {
+		return
+	}
+
 	var req transferRequest
-	err:= ctx.ShouldBindJSON(&req); 
+	err = ctx.ShouldBindJSON(&req); 
 	if err!=nil{
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
 		return 
 	}
 
@@ -39,79 +64,160 @@ func (server *Server) createTransfer(ctx *gin.Context){
 	fromAccount, err := server.store.GetAccount(ctx, req.FromAccountID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	if fromAccount.Owner != authPayload.Username {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("from account doesn't belong to the authenticated user")))
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("from account doesn't belong to the authenticated user")))
 		return
 	}
 
 	// If request specifies currency, ensure it matches source account.
 	if req.Currency != "" && fromAccount.Currency != req.Currency {
-		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("source account currency mismatch: %s vs %s", fromAccount.Currency, req.Currency)))
+		ctx.JSON(errorResponse(http.StatusBadRequest, fmt.Errorf("source account currency mismatch: %s vs %s", fromAccount.Currency, req.Currency)))
 		return
 	}
 
-	toAccount, err := server.store.GetAccount(ctx, req.ToAccountID)
+	toAccount, err := server.recipientAccount(ctx, req, authPayload.Username)
 	if err != nil {
+		if errors.Is(err, errRecipientRequired) {
+			ctx.JSON(errorResponse(http.StatusBadRequest, err))
+			return
+		}
+		if errors.Is(err, errBeneficiaryNotOwned) {
+			ctx.JSON(errorResponse(http.StatusUnauthorized, err))
+			return
+		}
+		if errors.Is(err, errPaymentQRInvalid) || errors.Is(err, errPaymentQRStale) || errors.Is(err, errSigningDisabled) {
+			ctx.JSON(paymentQRErrorResponse(err))
+			return
+		}
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
 		return
 	}
 
 	// If provided, validate recipient username matches the destination account owner.
 	if req.ToUsername != "" && toAccount.Owner != req.ToUsername {
-		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("recipient username does not match destination account")))
+		ctx.JSON(errorResponse(http.StatusBadRequest, errors.New("recipient username does not match destination account")))
+		return
+	}
+	if req.ToCurrency != "" && toAccount.Currency != req.ToCurrency {
+		ctx.JSON(errorResponse(http.StatusBadRequest, fmt.Errorf("destination account currency mismatch: %s vs %s", toAccount.Currency, req.ToCurrency)))
+		return
+	}
+
+	// What the user may send depends on their KYC tier.
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	err = server.limitEngine.CheckTransfer(ctx, limits.Transfer{
+		Tier:     user.KycTier,
+		Tenant:   user.Tenant,
+		Amount:   req.Amount,
+		Currency: fromAccount.Currency,
+		FX:       fromAccount.Currency != toAccount.Currency,
+		External: toAccount.Owner != authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, limits.ErrNotAllowed) {
+			ctx.JSON(errorResponse(http.StatusForbidden, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	// Large transfers need a token from /users/elevate.
+	if server.requiresStepUp(ctx, req.Amount, fromAccount.Currency) && !authPayload.Elevated {
+		ctx.JSON(errorResponse(http.StatusForbidden, errStepUpRequired))
 		return
 	}
 
 	// Same-currency: old path. Cross-currency: convert and credit converted amount.
+	var result db.TransferTxResult
 	if fromAccount.Currency == toAccount.Currency {
-		arg := db.TransferTxParams{
+		if req.QuoteID != "" {
+			ctx.JSON(errorResponse(http.StatusBadRequest, errQuoteMismatch))
+			return
+		}
+		result, err = server.store.TransferTx(ctx, db.TransferTxParams{
 			FromAccountID: req.FromAccountID,
-			ToAccountID:   req.ToAccountID,
+			ToAccountID:   toAccount.ID,
 			Amount:        req.Amount,
+			Memo:          req.Memo,
+			Idempotency:   idempotency,
+		})
+	} else {
+		// Without a quote the transfer gets the rate and fee of the moment.
+		var quote fxQuote
+		if req.QuoteID != "" {
+			quote, err = server.quoteFor(req.QuoteID, user.Username, fromAccount.Currency, toAccount.Currency, req.Amount)
+		} else {
+			quote, err = server.quoteFX(ctx, user, fromAccount.Currency, toAccount.Currency, req.Amount)
 		}
-		result, err := server.store.TransferTx(ctx, arg)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			ctx.JSON(fxQuoteErrorResponse(err))
 			return
 		}
-		ctx.JSON(http.StatusOK, result)
-		return
-	}
 
-	toAmount, rate, ok := util.ConvertAmount(req.Amount, fromAccount.Currency, toAccount.Currency)
-	if !ok {
-		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("unsupported currency conversion")))
-		return
+		result, err = server.store.TransferTxFX(ctx, db.TransferTxFXParams{
+			FromAccountID: req.FromAccountID,
+			ToAccountID:   toAccount.ID,
+			FromAmount:    req.Amount,
+			ToAmount:      quote.ConvertedAmount,
+			Rate:          quote.Rate,
+			Fee:           quote.Fee,
+			Memo:          req.Memo,
+			Idempotency:   idempotency,
+		})
 	}
-	if toAmount <= 0 {
-		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("amount too small for conversion")))
+	if errors.Is(err, db.ErrIdempotencyKeyUsed) {
+		server.replayConcurrentRequest(ctx, idempotency, err)
 		return
 	}
-
-	result, err := server.store.TransferTxFX(ctx, db.TransferTxFXParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		FromAmount:    req.Amount,
-		ToAmount:      toAmount,
-		Rate:          rate,
-	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(storeErrorResponse(err))
 		return
 	}
+	server.textLargeTransfer(ctx, user, result.Transfer, fromAccount.Currency)
 	ctx.JSON(http.StatusOK, result)
 }
 
+// recipientAccount looks the destination up by ID, by one of the sender's
+// saved beneficiaries or, so users don't have to exchange account numbers, by
+// the recipient's username and currency.
+func (server *Server) recipientAccount(ctx *gin.Context, req transferRequest, username string) (db.Account, error) {
+	if req.ToAccountID != 0 {
+		return server.store.GetAccount(ctx, req.ToAccountID)
+	}
+	if req.BeneficiaryID != 0 {
+		beneficiary, err := server.ownBeneficiary(ctx, req.BeneficiaryID, username)
+		if err != nil {
+			return db.Account{}, err
+		}
+		return server.store.GetAccount(ctx, beneficiary.AccountID)
+	}
+	if req.PaymentQR != "" {
+		return server.paymentQRAccount(ctx, req.PaymentQR)
+	}
+	if req.ToUsername == "" || req.ToCurrency == "" {
+		return db.Account{}, errRecipientRequired
+	}
+	return server.store.GetAccountByOwnerAndCurrency(ctx, db.GetAccountByOwnerAndCurrencyParams{
+		Owner:    req.ToUsername,
+		Currency: req.ToCurrency,
+	})
+}
+
 // validAccount removed: transfer validation now supports cross-currency and enforces ownership.
\ No newline at end of file
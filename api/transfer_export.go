@@ -0,0 +1,126 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// exportPageSize is how many transfers are read and written at a time.
+const exportPageSize = 500
+
+var exportHeader = []string{"id", "created_at", "from_account_id", "to_account_id", "amount", "from_currency", "to_currency", "memo", "status", "to_amount", "rate", "fx_fee"}
+
+type exportTransfersRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv"`
+	// Only transfers made on or between these days, both included.
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// exportTransfers streams the authenticated user's transfer history as CSV,
+// newest first. Transfers are read a page at a time and every page is flushed
+// to the client, so an export of any size holds one page in memory.
+func (server *Server) exportTransfers(ctx *gin.Context) {
+	var req exportTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	// Dates are validated by the binding. The range includes to.
+	var fromTime, toTime sql.NullTime
+	if req.From != "" {
+		from, _ := time.Parse(statementDateLayout, req.From)
+		fromTime = sql.NullTime{Time: from, Valid: true}
+	}
+	if req.To != "" {
+		to, _ := time.Parse(statementDateLayout, req.To)
+		toTime = sql.NullTime{Time: to.AddDate(0, 0, 1), Valid: true}
+	}
+	if fromTime.Valid && toTime.Valid && !fromTime.Time.Before(toTime.Time) {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errors.New("to is before from")))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.ListOwnerTransfersParams{
+		Owner:     authPayload.Username,
+		FromTime:  fromTime,
+		ToTime:    toTime,
+		PageLimit: exportPageSize,
+	}
+	// The first page is read before anything is written, so a failing query
+	// still gets a proper error response.
+	transfers, err := server.store.ListOwnerTransfers(ctx, arg)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", `attachment; filename="transfers.csv"`)
+	ctx.Status(http.StatusOK)
+
+	writer := csv.NewWriter(ctx.Writer)
+	if err := writer.Write(exportHeader); err != nil {
+		return
+	}
+	for {
+		for _, transfer := range transfers {
+			if err := writer.Write(exportRecord(transfer)); err != nil {
+				return
+			}
+		}
+		writer.Flush()
+		if writer.Error() != nil {
+			return
+		}
+		ctx.Writer.Flush()
+
+		if len(transfers) < exportPageSize {
+			return
+		}
+		arg.BeforeID = transfers[len(transfers)-1].ID
+		transfers, err = server.store.ListOwnerTransfers(ctx, arg)
+		if err != nil {
+			// The status is already sent, all that is left is to stop.
+			_ = ctx.Error(err)
+			return
+		}
+	}
+}
+
+func exportRecord(transfer db.ListOwnerTransfersRow) []string {
+	return []string{
+		strconv.FormatInt(transfer.ID, 10),
+		transfer.CreatedAt.UTC().Format(time.RFC3339),
+		strconv.FormatInt(transfer.FromAccountID, 10),
+		strconv.FormatInt(transfer.ToAccountID, 10),
+		strconv.FormatInt(transfer.Amount, 10),
+		transfer.FromCurrency,
+		transfer.ToCurrency,
+		spreadsheetSafe(transfer.Memo),
+		transfer.Status,
+		strconv.FormatInt(transfer.ToAmount, 10),
+		strconv.FormatFloat(transfer.Rate, 'f', -1, 64),
+		strconv.FormatInt(transfer.FxFee, 10),
+	}
+}
+
+// spreadsheetSafe keeps spreadsheets from evaluating a memo as a formula.
+// encoding/csv already takes care of quotes, commas and newlines.
+func spreadsheetSafe(field string) string {
+	if field != "" && strings.ContainsAny(field[:1], "=+-@\t\r") {
+		return "'" + field
+	}
+	return field
+}
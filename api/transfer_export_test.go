@@ -0,0 +1,121 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTransfersAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	createdAt := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+	// A full first page makes the export read a second one.
+	firstPage := make([]db.ListOwnerTransfersRow, exportPageSize)
+	for i := range firstPage {
+		firstPage[i] = db.ListOwnerTransfersRow{ID: int64(1000 - i), FromAccountID: 1, ToAccountID: 2, Amount: 10, FromCurrency: "USD", ToCurrency: "USD", Status: db.TransferCompleted, ToAmount: 10, CreatedAt: createdAt}
+	}
+	firstPage[0].Memo = `rent, "march"`
+	lastPage := []db.ListOwnerTransfersRow{
+		{ID: 3, FromAccountID: 2, ToAccountID: 1, Amount: 20, FromCurrency: "USD", ToCurrency: "USD", Memo: "=HYPERLINK()", Status: db.TransferPending, ToAmount: 20, CreatedAt: createdAt},
+		{ID: 2, FromAccountID: 1, ToAccountID: 5, Amount: 100, FromCurrency: "USD", ToCurrency: "INR", Status: db.TransferCompleted, ToAmount: 8_250, Rate: 83.33, FxFee: 1, CreatedAt: createdAt},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "format=csv&from=2024-03-01&to=2024-03-31",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListOwnerTransfersParams{
+					Owner:     user.Username,
+					FromTime:  sql.NullTime{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					ToTime:    sql.NullTime{Time: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					PageLimit: exportPageSize,
+				}
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(firstPage, nil)
+				arg.BeforeID = firstPage[exportPageSize-1].ID
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(lastPage, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+				records, err := csv.NewReader(recorder.Body).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, records, exportPageSize+3)
+				require.Equal(t, exportHeader, records[0])
+				require.Equal(t, []string{"1000", "2024-03-05T10:00:00Z", "1", "2", "10", "USD", "USD", `rent, "march"`, "completed", "10", "0", "0"}, records[1])
+				require.Equal(t, "'=HYPERLINK()", records[exportPageSize+1][7])
+				// Cross-currency transfers have the amount received and the conversion.
+				require.Equal(t, []string{"8250", "83.33", "1"}, records[exportPageSize+2][9:])
+				require.Equal(t, "pending", records[exportPageSize+1][8])
+			},
+		},
+		{
+			name:  "QueryFails",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:  "UnsupportedFormat",
+			query: "format=xlsx",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "ToBeforeFrom",
+			query: "from=2024-03-31&to=2024-03-01",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "to is before from")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/transfers/export?"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
-	"sort"
+	"strings"
 	"time"
 
 	db "github.com/ankurdas111111/simplebank/db/sqlc"
@@ -11,134 +13,126 @@ import (
 )
 
 type listTransfersRequest struct {
-	PageID   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=1,max=50"`
+	cursorPageRequest
+	// Only transfers whose memo contains this, ignoring case.
+	Query string `form:"q" binding:"max=140"`
+	// Only transfers the caller tagged with this.
+	Tag string `form:"tag" binding:"omitempty,alphanum,max=32"`
+	// Only transfers made on or between these days, both included.
+	FromDate  string `form:"from_date" binding:"omitempty,datetime=2006-01-02"`
+	ToDate    string `form:"to_date" binding:"omitempty,datetime=2006-01-02"`
+	MinAmount int64  `form:"min_amount" binding:"omitempty,min=1"`
+	MaxAmount int64  `form:"max_amount" binding:"omitempty,min=1"`
+	Direction string `form:"direction" binding:"omitempty,oneof=sent received"`
+	// Only transfers from or to this account.
+	CounterpartyAccountID int64  `form:"counterparty_account_id" binding:"omitempty,min=1"`
+	Currency              string `form:"currency" binding:"omitempty,currency"`
 }
 
+// transferHistoryItem is a transfer in the history with every status change
+// it went through, oldest first. Transfers made in one go have none.
 type transferHistoryItem struct {
-	ID          int64     `json:"id"`
-	FromAccount int64     `json:"from_account_id"`
-	ToAccount   int64     `json:"to_account_id"`
-	Amount      int64     `json:"amount"`
-	FromCurrency string   `json:"from_currency"`
-	ToCurrency   string   `json:"to_currency"`
-	CreatedAt   time.Time `json:"created_at"`
+	db.ListOwnerTransfersRow
+	StatusChanges []db.TransferStatusChange `json:"status_changes"`
 }
 
-// listTransfers returns transfer history for the authenticated user.
-// Implementation note: transfers table doesn't store owner, so we:
-// - list the user's accounts
-// - fetch recent transfers per account
-// - merge + de-duplicate + sort by created_at desc
+// memoPattern escapes ILIKE wildcards in the search text so it only matches
+// literally.
+var memoPattern = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// listTransfers returns transfer history for the authenticated user, newest
+// first: every transfer from or to one of their accounts, with the currency
+// of both sides. Every filter is applied by the query, so pages stay full.
 func (server *Server) listTransfers(ctx *gin.Context) {
 	var req listTransfersRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
 		return
 	}
-
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-
-	// Fetch all accounts for this owner (no handler-level page_size constraints here).
-	accounts, err := server.store.ListAccounts(ctx, db.ListAccountsParams{
-		Owner:  authPayload.Username,
-		Limit:  1000,
-		Offset: 0,
-	})
+	beforeID, err := req.lastID()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
 		return
 	}
-
-	accountCurrency := make(map[int64]string, len(accounts))
-	owned := make(map[int64]struct{}, len(accounts))
-	for _, a := range accounts {
-		accountCurrency[a.ID] = a.Currency
-		owned[a.ID] = struct{}{}
+	if req.MinAmount > 0 && req.MaxAmount > 0 && req.MaxAmount < req.MinAmount {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errors.New("max_amount is below min_amount")))
+		return
 	}
 
-	// We over-fetch up to page_id * page_size per account and then slice globally.
-	need := int(req.PageID * req.PageSize)
-	if need < 1 {
-		need = 1
+	// Dates are validated by the binding. The range includes to_date.
+	var fromTime, toTime sql.NullTime
+	if req.FromDate != "" {
+		from, _ := time.Parse(statementDateLayout, req.FromDate)
+		fromTime = sql.NullTime{Time: from, Valid: true}
 	}
-
-	seen := make(map[int64]transferHistoryItem)
-	for _, a := range accounts {
-		transfers, err := server.store.ListTransfers(ctx, db.ListTransfersParams{
-			FromAccountID: a.ID,
-			ToAccountID:   a.ID,
-			Limit:         int32(need),
-			Offset:        0,
-		})
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-			return
-		}
-
-		for _, t := range transfers {
-			// Deduplicate by transfer id across multiple accounts.
-			if _, ok := seen[t.ID]; ok {
-				continue
-			}
-
-			fromCur := accountCurrency[t.FromAccountID]
-			toCur := accountCurrency[t.ToAccountID]
-			// If transfer involves other user's account, currency won't be in map; resolve once.
-			if fromCur == "" {
-				acc, err := server.store.GetAccount(ctx, t.FromAccountID)
-				if err == nil {
-					fromCur = acc.Currency
-					accountCurrency[t.FromAccountID] = fromCur
-				}
-			}
-			if toCur == "" {
-				acc, err := server.store.GetAccount(ctx, t.ToAccountID)
-				if err == nil {
-					toCur = acc.Currency
-					accountCurrency[t.ToAccountID] = toCur
-				}
-			}
-
-			seen[t.ID] = transferHistoryItem{
-				ID:           t.ID,
-				FromAccount:  t.FromAccountID,
-				ToAccount:    t.ToAccountID,
-				Amount:       t.Amount,
-				FromCurrency: fromCur,
-				ToCurrency:   toCur,
-				CreatedAt:    t.CreatedAt,
-			}
-		}
+	if req.ToDate != "" {
+		to, _ := time.Parse(statementDateLayout, req.ToDate)
+		toTime = sql.NullTime{Time: to.AddDate(0, 0, 1), Valid: true}
+	}
+	if fromTime.Valid && toTime.Valid && !fromTime.Time.Before(toTime.Time) {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errors.New("to_date is before from_date")))
+		return
 	}
 
-	items := make([]transferHistoryItem, 0, len(seen))
-	for _, it := range seen {
-		// Only include transfers that touch an owned account (belt-and-suspenders).
-		if _, ok := owned[it.FromAccount]; ok {
-			items = append(items, it)
-			continue
-		}
-		if _, ok := owned[it.ToAccount]; ok {
-			items = append(items, it)
-		}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	search := ""
+	if req.Query != "" {
+		search = "%" + memoPattern.Replace(req.Query) + "%"
 	}
 
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].CreatedAt.After(items[j].CreatedAt)
+	transfers, err := server.store.ListOwnerTransfers(ctx, db.ListOwnerTransfersParams{
+		Owner:                 authPayload.Username,
+		BeforeID:              beforeID,
+		Search:                search,
+		Tag:                   normalizeTag(req.Tag),
+		FromTime:              fromTime,
+		ToTime:                toTime,
+		MinAmount:             req.MinAmount,
+		MaxAmount:             req.MaxAmount,
+		Direction:             req.Direction,
+		CounterpartyAccountID: req.CounterpartyAccountID,
+		Currency:              req.Currency,
+		PageLimit:             req.limit() + 1,
 	})
-
-	offset := int((req.PageID - 1) * req.PageSize)
-	if offset > len(items) {
-		ctx.JSON(http.StatusOK, []transferHistoryItem{})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
 		return
 	}
-	end := offset + int(req.PageSize)
-	if end > len(items) {
-		end = len(items)
+
+	page := newListResponse(transfers, req.limit(), func(transfer db.ListOwnerTransfersRow) int64 { return transfer.ID })
+	items, err := server.withStatusChanges(ctx, page.Items)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
 	}
 
-	ctx.JSON(http.StatusOK, items[offset:end])
+	ctx.JSON(http.StatusOK, withItems(page, items))
 }
 
+// withStatusChanges looks up the status changes of a page of transfers in one
+// query.
+func (server *Server) withStatusChanges(ctx *gin.Context, transfers []db.ListOwnerTransfersRow) ([]transferHistoryItem, error) {
+	items := make([]transferHistoryItem, len(transfers))
+	if len(transfers) == 0 {
+		return items, nil
+	}
 
+	ids := make([]int64, len(transfers))
+	byID := make(map[int64]*transferHistoryItem, len(transfers))
+	for i, transfer := range transfers {
+		ids[i] = transfer.ID
+		items[i] = transferHistoryItem{ListOwnerTransfersRow: transfer, StatusChanges: []db.TransferStatusChange{}}
+		byID[transfer.ID] = &items[i]
+	}
+
+	changes, err := server.store.ListTransferStatusChanges(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		if item, ok := byID[change.TransferID]; ok {
+			item.StatusChanges = append(item.StatusChanges, change)
+		}
+	}
+	return items, nil
+}
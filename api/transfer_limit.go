@@ -0,0 +1,88 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// listMyTransferLimits returns the caller's per-currency transfer limits and
+// how much of the daily total the last 24 hours used.
+func (server *Server) listMyTransferLimits(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	server.respondTransferLimits(ctx, authPayload.Username)
+}
+
+type transferLimitsURI struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+func (server *Server) listTransferLimits(ctx *gin.Context) {
+	var uri transferLimitsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	server.respondTransferLimits(ctx, uri.Username)
+}
+
+func (server *Server) respondTransferLimits(ctx *gin.Context, username string) {
+	rows, err := server.store.ListTransferLimits(ctx, username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	if rows == nil {
+		rows = []db.ListTransferLimitsRow{}
+	}
+	ctx.JSON(http.StatusOK, rows)
+}
+
+type upsertTransferLimitRequest struct {
+	Currency          string `json:"currency" binding:"required,currency"`
+	MaxSingleTransfer int64  `json:"max_single_transfer" binding:"min=0"`
+	MaxDailyTotal     int64  `json:"max_daily_total" binding:"min=0"`
+}
+
+// upsertTransferLimit sets a user's limits in one currency. Zero lifts a
+// limit; TransferTx enforces them.
+func (server *Server) upsertTransferLimit(ctx *gin.Context) {
+	var uri transferLimitsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req upsertTransferLimitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	if _, err := server.store.GetUser(ctx, uri.Username); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	limit, err := server.store.UpsertTransferLimit(ctx, db.UpsertTransferLimitParams{
+		Username:          uri.Username,
+		Currency:          req.Currency,
+		MaxSingleTransfer: req.MaxSingleTransfer,
+		MaxDailyTotal:     req.MaxDailyTotal,
+		UpdatedBy:         authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limit)
+}
@@ -2,18 +2,20 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
 	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/token"
 	"github.com/ankurdas111111/simplebank/util"
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
 )
 
 type createUserRequest struct{
 	Username    string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=6"` // make sure no unnecessary spaces otherwise it will go invalid
+	Password string `json:"password" binding:"required,password"` // make sure no unnecessary spaces otherwise it will go invalid
 	FullName string `json:"full_name" binding:"required"`
 	Email string `json:"email" binding:"required,email"`
 }
@@ -22,6 +24,10 @@ type UserResponse struct{
 	Username          string    `json:"username"`
 	FullName          string    `json:"full_name"`
 	Email             string    `json:"email"`
+	PhoneNumber       string    `json:"phone_number"`
+	KycTier           string    `json:"kyc_tier"`
+	Tenant            string    `json:"tenant"`
+	Status            string    `json:"status"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
 }
@@ -31,6 +37,10 @@ func newUserResponse(user db.User) UserResponse{
 		Username: user.Username,
 		FullName: user.FullName,
 		Email: user.Email,
+		PhoneNumber: user.PhoneNumber,
+		KycTier: user.KycTier,
+		Tenant: user.Tenant,
+		Status: user.Status,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt: user.CreatedAt,
 	}
@@ -40,13 +50,13 @@ func (server *Server) createUser(ctx *gin.Context){
 	var req createUserRequest
 	err:= ctx.ShouldBindJSON(&req); 
 	if err!=nil{
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
 		return 
 	}
 	
-	hashedPassword, err := util.HashPassword(req.Password)
+	hashedPassword, err := util.HashPasswordWith(server.config.PasswordHashAlgorithm, req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
 		return
 	}
 	
@@ -59,14 +69,7 @@ func (server *Server) createUser(ctx *gin.Context){
 	
 	user, err := server.store.CreateUser(ctx, arg)
 	if err!= nil{
-		if pqErr, ok := err.(*pq.Error); ok{
-			switch pqErr.Code.Name(){
-			case "unique_violation":
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
-				return
-			}
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(storeErrorResponse(err))
 		return
 	}
 	ctx.JSON(http.StatusOK, newUserResponse(user))
@@ -85,31 +88,68 @@ type loginUserResponse struct{
 func (server *Server) loginUser(ctx *gin.Context){
 	var req loginUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil{
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
 		return
 	}
 
 	user, err := server.store.GetUser(ctx, req.Username)
 	if err != nil{
 		if err == sql.ErrNoRows{
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
 		return
 	}
 
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil{
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		server.securityAlert(ctx, user.Username, notify.SecurityLoginFailed)
+		ctx.JSON(errorResponse(http.StatusUnauthorized, err))
+		return
+	}
+
+	// The plaintext is only available here, so this is where hashes made with an
+	// older algorithm get upgraded. A failure must not block the login.
+	if util.NeedsRehash(user.HashedPassword, server.config.PasswordHashAlgorithm) {
+		if err := server.rehashPassword(ctx, user, req.Password); err != nil {
+			server.logger.Warn("cannot rehash password", "user", user.Username, "error", err)
+		}
+	}
+
+	server.completeLogin(ctx, user)
+}
+
+// completeLogin issues the tokens of an authenticated user and records the
+// login. Every way of logging in ends here.
+func (server *Server) completeLogin(ctx *gin.Context, user db.User) {
+	if user.Status == db.UserSuspended {
+		ctx.JSON(errorResponse(http.StatusForbidden, errUserSuspended))
+		return
+	}
+
+	// Bind the token to the device that logged in, if the client sent one.
+	var opts []token.PayloadOption
+	if deviceID := ctx.GetHeader(deviceIDHeaderKey); deviceID != "" {
+		opts = append(opts, token.WithDevice(deviceID))
+	}
+
+	accessToken, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration, opts...)
+	if err != nil{
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
 		return
 	}
 
-	accessToken, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	_, err = server.store.CreateLoginEvent(ctx, db.CreateLoginEventParams{
+		Username: user.Username,
+		UserAgent: ctx.Request.UserAgent(),
+		ClientIp: ctx.ClientIP(),
+	})
 	if err != nil{
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
 		return
 	}
+	server.securityAlert(ctx, user.Username, notify.SecurityLogin)
 
 	rsp := loginUserResponse{
 		AccessToken: accessToken,
@@ -117,3 +157,134 @@ func (server *Server) loginUser(ctx *gin.Context){
 	}
 	ctx.JSON(http.StatusOK, rsp)
 }
+
+func (server *Server) rehashPassword(ctx *gin.Context, user db.User, password string) error {
+	hashedPassword, err := util.HashPasswordWith(server.config.PasswordHashAlgorithm, password)
+	if err != nil {
+		return err
+	}
+
+	_, err = server.store.RehashUserPassword(ctx, db.RehashUserPasswordParams{
+		NewHash:  hashedPassword,
+		Username: user.Username,
+		OldHash:  user.HashedPassword,
+	})
+	return err
+}
+
+// getCurrentUser returns the profile of the authenticated user.
+func (server *Server) getCurrentUser(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+type updateUserRequest struct {
+	FullName        *string `json:"full_name" binding:"omitempty,min=1"`
+	Email           *string `json:"email" binding:"omitempty,email"`
+	// An empty number stops security alerts by text message.
+	PhoneNumber     *string `json:"phone_number" binding:"omitempty,eq=|e164"`
+	Password        *string `json:"password" binding:"omitempty,password"`
+	CurrentPassword *string `json:"current_password"`
+}
+
+var (
+	errEmptyUserUpdate         = errors.New("nothing to update")
+	errCurrentPasswordRequired = errors.New("current_password is required to change the password")
+)
+
+// updateUser applies a partial update to the authenticated user's profile.
+// Changing the password needs the current one, so a stolen token alone
+// cannot lock the owner out.
+func (server *Server) updateUser(ctx *gin.Context) {
+	var uriReq struct {
+		Username string `uri:"username" binding:"required,alphanum"`
+	}
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req updateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if req.FullName == nil && req.Email == nil && req.PhoneNumber == nil && req.Password == nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errEmptyUserUpdate))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if uriReq.Username != authPayload.Username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errors.New("cannot update another user")))
+		return
+	}
+
+	arg := db.UpdateUserTxParams{
+		Username:  uriReq.Username,
+		ChangedBy: authPayload.Username,
+	}
+	if req.FullName != nil {
+		arg.FullName = sql.NullString{String: *req.FullName, Valid: true}
+	}
+	if req.Email != nil {
+		arg.Email = sql.NullString{String: *req.Email, Valid: true}
+	}
+	if req.PhoneNumber != nil {
+		arg.PhoneNumber = sql.NullString{String: *req.PhoneNumber, Valid: true}
+	}
+
+	if req.Password != nil {
+		if req.CurrentPassword == nil {
+			ctx.JSON(errorResponse(http.StatusBadRequest, errCurrentPasswordRequired))
+			return
+		}
+
+		user, err := server.store.GetUser(ctx, uriReq.Username)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.JSON(errorResponse(http.StatusNotFound, err))
+				return
+			}
+			ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+			return
+		}
+		if err := util.CheckPassword(*req.CurrentPassword, user.HashedPassword); err != nil {
+			ctx.JSON(errorResponse(http.StatusUnauthorized, err))
+			return
+		}
+
+		hashedPassword, err := util.HashPasswordWith(server.config.PasswordHashAlgorithm, *req.Password)
+		if err != nil {
+			ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+			return
+		}
+		arg.HashedPassword = sql.NullString{String: hashedPassword, Valid: true}
+	}
+
+	user, err := server.store.UpdateUserTx(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return
+		}
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+	if req.Password != nil {
+		server.textPasswordChanged(user)
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
@@ -11,4 +11,15 @@ var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
 		return util.IsSupportedCurrency(currency)
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// newPasswordValidator enforces the configured policy on fields tagged
+// `password`. Use it for new passwords only, never for login.
+func newPasswordValidator(policy util.PasswordPolicy) validator.Func {
+	return func(fieldLevel validator.FieldLevel) bool {
+		if password, ok := fieldLevel.Field().Interface().(string); ok {
+			return policy.Allows(password)
+		}
+		return false
+	}
+}
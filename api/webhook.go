@@ -0,0 +1,277 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errWebhookNotOwned  = errors.New("webhook doesn't belong to the authenticated user")
+	errWebhookURLScheme = errors.New("webhook url must be http or https")
+	errWebhookURLHost   = errors.New("webhook url must resolve to public addresses only")
+)
+
+type createWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2048"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=transfer.created deposit.completed account.frozen"`
+}
+
+// webhookResponse leaves out the secret, which is only shown once, when the
+// subscription is created.
+type webhookResponse struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type createWebhookResponse struct {
+	webhookResponse
+	// Secret verifies the signature header of every delivery.
+	Secret string `json:"secret"`
+}
+
+func newWebhookResponse(subscription db.WebhookSubscription) webhookResponse {
+	return webhookResponse{
+		ID:         subscription.ID,
+		URL:        subscription.Url,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+// createWebhook subscribes a URL to events on the caller's accounts. The
+// signing secret is generated here and returned once.
+func (server *Server) createWebhook(ctx *gin.Context) {
+	var req createWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errWebhookURLScheme))
+		return
+	}
+	if !server.isPublicHost(ctx, target.Hostname()) {
+		ctx.JSON(errorResponse(http.StatusBadRequest, errWebhookURLHost))
+		return
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	subscription, err := server.store.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		Owner:      authPayload.Username,
+		Url:        req.URL,
+		Secret:     secret,
+		EventTypes: uniqueStrings(req.EventTypes),
+	})
+	if err != nil {
+		ctx.JSON(storeErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createWebhookResponse{
+		webhookResponse: newWebhookResponse(subscription),
+		Secret:          subscription.Secret,
+	})
+}
+
+// isPublicHost reports whether every address host resolves to is public, so
+// deliveries can't be aimed at the server's own network, e.g. the cloud
+// metadata service. The webhook worker checks the address it dials again,
+// as DNS may have changed by then.
+func (server *Server) isPublicHost(ctx context.Context, host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return util.IsPublicIP(ip)
+	}
+	ips, err := server.lookupIP(ctx, host)
+	if err != nil || len(ips) == 0 {
+		return false
+	}
+	for _, ip := range ips {
+		if !util.IsPublicIP(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func (server *Server) listWebhooks(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	subscriptions, err := server.store.ListWebhookSubscriptions(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	items := make([]webhookResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		items[i] = newWebhookResponse(subscription)
+	}
+	ctx.JSON(http.StatusOK, items)
+}
+
+type webhookURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// deleteWebhook unsubscribes. Deliveries still queued are dropped with it.
+func (server *Server) deleteWebhook(ctx *gin.Context) {
+	var uri webhookURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.ownWebhook(ctx, uri.ID, authPayload.Username) {
+		return
+	}
+
+	_, err := server.store.DeleteWebhookSubscription(ctx, db.DeleteWebhookSubscriptionParams{
+		ID:    uri.ID,
+		Owner: authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+type webhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	ResponseStatus *int32          `json:"response_status"`
+	LastError      *string         `json:"last_error"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+func newWebhookDeliveryResponse(delivery db.WebhookDelivery) webhookDeliveryResponse {
+	rsp := webhookDeliveryResponse{
+		ID:        delivery.ID,
+		EventType: delivery.EventType,
+		Payload:   delivery.Payload,
+		Status:    delivery.Status,
+		Attempts:  delivery.Attempts,
+		CreatedAt: delivery.CreatedAt,
+	}
+	if delivery.ResponseStatus.Valid {
+		rsp.ResponseStatus = &delivery.ResponseStatus.Int32
+	}
+	if delivery.LastError.Valid {
+		rsp.LastError = &delivery.LastError.String
+	}
+	if delivery.Status == db.WebhookDeliveryPending {
+		rsp.NextAttemptAt = &delivery.LockedUntil
+	}
+	if delivery.DeliveredAt.Valid {
+		rsp.DeliveredAt = &delivery.DeliveredAt.Time
+	}
+	return rsp
+}
+
+// listWebhookDeliveries is the delivery log of a subscription, newest first,
+// for debugging a subscriber.
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var uri webhookURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req cursorPageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+	beforeID, err := req.lastID()
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.ownWebhook(ctx, uri.ID, authPayload.Username) {
+		return
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		SubscriptionID: uri.ID,
+		BeforeID:       beforeID,
+		PageLimit:      req.limit() + 1,
+	})
+	if err != nil {
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	page := newListResponse(deliveries, req.limit(), func(delivery db.WebhookDelivery) int64 { return delivery.ID })
+	items := make([]webhookDeliveryResponse, len(page.Items))
+	for i, delivery := range page.Items {
+		items[i] = newWebhookDeliveryResponse(delivery)
+	}
+	ctx.JSON(http.StatusOK, withItems(page, items))
+}
+
+// ownWebhook checks that the subscription belongs to username. It writes the
+// error response itself.
+func (server *Server) ownWebhook(ctx *gin.Context, id int64, username string) bool {
+	subscription, err := server.store.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(errorResponse(http.StatusNotFound, err))
+			return false
+		}
+		ctx.JSON(errorResponse(http.StatusInternalServerError, err))
+		return false
+	}
+	if subscription.Owner != username {
+		ctx.JSON(errorResponse(http.StatusUnauthorized, errWebhookNotOwned))
+		return false
+	}
+	return true
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// webSocketPingInterval keeps idle connections open through proxies.
+	webSocketPingInterval = 30 * time.Second
+	// webSocketWriteTimeout drops clients that stop reading.
+	webSocketWriteTimeout = 10 * time.Second
+)
+
+// webSocketTokenMiddleware moves the access_token and device_id query
+// parameters into the headers authMiddleware reads, unless the headers are
+// already set. It must run before authMiddleware.
+func webSocketTokenMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader(authorizationHeaderKey) == "" {
+			if accessToken := ctx.Query("access_token"); accessToken != "" {
+				ctx.Request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+			}
+		}
+		if ctx.GetHeader(deviceIDHeaderKey) == "" {
+			if deviceID := ctx.Query("device_id"); deviceID != "" {
+				ctx.Request.Header.Set(deviceIDHeaderKey, deviceID)
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// serveNotifications upgrades the request to a WebSocket that receives the
+// authenticated user's notifications as JSON text messages. The connection
+// is server to client only; anything the client sends is ignored.
+func (server *Server) serveNotifications(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	handler := websocket.Server{
+		// The bearer token authenticates the connection, not cookies, so other
+		// sites can't open one on the user's behalf and the origin needn't be
+		// checked.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			server.pushNotifications(conn, authPayload.Username)
+		},
+	}
+	handler.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+func (server *Server) pushNotifications(conn *websocket.Conn, username string) {
+	notifications, cancel := server.notifications.Subscribe(username)
+	defer cancel()
+
+	// Reading notices when the client goes away and answers its pings.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard []byte
+		for {
+			if err := websocket.Message.Receive(conn, &discard); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(webSocketPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-server.shutdown:
+			return
+		case notification, ok := <-notifications:
+			if !ok {
+				return
+			}
+			if !server.notificationEnabled(conn.Request().Context(), username, notify.ChannelInApp, notification.Type) {
+				continue
+			}
+			conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+			if err := websocket.JSON.Send(conn, notification); err != nil {
+				return
+			}
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+			conn.PayloadType = websocket.PingFrame
+			_, err := conn.Write(nil)
+			conn.PayloadType = websocket.TextFrame
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// securityAlert tells the user's connected clients about a security event on
+// their account.
+func (server *Server) securityAlert(ctx *gin.Context, username, event string) {
+	server.notifications.Alert(username, notify.SecurityAlertData{
+		Event:     event,
+		ClientIP:  ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+}
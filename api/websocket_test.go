@@ -0,0 +1,107 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/token"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestServeNotificationsAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetNotificationPreference(gomock.Any(), gomock.Any()).AnyTimes().Return(db.NotificationPreference{}, sql.ErrNoRows)
+
+	server := newTestServer(t, store)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	username := util.RandomOwner()
+	accessToken, err := server.tokenMaker.CreateToken(username, time.Minute)
+	require.NoError(t, err)
+
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/api/ws?access_token=" + url.QueryEscape(accessToken)
+	conn, err := websocket.Dial(wsURL, "", httpServer.URL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The connection subscribes just after the handshake, so notify until
+	// the first notification gets through.
+	var got struct {
+		Type string                   `json:"type"`
+		Data notify.SecurityAlertData `json:"data"`
+	}
+	for i := 0; ; i++ {
+		require.Less(t, i, 100, "no notification received")
+		server.notifications.Alert(username, notify.SecurityAlertData{Event: notify.SecurityLogin, ClientIP: "10.0.0.1"})
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+		err := websocket.JSON.Receive(conn, &got)
+		var netErr interface{ Timeout() bool }
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			continue
+		}
+		require.NoError(t, err)
+		break
+	}
+	require.Equal(t, notify.SecurityAlert, got.Type)
+	require.Equal(t, notify.SecurityLogin, got.Data.Event)
+	require.Equal(t, "10.0.0.1", got.Data.ClientIP)
+}
+
+func TestServeNotificationsAPIAuth(t *testing.T) {
+	testCases := []struct {
+		name       string
+		setupAuth  func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		wantStatus int
+	}{
+		{
+			name:       "NoToken",
+			setupAuth:  func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "MissingScope",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				accessToken, err := tokenMaker.CreateToken(util.RandomOwner(), time.Minute, token.WithScopes(token.ScopeTransfersRead))
+				require.NoError(t, err)
+				request.URL.RawQuery = "access_token=" + url.QueryEscape(accessToken)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := newTestServer(t, mockdb.NewMockStore(ctrl))
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/ws", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.wantStatus, recorder.Code)
+		})
+	}
+}
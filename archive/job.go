@@ -0,0 +1,99 @@
+// Package archive keeps the monthly partitions of transfers and entries
+// ahead of the clock and moves old ones out of the way.
+package archive
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+)
+
+// monthsAhead is how many months past the current one have their partitions
+// created, so a missed run never leaves new rows without a partition.
+const monthsAhead = 2
+
+// Store is the part of db.Store the job needs.
+type Store interface {
+	CreateLedgerPartitions(ctx context.Context, month time.Time) error
+	ArchiveLedgerPartitions(ctx context.Context, cutoff time.Time) ([]string, error)
+}
+
+// Job creates the partitions of the coming months and archives the
+// partitions of the months that ended more than a number of months ago.
+type Job struct {
+	store    Store
+	interval time.Duration
+	months   int
+	now      func() time.Time
+}
+
+// NewJob returns a job that archives months older than months, or never
+// archives when months is zero.
+func NewJob(store Store, interval time.Duration, months int) *Job {
+	return &Job{
+		store:    store,
+		interval: interval,
+		months:   months,
+		now:      time.Now,
+	}
+}
+
+// Cutoff is the start of the oldest month that isn't archived after months
+// months, or the zero time when months is zero.
+func Cutoff(now time.Time, months int) time.Time {
+	if months <= 0 {
+		return time.Time{}
+	}
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month()-time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// RunOnce creates the partitions of the current month and the months after
+// it, then archives what is past the cutoff and returns the archived
+// partitions. Both steps are idempotent.
+func (job *Job) RunOnce(ctx context.Context) ([]string, error) {
+	// Archiving moves whole tables, which takes longer than a query.
+	ctx = db.WithoutTimeouts(ctx)
+
+	now := job.now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= monthsAhead; i++ {
+		if err := job.store.CreateLedgerPartitions(ctx, month.AddDate(0, i, 0)); err != nil {
+			return nil, err
+		}
+	}
+
+	if job.months <= 0 {
+		return nil, nil
+	}
+	cutoff := Cutoff(now, job.months)
+	archived, err := job.store.ArchiveLedgerPartitions(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	for _, partition := range archived {
+		slog.Info("archived ledger partition", "partition", partition, "cutoff", cutoff.Format("2006-01-02"))
+	}
+	return archived, nil
+}
+
+// Start runs the job right away, as a server that was down at the turn of a
+// month may be short of partitions, and then every interval until ctx is
+// done. Failed runs are logged and retried at the next tick.
+func (job *Job) Start(ctx context.Context) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := job.RunOnce(ctx); err != nil {
+			slog.Error("ledger archive failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
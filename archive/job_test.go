@@ -0,0 +1,53 @@
+package archive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC)
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().CreateLedgerPartitions(gomock.Any(), gomock.Eq(time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC))).Times(1).Return(nil),
+		store.EXPECT().CreateLedgerPartitions(gomock.Any(), gomock.Eq(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))).Times(1).Return(nil),
+		store.EXPECT().CreateLedgerPartitions(gomock.Any(), gomock.Eq(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))).Times(1).Return(nil),
+		store.EXPECT().ArchiveLedgerPartitions(gomock.Any(), gomock.Eq(time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC))).
+			Times(1).
+			Return([]string{"entries_2023_11", "transfers_2023_11"}, nil),
+	)
+
+	job := NewJob(store, time.Hour, 12)
+	job.now = func() time.Time { return now }
+
+	archived, err := job.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"entries_2023_11", "transfers_2023_11"}, archived)
+}
+
+func TestRunOnceWithoutArchiving(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().CreateLedgerPartitions(gomock.Any(), gomock.Any()).Times(monthsAhead + 1).Return(nil)
+	store.EXPECT().ArchiveLedgerPartitions(gomock.Any(), gomock.Any()).Times(0)
+
+	archived, err := NewJob(store, time.Hour, 0).RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, archived)
+}
+
+func TestCutoff(t *testing.T) {
+	require.True(t, Cutoff(time.Now(), 0).IsZero())
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Cutoff(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), 2))
+	require.Equal(t, time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), Cutoff(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), 3))
+}
@@ -0,0 +1,116 @@
+// Package chaos injects faults into the store and outbound HTTP clients so
+// retry, idempotency and reconciliation can be exercised locally. It must
+// never be enabled outside development.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInjected      = errors.New("chaos: injected failure")
+	ErrCommitDropped = errors.New("chaos: commit acknowledgement dropped")
+)
+
+// Config sets how often and how badly things fail. Rates are between 0 and 1.
+type Config struct {
+	// ErrorRate is the chance that a transaction or HTTP request fails
+	// before doing anything.
+	ErrorRate float64
+	// Latency is added before every transaction and HTTP request.
+	Latency time.Duration
+	// DropCommitRate is the chance that a committed transaction reports an
+	// error anyway, as if the acknowledgement was lost on the way back.
+	DropCommitRate float64
+}
+
+// Enabled returns true if the config injects any fault.
+func (config Config) Enabled() bool {
+	return config.ErrorRate > 0 || config.Latency > 0 || config.DropCommitRate > 0
+}
+
+// Injector decides which operations fail. It is safe for concurrent use.
+type Injector struct {
+	config Config
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewInjector creates an injector for the config.
+func NewInjector(config Config) *Injector {
+	return &Injector{
+		config: config,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (injector *Injector) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	injector.mu.Lock()
+	defer injector.mu.Unlock()
+	return injector.rand.Float64() < rate
+}
+
+// delay waits for the configured latency or until ctx is done.
+func (injector *Injector) delay(ctx context.Context) error {
+	if injector.config.Latency <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(injector.config.Latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// BeforeTx is called before a database transaction starts.
+func (injector *Injector) BeforeTx(ctx context.Context) error {
+	if err := injector.delay(ctx); err != nil {
+		return err
+	}
+	if injector.roll(injector.config.ErrorRate) {
+		return ErrInjected
+	}
+	return nil
+}
+
+// AfterCommit is called once a transaction has been committed. The returned
+// error is reported to the caller even though the writes are durable.
+func (injector *Injector) AfterCommit(ctx context.Context) error {
+	if injector.roll(injector.config.DropCommitRate) {
+		return ErrCommitDropped
+	}
+	return nil
+}
+
+// Transport wraps an outbound HTTP transport with the same latency and error
+// rate as the store. A nil next uses http.DefaultTransport.
+func (injector *Injector) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripper{injector: injector, next: next}
+}
+
+type roundTripper struct {
+	injector *Injector
+	next     http.RoundTripper
+}
+
+func (rt roundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	if err := rt.injector.BeforeTx(request.Context()); err != nil {
+		return nil, err
+	}
+	return rt.next.RoundTrip(request)
+}
@@ -0,0 +1,51 @@
+package chaos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectorRates(t *testing.T) {
+	never := NewInjector(Config{})
+	require.False(t, Config{}.Enabled())
+	require.NoError(t, never.BeforeTx(context.Background()))
+	require.NoError(t, never.AfterCommit(context.Background()))
+
+	always := NewInjector(Config{ErrorRate: 1, DropCommitRate: 1})
+	require.ErrorIs(t, always.BeforeTx(context.Background()), ErrInjected)
+	require.ErrorIs(t, always.AfterCommit(context.Background()), ErrCommitDropped)
+}
+
+func TestInjectorLatency(t *testing.T) {
+	injector := NewInjector(Config{Latency: 50 * time.Millisecond})
+
+	start := time.Now()
+	require.NoError(t, injector.BeforeTx(context.Background()))
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, injector.BeforeTx(ctx), context.Canceled)
+}
+
+func TestTransport(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer backend.Close()
+
+	client := &http.Client{Transport: NewInjector(Config{}).Transport(nil)}
+	response, err := client.Get(backend.URL)
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusNoContent, response.StatusCode)
+
+	client = &http.Client{Transport: NewInjector(Config{ErrorRate: 1}).Transport(nil)}
+	_, err = client.Get(backend.URL)
+	require.ErrorIs(t, err, ErrInjected)
+}
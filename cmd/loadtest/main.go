@@ -0,0 +1,271 @@
+// Command loadtest hammers POST /transfers with concurrent transfers between
+// a small set of accounts and reports throughput, latency and how often
+// requests failed on lock conflicts. Run it against a local database:
+//
+//	go run ./cmd/loadtest -accounts 2 -concurrency 16 -duration 30s
+//
+// Without -addr the server runs in-process. The harness creates its own users
+// and raises them to the full KYC tier directly in the database so that
+// limits don't get in the way, so never point it at a real deployment.
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/api"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	_ "github.com/lib/pq"
+)
+
+type account struct {
+	id          int64
+	accessToken string
+}
+
+// result of one transfer. Status 0 means the request never got a response.
+type result struct {
+	status   int
+	conflict bool
+	latency  time.Duration
+}
+
+func main() {
+	addr := flag.String("addr", "", "base URL of a running server; empty runs one in-process")
+	configPath := flag.String("config", ".", "directory containing app.env")
+	accounts := flag.Int("accounts", 2, "number of accounts transfers are spread over; fewer means more contention")
+	concurrency := flag.Int("concurrency", 16, "number of concurrent clients")
+	duration := flag.Duration("duration", 10*time.Second, "how long to send transfers")
+	amount := flag.Int64("amount", 1, "amount of each transfer")
+	flag.Parse()
+
+	if *accounts < 2 {
+		log.Fatal("need at least 2 accounts")
+	}
+
+	config, err := util.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal("cannot load config:", err)
+	}
+	conn, err := sql.Open(config.DBdriver, config.DBsource)
+	if err != nil {
+		log.Fatal("cannot connect to db:", err)
+	}
+
+	baseURL := *addr
+	if baseURL == "" {
+		server, err := api.NewServer(config, db.NewStore(conn))
+		if err != nil {
+			log.Fatal("cannot create server:", err)
+		}
+		ts := httptest.NewServer(server.Handler())
+		defer ts.Close()
+		baseURL = ts.URL
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	pool := make([]account, *accounts)
+	for i := range pool {
+		pool[i], err = setupAccount(client, conn, baseURL)
+		if err != nil {
+			log.Fatal("cannot set up account:", err)
+		}
+	}
+	log.Printf("sending transfers between %d accounts from %d clients for %s", len(pool), *concurrency, *duration)
+
+	results := make(chan result, 1024)
+	deadline := time.Now().Add(*duration)
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed))
+			for time.Now().Before(deadline) {
+				from := rnd.Intn(len(pool))
+				to := (from + 1 + rnd.Intn(len(pool)-1)) % len(pool)
+				results <- transfer(client, baseURL, pool[from], pool[to], *amount)
+			}
+		}(time.Now().UnixNano() + int64(i))
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	start := time.Now()
+	var latencies []time.Duration
+	statuses := make(map[int]int)
+	conflicts := 0
+	for r := range results {
+		latencies = append(latencies, r.latency)
+		statuses[r.status]++
+		if r.conflict {
+			conflicts++
+		}
+	}
+	report(time.Since(start), latencies, statuses, conflicts)
+}
+
+// setupAccount signs up a user, raises them to the full KYC tier and creates
+// a funded INR account.
+func setupAccount(client *http.Client, conn *sql.DB, baseURL string) (account, error) {
+	username := "load" + util.RandomString(10)
+	password := "Str0ng-" + util.RandomString(12)
+
+	_, err := call(client, baseURL, http.MethodPost, "/api/users", "", map[string]interface{}{
+		"username":  username,
+		"password":  password,
+		"full_name": username,
+		"email":     util.RandomEmail(),
+	}, nil)
+	if err != nil {
+		return account{}, err
+	}
+
+	_, err = conn.Exec(`UPDATE users SET kyc_tier = $1 WHERE username = $2`, util.KYCTierFull, username)
+	if err != nil {
+		return account{}, err
+	}
+
+	var login struct {
+		AccessToken string `json:"access_token"`
+	}
+	_, err = call(client, baseURL, http.MethodPost, "/api/users/login", "", map[string]interface{}{
+		"username": username,
+		"password": password,
+	}, &login)
+	if err != nil {
+		return account{}, err
+	}
+
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	_, err = call(client, baseURL, http.MethodPost, "/api/accounts", login.AccessToken, map[string]interface{}{
+		"currency": util.INR,
+	}, &created)
+	if err != nil {
+		return account{}, err
+	}
+
+	_, err = call(client, baseURL, http.MethodPost, fmt.Sprintf("/api/accounts/%d/deposit", created.ID), login.AccessToken, map[string]interface{}{
+		"amount": 1_000_000_000,
+	}, nil)
+	if err != nil {
+		return account{}, err
+	}
+
+	return account{id: created.ID, accessToken: login.AccessToken}, nil
+}
+
+func transfer(client *http.Client, baseURL string, from, to account, amount int64) result {
+	start := time.Now()
+	body, err := call(client, baseURL, http.MethodPost, "/api/transfers", from.accessToken, map[string]interface{}{
+		"from_account_id": from.id,
+		"to_account_id":   to.id,
+		"amount":          amount,
+	}, nil)
+	r := result{latency: time.Since(start)}
+
+	var statusErr *statusError
+	switch {
+	case err == nil:
+		r.status = http.StatusOK
+	case errors.As(err, &statusErr):
+		r.status = statusErr.status
+		r.conflict = strings.Contains(body, "deadlock") || strings.Contains(body, "could not serialize")
+	}
+	return r
+}
+
+type statusError struct {
+	status int
+	body   string
+}
+
+func (err *statusError) Error() string {
+	return fmt.Sprintf("status %d: %s", err.status, err.body)
+}
+
+// call sends a JSON request and decodes a 200 response into out, if given.
+// It returns the raw body so callers can inspect error messages.
+func call(client *http.Client, baseURL, method, path, accessToken string, in, out interface{}) (string, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return "", err
+	}
+
+	request, err := http.NewRequest(method, baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if accessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != http.StatusOK {
+		return string(body), &statusError{status: response.StatusCode, body: string(body)}
+	}
+	if out != nil {
+		return string(body), json.Unmarshal(body, out)
+	}
+	return string(body), nil
+}
+
+func report(elapsed time.Duration, latencies []time.Duration, statuses map[int]int, conflicts int) {
+	total := len(latencies)
+	if total == 0 {
+		fmt.Println("no requests were sent")
+		return
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	percentile := func(p float64) time.Duration {
+		return latencies[int(float64(total-1)*p)]
+	}
+
+	fmt.Printf("requests:     %d in %s\n", total, elapsed.Round(time.Millisecond))
+	fmt.Printf("throughput:   %.1f req/s\n", float64(total)/elapsed.Seconds())
+	fmt.Printf("succeeded:    %d (%.2f%%)\n", statuses[http.StatusOK], 100*float64(statuses[http.StatusOK])/float64(total))
+	fmt.Printf("conflicts:    %d (%.2f%%)\n", conflicts, 100*float64(conflicts)/float64(total))
+	fmt.Printf("latency:      p50 %s  p95 %s  p99 %s  max %s\n",
+		percentile(0.50), percentile(0.95), percentile(0.99), latencies[total-1])
+
+	codes := make([]int, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		label := http.StatusText(code)
+		if code == 0 {
+			label = "transport error"
+		}
+		fmt.Printf("  %3d %-22s %d\n", code, label, statuses[code])
+	}
+}
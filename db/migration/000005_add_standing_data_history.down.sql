@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "standing_data_changes";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "role";
@@ -0,0 +1,19 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'customer';
+
+CREATE TABLE "standing_data_changes" (
+  "id" bigserial PRIMARY KEY,
+  "entity_type" varchar NOT NULL,
+  "entity_id" varchar NOT NULL,
+  "field" varchar NOT NULL,
+  "old_value" varchar NOT NULL,
+  "new_value" varchar NOT NULL,
+  "changed_by" varchar NOT NULL,
+  "reverts_change_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "standing_data_changes" ("entity_type", "entity_id", "created_at");
+
+COMMENT ON COLUMN "standing_data_changes"."reverts_change_id" IS 'set when this change rolled back an earlier one';
+
+ALTER TABLE "standing_data_changes" ADD FOREIGN KEY ("reverts_change_id") REFERENCES "standing_data_changes" ("id");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "kyc_documents";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "kyc_tier";
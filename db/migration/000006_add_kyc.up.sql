@@ -0,0 +1,24 @@
+ALTER TABLE "users" ADD COLUMN "kyc_tier" varchar NOT NULL DEFAULT 'basic';
+
+CREATE TABLE "kyc_documents" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "document_type" varchar NOT NULL,
+  "requested_tier" varchar NOT NULL,
+  "file_name" varchar NOT NULL,
+  "content_type" varchar NOT NULL,
+  "content" bytea NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "reviewed_by" varchar,
+  "review_note" varchar NOT NULL DEFAULT '',
+  "reviewed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "kyc_documents" ("username");
+
+CREATE INDEX ON "kyc_documents" ("status", "created_at");
+
+COMMENT ON COLUMN "kyc_documents"."status" IS 'pending, approved or rejected';
+
+ALTER TABLE "kyc_documents" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
@@ -0,0 +1,7 @@
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "adjusts_period";
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "kind";
+
+DROP TABLE IF EXISTS "accounting_periods";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "is_house";
@@ -0,0 +1,25 @@
+ALTER TABLE "accounts" ADD COLUMN "is_house" boolean NOT NULL DEFAULT false;
+
+ALTER TABLE "entries" ADD COLUMN "kind" varchar NOT NULL DEFAULT 'transfer';
+
+ALTER TABLE "entries" ADD COLUMN "adjusts_period" date;
+
+CREATE TABLE "accounting_periods" (
+  "period" date PRIMARY KEY,
+  "closed_by" varchar NOT NULL,
+  "closed_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "entries" ("created_at");
+
+CREATE INDEX ON "entries" ("adjusts_period");
+
+COMMENT ON COLUMN "accounts"."is_house" IS 'owned by the bank, e.g. fee income or interest expense';
+
+COMMENT ON COLUMN "entries"."kind" IS 'transfer, interest, fee or adjustment';
+
+COMMENT ON COLUMN "entries"."adjusts_period" IS 'closed period corrected by an adjustment entry';
+
+COMMENT ON COLUMN "accounting_periods"."period" IS 'first day of the closed month';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("adjusts_period") REFERENCES "accounting_periods" ("period");
@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "totp_secret" varchar NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "users"."totp_secret" IS 'empty until the user enrolls an authenticator app';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "settings";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "tenant";
@@ -0,0 +1,19 @@
+ALTER TABLE "users" ADD COLUMN "tenant" varchar NOT NULL DEFAULT 'default';
+
+CREATE TABLE "settings" (
+  "id" bigserial PRIMARY KEY,
+  "tenant" varchar NOT NULL DEFAULT '',
+  "currency" varchar NOT NULL DEFAULT '',
+  "key" varchar NOT NULL,
+  "value" varchar NOT NULL,
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "settings" ADD CONSTRAINT "settings_scope_key" UNIQUE ("tenant", "currency", "key");
+
+COMMENT ON COLUMN "users"."tenant" IS 'tenant or branch whose settings apply to the user';
+
+COMMENT ON COLUMN "settings"."tenant" IS 'empty for every tenant';
+
+COMMENT ON COLUMN "settings"."currency" IS 'empty for every currency';
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "retention_run_items";
+
+DROP TABLE IF EXISTS "retention_runs";
+
+DROP TABLE IF EXISTS "retention_rules";
+
+DROP TABLE IF EXISTS "login_events";
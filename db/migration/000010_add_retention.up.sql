@@ -0,0 +1,50 @@
+CREATE TABLE "login_events" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "user_agent" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "login_events" ("created_at");
+
+ALTER TABLE "login_events" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+CREATE TABLE "retention_rules" (
+  "target" varchar PRIMARY KEY,
+  "retain_days" integer NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "retention_runs" (
+  "id" bigserial PRIMARY KEY,
+  "dry_run" boolean NOT NULL,
+  "run_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "retention_run_items" (
+  "id" bigserial PRIMARY KEY,
+  "run_id" bigint NOT NULL,
+  "target" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "cutoff" timestamptz NOT NULL,
+  "affected" bigint NOT NULL
+);
+
+CREATE INDEX ON "retention_run_items" ("run_id");
+
+ALTER TABLE "retention_run_items" ADD FOREIGN KEY ("run_id") REFERENCES "retention_runs" ("id");
+
+COMMENT ON COLUMN "retention_rules"."target" IS 'login_events, sessions or kyc_documents';
+
+COMMENT ON COLUMN "retention_run_items"."action" IS 'purge or anonymize';
+
+COMMENT ON COLUMN "retention_run_items"."affected" IS 'rows removed, or that would be removed on a dry run';
+
+INSERT INTO "retention_rules" ("target", "retain_days", "updated_by") VALUES
+  ('login_events', 365, 'system'),
+  ('sessions', 30, 'system'),
+  ('kyc_documents', 2555, 'system');
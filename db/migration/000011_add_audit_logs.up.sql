@@ -0,0 +1,17 @@
+CREATE TABLE "audit_logs" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "impersonator" varchar,
+  "action" varchar NOT NULL,
+  "resource" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "audit_logs" ("actor", "created_at");
+
+CREATE INDEX ON "audit_logs" ("impersonator", "created_at");
+
+COMMENT ON COLUMN "audit_logs"."actor" IS 'user the action was performed as';
+
+COMMENT ON COLUMN "audit_logs"."impersonator" IS 'admin acting through an impersonation token, if any';
@@ -0,0 +1,24 @@
+CREATE TABLE "email_jobs" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "kind" varchar NOT NULL,
+  "recipient" varchar NOT NULL,
+  "subject" varchar NOT NULL,
+  "body" text NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_error" varchar,
+  "locked_until" timestamptz NOT NULL DEFAULT (now()),
+  "sent_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "email_jobs" ("status", "locked_until");
+
+CREATE INDEX ON "email_jobs" ("username", "kind", "created_at");
+
+ALTER TABLE "email_jobs" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "email_jobs"."status" IS 'pending, sent or failed';
+
+COMMENT ON COLUMN "email_jobs"."locked_until" IS 'a worker owns the job, or it waits for a retry, until then';
@@ -0,0 +1,31 @@
+CREATE TABLE "webauthn_credentials" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "credential_id" bytea UNIQUE NOT NULL,
+  "public_key" bytea NOT NULL,
+  "sign_count" bigint NOT NULL DEFAULT 0,
+  "name" varchar NOT NULL,
+  "last_used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webauthn_credentials" ("username");
+
+ALTER TABLE "webauthn_credentials" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+CREATE TABLE "webauthn_challenges" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "ceremony" varchar NOT NULL,
+  "challenge" bytea NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webauthn_challenges" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "webauthn_credentials"."public_key" IS 'COSE encoded public key';
+
+COMMENT ON COLUMN "webauthn_credentials"."sign_count" IS 'last signature counter reported by the authenticator';
+
+COMMENT ON COLUMN "webauthn_challenges"."ceremony" IS 'registration or login';
@@ -0,0 +1,5 @@
+ALTER TABLE "accounts" ADD COLUMN "house_role" varchar NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX ON "accounts" ("house_role", "currency") WHERE "house_role" <> '';
+
+COMMENT ON COLUMN "accounts"."house_role" IS 'what a house account is used for, e.g. cash; at most one per role and currency';
@@ -0,0 +1,33 @@
+CREATE TABLE "scheduled_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "execute_at" timestamptz NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "locked_until" timestamptz NOT NULL DEFAULT (now()),
+  "transfer_id" bigint,
+  "last_error" varchar,
+  "executed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "scheduled_transfers" ("status", "execute_at");
+
+CREATE INDEX ON "scheduled_transfers" ("owner", "id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+COMMENT ON COLUMN "scheduled_transfers"."status" IS 'pending, succeeded, failed or cancelled';
+
+COMMENT ON COLUMN "scheduled_transfers"."locked_until" IS 'a scheduler is executing the transfer until then';
+
+COMMENT ON COLUMN "scheduled_transfers"."transfer_id" IS 'the transfer made when it succeeded';
@@ -0,0 +1,15 @@
+CREATE TABLE "beneficiaries" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "nickname" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "beneficiaries" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "beneficiaries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "beneficiaries" ADD CONSTRAINT "owner_account_key" UNIQUE ("owner", "account_id");
+
+ALTER TABLE "beneficiaries" ADD CONSTRAINT "owner_nickname_key" UNIQUE ("owner", "nickname");
@@ -0,0 +1,17 @@
+CREATE TABLE "transfer_limits" (
+  "username" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "max_single_transfer" bigint NOT NULL DEFAULT 0 CHECK ("max_single_transfer" >= 0),
+  "max_daily_total" bigint NOT NULL DEFAULT 0 CHECK ("max_daily_total" >= 0),
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "currency")
+);
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "transfer_limits"."max_single_transfer" IS 'zero means no limit';
+
+COMMENT ON COLUMN "transfer_limits"."max_daily_total" IS 'cap on the transfers of any rolling 24 hours, zero means no limit';
@@ -0,0 +1,31 @@
+CREATE TABLE "payment_requests" (
+  "id" bigserial PRIMARY KEY,
+  "requester" varchar NOT NULL,
+  "payer" varchar NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "note" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "responded_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("requester" <> "payer")
+);
+
+CREATE INDEX ON "payment_requests" ("payer", "id");
+
+CREATE INDEX ON "payment_requests" ("requester", "id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("requester") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("payer") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+COMMENT ON COLUMN "payment_requests"."to_account_id" IS 'the requester account that is paid, in its currency';
+
+COMMENT ON COLUMN "payment_requests"."status" IS 'pending, accepted or declined';
+
+COMMENT ON COLUMN "payment_requests"."transfer_id" IS 'the transfer made when it was accepted';
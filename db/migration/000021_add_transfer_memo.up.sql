@@ -0,0 +1,3 @@
+ALTER TABLE "transfers" ADD COLUMN "memo" varchar;
+
+COMMENT ON COLUMN "transfers"."memo" IS 'free text the sender attached, e.g. an invoice number';
@@ -0,0 +1,25 @@
+CREATE TABLE "tags" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "tag" varchar NOT NULL,
+  "transfer_id" bigint,
+  "entry_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK (num_nonnulls("transfer_id", "entry_id") = 1)
+);
+
+CREATE INDEX ON "tags" ("owner", "tag");
+
+ALTER TABLE "tags" ADD CONSTRAINT "owner_transfer_tag_key" UNIQUE ("owner", "transfer_id", "tag");
+
+ALTER TABLE "tags" ADD CONSTRAINT "owner_entry_tag_key" UNIQUE ("owner", "entry_id", "tag");
+
+ALTER TABLE "tags" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "tags" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "tags" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+COMMENT ON COLUMN "tags"."owner" IS 'tags are private, both sides of a transfer can tag it differently';
+
+COMMENT ON COLUMN "tags"."tag" IS 'a lowercase category such as rent, food or salary';
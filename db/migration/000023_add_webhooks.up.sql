@@ -0,0 +1,38 @@
+CREATE TABLE "webhook_subscriptions" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "subscription_id" bigint NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "response_status" integer,
+  "last_error" varchar,
+  "locked_until" timestamptz NOT NULL DEFAULT (now()),
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webhook_subscriptions" ("owner");
+
+CREATE INDEX ON "webhook_deliveries" ("status", "locked_until");
+
+CREATE INDEX ON "webhook_deliveries" ("subscription_id", "id");
+
+ALTER TABLE "webhook_subscriptions" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscriptions" ("id") ON DELETE CASCADE;
+
+COMMENT ON COLUMN "webhook_subscriptions"."secret" IS 'signs every delivery with HMAC-SHA256';
+
+COMMENT ON COLUMN "webhook_deliveries"."status" IS 'pending, delivered or failed';
+
+COMMENT ON COLUMN "webhook_deliveries"."locked_until" IS 'a worker owns the delivery, or it waits for a retry, until then';
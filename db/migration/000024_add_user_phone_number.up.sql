@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "phone_number" varchar NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "users"."phone_number" IS 'E.164 number security alerts are texted to, empty for none';
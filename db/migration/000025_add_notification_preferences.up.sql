@@ -0,0 +1,14 @@
+CREATE TABLE "notification_preferences" (
+  "username" varchar NOT NULL,
+  "channel" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "enabled" boolean NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "channel", "event_type")
+);
+
+ALTER TABLE "notification_preferences" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "notification_preferences"."channel" IS 'in_app or sms';
+
+COMMENT ON COLUMN "notification_preferences"."enabled" IS 'only choices a user made are stored, a missing row means enabled';
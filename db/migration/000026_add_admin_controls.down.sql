@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "balance_adjustments";
+DROP INDEX IF EXISTS "users_status_idx";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "status";
@@ -0,0 +1,27 @@
+ALTER TABLE "users" ADD COLUMN "status" varchar NOT NULL DEFAULT 'active';
+
+CREATE TABLE "balance_adjustments" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "entry_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "reason" varchar NOT NULL,
+  "adjusted_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("amount" <> 0),
+  CHECK (btrim("reason") <> '')
+);
+
+CREATE INDEX ON "balance_adjustments" ("account_id", "id");
+
+CREATE INDEX ON "users" ("status");
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("adjusted_by") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "users"."status" IS 'active or suspended, a suspended user cannot log in';
+
+COMMENT ON COLUMN "balance_adjustments"."reason" IS 'why an admin corrected the balance, required';
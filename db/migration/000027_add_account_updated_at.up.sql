@@ -0,0 +1,3 @@
+ALTER TABLE "accounts" ADD COLUMN "updated_at" timestamptz NOT NULL DEFAULT (now());
+
+COMMENT ON COLUMN "accounts"."updated_at" IS 'bumped by every update, part of the ETag of account reads';
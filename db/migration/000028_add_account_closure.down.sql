@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "owner_currency_key";
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
+
+COMMENT ON COLUMN "accounts"."status" IS 'active or frozen, frozen accounts can neither send nor receive money';
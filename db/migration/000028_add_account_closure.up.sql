@@ -0,0 +1,6 @@
+-- Closed accounts are kept for their history, but no longer take up their
+-- owner's slot for the currency.
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_key";
+CREATE UNIQUE INDEX "owner_currency_key" ON "accounts" ("owner", "currency") WHERE "status" <> 'closed';
+
+COMMENT ON COLUMN "accounts"."status" IS 'active, frozen or closed, only active accounts can send or receive money';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "pot_moves";
+DROP TABLE IF EXISTS "pots";
+
+COMMENT ON COLUMN "entries"."kind" IS 'transfer, interest, fee or adjustment';
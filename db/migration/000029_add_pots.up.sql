@@ -0,0 +1,36 @@
+CREATE TABLE "pots" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "balance" bigint NOT NULL DEFAULT 0 CHECK ("balance" >= 0),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "pot_moves" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "from_pot_id" bigint,
+  "to_pot_id" bigint,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "account_pot_name_key" ON "pots" ("account_id", "name");
+
+CREATE INDEX ON "pot_moves" ("account_id", "id");
+
+ALTER TABLE "pots" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "pot_moves" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "pot_moves" ADD FOREIGN KEY ("from_pot_id") REFERENCES "pots" ("id");
+
+ALTER TABLE "pot_moves" ADD FOREIGN KEY ("to_pot_id") REFERENCES "pots" ("id");
+
+COMMENT ON COLUMN "pots"."balance" IS 'set aside from the balance of the account, which does not include it';
+
+COMMENT ON COLUMN "pot_moves"."from_pot_id" IS 'null when the money came from the balance of the account';
+
+COMMENT ON COLUMN "pot_moves"."to_pot_id" IS 'null when the money went back to the balance of the account';
+
+COMMENT ON COLUMN "entries"."kind" IS 'transfer, deposit, withdrawal, interest, fee, adjustment or pot';
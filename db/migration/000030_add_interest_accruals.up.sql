@@ -0,0 +1,26 @@
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "rate_bps" bigint NOT NULL,
+  "amount_micros" bigint NOT NULL CHECK ("amount_micros" >= 0),
+  "entry_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "account_accrual_date_key" ON "interest_accruals" ("account_id", "accrual_date");
+
+CREATE INDEX ON "interest_accruals" ("accrual_date") WHERE "entry_id" IS NULL;
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'balance of the account and its pots the interest was worked out on';
+
+COMMENT ON COLUMN "interest_accruals"."rate_bps" IS 'yearly rate in basis points';
+
+COMMENT ON COLUMN "interest_accruals"."amount_micros" IS 'interest of the day in millionths of the smallest unit of the currency';
+
+COMMENT ON COLUMN "interest_accruals"."entry_id" IS 'interest entry the accrual was posted with, null until then';
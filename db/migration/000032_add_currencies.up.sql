@@ -0,0 +1,14 @@
+CREATE TABLE "currencies" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "exponent" int NOT NULL CHECK ("exponent" BETWEEN 0 AND 4),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 code, as stored in accounts.currency';
+COMMENT ON COLUMN "currencies"."exponent" IS 'digits after the decimal point of the minor unit, e.g. 2 for cents';
+
+INSERT INTO "currencies" ("code", "name", "exponent") VALUES
+  ('USD', 'US Dollar', 2),
+  ('EUR', 'Euro', 2),
+  ('INR', 'Indian Rupee', 2);
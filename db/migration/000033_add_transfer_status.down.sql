@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "transfer_status_changes";
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "status";
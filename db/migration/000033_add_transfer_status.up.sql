@@ -0,0 +1,18 @@
+ALTER TABLE "transfers" ADD COLUMN "status" varchar NOT NULL DEFAULT 'completed' CHECK ("status" IN ('pending', 'completed', 'failed'));
+
+COMMENT ON COLUMN "transfers"."status" IS 'pending transfers have not moved money yet, failed ones never will';
+
+CREATE TABLE "transfer_status_changes" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_id" bigint NOT NULL,
+  "from_status" varchar NOT NULL,
+  "to_status" varchar NOT NULL,
+  "reason" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfer_status_changes" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "transfer_status_changes" ("transfer_id");
+
+COMMENT ON COLUMN "transfer_status_changes"."reason" IS 'why the transfer moved, e.g. why it failed';
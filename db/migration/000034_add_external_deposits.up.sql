@@ -0,0 +1,17 @@
+CREATE TABLE "external_deposits" (
+  "id" bigserial PRIMARY KEY,
+  "reference" varchar UNIQUE NOT NULL,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "entry_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "external_deposits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "external_deposits" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+CREATE INDEX ON "external_deposits" ("account_id");
+
+COMMENT ON COLUMN "external_deposits"."reference" IS 'the sending bank reference, credited at most once';
+COMMENT ON COLUMN "external_deposits"."entry_id" IS 'the credit, set in the transaction that records the deposit';
@@ -0,0 +1,5 @@
+ALTER TABLE "transfers" DROP CONSTRAINT IF EXISTS "transfers_fx_check";
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "fx_fee";
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "rate";
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "to_amount";
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "from_amount";
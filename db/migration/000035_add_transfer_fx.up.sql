@@ -0,0 +1,11 @@
+ALTER TABLE "transfers" ADD COLUMN "from_amount" bigint;
+ALTER TABLE "transfers" ADD COLUMN "to_amount" bigint;
+ALTER TABLE "transfers" ADD COLUMN "rate" double precision;
+ALTER TABLE "transfers" ADD COLUMN "fx_fee" bigint;
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfers_fx_check" CHECK (num_nulls("from_amount", "to_amount", "rate", "fx_fee") IN (0, 4));
+
+COMMENT ON COLUMN "transfers"."from_amount" IS 'debited from the sender, in its currency, set on cross-currency transfers only';
+COMMENT ON COLUMN "transfers"."to_amount" IS 'credited to the recipient, in its currency';
+COMMENT ON COLUMN "transfers"."rate" IS 'conversion rate from the sender currency to the recipient currency';
+COMMENT ON COLUMN "transfers"."fx_fee" IS 'part of from_amount kept as the conversion fee, in the sender currency';
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS "balance_within_overdraft" ON "accounts";
+
+DROP FUNCTION IF EXISTS check_account_balance();
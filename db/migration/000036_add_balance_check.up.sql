@@ -0,0 +1,17 @@
+-- A CHECK constraint can't express this: a balance may stay below a lowered
+-- overdraft limit, only further debits are refused. Debits lock the account
+-- row, so concurrent ones are checked one after another.
+CREATE FUNCTION check_account_balance() RETURNS trigger AS $$
+BEGIN
+  IF NOT NEW."is_house" AND NEW."balance" < OLD."balance" AND NEW."balance" < -NEW."overdraft_limit" THEN
+    RAISE EXCEPTION 'insufficient funds'
+      USING ERRCODE = 'check_violation',
+            CONSTRAINT = 'balance_within_overdraft',
+            DETAIL = format('account %s', NEW."id");
+  END IF;
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "balance_within_overdraft" BEFORE UPDATE OF "balance" ON "accounts"
+  FOR EACH ROW EXECUTE FUNCTION check_account_balance();
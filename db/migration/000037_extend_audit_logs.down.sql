@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS "audit_logs_append_only" ON "audit_logs";
+DROP FUNCTION IF EXISTS reject_audit_log_change();
+ALTER TABLE "audit_logs" DROP COLUMN IF EXISTS "after";
+ALTER TABLE "audit_logs" DROP COLUMN IF EXISTS "before";
+ALTER TABLE "audit_logs" DROP COLUMN IF EXISTS "request_id";
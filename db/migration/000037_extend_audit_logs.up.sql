@@ -0,0 +1,28 @@
+ALTER TABLE "audit_logs" ADD COLUMN "request_id" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "audit_logs" ADD COLUMN "before" jsonb NOT NULL DEFAULT 'null';
+
+ALTER TABLE "audit_logs" ADD COLUMN "after" jsonb NOT NULL DEFAULT 'null';
+
+CREATE INDEX ON "audit_logs" ("resource", "id");
+
+CREATE INDEX ON "audit_logs" ("request_id") WHERE "request_id" <> '';
+
+COMMENT ON COLUMN "audit_logs"."resource" IS 'what was acted on, e.g. accounts/42, or the request path of an impersonated request';
+
+COMMENT ON COLUMN "audit_logs"."request_id" IS 'X-Request-ID of the API request, empty for background jobs';
+
+COMMENT ON COLUMN "audit_logs"."before" IS 'the resource before a write, null when it was created';
+
+COMMENT ON COLUMN "audit_logs"."after" IS 'the resource after a write';
+
+-- The audit trail is append-only: rows can be added but never changed or
+-- removed.
+CREATE FUNCTION reject_audit_log_change() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_logs is append-only';
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_logs_append_only" BEFORE UPDATE OR DELETE OR TRUNCATE ON "audit_logs"
+  FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_log_change();
@@ -0,0 +1,3 @@
+ALTER TABLE "accounts" ADD COLUMN "version" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "accounts"."version" IS 'incremented on every balance change, for compare-and-swap updates';
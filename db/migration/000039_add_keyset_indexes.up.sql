@@ -0,0 +1,8 @@
+CREATE INDEX ON "transfers" ("from_account_id", "created_at", "id");
+
+CREATE INDEX ON "transfers" ("to_account_id", "created_at", "id");
+
+CREATE INDEX ON "entries" ("account_id", "created_at", "id");
+
+-- Covered by the indexes above, which start with the same column.
+DROP INDEX IF EXISTS "transfers_from_account_id_created_at_idx";
@@ -0,0 +1,3 @@
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "entries_linked";
+
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "transfer_id";
@@ -0,0 +1,15 @@
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+-- Existing transfers can't be matched to their entries, so they get false and
+-- every transfer made from now on gets true.
+ALTER TABLE "transfers" ADD COLUMN "entries_linked" boolean NOT NULL DEFAULT false;
+
+ALTER TABLE "transfers" ALTER COLUMN "entries_linked" SET DEFAULT true;
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'the transfer that booked the entry, null for other kinds of entry';
+
+COMMENT ON COLUMN "transfers"."entries_linked" IS 'false for transfers made before entries recorded their transfer, which the ledger check skips';
@@ -0,0 +1,16 @@
+CREATE TABLE "account_balance_snapshots" (
+  "account_id" bigint NOT NULL,
+  "day" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "pot_balance" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "day")
+);
+
+ALTER TABLE "account_balance_snapshots" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+COMMENT ON COLUMN "account_balance_snapshots"."balance" IS 'closing balance of the account on the day';
+
+COMMENT ON COLUMN "account_balance_snapshots"."pot_balance" IS 'closing balance of the pots of the account on the day';
+
+COMMENT ON COLUMN "account_balance_snapshots"."created_at" IS 'when the snapshot was last worked out';
@@ -0,0 +1,22 @@
+ALTER TABLE "entries" ADD COLUMN "balance_after" bigint;
+
+-- Entries made before the column existed get the balance worked out
+-- backwards from the current one, newest entry first.
+UPDATE "entries" e
+SET "balance_after" = b."balance_after"
+FROM (
+  SELECT
+    e."id",
+    a."balance" - COALESCE(SUM(e."amount") OVER (
+      PARTITION BY e."account_id"
+      ORDER BY e."id" DESC
+      ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+    ), 0) AS "balance_after"
+  FROM "entries" e
+  JOIN "accounts" a ON a."id" = e."account_id"
+) b
+WHERE e."id" = b."id";
+
+ALTER TABLE "entries" ALTER COLUMN "balance_after" SET NOT NULL;
+
+COMMENT ON COLUMN "entries"."balance_after" IS 'balance of the account once the entry was booked';
@@ -0,0 +1,106 @@
+-- Archived partitions stay in the archive schema, so the foreign keys to
+-- transfers and entries are added back without checking existing rows.
+DROP FUNCTION IF EXISTS archive_ledger_partitions(date);
+
+DROP TABLE IF EXISTS "archived_entry_totals";
+
+ALTER TABLE "transfers" RENAME TO "transfers_partitioned";
+
+ALTER INDEX "transfers_pkey" RENAME TO "transfers_partitioned_pkey";
+
+ALTER SEQUENCE "transfers_id_seq" OWNED BY NONE;
+
+ALTER TABLE "entries" RENAME TO "entries_partitioned";
+
+ALTER INDEX "entries_pkey" RENAME TO "entries_partitioned_pkey";
+
+ALTER SEQUENCE "entries_id_seq" OWNED BY NONE;
+
+CREATE TABLE "transfers" (
+  "id" bigint PRIMARY KEY DEFAULT nextval('transfers_id_seq'),
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "memo" varchar,
+  "status" varchar NOT NULL DEFAULT 'completed' CHECK ("status" IN ('pending', 'completed', 'failed')),
+  "from_amount" bigint,
+  "to_amount" bigint,
+  "rate" double precision,
+  "fx_fee" bigint,
+  "entries_linked" boolean NOT NULL DEFAULT true,
+  CONSTRAINT "transfers_fx_check" CHECK (num_nulls("from_amount", "to_amount", "rate", "fx_fee") IN (0, 4))
+);
+
+CREATE TABLE "entries" (
+  "id" bigint PRIMARY KEY DEFAULT nextval('entries_id_seq'),
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "kind" varchar NOT NULL DEFAULT 'transfer',
+  "adjusts_period" date,
+  "transfer_id" bigint,
+  "balance_after" bigint NOT NULL
+);
+
+ALTER SEQUENCE "transfers_id_seq" OWNED BY "transfers"."id";
+
+ALTER SEQUENCE "entries_id_seq" OWNED BY "entries"."id";
+
+INSERT INTO "transfers" SELECT * FROM "transfers_partitioned";
+
+INSERT INTO "entries" SELECT * FROM "entries_partitioned";
+
+DROP TABLE "transfers_partitioned";
+
+DROP TABLE "entries_partitioned";
+
+DROP FUNCTION IF EXISTS create_ledger_partitions(date);
+
+CREATE INDEX ON "transfers" ("to_account_id");
+
+CREATE INDEX ON "transfers" ("from_account_id");
+
+CREATE INDEX ON "transfers" ("to_account_id", "from_account_id");
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at", "id");
+
+CREATE INDEX ON "transfers" ("to_account_id", "created_at", "id");
+
+CREATE INDEX ON "entries" ("account_id");
+
+CREATE INDEX ON "entries" ("created_at");
+
+CREATE INDEX ON "entries" ("adjusts_period");
+
+CREATE INDEX ON "entries" ("account_id", "created_at", "id");
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("adjusts_period") REFERENCES "accounting_periods" ("period");
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id") NOT VALID;
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id") NOT VALID;
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id") NOT VALID;
+
+ALTER TABLE "tags" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id") NOT VALID;
+
+ALTER TABLE "tags" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id") NOT VALID;
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id") NOT VALID;
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id") NOT VALID;
+
+ALTER TABLE "transfer_status_changes" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id") NOT VALID;
+
+ALTER TABLE "external_deposits" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id") NOT VALID;
@@ -0,0 +1,228 @@
+-- Transfers and entries are partitioned by the month they were created in,
+-- in UTC. The primary key of a partitioned table must include the partition
+-- key, so the IDs of transfers and entries can no longer be referenced by
+-- foreign keys.
+ALTER TABLE "entries" DROP CONSTRAINT IF EXISTS "entries_transfer_id_fkey";
+
+ALTER TABLE "scheduled_transfers" DROP CONSTRAINT IF EXISTS "scheduled_transfers_transfer_id_fkey";
+
+ALTER TABLE "payment_requests" DROP CONSTRAINT IF EXISTS "payment_requests_transfer_id_fkey";
+
+ALTER TABLE "tags" DROP CONSTRAINT IF EXISTS "tags_transfer_id_fkey";
+
+ALTER TABLE "tags" DROP CONSTRAINT IF EXISTS "tags_entry_id_fkey";
+
+ALTER TABLE "balance_adjustments" DROP CONSTRAINT IF EXISTS "balance_adjustments_entry_id_fkey";
+
+ALTER TABLE "interest_accruals" DROP CONSTRAINT IF EXISTS "interest_accruals_entry_id_fkey";
+
+ALTER TABLE "transfer_status_changes" DROP CONSTRAINT IF EXISTS "transfer_status_changes_transfer_id_fkey";
+
+ALTER TABLE "external_deposits" DROP CONSTRAINT IF EXISTS "external_deposits_entry_id_fkey";
+
+ALTER TABLE "transfers" RENAME TO "transfers_unpartitioned";
+
+ALTER INDEX "transfers_pkey" RENAME TO "transfers_unpartitioned_pkey";
+
+ALTER SEQUENCE "transfers_id_seq" OWNED BY NONE;
+
+ALTER TABLE "entries" RENAME TO "entries_unpartitioned";
+
+ALTER INDEX "entries_pkey" RENAME TO "entries_unpartitioned_pkey";
+
+ALTER SEQUENCE "entries_id_seq" OWNED BY NONE;
+
+CREATE TABLE "transfers" (
+  "id" bigint NOT NULL DEFAULT nextval('transfers_id_seq'),
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "memo" varchar,
+  "status" varchar NOT NULL DEFAULT 'completed' CHECK ("status" IN ('pending', 'completed', 'failed')),
+  "from_amount" bigint,
+  "to_amount" bigint,
+  "rate" double precision,
+  "fx_fee" bigint,
+  "entries_linked" boolean NOT NULL DEFAULT true,
+  PRIMARY KEY ("id", "created_at"),
+  CONSTRAINT "transfers_fx_check" CHECK (num_nulls("from_amount", "to_amount", "rate", "fx_fee") IN (0, 4))
+) PARTITION BY RANGE ("created_at");
+
+CREATE TABLE "entries" (
+  "id" bigint NOT NULL DEFAULT nextval('entries_id_seq'),
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "kind" varchar NOT NULL DEFAULT 'transfer',
+  "adjusts_period" date,
+  "transfer_id" bigint,
+  "balance_after" bigint NOT NULL,
+  PRIMARY KEY ("id", "created_at")
+) PARTITION BY RANGE ("created_at");
+
+ALTER SEQUENCE "transfers_id_seq" OWNED BY "transfers"."id";
+
+ALTER SEQUENCE "entries_id_seq" OWNED BY "entries"."id";
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("adjusts_period") REFERENCES "accounting_periods" ("period");
+
+-- create_ledger_partitions creates the partitions of the transfers and
+-- entries of the month in_month falls in, unless they exist.
+CREATE FUNCTION create_ledger_partitions(in_month date) RETURNS void AS $$
+DECLARE
+  month_start date := date_trunc('month', in_month);
+  parent text;
+BEGIN
+  FOREACH parent IN ARRAY ARRAY['transfers', 'entries'] LOOP
+    EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+      parent || '_' || to_char(month_start, 'YYYY_MM'),
+      parent,
+      month_start::timestamp AT TIME ZONE 'UTC',
+      (month_start + interval '1 month')::timestamp AT TIME ZONE 'UTC');
+  END LOOP;
+END
+$$ LANGUAGE plpgsql;
+
+-- Every month with transfers or entries gets its partitions, and so do the
+-- current and the next month.
+DO $$
+DECLARE
+  m date;
+BEGIN
+  FOR m IN
+    SELECT generate_series(
+      date_trunc('month', LEAST(
+        (SELECT min("created_at") FROM "transfers_unpartitioned"),
+        (SELECT min("created_at") FROM "entries_unpartitioned"),
+        now()
+      ) AT TIME ZONE 'UTC'),
+      date_trunc('month', now() AT TIME ZONE 'UTC') + interval '1 month',
+      interval '1 month'
+    )::date
+  LOOP
+    PERFORM create_ledger_partitions(m);
+  END LOOP;
+END
+$$;
+
+INSERT INTO "transfers" (
+  "id", "from_account_id", "to_account_id", "amount", "created_at", "memo", "status",
+  "from_amount", "to_amount", "rate", "fx_fee", "entries_linked"
+)
+SELECT
+  "id", "from_account_id", "to_account_id", "amount", "created_at", "memo", "status",
+  "from_amount", "to_amount", "rate", "fx_fee", "entries_linked"
+FROM "transfers_unpartitioned";
+
+INSERT INTO "entries" (
+  "id", "account_id", "amount", "created_at", "kind", "adjusts_period", "transfer_id", "balance_after"
+)
+SELECT
+  "id", "account_id", "amount", "created_at", "kind", "adjusts_period", "transfer_id", "balance_after"
+FROM "entries_unpartitioned";
+
+DROP TABLE "transfers_unpartitioned";
+
+DROP TABLE "entries_unpartitioned";
+
+CREATE INDEX ON "transfers" ("to_account_id");
+
+CREATE INDEX ON "transfers" ("from_account_id");
+
+CREATE INDEX ON "transfers" ("to_account_id", "from_account_id");
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at", "id");
+
+CREATE INDEX ON "transfers" ("to_account_id", "created_at", "id");
+
+CREATE INDEX ON "entries" ("account_id");
+
+CREATE INDEX ON "entries" ("created_at");
+
+CREATE INDEX ON "entries" ("adjusts_period");
+
+CREATE INDEX ON "entries" ("account_id", "created_at", "id");
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+COMMENT ON COLUMN "transfers"."amount" IS 'must be positive';
+
+COMMENT ON COLUMN "transfers"."memo" IS 'free text the sender attached, e.g. an invoice number';
+
+COMMENT ON COLUMN "transfers"."status" IS 'pending transfers have not moved money yet, failed ones never will';
+
+COMMENT ON COLUMN "transfers"."from_amount" IS 'debited from the sender, in its currency, set on cross-currency transfers only';
+
+COMMENT ON COLUMN "transfers"."to_amount" IS 'credited to the recipient, in its currency';
+
+COMMENT ON COLUMN "transfers"."rate" IS 'conversion rate from the sender currency to the recipient currency';
+
+COMMENT ON COLUMN "transfers"."fx_fee" IS 'part of from_amount kept as the conversion fee, in the sender currency';
+
+COMMENT ON COLUMN "transfers"."entries_linked" IS 'false for transfers made before entries recorded their transfer, which the ledger check skips';
+
+COMMENT ON COLUMN "entries"."amount" IS 'can be positive or negative';
+
+COMMENT ON COLUMN "entries"."kind" IS 'transfer, deposit, withdrawal, interest, fee, adjustment or pot';
+
+COMMENT ON COLUMN "entries"."adjusts_period" IS 'closed period corrected by an adjustment entry';
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'the transfer that booked the entry, null for other kinds of entry';
+
+COMMENT ON COLUMN "entries"."balance_after" IS 'balance of the account once the entry was booked';
+
+-- Partitions past the archive window are detached into the archive schema,
+-- where they can still be queried, dumped or dropped. What the archived
+-- entries added up to is kept per account so the ledger check still holds.
+CREATE SCHEMA IF NOT EXISTS "archive";
+
+CREATE TABLE "archived_entry_totals" (
+  "account_id" bigint PRIMARY KEY,
+  "total" bigint NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "archived_entry_totals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+COMMENT ON COLUMN "archived_entry_totals"."total" IS 'sum of the entries of the account that have been archived';
+
+-- archive_ledger_partitions detaches the partitions of the months that ended
+-- by cutoff into the archive schema and returns their names.
+CREATE FUNCTION archive_ledger_partitions(cutoff date) RETURNS SETOF varchar AS $$
+DECLARE
+  p record;
+BEGIN
+  FOR p IN
+    SELECT child.relname::varchar AS name, parent.relname::varchar AS parent
+    FROM pg_inherits i
+    JOIN pg_class child ON child.oid = i.inhrelid
+    JOIN pg_class parent ON parent.oid = i.inhparent
+    WHERE parent.relnamespace = 'public'::regnamespace
+      AND parent.relname IN ('transfers', 'entries')
+      AND child.relname ~ '_\d{4}_\d{2}$'
+      AND to_date(right(child.relname, 7), 'YYYY_MM') + interval '1 month' <= cutoff
+    ORDER BY child.relname
+  LOOP
+    IF p.parent = 'entries' THEN
+      EXECUTE format(
+        'INSERT INTO archived_entry_totals (account_id, total)
+         SELECT account_id, SUM(amount) FROM %I GROUP BY account_id
+         ON CONFLICT (account_id) DO UPDATE
+         SET total = archived_entry_totals.total + EXCLUDED.total, updated_at = now()',
+        p.name);
+    END IF;
+    EXECUTE format('ALTER TABLE %I DETACH PARTITION %I', p.parent, p.name);
+    EXECUTE format('ALTER TABLE %I SET SCHEMA archive', p.name);
+    RETURN NEXT p.name;
+  END LOOP;
+END
+$$ LANGUAGE plpgsql;
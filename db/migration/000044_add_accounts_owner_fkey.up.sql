@@ -0,0 +1,19 @@
+-- Migration 000004 dropped the foreign key from accounts to their owners so
+-- tests could create accounts for users that didn't exist. The key applies to
+-- new and updated rows right away. It is only validated here when every
+-- account has an owner; otherwise the orphans need fixing by hand, then
+-- ALTER TABLE accounts VALIDATE CONSTRAINT accounts_owner_fkey.
+ALTER TABLE accounts VALIDATE CONSTRAINT accounts_owner_fkey.
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "accounts_owner_fkey";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_owner_fkey" FOREIGN KEY ("owner") REFERENCES "users" ("username") NOT VALID;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1 FROM "accounts" a
+    WHERE NOT EXISTS (SELECT 1 FROM "users" u WHERE u."username" = a."owner")
+  ) THEN
+    ALTER TABLE "accounts" VALIDATE CONSTRAINT "accounts_owner_fkey";
+  END IF;
+END $$;
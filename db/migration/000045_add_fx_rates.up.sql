@@ -0,0 +1,21 @@
+-- A rate applies from valid_from until the next rate of its pair, so the
+-- rate of any conversion can be looked up after the fact.
+CREATE TABLE "fx_rates" (
+  "id" bigserial PRIMARY KEY,
+  "base" varchar NOT NULL,
+  "quote" varchar NOT NULL,
+  "rate" double precision NOT NULL CHECK ("rate" > 0),
+  "valid_from" timestamptz NOT NULL DEFAULT (now()),
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("base" <> "quote")
+);
+
+ALTER TABLE "fx_rates" ADD CONSTRAINT "fx_rates_pair_valid_from_key" UNIQUE ("base", "quote", "valid_from");
+
+COMMENT ON COLUMN "fx_rates"."rate" IS 'units of quote one unit of base is worth';
+
+-- The rates that used to be built in, in force since before any transfer.
+INSERT INTO "fx_rates" ("base", "quote", "rate", "valid_from", "created_by") VALUES
+  ('USD', 'INR', 83, '1970-01-01 00:00:00+00', 'system'),
+  ('EUR', 'INR', 90, '1970-01-01 00:00:00+00', 'system');
@@ -0,0 +1,4 @@
+DELETE FROM "accounts" WHERE "owner" = '_house' AND "house_role" = 'fees';
+DELETE FROM "users" u
+WHERE u."username" = '_house'
+  AND NOT EXISTS (SELECT 1 FROM "accounts" a WHERE a."owner" = u."username");
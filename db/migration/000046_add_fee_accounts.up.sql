@@ -0,0 +1,12 @@
+-- House accounts need an owner. Usernames are alphanumeric, so no one can
+-- sign up as this one, and it can't log in.
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email", "status") VALUES
+  ('_house', '', 'SimpleBank', 'house@simplebank.invalid', 'suspended')
+ON CONFLICT DO NOTHING;
+
+-- Fees are credited to the fees account of their currency, so money a
+-- transfer keeps is still on the books.
+INSERT INTO "accounts" ("owner", "balance", "currency", "is_house", "house_role")
+SELECT '_house', 0, c, true, 'fees'
+FROM unnest(ARRAY['USD', 'EUR', 'INR']) AS c
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+DELETE FROM "idempotency_keys" WHERE "status" = 'in_progress';
+ALTER TABLE "idempotency_keys" DROP COLUMN IF EXISTS "response_status";
+ALTER TABLE "idempotency_keys" DROP COLUMN IF EXISTS "status";
+
+COMMENT ON COLUMN "idempotency_keys"."response" IS 'written in the transaction that claimed the key, so it is set whenever the row is visible';
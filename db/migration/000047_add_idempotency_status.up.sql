@@ -0,0 +1,11 @@
+-- Keys claimed in the transaction of their request are only visible once it
+-- commits with the response, so they are completed from the start. Keys
+-- claimed ahead of a request are in progress until its response is stored.
+ALTER TABLE "idempotency_keys" ADD COLUMN "status" varchar NOT NULL DEFAULT 'completed';
+ALTER TABLE "idempotency_keys" ADD COLUMN "response_status" int NOT NULL DEFAULT 200;
+
+COMMENT ON COLUMN "idempotency_keys"."status" IS 'in_progress while the request runs, completed once its response is stored';
+
+COMMENT ON COLUMN "idempotency_keys"."response" IS 'set whenever status is completed';
+
+COMMENT ON COLUMN "idempotency_keys"."response_status" IS 'HTTP status a retry of the request is answered with';
@@ -0,0 +1,48 @@
+-- A hold sets money aside for a payment that is authorized but not yet
+-- taken, e.g. a card authorization. It stays on the account until it is
+-- released, or captured by a transfer of the money.
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "status" varchar NOT NULL DEFAULT 'active',
+  "memo" varchar,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "resolved_at" timestamptz
+);
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "holds" ("account_id") WHERE "status" = 'active';
+
+COMMENT ON COLUMN "holds"."status" IS 'active, released or captured';
+
+COMMENT ON COLUMN "holds"."transfer_id" IS 'the transfer that captured the hold';
+
+-- The total of the active holds, so the available balance can be checked
+-- under the row lock of the account.
+ALTER TABLE "accounts" ADD COLUMN "held" bigint NOT NULL DEFAULT 0 CHECK ("held" >= 0);
+
+COMMENT ON COLUMN "accounts"."held" IS 'total of the active holds on the account';
+
+-- Held money can't be spent: what a debit or a new hold leaves available must
+-- stay within the overdraft.
+CREATE OR REPLACE FUNCTION check_account_balance() RETURNS trigger AS $$
+BEGIN
+  IF NOT NEW."is_house"
+    AND NEW."balance" - NEW."held" < OLD."balance" - OLD."held"
+    AND NEW."balance" - NEW."held" < -NEW."overdraft_limit" THEN
+    RAISE EXCEPTION 'insufficient funds'
+      USING ERRCODE = 'check_violation',
+            CONSTRAINT = 'balance_within_overdraft',
+            DETAIL = format('account %s', NEW."id");
+  END IF;
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS "balance_within_overdraft" ON "accounts";
+
+CREATE TRIGGER "balance_within_overdraft" BEFORE UPDATE OF "balance", "held" ON "accounts"
+  FOR EACH ROW EXECUTE FUNCTION check_account_balance();
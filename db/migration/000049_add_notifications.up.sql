@@ -0,0 +1,29 @@
+-- Every notification generated for a user, in-app ones as their inbox and
+-- the others as an outbox their deliveries are retried from.
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "channel" varchar NOT NULL,
+  "type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_error" varchar,
+  "locked_until" timestamptz NOT NULL DEFAULT (now()),
+  "sent_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "notifications" ("username", "channel", "id");
+
+CREATE INDEX ON "notifications" ("status", "locked_until");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "notifications"."channel" IS 'in_app or sms';
+
+COMMENT ON COLUMN "notifications"."payload" IS 'the data of an in-app notification, the message of the others';
+
+COMMENT ON COLUMN "notifications"."status" IS 'pending, sent or failed; in-app notifications are sent once stored';
+
+COMMENT ON COLUMN "notifications"."locked_until" IS 'a sender owns the delivery, or it waits for a retry, until then';
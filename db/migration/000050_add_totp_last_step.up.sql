@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "totp_last_step" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "users"."totp_last_step" IS 'the time step of the last TOTP code accepted, codes from it or earlier are refused';
@@ -0,0 +1,38 @@
+// Package migration embeds the schema migrations, which golang-migrate
+// applies from this directory, so the server knows which schema it expects.
+package migration
+
+import (
+	"embed"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.up.sql
+var migrations embed.FS
+
+// LatestVersion is the schema version the newest migration brings the
+// database to.
+func LatestVersion() (int64, error) {
+	entries, err := migrations.ReadDir(".")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int64
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok {
+			return 0, fmt.Errorf("migration %s has no version prefix", entry.Name())
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	return latest, nil
+}
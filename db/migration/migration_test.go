@@ -0,0 +1,19 @@
+package migration
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestVersion(t *testing.T) {
+	files, err := filepath.Glob("*.up.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	// Versions are numbered without gaps, so the newest is the count.
+	latest, err := LatestVersion()
+	require.NoError(t, err)
+	require.Equal(t, int64(len(files)), latest)
+}
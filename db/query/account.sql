@@ -15,6 +15,13 @@ INSERT INTO accounts (
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1;
 
+-- name: GetAccountsByIDs :many
+-- Resolves many accounts in one round trip. IDs that don't exist are left
+-- out, so callers compare what they got against what they asked for
+SELECT * FROM accounts
+WHERE id = ANY(sqlc.arg(ids)::bigint[])
+ORDER BY id;
+
 -- name: GetAccountForUpdate :one
 -- Direct primary key lookup ensures O(1) performance via B-tree index
 -- LIMIT 1 optimizes query planning - tells PostgreSQL to stop after first match
@@ -28,26 +35,47 @@ FOR NO KEY UPDATE;
 -- Ordering by primary key is efficient due to clustered index usage
 SELECT * FROM accounts
 WHERE owner = $1
+  AND status <> 'closed'
 ORDER BY id
 LIMIT $2
 OFFSET $3;
 
--- name: UpdateAccount :one
+-- name: SetAccountBalance :one
 -- Single-row UPDATE targeting primary key for efficient index scan
 -- RETURNING clause eliminates need for separate SELECT after UPDATE
--- This is an absolute-value update (overwrites existing balance)
+-- Overwrites the balance without an entry, so it is only for fixtures and
+-- repairs; money movements use AddAccountBalance
 UPDATE accounts
-SET balance = $2
-WHERE id = $1
+SET balance = sqlc.arg(balance),
+    version = version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
 RETURNING *;
 
--- name: UpdateAccountBalance :one
+-- name: AddAccountBalance :one
 -- Atomic increment/decrement pattern for concurrent safety
--- Uses SET balance = balance + $2 for race-condition-free operation
+-- Uses SET balance = balance + amount for race-condition-free operation
 -- Critical for maintaining consistency under concurrent modifications
+-- A negative amount is a debit
+UPDATE accounts
+SET balance = balance + sqlc.arg(amount),
+    version = version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: AddAccountBalanceIfVersion :one
+-- Compare-and-swap alternative to locking the row with GetAccountForUpdate:
+-- the balance only changes if nothing changed it since the account was read
+-- at version. No row, sql.ErrNoRows, means it did and the caller should read
+-- the account again and retry. Suits accounts whose balance is read far more
+-- often than it is changed, e.g. deposits without a cash side
 UPDATE accounts
-SET balance = balance + $2
-WHERE id = $1
+SET balance = balance + sqlc.arg(amount),
+    version = version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+  AND version = sqlc.arg(version)
 RETURNING *;
 
 -- name: DeleteAccount :exec
@@ -56,3 +84,55 @@ RETURNING *;
 -- Returns no rows (exec) since we don't need the deleted data
 DELETE FROM accounts
 WHERE id = $1;
+
+-- name: GetHouseAccount :one
+-- House accounts are looked up by what they are used for rather than by ID,
+-- so each environment can create its own
+SELECT * FROM accounts
+WHERE is_house
+  AND house_role = sqlc.arg(house_role)
+  AND currency = sqlc.arg(currency)
+LIMIT 1;
+
+-- name: ListAccountsAfter :many
+-- Keyset pagination: the page starts after the last ID of the previous one,
+-- so deep pages stay cheap and don't shift when accounts are added
+SELECT * FROM accounts
+WHERE owner = sqlc.arg(owner)
+  AND status <> 'closed'
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: GetAccountByOwnerAndCurrency :one
+-- A user has at most one open account per currency (owner_currency_key), so
+-- the pair names an account without its ID
+SELECT * FROM accounts
+WHERE owner = sqlc.arg(owner)
+  AND currency = sqlc.arg(currency)
+  AND status <> 'closed'
+LIMIT 1;
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = sqlc.arg(status),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = sqlc.arg(overdraft_limit),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SearchAccounts :many
+-- Admin lookup across all owners. Each filter is off at its zero value
+SELECT * FROM accounts
+WHERE (sqlc.arg(owner)::varchar = '' OR owner = sqlc.arg(owner)::varchar)
+  AND (sqlc.arg(currency)::varchar = '' OR currency = sqlc.arg(currency)::varchar)
+  AND (sqlc.arg(status)::varchar = '' OR status = sqlc.arg(status)::varchar)
+ORDER BY id
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
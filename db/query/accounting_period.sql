@@ -0,0 +1,53 @@
+-- name: CloseAccountingPeriod :one
+INSERT INTO accounting_periods (
+  period,
+  closed_by
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetAccountingPeriod :one
+SELECT * FROM accounting_periods
+WHERE period = $1 LIMIT 1;
+
+-- name: ListAccountingPeriods :many
+SELECT * FROM accounting_periods
+ORDER BY period DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: IsCurrentPeriodClosed :one
+-- now() is the transaction start time, which is also what entries are stamped with
+SELECT EXISTS (
+  SELECT 1 FROM accounting_periods
+  WHERE period = date_trunc('month', now())::date
+)::bool AS closed;
+
+-- name: GetHouseTrialBalance :many
+-- Debits are money leaving an account, credits money arriving
+SELECT
+  a.id AS account_id,
+  a.owner,
+  a.currency,
+  COALESCE(SUM(CASE WHEN e.amount < 0 THEN -e.amount ELSE 0 END), 0)::bigint AS debits,
+  COALESCE(SUM(CASE WHEN e.amount > 0 THEN e.amount ELSE 0 END), 0)::bigint AS credits
+FROM accounts a
+JOIN entries e ON e.account_id = a.id
+WHERE a.is_house
+  AND e.created_at >= sqlc.arg(period_start)::timestamptz
+  AND e.created_at < sqlc.arg(period_end)::timestamptz
+GROUP BY a.id
+ORDER BY a.id;
+
+-- name: SumEntriesByKind :many
+SELECT
+  a.currency,
+  e.kind,
+  COUNT(*)::bigint AS entries,
+  COALESCE(SUM(e.amount), 0)::bigint AS total
+FROM entries e
+JOIN accounts a ON a.id = e.account_id
+WHERE e.created_at >= sqlc.arg(period_start)::timestamptz
+  AND e.created_at < sqlc.arg(period_end)::timestamptz
+GROUP BY a.currency, e.kind
+ORDER BY a.currency, e.kind;
@@ -0,0 +1,51 @@
+-- name: ListAccountActivity :many
+-- Everything that happened on an account, newest first: its transfers, in
+-- any status, and its other entries such as deposits, withdrawals and
+-- interest. Transfer entries are left out as the transfer stands for them.
+-- Amounts are signed from the account's side, in the currency given, and
+-- balance_after is what the row left on the account, null for transfers
+-- that moved no money. Rows are ordered by created_at, source and id, so a
+-- page can start after any row; a NULL before_time starts from the latest
+SELECT source, id, kind, amount, currency, status, counterparty_account_id, memo, reference, balance_after, created_at
+FROM (
+  SELECT
+    'transfer'::varchar AS source,
+    t.id,
+    'transfer'::varchar AS kind,
+    (CASE WHEN t.from_account_id = sqlc.arg(account_id)::bigint THEN -t.amount ELSE t.amount END)::bigint AS amount,
+    fa.currency,
+    t.status,
+    (CASE WHEN t.from_account_id = sqlc.arg(account_id)::bigint THEN t.to_account_id ELSE t.from_account_id END)::bigint AS counterparty_account_id,
+    COALESCE(t.memo, '')::varchar AS memo,
+    ''::varchar AS reference,
+    (SELECT e.balance_after FROM entries e
+      WHERE e.transfer_id = t.id
+        AND e.account_id = sqlc.arg(account_id)::bigint) AS balance_after,
+    t.created_at
+  FROM transfers t
+  JOIN accounts fa ON fa.id = t.from_account_id
+  WHERE t.from_account_id = sqlc.arg(account_id)::bigint
+     OR t.to_account_id = sqlc.arg(account_id)::bigint
+  UNION ALL
+  SELECT
+    'entry'::varchar AS source,
+    e.id,
+    e.kind,
+    e.amount,
+    a.currency,
+    'completed'::varchar AS status,
+    0::bigint AS counterparty_account_id,
+    ''::varchar AS memo,
+    COALESCE(d.reference, '')::varchar AS reference,
+    e.balance_after,
+    e.created_at
+  FROM entries e
+  JOIN accounts a ON a.id = e.account_id
+  LEFT JOIN external_deposits d ON d.entry_id = e.id
+  WHERE e.account_id = sqlc.arg(account_id)::bigint
+    AND e.kind <> 'transfer'
+) activity
+WHERE sqlc.narg(before_time)::timestamptz IS NULL
+   OR (created_at, source, id) < (sqlc.narg(before_time)::timestamptz, sqlc.arg(before_source)::varchar, sqlc.arg(before_id)::bigint)
+ORDER BY created_at DESC, source DESC, id DESC
+LIMIT sqlc.arg(page_limit)::int;
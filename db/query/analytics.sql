@@ -0,0 +1,61 @@
+-- name: GetAccountSummary :one
+-- Money in and out of an account in [from_time, to_time), from its entries,
+-- and how many completed transfers it took part in
+SELECT
+  COALESCE(SUM(e.amount) FILTER (WHERE e.amount > 0), 0)::bigint AS inflow,
+  COALESCE(-SUM(e.amount) FILTER (WHERE e.amount < 0), 0)::bigint AS outflow,
+  COALESCE(SUM(e.amount), 0)::bigint AS net_change,
+  (
+    SELECT COUNT(*) FROM transfers t
+    WHERE (t.from_account_id = sqlc.arg(account_id)::bigint OR t.to_account_id = sqlc.arg(account_id)::bigint)
+      AND t.status = 'completed'
+      AND t.created_at >= sqlc.arg(from_time)::timestamptz
+      AND t.created_at < sqlc.arg(to_time)::timestamptz
+  )::bigint AS transfer_count
+FROM entries e
+WHERE e.account_id = sqlc.arg(account_id)::bigint
+  AND e.created_at >= sqlc.arg(from_time)::timestamptz
+  AND e.created_at < sqlc.arg(to_time)::timestamptz;
+
+-- name: ListTopCounterparties :many
+-- The accounts an account exchanged the most money with in
+-- [from_time, to_time), over completed transfers. Amounts are those of the
+-- transfers, in the currency of the sending account
+SELECT
+  c.id AS account_id,
+  c.owner,
+  c.currency,
+  COUNT(*)::bigint AS transfer_count,
+  COALESCE(SUM(t.amount) FILTER (WHERE t.from_account_id = sqlc.arg(account_id)::bigint), 0)::bigint AS sent,
+  COALESCE(SUM(t.amount) FILTER (WHERE t.to_account_id = sqlc.arg(account_id)::bigint), 0)::bigint AS received
+FROM transfers t
+JOIN accounts c ON c.id = CASE WHEN t.from_account_id = sqlc.arg(account_id)::bigint THEN t.to_account_id ELSE t.from_account_id END
+WHERE (t.from_account_id = sqlc.arg(account_id)::bigint OR t.to_account_id = sqlc.arg(account_id)::bigint)
+  AND t.status = 'completed'
+  AND t.created_at >= sqlc.arg(from_time)::timestamptz
+  AND t.created_at < sqlc.arg(to_time)::timestamptz
+GROUP BY c.id, c.owner, c.currency
+ORDER BY SUM(t.amount) DESC, c.id
+LIMIT sqlc.arg(top)::int;
+
+-- name: ListSpendingByCounterparty :many
+-- Where a user's money went in [from_time, to_time): completed transfers from
+-- any of their accounts to other users, grouped by the receiving user and the
+-- currency the money left in. Moves between the user's own accounts are left
+-- out
+SELECT
+  r.owner AS counterparty,
+  f.currency,
+  COUNT(*)::bigint AS transfer_count,
+  SUM(t.amount)::bigint AS total
+FROM transfers t
+JOIN accounts f ON f.id = t.from_account_id
+JOIN accounts r ON r.id = t.to_account_id
+WHERE f.owner = sqlc.arg(owner)
+  AND r.owner <> sqlc.arg(owner)
+  AND t.status = 'completed'
+  AND t.created_at >= sqlc.arg(from_time)::timestamptz
+  AND t.created_at < sqlc.arg(to_time)::timestamptz
+GROUP BY r.owner, f.currency
+ORDER BY SUM(t.amount) DESC, r.owner, f.currency
+LIMIT sqlc.arg(top)::int;
@@ -0,0 +1,41 @@
+-- name: CreateAuditLog :one
+-- before and after are stored as JSON null when not given
+INSERT INTO audit_logs (
+  actor,
+  impersonator,
+  action,
+  resource,
+  client_ip,
+  request_id,
+  before,
+  after
+) VALUES (
+  sqlc.arg(actor),
+  sqlc.narg(impersonator),
+  sqlc.arg(action),
+  sqlc.arg(resource),
+  sqlc.arg(client_ip),
+  sqlc.arg(request_id),
+  COALESCE(sqlc.arg(before)::jsonb, 'null'),
+  COALESCE(sqlc.arg(after)::jsonb, 'null')
+) RETURNING *;
+
+-- name: ListAuditLogs :many
+-- Everything a user did, and everything done while impersonating them or as them
+SELECT * FROM audit_logs
+WHERE actor = $1 OR impersonator = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: SearchAuditLogs :many
+-- Newest first. A before_id of 0 starts from the latest entry, and every
+-- other filter is off when empty. actor matches impersonators too
+SELECT * FROM audit_logs
+WHERE (sqlc.arg(before_id)::bigint = 0 OR id < sqlc.arg(before_id)::bigint)
+  AND (sqlc.arg(actor)::varchar = '' OR actor = sqlc.arg(actor)::varchar OR impersonator = sqlc.arg(actor)::varchar)
+  AND (sqlc.arg(action)::varchar = '' OR action = sqlc.arg(action)::varchar)
+  AND (sqlc.arg(resource)::varchar = '' OR resource = sqlc.arg(resource)::varchar)
+  AND (sqlc.arg(request_id)::varchar = '' OR request_id = sqlc.arg(request_id)::varchar)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,18 @@
+-- name: CreateBalanceAdjustment :one
+INSERT INTO balance_adjustments (
+  account_id,
+  entry_id,
+  amount,
+  reason,
+  adjusted_by
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListBalanceAdjustments :many
+-- Newest first
+SELECT * FROM balance_adjustments
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,37 @@
+-- name: CreateBalanceSnapshots :many
+-- Snapshots the closing balances of a page of the accounts that existed on
+-- day, worked out backwards from the current balances. Money set aside in a
+-- pot is booked as a pot entry, so pot balances are worked out the same way.
+-- Taking a day again replaces its snapshots
+INSERT INTO account_balance_snapshots (
+  account_id,
+  day,
+  balance,
+  pot_balance
+)
+SELECT
+  a.id,
+  sqlc.arg(day)::date,
+  (a.balance - COALESCE(SUM(e.amount), 0))::bigint,
+  (COALESCE((SELECT SUM(p.balance) FROM pots p WHERE p.account_id = a.id), 0)
+    + COALESCE(SUM(e.amount) FILTER (WHERE e.kind = 'pot'), 0))::bigint
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+  AND e.created_at >= sqlc.arg(day)::date + 1
+WHERE a.id > sqlc.arg(after_id)
+  AND a.created_at < sqlc.arg(day)::date + 1
+GROUP BY a.id
+ORDER BY a.id
+LIMIT sqlc.arg(page_limit)::int
+ON CONFLICT (account_id, day) DO UPDATE
+SET balance = EXCLUDED.balance,
+  pot_balance = EXCLUDED.pot_balance,
+  created_at = now()
+RETURNING account_id;
+
+-- name: ListBalanceSnapshots :many
+SELECT * FROM account_balance_snapshots
+WHERE account_id = sqlc.arg(account_id)
+  AND day >= sqlc.arg(from_day)::date
+  AND day <= sqlc.arg(to_day)::date
+ORDER BY day;
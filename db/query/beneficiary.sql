@@ -0,0 +1,26 @@
+-- name: CreateBeneficiary :one
+INSERT INTO beneficiaries (
+  owner,
+  account_id,
+  nickname
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetBeneficiary :one
+SELECT * FROM beneficiaries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListBeneficiaries :many
+-- The payee's name and currency come from the account, so they stay current
+SELECT b.id, b.account_id, b.nickname, a.owner AS account_owner, a.currency, b.created_at
+FROM beneficiaries b
+JOIN accounts a ON a.id = b.account_id
+WHERE b.owner = sqlc.arg(owner)
+  AND b.id > sqlc.arg(after_id)
+ORDER BY b.id
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: DeleteBeneficiary :exec
+DELETE FROM beneficiaries
+WHERE id = $1;
@@ -0,0 +1,54 @@
+-- name: CreateEmailJob :one
+INSERT INTO email_jobs (
+  username,
+  kind,
+  recipient,
+  subject,
+  body
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: CountEmailJobsSince :one
+SELECT COUNT(*)::bigint AS jobs FROM email_jobs
+WHERE username = sqlc.arg(username)
+  AND kind = sqlc.arg(kind)
+  AND created_at >= sqlc.arg(since);
+
+-- name: ClaimEmailJobs :many
+-- SKIP LOCKED lets several workers poll without handing out a job twice
+UPDATE email_jobs
+SET attempts = attempts + 1,
+    locked_until = sqlc.arg(locked_until)
+WHERE id IN (
+  SELECT id FROM email_jobs
+  WHERE status = 'pending'
+    AND locked_until <= sqlc.arg(now)
+  ORDER BY id
+  LIMIT sqlc.arg(batch_size)::int
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkEmailJobSent :exec
+UPDATE email_jobs
+SET status = 'sent',
+    sent_at = now(),
+    last_error = NULL
+WHERE id = $1;
+
+-- name: MarkEmailJobFailed :exec
+-- Failed jobs either wait until retry_at or are given up on for good
+UPDATE email_jobs
+SET status = sqlc.arg(status),
+    last_error = sqlc.arg(last_error),
+    locked_until = sqlc.arg(retry_at)
+WHERE id = sqlc.arg(id);
+
+-- name: GetEmailQueueHealth :one
+-- Jobs that are due but not picked up show that no worker is polling
+SELECT COUNT(*)::bigint AS due_jobs,
+       COALESCE(EXTRACT(EPOCH FROM now() - MIN(locked_until)), 0)::bigint AS oldest_due_seconds
+FROM email_jobs
+WHERE status = 'pending'
+  AND locked_until <= now();
@@ -1,10 +1,14 @@
 
 -- name: CreateEntry :one
+-- Entries are booked once the balance of the account has been changed, in
+-- the same transaction, so balance_after is the balance the change left
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id,
+  balance_after
 ) VALUES (
-  $1, $2
+  $1, $2, $3, $4
 ) RETURNING *;
 
 -- name: GetEntry :one
@@ -16,4 +20,133 @@ SELECT * FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
-OFFSET $3;
\ No newline at end of file
+OFFSET $3;
+
+-- name: CreateAdjustingEntry :one
+-- Late corrections to a closed period are posted in the open period and
+-- point back at the period they correct
+INSERT INTO entries (
+  account_id,
+  amount,
+  kind,
+  adjusts_period,
+  balance_after
+) VALUES (
+  $1, $2, 'adjustment', $3, $4
+) RETURNING *;
+
+-- name: ListAdjustingEntries :many
+SELECT * FROM entries
+WHERE adjusts_period = $1
+ORDER BY id;
+
+-- name: ListEntriesBetween :many
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+ORDER BY id;
+
+-- name: CreateEntryOfKind :one
+INSERT INTO entries (
+  account_id,
+  amount,
+  kind,
+  balance_after
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: CreateFeeEntry :one
+-- Fees are booked with the transfer they were charged on
+INSERT INTO entries (
+  account_id,
+  amount,
+  kind,
+  transfer_id,
+  balance_after
+) VALUES (
+  $1, $2, 'fee', $3, $4
+) RETURNING *;
+
+-- name: ListDailyBalances :many
+-- An account's opening balance is not an entry, so closing balances are
+-- worked out backwards from a later one: the first snapshot on or after
+-- to_day, or the current balance when there's none yet. Only the entries
+-- between from_day and that balance are read
+WITH anchor AS (
+  SELECT balance, until FROM (
+    SELECT s.balance, (s.day + 1)::timestamptz AS until
+    FROM account_balance_snapshots s
+    WHERE s.account_id = sqlc.arg(account_id)
+      AND s.day >= sqlc.arg(to_day)::date
+    UNION ALL
+    SELECT a.balance, 'infinity'::timestamptz
+    FROM accounts a
+    WHERE a.id = sqlc.arg(account_id)
+  ) b
+  ORDER BY until
+  LIMIT 1
+), daily AS (
+  SELECT date_trunc('day', created_at)::date AS day, SUM(amount)::bigint AS net
+  FROM entries
+  WHERE account_id = sqlc.arg(account_id)
+    AND created_at >= sqlc.arg(from_day)::date
+    AND created_at < (SELECT until FROM anchor)
+  GROUP BY 1
+)
+SELECT
+  d.day::date AS day,
+  (a.balance - COALESCE((SELECT SUM(net) FROM daily WHERE daily.day > d.day), 0))::bigint AS balance
+FROM anchor a
+CROSS JOIN generate_series(sqlc.arg(from_day)::date, sqlc.arg(to_day)::date, interval '1 day') AS d(day)
+ORDER BY d.day;
+
+-- name: ListEntriesAfter :many
+-- A non-empty tag keeps the entries the owner tagged with it
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND id > sqlc.arg(after_id)
+  AND (sqlc.arg(tag)::varchar = '' OR EXISTS (
+    SELECT 1 FROM tags g
+    WHERE g.entry_id = entries.id
+      AND g.owner = sqlc.arg(owner)::varchar
+      AND g.tag = sqlc.arg(tag)::varchar
+  ))
+ORDER BY id
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: ListEntriesByAccountBefore :many
+-- Newest first, paged like ListTransfersByAccountBefore
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND (sqlc.narg(before_created_at)::timestamptz IS NULL
+    OR (created_at, id) < (sqlc.narg(before_created_at)::timestamptz, sqlc.arg(before_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetStatementBalances :one
+-- The balance of an account at from_time and at to_time, worked out
+-- backwards from a later balance like ListDailyBalances
+WITH anchor AS (
+  SELECT balance, until FROM (
+    SELECT s.balance, (s.day + 1)::timestamptz AS until
+    FROM account_balance_snapshots s
+    WHERE s.account_id = sqlc.arg(account_id)
+      AND (s.day + 1)::timestamptz >= sqlc.arg(to_time)::timestamptz
+    UNION ALL
+    SELECT a.balance, 'infinity'::timestamptz
+    FROM accounts a
+    WHERE a.id = sqlc.arg(account_id)
+  ) b
+  ORDER BY until
+  LIMIT 1
+)
+SELECT
+  (a.balance - COALESCE(SUM(e.amount) FILTER (WHERE e.created_at >= sqlc.arg(from_time)::timestamptz), 0))::bigint AS opening_balance,
+  (a.balance - COALESCE(SUM(e.amount) FILTER (WHERE e.created_at >= sqlc.arg(to_time)::timestamptz), 0))::bigint AS closing_balance
+FROM anchor a
+LEFT JOIN entries e ON e.account_id = sqlc.arg(account_id)
+  AND e.created_at >= sqlc.arg(from_time)::timestamptz
+  AND e.created_at < a.until
+GROUP BY a.balance;
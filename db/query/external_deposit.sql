@@ -0,0 +1,22 @@
+-- name: CreateExternalDeposit :one
+-- Returns no row when the reference was already recorded. A concurrent
+-- insert of the same reference waits for the first transaction to finish
+INSERT INTO external_deposits (
+  reference,
+  account_id,
+  amount
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (reference) DO NOTHING
+RETURNING *;
+
+-- name: GetExternalDepositByReference :one
+SELECT * FROM external_deposits
+WHERE reference = $1 LIMIT 1;
+
+-- name: SetExternalDepositEntry :one
+UPDATE external_deposits
+SET entry_id = sqlc.arg(entry_id)
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,25 @@
+-- name: CreateFxRate :one
+INSERT INTO fx_rates (
+  base,
+  quote,
+  rate,
+  valid_from,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: ListFxRates :many
+-- The rate of every pair in force at a time.
+SELECT DISTINCT ON (base, quote) id, base, quote, rate, valid_from, created_by, created_at
+FROM fx_rates
+WHERE valid_from <= sqlc.arg(at)
+ORDER BY base, quote, valid_from DESC;
+
+-- name: ListFxRateHistory :many
+SELECT * FROM fx_rates
+WHERE base = $1 AND quote = $2
+ORDER BY valid_from DESC
+LIMIT $3
+OFFSET $4;
@@ -0,0 +1,45 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+  account_id,
+  amount,
+  memo
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetHoldForUpdate :one
+-- Locks the hold so it is released or captured only once
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListActiveHolds :many
+SELECT * FROM holds
+WHERE account_id = $1
+  AND status = 'active'
+ORDER BY id;
+
+-- name: ResolveHold :one
+-- Ends an active hold as released or captured. No row means it had already
+-- ended
+UPDATE holds
+SET status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id),
+    resolved_at = now()
+WHERE id = sqlc.arg(id)
+  AND status = 'active'
+RETURNING *;
+
+-- name: AddAccountHeld :one
+-- A positive amount places a hold, a negative one ends it. The accounts
+-- trigger refuses holds past what is available
+UPDATE accounts
+SET held = held + sqlc.arg(amount),
+    version = version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,58 @@
+-- name: ClaimIdempotencyKey :one
+-- Returns no row when the key is already taken. A concurrent claim waits for
+-- the first transaction to finish instead of failing
+INSERT INTO idempotency_keys (
+  username,
+  key,
+  request_hash
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: SetIdempotencyKeyResponse :exec
+UPDATE idempotency_keys
+SET response = $3
+WHERE username = $1 AND key = $2;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE username = $1 AND key = $2
+LIMIT 1;
+
+-- name: StartIdempotentRequest :one
+-- Claims the key for a request that isn't made in one transaction, until
+-- FinishIdempotentRequest stores its response. A claim still in progress from
+-- before stale_before was left by a server that died mid-request, so a retry
+-- of the same request takes it over. Returns no row when the key is taken
+INSERT INTO idempotency_keys (
+  username,
+  key,
+  request_hash,
+  status
+) VALUES (
+  sqlc.arg(username), sqlc.arg(key), sqlc.arg(request_hash), 'in_progress'
+)
+ON CONFLICT (username, key) DO UPDATE
+SET created_at = now()
+WHERE idempotency_keys.status = 'in_progress'
+  AND idempotency_keys.created_at < sqlc.arg(stale_before)
+  AND idempotency_keys.request_hash = EXCLUDED.request_hash
+RETURNING *;
+
+-- name: FinishIdempotentRequest :exec
+UPDATE idempotency_keys
+SET status = 'completed',
+    response_status = sqlc.arg(response_status),
+    response = sqlc.arg(response)
+WHERE username = sqlc.arg(username)
+  AND key = sqlc.arg(key)
+  AND status = 'in_progress';
+
+-- name: DeleteIdempotentRequest :exec
+-- Releases the key of a request that failed, so it can be retried
+DELETE FROM idempotency_keys
+WHERE username = sqlc.arg(username)
+  AND key = sqlc.arg(key)
+  AND status = 'in_progress';
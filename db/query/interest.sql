@@ -0,0 +1,58 @@
+-- name: ListInterestEligibleAccounts :many
+-- Customer accounts that are not closed, with the money in their pots
+-- counted in, and the tenant of the owner to look up the rate with. The
+-- balance is the closing balance of day when it has been snapshotted, and
+-- the current one otherwise
+SELECT a.id, a.currency, u.tenant,
+  COALESCE(s.balance + s.pot_balance,
+    a.balance + COALESCE((SELECT SUM(p.balance) FROM pots p WHERE p.account_id = a.id), 0))::bigint AS balance
+FROM accounts a
+JOIN users u ON u.username = a.owner
+LEFT JOIN account_balance_snapshots s ON s.account_id = a.id
+  AND s.day = sqlc.arg(day)::date
+WHERE NOT a.is_house
+  AND a.status <> 'closed'
+  AND a.id > sqlc.arg(after_id)
+ORDER BY a.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: CreateInterestAccrual :execrows
+-- A day is accrued once, so runs can be repeated safely
+INSERT INTO interest_accruals (
+  account_id,
+  accrual_date,
+  balance,
+  rate_bps,
+  amount_micros
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (account_id, accrual_date) DO NOTHING;
+
+-- name: ListUnpostedInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+  AND entry_id IS NULL
+ORDER BY accrual_date;
+
+-- name: ListAccountsWithUnpostedInterest :many
+SELECT DISTINCT account_id FROM interest_accruals
+WHERE entry_id IS NULL
+  AND accrual_date < sqlc.arg(before)
+  AND account_id > sqlc.arg(after_id)
+ORDER BY account_id
+LIMIT sqlc.arg(page_limit);
+
+-- name: SumUnpostedInterest :one
+SELECT COALESCE(SUM(amount_micros), 0)::bigint AS amount_micros
+FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id)
+  AND entry_id IS NULL
+  AND accrual_date < sqlc.arg(before);
+
+-- name: MarkInterestPosted :execrows
+UPDATE interest_accruals
+SET entry_id = sqlc.arg(entry_id)
+WHERE account_id = sqlc.arg(account_id)
+  AND entry_id IS NULL
+  AND accrual_date < sqlc.arg(before);
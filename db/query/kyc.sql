@@ -0,0 +1,45 @@
+-- name: CreateKycDocument :one
+INSERT INTO kyc_documents (
+  username,
+  document_type,
+  requested_tier,
+  file_name,
+  content_type,
+  content
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, username, document_type, requested_tier, file_name, content_type, status, reviewed_by, review_note, reviewed_at, created_at;
+
+-- name: GetKycDocument :one
+SELECT * FROM kyc_documents
+WHERE id = $1 LIMIT 1;
+
+-- name: GetKycDocumentForUpdate :one
+SELECT id, username, document_type, requested_tier, file_name, content_type, status, reviewed_by, review_note, reviewed_at, created_at FROM kyc_documents
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListKycDocuments :many
+-- Document content is never listed, only fetched one at a time for review
+SELECT id, username, document_type, requested_tier, file_name, content_type, status, reviewed_by, review_note, reviewed_at, created_at FROM kyc_documents
+WHERE username = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListKycDocumentsByStatus :many
+SELECT id, username, document_type, requested_tier, file_name, content_type, status, reviewed_by, review_note, reviewed_at, created_at FROM kyc_documents
+WHERE status = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: ReviewKycDocument :one
+UPDATE kyc_documents
+SET
+  status = $2,
+  reviewed_by = $3,
+  review_note = $4,
+  reviewed_at = now()
+WHERE id = $1
+RETURNING id, username, document_type, requested_tier, file_name, content_type, status, reviewed_by, review_note, reviewed_at, created_at;
@@ -0,0 +1,43 @@
+-- name: ListAccountBalanceMismatches :many
+-- Accounts whose balance isn't the sum of their entries, archived ones
+-- included
+SELECT
+  a.id,
+  a.currency,
+  a.balance,
+  (COALESCE(SUM(e.amount), 0) + COALESCE(x.total, 0))::bigint AS entry_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+LEFT JOIN archived_entry_totals x ON x.account_id = a.id
+WHERE a.id > sqlc.arg(after_id)
+GROUP BY a.id, x.total
+HAVING a.balance <> COALESCE(SUM(e.amount), 0) + COALESCE(x.total, 0)
+ORDER BY a.id
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: ListTransferEntryMismatches :many
+-- Completed transfers must have booked a debit of their amount on the sender
+-- and a credit on the recipient, plus a fee entry for the fee they kept, and
+-- nothing else. Cross-currency transfers only net to zero at their rate, so
+-- the credit is checked against to_amount. Transfers that moved no money must
+-- have no entries
+SELECT
+  t.id,
+  t.status,
+  COUNT(e.id)::bigint AS entry_count,
+  COALESCE(SUM(e.amount), 0)::bigint AS entry_total
+FROM transfers t
+LEFT JOIN entries e ON e.transfer_id = t.id
+WHERE t.id > sqlc.arg(after_id)
+  AND t.entries_linked
+GROUP BY t.id
+HAVING (t.status = 'completed' AND (
+    COUNT(e.id) FILTER (WHERE e.kind <> 'fee') <> 2
+    OR COUNT(e.id) FILTER (WHERE e.kind = 'fee') <> (COALESCE(t.fx_fee, 0) > 0)::int
+    OR COALESCE(SUM(e.amount) FILTER (WHERE e.kind = 'fee'), 0) <> COALESCE(t.fx_fee, 0)
+    OR COUNT(e.id) FILTER (WHERE e.account_id = t.from_account_id AND e.amount = -t.amount) <> 1
+    OR COUNT(e.id) FILTER (WHERE e.account_id = t.to_account_id AND e.amount = COALESCE(t.to_amount, t.amount)) <> 1
+  ))
+  OR (t.status <> 'completed' AND COUNT(e.id) <> 0)
+ORDER BY t.id
+LIMIT sqlc.arg(page_limit)::int;
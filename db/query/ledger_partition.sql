@@ -0,0 +1,9 @@
+-- name: CreateLedgerPartitions :exec
+-- Creates the transfers and entries partitions of the month that month
+-- falls in, unless they exist
+SELECT create_ledger_partitions(sqlc.arg(month)::date);
+
+-- name: ArchiveLedgerPartitions :many
+-- Detaches the transfers and entries partitions of the months that ended by
+-- cutoff into the archive schema
+SELECT archive_ledger_partitions(sqlc.arg(cutoff)::date)::varchar AS partition;
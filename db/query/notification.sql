@@ -0,0 +1,64 @@
+-- name: CreateNotification :one
+-- A delivery attempted right away is created with its first attempt made
+-- and locked_until set past it, so no sender retries it meanwhile
+INSERT INTO notifications (
+  username,
+  channel,
+  type,
+  payload,
+  status,
+  attempts,
+  locked_until,
+  sent_at
+) VALUES (
+  sqlc.arg(username), sqlc.arg(channel), sqlc.arg(type), sqlc.arg(payload),
+  sqlc.arg(status), sqlc.arg(attempts), sqlc.arg(locked_until),
+  CASE WHEN sqlc.arg(status)::varchar = 'sent' THEN now() END
+) RETURNING *;
+
+-- name: ListNotifications :many
+-- The in-app inbox of a user, newest first, without the types the user
+-- turned off. A before_id of 0 starts from the latest notification
+SELECT n.* FROM notifications n
+WHERE n.username = sqlc.arg(username)
+  AND n.channel = 'in_app'
+  AND (sqlc.arg(before_id)::bigint = 0 OR n.id < sqlc.arg(before_id)::bigint)
+  AND NOT EXISTS (
+    SELECT 1 FROM notification_preferences p
+    WHERE p.username = n.username
+      AND p.channel = n.channel
+      AND p.event_type = n.type
+      AND NOT p.enabled
+  )
+ORDER BY n.id DESC
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: ClaimNotifications :many
+-- SKIP LOCKED lets several workers poll without handing out a delivery twice
+UPDATE notifications
+SET attempts = attempts + 1,
+    locked_until = sqlc.arg(locked_until)
+WHERE id IN (
+  SELECT id FROM notifications
+  WHERE status = 'pending'
+    AND locked_until <= sqlc.arg(now)
+  ORDER BY id
+  LIMIT sqlc.arg(batch_size)::int
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkNotificationSent :exec
+UPDATE notifications
+SET status = 'sent',
+    sent_at = now(),
+    last_error = NULL
+WHERE id = $1;
+
+-- name: MarkNotificationFailed :exec
+-- Failed deliveries either wait until retry_at or are given up on for good
+UPDATE notifications
+SET status = sqlc.arg(status),
+    last_error = sqlc.arg(last_error),
+    locked_until = sqlc.arg(retry_at)
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,25 @@
+-- name: GetNotificationPreference :one
+SELECT * FROM notification_preferences
+WHERE username = $1
+  AND channel = $2
+  AND event_type = $3
+LIMIT 1;
+
+-- name: ListNotificationPreferences :many
+SELECT * FROM notification_preferences
+WHERE username = $1
+ORDER BY channel, event_type;
+
+-- name: UpsertNotificationPreference :one
+INSERT INTO notification_preferences (
+  username,
+  channel,
+  event_type,
+  enabled
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (username, channel, event_type) DO UPDATE
+SET enabled = EXCLUDED.enabled,
+    updated_at = now()
+RETURNING *;
@@ -0,0 +1,50 @@
+-- name: CreatePaymentRequest :one
+INSERT INTO payment_requests (
+  requester,
+  payer,
+  to_account_id,
+  amount,
+  note
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetPaymentRequest :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPaymentRequestForUpdate :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListIncomingPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE payer = sqlc.arg(payer)
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: ListOutgoingPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE requester = sqlc.arg(requester)
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: MarkPaymentRequestAccepted :one
+UPDATE payment_requests
+SET status = 'accepted',
+    transfer_id = sqlc.arg(transfer_id),
+    responded_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeclinePaymentRequest :one
+-- Returns no row once the request was answered
+UPDATE payment_requests
+SET status = 'declined',
+    responded_at = now()
+WHERE id = $1
+  AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,41 @@
+-- name: CreatePot :one
+INSERT INTO pots (
+  account_id,
+  name
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: ListPots :many
+SELECT * FROM pots
+WHERE account_id = $1
+ORDER BY id;
+
+-- name: GetPotForUpdate :one
+-- Scoped to the account, so a pot of another account reads as missing
+SELECT * FROM pots
+WHERE id = sqlc.arg(id)
+  AND account_id = sqlc.arg(account_id)
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: AddPotBalance :one
+UPDATE pots
+SET balance = balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SumPotBalances :one
+SELECT COALESCE(SUM(balance), 0)::bigint AS total
+FROM pots
+WHERE account_id = $1;
+
+-- name: CreatePotMove :one
+INSERT INTO pot_moves (
+  account_id,
+  from_pot_id,
+  to_pot_id,
+  amount
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
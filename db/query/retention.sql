@@ -0,0 +1,81 @@
+-- name: CreateLoginEvent :one
+INSERT INTO login_events (
+  username,
+  user_agent,
+  client_ip
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: UpsertRetentionRule :one
+INSERT INTO retention_rules (
+  target,
+  retain_days,
+  enabled,
+  updated_by
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (target) DO UPDATE
+SET retain_days = EXCLUDED.retain_days,
+    enabled = EXCLUDED.enabled,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: ListRetentionRules :many
+SELECT * FROM retention_rules
+ORDER BY target;
+
+-- name: DeleteLoginEventsBefore :execrows
+DELETE FROM login_events
+WHERE created_at < sqlc.arg(cutoff);
+
+-- name: DeleteExpiredSessionsBefore :execrows
+-- Only sessions that already expired before the cutoff are removed
+DELETE FROM sessions
+WHERE expires_at < sqlc.arg(cutoff);
+
+-- name: AnonymizeKycDocumentsBefore :execrows
+-- Reviewed documents keep their decision but lose the uploaded file
+UPDATE kyc_documents
+SET
+  file_name = '',
+  content = ''
+WHERE status <> 'pending'
+  AND reviewed_at < sqlc.arg(cutoff)::timestamptz
+  AND file_name <> '';
+
+-- name: CreateRetentionRun :one
+INSERT INTO retention_runs (
+  dry_run,
+  run_by
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: CreateRetentionRunItem :one
+INSERT INTO retention_run_items (
+  run_id,
+  target,
+  action,
+  cutoff,
+  affected
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListRetentionRuns :many
+SELECT * FROM retention_runs
+ORDER BY id DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: ListRetentionRunItems :many
+SELECT * FROM retention_run_items
+WHERE run_id = $1
+ORDER BY id;
+
+-- name: GetRetentionRun :one
+SELECT * FROM retention_runs
+WHERE id = $1 LIMIT 1;
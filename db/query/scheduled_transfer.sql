@@ -0,0 +1,62 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+  owner,
+  from_account_id,
+  to_account_id,
+  amount,
+  execute_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: ListScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE owner = sqlc.arg(owner)
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: CancelScheduledTransfer :one
+-- Returns no row once the transfer has run or is being run
+UPDATE scheduled_transfers
+SET status = 'cancelled'
+WHERE id = $1
+  AND status = 'pending'
+  AND locked_until <= now()
+RETURNING *;
+
+-- name: ClaimDueScheduledTransfers :many
+-- SKIP LOCKED lets several schedulers poll without running a transfer twice
+UPDATE scheduled_transfers
+SET attempts = attempts + 1,
+    locked_until = sqlc.arg(locked_until)
+WHERE id IN (
+  SELECT id FROM scheduled_transfers
+  WHERE status = 'pending'
+    AND execute_at <= sqlc.arg(now)
+    AND locked_until <= sqlc.arg(now)
+  ORDER BY execute_at
+  LIMIT sqlc.arg(batch_size)::int
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkScheduledTransferSucceeded :exec
+UPDATE scheduled_transfers
+SET status = 'succeeded',
+    transfer_id = sqlc.arg(transfer_id),
+    executed_at = now(),
+    last_error = NULL
+WHERE id = sqlc.arg(id);
+
+-- name: MarkScheduledTransferFailed :exec
+-- Failed transfers either wait until retry_at or are given up on for good
+UPDATE scheduled_transfers
+SET status = sqlc.arg(status),
+    last_error = sqlc.arg(last_error),
+    locked_until = sqlc.arg(retry_at)
+WHERE id = sqlc.arg(id);
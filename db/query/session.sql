@@ -13,4 +13,10 @@ INSERT INTO sessions (
 
 -- name: GetSession :one
 SELECT * FROM sessions
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+WHERE id = $1 LIMIT 1;
+-- name: BlockSession :one
+-- A blocked session can't renew access tokens, even before it expires.
+UPDATE sessions
+SET is_blocked = true
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,23 @@
+-- name: UpsertSetting :one
+INSERT INTO settings (
+  tenant,
+  currency,
+  key,
+  value,
+  updated_by
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (tenant, currency, key) DO UPDATE
+SET value = EXCLUDED.value,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: ListSettings :many
+SELECT * FROM settings
+ORDER BY key, tenant, currency;
+
+-- name: DeleteSetting :exec
+DELETE FROM settings
+WHERE id = $1;
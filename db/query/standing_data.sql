@@ -0,0 +1,24 @@
+-- name: CreateStandingDataChange :one
+INSERT INTO standing_data_changes (
+  entity_type,
+  entity_id,
+  field,
+  old_value,
+  new_value,
+  changed_by,
+  reverts_change_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetStandingDataChange :one
+SELECT * FROM standing_data_changes
+WHERE id = $1 LIMIT 1;
+
+-- name: ListStandingDataChanges :many
+-- Newest first so the admin timeline reads top-down from the current value
+SELECT * FROM standing_data_changes
+WHERE entity_type = $1 AND entity_id = $2
+ORDER BY id DESC
+LIMIT $3
+OFFSET $4;
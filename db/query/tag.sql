@@ -0,0 +1,76 @@
+-- name: AddTransferTag :one
+-- Adding a tag twice is a no-op
+INSERT INTO tags (
+  owner,
+  tag,
+  transfer_id
+) VALUES (
+  sqlc.arg(owner), sqlc.arg(tag), sqlc.arg(transfer_id)::bigint
+)
+ON CONFLICT (owner, transfer_id, tag) DO UPDATE
+SET tag = EXCLUDED.tag
+RETURNING *;
+
+-- name: AddEntryTag :one
+INSERT INTO tags (
+  owner,
+  tag,
+  entry_id
+) VALUES (
+  sqlc.arg(owner), sqlc.arg(tag), sqlc.arg(entry_id)::bigint
+)
+ON CONFLICT (owner, entry_id, tag) DO UPDATE
+SET tag = EXCLUDED.tag
+RETURNING *;
+
+-- name: DeleteTransferTag :execrows
+DELETE FROM tags
+WHERE owner = sqlc.arg(owner)
+  AND transfer_id = sqlc.arg(transfer_id)::bigint
+  AND tag = sqlc.arg(tag);
+
+-- name: DeleteEntryTag :execrows
+DELETE FROM tags
+WHERE owner = sqlc.arg(owner)
+  AND entry_id = sqlc.arg(entry_id)::bigint
+  AND tag = sqlc.arg(tag);
+
+-- name: ListTransferTags :many
+SELECT tag FROM tags
+WHERE owner = sqlc.arg(owner)
+  AND transfer_id = sqlc.arg(transfer_id)::bigint
+ORDER BY tag;
+
+-- name: ListEntryTags :many
+SELECT tag FROM tags
+WHERE owner = sqlc.arg(owner)
+  AND entry_id = sqlc.arg(entry_id)::bigint
+ORDER BY tag;
+
+-- name: ListTagSpending :many
+-- Money out of the owner's accounts per tag and currency: tagged transfers
+-- they sent, in the source currency, and tagged debit entries
+WITH spent AS (
+  SELECT g.tag, a.currency, t.amount
+  FROM tags g
+  JOIN transfers t ON t.id = g.transfer_id
+  JOIN accounts a ON a.id = t.from_account_id
+  WHERE g.owner = sqlc.arg(owner)
+    AND a.owner = sqlc.arg(owner)
+    AND t.status = 'completed'
+  UNION ALL
+  SELECT g.tag, a.currency, -e.amount
+  FROM tags g
+  JOIN entries e ON e.id = g.entry_id
+  JOIN accounts a ON a.id = e.account_id
+  WHERE g.owner = sqlc.arg(owner)
+    AND e.amount < 0
+)
+SELECT
+  tag,
+  currency,
+  COUNT(*)::bigint AS count,
+  SUM(amount)::bigint AS total
+FROM spent
+GROUP BY tag, currency
+ORDER BY tag, currency;
@@ -2,15 +2,61 @@
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  memo,
+  status
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: CreateFXTransfer :one
+-- A cross-currency transfer, recording the conversion it was made at. Its
+-- amount is the from_amount
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  memo,
+  status,
+  from_amount,
+  to_amount,
+  rate,
+  fx_fee
+) VALUES (
+  sqlc.arg(from_account_id), sqlc.arg(to_account_id), sqlc.arg(from_amount)::bigint, sqlc.arg(memo), sqlc.arg(status),
+  sqlc.arg(from_amount)::bigint, sqlc.arg(to_amount)::bigint, sqlc.arg(rate)::float8, sqlc.arg(fx_fee)::bigint
 ) RETURNING *;
 
 -- name: GetTransfer :one
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateTransferStatusChange :one
+INSERT INTO transfer_status_changes (
+  transfer_id,
+  from_status,
+  to_status,
+  reason
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListTransferStatusChanges :many
+SELECT * FROM transfer_status_changes
+WHERE transfer_id = ANY(sqlc.arg(transfer_ids)::bigint[])
+ORDER BY transfer_id, id;
+
 -- name: ListTransfers :many
 SELECT * FROM transfers
 WHERE 
@@ -18,4 +64,81 @@ WHERE
     to_account_id = $2
 ORDER BY id
 LIMIT $3
-OFFSET $4;
\ No newline at end of file
+OFFSET $4;
+-- name: ListTransfersByAccountBefore :many
+-- Newest first, by (created_at, id) so the order is stable for transfers
+-- made in the same instant. The first page has a NULL before_created_at, the
+-- next ones the created_at and id of the last transfer of the page before
+SELECT * FROM transfers
+WHERE (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id))
+  AND (sqlc.narg(before_created_at)::timestamptz IS NULL
+    OR (created_at, id) < (sqlc.narg(before_created_at)::timestamptz, sqlc.arg(before_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListOwnerTransfers :many
+-- Newest first. A before_id of 0 starts from the latest transfer. A non-empty
+-- search keeps transfers whose memo matches it as an ILIKE pattern, a
+-- non-empty tag those the owner tagged with it. The other filters are off at
+-- their zero value: created_at in [from_time, to_time), the amount between
+-- min_amount and max_amount, direction sent or received from the owner's
+-- point of view, a counterparty account on either side and a currency on
+-- either side. to_amount is what the recipient got, in its currency. rate
+-- and fx_fee are 0 unless the conversion of a cross-currency transfer was
+-- recorded, which it wasn't before they were added
+SELECT
+  t.id,
+  t.from_account_id,
+  t.to_account_id,
+  t.amount,
+  fa.currency AS from_currency,
+  ta.currency AS to_currency,
+  COALESCE(t.memo, '')::varchar AS memo,
+  t.status,
+  COALESCE(t.to_amount, t.amount)::bigint AS to_amount,
+  COALESCE(t.rate, 0)::float8 AS rate,
+  COALESCE(t.fx_fee, 0)::bigint AS fx_fee,
+  t.created_at
+FROM transfers t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+WHERE (fa.owner = sqlc.arg(owner) OR ta.owner = sqlc.arg(owner))
+  AND (sqlc.arg(before_id)::bigint = 0 OR t.id < sqlc.arg(before_id)::bigint)
+  AND (sqlc.arg(search)::varchar = '' OR t.memo ILIKE sqlc.arg(search)::varchar)
+  AND (sqlc.arg(tag)::varchar = '' OR EXISTS (
+    SELECT 1 FROM tags g
+    WHERE g.transfer_id = t.id
+      AND g.owner = sqlc.arg(owner)
+      AND g.tag = sqlc.arg(tag)::varchar
+  ))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)::timestamptz)
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)::timestamptz)
+  AND (sqlc.arg(min_amount)::bigint = 0 OR t.amount >= sqlc.arg(min_amount)::bigint)
+  AND (sqlc.arg(max_amount)::bigint = 0 OR t.amount <= sqlc.arg(max_amount)::bigint)
+  AND (sqlc.arg(direction)::varchar = ''
+    OR (sqlc.arg(direction)::varchar = 'sent' AND fa.owner = sqlc.arg(owner))
+    OR (sqlc.arg(direction)::varchar = 'received' AND ta.owner = sqlc.arg(owner)))
+  AND (sqlc.arg(counterparty_account_id)::bigint = 0
+    OR t.from_account_id = sqlc.arg(counterparty_account_id)::bigint
+    OR t.to_account_id = sqlc.arg(counterparty_account_id)::bigint)
+  AND (sqlc.arg(currency)::varchar = '' OR fa.currency = sqlc.arg(currency)::varchar OR ta.currency = sqlc.arg(currency)::varchar)
+ORDER BY t.id DESC
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: SearchTransfers :many
+-- Admin lookup across all owners, newest first. Each filter is off at its
+-- zero value: an account on either side, an owner on either side, created_at
+-- in [from_time, to_time) and the amount from min_amount up
+SELECT t.* FROM transfers t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+WHERE (sqlc.arg(account_id)::bigint = 0
+    OR t.from_account_id = sqlc.arg(account_id)::bigint
+    OR t.to_account_id = sqlc.arg(account_id)::bigint)
+  AND (sqlc.arg(owner)::varchar = '' OR fa.owner = sqlc.arg(owner)::varchar OR ta.owner = sqlc.arg(owner)::varchar)
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)::timestamptz)
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)::timestamptz)
+  AND (sqlc.arg(min_amount)::bigint = 0 OR t.amount >= sqlc.arg(min_amount)::bigint)
+ORDER BY t.id DESC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
@@ -0,0 +1,45 @@
+-- name: UpsertTransferLimit :one
+INSERT INTO transfer_limits (
+  username,
+  currency,
+  max_single_transfer,
+  max_daily_total,
+  updated_by
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (username, currency) DO UPDATE
+SET max_single_transfer = EXCLUDED.max_single_transfer,
+    max_daily_total = EXCLUDED.max_daily_total,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetTransferLimit :one
+SELECT * FROM transfer_limits
+WHERE username = $1 AND currency = $2
+LIMIT 1;
+
+-- name: ListTransferLimits :many
+-- used_last_24h counts the transfers out of the user's account in the
+-- currency, the same total TransferTx checks max_daily_total against
+SELECT l.username, l.currency, l.max_single_transfer, l.max_daily_total,
+  COALESCE((
+    SELECT SUM(t.amount) FROM transfers t
+    JOIN accounts a ON a.id = t.from_account_id
+    WHERE a.owner = l.username
+      AND a.currency = l.currency
+      AND t.created_at > now() - interval '24 hours'
+      AND t.status <> 'failed'
+  ), 0)::bigint AS used_last_24h,
+  l.updated_by, l.updated_at
+FROM transfer_limits l
+WHERE l.username = $1
+ORDER BY l.currency;
+
+-- name: SumTransfersSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM transfers
+WHERE from_account_id = sqlc.arg(from_account_id)
+  AND created_at > sqlc.arg(since)
+  AND status <> 'failed';
@@ -12,4 +12,83 @@ INSERT INTO users (
 -- Direct primary key lookup ensures O(1) performance via B-tree index
 -- LIMIT 1 optimizes query planning - tells PostgreSQL to stop after first match
 SELECT * FROM users
-WHERE username = $1 LIMIT 1;
\ No newline at end of file
+WHERE username = $1 LIMIT 1;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdateUserEmail :one
+UPDATE users
+SET email = $2
+WHERE username = $1
+RETURNING *;
+
+-- name: UpdateUserFullName :one
+UPDATE users
+SET full_name = $2
+WHERE username = $1
+RETURNING *;
+
+-- name: UpdateUserKycTier :one
+UPDATE users
+SET kyc_tier = $2
+WHERE username = $1
+RETURNING *;
+
+-- name: UpdateUserPhoneNumber :one
+UPDATE users
+SET phone_number = $2
+WHERE username = $1
+RETURNING *;
+
+-- name: UpdateUserTotpSecret :one
+UPDATE users
+SET totp_secret = $2
+WHERE username = $1
+RETURNING *;
+
+-- name: UseUserTotpStep :execrows
+-- Only moves forward, so a code is accepted once however many requests race with it
+UPDATE users
+SET totp_last_step = sqlc.arg(step)
+WHERE username = sqlc.arg(username)
+  AND totp_last_step < sqlc.arg(step);
+
+-- name: RehashUserPassword :execrows
+-- Only replaces the hash it was computed from, so a concurrent password change wins
+UPDATE users
+SET hashed_password = sqlc.arg(new_hash)
+WHERE username = sqlc.arg(username)
+  AND hashed_password = sqlc.arg(old_hash);
+
+-- name: UpdateUser :one
+-- NULL params leave the column unchanged
+UPDATE users
+SET hashed_password = COALESCE(sqlc.narg(hashed_password), hashed_password),
+    password_changed_at = COALESCE(sqlc.narg(password_changed_at), password_changed_at),
+    full_name = COALESCE(sqlc.narg(full_name), full_name),
+    email = COALESCE(sqlc.narg(email), email),
+    phone_number = COALESCE(sqlc.narg(phone_number), phone_number)
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: UpdateUserStatus :one
+UPDATE users
+SET status = $2
+WHERE username = $1
+RETURNING *;
+
+-- name: SearchUsers :many
+-- Admin lookup. A non-empty search matches the username, email or full name
+-- as an ILIKE pattern, a non-empty status only users in it
+SELECT * FROM users
+WHERE (sqlc.arg(search)::varchar = ''
+    OR username ILIKE sqlc.arg(search)::varchar
+    OR email ILIKE sqlc.arg(search)::varchar
+    OR full_name ILIKE sqlc.arg(search)::varchar)
+  AND (sqlc.arg(status)::varchar = '' OR status = sqlc.arg(status)::varchar)
+ORDER BY username
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
@@ -0,0 +1,42 @@
+-- name: CreateWebauthnChallenge :one
+INSERT INTO webauthn_challenges (
+  id,
+  username,
+  ceremony,
+  challenge,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ConsumeWebauthnChallenge :one
+-- Challenges are single use: reading one deletes it
+DELETE FROM webauthn_challenges
+WHERE id = $1 AND ceremony = $2
+RETURNING *;
+
+-- name: CreateWebauthnCredential :one
+INSERT INTO webauthn_credentials (
+  username,
+  credential_id,
+  public_key,
+  sign_count,
+  name
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetWebauthnCredential :one
+SELECT * FROM webauthn_credentials
+WHERE credential_id = $1 LIMIT 1;
+
+-- name: ListWebauthnCredentials :many
+SELECT * FROM webauthn_credentials
+WHERE username = $1
+ORDER BY id;
+
+-- name: UpdateWebauthnCredentialSignCount :exec
+UPDATE webauthn_credentials
+SET sign_count = $2,
+    last_used_at = now()
+WHERE id = $1;
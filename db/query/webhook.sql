@@ -0,0 +1,81 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+  owner,
+  url,
+  secret,
+  event_types
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions
+WHERE owner = $1
+ORDER BY id;
+
+-- name: DeleteWebhookSubscription :execrows
+DELETE FROM webhook_subscriptions
+WHERE id = sqlc.arg(id)
+  AND owner = sqlc.arg(owner);
+
+-- name: EnqueueWebhookDeliveries :execrows
+-- Queues the event for every subscription of the owner that wants it
+INSERT INTO webhook_deliveries (
+  subscription_id,
+  event_type,
+  payload
+)
+SELECT id, sqlc.arg(event_type)::varchar, sqlc.arg(payload)::jsonb
+FROM webhook_subscriptions
+WHERE owner = sqlc.arg(owner)
+  AND sqlc.arg(event_type)::varchar = ANY(event_types);
+
+-- name: ClaimWebhookDeliveries :many
+-- SKIP LOCKED lets several workers poll without handing out a delivery twice
+WITH claimed AS (
+  UPDATE webhook_deliveries
+  SET attempts = attempts + 1,
+      locked_until = sqlc.arg(locked_until)
+  WHERE id IN (
+    SELECT id FROM webhook_deliveries
+    WHERE status = 'pending'
+      AND locked_until <= sqlc.arg(now)
+    ORDER BY id
+    LIMIT sqlc.arg(batch_size)::int
+    FOR UPDATE SKIP LOCKED
+  )
+  RETURNING id, subscription_id, event_type, payload, attempts
+)
+SELECT c.id, c.event_type, c.payload, c.attempts, s.url, s.secret
+FROM claimed c
+JOIN webhook_subscriptions s ON s.id = c.subscription_id
+ORDER BY c.id;
+
+-- name: MarkWebhookDeliveryDelivered :exec
+UPDATE webhook_deliveries
+SET status = 'delivered',
+    response_status = sqlc.arg(response_status)::int,
+    delivered_at = now(),
+    last_error = NULL
+WHERE id = sqlc.arg(id);
+
+-- name: MarkWebhookDeliveryFailed :exec
+-- Failed deliveries either wait until retry_at or are given up on for good
+UPDATE webhook_deliveries
+SET status = sqlc.arg(status),
+    response_status = sqlc.arg(response_status),
+    last_error = sqlc.arg(last_error),
+    locked_until = sqlc.arg(retry_at)
+WHERE id = sqlc.arg(id);
+
+-- name: ListWebhookDeliveries :many
+-- Newest first. A before_id of 0 starts from the latest delivery
+SELECT * FROM webhook_deliveries
+WHERE subscription_id = sqlc.arg(subscription_id)
+  AND (sqlc.arg(before_id)::bigint = 0 OR id < sqlc.arg(before_id)::bigint)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit)::int;
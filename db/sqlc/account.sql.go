@@ -7,8 +7,89 @@ package db
 
 import (
 	"context"
+
+	"github.com/lib/pq"
 )
 
+const addAccountBalance = `-- name: AddAccountBalance :one
+UPDATE accounts
+SET balance = balance + $1,
+    version = version + 1,
+    updated_at = now()
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held
+`
+
+type AddAccountBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+// Atomic increment/decrement pattern for concurrent safety
+// Uses SET balance = balance + amount for race-condition-free operation
+// Critical for maintaining consistency under concurrent modifications
+// A negative amount is a debit
+func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, addAccountBalance, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.IsHouse,
+		&i.HouseRole,
+		&i.Status,
+		&i.UpdatedAt,
+		&i.OverdraftLimit,
+		&i.Version,
+		&i.Held,
+	)
+	return i, err
+}
+
+const addAccountBalanceIfVersion = `-- name: AddAccountBalanceIfVersion :one
+UPDATE accounts
+SET balance = balance + $1,
+    version = version + 1,
+    updated_at = now()
+WHERE id = $2
+  AND version = $3
+RETURNING id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held
+`
+
+type AddAccountBalanceIfVersionParams struct {
+	Amount  int64 `json:"amount"`
+	ID      int64 `json:"id"`
+	Version int64 `json:"version"`
+}
+
+// Compare-and-swap alternative to locking the row with GetAccountForUpdate:
+// the balance only changes if nothing changed it since the account was read
+// at version. No row, sql.ErrNoRows, means it did and the caller should read
+// the account again and retry. Suits accounts whose balance is read far more
+// often than it is changed, e.g. deposits without a cash side
+func (q *Queries) AddAccountBalanceIfVersion(ctx context.Context, arg AddAccountBalanceIfVersionParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, addAccountBalanceIfVersion, arg.Amount, arg.ID, arg.Version)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.IsHouse,
+		&i.HouseRole,
+		&i.Status,
+		&i.UpdatedAt,
+		&i.OverdraftLimit,
+		&i.Version,
+		&i.Held,
+	)
+	return i, err
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
     owner,
@@ -16,7 +97,7 @@ INSERT INTO accounts (
     currency    
 ) VALUES (
     $1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+) RETURNING id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held
 `
 
 type CreateAccountParams struct {
@@ -36,6 +117,13 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.IsHouse,
+		&i.HouseRole,
+		&i.Status,
+		&i.UpdatedAt,
+		&i.OverdraftLimit,
+		&i.Version,
+		&i.Held,
 	)
 	return i, err
 }
@@ -54,7 +142,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -69,12 +157,54 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.IsHouse,
+		&i.HouseRole,
+		&i.Status,
+		&i.UpdatedAt,
+		&i.OverdraftLimit,
+		&i.Version,
+		&i.Held,
+	)
+	return i, err
+}
+
+const getAccountByOwnerAndCurrency = `-- name: GetAccountByOwnerAndCurrency :one
+SELECT id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held FROM accounts
+WHERE owner = $1
+  AND currency = $2
+  AND status <> 'closed'
+LIMIT 1
+`
+
+type GetAccountByOwnerAndCurrencyParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+// A user has at most one open account per currency (owner_currency_key), so
+// the pair names an account without its ID
+func (q *Queries) GetAccountByOwnerAndCurrency(ctx context.Context, arg GetAccountByOwnerAndCurrencyParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByOwnerAndCurrency, arg.Owner, arg.Currency)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.IsHouse,
+		&i.HouseRole,
+		&i.Status,
+		&i.UpdatedAt,
+		&i.OverdraftLimit,
+		&i.Version,
+		&i.Held,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -90,13 +220,100 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.IsHouse,
+		&i.HouseRole,
+		&i.Status,
+		&i.UpdatedAt,
+		&i.OverdraftLimit,
+		&i.Version,
+		&i.Held,
+	)
+	return i, err
+}
+
+const getAccountsByIDs = `-- name: GetAccountsByIDs :many
+SELECT id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held FROM accounts
+WHERE id = ANY($1::bigint[])
+ORDER BY id
+`
+
+// Resolves many accounts in one round trip. IDs that don't exist are left
+// out, so callers compare what they got against what they asked for
+func (q *Queries) GetAccountsByIDs(ctx context.Context, ids []int64) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, getAccountsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.IsHouse,
+			&i.HouseRole,
+			&i.Status,
+			&i.UpdatedAt,
+			&i.OverdraftLimit,
+			&i.Version,
+			&i.Held,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHouseAccount = `-- name: GetHouseAccount :one
+SELECT id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held FROM accounts
+WHERE is_house
+  AND house_role = $1
+  AND currency = $2
+LIMIT 1
+`
+
+type GetHouseAccountParams struct {
+	HouseRole string `json:"house_role"`
+	Currency  string `json:"currency"`
+}
+
+// House accounts are looked up by what they are used for rather than by ID,
+// so each environment can create its own
+func (q *Queries) GetHouseAccount(ctx context.Context, arg GetHouseAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getHouseAccount, arg.HouseRole, arg.Currency)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.IsHouse,
+		&i.HouseRole,
+		&i.Status,
+		&i.UpdatedAt,
+		&i.OverdraftLimit,
+		&i.Version,
+		&i.Held,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held FROM accounts
 WHERE owner = $1
+  AND status <> 'closed'
 ORDER BY id
 LIMIT $2
 OFFSET $3
@@ -126,6 +343,127 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.IsHouse,
+			&i.HouseRole,
+			&i.Status,
+			&i.UpdatedAt,
+			&i.OverdraftLimit,
+			&i.Version,
+			&i.Held,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsAfter = `-- name: ListAccountsAfter :many
+SELECT id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held FROM accounts
+WHERE owner = $1
+  AND status <> 'closed'
+  AND id > $2
+ORDER BY id
+LIMIT $3::int
+`
+
+type ListAccountsAfterParams struct {
+	Owner     string `json:"owner"`
+	AfterID   int64  `json:"after_id"`
+	PageLimit int32  `json:"page_limit"`
+}
+
+// Keyset pagination: the page starts after the last ID of the previous one,
+// so deep pages stay cheap and don't shift when accounts are added
+func (q *Queries) ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountsAfter, arg.Owner, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.IsHouse,
+			&i.HouseRole,
+			&i.Status,
+			&i.UpdatedAt,
+			&i.OverdraftLimit,
+			&i.Version,
+			&i.Held,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchAccounts = `-- name: SearchAccounts :many
+SELECT id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held FROM accounts
+WHERE ($1::varchar = '' OR owner = $1::varchar)
+  AND ($2::varchar = '' OR currency = $2::varchar)
+  AND ($3::varchar = '' OR status = $3::varchar)
+ORDER BY id
+LIMIT $4::int
+OFFSET $5::int
+`
+
+type SearchAccountsParams struct {
+	Owner      string `json:"owner"`
+	Currency   string `json:"currency"`
+	Status     string `json:"status"`
+	PageLimit  int32  `json:"page_limit"`
+	PageOffset int32  `json:"page_offset"`
+}
+
+// Admin lookup across all owners. Each filter is off at its zero value
+func (q *Queries) SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, searchAccounts,
+		arg.Owner,
+		arg.Currency,
+		arg.Status,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.IsHouse,
+			&i.HouseRole,
+			&i.Status,
+			&i.UpdatedAt,
+			&i.OverdraftLimit,
+			&i.Version,
+			&i.Held,
 		); err != nil {
 			return nil, err
 		}
@@ -140,23 +478,26 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 	return items, nil
 }
 
-const updateAccount = `-- name: UpdateAccount :one
+const setAccountBalance = `-- name: SetAccountBalance :one
 UPDATE accounts
-SET balance = $2
-WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+SET balance = $1,
+    version = version + 1,
+    updated_at = now()
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held
 `
 
-type UpdateAccountParams struct {
-	ID      int64 `json:"id"`
+type SetAccountBalanceParams struct {
 	Balance int64 `json:"balance"`
+	ID      int64 `json:"id"`
 }
 
 // Single-row UPDATE targeting primary key for efficient index scan
 // RETURNING clause eliminates need for separate SELECT after UPDATE
-// This is an absolute-value update (overwrites existing balance)
-func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, updateAccount, arg.ID, arg.Balance)
+// Overwrites the balance without an entry, so it is only for fixtures and
+// repairs; money movements use AddAccountBalance
+func (q *Queries) SetAccountBalance(ctx context.Context, arg SetAccountBalanceParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, setAccountBalance, arg.Balance, arg.ID)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -164,27 +505,65 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.IsHouse,
+		&i.HouseRole,
+		&i.Status,
+		&i.UpdatedAt,
+		&i.OverdraftLimit,
+		&i.Version,
+		&i.Held,
 	)
 	return i, err
 }
 
-const updateAccountBalance = `-- name: UpdateAccountBalance :one
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
 UPDATE accounts
-SET balance = balance + $2
-WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+SET overdraft_limit = $1,
+    updated_at = now()
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held
 `
 
-type UpdateAccountBalanceParams struct {
-	ID      int64 `json:"id"`
-	Balance int64 `json:"balance"`
+type UpdateAccountOverdraftLimitParams struct {
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	ID             int64 `json:"id"`
 }
 
-// Atomic increment/decrement pattern for concurrent safety
-// Uses SET balance = balance + $2 for race-condition-free operation
-// Critical for maintaining consistency under concurrent modifications
-func (q *Queries) UpdateAccountBalance(ctx context.Context, arg UpdateAccountBalanceParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, updateAccountBalance, arg.ID, arg.Balance)
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraftLimit, arg.OverdraftLimit, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.IsHouse,
+		&i.HouseRole,
+		&i.Status,
+		&i.UpdatedAt,
+		&i.OverdraftLimit,
+		&i.Version,
+		&i.Held,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $1,
+    updated_at = now()
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, is_house, house_role, status, updated_at, overdraft_limit, version, held
+`
+
+type UpdateAccountStatusParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.Status, arg.ID)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -192,6 +571,13 @@ func (q *Queries) UpdateAccountBalance(ctx context.Context, arg UpdateAccountBal
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.IsHouse,
+		&i.HouseRole,
+		&i.Status,
+		&i.UpdatedAt,
+		&i.OverdraftLimit,
+		&i.Version,
+		&i.Held,
 	)
 	return i, err
 }
@@ -3,37 +3,21 @@ package db
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"testing"
 	"time"
 
 	"github.com/ankurdas111111/simplebank/util"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
-// ErrRecordNotFound is used when a query returns no rows
-var ErrRecordNotFound = sql.ErrNoRows
-
-// TestUser is a local struct for testing purposes
-type TestUser struct {
-	Username string
-}
-
-// Stub implementation for createRandomUser
-func createRandomUser(t *testing.T) TestUser {
-	// Generate usernames in the format 'user_X' to match what we create in CI
-	return TestUser{
-		Username: fmt.Sprintf("user_%d", util.RandomInt(1, 100)),
-	}
-}
-
-
-func createRandomAccount(t *testing.T) Account {
-	user := createRandomTestUser(t)
+func createRandomAccount(t testing.TB) Account {
+	user := createRandomUser(t)
 
 	arg := CreateAccountParams{
 		Owner:    user.Username,
-		Balance:  util.RandomMoney(),
+		// Enough for the transfers the tests make, which can't overdraw.
+		Balance:  1000 + util.RandomMoney(),
 		Currency: util.RandomCurrency(),
 	}
 
@@ -55,6 +39,17 @@ func TestCreateAccount(t *testing.T) {
 	createRandomAccount(t)
 }
 
+func TestCreateAccountUnknownOwner(t *testing.T) {
+	_, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    util.RandomOwner(),
+		Balance:  0,
+		Currency: util.RandomCurrency(),
+	})
+	var pqErr *pq.Error
+	require.ErrorAs(t, err, &pqErr)
+	require.Equal(t, "foreign_key_violation", pqErr.Code.Name())
+}
+
 func TestGetAccount(t *testing.T) {
 	account1 := createRandomAccount(t)
 	account2, err := testStore.GetAccount(context.Background(), account1.ID)
@@ -68,15 +63,26 @@ func TestGetAccount(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
-func TestUpdateAccount(t *testing.T) {
+func TestGetAccountsByIDs(t *testing.T) {
 	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
 
-	arg := UpdateAccountParams{
+	accounts, err := testStore.GetAccountsByIDs(context.Background(), []int64{account2.ID, account1.ID, account2.ID + 1_000_000})
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	require.Equal(t, account1.ID, accounts[0].ID)
+	require.Equal(t, account2.ID, accounts[1].ID)
+}
+
+func TestSetAccountBalance(t *testing.T) {
+	account1 := createRandomAccount(t)
+
+	arg := SetAccountBalanceParams{
 		ID:      account1.ID,
 		Balance: util.RandomMoney(),
 	}
 
-	account2, err := testStore.UpdateAccount(context.Background(), arg)
+	account2, err := testStore.SetAccountBalance(context.Background(), arg)
 	require.NoError(t, err)
 	require.NotEmpty(t, account2)
 
@@ -87,6 +93,53 @@ func TestUpdateAccount(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
+func TestAddAccountBalance(t *testing.T) {
+	account1 := createRandomAccount(t)
+
+	account2, err := testStore.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account1.ID,
+		Amount: -10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-10, account2.Balance)
+
+	account2, err = testStore.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account1.ID,
+		Amount: 25,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance+15, account2.Balance)
+}
+
+func TestAddAccountBalanceIfVersion(t *testing.T) {
+	account1 := createRandomAccount(t)
+	require.Zero(t, account1.Version)
+
+	account2, err := testStore.AddAccountBalanceIfVersion(context.Background(), AddAccountBalanceIfVersionParams{
+		ID:      account1.ID,
+		Amount:  -10,
+		Version: account1.Version,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-10, account2.Balance)
+	require.Equal(t, account1.Version+1, account2.Version)
+
+	// The account changed since account1 was read, so the swap fails.
+	_, err = testStore.AddAccountBalanceIfVersion(context.Background(), AddAccountBalanceIfVersionParams{
+		ID:      account1.ID,
+		Amount:  -10,
+		Version: account1.Version,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	account3, err := testStore.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account1.ID,
+		Amount: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account2.Version+1, account3.Version)
+}
+
 func TestDeleteAccount(t *testing.T) {
 	account1 := createRandomAccount(t)
 	err := testStore.DeleteAccount(context.Background(), account1.ID)
@@ -94,7 +147,7 @@ func TestDeleteAccount(t *testing.T) {
 
 	account2, err := testStore.GetAccount(context.Background(), account1.ID)
 	require.Error(t, err)
-	require.EqualError(t, err, ErrRecordNotFound.Error())
+	require.EqualError(t, err, sql.ErrNoRows.Error())
 	require.Empty(t, account2)
 }
 
@@ -118,4 +171,40 @@ func TestListAccounts(t *testing.T) {
 	for _, account := range accounts {
 		require.NotEmpty(t, account)
 	}
-}
\ No newline at end of file
+}
+
+func TestListAccountsAfter(t *testing.T) {
+	first := createRandomAccount(t)
+	second, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    first.Owner,
+		Balance:  0,
+		Currency: util.INR, // never picked by RandomCurrency
+	})
+	require.NoError(t, err)
+
+	accounts, err := testStore.ListAccountsAfter(context.Background(), ListAccountsAfterParams{
+		Owner:     first.Owner,
+		AfterID:   first.ID,
+		PageLimit: 5,
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, second.ID, accounts[0].ID)
+}
+
+func TestGetAccountByOwnerAndCurrency(t *testing.T) {
+	account := createRandomAccount(t)
+
+	found, err := testStore.GetAccountByOwnerAndCurrency(context.Background(), GetAccountByOwnerAndCurrencyParams{
+		Owner:    account.Owner,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, found.ID)
+
+	_, err = testStore.GetAccountByOwnerAndCurrency(context.Background(), GetAccountByOwnerAndCurrencyParams{
+		Owner:    util.RandomOwner(),
+		Currency: account.Currency,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
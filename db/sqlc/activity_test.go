@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAccountActivity(t *testing.T) {
+	account := createRandomAccount(t)
+	other := createRandomAccount(t)
+
+	_, err := testStore.DepositTx(context.Background(), DepositTxParams{AccountID: account.ID, Amount: 5})
+	require.NoError(t, err)
+	sent, err := testStore.TransferTx(context.Background(), TransferTxParams{FromAccountID: account.ID, ToAccountID: other.ID, Amount: 3})
+	require.NoError(t, err)
+	received, err := testStore.TransferTx(context.Background(), TransferTxParams{FromAccountID: other.ID, ToAccountID: account.ID, Amount: 2, Pending: true})
+	require.NoError(t, err)
+
+	arg := ListAccountActivityParams{AccountID: account.ID, PageLimit: 10}
+	rows, err := testStore.ListAccountActivity(context.Background(), arg)
+	require.NoError(t, err)
+	// The transfer entries are not listed next to their transfers.
+	require.Len(t, rows, 3)
+
+	require.Equal(t, received.Transfer.ID, rows[0].ID)
+	require.Equal(t, "transfer", rows[0].Kind)
+	require.Equal(t, int64(2), rows[0].Amount)
+	require.Equal(t, TransferPending, rows[0].Status)
+	require.Equal(t, other.ID, rows[0].CounterpartyAccountID)
+	// A pending transfer hasn't changed the balance.
+	require.False(t, rows[0].BalanceAfter.Valid)
+
+	require.Equal(t, sent.Transfer.ID, rows[1].ID)
+	require.Equal(t, int64(-3), rows[1].Amount)
+	require.Equal(t, TransferCompleted, rows[1].Status)
+	require.Equal(t, sql.NullInt64{Int64: account.Balance + 5 - 3, Valid: true}, rows[1].BalanceAfter)
+
+	require.Equal(t, EntryKindDeposit, rows[2].Kind)
+	require.Equal(t, int64(5), rows[2].Amount)
+	require.Equal(t, sql.NullInt64{Int64: account.Balance + 5, Valid: true}, rows[2].BalanceAfter)
+
+	// Paging on from a row returns the rows after it.
+	arg.BeforeTime = sql.NullTime{Time: rows[0].CreatedAt, Valid: true}
+	arg.BeforeSource = rows[0].Source
+	arg.BeforeID = rows[0].ID
+	next, err := testStore.ListAccountActivity(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, rows[1:], next)
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountSummary(t *testing.T) {
+	account := createRandomAccount(t)
+	alice := createRandomAccount(t)
+	bob := createRandomAccount(t)
+
+	_, err := testStore.DepositTx(context.Background(), DepositTxParams{AccountID: account.ID, Amount: 50})
+	require.NoError(t, err)
+	for _, arg := range []TransferTxParams{
+		{FromAccountID: account.ID, ToAccountID: alice.ID, Amount: 30},
+		{FromAccountID: alice.ID, ToAccountID: account.ID, Amount: 5},
+		{FromAccountID: account.ID, ToAccountID: bob.ID, Amount: 10},
+		// Pending transfers move no money and are left out.
+		{FromAccountID: account.ID, ToAccountID: bob.ID, Amount: 100, Pending: true},
+	} {
+		_, err := testStore.TransferTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	from := time.Now().Add(-time.Hour)
+	to := time.Now().Add(time.Hour)
+
+	summary, err := testStore.GetAccountSummary(context.Background(), GetAccountSummaryParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+	})
+	require.NoError(t, err)
+	require.Equal(t, GetAccountSummaryRow{Inflow: 55, Outflow: 40, NetChange: 15, TransferCount: 3}, summary)
+
+	top, err := testStore.ListTopCounterparties(context.Background(), ListTopCounterpartiesParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+		Top:       5,
+	})
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	require.Equal(t, ListTopCounterpartiesRow{
+		AccountID: alice.ID, Owner: alice.Owner, Currency: alice.Currency,
+		TransferCount: 2, Sent: 30, Received: 5,
+	}, top[0])
+	require.Equal(t, bob.ID, top[1].AccountID)
+	require.Equal(t, int64(10), top[1].Sent)
+
+	// Nothing happened before the window.
+	summary, err = testStore.GetAccountSummary(context.Background(), GetAccountSummaryParams{
+		AccountID: account.ID,
+		FromTime:  from.Add(-24 * time.Hour),
+		ToTime:    from,
+	})
+	require.NoError(t, err)
+	require.Zero(t, summary)
+}
+
+func TestListSpendingByCounterparty(t *testing.T) {
+	from1 := createRandomAccount(t)
+	from2, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    from1.Owner,
+		Balance:  1000,
+		Currency: util.INR, // never picked by RandomCurrency
+	})
+	require.NoError(t, err)
+	alice := createRandomAccount(t)
+	bob := createRandomAccount(t)
+
+	for _, arg := range []TransferTxParams{
+		{FromAccountID: from1.ID, ToAccountID: alice.ID, Amount: 20},
+		{FromAccountID: from2.ID, ToAccountID: alice.ID, Amount: 15},
+		{FromAccountID: from1.ID, ToAccountID: bob.ID, Amount: 7},
+		// Money moved between the user's own accounts, or coming in, isn't spending.
+		{FromAccountID: from1.ID, ToAccountID: from2.ID, Amount: 100},
+		{FromAccountID: bob.ID, ToAccountID: from1.ID, Amount: 100},
+	} {
+		_, err := testStore.TransferTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	rows, err := testStore.ListSpendingByCounterparty(context.Background(), ListSpendingByCounterpartyParams{
+		Owner:    from1.Owner,
+		FromTime: time.Now().Add(-time.Hour),
+		ToTime:   time.Now().Add(time.Hour),
+		Top:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, alice.Owner, rows[0].Counterparty)
+	require.Equal(t, int64(2), rows[0].TransferCount)
+	require.Equal(t, int64(35), rows[0].Total)
+	require.Equal(t, bob.Owner, rows[1].Counterparty)
+	require.Equal(t, int64(7), rows[1].Total)
+}
@@ -0,0 +1,558 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// AuditInfoKey is the context key of the AuditInfo writes are audited with.
+// It is a plain string so the API can set it on a *gin.Context, whose Value
+// only looks up string keys.
+const AuditInfoKey = "audit_info"
+
+// Actors of writes made without a user.
+const (
+	// AuditActorSystem makes the writes of background jobs, which have no
+	// AuditInfo.
+	AuditActorSystem = "system"
+	// AuditActorAnonymous makes the writes of requests made before logging
+	// in, e.g. signing up.
+	AuditActorAnonymous = "anonymous"
+)
+
+// AuditInfo says who a write is made by and for which request.
+type AuditInfo struct {
+	Actor string
+	// Set when an admin acts through an impersonation token.
+	Impersonator string
+	ClientIP     string
+	RequestID    string
+}
+
+// WithAuditInfo returns a copy of ctx whose writes are audited with info.
+func WithAuditInfo(ctx context.Context, info AuditInfo) context.Context {
+	return context.WithValue(ctx, AuditInfoKey, info)
+}
+
+// auditedStore records the writes made through it in the audit log. The
+// writes audit.go doesn't audit by hand are generated, see audited_store.go.
+type auditedStore struct {
+	Store
+}
+
+// NewAuditedStore wraps store so that the writes users and admins make
+// through it are recorded in the audit log. The bookkeeping of the server,
+// e.g. workers claiming jobs and idempotency keys storing responses, isn't. Creating accounts and users, changing balances,
+// making transfers and updating users record the resource before and after,
+// other writes their parameters and result. An entry is written in the
+// transaction of its write, so a write whose entry can't be written fails
+// and is rolled back.
+func NewAuditedStore(store Store) Store {
+	return &auditedStore{Store: store}
+}
+
+// auditedTx runs write and then audit, which writes its entry, in one
+// transaction. Both must use the context they are given, which runs them in
+// it.
+func auditedTx[T any](ctx context.Context, store *auditedStore, write func(ctx context.Context) (T, error), audit func(ctx context.Context, result T) error) (T, error) {
+	var result T
+	err := store.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = write(ctx)
+		if err != nil {
+			return err
+		}
+		return audit(ctx, result)
+	})
+	return result, err
+}
+
+// audit writes an entry for a write. before is nil for a resource that was
+// created.
+func (store *auditedStore) audit(ctx context.Context, action, resource string, before, after any) error {
+	info, ok := ctx.Value(AuditInfoKey).(AuditInfo)
+	actor := info.Actor
+	switch {
+	case !ok:
+		actor = AuditActorSystem
+	case actor == "":
+		actor = AuditActorAnonymous
+	}
+
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return fmt.Errorf("cannot audit %s: %w", action, err)
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return fmt.Errorf("cannot audit %s: %w", action, err)
+	}
+
+	_, err = store.CreateAuditLog(ctx, CreateAuditLogParams{
+		Actor:        actor,
+		Impersonator: sql.NullString{String: info.Impersonator, Valid: info.Impersonator != ""},
+		Action:       action,
+		Resource:     resource,
+		ClientIp:     info.ClientIP,
+		RequestID:    info.RequestID,
+		Before:       beforeJSON,
+		After:        afterJSON,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot audit %s: %w", action, err)
+	}
+	return nil
+}
+
+// auditedCall is what the writes of audited_store.go record after.
+type auditedCall struct {
+	Params any `json:"params,omitempty"`
+	Result any `json:"result,omitempty"`
+}
+
+// auditCall writes the entry of a write generated in audited_store.go. Its
+// resource gets the ID of the result, or of the first parameter, when it has
+// one. A write that returned an empty list, e.g. notification preferences
+// updated with none, changed nothing and isn't recorded.
+func (store *auditedStore) auditCall(ctx context.Context, action, resource string, result any, params ...any) error {
+	if value := reflect.ValueOf(result); value.Kind() == reflect.Slice && value.Len() == 0 {
+		return nil
+	}
+	if id, ok := auditedID(result, params); ok {
+		resource += "/" + id
+	}
+
+	call := auditedCall{Result: result}
+	switch len(params) {
+	case 0:
+	case 1:
+		call.Params = params[0]
+	default:
+		call.Params = params
+	}
+	after, err := withoutSecrets(call)
+	if err != nil {
+		return fmt.Errorf("cannot audit %s: %w", action, err)
+	}
+	return store.audit(ctx, action, resource, nil, after)
+}
+
+// auditedID finds the ID of what a write wrote: the ID field of its result,
+// or its first parameter if that is an ID or has an ID field.
+func auditedID(result any, params []any) (string, bool) {
+	if id, ok := idField(result); ok {
+		return id, true
+	}
+	if len(params) == 0 {
+		return "", false
+	}
+	if id, ok := idField(params[0]); ok {
+		return id, true
+	}
+	return formatID(params[0])
+}
+
+func idField(value any) (string, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+	field := v.FieldByName("ID")
+	if !field.IsValid() {
+		return "", false
+	}
+	return formatID(field.Interface())
+}
+
+func formatID(value any) (string, bool) {
+	switch id := value.(type) {
+	case int64:
+		return strconv.FormatInt(id, 10), true
+	case uuid.UUID:
+		return id.String(), true
+	}
+	return "", false
+}
+
+// auditedSecrets are the JSON keys of secrets, which withoutSecrets leaves
+// out of the audit log.
+var auditedSecrets = map[string]bool{
+	"hashed_password": true,
+	"old_hash":        true,
+	"new_hash":        true,
+	"totp_secret":     true,
+	"refresh_token":   true,
+	"secret":          true,
+	"challenge":       true,
+	// The stored response of an idempotent request.
+	"response": true,
+}
+
+// withoutSecrets encodes value as JSON, leaving out the secrets in it at any
+// depth.
+func withoutSecrets(value any) (json.RawMessage, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Numbers stay as they were, e.g. IDs beyond the precision of a float.
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	removeSecrets(decoded)
+	return json.Marshal(decoded)
+}
+
+func removeSecrets(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if auditedSecrets[key] {
+				delete(v, key)
+				continue
+			}
+			removeSecrets(field)
+		}
+	case []any:
+		for _, element := range v {
+			removeSecrets(element)
+		}
+	}
+}
+
+func accountResource(id int64) string {
+	return fmt.Sprintf("accounts/%d", id)
+}
+
+func transferResource(id int64) string {
+	return fmt.Sprintf("transfers/%d", id)
+}
+
+func holdResource(id int64) string {
+	return fmt.Sprintf("holds/%d", id)
+}
+
+func userResource(username string) string {
+	return "users/" + username
+}
+
+// auditedBalance is what balance changes record before and after. The change
+// happens under a row lock, so the balance before is exactly the balance after
+// less the amount, without reading the account again.
+type auditedBalance struct {
+	Balance int64 `json:"balance"`
+}
+
+func (store *auditedStore) auditBalance(ctx context.Context, action string, account Account, amount int64) error {
+	return store.audit(ctx, action, accountResource(account.ID),
+		auditedBalance{Balance: account.Balance - amount},
+		auditedBalance{Balance: account.Balance})
+}
+
+func (store *auditedStore) auditTransfer(ctx context.Context, result TransferTxResult) error {
+	return store.audit(ctx, "transfer.create", transferResource(result.Transfer.ID), nil, result)
+}
+
+// auditedUser leaves the secrets of a user out of the audit log: the empty
+// fields hide those of User. A new password still shows in
+// password_changed_at, and enrolling an authenticator in totp_enabled.
+type auditedUser struct {
+	User
+	HashedPassword string `json:"hashed_password,omitempty"`
+	TotpSecret     string `json:"totp_secret,omitempty"`
+	TotpEnabled    bool   `json:"totp_enabled"`
+}
+
+func newAuditedUser(user User) auditedUser {
+	return auditedUser{User: user, TotpEnabled: user.TotpSecret != ""}
+}
+
+// auditUserUpdate runs update and audits it with the user read before, in
+// one transaction.
+func (store *auditedStore) auditUserUpdate(ctx context.Context, username string, update func(ctx context.Context) (User, error)) (User, error) {
+	var before User
+	return auditedTx(ctx, store, func(ctx context.Context) (User, error) {
+		var err error
+		before, err = store.GetUser(ctx, username)
+		if err != nil {
+			return User{}, err
+		}
+		return update(ctx)
+	}, func(ctx context.Context, user User) error {
+		return store.audit(ctx, "user.update", userResource(username), newAuditedUser(before), newAuditedUser(user))
+	})
+}
+
+// auditAccountUpdate runs update and audits it with the account read before,
+// in one transaction.
+func (store *auditedStore) auditAccountUpdate(ctx context.Context, action string, id int64, update func(ctx context.Context) (Account, error)) (Account, error) {
+	var before Account
+	return auditedTx(ctx, store, func(ctx context.Context) (Account, error) {
+		var err error
+		before, err = store.GetAccount(ctx, id)
+		if err != nil {
+			return Account{}, err
+		}
+		return update(ctx)
+	}, func(ctx context.Context, account Account) error {
+		return store.audit(ctx, action, accountResource(id), before, account)
+	})
+}
+
+func (store *auditedStore) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (Account, error) {
+		return store.Store.CreateAccount(ctx, arg)
+	}, func(ctx context.Context, account Account) error {
+		return store.audit(ctx, "account.create", accountResource(account.ID), nil, account)
+	})
+}
+
+func (store *auditedStore) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	return store.auditAccountUpdate(ctx, "account.update_status", arg.ID, func(ctx context.Context) (Account, error) {
+		return store.Store.UpdateAccountStatus(ctx, arg)
+	})
+}
+
+func (store *auditedStore) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	return store.auditAccountUpdate(ctx, "account.update_overdraft_limit", arg.ID, func(ctx context.Context) (Account, error) {
+		return store.Store.UpdateAccountOverdraftLimit(ctx, arg)
+	})
+}
+
+func (store *auditedStore) SetAccountBalance(ctx context.Context, arg SetAccountBalanceParams) (Account, error) {
+	return store.auditAccountUpdate(ctx, "account.set_balance", arg.ID, func(ctx context.Context) (Account, error) {
+		return store.Store.SetAccountBalance(ctx, arg)
+	})
+}
+
+func (store *auditedStore) AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (Account, error) {
+		return store.Store.AddAccountBalance(ctx, arg)
+	}, func(ctx context.Context, account Account) error {
+		return store.auditBalance(ctx, "account.add_balance", account, arg.Amount)
+	})
+}
+
+func (store *auditedStore) AddAccountBalanceIfVersion(ctx context.Context, arg AddAccountBalanceIfVersionParams) (Account, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (Account, error) {
+		return store.Store.AddAccountBalanceIfVersion(ctx, arg)
+	}, func(ctx context.Context, account Account) error {
+		return store.auditBalance(ctx, "account.add_balance", account, arg.Amount)
+	})
+}
+
+func (store *auditedStore) CloseAccountTx(ctx context.Context, arg CloseAccountTxParams) (CloseAccountTxResult, error) {
+	var before Account
+	return auditedTx(ctx, store, func(ctx context.Context) (CloseAccountTxResult, error) {
+		var err error
+		before, err = store.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return CloseAccountTxResult{}, err
+		}
+		return store.Store.CloseAccountTx(ctx, arg)
+	}, func(ctx context.Context, result CloseAccountTxResult) error {
+		if result.Sweep != nil {
+			if err := store.auditTransfer(ctx, *result.Sweep); err != nil {
+				return err
+			}
+		}
+		return store.audit(ctx, "account.close", accountResource(arg.AccountID), before, result.Account)
+	})
+}
+
+func (store *auditedStore) DepositTx(ctx context.Context, arg DepositTxParams) (DepositTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (DepositTxResult, error) {
+		return store.Store.DepositTx(ctx, arg)
+	}, func(ctx context.Context, result DepositTxResult) error {
+		return store.auditBalance(ctx, "account.deposit", result.Account, result.Entry.Amount)
+	})
+}
+
+func (store *auditedStore) ExternalDepositTx(ctx context.Context, arg ExternalDepositTxParams) (ExternalDepositTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (ExternalDepositTxResult, error) {
+		return store.Store.ExternalDepositTx(ctx, arg)
+	}, func(ctx context.Context, result ExternalDepositTxResult) error {
+		if result.Replayed {
+			return nil
+		}
+		return store.auditBalance(ctx, "account.deposit", result.Account, result.Entry.Amount)
+	})
+}
+
+func (store *auditedStore) WithdrawTx(ctx context.Context, arg WithdrawTxParams) (WithdrawTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (WithdrawTxResult, error) {
+		return store.Store.WithdrawTx(ctx, arg)
+	}, func(ctx context.Context, result WithdrawTxResult) error {
+		return store.auditBalance(ctx, "account.withdraw", result.Account, result.Entry.Amount)
+	})
+}
+
+func (store *auditedStore) AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (AdjustBalanceTxResult, error) {
+		return store.Store.AdjustBalanceTx(ctx, arg)
+	}, func(ctx context.Context, result AdjustBalanceTxResult) error {
+		return store.auditBalance(ctx, "account.adjust_balance", result.Account, result.Entry.Amount)
+	})
+}
+
+func (store *auditedStore) PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (PostInterestTxResult, error) {
+		return store.Store.PostInterestTx(ctx, arg)
+	}, func(ctx context.Context, result PostInterestTxResult) error {
+		if result.Entry == nil {
+			return nil
+		}
+		return store.auditBalance(ctx, "account.post_interest", result.Account, result.Entry.Amount)
+	})
+}
+
+func (store *auditedStore) MovePotMoneyTx(ctx context.Context, arg MovePotMoneyTxParams) (MovePotMoneyTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (MovePotMoneyTxResult, error) {
+		return store.Store.MovePotMoneyTx(ctx, arg)
+	}, func(ctx context.Context, result MovePotMoneyTxResult) error {
+		if result.Entry == nil {
+			return nil
+		}
+		return store.auditBalance(ctx, "account.move_pot_money", result.Account, result.Entry.Amount)
+	})
+}
+
+func (store *auditedStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (TransferTxResult, error) {
+		return store.Store.TransferTx(ctx, arg)
+	}, store.auditTransfer)
+}
+
+func (store *auditedStore) TransferTxFX(ctx context.Context, arg TransferTxFXParams) (TransferTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (TransferTxResult, error) {
+		return store.Store.TransferTxFX(ctx, arg)
+	}, store.auditTransfer)
+}
+
+func (store *auditedStore) BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (BatchTransferTxResult, error) {
+		return store.Store.BatchTransferTx(ctx, arg)
+	}, func(ctx context.Context, result BatchTransferTxResult) error {
+		for _, transfer := range result.made() {
+			if err := store.auditTransfer(ctx, transfer); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (store *auditedStore) AcceptPaymentRequestTx(ctx context.Context, arg AcceptPaymentRequestTxParams) (AcceptPaymentRequestTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (AcceptPaymentRequestTxResult, error) {
+		return store.Store.AcceptPaymentRequestTx(ctx, arg)
+	}, func(ctx context.Context, result AcceptPaymentRequestTxResult) error {
+		return store.auditTransfer(ctx, result.Transfer)
+	})
+}
+
+func (store *auditedStore) PlaceHoldTx(ctx context.Context, arg PlaceHoldTxParams) (HoldTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (HoldTxResult, error) {
+		return store.Store.PlaceHoldTx(ctx, arg)
+	}, func(ctx context.Context, result HoldTxResult) error {
+		return store.audit(ctx, "hold.place", holdResource(result.Hold.ID), nil, result.Hold)
+	})
+}
+
+func (store *auditedStore) ReleaseHoldTx(ctx context.Context, holdID int64) (HoldTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (HoldTxResult, error) {
+		return store.Store.ReleaseHoldTx(ctx, holdID)
+	}, func(ctx context.Context, result HoldTxResult) error {
+		return store.audit(ctx, "hold.release", holdResource(holdID), nil, result.Hold)
+	})
+}
+
+func (store *auditedStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (CaptureHoldTxResult, error) {
+		return store.Store.CaptureHoldTx(ctx, arg)
+	}, func(ctx context.Context, result CaptureHoldTxResult) error {
+		if err := store.audit(ctx, "hold.capture", holdResource(arg.HoldID), nil, result.Hold); err != nil {
+			return err
+		}
+		return store.auditTransfer(ctx, result.Transfer)
+	})
+}
+
+func (store *auditedStore) UpdateTransferStatusTx(ctx context.Context, arg UpdateTransferStatusTxParams) (TransferTxResult, error) {
+	var before Transfer
+	return auditedTx(ctx, store, func(ctx context.Context) (TransferTxResult, error) {
+		var err error
+		before, err = store.GetTransfer(ctx, arg.TransferID)
+		if err != nil {
+			return TransferTxResult{}, err
+		}
+		return store.Store.UpdateTransferStatusTx(ctx, arg)
+	}, func(ctx context.Context, result TransferTxResult) error {
+		return store.audit(ctx, "transfer.update_status", transferResource(arg.TransferID), before, result.Transfer)
+	})
+}
+
+func (store *auditedStore) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	return auditedTx(ctx, store, func(ctx context.Context) (User, error) {
+		return store.Store.CreateUser(ctx, arg)
+	}, func(ctx context.Context, user User) error {
+		return store.audit(ctx, "user.create", userResource(user.Username), nil, newAuditedUser(user))
+	})
+}
+
+func (store *auditedStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (User, error) {
+	return store.auditUserUpdate(ctx, arg.Username, func(ctx context.Context) (User, error) {
+		return store.Store.UpdateUserTx(ctx, arg)
+	})
+}
+
+func (store *auditedStore) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	return store.auditUserUpdate(ctx, arg.Username, func(ctx context.Context) (User, error) {
+		return store.Store.UpdateUser(ctx, arg)
+	})
+}
+
+func (store *auditedStore) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error) {
+	return store.auditUserUpdate(ctx, arg.Username, func(ctx context.Context) (User, error) {
+		return store.Store.UpdateUserEmail(ctx, arg)
+	})
+}
+
+func (store *auditedStore) UpdateUserFullName(ctx context.Context, arg UpdateUserFullNameParams) (User, error) {
+	return store.auditUserUpdate(ctx, arg.Username, func(ctx context.Context) (User, error) {
+		return store.Store.UpdateUserFullName(ctx, arg)
+	})
+}
+
+func (store *auditedStore) UpdateUserPhoneNumber(ctx context.Context, arg UpdateUserPhoneNumberParams) (User, error) {
+	return store.auditUserUpdate(ctx, arg.Username, func(ctx context.Context) (User, error) {
+		return store.Store.UpdateUserPhoneNumber(ctx, arg)
+	})
+}
+
+func (store *auditedStore) UpdateUserKycTier(ctx context.Context, arg UpdateUserKycTierParams) (User, error) {
+	return store.auditUserUpdate(ctx, arg.Username, func(ctx context.Context) (User, error) {
+		return store.Store.UpdateUserKycTier(ctx, arg)
+	})
+}
+
+func (store *auditedStore) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	return store.auditUserUpdate(ctx, arg.Username, func(ctx context.Context) (User, error) {
+		return store.Store.UpdateUserStatus(ctx, arg)
+	})
+}
+
+func (store *auditedStore) UpdateUserTotpSecret(ctx context.Context, arg UpdateUserTotpSecretParams) (User, error) {
+	return store.auditUserUpdate(ctx, arg.Username, func(ctx context.Context) (User, error) {
+		return store.Store.UpdateUserTotpSecret(ctx, arg)
+	})
+}
@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditedStore(t *testing.T) {
+	store := NewAuditedStore(testStore)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	info := AuditInfo{Actor: account1.Owner, ClientIP: "127.0.0.1", RequestID: util.RandomString(16)}
+	ctx := WithAuditInfo(context.Background(), info)
+
+	transfer, err := store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	withdrawal, err := store.WithdrawTx(ctx, WithdrawTxParams{AccountID: account1.ID, Amount: 5})
+	require.NoError(t, err)
+
+	entries, err := store.SearchAuditLogs(context.Background(), SearchAuditLogsParams{
+		RequestID: info.RequestID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// Newest first.
+	require.Equal(t, "account.withdraw", entries[0].Action)
+	require.Equal(t, accountResource(account1.ID), entries[0].Resource)
+	require.JSONEq(t, fmt.Sprintf(`{"balance":%d}`, withdrawal.Account.Balance+5), string(entries[0].Before))
+	require.JSONEq(t, fmt.Sprintf(`{"balance":%d}`, withdrawal.Account.Balance), string(entries[0].After))
+
+	require.Equal(t, "transfer.create", entries[1].Action)
+	require.Equal(t, transferResource(transfer.Transfer.ID), entries[1].Resource)
+	require.Equal(t, info.Actor, entries[1].Actor)
+	require.False(t, entries[1].Impersonator.Valid)
+	require.Equal(t, info.ClientIP, entries[1].ClientIp)
+	require.JSONEq(t, "null", string(entries[1].Before))
+	var after TransferTxResult
+	require.NoError(t, json.Unmarshal(entries[1].After, &after))
+	require.Equal(t, transfer.Transfer.ID, after.Transfer.ID)
+}
+
+func TestAuditedStoreSystemActor(t *testing.T) {
+	store := NewAuditedStore(testStore)
+
+	account, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Currency: util.RandomCurrency(),
+	})
+	require.NoError(t, err)
+
+	entries, err := store.SearchAuditLogs(context.Background(), SearchAuditLogsParams{
+		Resource:  accountResource(account.ID),
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "account.create", entries[0].Action)
+	require.Equal(t, AuditActorSystem, entries[0].Actor)
+	require.Empty(t, entries[0].RequestID)
+}
+
+func TestAuditedUserHidesSecrets(t *testing.T) {
+	user := createRandomUser(t)
+	user.TotpSecret = "JBSWY3DPEHPK3PXP"
+
+	data, err := json.Marshal(newAuditedUser(user))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), user.HashedPassword)
+	require.NotContains(t, string(data), user.TotpSecret)
+	require.Contains(t, string(data), `"totp_enabled":true`)
+}
+
+func TestAuditLogsAppendOnly(t *testing.T) {
+	_, err := testDB.Exec("UPDATE audit_logs SET actor = 'someone'")
+	require.ErrorContains(t, err, "append-only")
+
+	_, err = testDB.Exec("DELETE FROM audit_logs")
+	require.ErrorContains(t, err, "append-only")
+}
+
+func TestAuditedStoreGeneratedWrite(t *testing.T) {
+	store := NewAuditedStore(testStore)
+	session := createRandomSession(t)
+
+	info := AuditInfo{Actor: session.Username, ClientIP: "127.0.0.1", RequestID: util.RandomString(16)}
+	blocked, err := store.BlockSession(WithAuditInfo(context.Background(), info), session.ID)
+	require.NoError(t, err)
+	require.True(t, blocked.IsBlocked)
+
+	entries, err := store.SearchAuditLogs(context.Background(), SearchAuditLogsParams{
+		RequestID: info.RequestID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "session.block", entries[0].Action)
+	require.Equal(t, "sessions/"+session.ID.String(), entries[0].Resource)
+	require.JSONEq(t, "null", string(entries[0].Before))
+	require.Contains(t, string(entries[0].After), `"is_blocked":true`)
+	require.NotContains(t, string(entries[0].After), session.RefreshToken)
+}
+
+func TestAuditedStoreRollsBackUnaudited(t *testing.T) {
+	store := NewAuditedStore(testStore)
+	session := createRandomSession(t)
+
+	// Postgres refuses text with a NUL in it, so the entry can't be written.
+	info := AuditInfo{Actor: "\x00"}
+	_, err := store.BlockSession(WithAuditInfo(context.Background(), info), session.ID)
+	require.ErrorContains(t, err, "cannot audit session.block")
+
+	unchanged, err := store.GetSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.False(t, unchanged.IsBlocked)
+}
+
+func TestAuditedStoreSkipsServerWrites(t *testing.T) {
+	store := NewAuditedStore(testStore)
+	user := createRandomUser(t)
+	requestID := util.RandomString(16)
+
+	ctx := WithAuditInfo(context.Background(), AuditInfo{Actor: user.Username, RequestID: requestID})
+	key := util.RandomString(16)
+	_, err := store.StartIdempotentRequest(ctx, StartIdempotentRequestParams{
+		Username:    user.Username,
+		Key:         key,
+		RequestHash: util.RandomString(32),
+		StaleBefore: time.Now(),
+	})
+	require.NoError(t, err)
+	err = store.FinishIdempotentRequest(ctx, FinishIdempotentRequestParams{
+		Username:       user.Username,
+		Key:            key,
+		ResponseStatus: 200,
+		Response:       json.RawMessage(`{"balance":100}`),
+	})
+	require.NoError(t, err)
+
+	entries, err := store.SearchAuditLogs(context.Background(), SearchAuditLogsParams{
+		RequestID: requestID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestWithoutSecrets(t *testing.T) {
+	data, err := withoutSecrets(auditedCall{
+		Params: CreateSessionParams{RefreshToken: "token", ClientIp: "127.0.0.1"},
+		Result: []WebhookSubscription{{ID: 9007199254740993, Secret: "secret"}},
+	})
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "refresh_token")
+	require.NotContains(t, string(data), `"secret"`)
+	require.Contains(t, string(data), `"client_ip":"127.0.0.1"`)
+	require.Contains(t, string(data), `"id":9007199254740993`)
+}
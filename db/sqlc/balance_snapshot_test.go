@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotAccounts snapshots day for every account created after account,
+// account included.
+func snapshotAccounts(t *testing.T, account Account, day time.Time) {
+	_, err := testStore.CreateBalanceSnapshots(context.Background(), CreateBalanceSnapshotsParams{
+		Day:       day,
+		AfterID:   account.ID - 1,
+		PageLimit: 1000,
+	})
+	require.NoError(t, err)
+}
+
+func TestCreateBalanceSnapshots(t *testing.T) {
+	account := createRandomAccount(t)
+	entry := createRandomEntry(t, account)
+	today := entry.CreatedAt.UTC().Truncate(24 * time.Hour)
+
+	// The account didn't exist yet.
+	snapshotAccounts(t, account, today.AddDate(0, 0, -1))
+
+	snapshotAccounts(t, account, today)
+	snapshots, err := testStore.ListBalanceSnapshots(context.Background(), ListBalanceSnapshotsParams{
+		AccountID: account.ID,
+		FromDay:   today.AddDate(0, 0, -1),
+		ToDay:     today,
+	})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.True(t, today.Equal(snapshots[0].Day))
+	require.Equal(t, account.Balance, snapshots[0].Balance)
+	require.Zero(t, snapshots[0].PotBalance)
+
+	// Taking the day again replaces its snapshot.
+	_, err = testStore.AddAccountBalance(context.Background(), AddAccountBalanceParams{ID: account.ID, Amount: 10})
+	require.NoError(t, err)
+	snapshotAccounts(t, account, today)
+	snapshots, err = testStore.ListBalanceSnapshots(context.Background(), ListBalanceSnapshotsParams{
+		AccountID: account.ID,
+		FromDay:   today,
+		ToDay:     today,
+	})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.Equal(t, account.Balance+10, snapshots[0].Balance)
+}
+
+func TestBalancesStartFromSnapshot(t *testing.T) {
+	account := createRandomAccount(t)
+	entry := createRandomEntry(t, account)
+	today := entry.CreatedAt.UTC().Truncate(24 * time.Hour)
+	snapshotAccounts(t, account, today)
+
+	// A day is only snapshotted once it is over, so changing the balance
+	// after the snapshot shows that balances are worked out from the
+	// snapshot, not from the current balance.
+	_, err := testStore.AddAccountBalance(context.Background(), AddAccountBalanceParams{ID: account.ID, Amount: 10})
+	require.NoError(t, err)
+
+	days, err := testStore.ListDailyBalances(context.Background(), ListDailyBalancesParams{
+		AccountID: account.ID,
+		FromDay:   today.AddDate(0, 0, -1),
+		ToDay:     today,
+	})
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+	require.Equal(t, account.Balance-entry.Amount, days[0].Balance)
+	require.Equal(t, account.Balance, days[1].Balance)
+
+	balances, err := testStore.GetStatementBalances(context.Background(), GetStatementBalancesParams{
+		AccountID: account.ID,
+		FromTime:  today,
+		ToTime:    today.AddDate(0, 0, 1),
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance-entry.Amount, balances.OpeningBalance)
+	require.Equal(t, account.Balance, balances.ClosingBalance)
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeneficiaries(t *testing.T) {
+	user := createRandomUser(t)
+	payee := createRandomAccount(t)
+
+	beneficiary, err := testStore.CreateBeneficiary(context.Background(), CreateBeneficiaryParams{
+		Owner:     user.Username,
+		AccountID: payee.ID,
+		Nickname:  "Landlord",
+	})
+	require.NoError(t, err)
+
+	// An account is saved once per user.
+	_, err = testStore.CreateBeneficiary(context.Background(), CreateBeneficiaryParams{
+		Owner:     user.Username,
+		AccountID: payee.ID,
+		Nickname:  "Rent",
+	})
+	var pqErr *pq.Error
+	require.ErrorAs(t, err, &pqErr)
+	require.Equal(t, "unique_violation", pqErr.Code.Name())
+
+	rows, err := testStore.ListBeneficiaries(context.Background(), ListBeneficiariesParams{
+		Owner:     user.Username,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, beneficiary.ID, rows[0].ID)
+	require.Equal(t, payee.Owner, rows[0].AccountOwner)
+	require.Equal(t, payee.Currency, rows[0].Currency)
+
+	require.NoError(t, testStore.DeleteBeneficiary(context.Background(), beneficiary.ID))
+	rows, err = testStore.ListBeneficiaries(context.Background(), ListBeneficiariesParams{
+		Owner:     user.Username,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
@@ -0,0 +1,25 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// The currencies table must list every currency the API accepts.
+func TestListCurrencies(t *testing.T) {
+	currencies, err := testStore.ListCurrencies(context.Background())
+	require.NoError(t, err)
+
+	codes := map[string]bool{}
+	for _, currency := range currencies {
+		require.NotEmpty(t, currency.Name)
+		codes[currency.Code] = true
+	}
+	for _, code := range []string{util.USD, util.EUR, util.INR} {
+		require.True(t, util.IsSupportedCurrency(code))
+		require.True(t, codes[code], "currency %s is missing", code)
+	}
+}
@@ -7,36 +7,175 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
+const createAdjustingEntry = `-- name: CreateAdjustingEntry :one
+INSERT INTO entries (
+  account_id,
+  amount,
+  kind,
+  adjusts_period,
+  balance_after
+) VALUES (
+  $1, $2, 'adjustment', $3, $4
+) RETURNING id, account_id, amount, created_at, kind, adjusts_period, transfer_id, balance_after
+`
+
+type CreateAdjustingEntryParams struct {
+	AccountID     int64        `json:"account_id"`
+	Amount        int64        `json:"amount"`
+	AdjustsPeriod sql.NullTime `json:"adjusts_period"`
+	BalanceAfter  int64        `json:"balance_after"`
+}
+
+// Late corrections to a closed period are posted in the open period and
+// point back at the period they correct
+func (q *Queries) CreateAdjustingEntry(ctx context.Context, arg CreateAdjustingEntryParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, createAdjustingEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.AdjustsPeriod,
+		arg.BalanceAfter,
+	)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Kind,
+		&i.AdjustsPeriod,
+		&i.TransferID,
+		&i.BalanceAfter,
+	)
+	return i, err
+}
+
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id,
+  balance_after
 ) VALUES (
-  $1, $2
-) RETURNING id, account_id, amount, created_at
+  $1, $2, $3, $4
+) RETURNING id, account_id, amount, created_at, kind, adjusts_period, transfer_id, balance_after
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID    int64         `json:"account_id"`
+	Amount       int64         `json:"amount"`
+	TransferID   sql.NullInt64 `json:"transfer_id"`
+	BalanceAfter int64         `json:"balance_after"`
 }
 
+// Entries are booked once the balance of the account has been changed, in
+// the same transaction, so balance_after is the balance the change left
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.TransferID,
+		arg.BalanceAfter,
+	)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.AdjustsPeriod,
+		&i.TransferID,
+		&i.BalanceAfter,
+	)
+	return i, err
+}
+
+const createEntryOfKind = `-- name: CreateEntryOfKind :one
+INSERT INTO entries (
+  account_id,
+  amount,
+  kind,
+  balance_after
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, account_id, amount, created_at, kind, adjusts_period, transfer_id, balance_after
+`
+
+type CreateEntryOfKindParams struct {
+	AccountID    int64  `json:"account_id"`
+	Amount       int64  `json:"amount"`
+	Kind         string `json:"kind"`
+	BalanceAfter int64  `json:"balance_after"`
+}
+
+func (q *Queries) CreateEntryOfKind(ctx context.Context, arg CreateEntryOfKindParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, createEntryOfKind,
+		arg.AccountID,
+		arg.Amount,
+		arg.Kind,
+		arg.BalanceAfter,
+	)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Kind,
+		&i.AdjustsPeriod,
+		&i.TransferID,
+		&i.BalanceAfter,
+	)
+	return i, err
+}
+
+const createFeeEntry = `-- name: CreateFeeEntry :one
+INSERT INTO entries (
+  account_id,
+  amount,
+  kind,
+  transfer_id,
+  balance_after
+) VALUES (
+  $1, $2, 'fee', $3, $4
+) RETURNING id, account_id, amount, created_at, kind, adjusts_period, transfer_id, balance_after
+`
+
+type CreateFeeEntryParams struct {
+	AccountID    int64         `json:"account_id"`
+	Amount       int64         `json:"amount"`
+	TransferID   sql.NullInt64 `json:"transfer_id"`
+	BalanceAfter int64         `json:"balance_after"`
+}
+
+// Fees are booked with the transfer they were charged on
+func (q *Queries) CreateFeeEntry(ctx context.Context, arg CreateFeeEntryParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, createFeeEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.TransferID,
+		arg.BalanceAfter,
+	)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Kind,
+		&i.AdjustsPeriod,
+		&i.TransferID,
+		&i.BalanceAfter,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, kind, adjusts_period, transfer_id, balance_after FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -48,12 +187,167 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.AdjustsPeriod,
+		&i.TransferID,
+		&i.BalanceAfter,
 	)
 	return i, err
 }
 
+const getStatementBalances = `-- name: GetStatementBalances :one
+WITH anchor AS (
+  SELECT balance, until FROM (
+    SELECT s.balance, (s.day + 1)::timestamptz AS until
+    FROM account_balance_snapshots s
+    WHERE s.account_id = $1
+      AND (s.day + 1)::timestamptz >= $2::timestamptz
+    UNION ALL
+    SELECT a.balance, 'infinity'::timestamptz
+    FROM accounts a
+    WHERE a.id = $1
+  ) b
+  ORDER BY until
+  LIMIT 1
+)
+SELECT
+  (a.balance - COALESCE(SUM(e.amount) FILTER (WHERE e.created_at >= $3::timestamptz), 0))::bigint AS opening_balance,
+  (a.balance - COALESCE(SUM(e.amount) FILTER (WHERE e.created_at >= $2::timestamptz), 0))::bigint AS closing_balance
+FROM anchor a
+LEFT JOIN entries e ON e.account_id = $1
+  AND e.created_at >= $3::timestamptz
+  AND e.created_at < a.until
+GROUP BY a.balance
+`
+
+type GetStatementBalancesParams struct {
+	AccountID int64     `json:"account_id"`
+	ToTime    time.Time `json:"to_time"`
+	FromTime  time.Time `json:"from_time"`
+}
+
+type GetStatementBalancesRow struct {
+	OpeningBalance int64 `json:"opening_balance"`
+	ClosingBalance int64 `json:"closing_balance"`
+}
+
+// The balance of an account at from_time and at to_time, worked out
+// backwards from a later balance like ListDailyBalances
+func (q *Queries) GetStatementBalances(ctx context.Context, arg GetStatementBalancesParams) (GetStatementBalancesRow, error) {
+	row := q.db.QueryRowContext(ctx, getStatementBalances, arg.AccountID, arg.ToTime, arg.FromTime)
+	var i GetStatementBalancesRow
+	err := row.Scan(&i.OpeningBalance, &i.ClosingBalance)
+	return i, err
+}
+
+const listAdjustingEntries = `-- name: ListAdjustingEntries :many
+SELECT id, account_id, amount, created_at, kind, adjusts_period, transfer_id, balance_after FROM entries
+WHERE adjusts_period = $1
+ORDER BY id
+`
+
+func (q *Queries) ListAdjustingEntries(ctx context.Context, adjustsPeriod sql.NullTime) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listAdjustingEntries, adjustsPeriod)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Kind,
+			&i.AdjustsPeriod,
+			&i.TransferID,
+			&i.BalanceAfter,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDailyBalances = `-- name: ListDailyBalances :many
+WITH anchor AS (
+  SELECT balance, until FROM (
+    SELECT s.balance, (s.day + 1)::timestamptz AS until
+    FROM account_balance_snapshots s
+    WHERE s.account_id = $1
+      AND s.day >= $2::date
+    UNION ALL
+    SELECT a.balance, 'infinity'::timestamptz
+    FROM accounts a
+    WHERE a.id = $1
+  ) b
+  ORDER BY until
+  LIMIT 1
+), daily AS (
+  SELECT date_trunc('day', created_at)::date AS day, SUM(amount)::bigint AS net
+  FROM entries
+  WHERE account_id = $1
+    AND created_at >= $3::date
+    AND created_at < (SELECT until FROM anchor)
+  GROUP BY 1
+)
+SELECT
+  d.day::date AS day,
+  (a.balance - COALESCE((SELECT SUM(net) FROM daily WHERE daily.day > d.day), 0))::bigint AS balance
+FROM anchor a
+CROSS JOIN generate_series($3::date, $2::date, interval '1 day') AS d(day)
+ORDER BY d.day
+`
+
+type ListDailyBalancesParams struct {
+	AccountID int64     `json:"account_id"`
+	ToDay     time.Time `json:"to_day"`
+	FromDay   time.Time `json:"from_day"`
+}
+
+type ListDailyBalancesRow struct {
+	Day     time.Time `json:"day"`
+	Balance int64     `json:"balance"`
+}
+
+// An account's opening balance is not an entry, so closing balances are
+// worked out backwards from a later one: the first snapshot on or after
+// to_day, or the current balance when there's none yet. Only the entries
+// between from_day and that balance are read
+func (q *Queries) ListDailyBalances(ctx context.Context, arg ListDailyBalancesParams) ([]ListDailyBalancesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDailyBalances, arg.AccountID, arg.ToDay, arg.FromDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDailyBalancesRow{}
+	for rows.Next() {
+		var i ListDailyBalancesRow
+		if err := rows.Scan(&i.Day, &i.Balance); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, kind, adjusts_period, transfer_id, balance_after FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
@@ -80,6 +374,171 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Kind,
+			&i.AdjustsPeriod,
+			&i.TransferID,
+			&i.BalanceAfter,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntriesAfter = `-- name: ListEntriesAfter :many
+SELECT id, account_id, amount, created_at, kind, adjusts_period, transfer_id, balance_after FROM entries
+WHERE account_id = $1
+  AND id > $2
+  AND ($3::varchar = '' OR EXISTS (
+    SELECT 1 FROM tags g
+    WHERE g.entry_id = entries.id
+      AND g.owner = $4::varchar
+      AND g.tag = $3::varchar
+  ))
+ORDER BY id
+LIMIT $5::int
+`
+
+type ListEntriesAfterParams struct {
+	AccountID int64  `json:"account_id"`
+	AfterID   int64  `json:"after_id"`
+	Tag       string `json:"tag"`
+	Owner     string `json:"owner"`
+	PageLimit int32  `json:"page_limit"`
+}
+
+// A non-empty tag keeps the entries the owner tagged with it
+func (q *Queries) ListEntriesAfter(ctx context.Context, arg ListEntriesAfterParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listEntriesAfter,
+		arg.AccountID,
+		arg.AfterID,
+		arg.Tag,
+		arg.Owner,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Kind,
+			&i.AdjustsPeriod,
+			&i.TransferID,
+			&i.BalanceAfter,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntriesBetween = `-- name: ListEntriesBetween :many
+SELECT id, account_id, amount, created_at, kind, adjusts_period, transfer_id, balance_after FROM entries
+WHERE account_id = $1
+  AND created_at >= $2
+  AND created_at < $3
+ORDER BY id
+`
+
+type ListEntriesBetweenParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+func (q *Queries) ListEntriesBetween(ctx context.Context, arg ListEntriesBetweenParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listEntriesBetween, arg.AccountID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Kind,
+			&i.AdjustsPeriod,
+			&i.TransferID,
+			&i.BalanceAfter,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntriesByAccountBefore = `-- name: ListEntriesByAccountBefore :many
+SELECT id, account_id, amount, created_at, kind, adjusts_period, transfer_id, balance_after FROM entries
+WHERE account_id = $1
+  AND ($2::timestamptz IS NULL
+    OR (created_at, id) < ($2::timestamptz, $3::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
+`
+
+type ListEntriesByAccountBeforeParams struct {
+	AccountID       int64        `json:"account_id"`
+	BeforeCreatedAt sql.NullTime `json:"before_created_at"`
+	BeforeID        int64        `json:"before_id"`
+	PageLimit       int32        `json:"page_limit"`
+}
+
+// Newest first, paged like ListTransfersByAccountBefore
+func (q *Queries) ListEntriesByAccountBefore(ctx context.Context, arg ListEntriesByAccountBeforeParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listEntriesByAccountBefore,
+		arg.AccountID,
+		arg.BeforeCreatedAt,
+		arg.BeforeID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Kind,
+			&i.AdjustsPeriod,
+			&i.TransferID,
+			&i.BalanceAfter,
 		); err != nil {
 			return nil, err
 		}
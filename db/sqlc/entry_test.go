@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -11,8 +12,9 @@ import (
 
 func createRandomEntry(t *testing.T, account Account) Entry {
 	arg := CreateEntryParams{
-		AccountID: account.ID,
-		Amount:    util.RandomMoney(),
+		AccountID:    account.ID,
+		Amount:       util.RandomMoney(),
+		BalanceAfter: account.Balance,
 	}
 
 	entry, err := testStore.CreateEntry(context.Background(), arg)
@@ -21,6 +23,7 @@ func createRandomEntry(t *testing.T, account Account) Entry {
 
 	require.Equal(t, arg.AccountID, entry.AccountID)
 	require.Equal(t, arg.Amount, entry.Amount)
+	require.Equal(t, arg.BalanceAfter, entry.BalanceAfter)
 
 	require.NotZero(t, entry.ID)
 	require.NotZero(t, entry.CreatedAt)
@@ -66,4 +69,87 @@ func TestListEntries(t *testing.T) {
 		require.NotEmpty(t, entry)
 		require.Equal(t, arg.AccountID, entry.AccountID)
 	}
-}
\ No newline at end of file
+}
+
+func TestListDailyBalances(t *testing.T) {
+	account := createRandomAccount(t)
+	entry := createRandomEntry(t, account)
+
+	today := entry.CreatedAt.UTC().Truncate(24 * time.Hour)
+	days, err := testStore.ListDailyBalances(context.Background(), ListDailyBalancesParams{
+		AccountID: account.ID,
+		FromDay:   today.AddDate(0, 0, -2),
+		ToDay:     today,
+	})
+	require.NoError(t, err)
+	require.Len(t, days, 3)
+
+	// The entry was made today, so earlier days closed without it.
+	require.Equal(t, account.Balance-entry.Amount, days[0].Balance)
+	require.Equal(t, account.Balance-entry.Amount, days[1].Balance)
+	require.Equal(t, account.Balance, days[2].Balance)
+}
+
+func TestGetStatementBalances(t *testing.T) {
+	account := createRandomAccount(t)
+	entry := createRandomEntry(t, account)
+
+	balances, err := testStore.GetStatementBalances(context.Background(), GetStatementBalancesParams{
+		AccountID: account.ID,
+		FromTime:  entry.CreatedAt.Add(-time.Hour),
+		ToTime:    entry.CreatedAt.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance-entry.Amount, balances.OpeningBalance)
+	require.Equal(t, account.Balance, balances.ClosingBalance)
+}
+
+func TestListEntriesAfter(t *testing.T) {
+	account := createRandomAccount(t)
+	for i := 0; i < 5; i++ {
+		createRandomEntry(t, account)
+	}
+
+	first, err := testStore.ListEntriesAfter(context.Background(), ListEntriesAfterParams{
+		AccountID: account.ID,
+		PageLimit: 3,
+	})
+	require.NoError(t, err)
+	require.Len(t, first, 3)
+
+	rest, err := testStore.ListEntriesAfter(context.Background(), ListEntriesAfterParams{
+		AccountID: account.ID,
+		AfterID:   first[2].ID,
+		PageLimit: 3,
+	})
+	require.NoError(t, err)
+	require.Len(t, rest, 2)
+	require.Greater(t, rest[0].ID, first[2].ID)
+}
+
+func TestListEntriesByAccountBefore(t *testing.T) {
+	account := createRandomAccount(t)
+	var entries []Entry
+	for i := 0; i < 3; i++ {
+		entries = append(entries, createRandomEntry(t, account))
+	}
+
+	first, err := testStore.ListEntriesByAccountBefore(context.Background(), ListEntriesByAccountBeforeParams{
+		AccountID: account.ID,
+		PageLimit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	require.Equal(t, entries[2].ID, first[0].ID)
+	require.Equal(t, entries[1].ID, first[1].ID)
+
+	rest, err := testStore.ListEntriesByAccountBefore(context.Background(), ListEntriesByAccountBeforeParams{
+		AccountID:       account.ID,
+		BeforeCreatedAt: sql.NullTime{Time: first[1].CreatedAt, Valid: true},
+		BeforeID:        first[1].ID,
+		PageLimit:       2,
+	})
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	require.Equal(t, entries[0].ID, rest[0].ID)
+}
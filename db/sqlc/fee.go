@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const (
+	// EntryKindFee marks entries for fees the bank kept.
+	EntryKindFee = "fee"
+
+	// HouseRoleFees is the house account fees are credited to. There is one
+	// per supported currency.
+	HouseRoleFees = "fees"
+)
+
+// creditFee credits a fee a transfer kept to the fees account of its
+// currency and books the entry with the transfer. The fees account is always
+// locked last, after the accounts of the transfer, so transfers can't
+// deadlock on it.
+func creditFee(ctx context.Context, q *Queries, transferID int64, currency string, fee int64) (Entry, error) {
+	fees, err := q.GetHouseAccount(ctx, GetHouseAccountParams{
+		HouseRole: HouseRoleFees,
+		Currency:  currency,
+	})
+	if err == sql.ErrNoRows {
+		return Entry{}, fmt.Errorf("no %s account for %s", HouseRoleFees, currency)
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+
+	fees, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{ID: fees.ID, Amount: fee})
+	if err != nil {
+		return Entry{}, err
+	}
+	return q.CreateFeeEntry(ctx, CreateFeeEntryParams{
+		AccountID:    fees.ID,
+		Amount:       fee,
+		TransferID:   sql.NullInt64{Int64: transferID, Valid: true},
+		BalanceAfter: fees.Balance,
+	})
+}
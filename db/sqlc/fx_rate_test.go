@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFxRates(t *testing.T) {
+	// A pair of its own, so rates other tests add don't get in the way.
+	base := util.RandomString(3)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, arg := range []CreateFxRateParams{
+		{Base: base, Quote: util.INR, Rate: 10, ValidFrom: now.Add(-48 * time.Hour), CreatedBy: "test"},
+		{Base: base, Quote: util.INR, Rate: 11, ValidFrom: now.Add(-time.Hour), CreatedBy: "test"},
+		{Base: base, Quote: util.INR, Rate: 12, ValidFrom: now.Add(time.Hour), CreatedBy: "test"},
+	} {
+		rate, err := testStore.CreateFxRate(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, arg.Rate, rate.Rate)
+	}
+
+	rateAt := func(at time.Time) float64 {
+		rates, err := testStore.ListFxRates(context.Background(), at)
+		require.NoError(t, err)
+		for _, rate := range rates {
+			if rate.Base == base && rate.Quote == util.INR {
+				return rate.Rate
+			}
+		}
+		return 0
+	}
+	require.Equal(t, 11.0, rateAt(now))
+	require.Equal(t, 10.0, rateAt(now.Add(-24*time.Hour)))
+	require.Equal(t, 0.0, rateAt(now.Add(-72*time.Hour)))
+
+	history, err := testStore.ListFxRateHistory(context.Background(), ListFxRateHistoryParams{
+		Base:  base,
+		Quote: util.INR,
+		Limit: 5,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, 12.0, history[0].Rate)
+}
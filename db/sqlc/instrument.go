@@ -0,0 +1,34 @@
+package db
+
+import "time"
+
+//go:generate go run ./internal/instrumentgen
+
+// QueryObserver is told how every call to an instrumented store went. rows
+// is the number of rows a call returned: the length of a slice, 1 for a
+// single result and 0 for calls that only return an error. Calls that
+// failed report their rows as 0.
+type QueryObserver interface {
+	ObserveQuery(method string, duration time.Duration, rows int, err error)
+}
+
+// instrumentedStore reports every call made through it to an observer. Its
+// methods are generated, see instrumented_store.go.
+type instrumentedStore struct {
+	Store
+	observer QueryObserver
+}
+
+// NewInstrumentedStore wraps store so that the duration, outcome and size of
+// the result of every method called on it go to observer. A transaction is
+// observed as a whole, not query by query.
+func NewInstrumentedStore(store Store, observer QueryObserver) Store {
+	return &instrumentedStore{Store: store, observer: observer}
+}
+
+func (store *instrumentedStore) observe(method string, start time.Time, rows int, err error) {
+	if err != nil {
+		rows = 0
+	}
+	store.observer.ObserveQuery(method, time.Since(start), rows, err)
+}
@@ -0,0 +1,433 @@
+// Command instrumentgen writes the decorators that wrap every method of
+// db.Store: instrumented_store.go times each call, traced_store.go starts a
+// span for it and timeout_store.go bounds how long it may take.
+// audited_store.go records the writes users and admins make in the audit
+// log, except those audit.go audits by hand. It reads the Store and Querier interfaces from
+// the package source, so run it from db/sqlc after either changes:
+//
+//	go generate ./db/sqlc
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// wrapper is a decorator the command writes.
+type wrapper struct {
+	file     string
+	receiver string
+	// imports the body needs whatever the signatures are.
+	imports []string
+	// before is written ahead of the call, after behind it, with $method the
+	// quoted name of the method, $rows the number of rows it returned and
+	// $interface the quoted name of the interface that declares it.
+	before, after string
+	// audit makes the decorator audit the writes instead, see auditCall.
+	audit bool
+}
+
+var wrappers = []wrapper{
+	{
+		file:     "instrumented_store.go",
+		receiver: "instrumentedStore",
+		imports:  []string{"time"},
+		before:   "start := time.Now()",
+		after:    "store.observe($method, start, $rows, err)",
+	},
+	{
+		file:     "traced_store.go",
+		receiver: "tracedStore",
+		before:   "ctx, span := store.tracer.Start(ctx, $method)",
+		after:    "span.End(err)",
+	},
+	{
+		file:     "timeout_store.go",
+		receiver: "timeoutStore",
+		before:   "ctx, cancel := store.withTimeout(ctx, $interface)\n\tdefer cancel()",
+		after:    "err = timedOut(ctx, err)",
+	},
+	{
+		file:     "audited_store.go",
+		receiver: "auditedStore",
+		audit:    true,
+	},
+}
+
+// readPrefixes start the names of the methods that only read. Every other
+// method writes.
+var readPrefixes = []string{"Get", "List", "Count", "Sum", "Search", "Is"}
+
+// notAudited are methods that write nothing worth an entry: writing the
+// entry itself, running a transaction, whose writes are audited, and the
+// bookkeeping of the server rather than the writes users and admins make.
+var notAudited = map[string]bool{
+	"CreateAuditLog": true,
+	"InTx":           true,
+	"Ping":           true,
+	"SchemaVersion":  true,
+
+	// Workers claiming and settling their jobs.
+	"ClaimDueScheduledTransfers":     true,
+	"ClaimEmailJobs":                 true,
+	"ClaimNotifications":             true,
+	"ClaimWebhookDeliveries":         true,
+	"CreateEmailJob":                 true,
+	"CreateNotification":             true,
+	"EnqueueEmailTx":                 true,
+	"EnqueueWebhookDeliveries":       true,
+	"MarkEmailJobFailed":             true,
+	"MarkEmailJobSent":               true,
+	"MarkNotificationFailed":         true,
+	"MarkNotificationSent":           true,
+	"MarkScheduledTransferFailed":    true,
+	"MarkScheduledTransferSucceeded": true,
+	"MarkWebhookDeliveryDelivered":   true,
+	"MarkWebhookDeliveryFailed":      true,
+	"CreateInterestAccrual":          true,
+	"MarkInterestPosted":             true,
+	"CreateFxRate":                   true,
+
+	// Ledger and retention maintenance, recorded in retention runs.
+	"ArchiveLedgerPartitions":     true,
+	"CreateBalanceSnapshots":      true,
+	"CreateLedgerPartitions":      true,
+	"AnonymizeKycDocumentsBefore": true,
+	"CreateRetentionRun":          true,
+	"CreateRetentionRunItem":      true,
+	"DeleteExpiredSessionsBefore": true,
+	"DeleteLoginEventsBefore":     true,
+	"PurgeRetentionTx":            true,
+
+	// Idempotency keys, whose stored responses mustn't be copied into the
+	// log.
+	"ClaimIdempotencyKey":       true,
+	"DeleteIdempotentRequest":   true,
+	"FinishIdempotentRequest":   true,
+	"SetIdempotencyKeyResponse": true,
+	"StartIdempotentRequest":    true,
+
+	// Authentication state kept while logging in.
+	"ConsumeWebauthnChallenge":          true,
+	"CreateLoginEvent":                  true,
+	"CreateWebauthnChallenge":           true,
+	"RehashUserPassword":                true,
+	"UpdateWebauthnCredentialSignCount": true,
+	"UseUserTotpStep":                   true,
+}
+
+type method struct {
+	iface   string
+	name    string
+	params  []string // "name type"
+	args    []string
+	results []string
+}
+
+func main() {
+	for _, w := range wrappers {
+		source, err := run(".", w)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(w.file, source, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// run generates a decorator for the package in dir.
+func run(dir string, w wrapper) ([]byte, error) {
+	fset := token.NewFileSet()
+	interfaces := make(map[string]*ast.InterfaceType)
+	imports := map[string]string{"time": `"time"`} // path -> import spec
+	for _, file := range []string{"querier.go", "store.go"} {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, file), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, spec := range f.Imports {
+			imports[strings.Trim(spec.Path.Value, `"`)] = importSpec(spec)
+		}
+		ast.Inspect(f, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			if iface, ok := spec.Type.(*ast.InterfaceType); ok {
+				interfaces[spec.Name.Name] = iface
+			}
+			return false
+		})
+	}
+
+	methods, err := collect(interfaces, "Store")
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].name < methods[j].name })
+
+	if w.audit {
+		handWritten, err := methodsOf(fset, filepath.Join(dir, "audit.go"), w.receiver)
+		if err != nil {
+			return nil, err
+		}
+		var writes []method
+		for _, m := range methods {
+			if isWrite(m.name) && !handWritten[m.name] {
+				writes = append(writes, m)
+			}
+		}
+		methods = writes
+	}
+
+	return generate(w, methods, imports)
+}
+
+// isWrite reports whether the method called name writes, going by its name.
+func isWrite(name string) bool {
+	if notAudited[name] {
+		return false
+	}
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// methodsOf lists the methods declared on receiver in file.
+func methodsOf(fset *token.FileSet, file, receiver string) (map[string]bool, error) {
+	f, err := parser.ParseFile(fset, file, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	methods := make(map[string]bool)
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil {
+			continue
+		}
+		if types.ExprString(fn.Recv.List[0].Type) == "*"+receiver {
+			methods[fn.Name.Name] = true
+		}
+	}
+	return methods, nil
+}
+
+// action names the entry of a write after its method, as noun.verb, e.g.
+// beneficiary.create for CreateBeneficiary. resource is the kind of thing it
+// writes, e.g. beneficiaries: that of the model it returns, e.g. accounts for
+// AddAccountHeld, or else the noun.
+func action(m method) (action, resource string) {
+	words := splitWords(strings.TrimSuffix(m.name, "Tx"))
+	verb, noun := words[0], words[1:]
+	// MarkEmailJobSent marks an email job sent.
+	if verb == "mark" && len(noun) > 1 {
+		verb, noun = verb+"_"+noun[len(noun)-1], noun[:len(noun)-1]
+	}
+	// DeleteLoginEventsBefore deletes login events before a cutoff.
+	if len(noun) > 1 && noun[len(noun)-1] == "before" {
+		noun = noun[:len(noun)-1]
+	}
+	if len(noun) == 0 {
+		noun = []string{verb}
+	}
+	action = strings.Join(noun, "_") + "." + verb
+	resource = plural(strings.Join(noun, "_"))
+
+	if len(m.results) == 2 {
+		model := strings.TrimPrefix(m.results[0], "[]")
+		isModel := isUpper(model[0]) && !strings.Contains(model, ".")
+		if isModel && !strings.HasSuffix(model, "Result") && !strings.HasSuffix(model, "Row") {
+			resource = plural(strings.Join(splitWords(model), "_"))
+		}
+	}
+	return action, resource
+}
+
+// splitWords splits a Go name into lower case words, keeping initialisms
+// whole: CreateFXTransfer is create, fx, transfer.
+func splitWords(name string) []string {
+	var words []string
+	start := 0
+	for i := 1; i < len(name); i++ {
+		upper := isUpper(name[i])
+		startsWord := upper && !isUpper(name[i-1])
+		// The last letter of an initialism followed by a word starts it.
+		endsInitialism := upper && isUpper(name[i-1]) && i+1 < len(name) && !isUpper(name[i+1])
+		if startsWord || endsInitialism {
+			words = append(words, strings.ToLower(name[start:i]))
+			start = i
+		}
+	}
+	return append(words, strings.ToLower(name[start:]))
+}
+
+func isUpper(c byte) bool {
+	return 'A' <= c && c <= 'Z'
+}
+
+func plural(noun string) string {
+	switch {
+	case strings.HasSuffix(noun, "s"), strings.HasSuffix(noun, "data"):
+		return noun
+	case strings.HasSuffix(noun, "y") && !strings.ContainsAny(noun[len(noun)-2:len(noun)-1], "aeiou"):
+		return noun[:len(noun)-1] + "ies"
+	}
+	return noun + "s"
+}
+
+func importSpec(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name + " " + spec.Path.Value
+	}
+	return spec.Path.Value
+}
+
+// collect lists the methods of an interface, including those of the
+// interfaces it embeds.
+func collect(interfaces map[string]*ast.InterfaceType, name string) ([]method, error) {
+	iface, ok := interfaces[name]
+	if !ok {
+		return nil, fmt.Errorf("interface %s not found", name)
+	}
+
+	var methods []method
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			embedded, err := collect(interfaces, types.ExprString(field.Type))
+			if err != nil {
+				return nil, err
+			}
+			methods = append(methods, embedded...)
+			continue
+		}
+
+		m := method{iface: name, name: field.Names[0].Name}
+		for i, param := range fn.Params.List {
+			names := param.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("arg%d", i))}
+			}
+			for _, n := range names {
+				m.params = append(m.params, n.Name+" "+types.ExprString(param.Type))
+				m.args = append(m.args, n.Name)
+			}
+		}
+		for _, result := range fn.Results.List {
+			m.results = append(m.results, types.ExprString(result.Type))
+		}
+		if m.results[len(m.results)-1] != "error" || len(m.results) > 2 {
+			return nil, fmt.Errorf("%s must return a value and an error, or only an error", m.name)
+		}
+		methods = append(methods, m)
+	}
+	return methods, nil
+}
+
+// writeAudited writes the body of an audited method: the call and its entry,
+// in one transaction.
+func writeAudited(body *bytes.Buffer, m method) {
+	action, resource := action(m)
+	call := fmt.Sprintf("store.Store.%s(%s)", m.name, strings.Join(m.args, ", "))
+	entry := fmt.Sprintf("store.auditCall(%s)", strings.Join(append([]string{"ctx", strconv.Quote(action), strconv.Quote(resource), "%s"}, m.args[1:]...), ", "))
+	if len(m.results) == 1 {
+		fmt.Fprintf(body, "\treturn store.InTx(ctx, func(ctx context.Context) error {\n")
+		fmt.Fprintf(body, "\t\tif err := %s; err != nil {\n\t\t\treturn err\n\t\t}\n", call)
+		fmt.Fprintf(body, "\t\treturn %s\n\t})\n}\n", fmt.Sprintf(entry, "nil"))
+		return
+	}
+	fmt.Fprintf(body, "\tvar result %s\n", m.results[0])
+	fmt.Fprintf(body, "\terr := store.InTx(ctx, func(ctx context.Context) error {\n")
+	fmt.Fprintf(body, "\t\tvar err error\n\t\tresult, err = %s\n", call)
+	fmt.Fprintf(body, "\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n")
+	fmt.Fprintf(body, "\t\treturn %s\n\t})\n", fmt.Sprintf(entry, "result"))
+	fmt.Fprintf(body, "\treturn result, err\n}\n")
+}
+
+func generate(w wrapper, methods []method, imports map[string]string) ([]byte, error) {
+	var body bytes.Buffer
+	used := make(map[string]bool)
+	for _, path := range w.imports {
+		used[path] = true
+	}
+	for _, m := range methods {
+		if m.args[0] != "ctx" {
+			return nil, fmt.Errorf("%s must take ctx first", m.name)
+		}
+		signature := strings.Join(m.params, ", ") + " " + strings.Join(m.results, " ")
+		for path := range imports {
+			pkg := path[strings.LastIndex(path, "/")+1:]
+			if strings.Contains(signature, pkg+".") {
+				used[path] = true
+			}
+		}
+
+		rows := "0"
+		if len(m.results) == 2 {
+			rows = "1"
+			if strings.HasPrefix(m.results[0], "[]") {
+				rows = "len(result)"
+			}
+		}
+		results := "(" + strings.Join(m.results, ", ") + ")"
+		fmt.Fprintf(&body, "\nfunc (store *%s) %s(%s) %s {\n", w.receiver, m.name, strings.Join(m.params, ", "), results)
+		if w.audit {
+			writeAudited(&body, m)
+			continue
+		}
+		expand := strings.NewReplacer("$method", strconv.Quote(m.name), "$rows", rows, "$interface", strconv.Quote(m.iface))
+		fmt.Fprintf(&body, "\t%s\n", expand.Replace(w.before))
+		call := fmt.Sprintf("store.Store.%s(%s)", m.name, strings.Join(m.args, ", "))
+		if len(m.results) == 1 {
+			fmt.Fprintf(&body, "\terr := %s\n", call)
+			fmt.Fprintf(&body, "\t%s\n", expand.Replace(w.after))
+			fmt.Fprintf(&body, "\treturn err\n}\n")
+			continue
+		}
+		fmt.Fprintf(&body, "\tresult, err := %s\n", call)
+		fmt.Fprintf(&body, "\t%s\n", expand.Replace(w.after))
+		fmt.Fprintf(&body, "\treturn result, err\n}\n")
+	}
+
+	// The standard library goes first, like goimports does.
+	var std, others []string
+	for path := range used {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			others = append(others, imports[path])
+		} else {
+			std = append(std, imports[path])
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(others)
+
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by instrumentgen. DO NOT EDIT.\n\npackage db\n\nimport (\n")
+	for _, spec := range std {
+		fmt.Fprintf(&source, "\t%s\n", spec)
+	}
+	if len(std) > 0 && len(others) > 0 {
+		fmt.Fprintf(&source, "\n")
+	}
+	for _, spec := range others {
+		fmt.Fprintf(&source, "\t%s\n", spec)
+	}
+	fmt.Fprintf(&source, ")\n")
+	source.Write(body.Bytes())
+	return format.Source(source.Bytes())
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The wrappers must be regenerated whenever Store or Querier changes, or new
+// methods go unobserved.
+func TestGeneratedFilesAreUpToDate(t *testing.T) {
+	for _, w := range wrappers {
+		t.Run(w.file, func(t *testing.T) {
+			source, err := run("../..", w)
+			require.NoError(t, err)
+
+			generated, err := os.ReadFile("../../" + w.file)
+			require.NoError(t, err)
+			require.Equal(t, string(source), string(generated), "run go generate ./db/sqlc")
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// firstMismatchedAccount returns the first account from id on that doesn't
+// add up, as other tests leave plenty of those behind.
+func firstMismatchedAccount(t *testing.T, id int64) *ListAccountBalanceMismatchesRow {
+	rows, err := testStore.ListAccountBalanceMismatches(context.Background(), ListAccountBalanceMismatchesParams{
+		AfterID:   id - 1,
+		PageLimit: 1,
+	})
+	require.NoError(t, err)
+	if len(rows) == 0 {
+		return nil
+	}
+	return &rows[0]
+}
+
+func TestListAccountBalanceMismatches(t *testing.T) {
+	// Test accounts are opened with a balance that no entry accounts for.
+	account := createRandomAccount(t)
+
+	mismatch := firstMismatchedAccount(t, account.ID)
+	require.NotNil(t, mismatch)
+	require.Equal(t, account.ID, mismatch.ID)
+	require.Equal(t, account.Balance, mismatch.Balance)
+	require.Zero(t, mismatch.EntryTotal)
+
+	_, err := testStore.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID:    account.ID,
+		Amount:       account.Balance,
+		BalanceAfter: account.Balance,
+	})
+	require.NoError(t, err)
+
+	mismatch = firstMismatchedAccount(t, account.ID)
+	if mismatch != nil {
+		require.NotEqual(t, account.ID, mismatch.ID)
+	}
+}
+
+func TestListTransferEntryMismatches(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, sql.NullInt64{Int64: result.Transfer.ID, Valid: true}, result.FromEntry.TransferID)
+	require.Equal(t, sql.NullInt64{Int64: result.Transfer.ID, Valid: true}, result.ToEntry.TransferID)
+
+	pending, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Pending:       true,
+	})
+	require.NoError(t, err)
+
+	// Completed without booking anything.
+	bare, err := testStore.CreateTransfer(context.Background(), CreateTransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Status:        TransferCompleted,
+	})
+	require.NoError(t, err)
+
+	rows, err := testStore.ListTransferEntryMismatches(context.Background(), ListTransferEntryMismatchesParams{
+		AfterID:   result.Transfer.ID - 1,
+		PageLimit: 100,
+	})
+	require.NoError(t, err)
+
+	flagged := make(map[int64]ListTransferEntryMismatchesRow)
+	for _, row := range rows {
+		flagged[row.ID] = row
+	}
+	require.NotContains(t, flagged, result.Transfer.ID)
+	require.NotContains(t, flagged, pending.Transfer.ID)
+	require.Contains(t, flagged, bare.ID)
+	require.Zero(t, flagged[bare.ID].EntryCount)
+}
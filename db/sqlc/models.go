@@ -5,6 +5,8 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +18,108 @@ type Account struct {
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// owned by the bank, e.g. fee income or interest expense
+	IsHouse   bool   `json:"is_house"`
+	HouseRole string `json:"house_role"`
+	// active, frozen or closed, only active accounts can send or receive money
+	Status string `json:"status"`
+	// bumped by every update, part of the ETag of account reads
+	UpdatedAt time.Time `json:"updated_at"`
+	// how far below zero transfers and withdrawals may take the balance
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	// incremented on every balance change, for compare-and-swap updates
+	Version int64 `json:"version"`
+	// total of the active holds on the account
+	Held int64 `json:"held"`
+}
+
+type AccountBalanceSnapshot struct {
+	AccountID int64     `json:"account_id"`
+	Day       time.Time `json:"day"`
+	// closing balance of the account on the day
+	Balance int64 `json:"balance"`
+	// closing balance of the pots of the account on the day
+	PotBalance int64 `json:"pot_balance"`
+	// when the snapshot was last worked out
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AccountingPeriod struct {
+	// first day of the closed month
+	Period   time.Time `json:"period"`
+	ClosedBy string    `json:"closed_by"`
+	ClosedAt time.Time `json:"closed_at"`
+}
+
+type ArchivedEntryTotal struct {
+	AccountID int64 `json:"account_id"`
+	// sum of the entries of the account that have been archived
+	Total     int64     `json:"total"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type AuditLog struct {
+	ID int64 `json:"id"`
+	// user the action was performed as
+	Actor string `json:"actor"`
+	// admin acting through an impersonation token, if any
+	Impersonator sql.NullString `json:"impersonator"`
+	Action       string         `json:"action"`
+	// what was acted on, e.g. accounts/42, or the request path of an impersonated request
+	Resource  string    `json:"resource"`
+	ClientIp  string    `json:"client_ip"`
+	CreatedAt time.Time `json:"created_at"`
+	// X-Request-ID of the API request, empty for background jobs
+	RequestID string `json:"request_id"`
+	// the resource before a write, null when it was created
+	Before json.RawMessage `json:"before"`
+	// the resource after a write
+	After json.RawMessage `json:"after"`
+}
+
+type BalanceAdjustment struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	EntryID   int64 `json:"entry_id"`
+	Amount    int64 `json:"amount"`
+	// why an admin corrected the balance, required
+	Reason     string    `json:"reason"`
+	AdjustedBy string    `json:"adjusted_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Beneficiary struct {
+	ID        int64     `json:"id"`
+	Owner     string    `json:"owner"`
+	AccountID int64     `json:"account_id"`
+	Nickname  string    `json:"nickname"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Currency struct {
+	// ISO 4217 code, as stored in accounts.currency
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// digits after the decimal point of the minor unit, e.g. 2 for cents
+	Exponent  int32     `json:"exponent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type EmailJob struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	Kind      string `json:"kind"`
+	Recipient string `json:"recipient"`
+	Subject   string `json:"subject"`
+	Body      string `json:"body"`
+	// pending, sent or failed
+	Status    string         `json:"status"`
+	Attempts  int32          `json:"attempts"`
+	LastError sql.NullString `json:"last_error"`
+	// a worker owns the job, or it waits for a retry, until then
+	LockedUntil time.Time    `json:"locked_until"`
+	SentAt      sql.NullTime `json:"sent_at"`
+	CreatedAt   time.Time    `json:"created_at"`
 }
 
 type Entry struct {
@@ -24,6 +128,211 @@ type Entry struct {
 	// can be positive or negative
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// transfer, deposit, withdrawal, interest, fee, adjustment or pot
+	Kind string `json:"kind"`
+	// closed period corrected by an adjustment entry
+	AdjustsPeriod sql.NullTime `json:"adjusts_period"`
+	// the transfer that booked the entry, null for other kinds of entry
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	// balance of the account once the entry was booked
+	BalanceAfter int64 `json:"balance_after"`
+}
+
+type ExternalDeposit struct {
+	ID int64 `json:"id"`
+	// the sending bank reference, credited at most once
+	Reference string `json:"reference"`
+	AccountID int64  `json:"account_id"`
+	Amount    int64  `json:"amount"`
+	// the credit, set in the transaction that records the deposit
+	EntryID   sql.NullInt64 `json:"entry_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type FxRate struct {
+	ID    int64  `json:"id"`
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	// units of quote one unit of base is worth
+	Rate      float64   `json:"rate"`
+	ValidFrom time.Time `json:"valid_from"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Hold struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+	// active, released or captured
+	Status string         `json:"status"`
+	Memo   sql.NullString `json:"memo"`
+	// the transfer that captured the hold
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+	ResolvedAt sql.NullTime  `json:"resolved_at"`
+}
+
+type IdempotencyKey struct {
+	Username string `json:"username"`
+	Key      string `json:"key"`
+	// sha256 of the request body, so a key cannot be reused for a different request
+	RequestHash string `json:"request_hash"`
+	// set whenever status is completed
+	Response  json.RawMessage `json:"response"`
+	CreatedAt time.Time       `json:"created_at"`
+	// in_progress while the request runs, completed once its response is stored
+	Status string `json:"status"`
+	// HTTP status a retry of the request is answered with
+	ResponseStatus int32 `json:"response_status"`
+}
+
+type InterestAccrual struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+	// balance of the account and its pots the interest was worked out on
+	Balance int64 `json:"balance"`
+	// yearly rate in basis points
+	RateBps int64 `json:"rate_bps"`
+	// interest of the day in millionths of the smallest unit of the currency
+	AmountMicros int64 `json:"amount_micros"`
+	// interest entry the accrual was posted with, null until then
+	EntryID   sql.NullInt64 `json:"entry_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type KycDocument struct {
+	ID            int64  `json:"id"`
+	Username      string `json:"username"`
+	DocumentType  string `json:"document_type"`
+	RequestedTier string `json:"requested_tier"`
+	FileName      string `json:"file_name"`
+	ContentType   string `json:"content_type"`
+	Content       []byte `json:"content"`
+	// pending, approved or rejected
+	Status     string         `json:"status"`
+	ReviewedBy sql.NullString `json:"reviewed_by"`
+	ReviewNote string         `json:"review_note"`
+	ReviewedAt sql.NullTime   `json:"reviewed_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type LoginEvent struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	UserAgent string    `json:"user_agent"`
+	ClientIp  string    `json:"client_ip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Notification struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// in_app or sms
+	Channel string `json:"channel"`
+	Type    string `json:"type"`
+	// the data of an in-app notification, the message of the others
+	Payload   json.RawMessage `json:"payload"`
+	Status    string          `json:"status"`
+	Attempts  int32           `json:"attempts"`
+	LastError sql.NullString  `json:"last_error"`
+	// a sender owns the delivery, or it waits for a retry, until then
+	LockedUntil time.Time    `json:"locked_until"`
+	SentAt      sql.NullTime `json:"sent_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	Username string `json:"username"`
+	// in_app or sms
+	Channel   string `json:"channel"`
+	EventType string `json:"event_type"`
+	// only choices a user made are stored, a missing row means enabled
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PaymentRequest struct {
+	ID        int64  `json:"id"`
+	Requester string `json:"requester"`
+	Payer     string `json:"payer"`
+	// the requester account that is paid, in its currency
+	ToAccountID int64  `json:"to_account_id"`
+	Amount      int64  `json:"amount"`
+	Note        string `json:"note"`
+	// pending, accepted or declined
+	Status string `json:"status"`
+	// the transfer made when it was accepted
+	TransferID  sql.NullInt64 `json:"transfer_id"`
+	RespondedAt sql.NullTime  `json:"responded_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type Pot struct {
+	ID        int64  `json:"id"`
+	AccountID int64  `json:"account_id"`
+	Name      string `json:"name"`
+	// set aside from the balance of the account, which does not include it
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PotMove struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// null when the money came from the balance of the account
+	FromPotID sql.NullInt64 `json:"from_pot_id"`
+	// null when the money went back to the balance of the account
+	ToPotID   sql.NullInt64 `json:"to_pot_id"`
+	Amount    int64         `json:"amount"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type RetentionRule struct {
+	// login_events, sessions or kyc_documents
+	Target     string    `json:"target"`
+	RetainDays int32     `json:"retain_days"`
+	Enabled    bool      `json:"enabled"`
+	UpdatedBy  string    `json:"updated_by"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type RetentionRun struct {
+	ID        int64     `json:"id"`
+	DryRun    bool      `json:"dry_run"`
+	RunBy     string    `json:"run_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RetentionRunItem struct {
+	ID     int64  `json:"id"`
+	RunID  int64  `json:"run_id"`
+	Target string `json:"target"`
+	// purge or anonymize
+	Action string    `json:"action"`
+	Cutoff time.Time `json:"cutoff"`
+	// rows removed, or that would be removed on a dry run
+	Affected int64 `json:"affected"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64     `json:"id"`
+	Owner         string    `json:"owner"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	ExecuteAt     time.Time `json:"execute_at"`
+	// pending, succeeded, failed or cancelled
+	Status   string `json:"status"`
+	Attempts int32  `json:"attempts"`
+	// a scheduler is executing the transfer until then
+	LockedUntil time.Time `json:"locked_until"`
+	// the transfer made when it succeeded
+	TransferID sql.NullInt64  `json:"transfer_id"`
+	LastError  sql.NullString `json:"last_error"`
+	ExecutedAt sql.NullTime   `json:"executed_at"`
+	CreatedAt  time.Time      `json:"created_at"`
 }
 
 type Session struct {
@@ -37,6 +346,42 @@ type Session struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+type Setting struct {
+	ID int64 `json:"id"`
+	// empty for every tenant
+	Tenant string `json:"tenant"`
+	// empty for every currency
+	Currency  string    `json:"currency"`
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type StandingDataChange struct {
+	ID         int64  `json:"id"`
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Field      string `json:"field"`
+	OldValue   string `json:"old_value"`
+	NewValue   string `json:"new_value"`
+	ChangedBy  string `json:"changed_by"`
+	// set when this change rolled back an earlier one
+	RevertsChangeID sql.NullInt64 `json:"reverts_change_id"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+type Tag struct {
+	ID int64 `json:"id"`
+	// tags are private, both sides of a transfer can tag it differently
+	Owner string `json:"owner"`
+	// a lowercase category such as rent, food or salary
+	Tag        string        `json:"tag"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	EntryID    sql.NullInt64 `json:"entry_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
 type Transfer struct {
 	ID            int64 `json:"id"`
 	FromAccountID int64 `json:"from_account_id"`
@@ -44,6 +389,41 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// free text the sender attached, e.g. an invoice number
+	Memo sql.NullString `json:"memo"`
+	// pending transfers have not moved money yet, failed ones never will
+	Status string `json:"status"`
+	// debited from the sender, in its currency, set on cross-currency transfers only
+	FromAmount sql.NullInt64 `json:"from_amount"`
+	// credited to the recipient, in its currency
+	ToAmount sql.NullInt64 `json:"to_amount"`
+	// conversion rate from the sender currency to the recipient currency
+	Rate sql.NullFloat64 `json:"rate"`
+	// part of from_amount kept as the conversion fee, in the sender currency
+	FxFee sql.NullInt64 `json:"fx_fee"`
+	// false for transfers made before entries recorded their transfer, which the ledger check skips
+	EntriesLinked bool `json:"entries_linked"`
+}
+
+type TransferLimit struct {
+	Username string `json:"username"`
+	Currency string `json:"currency"`
+	// zero means no limit
+	MaxSingleTransfer int64 `json:"max_single_transfer"`
+	// cap on the transfers of any rolling 24 hours, zero means no limit
+	MaxDailyTotal int64     `json:"max_daily_total"`
+	UpdatedBy     string    `json:"updated_by"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type TransferStatusChange struct {
+	ID         int64  `json:"id"`
+	TransferID int64  `json:"transfer_id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	// why the transfer moved, e.g. why it failed
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type User struct {
@@ -53,4 +433,65 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	Role              string    `json:"role"`
+	KycTier           string    `json:"kyc_tier"`
+	// empty until the user enrolls an authenticator app
+	TotpSecret string `json:"totp_secret"`
+	// tenant or branch whose settings apply to the user
+	Tenant string `json:"tenant"`
+	// E.164 number security alerts are texted to, empty for none
+	PhoneNumber string `json:"phone_number"`
+	// active or suspended, a suspended user cannot log in
+	Status string `json:"status"`
+	// the time step of the last TOTP code accepted, codes from it or earlier are refused
+	TotpLastStep int64 `json:"totp_last_step"`
+}
+
+type WebauthnChallenge struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	// registration or login
+	Ceremony  string    `json:"ceremony"`
+	Challenge []byte    `json:"challenge"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebauthnCredential struct {
+	ID           int64  `json:"id"`
+	Username     string `json:"username"`
+	CredentialID []byte `json:"credential_id"`
+	// COSE encoded public key
+	PublicKey []byte `json:"public_key"`
+	// last signature counter reported by the authenticator
+	SignCount  int64        `json:"sign_count"`
+	Name       string       `json:"name"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	// pending, delivered or failed
+	Status         string         `json:"status"`
+	Attempts       int32          `json:"attempts"`
+	ResponseStatus sql.NullInt32  `json:"response_status"`
+	LastError      sql.NullString `json:"last_error"`
+	// a worker owns the delivery, or it waits for a retry, until then
+	LockedUntil time.Time    `json:"locked_until"`
+	DeliveredAt sql.NullTime `json:"delivered_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type WebhookSubscription struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	Url   string `json:"url"`
+	// signs every delivery with HMAC-SHA256
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
 }
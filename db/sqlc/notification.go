@@ -0,0 +1,16 @@
+package db
+
+// Statuses of a notification. In-app notifications are stored sent; the
+// others stay pending until they are delivered or given up on.
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed"
+)
+
+// SMSNotificationPayload is the payload of a notification on the sms channel:
+// the message as it was texted, so retries send the same text.
+type SMSNotificationPayload struct {
+	To   string `json:"to"`
+	Body string `json:"body"`
+}
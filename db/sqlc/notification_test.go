@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createTestNotification(t *testing.T, username, channel, kind, status string) Notification {
+	notification, err := testStore.CreateNotification(context.Background(), CreateNotificationParams{
+		Username:    username,
+		Channel:     channel,
+		Type:        kind,
+		Payload:     json.RawMessage(`{"event":"login"}`),
+		Status:      status,
+		LockedUntil: time.Now(),
+	})
+	require.NoError(t, err)
+	return notification
+}
+
+func TestListNotifications(t *testing.T) {
+	user := createRandomUser(t)
+	alert := createTestNotification(t, user.Username, "in_app", "security.alert", NotificationSent)
+	require.True(t, alert.SentAt.Valid)
+	received := createTestNotification(t, user.Username, "in_app", "transfer.received", NotificationSent)
+	createTestNotification(t, user.Username, "sms", "security.alert", NotificationPending)
+
+	arg := ListNotificationsParams{Username: user.Username, PageLimit: 10}
+	notifications, err := testStore.ListNotifications(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []int64{received.ID, alert.ID}, notificationIDs(notifications))
+
+	arg.BeforeID = received.ID
+	notifications, err = testStore.ListNotifications(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []int64{alert.ID}, notificationIDs(notifications))
+
+	// Types the user turned off are left out of the inbox.
+	_, err = testStore.UpsertNotificationPreference(context.Background(), UpsertNotificationPreferenceParams{
+		Username:  user.Username,
+		Channel:   "in_app",
+		EventType: "transfer.received",
+	})
+	require.NoError(t, err)
+	arg.BeforeID = 0
+	notifications, err = testStore.ListNotifications(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []int64{alert.ID}, notificationIDs(notifications))
+}
+
+func TestClaimNotifications(t *testing.T) {
+	user := createRandomUser(t)
+	notification := createTestNotification(t, user.Username, "sms", "security.alert", NotificationPending)
+	require.False(t, notification.SentAt.Valid)
+	sent := createTestNotification(t, user.Username, "in_app", "security.alert", NotificationSent)
+
+	now := time.Now().Add(time.Second)
+	claimed, err := testStore.ClaimNotifications(context.Background(), ClaimNotificationsParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+	require.Contains(t, notificationIDs(claimed), notification.ID)
+	require.NotContains(t, notificationIDs(claimed), sent.ID)
+
+	// A claimed notification is hidden until its lease runs out.
+	again, err := testStore.ClaimNotifications(context.Background(), ClaimNotificationsParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+	require.NotContains(t, notificationIDs(again), notification.ID)
+
+	err = testStore.MarkNotificationFailed(context.Background(), MarkNotificationFailedParams{
+		Status:    NotificationPending,
+		LastError: sql.NullString{String: "provider down", Valid: true},
+		RetryAt:   now,
+		ID:        notification.ID,
+	})
+	require.NoError(t, err)
+
+	retried, err := testStore.ClaimNotifications(context.Background(), ClaimNotificationsParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+	require.Contains(t, notificationIDs(retried), notification.ID)
+
+	require.NoError(t, testStore.MarkNotificationSent(context.Background(), notification.ID))
+}
+
+func notificationIDs(notifications []Notification) []int64 {
+	ids := make([]int64, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = notification.ID
+	}
+	return ids
+}
@@ -6,48 +6,359 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	// Atomic increment/decrement pattern for concurrent safety
+	// Uses SET balance = balance + amount for race-condition-free operation
+	// Critical for maintaining consistency under concurrent modifications
+	// A negative amount is a debit
+	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	// Compare-and-swap alternative to locking the row with GetAccountForUpdate:
+	// the balance only changes if nothing changed it since the account was read
+	// at version. No row, sql.ErrNoRows, means it did and the caller should read
+	// the account again and retry. Suits accounts whose balance is read far more
+	// often than it is changed, e.g. deposits without a cash side
+	AddAccountBalanceIfVersion(ctx context.Context, arg AddAccountBalanceIfVersionParams) (Account, error)
+	// A positive amount places a hold, a negative one ends it. The accounts
+	// trigger refuses holds past what is available
+	AddAccountHeld(ctx context.Context, arg AddAccountHeldParams) (Account, error)
+	AddEntryTag(ctx context.Context, arg AddEntryTagParams) (Tag, error)
+	AddPotBalance(ctx context.Context, arg AddPotBalanceParams) (Pot, error)
+	// Adding a tag twice is a no-op
+	AddTransferTag(ctx context.Context, arg AddTransferTagParams) (Tag, error)
+	// Reviewed documents keep their decision but lose the uploaded file
+	AnonymizeKycDocumentsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	// Detaches the transfers and entries partitions of the months that ended by
+	// cutoff into the archive schema
+	ArchiveLedgerPartitions(ctx context.Context, cutoff time.Time) ([]string, error)
+	// A blocked session can't renew access tokens, even before it expires.
+	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	// Returns no row once the transfer has run or is being run
+	CancelScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	// SKIP LOCKED lets several schedulers poll without running a transfer twice
+	ClaimDueScheduledTransfers(ctx context.Context, arg ClaimDueScheduledTransfersParams) ([]ScheduledTransfer, error)
+	// SKIP LOCKED lets several workers poll without handing out a job twice
+	ClaimEmailJobs(ctx context.Context, arg ClaimEmailJobsParams) ([]EmailJob, error)
+	// Returns no row when the key is already taken. A concurrent claim waits for
+	// the first transaction to finish instead of failing
+	ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (IdempotencyKey, error)
+	// SKIP LOCKED lets several workers poll without handing out a delivery twice
+	ClaimNotifications(ctx context.Context, arg ClaimNotificationsParams) ([]Notification, error)
+	// SKIP LOCKED lets several workers poll without handing out a delivery twice
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error)
+	CloseAccountingPeriod(ctx context.Context, arg CloseAccountingPeriodParams) (AccountingPeriod, error)
+	// Challenges are single use: reading one deletes it
+	ConsumeWebauthnChallenge(ctx context.Context, arg ConsumeWebauthnChallengeParams) (WebauthnChallenge, error)
+	CountEmailJobsSince(ctx context.Context, arg CountEmailJobsSinceParams) (int64, error)
 	// Parameterized INSERT using positional arguments ($1, $2, $3) for SQL injection protection
 	// RETURNING clause fetches newly created row in a single roundtrip, saving a subsequent SELECT
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	// Late corrections to a closed period are posted in the open period and
+	// point back at the period they correct
+	CreateAdjustingEntry(ctx context.Context, arg CreateAdjustingEntryParams) (Entry, error)
+	// before and after are stored as JSON null when not given
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error)
+	CreateBalanceAdjustment(ctx context.Context, arg CreateBalanceAdjustmentParams) (BalanceAdjustment, error)
+	// Snapshots the closing balances of a page of the accounts that existed on
+	// day, worked out backwards from the current balances. Money set aside in a
+	// pot is booked as a pot entry, so pot balances are worked out the same way.
+	// Taking a day again replaces its snapshots
+	CreateBalanceSnapshots(ctx context.Context, arg CreateBalanceSnapshotsParams) ([]int64, error)
+	CreateBeneficiary(ctx context.Context, arg CreateBeneficiaryParams) (Beneficiary, error)
+	CreateEmailJob(ctx context.Context, arg CreateEmailJobParams) (EmailJob, error)
+	// Entries are booked once the balance of the account has been changed, in
+	// the same transaction, so balance_after is the balance the change left
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateEntryOfKind(ctx context.Context, arg CreateEntryOfKindParams) (Entry, error)
+	// Returns no row when the reference was already recorded. A concurrent
+	// insert of the same reference waits for the first transaction to finish
+	CreateExternalDeposit(ctx context.Context, arg CreateExternalDepositParams) (ExternalDeposit, error)
+	// A cross-currency transfer, recording the conversion it was made at. Its
+	// amount is the from_amount
+	CreateFXTransfer(ctx context.Context, arg CreateFXTransferParams) (Transfer, error)
+	// Fees are booked with the transfer they were charged on
+	CreateFeeEntry(ctx context.Context, arg CreateFeeEntryParams) (Entry, error)
+	CreateFxRate(ctx context.Context, arg CreateFxRateParams) (FxRate, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	// A day is accrued once, so runs can be repeated safely
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (int64, error)
+	CreateKycDocument(ctx context.Context, arg CreateKycDocumentParams) (CreateKycDocumentRow, error)
+	// Creates the transfers and entries partitions of the month that month
+	// falls in, unless they exist
+	CreateLedgerPartitions(ctx context.Context, month time.Time) error
+	CreateLoginEvent(ctx context.Context, arg CreateLoginEventParams) (LoginEvent, error)
+	// A delivery attempted right away is created with its first attempt made
+	// and locked_until set past it, so no sender retries it meanwhile
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error)
+	CreatePot(ctx context.Context, arg CreatePotParams) (Pot, error)
+	CreatePotMove(ctx context.Context, arg CreatePotMoveParams) (PotMove, error)
+	CreateRetentionRun(ctx context.Context, arg CreateRetentionRunParams) (RetentionRun, error)
+	CreateRetentionRunItem(ctx context.Context, arg CreateRetentionRunItemParams) (RetentionRunItem, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateStandingDataChange(ctx context.Context, arg CreateStandingDataChangeParams) (StandingDataChange, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferStatusChange(ctx context.Context, arg CreateTransferStatusChangeParams) (TransferStatusChange, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebauthnChallenge(ctx context.Context, arg CreateWebauthnChallengeParams) (WebauthnChallenge, error)
+	CreateWebauthnCredential(ctx context.Context, arg CreateWebauthnCredentialParams) (WebauthnCredential, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
+	// Returns no row once the request was answered
+	DeclinePaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
 	// Simple primary-key targeted DELETE operation
 	// CASCADE behavior depends on foreign key constraints defined in schema
 	// Returns no rows (exec) since we don't need the deleted data
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteBeneficiary(ctx context.Context, id int64) error
+	DeleteEntryTag(ctx context.Context, arg DeleteEntryTagParams) (int64, error)
+	// Only sessions that already expired before the cutoff are removed
+	DeleteExpiredSessionsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	// Releases the key of a request that failed, so it can be retried
+	DeleteIdempotentRequest(ctx context.Context, arg DeleteIdempotentRequestParams) error
+	DeleteLoginEventsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	DeleteSetting(ctx context.Context, id int64) error
+	DeleteTransferTag(ctx context.Context, arg DeleteTransferTagParams) (int64, error)
+	DeleteWebhookSubscription(ctx context.Context, arg DeleteWebhookSubscriptionParams) (int64, error)
+	// Queues the event for every subscription of the owner that wants it
+	EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error)
+	FinishIdempotentRequest(ctx context.Context, arg FinishIdempotentRequestParams) error
 	// Direct primary key lookup ensures O(1) performance via B-tree index
 	// LIMIT 1 optimizes query planning - tells PostgreSQL to stop after first match
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	// A user has at most one open account per currency (owner_currency_key), so
+	// the pair names an account without its ID
+	GetAccountByOwnerAndCurrency(ctx context.Context, arg GetAccountByOwnerAndCurrencyParams) (Account, error)
 	// Direct primary key lookup ensures O(1) performance via B-tree index
 	// LIMIT 1 optimizes query planning - tells PostgreSQL to stop after first match
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	// Money in and out of an account in [from_time, to_time), from its entries,
+	// and how many completed transfers it took part in
+	GetAccountSummary(ctx context.Context, arg GetAccountSummaryParams) (GetAccountSummaryRow, error)
+	GetAccountingPeriod(ctx context.Context, period time.Time) (AccountingPeriod, error)
+	// Resolves many accounts in one round trip. IDs that don't exist are left
+	// out, so callers compare what they got against what they asked for
+	GetAccountsByIDs(ctx context.Context, ids []int64) ([]Account, error)
+	GetBeneficiary(ctx context.Context, id int64) (Beneficiary, error)
+	// Jobs that are due but not picked up show that no worker is polling
+	GetEmailQueueHealth(ctx context.Context) (GetEmailQueueHealthRow, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetExternalDepositByReference(ctx context.Context, reference string) (ExternalDeposit, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	// Locks the hold so it is released or captured only once
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	// House accounts are looked up by what they are used for rather than by ID,
+	// so each environment can create its own
+	GetHouseAccount(ctx context.Context, arg GetHouseAccountParams) (Account, error)
+	// Debits are money leaving an account, credits money arriving
+	GetHouseTrialBalance(ctx context.Context, arg GetHouseTrialBalanceParams) ([]GetHouseTrialBalanceRow, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetKycDocument(ctx context.Context, id int64) (KycDocument, error)
+	GetKycDocumentForUpdate(ctx context.Context, id int64) (GetKycDocumentForUpdateRow, error)
+	GetNotificationPreference(ctx context.Context, arg GetNotificationPreferenceParams) (NotificationPreference, error)
+	GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error)
+	// Scoped to the account, so a pot of another account reads as missing
+	GetPotForUpdate(ctx context.Context, arg GetPotForUpdateParams) (Pot, error)
+	GetRetentionRun(ctx context.Context, id int64) (RetentionRun, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetStandingDataChange(ctx context.Context, id int64) (StandingDataChange, error)
+	// The balance of an account at from_time and at to_time, worked out
+	// backwards from a later balance like ListDailyBalances
+	GetStatementBalances(ctx context.Context, arg GetStatementBalancesParams) (GetStatementBalancesRow, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	GetTransferLimit(ctx context.Context, arg GetTransferLimitParams) (TransferLimit, error)
 	// Direct primary key lookup ensures O(1) performance via B-tree index
 	// LIMIT 1 optimizes query planning - tells PostgreSQL to stop after first match
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserForUpdate(ctx context.Context, username string) (User, error)
+	GetWebauthnCredential(ctx context.Context, credentialID []byte) (WebauthnCredential, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
+	// now() is the transaction start time, which is also what entries are stamped with
+	IsCurrentPeriodClosed(ctx context.Context) (bool, error)
+	// Everything that happened on an account, newest first: its transfers, in
+	// any status, and its other entries such as deposits, withdrawals and
+	// interest. Transfer entries are left out as the transfer stands for them.
+	// Amounts are signed from the account's side, in the currency given, and
+	// balance_after is what the row left on the account, null for transfers
+	// that moved no money. Rows are ordered by created_at, source and id, so a
+	// page can start after any row; a NULL before_time starts from the latest
+	ListAccountActivity(ctx context.Context, arg ListAccountActivityParams) ([]ListAccountActivityRow, error)
+	// Accounts whose balance isn't the sum of their entries, archived ones
+	// included
+	ListAccountBalanceMismatches(ctx context.Context, arg ListAccountBalanceMismatchesParams) ([]ListAccountBalanceMismatchesRow, error)
+	ListAccountingPeriods(ctx context.Context, arg ListAccountingPeriodsParams) ([]AccountingPeriod, error)
 	// Paginated query pattern with LIMIT/OFFSET for incremental data retrieval
 	// ORDER BY ensures stable pagination even with concurrent modifications
 	// Ordering by primary key is efficient due to clustered index usage
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	// Keyset pagination: the page starts after the last ID of the previous one,
+	// so deep pages stay cheap and don't shift when accounts are added
+	ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error)
+	ListAccountsWithUnpostedInterest(ctx context.Context, arg ListAccountsWithUnpostedInterestParams) ([]int64, error)
+	ListActiveHolds(ctx context.Context, accountID int64) ([]Hold, error)
+	ListAdjustingEntries(ctx context.Context, adjustsPeriod sql.NullTime) ([]Entry, error)
+	// Everything a user did, and everything done while impersonating them or as them
+	ListAuditLogs(ctx context.Context, arg ListAuditLogsParams) ([]AuditLog, error)
+	// Newest first
+	ListBalanceAdjustments(ctx context.Context, arg ListBalanceAdjustmentsParams) ([]BalanceAdjustment, error)
+	ListBalanceSnapshots(ctx context.Context, arg ListBalanceSnapshotsParams) ([]AccountBalanceSnapshot, error)
+	// The payee's name and currency come from the account, so they stay current
+	ListBeneficiaries(ctx context.Context, arg ListBeneficiariesParams) ([]ListBeneficiariesRow, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	// An account's opening balance is not an entry, so closing balances are
+	// worked out backwards from a later one: the first snapshot on or after
+	// to_day, or the current balance when there's none yet. Only the entries
+	// between from_day and that balance are read
+	ListDailyBalances(ctx context.Context, arg ListDailyBalancesParams) ([]ListDailyBalancesRow, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	// A non-empty tag keeps the entries the owner tagged with it
+	ListEntriesAfter(ctx context.Context, arg ListEntriesAfterParams) ([]Entry, error)
+	ListEntriesBetween(ctx context.Context, arg ListEntriesBetweenParams) ([]Entry, error)
+	// Newest first, paged like ListTransfersByAccountBefore
+	ListEntriesByAccountBefore(ctx context.Context, arg ListEntriesByAccountBeforeParams) ([]Entry, error)
+	ListEntryTags(ctx context.Context, arg ListEntryTagsParams) ([]string, error)
+	ListFxRateHistory(ctx context.Context, arg ListFxRateHistoryParams) ([]FxRate, error)
+	// The rate of every pair in force at a time.
+	ListFxRates(ctx context.Context, at time.Time) ([]FxRate, error)
+	ListIncomingPaymentRequests(ctx context.Context, arg ListIncomingPaymentRequestsParams) ([]PaymentRequest, error)
+	// Customer accounts that are not closed, with the money in their pots
+	// counted in, and the tenant of the owner to look up the rate with. The
+	// balance is the closing balance of day when it has been snapshotted, and
+	// the current one otherwise
+	ListInterestEligibleAccounts(ctx context.Context, arg ListInterestEligibleAccountsParams) ([]ListInterestEligibleAccountsRow, error)
+	// Document content is never listed, only fetched one at a time for review
+	ListKycDocuments(ctx context.Context, arg ListKycDocumentsParams) ([]ListKycDocumentsRow, error)
+	ListKycDocumentsByStatus(ctx context.Context, arg ListKycDocumentsByStatusParams) ([]ListKycDocumentsByStatusRow, error)
+	ListNotificationPreferences(ctx context.Context, username string) ([]NotificationPreference, error)
+	// The in-app inbox of a user, newest first, without the types the user
+	// turned off. A before_id of 0 starts from the latest notification
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListOutgoingPaymentRequests(ctx context.Context, arg ListOutgoingPaymentRequestsParams) ([]PaymentRequest, error)
+	// Newest first. A before_id of 0 starts from the latest transfer. A non-empty
+	// search keeps transfers whose memo matches it as an ILIKE pattern, a
+	// non-empty tag those the owner tagged with it. The other filters are off at
+	// their zero value: created_at in [from_time, to_time), the amount between
+	// min_amount and max_amount, direction sent or received from the owner's
+	// point of view, a counterparty account on either side and a currency on
+	// either side. to_amount is what the recipient got, in its currency. rate
+	// and fx_fee are 0 unless the conversion of a cross-currency transfer was
+	// recorded, which it wasn't before they were added
+	ListOwnerTransfers(ctx context.Context, arg ListOwnerTransfersParams) ([]ListOwnerTransfersRow, error)
+	ListPots(ctx context.Context, accountID int64) ([]Pot, error)
+	ListRetentionRules(ctx context.Context) ([]RetentionRule, error)
+	ListRetentionRunItems(ctx context.Context, runID int64) ([]RetentionRunItem, error)
+	ListRetentionRuns(ctx context.Context, arg ListRetentionRunsParams) ([]RetentionRun, error)
+	ListScheduledTransfers(ctx context.Context, arg ListScheduledTransfersParams) ([]ScheduledTransfer, error)
+	ListSettings(ctx context.Context) ([]Setting, error)
+	// Where a user's money went in [from_time, to_time): completed transfers from
+	// any of their accounts to other users, grouped by the receiving user and the
+	// currency the money left in. Moves between the user's own accounts are left
+	// out
+	ListSpendingByCounterparty(ctx context.Context, arg ListSpendingByCounterpartyParams) ([]ListSpendingByCounterpartyRow, error)
+	// Newest first so the admin timeline reads top-down from the current value
+	ListStandingDataChanges(ctx context.Context, arg ListStandingDataChangesParams) ([]StandingDataChange, error)
+	// Money out of the owner's accounts per tag and currency: tagged transfers
+	// they sent, in the source currency, and tagged debit entries
+	ListTagSpending(ctx context.Context, owner string) ([]ListTagSpendingRow, error)
+	// The accounts an account exchanged the most money with in
+	// [from_time, to_time), over completed transfers. Amounts are those of the
+	// transfers, in the currency of the sending account
+	ListTopCounterparties(ctx context.Context, arg ListTopCounterpartiesParams) ([]ListTopCounterpartiesRow, error)
+	// Completed transfers must have booked a debit of their amount on the sender
+	// and a credit on the recipient, plus a fee entry for the fee they kept, and
+	// nothing else. Cross-currency transfers only net to zero at their rate, so
+	// the credit is checked against to_amount. Transfers that moved no money must
+	// have no entries
+	ListTransferEntryMismatches(ctx context.Context, arg ListTransferEntryMismatchesParams) ([]ListTransferEntryMismatchesRow, error)
+	// used_last_24h counts the transfers out of the user's account in the
+	// currency, the same total TransferTx checks max_daily_total against
+	ListTransferLimits(ctx context.Context, username string) ([]ListTransferLimitsRow, error)
+	ListTransferStatusChanges(ctx context.Context, transferIds []int64) ([]TransferStatusChange, error)
+	ListTransferTags(ctx context.Context, arg ListTransferTagsParams) ([]string, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	// Newest first, by (created_at, id) so the order is stable for transfers
+	// made in the same instant. The first page has a NULL before_created_at, the
+	// next ones the created_at and id of the last transfer of the page before
+	ListTransfersByAccountBefore(ctx context.Context, arg ListTransfersByAccountBeforeParams) ([]Transfer, error)
+	ListUnpostedInterestAccruals(ctx context.Context, accountID int64) ([]InterestAccrual, error)
+	ListWebauthnCredentials(ctx context.Context, username string) ([]WebauthnCredential, error)
+	// Newest first. A before_id of 0 starts from the latest delivery
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookSubscriptions(ctx context.Context, owner string) ([]WebhookSubscription, error)
+	// Failed jobs either wait until retry_at or are given up on for good
+	MarkEmailJobFailed(ctx context.Context, arg MarkEmailJobFailedParams) error
+	MarkEmailJobSent(ctx context.Context, id int64) error
+	MarkInterestPosted(ctx context.Context, arg MarkInterestPostedParams) (int64, error)
+	// Failed deliveries either wait until retry_at or are given up on for good
+	MarkNotificationFailed(ctx context.Context, arg MarkNotificationFailedParams) error
+	MarkNotificationSent(ctx context.Context, id int64) error
+	MarkPaymentRequestAccepted(ctx context.Context, arg MarkPaymentRequestAcceptedParams) (PaymentRequest, error)
+	// Failed transfers either wait until retry_at or are given up on for good
+	MarkScheduledTransferFailed(ctx context.Context, arg MarkScheduledTransferFailedParams) error
+	MarkScheduledTransferSucceeded(ctx context.Context, arg MarkScheduledTransferSucceededParams) error
+	MarkWebhookDeliveryDelivered(ctx context.Context, arg MarkWebhookDeliveryDeliveredParams) error
+	// Failed deliveries either wait until retry_at or are given up on for good
+	MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error
+	// Only replaces the hash it was computed from, so a concurrent password change wins
+	RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) (int64, error)
+	// Ends an active hold as released or captured. No row means it had already
+	// ended
+	ResolveHold(ctx context.Context, arg ResolveHoldParams) (Hold, error)
+	ReviewKycDocument(ctx context.Context, arg ReviewKycDocumentParams) (ReviewKycDocumentRow, error)
+	// Admin lookup across all owners. Each filter is off at its zero value
+	SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error)
+	// Newest first. A before_id of 0 starts from the latest entry, and every
+	// other filter is off when empty. actor matches impersonators too
+	SearchAuditLogs(ctx context.Context, arg SearchAuditLogsParams) ([]AuditLog, error)
+	// Admin lookup across all owners, newest first. Each filter is off at its
+	// zero value: an account on either side, an owner on either side, created_at
+	// in [from_time, to_time) and the amount from min_amount up
+	SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]Transfer, error)
+	// Admin lookup. A non-empty search matches the username, email or full name
+	// as an ILIKE pattern, a non-empty status only users in it
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error)
 	// Single-row UPDATE targeting primary key for efficient index scan
 	// RETURNING clause eliminates need for separate SELECT after UPDATE
-	// This is an absolute-value update (overwrites existing balance)
-	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
-	// Atomic increment/decrement pattern for concurrent safety
-	// Uses SET balance = balance + $2 for race-condition-free operation
-	// Critical for maintaining consistency under concurrent modifications
-	UpdateAccountBalance(ctx context.Context, arg UpdateAccountBalanceParams) (Account, error)
+	// Overwrites the balance without an entry, so it is only for fixtures and
+	// repairs; money movements use AddAccountBalance
+	SetAccountBalance(ctx context.Context, arg SetAccountBalanceParams) (Account, error)
+	SetExternalDepositEntry(ctx context.Context, arg SetExternalDepositEntryParams) (ExternalDeposit, error)
+	SetIdempotencyKeyResponse(ctx context.Context, arg SetIdempotencyKeyResponseParams) error
+	// Claims the key for a request that isn't made in one transaction, until
+	// FinishIdempotentRequest stores its response. A claim still in progress from
+	// before stale_before was left by a server that died mid-request, so a retry
+	// of the same request takes it over. Returns no row when the key is taken
+	StartIdempotentRequest(ctx context.Context, arg StartIdempotentRequestParams) (IdempotencyKey, error)
+	SumEntriesByKind(ctx context.Context, arg SumEntriesByKindParams) ([]SumEntriesByKindRow, error)
+	SumPotBalances(ctx context.Context, accountID int64) (int64, error)
+	SumTransfersSince(ctx context.Context, arg SumTransfersSinceParams) (int64, error)
+	SumUnpostedInterest(ctx context.Context, arg SumUnpostedInterestParams) (int64, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfer, error)
+	// NULL params leave the column unchanged
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error)
+	UpdateUserFullName(ctx context.Context, arg UpdateUserFullNameParams) (User, error)
+	UpdateUserKycTier(ctx context.Context, arg UpdateUserKycTierParams) (User, error)
+	UpdateUserPhoneNumber(ctx context.Context, arg UpdateUserPhoneNumberParams) (User, error)
+	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error)
+	UpdateUserTotpSecret(ctx context.Context, arg UpdateUserTotpSecretParams) (User, error)
+	UpdateWebauthnCredentialSignCount(ctx context.Context, arg UpdateWebauthnCredentialSignCountParams) error
+	UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) (NotificationPreference, error)
+	UpsertRetentionRule(ctx context.Context, arg UpsertRetentionRuleParams) (RetentionRule, error)
+	UpsertSetting(ctx context.Context, arg UpsertSettingParams) (Setting, error)
+	UpsertTransferLimit(ctx context.Context, arg UpsertTransferLimitParams) (TransferLimit, error)
+	// Only moves forward, so a code is accepted once however many requests race with it
+	UseUserTotpStep(ctx context.Context, arg UseUserTotpStepParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
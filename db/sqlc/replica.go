@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var ErrReplicaNotReplaying = errors.New("replica is not replaying WAL")
+
+// ReplicaHealth tracks whether a read replica is close enough to the primary
+// to serve reads. It starts out unhealthy, so nothing is read from the replica
+// before the first successful check.
+type ReplicaHealth struct {
+	primary     *sql.DB
+	replica     *sql.DB
+	maxLagBytes int64
+	healthy     atomic.Bool
+	lagBytes    atomic.Int64
+}
+
+// NewReplicaHealth creates a health tracker that considers the replica
+// unhealthy once it is more than maxLagBytes of WAL behind the primary.
+func NewReplicaHealth(primary, replica *sql.DB, maxLagBytes int64) *ReplicaHealth {
+	return &ReplicaHealth{
+		primary:     primary,
+		replica:     replica,
+		maxLagBytes: maxLagBytes,
+	}
+}
+
+// Healthy reports whether reads may go to the replica.
+func (health *ReplicaHealth) Healthy() bool {
+	return health.healthy.Load()
+}
+
+// LagBytes is the replication lag measured by the last successful check.
+func (health *ReplicaHealth) LagBytes() int64 {
+	return health.lagBytes.Load()
+}
+
+// Check measures the lag as the distance between the primary's current WAL
+// position and the last position the replica replayed, and updates Healthy.
+func (health *ReplicaHealth) Check(ctx context.Context) error {
+	lag, err := health.measureLag(ctx)
+	if err != nil {
+		health.setHealthy(false)
+		return err
+	}
+
+	health.lagBytes.Store(lag)
+	health.setHealthy(lag <= health.maxLagBytes)
+	return nil
+}
+
+func (health *ReplicaHealth) measureLag(ctx context.Context) (int64, error) {
+	var primaryLSN string
+	err := health.primary.QueryRowContext(ctx, `SELECT pg_current_wal_lsn()::text`).Scan(&primaryLSN)
+	if err != nil {
+		return 0, err
+	}
+
+	// pg_last_wal_replay_lsn is NULL on a server that is not in recovery.
+	var lag sql.NullInt64
+	err = health.replica.QueryRowContext(ctx,
+		`SELECT pg_wal_lsn_diff($1::pg_lsn, pg_last_wal_replay_lsn())::bigint`, primaryLSN).Scan(&lag)
+	if err != nil {
+		return 0, err
+	}
+	if !lag.Valid {
+		return 0, ErrReplicaNotReplaying
+	}
+	if lag.Int64 < 0 {
+		// The replica replayed WAL written after we read the primary position.
+		return 0, nil
+	}
+	return lag.Int64, nil
+}
+
+func (health *ReplicaHealth) setHealthy(healthy bool) {
+	if health.healthy.Swap(healthy) != healthy {
+		if healthy {
+			slog.Info("read replica healthy, routing reads to it", "lag_bytes", health.LagBytes())
+		} else {
+			slog.Warn("read replica unhealthy, falling back to the primary")
+		}
+	}
+}
+
+// Start checks the replica every interval until ctx is done.
+func (health *ReplicaHealth) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := health.Check(ctx); err != nil {
+			slog.Warn("read replica health check failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// replicaRouter sends read-only statements to the replica while it is
+// healthy and everything else to the primary. Transactions never go through
+// it: execTx always begins on the primary.
+type replicaRouter struct {
+	primary *sql.DB
+	replica *sql.DB
+	health  *ReplicaHealth
+}
+
+func (router *replicaRouter) readDB(query string) DBTX {
+	if router.health.Healthy() && isReadOnlyQuery(query) {
+		return router.replica
+	}
+	return router.primary
+}
+
+func (router *replicaRouter) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return router.primary.ExecContext(ctx, query, args...)
+}
+
+func (router *replicaRouter) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return router.primary.PrepareContext(ctx, query)
+}
+
+func (router *replicaRouter) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return router.readDB(query).QueryContext(ctx, query, args...)
+}
+
+func (router *replicaRouter) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return router.readDB(query).QueryRowContext(ctx, query, args...)
+}
+
+var (
+	sqlCommentLine = regexp.MustCompile(`(?m)^\s*--.*$`)
+	lockingClause  = regexp.MustCompile(`(?i)\bFOR\s+(NO\s+KEY\s+UPDATE|UPDATE|SHARE|KEY\s+SHARE)\b`)
+)
+
+// isReadOnlyQuery reports whether a statement can run on a replica: a plain
+// SELECT that takes no row locks.
+func isReadOnlyQuery(query string) bool {
+	query = strings.TrimSpace(sqlCommentLine.ReplaceAllString(query, ""))
+	if len(query) < len("SELECT") || !strings.EqualFold(query[:len("SELECT")], "SELECT") {
+		return false
+	}
+	return !lockingClause.MatchString(query)
+}
+
+// WithReadReplica routes non-transactional reads to the replica while health
+// reports it as caught up. Writes, locking reads and transactions always use
+// the primary.
+func WithReadReplica(replica *sql.DB, health *ReplicaHealth) StoreOption {
+	return func(store *SQLStore) {
+		store.Queries = New(&replicaRouter{
+			primary: store.db,
+			replica: replica,
+			health:  health,
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsReadOnlyQuery(t *testing.T) {
+	require.True(t, isReadOnlyQuery(getAccount))
+	require.True(t, isReadOnlyQuery(listAccounts))
+	require.False(t, isReadOnlyQuery(getAccountForUpdate))
+	require.False(t, isReadOnlyQuery(createAccount))
+	require.False(t, isReadOnlyQuery(addAccountBalance))
+	require.False(t, isReadOnlyQuery("SELECT * FROM accounts WHERE id = $1 FOR SHARE"))
+	require.False(t, isReadOnlyQuery("WITH moved AS (DELETE FROM entries RETURNING *) SELECT * FROM moved"))
+}
+
+func TestReplicaHealthNotReplaying(t *testing.T) {
+	// The test database is a primary, so it never replays WAL.
+	health := NewReplicaHealth(testDB, testDB, 1024)
+
+	err := health.Check(context.Background())
+	require.ErrorIs(t, err, ErrReplicaNotReplaying)
+	require.False(t, health.Healthy())
+}
+
+func TestReplicaRouterFallsBackToPrimary(t *testing.T) {
+	health := NewReplicaHealth(testDB, testDB, 1024)
+	store := NewStore(testDB, WithReadReplica(testDB, health)).(*SQLStore)
+
+	router := store.unscoped().(*replicaRouter)
+	require.False(t, health.Healthy())
+	require.Equal(t, DBTX(testDB), router.readDB(getAccount))
+
+	health.healthy.Store(true)
+	require.Equal(t, DBTX(router.replica), router.readDB(getAccount))
+	require.Equal(t, DBTX(router.primary), router.readDB(getAccountForUpdate))
+}
@@ -0,0 +1,22 @@
+package db
+
+import "fmt"
+
+// Statuses of a scheduled transfer.
+const (
+	ScheduledTransferPending   = "pending"
+	ScheduledTransferSucceeded = "succeeded"
+	ScheduledTransferFailed    = "failed"
+	ScheduledTransferCancelled = "cancelled"
+)
+
+// ScheduledTransferIdempotency is the key a scheduled transfer is executed
+// under. A scheduler that crashes after the transfer commits finds the key
+// used on its next attempt instead of moving the money again.
+func ScheduledTransferIdempotency(scheduled ScheduledTransfer) ClaimIdempotencyKeyParams {
+	return ClaimIdempotencyKeyParams{
+		Username:    scheduled.Owner,
+		Key:         fmt.Sprintf("scheduled-transfer-%d", scheduled.ID),
+		RequestHash: fmt.Sprintf("scheduled_transfers/%d", scheduled.ID),
+	}
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomScheduledTransfer(t *testing.T, executeAt time.Time) ScheduledTransfer {
+	from := createRandomAccount(t)
+	to := createRandomAccount(t)
+
+	scheduled, err := testStore.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+		Owner:         from.Owner,
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        10,
+		ExecuteAt:     executeAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferPending, scheduled.Status)
+	require.Zero(t, scheduled.Attempts)
+	require.False(t, scheduled.TransferID.Valid)
+	return scheduled
+}
+
+func TestClaimDueScheduledTransfers(t *testing.T) {
+	due := createRandomScheduledTransfer(t, time.Now().Add(-time.Minute))
+	later := createRandomScheduledTransfer(t, time.Now().Add(time.Hour))
+
+	now := time.Now().Add(time.Second)
+	claimed, err := testStore.ClaimDueScheduledTransfers(context.Background(), ClaimDueScheduledTransfersParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+	ids := scheduledTransferIDs(claimed)
+	require.Contains(t, ids, due.ID)
+	require.NotContains(t, ids, later.ID)
+
+	// A claimed transfer can neither be claimed again nor cancelled.
+	again, err := testStore.ClaimDueScheduledTransfers(context.Background(), ClaimDueScheduledTransfersParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+	require.NotContains(t, scheduledTransferIDs(again), due.ID)
+
+	_, err = testStore.CancelScheduledTransfer(context.Background(), due.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	transfer, err := testStore.CreateTransfer(context.Background(), CreateTransferParams{
+		FromAccountID: due.FromAccountID,
+		ToAccountID:   due.ToAccountID,
+		Amount:        due.Amount,
+		Status:        TransferCompleted,
+	})
+	require.NoError(t, err)
+	err = testStore.MarkScheduledTransferSucceeded(context.Background(), MarkScheduledTransferSucceededParams{
+		TransferID: sql.NullInt64{Int64: transfer.ID, Valid: true},
+		ID:         due.ID,
+	})
+	require.NoError(t, err)
+
+	done, err := testStore.GetScheduledTransfer(context.Background(), due.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferSucceeded, done.Status)
+	require.Equal(t, int32(1), done.Attempts)
+	require.Equal(t, transfer.ID, done.TransferID.Int64)
+	require.True(t, done.ExecutedAt.Valid)
+}
+
+func TestCancelScheduledTransfer(t *testing.T) {
+	scheduled := createRandomScheduledTransfer(t, time.Now().Add(time.Hour))
+
+	cancelled, err := testStore.CancelScheduledTransfer(context.Background(), scheduled.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferCancelled, cancelled.Status)
+
+	_, err = testStore.CancelScheduledTransfer(context.Background(), scheduled.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func scheduledTransferIDs(rows []ScheduledTransfer) []int64 {
+	ids := make([]int64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	return ids
+}
@@ -3,13 +3,51 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+//go:generate mockgen -package mockdb -destination ../mock/store.go -build_flags=-mod=mod github.com/ankurdas111111/simplebank/db/sqlc Store
+
+// Store runs queries and transactions. API handlers and workers are tested
+// against the mock in db/mock, generated from it.
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
 	TransferTxFX(ctx context.Context, arg TransferTxFXParams) (TransferTxResult, error)
+	UpdateTransferStatusTx(ctx context.Context, arg UpdateTransferStatusTxParams) (TransferTxResult, error)
+	UpdateStandingDataTx(ctx context.Context, arg UpdateStandingDataTxParams) (StandingDataChange, error)
+	RevertStandingDataChangeTx(ctx context.Context, arg RevertStandingDataChangeTxParams) (StandingDataChange, error)
+	ReviewKycDocumentTx(ctx context.Context, arg ReviewKycDocumentTxParams) (ReviewKycDocumentTxResult, error)
+	ClosePeriodTx(ctx context.Context, arg ClosePeriodTxParams) (ClosePeriodTxResult, error)
+	PostAdjustmentTx(ctx context.Context, arg PostAdjustmentTxParams) (PostAdjustmentTxResult, error)
+	GetPeriodReport(ctx context.Context, period time.Time) (PeriodReport, error)
+	PurgeRetentionTx(ctx context.Context, arg PurgeRetentionTxParams) (RetentionReport, error)
+	GetRetentionReport(ctx context.Context, runID int64) (RetentionReport, error)
+	EnqueueEmailTx(ctx context.Context, arg EnqueueEmailTxParams) (EmailJob, error)
+	UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (User, error)
+	DepositTx(ctx context.Context, arg DepositTxParams) (DepositTxResult, error)
+	ExternalDepositTx(ctx context.Context, arg ExternalDepositTxParams) (ExternalDepositTxResult, error)
+	WithdrawTx(ctx context.Context, arg WithdrawTxParams) (WithdrawTxResult, error)
+	AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error)
+	CloseAccountTx(ctx context.Context, arg CloseAccountTxParams) (CloseAccountTxResult, error)
+	MovePotMoneyTx(ctx context.Context, arg MovePotMoneyTxParams) (MovePotMoneyTxResult, error)
+	PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error)
+	AcceptPaymentRequestTx(ctx context.Context, arg AcceptPaymentRequestTxParams) (AcceptPaymentRequestTxResult, error)
+	PlaceHoldTx(ctx context.Context, arg PlaceHoldTxParams) (HoldTxResult, error)
+	ReleaseHoldTx(ctx context.Context, holdID int64) (HoldTxResult, error)
+	CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error)
+	BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error)
+	UpdateNotificationPreferencesTx(ctx context.Context, arg UpdateNotificationPreferencesTxParams) ([]NotificationPreference, error)
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+	Ping(ctx context.Context) error
+	SchemaVersion(ctx context.Context) (SchemaVersion, error)
 }
 
 // Store implements the Repository pattern for database access
@@ -17,32 +55,235 @@ type Store interface {
 type SQLStore struct {
 	db *sql.DB      // Maintains a single connection pool for DB operations
 	*Queries        // Embeds query methods via composition (preferred over inheritance in Go)
+	faults FaultInjector
+	transfers []TransferPublisher
+	tracer Tracer
+	// txOptions is what execTx begins transactions with, nil for the
+	// defaults of the database.
+	txOptions *sql.TxOptions
+	logger *slog.Logger
+}
+
+// FaultInjector simulates database failures around transactions. It is only
+// set in development, see the chaos package.
+type FaultInjector interface {
+	// BeforeTx may delay or fail a transaction before it begins.
+	BeforeTx(ctx context.Context) error
+	// AfterCommit may report an error for a transaction that did commit.
+	AfterCommit(ctx context.Context) error
+}
+
+// TransferPublisher is told about every transfer once it has committed, for
+// pushing balance updates to connected clients. See the events package.
+type TransferPublisher interface {
+	PublishTransfer(result TransferTxResult)
+}
+
+// StoreOption configures optional SQLStore behaviour.
+type StoreOption func(store *SQLStore)
+
+// WithFaultInjector makes every transaction go through the injector.
+func WithFaultInjector(faults FaultInjector) StoreOption {
+	return func(store *SQLStore) {
+		store.faults = faults
+	}
+}
+
+// WithTransferPublisher publishes committed transfers to publisher. It may be
+// given more than once.
+func WithTransferPublisher(publisher TransferPublisher) StoreOption {
+	return func(store *SQLStore) {
+		store.transfers = append(store.transfers, publisher)
+	}
+}
+
+// WithTxIsolation makes execTx begin transactions at level, unless a
+// transaction asks for a level of its own with execTxWith.
+func WithTxIsolation(level sql.IsolationLevel) StoreOption {
+	return func(store *SQLStore) {
+		store.txOptions = &sql.TxOptions{Isolation: level}
+	}
+}
+
+// WithLogger logs through logger, e.g. the retries of transactions that
+// conflicted. Without it the store logs through slog's default logger.
+func WithLogger(logger *slog.Logger) StoreOption {
+	return func(store *SQLStore) {
+		store.logger = logger
+	}
+}
+
+// ParseIsolationLevel parses an isolation level the way Postgres names it,
+// e.g. "repeatable read". Empty is the default of the database.
+func ParseIsolationLevel(name string) (sql.IsolationLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "":
+		return sql.LevelDefault, nil
+	case "read committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	}
+	return sql.LevelDefault, fmt.Errorf("unsupported isolation level %q", name)
 }
 
 // NewStore constructs a Store instance with dependency injection pattern
 // This follows Go's preference for explicit dependencies over global state
-func NewStore(db *sql.DB) Store {
-	return &SQLStore{
+func NewStore(db *sql.DB, opts ...StoreOption) Store {
+	store := &SQLStore{
 		db:      db,
 		Queries: New(db), // Uses constructor pattern rather than direct initialization
+		logger:  slog.Default(),
 	}
+	for _, opt := range opts {
+		opt(store)
+	}
+	store.Queries = New(&scopedDB{store: store, db: store.Queries.db})
+	return store
+}
+
+// Ping checks that the primary database is reachable.
+func (store *SQLStore) Ping(ctx context.Context) error {
+	return store.db.PingContext(ctx)
 }
 
+// SchemaVersion is the migration state golang-migrate keeps in schema_migrations.
+type SchemaVersion struct {
+	Version int64 `json:"version"`
+	// Dirty is set while a migration is running, or after one failed halfway.
+	Dirty bool `json:"dirty"`
+}
+
+// SchemaVersion reads the migration state of the primary database.
+func (store *SQLStore) SchemaVersion(ctx context.Context) (SchemaVersion, error) {
+	var version SchemaVersion
+	err := store.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version.Version, &version.Dirty)
+	return version, err
+}
+
+const (
+	// maxTxAttempts bounds how often execTx runs a transaction that keeps
+	// losing to concurrent ones.
+	maxTxAttempts = 3
+	// txRetryBackoff is the mean wait before the first retry. It doubles for
+	// every retry after that.
+	txRetryBackoff = 20 * time.Millisecond
+)
+
 // execTx implements the functional options pattern for transaction execution
 // This higher-order function accepts a function parameter for execution within a tx context
 // (Higher-order functions are a key Go idiom for extending behavior)
+//
+// A transaction that fails on a serialization failure or deadlock is run
+// again, so fn may be called more than once and must not carry state from one
+// call to the next. Transactions begin with the options of the store, see
+// WithTxIsolation.
 func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+	return store.execTxWith(ctx, store.txOptions, fn)
+}
+
+// execTxWith is execTx for a transaction that needs options of its own, e.g.
+// a report that must read a single snapshot. Isolation levels above read
+// committed fail more transactions on conflicts, which are retried the same
+// way.
+//
+// Called in InTx, fn runs in a savepoint of its transaction instead, and a
+// conflict is retried by InTx.
+func (store *SQLStore) execTxWith(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	if scope := store.scope(ctx); scope != nil {
+		return scope.runTx(ctx, opts, fn)
+	}
+	return store.retryTx(ctx, func(attempt int) error {
+		return store.runTx(ctx, attempt, opts, fn)
+	})
+}
+
+// retryTx calls run until it returns nil or an error other than a conflict,
+// at most maxTxAttempts times.
+func (store *SQLStore) retryTx(ctx context.Context, run func(attempt int) error) error {
+	backoff := txRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := run(attempt)
+		if attempt == maxTxAttempts || !isTxConflict(err) {
+			return err
+		}
+
+		// Jitter keeps the transactions that conflicted from retrying in step.
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff)))
+		store.logger.LogAttrs(ctx, slog.LevelInfo, "retrying transaction after a conflict",
+			slog.Int("attempt", attempt),
+			slog.Duration("wait", wait),
+			slog.String("error", err.Error()),
+		)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// isTxConflict reports errors Postgres returns for a transaction that lost to
+// a concurrent one and was rolled back, which succeed when tried again.
+func isTxConflict(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code.Name() {
+	case "serialization_failure", "deadlock_detected":
+		return true
+	}
+	return false
+}
+
+// runTx runs fn once in a transaction, committing when it returns nil.
+func (store *SQLStore) runTx(ctx context.Context, attempt int, opts *sql.TxOptions, fn func(*Queries) error) (err error) {
+	committed := false
+	if store.tracer != nil {
+		var span Span
+		ctx, span = store.tracer.Start(ctx, "tx")
+		span.SetAttribute("tx.attempt", attempt)
+		if opts != nil {
+			span.SetAttribute("tx.isolation", opts.Isolation.String())
+		}
+		defer func() {
+			outcome := TxRolledBack
+			switch {
+			case committed:
+				outcome = TxCommitted
+			case isTxConflict(err):
+				outcome = TxConflict
+			}
+			span.SetAttribute("tx.outcome", outcome)
+			span.End(err)
+		}()
+	}
+
+	if store.faults != nil {
+		if err := store.faults.BeforeTx(ctx); err != nil {
+			return err
+		}
+	}
+
 	// BeginTx accepts a context for propagating cancellation and deadlines
-	tx, err := store.db.BeginTx(ctx, nil)
+	tx, err := store.db.BeginTx(ctx, opts)
 	if err != nil {
 		return err // Early return pattern for error handling (preferred in Go)
 	}
 
 	// Creates a query executor scoped to this transaction
-	q := New(tx)
+	var db DBTX = tx
+	if store.tracer != nil {
+		db = &tracedDB{db: tx, tracer: store.tracer, span: ctx}
+	}
+	q := New(db)
 	
 	// Execute the callback, maintaining the error in local scope
-	err = fn(q)
+	err = balanceViolation(fn(q))
 	
 	// Uses deferred execution via explicit error handling rather than defer
 	// This provides more granular control over the transaction outcome
@@ -56,7 +297,46 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	}
 	
 	// Explicit commit required in Go (no auto-commit like some ORMs)
-	return tx.Commit()
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	committed = true
+	if store.faults == nil {
+		return nil
+	}
+	return store.faults.AfterCommit(ctx)
+}
+
+// savepointName names the savepoints inSavepoint sets. Savepoints of the same
+// name nest: a rollback to or release of the name applies to the innermost.
+const savepointName = "nested"
+
+// inSavepoint runs fn in a savepoint of the transaction q runs in, so a
+// composite operation can undo one of its parts and go on, e.g. a batch that
+// skips the transfers that fail. failed is the error of fn, whose changes are
+// rolled back. err is an error the transaction can't go on from: the
+// savepoint couldn't be set or rolled back, or fn lost a conflict, which
+// execTx retries the whole transaction for.
+func inSavepoint(ctx context.Context, q *Queries, fn func(*Queries) error) (failed error, err error) {
+	if _, err := q.db.ExecContext(ctx, "SAVEPOINT "+savepointName); err != nil {
+		return nil, err
+	}
+
+	failed = balanceViolation(fn(q))
+	if isTxConflict(failed) {
+		return nil, failed
+	}
+	if failed != nil {
+		if _, err := q.db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepointName); err != nil {
+			return nil, fmt.Errorf("savepoint err: %v, rb err: %v", failed, err)
+		}
+	}
+	// Rolling back keeps the savepoint, so it is released either way.
+	if _, err := q.db.ExecContext(ctx, "RELEASE SAVEPOINT "+savepointName); err != nil {
+		return nil, err
+	}
+	return failed, nil
 }
 
 // TransferTxParams uses struct field tags for JSON serialization
@@ -65,6 +345,14 @@ type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"` // Uses lowercase+underscore naming for external representation
 	ToAccountID   int64 `json:"to_account_id"`   // But keeps CamelCase for Go identifiers (idiomatic Go style)
 	Amount        int64 `json:"amount"`          // Uses int64 for precise currency representation (avoid float)
+	// Optional: stored NULL when empty.
+	Memo string `json:"memo,omitempty"`
+	// Optional: with a Key, the transfer happens at most once per key and its
+	// result is stored for replay.
+	Idempotency ClaimIdempotencyKeyParams `json:"-"`
+	// Creates the transfer pending: no money moves until it is completed
+	// with UpdateTransferStatusTx.
+	Pending bool `json:"pending,omitempty"`
 }
 
 type TransferTxFXParams struct {
@@ -73,6 +361,10 @@ type TransferTxFXParams struct {
 	FromAmount    int64   `json:"from_amount"`
 	ToAmount      int64   `json:"to_amount"`
 	Rate          float64 `json:"rate"`
+	// Part of FromAmount kept as the conversion fee.
+	Fee           int64   `json:"fee"`
+	Memo          string  `json:"memo,omitempty"`
+	Idempotency   ClaimIdempotencyKeyParams `json:"-"`
 }
 
 // TransferTxResult uses value semantics for immutable return data
@@ -83,6 +375,9 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`   
 	FromEntry   Entry    `json:"from_entry"`   
 	ToEntry     Entry    `json:"to_entry"`     
+	// The credit of the fee the transfer kept, if any. It is on a house
+	// account, so it's left out of responses.
+	FeeEntry *Entry `json:"-"`
 }
 
 // addAccountsForUpdate demonstrates the multi-value return idiom in Go
@@ -138,151 +433,287 @@ func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (Tr
 	// This is a common Go pattern for transactional operations
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
+		result, err = transferTx(ctx, q, arg)
+		return err
+	})
+	if err == nil && !arg.Pending {
+		store.publishTransfers(ctx, result)
+	}
 
-		// Sequence of operations with chain-style error handling
-		// Each operation proceeds only if previous ones succeeded
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
+	return result, err // Return both result and error to let caller handle errors
+}
+
+// publishTransfers hands committed transfers to the publishers. It must only
+// be called after the transaction has committed. Called in InTx, it waits for
+// the transaction of InTx to commit.
+func (store *SQLStore) publishTransfers(ctx context.Context, results ...TransferTxResult) {
+	if scope := store.scope(ctx); scope != nil {
+		scope.afterCommit = append(scope.afterCommit, func() {
+			store.publishTransfers(context.Background(), results...)
 		})
-		if err != nil {
-			return err // Early return on failure
+		return
+	}
+	for _, publisher := range store.transfers {
+		for _, result := range results {
+			publisher.PublishTransfer(result)
 		}
+	}
+}
 
-		// Entry creation follows the same pattern
-		// Note that we use negative value for outgoing money - avoids separate operation types
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount, // Unary negation operator for opposing operations
+// transferTx moves the money inside a transaction the caller owns, so other
+// transactions can make a transfer part of a larger change.
+func transferTx(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	// Closed periods are frozen; corrections go through PostAdjustmentTx
+	err := checkPeriodOpen(ctx, q)
+	if err != nil {
+		return result, err
+	}
+
+	err = claimIdempotency(ctx, q, arg.Idempotency)
+	if err != nil {
+		return result, err
+	}
+
+	err = checkTransferLimit(ctx, q, arg.FromAccountID, arg.Amount)
+	if err != nil {
+		return result, err
+	}
+
+	status := TransferCompleted
+	if arg.Pending {
+		status = TransferPending
+	}
+
+	// Sequence of operations with chain-style error handling
+	// Each operation proceeds only if previous ones succeeded
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		Memo:          newMemo(arg.Memo),
+		Status:        status,
+	})
+	if err != nil {
+		return result, err // Early return on failure
+	}
+
+	// A pending transfer moves no money until UpdateTransferStatusTx
+	// completes it.
+	if arg.Pending {
+		err = loadTransferAccounts(ctx, q, &result)
+		if err == nil {
+			err = checkAccountsActive(result.FromAccount, result.ToAccount)
+		}
+	} else {
+		err = moveTransferMoney(ctx, q, &result, result.Transfer.ID, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	err = enqueueWebhookEvent(ctx, q, WebhookEventTransferCreated, result.Transfer, result.FromAccount.Owner, result.ToAccount.Owner)
+	if err != nil {
+		return result, err
+	}
+
+	err = saveIdempotentResponse(ctx, q, arg.Idempotency, result)
+	return result, err
+}
+
+// moveTransferMoney moves the money between the two accounts and books the
+// entries of a transfer with the balances they leave, then checks both
+// accounts can take the change.
+func moveTransferMoney(ctx context.Context, q *Queries, result *TransferTxResult, transferID, fromAccountID, toAccountID, amount int64) error {
+	var err error
+
+	// Implements Coffman deadlock prevention algorithm using resource ordering
+	// This is a critical pattern for concurrent systems to prevent deadlock
+	if fromAccountID < toAccountID {
+		// Process in ID order when from < to
+		result.FromAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     fromAccountID,
+			Amount: -amount,
 		})
 		if err != nil {
 			return err
 		}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
+		result.ToAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     toAccountID,
+			Amount: amount,
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		// Process in reverse ID order when to < from
+		// This ensures a global ordering of locks regardless of transfer direction
+		result.ToAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     toAccountID,
+			Amount: amount,
 		})
 		if err != nil {
 			return err
 		}
 
-		// Implements Coffman deadlock prevention algorithm using resource ordering
-		// This is a critical pattern for concurrent systems to prevent deadlock
-		if arg.FromAccountID < arg.ToAccountID {
-			// Process in ID order when from < to
-			result.FromAccount, err = q.UpdateAccountBalance(ctx, UpdateAccountBalanceParams{
-				ID:      arg.FromAccountID,
-				Balance: -arg.Amount,
-			})
-			if err != nil {
-				return err
-			}
-
-			result.ToAccount, err = q.UpdateAccountBalance(ctx, UpdateAccountBalanceParams{
-				ID:      arg.ToAccountID,
-				Balance: arg.Amount,
-			})
-			if err != nil {
-				return err
-			}
-		} else {
-			// Process in reverse ID order when to < from
-			// This ensures a global ordering of locks regardless of transfer direction
-			result.ToAccount, err = q.UpdateAccountBalance(ctx, UpdateAccountBalanceParams{
-				ID:      arg.ToAccountID,
-				Balance: arg.Amount,
-			})
-			if err != nil {
-				return err
-			}
-
-			result.FromAccount, err = q.UpdateAccountBalance(ctx, UpdateAccountBalanceParams{
-				ID:      arg.FromAccountID,
-				Balance: -arg.Amount,
-			})
-			if err != nil {
-				return err
-			}
+		result.FromAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     fromAccountID,
+			Amount: -amount,
+		})
+		if err != nil {
+			return err
 		}
+	}
 
-		return nil // Explicit nil return required even when error is obvious
+	// Note that we use negative value for outgoing money - avoids separate operation types
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:    fromAccountID,
+		Amount:       -amount, // Unary negation operator for opposing operations
+		TransferID:   sql.NullInt64{Int64: transferID, Valid: true},
+		BalanceAfter: result.FromAccount.Balance,
 	})
+	if err != nil {
+		return err
+	}
 
-	return result, err // Return both result and error to let caller handle errors
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:    toAccountID,
+		Amount:       amount,
+		TransferID:   sql.NullInt64{Int64: transferID, Valid: true},
+		BalanceAfter: result.ToAccount.Balance,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := checkAccountsActive(result.FromAccount, result.ToAccount); err != nil {
+		return err
+	}
+	if err := checkOverdraft(result.FromAccount); err != nil {
+		return err
+	}
+	return nil
 }
 
 // TransferTxFX performs a cross-currency transfer by debiting FromAmount from the
 // source account and crediting ToAmount to the destination account.
-// Transfer.Amount is stored as FromAmount (in the source account currency).
+// Transfer.Amount is stored as FromAmount (in the source account currency),
+// and the transfer records both amounts, the rate and the fee. The fee is
+// credited to the fees account of the source currency.
 func (store *SQLStore) TransferTxFX(ctx context.Context, arg TransferTxFXParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
 	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
+		err := checkPeriodOpen(ctx, q)
+		if err != nil {
+			return err
+		}
 
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.FromAmount,
-		})
+		err = claimIdempotency(ctx, q, arg.Idempotency)
 		if err != nil {
 			return err
 		}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.FromAmount,
-		})
+		err = checkTransferLimit(ctx, q, arg.FromAccountID, arg.FromAmount)
 		if err != nil {
 			return err
 		}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.ToAmount,
+		result.Transfer, err = q.CreateFXTransfer(ctx, CreateFXTransferParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   arg.ToAccountID,
+			FromAmount:    arg.FromAmount,
+			Memo:          newMemo(arg.Memo),
+			Status:        TransferCompleted,
+			ToAmount:      arg.ToAmount,
+			Rate:          arg.Rate,
+			FxFee:         arg.Fee,
 		})
 		if err != nil {
 			return err
 		}
 
 		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, err = q.UpdateAccountBalance(ctx, UpdateAccountBalanceParams{
-				ID:      arg.FromAccountID,
-				Balance: -arg.FromAmount,
+			result.FromAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+				ID:     arg.FromAccountID,
+				Amount: -arg.FromAmount,
 			})
 			if err != nil {
 				return err
 			}
 
-			result.ToAccount, err = q.UpdateAccountBalance(ctx, UpdateAccountBalanceParams{
-				ID:      arg.ToAccountID,
-				Balance: arg.ToAmount,
+			result.ToAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+				ID:     arg.ToAccountID,
+				Amount: arg.ToAmount,
 			})
 			if err != nil {
 				return err
 			}
 		} else {
-			result.ToAccount, err = q.UpdateAccountBalance(ctx, UpdateAccountBalanceParams{
-				ID:      arg.ToAccountID,
-				Balance: arg.ToAmount,
+			result.ToAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+				ID:     arg.ToAccountID,
+				Amount: arg.ToAmount,
 			})
 			if err != nil {
 				return err
 			}
 
-			result.FromAccount, err = q.UpdateAccountBalance(ctx, UpdateAccountBalanceParams{
-				ID:      arg.FromAccountID,
-				Balance: -arg.FromAmount,
+			result.FromAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+				ID:     arg.FromAccountID,
+				Amount: -arg.FromAmount,
 			})
 			if err != nil {
 				return err
 			}
 		}
 
-		return nil
+		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:    arg.FromAccountID,
+			Amount:       -arg.FromAmount,
+			TransferID:   sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+			BalanceAfter: result.FromAccount.Balance,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:    arg.ToAccountID,
+			Amount:       arg.ToAmount,
+			TransferID:   sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+			BalanceAfter: result.ToAccount.Balance,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.Fee > 0 {
+			feeEntry, err := creditFee(ctx, q, result.Transfer.ID, result.FromAccount.Currency, arg.Fee)
+			if err != nil {
+				return err
+			}
+			result.FeeEntry = &feeEntry
+		}
+
+		if err := checkAccountsActive(result.FromAccount, result.ToAccount); err != nil {
+			return err
+		}
+		if err := checkOverdraft(result.FromAccount); err != nil {
+			return err
+		}
+
+		err = enqueueWebhookEvent(ctx, q, WebhookEventTransferCreated, result.Transfer, result.FromAccount.Owner, result.ToAccount.Owner)
+		if err != nil {
+			return err
+		}
+
+		return saveIdempotentResponse(ctx, q, arg.Idempotency, result)
 	})
+	if err == nil {
+		store.publishTransfers(ctx, result)
+	}
 
 	return result, err
 }
\ No newline at end of file
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// isLockConflict reports errors caused by contention between transactions
+// rather than by the transfer itself.
+func isLockConflict(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code.Name() {
+	case "deadlock_detected", "serialization_failure", "lock_not_available":
+		return true
+	}
+	return false
+}
+
+// createBenchAccount creates an account that can't run out of money however
+// large b.N gets.
+func createBenchAccount(b *testing.B) Account {
+	account := createRandomAccount(b)
+	account, err := testStore.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account.ID,
+		OverdraftLimit: math.MaxInt32,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return account
+}
+
+// benchmarkTransfers runs b.N transfers spread over the goroutines started by
+// RunParallel. pick chooses the accounts of the i-th transfer of a goroutine.
+// Besides ns/op it reports transfers per second and the share of transfers
+// that failed on a lock conflict.
+func benchmarkTransfers(b *testing.B, pick func(i int) (from, to int64)) {
+	var transfers, conflicts atomic.Int64
+
+	b.ResetTimer()
+	start := time.Now()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			from, to := pick(i)
+			_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: from,
+				ToAccountID:   to,
+				Amount:        1,
+			})
+			transfers.Add(1)
+			if err == nil {
+				continue
+			}
+			if !isLockConflict(err) {
+				b.Fatal(err)
+			}
+			conflicts.Add(1)
+		}
+	})
+	elapsed := time.Since(start)
+	b.StopTimer()
+
+	if n := transfers.Load(); n > 0 {
+		b.ReportMetric(float64(n)/elapsed.Seconds(), "transfers/s")
+		b.ReportMetric(float64(conflicts.Load())/float64(n), "conflicts/op")
+	}
+}
+
+// BenchmarkTransferTxSerial is the uncontended baseline: one goroutine moving
+// money between the same two accounts.
+func BenchmarkTransferTxSerial(b *testing.B) {
+	account1 := createBenchAccount(b)
+	account2 := createBenchAccount(b)
+
+	b.SetParallelism(1)
+	benchmarkTransfers(b, func(i int) (int64, int64) {
+		return account1.ID, account2.ID
+	})
+}
+
+// BenchmarkTransferTxContention has every goroutine transfer back and forth
+// between the same two accounts, so each transfer waits on the row locks of
+// the previous one. Alternating the direction is what used to deadlock.
+func BenchmarkTransferTxContention(b *testing.B) {
+	account1 := createBenchAccount(b)
+	account2 := createBenchAccount(b)
+
+	b.SetParallelism(4)
+	benchmarkTransfers(b, func(i int) (int64, int64) {
+		if i%2 == 0 {
+			return account1.ID, account2.ID
+		}
+		return account2.ID, account1.ID
+	})
+}
+
+// BenchmarkTransferTxHotAccount models a merchant or house account: many
+// source accounts paying into one destination.
+func BenchmarkTransferTxHotAccount(b *testing.B) {
+	hot := createBenchAccount(b)
+	sources := make([]Account, 8)
+	for i := range sources {
+		sources[i] = createBenchAccount(b)
+	}
+
+	var next atomic.Int64
+	b.SetParallelism(4)
+	benchmarkTransfers(b, func(i int) (int64, int64) {
+		source := sources[next.Add(1)%int64(len(sources))]
+		return source.ID, hot.ID
+	})
+}
@@ -1,10 +1,16 @@
 package db
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"testing"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -85,6 +91,10 @@ func TestTransferTx(t *testing.T) {
 		require.NotEmpty(t, toAccount)
 		require.Equal(t, account2.ID, toAccount.ID)
 
+		// each entry records the balance its transfer left
+		require.Equal(t, fromAccount.Balance, fromEntry.BalanceAfter)
+		require.Equal(t, toAccount.Balance, toEntry.BalanceAfter)
+
 		// check balances
 		fmt.Println(">> tx:", fromAccount.Balance, toAccount.Balance)
 
@@ -157,4 +167,92 @@ func TestTransferTxDeadlock(t *testing.T) {
 	fmt.Println(">> after:", updatedAccount1.Balance, updatedAccount2.Balance)
 	require.Equal(t, account1.Balance, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
-}
\ No newline at end of file
+}
+
+func TestExecTxRetriesConflicts(t *testing.T) {
+	store := testStore.(*SQLStore)
+	deadlock := &pq.Error{Code: "40P01", Message: "deadlock detected"}
+
+	calls := 0
+	err := store.execTx(context.Background(), func(q *Queries) error {
+		calls++
+		if calls == 1 {
+			return deadlock
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	// A transaction that keeps conflicting gives up after maxTxAttempts.
+	calls = 0
+	err = store.execTx(context.Background(), func(q *Queries) error {
+		calls++
+		return &pq.Error{Code: "40001", Message: "could not serialize access"}
+	})
+	require.True(t, isTxConflict(err))
+	require.Equal(t, maxTxAttempts, calls)
+
+	// Other errors aren't retried.
+	calls = 0
+	err = store.execTx(context.Background(), func(q *Queries) error {
+		calls++
+		return errors.New("boom")
+	})
+	require.EqualError(t, err, "boom")
+	require.Equal(t, 1, calls)
+}
+
+func TestExecTxLogsRetries(t *testing.T) {
+	var output bytes.Buffer
+	store := NewStore(testDB, WithLogger(slog.New(slog.NewJSONHandler(&output, nil)))).(*SQLStore)
+
+	calls := 0
+	err := store.execTx(context.Background(), func(q *Queries) error {
+		calls++
+		if calls == 1 {
+			return &pq.Error{Code: "40P01", Message: "deadlock detected"}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(output.Bytes(), &line))
+	require.Equal(t, "retrying transaction after a conflict", line["msg"])
+	require.Equal(t, float64(1), line["attempt"])
+	require.Contains(t, line["error"], "deadlock detected")
+}
+
+func TestExecTxIsolation(t *testing.T) {
+	isolation := func(store *SQLStore, opts *sql.TxOptions) string {
+		var level string
+		err := store.execTxWith(context.Background(), opts, func(q *Queries) error {
+			return q.db.QueryRowContext(context.Background(), "SHOW transaction_isolation").Scan(&level)
+		})
+		require.NoError(t, err)
+		return level
+	}
+
+	store := NewStore(testDB, WithTxIsolation(sql.LevelSerializable)).(*SQLStore)
+	require.Equal(t, "serializable", isolation(store, store.txOptions))
+	// A transaction can ask for a level of its own.
+	require.Equal(t, "repeatable read", isolation(store, &sql.TxOptions{Isolation: sql.LevelRepeatableRead}))
+	require.Equal(t, "read committed", isolation(testStore.(*SQLStore), nil))
+}
+
+func TestParseIsolationLevel(t *testing.T) {
+	for name, want := range map[string]sql.IsolationLevel{
+		"":                sql.LevelDefault,
+		"read committed":  sql.LevelReadCommitted,
+		"Repeatable Read": sql.LevelRepeatableRead,
+		"serializable":    sql.LevelSerializable,
+	} {
+		level, err := ParseIsolationLevel(name)
+		require.NoError(t, err)
+		require.Equal(t, want, level)
+	}
+
+	_, err := ParseIsolationLevel("read uncommitted")
+	require.Error(t, err)
+}
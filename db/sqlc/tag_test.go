@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTags(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	transferID := result.Transfer.ID
+
+	// Tagging twice is a no-op, and each side has its own tags.
+	for i := 0; i < 2; i++ {
+		_, err = testStore.AddTransferTag(context.Background(), AddTransferTagParams{
+			Owner:      account1.Owner,
+			Tag:        "rent",
+			TransferID: transferID,
+		})
+		require.NoError(t, err)
+	}
+	_, err = testStore.AddTransferTag(context.Background(), AddTransferTagParams{
+		Owner:      account2.Owner,
+		Tag:        "salary",
+		TransferID: transferID,
+	})
+	require.NoError(t, err)
+
+	tags, err := testStore.ListTransferTags(context.Background(), ListTransferTagsParams{
+		Owner:      account1.Owner,
+		TransferID: transferID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"rent"}, tags)
+
+	transfers, err := testStore.ListOwnerTransfers(context.Background(), ListOwnerTransfersParams{
+		Owner:     account2.Owner,
+		Tag:       "rent",
+		PageLimit: 5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, transfers)
+
+	transfers, err = testStore.ListOwnerTransfers(context.Background(), ListOwnerTransfersParams{
+		Owner:     account2.Owner,
+		Tag:       "salary",
+		PageLimit: 5,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, transferID, transfers[0].ID)
+
+	spending, err := testStore.ListTagSpending(context.Background(), account1.Owner)
+	require.NoError(t, err)
+	require.Equal(t, []ListTagSpendingRow{{Tag: "rent", Currency: account1.Currency, Count: 1, Total: 10}}, spending)
+
+	// Money received isn't spending.
+	spending, err = testStore.ListTagSpending(context.Background(), account2.Owner)
+	require.NoError(t, err)
+	require.Empty(t, spending)
+
+	deleted, err := testStore.DeleteTransferTag(context.Background(), DeleteTransferTagParams{
+		Owner:      account1.Owner,
+		TransferID: transferID,
+		Tag:        "rent",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+}
+
+func TestEntryTags(t *testing.T) {
+	account := createRandomAccount(t)
+	entry := createRandomEntry(t, account)
+
+	_, err := testStore.AddEntryTag(context.Background(), AddEntryTagParams{
+		Owner:   account.Owner,
+		Tag:     "food",
+		EntryID: entry.ID,
+	})
+	require.NoError(t, err)
+
+	entries, err := testStore.ListEntriesAfter(context.Background(), ListEntriesAfterParams{
+		AccountID: account.ID,
+		Tag:       "food",
+		Owner:     account.Owner,
+		PageLimit: 5,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, entry.ID, entries[0].ID)
+}
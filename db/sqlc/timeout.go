@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is returned, wrapped around the database error, by calls that
+// ran out of the time Timeouts gives them.
+var ErrTimeout = errors.New("database did not answer in time")
+
+// Timeouts bounds how long store calls may take, so a statement stuck behind
+// a lock gives its connection back instead of holding it indefinitely. Zero
+// leaves calls of that kind unbounded.
+type Timeouts struct {
+	// Query bounds a single query, e.g. GetAccount.
+	Query time.Duration
+	// Tx bounds the methods Store adds to the queries, e.g. TransferTx,
+	// which run several statements, mostly in a transaction. It covers every
+	// attempt execTx makes.
+	Tx time.Duration
+}
+
+// timeoutStore bounds every call made through it. Its methods are
+// generated, see timeout_store.go.
+type timeoutStore struct {
+	Store
+	timeouts Timeouts
+}
+
+type noTimeoutsKey struct{}
+
+// WithoutTimeouts returns a copy of ctx whose store calls Timeouts don't
+// bound, for jobs that read whole tables. They should set a deadline of
+// their own.
+func WithoutTimeouts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noTimeoutsKey{}, true)
+}
+
+// NewTimeoutStore wraps store so that every call made through it gets a
+// deadline from timeouts. A deadline ctx already has that is sooner is kept.
+func NewTimeoutStore(store Store, timeouts Timeouts) Store {
+	return &timeoutStore{Store: store, timeouts: timeouts}
+}
+
+// withTimeout derives the context of a call to a method declared by the
+// interface named declaredBy: queries are bounded by Query and everything
+// else by Tx.
+func (store *timeoutStore) withTimeout(ctx context.Context, declaredBy string) (context.Context, context.CancelFunc) {
+	timeout := store.timeouts.Tx
+	if declaredBy == "Querier" {
+		timeout = store.timeouts.Query
+	}
+	if timeout <= 0 || ctx.Value(noTimeoutsKey{}) != nil {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timedOut marks the error of a call that ran out of time. The driver
+// reports that in its own words, e.g. as a cancelled statement.
+func timedOut(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, ErrTimeout) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrTimeout, err)
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lockAccount holds the row lock of an account until the test ends.
+func lockAccount(t *testing.T, accountID int64) {
+	tx, err := testDB.Begin()
+	require.NoError(t, err)
+	t.Cleanup(func() { tx.Rollback() })
+
+	_, err = tx.Exec("SELECT id FROM accounts WHERE id = $1 FOR UPDATE", accountID)
+	require.NoError(t, err)
+}
+
+func TestTimeoutStoreQuery(t *testing.T) {
+	account := createRandomAccount(t)
+	lockAccount(t, account.ID)
+
+	store := NewTimeoutStore(testStore, Timeouts{Query: 100 * time.Millisecond, Tx: time.Minute})
+
+	start := time.Now()
+	_, err := store.AddAccountBalance(context.Background(), AddAccountBalanceParams{ID: account.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrTimeout)
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	// Queries that don't wait on the lock are unaffected.
+	_, err = store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+}
+
+func TestTimeoutStoreTx(t *testing.T) {
+	account := createRandomAccount(t)
+	lockAccount(t, account.ID)
+
+	store := NewTimeoutStore(testStore, Timeouts{Query: time.Minute, Tx: 100 * time.Millisecond})
+
+	_, err := store.DepositTx(context.Background(), DepositTxParams{AccountID: account.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrTimeout)
+
+	got, err := testStore.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, got.Balance)
+}
+
+func TestWithoutTimeouts(t *testing.T) {
+	store := NewTimeoutStore(testStore, Timeouts{Query: time.Nanosecond, Tx: time.Nanosecond})
+
+	_, err := store.ListAccountBalanceMismatches(WithoutTimeouts(context.Background()), ListAccountBalanceMismatchesParams{PageLimit: 1})
+	require.NoError(t, err)
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+)
+
+// Tracer starts spans. It has the shape of an OpenTelemetry tracer, so one
+// takes a small adapter; the tracing package has one that logs slow traces.
+type Tracer interface {
+	// Start starts a span as a child of the span in ctx, if any, and returns
+	// a context holding the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is one timed operation of a trace.
+type Span interface {
+	SetAttribute(key string, value any)
+	// End finishes the span. A non-nil err marks it failed.
+	End(err error)
+}
+
+// Outcomes of a transaction, recorded on its span as tx.outcome.
+const (
+	TxCommitted  = "committed"
+	TxRolledBack = "rolled_back"
+	// TxConflict is a transaction rolled back on a serialization failure or
+	// deadlock, which execTx runs again.
+	TxConflict = "conflict"
+)
+
+// WithTracer traces the transactions of the store: every attempt gets a span
+// recording its outcome, with a span for each statement it ran. Wrap the
+// store in NewTracedStore with the same tracer for the calls themselves.
+func WithTracer(tracer Tracer) StoreOption {
+	return func(store *SQLStore) {
+		store.tracer = tracer
+	}
+}
+
+// tracedStore starts a span for every call made through it. Its methods are
+// generated, see traced_store.go.
+type tracedStore struct {
+	Store
+	tracer Tracer
+}
+
+// NewTracedStore wraps store so that every method called on it is a span,
+// named after the method. Given a store made WithTracer, the transactions and
+// statements a call runs are its children.
+func NewTracedStore(store Store, tracer Tracer) Store {
+	return &tracedStore{Store: store, tracer: tracer}
+}
+
+// tracedDB starts a span for every statement run on db, as a child of the
+// span in span. The queries of a transaction are called with the context of
+// the Store method that runs it, so without it statements would end up
+// beside the transaction rather than inside it.
+type tracedDB struct {
+	db     DBTX
+	tracer Tracer
+	span   context.Context
+}
+
+func (traced *tracedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := traced.start(ctx, query)
+	result, err := traced.db.ExecContext(ctx, query, args...)
+	span.End(err)
+	return result, err
+}
+
+func (traced *tracedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, span := traced.start(ctx, query)
+	stmt, err := traced.db.PrepareContext(ctx, query)
+	span.End(err)
+	return stmt, err
+}
+
+// The span of a query covers running it, not reading its rows.
+func (traced *tracedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := traced.start(ctx, query)
+	rows, err := traced.db.QueryContext(ctx, query, args...)
+	span.End(err)
+	return rows, err
+}
+
+func (traced *tracedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := traced.start(ctx, query)
+	row := traced.db.QueryRowContext(ctx, query, args...)
+	// No rows is an answer, not a failure of the statement.
+	err := row.Err()
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	span.End(err)
+	return row
+}
+
+// start returns ctx itself: only the span comes from the transaction, the
+// statement still runs with the deadline and cancellation of the caller.
+func (traced *tracedDB) start(ctx context.Context, query string) (context.Context, Span) {
+	_, span := traced.tracer.Start(traced.span, statementName(query))
+	span.SetAttribute("db.system", "postgresql")
+	return ctx, span
+}
+
+var queryName = regexp.MustCompile(`^-- name: (\w+)`)
+
+// statementName names a statement after its sqlc query, or after the command
+// it starts with for hand-written ones, e.g. SELECT.
+func statementName(query string) string {
+	if match := queryName.FindStringSubmatch(query); match != nil {
+		return match[1]
+	}
+	command, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	return strings.ToUpper(command)
+}
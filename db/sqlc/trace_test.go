@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTracer keeps every span it starts.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	name       string
+	parent     *recordedSpan
+	attributes map[string]any
+	ended      bool
+	err        error
+}
+
+type recordedSpanKey struct{}
+
+func (tracer *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(recordedSpanKey{}).(*recordedSpan)
+	span := &recordedSpan{name: name, parent: parent, attributes: make(map[string]any)}
+
+	tracer.mu.Lock()
+	tracer.spans = append(tracer.spans, span)
+	tracer.mu.Unlock()
+	return context.WithValue(ctx, recordedSpanKey{}, span), span
+}
+
+func (span *recordedSpan) SetAttribute(key string, value any) {
+	span.attributes[key] = value
+}
+
+func (span *recordedSpan) End(err error) {
+	span.ended = true
+	span.err = err
+}
+
+func (tracer *recordingTracer) children(parent *recordedSpan) []string {
+	var names []string
+	for _, span := range tracer.spans {
+		if span.parent == parent {
+			names = append(names, span.name)
+		}
+	}
+	return names
+}
+
+func TestTracedTransferTx(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	tracer := &recordingTracer{}
+	store := NewTracedStore(NewStore(testDB, WithTracer(tracer)), tracer)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	root := tracer.spans[0]
+	require.Equal(t, "TransferTx", root.name)
+	require.Nil(t, root.parent)
+	require.Equal(t, []string{"tx"}, tracer.children(root))
+
+	tx := tracer.spans[1]
+	require.Equal(t, 1, tx.attributes["tx.attempt"])
+	require.Equal(t, TxCommitted, tx.attributes["tx.outcome"])
+
+	statements := tracer.children(tx)
+	require.Contains(t, statements, "CreateTransfer")
+	require.Contains(t, statements, "CreateEntry")
+	require.Contains(t, statements, "AddAccountBalance")
+
+	for _, span := range tracer.spans {
+		require.True(t, span.ended, span.name)
+		require.NoError(t, span.err, span.name)
+	}
+}
+
+func TestTracedTxRollback(t *testing.T) {
+	tracer := &recordingTracer{}
+	store := NewStore(testDB, WithTracer(tracer)).(*SQLStore)
+
+	err := store.execTx(context.Background(), func(q *Queries) error {
+		_, err := q.GetAccount(context.Background(), -1)
+		return err
+	})
+	require.Error(t, err)
+
+	tx := tracer.spans[0]
+	require.Equal(t, "tx", tx.name)
+	require.Equal(t, TxRolledBack, tx.attributes["tx.outcome"])
+	require.Error(t, tx.err)
+	// No rows is not a failed statement.
+	require.Equal(t, []string{"GetAccount"}, tracer.children(tx))
+	require.NoError(t, tracer.spans[1].err)
+}
+
+func TestStatementName(t *testing.T) {
+	require.Equal(t, "GetAccount", statementName(getAccount))
+	require.Equal(t, "SELECT", statementName(" select version, dirty FROM schema_migrations"))
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Directions ListOwnerTransfers can filter on, from the owner's point of view.
+const (
+	TransferDirectionSent     = "sent"
+	TransferDirectionReceived = "received"
+)
+
+// transferJSON is how a Transfer looks in API responses and stored
+// idempotent responses: the memo is a string, or null without one, and so
+// are the conversion fields of transfers that didn't record one.
+type transferJSON struct {
+	ID            int64     `json:"id"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	Memo          *string   `json:"memo"`
+	Status        string    `json:"status"`
+	FromAmount    *int64    `json:"from_amount"`
+	ToAmount      *int64    `json:"to_amount"`
+	Rate          *float64  `json:"rate"`
+	FxFee         *int64    `json:"fx_fee"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (transfer Transfer) MarshalJSON() ([]byte, error) {
+	data := transferJSON{
+		ID:            transfer.ID,
+		FromAccountID: transfer.FromAccountID,
+		ToAccountID:   transfer.ToAccountID,
+		Amount:        transfer.Amount,
+		Status:        transfer.Status,
+		CreatedAt:     transfer.CreatedAt,
+	}
+	if transfer.Memo.Valid {
+		data.Memo = &transfer.Memo.String
+	}
+	if transfer.FromAmount.Valid {
+		data.FromAmount = &transfer.FromAmount.Int64
+	}
+	if transfer.ToAmount.Valid {
+		data.ToAmount = &transfer.ToAmount.Int64
+	}
+	if transfer.Rate.Valid {
+		data.Rate = &transfer.Rate.Float64
+	}
+	if transfer.FxFee.Valid {
+		data.FxFee = &transfer.FxFee.Int64
+	}
+	return json.Marshal(data)
+}
+
+func (transfer *Transfer) UnmarshalJSON(b []byte) error {
+	var data transferJSON
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	*transfer = Transfer{
+		ID:            data.ID,
+		FromAccountID: data.FromAccountID,
+		ToAccountID:   data.ToAccountID,
+		Amount:        data.Amount,
+		Status:        data.Status,
+		CreatedAt:     data.CreatedAt,
+	}
+	if data.Memo != nil {
+		transfer.Memo = sql.NullString{String: *data.Memo, Valid: true}
+	}
+	if data.FromAmount != nil {
+		transfer.FromAmount = sql.NullInt64{Int64: *data.FromAmount, Valid: true}
+	}
+	if data.ToAmount != nil {
+		transfer.ToAmount = sql.NullInt64{Int64: *data.ToAmount, Valid: true}
+	}
+	if data.Rate != nil {
+		transfer.Rate = sql.NullFloat64{Float64: *data.Rate, Valid: true}
+	}
+	if data.FxFee != nil {
+		transfer.FxFee = sql.NullInt64{Int64: *data.FxFee, Valid: true}
+	}
+	// Responses stored before transfers had a status were all completed.
+	if transfer.Status == "" {
+		transfer.Status = TransferCompleted
+	}
+	return nil
+}
+
+// newMemo stores an empty memo as NULL.
+func newMemo(memo string) sql.NullString {
+	return sql.NullString{String: memo, Valid: memo != ""}
+}
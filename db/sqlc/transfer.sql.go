@@ -7,26 +7,99 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+const createFXTransfer = `-- name: CreateFXTransfer :one
+INSERT INTO transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  memo,
+  status,
+  from_amount,
+  to_amount,
+  rate,
+  fx_fee
+) VALUES (
+  $1, $2, $3::bigint, $4, $5,
+  $3::bigint, $6::bigint, $7::float8, $8::bigint
+) RETURNING id, from_account_id, to_account_id, amount, created_at, memo, status, from_amount, to_amount, rate, fx_fee, entries_linked
+`
+
+type CreateFXTransferParams struct {
+	FromAccountID int64          `json:"from_account_id"`
+	ToAccountID   int64          `json:"to_account_id"`
+	FromAmount    int64          `json:"from_amount"`
+	Memo          sql.NullString `json:"memo"`
+	Status        string         `json:"status"`
+	ToAmount      int64          `json:"to_amount"`
+	Rate          float64        `json:"rate"`
+	FxFee         int64          `json:"fx_fee"`
+}
+
+// A cross-currency transfer, recording the conversion it was made at. Its
+// amount is the from_amount
+func (q *Queries) CreateFXTransfer(ctx context.Context, arg CreateFXTransferParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, createFXTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.FromAmount,
+		arg.Memo,
+		arg.Status,
+		arg.ToAmount,
+		arg.Rate,
+		arg.FxFee,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Memo,
+		&i.Status,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Rate,
+		&i.FxFee,
+		&i.EntriesLinked,
+	)
+	return i, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  memo,
+  status
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+  $1, $2, $3, $4, $5
+) RETURNING id, from_account_id, to_account_id, amount, created_at, memo, status, from_amount, to_amount, rate, fx_fee, entries_linked
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64          `json:"from_account_id"`
+	ToAccountID   int64          `json:"to_account_id"`
+	Amount        int64          `json:"amount"`
+	Memo          sql.NullString `json:"memo"`
+	Status        string         `json:"status"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Memo,
+		arg.Status,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -34,12 +107,56 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.Status,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Rate,
+		&i.FxFee,
+		&i.EntriesLinked,
+	)
+	return i, err
+}
+
+const createTransferStatusChange = `-- name: CreateTransferStatusChange :one
+INSERT INTO transfer_status_changes (
+  transfer_id,
+  from_status,
+  to_status,
+  reason
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, transfer_id, from_status, to_status, reason, created_at
+`
+
+type CreateTransferStatusChangeParams struct {
+	TransferID int64  `json:"transfer_id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason"`
+}
+
+func (q *Queries) CreateTransferStatusChange(ctx context.Context, arg CreateTransferStatusChangeParams) (TransferStatusChange, error) {
+	row := q.db.QueryRowContext(ctx, createTransferStatusChange,
+		arg.TransferID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.Reason,
+	)
+	var i TransferStatusChange
+	err := row.Scan(
+		&i.ID,
+		&i.TransferID,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.Reason,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, status, from_amount, to_amount, rate, fx_fee, entries_linked FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -52,12 +169,210 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.Status,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Rate,
+		&i.FxFee,
+		&i.EntriesLinked,
 	)
 	return i, err
 }
 
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, status, from_amount, to_amount, rate, fx_fee, entries_linked FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Memo,
+		&i.Status,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Rate,
+		&i.FxFee,
+		&i.EntriesLinked,
+	)
+	return i, err
+}
+
+const listOwnerTransfers = `-- name: ListOwnerTransfers :many
+SELECT
+  t.id,
+  t.from_account_id,
+  t.to_account_id,
+  t.amount,
+  fa.currency AS from_currency,
+  ta.currency AS to_currency,
+  COALESCE(t.memo, '')::varchar AS memo,
+  t.status,
+  COALESCE(t.to_amount, t.amount)::bigint AS to_amount,
+  COALESCE(t.rate, 0)::float8 AS rate,
+  COALESCE(t.fx_fee, 0)::bigint AS fx_fee,
+  t.created_at
+FROM transfers t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+WHERE (fa.owner = $1 OR ta.owner = $1)
+  AND ($2::bigint = 0 OR t.id < $2::bigint)
+  AND ($3::varchar = '' OR t.memo ILIKE $3::varchar)
+  AND ($4::varchar = '' OR EXISTS (
+    SELECT 1 FROM tags g
+    WHERE g.transfer_id = t.id
+      AND g.owner = $1
+      AND g.tag = $4::varchar
+  ))
+  AND ($5::timestamptz IS NULL OR t.created_at >= $5::timestamptz)
+  AND ($6::timestamptz IS NULL OR t.created_at < $6::timestamptz)
+  AND ($7::bigint = 0 OR t.amount >= $7::bigint)
+  AND ($8::bigint = 0 OR t.amount <= $8::bigint)
+  AND ($9::varchar = ''
+    OR ($9::varchar = 'sent' AND fa.owner = $1)
+    OR ($9::varchar = 'received' AND ta.owner = $1))
+  AND ($10::bigint = 0
+    OR t.from_account_id = $10::bigint
+    OR t.to_account_id = $10::bigint)
+  AND ($11::varchar = '' OR fa.currency = $11::varchar OR ta.currency = $11::varchar)
+ORDER BY t.id DESC
+LIMIT $12::int
+`
+
+type ListOwnerTransfersParams struct {
+	Owner                 string       `json:"owner"`
+	BeforeID              int64        `json:"before_id"`
+	Search                string       `json:"search"`
+	Tag                   string       `json:"tag"`
+	FromTime              sql.NullTime `json:"from_time"`
+	ToTime                sql.NullTime `json:"to_time"`
+	MinAmount             int64        `json:"min_amount"`
+	MaxAmount             int64        `json:"max_amount"`
+	Direction             string       `json:"direction"`
+	CounterpartyAccountID int64        `json:"counterparty_account_id"`
+	Currency              string       `json:"currency"`
+	PageLimit             int32        `json:"page_limit"`
+}
+
+type ListOwnerTransfersRow struct {
+	ID            int64     `json:"id"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	FromCurrency  string    `json:"from_currency"`
+	ToCurrency    string    `json:"to_currency"`
+	Memo          string    `json:"memo"`
+	Status        string    `json:"status"`
+	ToAmount      int64     `json:"to_amount"`
+	Rate          float64   `json:"rate"`
+	FxFee         int64     `json:"fx_fee"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Newest first. A before_id of 0 starts from the latest transfer. A non-empty
+// search keeps transfers whose memo matches it as an ILIKE pattern, a
+// non-empty tag those the owner tagged with it. The other filters are off at
+// their zero value: created_at in [from_time, to_time), the amount between
+// min_amount and max_amount, direction sent or received from the owner's
+// point of view, a counterparty account on either side and a currency on
+// either side. to_amount is what the recipient got, in its currency. rate
+// and fx_fee are 0 unless the conversion of a cross-currency transfer was
+// recorded, which it wasn't before they were added
+func (q *Queries) ListOwnerTransfers(ctx context.Context, arg ListOwnerTransfersParams) ([]ListOwnerTransfersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOwnerTransfers,
+		arg.Owner,
+		arg.BeforeID,
+		arg.Search,
+		arg.Tag,
+		arg.FromTime,
+		arg.ToTime,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.Direction,
+		arg.CounterpartyAccountID,
+		arg.Currency,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOwnerTransfersRow{}
+	for rows.Next() {
+		var i ListOwnerTransfersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.FromCurrency,
+			&i.ToCurrency,
+			&i.Memo,
+			&i.Status,
+			&i.ToAmount,
+			&i.Rate,
+			&i.FxFee,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransferStatusChanges = `-- name: ListTransferStatusChanges :many
+SELECT id, transfer_id, from_status, to_status, reason, created_at FROM transfer_status_changes
+WHERE transfer_id = ANY($1::bigint[])
+ORDER BY transfer_id, id
+`
+
+func (q *Queries) ListTransferStatusChanges(ctx context.Context, transferIds []int64) ([]TransferStatusChange, error) {
+	rows, err := q.db.QueryContext(ctx, listTransferStatusChanges, pq.Array(transferIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TransferStatusChange{}
+	for rows.Next() {
+		var i TransferStatusChange
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransferID,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, status, from_amount, to_amount, rate, fx_fee, entries_linked FROM transfers
 WHERE 
     from_account_id = $1 OR
     to_account_id = $2
@@ -93,6 +408,73 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Memo,
+			&i.Status,
+			&i.FromAmount,
+			&i.ToAmount,
+			&i.Rate,
+			&i.FxFee,
+			&i.EntriesLinked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransfersByAccountBefore = `-- name: ListTransfersByAccountBefore :many
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, status, from_amount, to_amount, rate, fx_fee, entries_linked FROM transfers
+WHERE (from_account_id = $1 OR to_account_id = $1)
+  AND ($2::timestamptz IS NULL
+    OR (created_at, id) < ($2::timestamptz, $3::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT $4
+`
+
+type ListTransfersByAccountBeforeParams struct {
+	AccountID       int64        `json:"account_id"`
+	BeforeCreatedAt sql.NullTime `json:"before_created_at"`
+	BeforeID        int64        `json:"before_id"`
+	PageLimit       int32        `json:"page_limit"`
+}
+
+// Newest first, by (created_at, id) so the order is stable for transfers
+// made in the same instant. The first page has a NULL before_created_at, the
+// next ones the created_at and id of the last transfer of the page before
+func (q *Queries) ListTransfersByAccountBefore(ctx context.Context, arg ListTransfersByAccountBeforeParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listTransfersByAccountBefore,
+		arg.AccountID,
+		arg.BeforeCreatedAt,
+		arg.BeforeID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Memo,
+			&i.Status,
+			&i.FromAmount,
+			&i.ToAmount,
+			&i.Rate,
+			&i.FxFee,
+			&i.EntriesLinked,
 		); err != nil {
 			return nil, err
 		}
@@ -106,3 +488,108 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	}
 	return items, nil
 }
+
+const searchTransfers = `-- name: SearchTransfers :many
+SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.memo, t.status, t.from_amount, t.to_amount, t.rate, t.fx_fee, t.entries_linked FROM transfers t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+WHERE ($1::bigint = 0
+    OR t.from_account_id = $1::bigint
+    OR t.to_account_id = $1::bigint)
+  AND ($2::varchar = '' OR fa.owner = $2::varchar OR ta.owner = $2::varchar)
+  AND ($3::timestamptz IS NULL OR t.created_at >= $3::timestamptz)
+  AND ($4::timestamptz IS NULL OR t.created_at < $4::timestamptz)
+  AND ($5::bigint = 0 OR t.amount >= $5::bigint)
+ORDER BY t.id DESC
+LIMIT $6::int
+OFFSET $7::int
+`
+
+type SearchTransfersParams struct {
+	AccountID  int64        `json:"account_id"`
+	Owner      string       `json:"owner"`
+	FromTime   sql.NullTime `json:"from_time"`
+	ToTime     sql.NullTime `json:"to_time"`
+	MinAmount  int64        `json:"min_amount"`
+	PageLimit  int32        `json:"page_limit"`
+	PageOffset int32        `json:"page_offset"`
+}
+
+// Admin lookup across all owners, newest first. Each filter is off at its
+// zero value: an account on either side, an owner on either side, created_at
+// in [from_time, to_time) and the amount from min_amount up
+func (q *Queries) SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, searchTransfers,
+		arg.AccountID,
+		arg.Owner,
+		arg.FromTime,
+		arg.ToTime,
+		arg.MinAmount,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Memo,
+			&i.Status,
+			&i.FromAmount,
+			&i.ToAmount,
+			&i.Rate,
+			&i.FxFee,
+			&i.EntriesLinked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTransferStatus = `-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET status = $1
+WHERE id = $2
+RETURNING id, from_account_id, to_account_id, amount, created_at, memo, status, from_amount, to_amount, rate, fx_fee, entries_linked
+`
+
+type UpdateTransferStatusParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, updateTransferStatus, arg.Status, arg.ID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Memo,
+		&i.Status,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Rate,
+		&i.FxFee,
+		&i.EntriesLinked,
+	)
+	return i, err
+}
@@ -0,0 +1,318 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOwnerTransfers(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	var transfers []Transfer
+	for i := 0; i < 3; i++ {
+		transfer, err := testStore.CreateTransfer(context.Background(), CreateTransferParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        int64(i + 1),
+			Status:        TransferCompleted,
+		})
+		require.NoError(t, err)
+		transfers = append(transfers, transfer)
+	}
+
+	// The receiving owner sees the transfers too, newest first.
+	page, err := testStore.ListOwnerTransfers(context.Background(), ListOwnerTransfersParams{
+		Owner:     account2.Owner,
+		PageLimit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, transfers[2].ID, page[0].ID)
+	require.Equal(t, account1.Currency, page[0].FromCurrency)
+	require.Equal(t, account2.Currency, page[0].ToCurrency)
+
+	page, err = testStore.ListOwnerTransfers(context.Background(), ListOwnerTransfersParams{
+		Owner:     account1.Owner,
+		BeforeID:  page[1].ID,
+		PageLimit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, transfers[0].ID, page[0].ID)
+}
+
+func TestListTransfersByAccountBefore(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	var transfers []Transfer
+	for i := 0; i < 5; i++ {
+		// Both directions count.
+		from, to := account1, account2
+		if i%2 == 1 {
+			from, to = account2, account1
+		}
+		transfer, err := testStore.CreateTransfer(context.Background(), CreateTransferParams{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        int64(i + 1),
+			Status:        TransferCompleted,
+		})
+		require.NoError(t, err)
+		transfers = append(transfers, transfer)
+	}
+
+	var pages [][]Transfer
+	arg := ListTransfersByAccountBeforeParams{AccountID: account1.ID, PageLimit: 2}
+	for {
+		page, err := testStore.ListTransfersByAccountBefore(context.Background(), arg)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		pages = append(pages, page)
+		last := page[len(page)-1]
+		arg.BeforeCreatedAt = sql.NullTime{Time: last.CreatedAt, Valid: true}
+		arg.BeforeID = last.ID
+	}
+
+	require.Len(t, pages, 3)
+	var seen []int64
+	for _, page := range pages {
+		for _, transfer := range page {
+			seen = append(seen, transfer.ID)
+		}
+	}
+	require.Equal(t, []int64{transfers[4].ID, transfers[3].ID, transfers[2].ID, transfers[1].ID, transfers[0].ID}, seen)
+}
+
+func TestListOwnerTransfersFilters(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+
+	create := func(from, to Account, amount int64) Transfer {
+		transfer, err := testStore.CreateTransfer(context.Background(), CreateTransferParams{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        amount,
+			Status:        TransferCompleted,
+		})
+		require.NoError(t, err)
+		return transfer
+	}
+	small := create(account1, account2, 5)
+	large := create(account1, account3, 500)
+	received := create(account2, account1, 50)
+
+	testCases := []struct {
+		name string
+		arg  ListOwnerTransfersParams
+		want []Transfer
+	}{
+		{
+			name: "AmountRange",
+			arg:  ListOwnerTransfersParams{MinAmount: 10, MaxAmount: 100},
+			want: []Transfer{received},
+		},
+		{
+			name: "Sent",
+			arg:  ListOwnerTransfersParams{Direction: TransferDirectionSent},
+			want: []Transfer{large, small},
+		},
+		{
+			name: "Received",
+			arg:  ListOwnerTransfersParams{Direction: TransferDirectionReceived},
+			want: []Transfer{received},
+		},
+		{
+			name: "Counterparty",
+			arg:  ListOwnerTransfersParams{CounterpartyAccountID: account3.ID},
+			want: []Transfer{large},
+		},
+		{
+			name: "Future",
+			arg:  ListOwnerTransfersParams{FromTime: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			arg := tc.arg
+			arg.Owner = account1.Owner
+			arg.PageLimit = 10
+			page, err := testStore.ListOwnerTransfers(context.Background(), arg)
+			require.NoError(t, err)
+
+			var ids []int64
+			for _, transfer := range page {
+				ids = append(ids, transfer.ID)
+			}
+			var want []int64
+			for _, transfer := range tc.want {
+				want = append(want, transfer.ID)
+			}
+			require.Equal(t, want, ids)
+		})
+	}
+}
+
+func TestTransferMemo(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+		Memo:          "Invoice 1042",
+	})
+	require.NoError(t, err)
+	require.Equal(t, sql.NullString{String: "Invoice 1042", Valid: true}, result.Transfer.Memo)
+
+	// No memo is stored as NULL and listed as empty.
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        2,
+	})
+	require.NoError(t, err)
+
+	page, err := testStore.ListOwnerTransfers(context.Background(), ListOwnerTransfersParams{
+		Owner:     account2.Owner,
+		Search:    "%invoice%",
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, result.Transfer.ID, page[0].ID)
+	require.Equal(t, "Invoice 1042", page[0].Memo)
+}
+
+func TestTransferJSON(t *testing.T) {
+	transfer := Transfer{
+		ID:            1,
+		FromAccountID: 2,
+		ToAccountID:   3,
+		Amount:        4,
+		CreatedAt:     time.Now().UTC().Truncate(time.Second),
+		Memo:          sql.NullString{String: "rent", Valid: true},
+		Status:        TransferCompleted,
+		FromAmount:    sql.NullInt64{Int64: 4, Valid: true},
+		ToAmount:      sql.NullInt64{Int64: 330, Valid: true},
+		Rate:          sql.NullFloat64{Float64: 83.5, Valid: true},
+		FxFee:         sql.NullInt64{Int64: 0, Valid: true},
+	}
+
+	data, err := json.Marshal(transfer)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"memo":"rent"`)
+	require.Contains(t, string(data), `"to_amount":330,"rate":83.5,"fx_fee":0`)
+
+	var got Transfer
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Equal(t, transfer, got)
+
+	data, err = json.Marshal(Transfer{})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"memo":null`)
+	require.Contains(t, string(data), `"to_amount":null`)
+}
+
+func TestTransferTxFX(t *testing.T) {
+	// Fees are kept in the sender's currency, which needs a fees account.
+	account1, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Balance:  1000,
+		Currency: "USD",
+	})
+	require.NoError(t, err)
+	account2 := createRandomAccount(t)
+
+	result, err := testStore.TransferTxFX(context.Background(), TransferTxFXParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		FromAmount:    100,
+		ToAmount:      8_250,
+		Rate:          83.33,
+		Fee:           1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), result.Transfer.Amount)
+	require.Equal(t, sql.NullInt64{Int64: 100, Valid: true}, result.Transfer.FromAmount)
+	require.Equal(t, sql.NullInt64{Int64: 8_250, Valid: true}, result.Transfer.ToAmount)
+	require.Equal(t, sql.NullFloat64{Float64: 83.33, Valid: true}, result.Transfer.Rate)
+	require.Equal(t, sql.NullInt64{Int64: 1, Valid: true}, result.Transfer.FxFee)
+	require.Equal(t, int64(8_250), result.ToEntry.Amount)
+
+	require.NotNil(t, result.FeeEntry)
+	require.Equal(t, int64(1), result.FeeEntry.Amount)
+	require.Equal(t, EntryKindFee, result.FeeEntry.Kind)
+	require.Equal(t, sql.NullInt64{Int64: result.Transfer.ID, Valid: true}, result.FeeEntry.TransferID)
+	fees, err := testStore.GetHouseAccount(context.Background(), GetHouseAccountParams{HouseRole: HouseRoleFees, Currency: "USD"})
+	require.NoError(t, err)
+	require.Equal(t, fees.ID, result.FeeEntry.AccountID)
+
+	mismatches, err := testStore.ListTransferEntryMismatches(context.Background(), ListTransferEntryMismatchesParams{
+		AfterID:   result.Transfer.ID - 1,
+		PageLimit: 1,
+	})
+	require.NoError(t, err)
+	if len(mismatches) > 0 {
+		require.NotEqual(t, result.Transfer.ID, mismatches[0].ID)
+	}
+
+	// Plain transfers record no conversion, and history shows them received
+	// as sent.
+	plain, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        7,
+	})
+	require.NoError(t, err)
+	require.False(t, plain.Transfer.ToAmount.Valid)
+
+	page, err := testStore.ListOwnerTransfers(context.Background(), ListOwnerTransfersParams{
+		Owner:     account2.Owner,
+		PageLimit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, int64(7), page[0].ToAmount)
+	require.Zero(t, page[0].Rate)
+	require.Equal(t, int64(8_250), page[1].ToAmount)
+	require.Equal(t, 83.33, page[1].Rate)
+	require.Equal(t, int64(1), page[1].FxFee)
+}
+
+func TestTransferTxFXWithoutFeesAccount(t *testing.T) {
+	account1, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Balance:  1000,
+		Currency: util.CAD, // not supported, so it has no fees account
+	})
+	require.NoError(t, err)
+	account2 := createRandomAccount(t)
+
+	_, err = testStore.TransferTxFX(context.Background(), TransferTxFXParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		FromAmount:    100,
+		ToAmount:      8_250,
+		Rate:          83.33,
+		Fee:           1,
+	})
+	require.ErrorContains(t, err, "no fees account for CAD")
+
+	// Nothing moved.
+	account1, err = testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), account1.Balance)
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrAccountNotEmpty = errors.New("account has a balance, give an account to sweep it to")
+
+// closureMemo is the memo of the transfer that empties a closing account.
+const closureMemo = "account closure"
+
+type CloseAccountTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Optional: where a positive balance is moved. Without it the balance
+	// must already be zero.
+	SweepToAccountID int64  `json:"sweep_to_account_id"`
+	ClosedBy         string `json:"closed_by"`
+}
+
+type CloseAccountTxResult struct {
+	Account Account `json:"account"`
+	// Set when a balance was swept.
+	Sweep *TransferTxResult `json:"sweep,omitempty"`
+}
+
+// CloseAccountTx sweeps the balance of an account and closes it in one
+// transaction, so an account is never closed with money left in it. The
+// account is kept for its history, and the status change is recorded like
+// any other.
+func (store *SQLStore) CloseAccountTx(ctx context.Context, arg CloseAccountTxParams) (CloseAccountTxResult, error) {
+	var result CloseAccountTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// Lock both accounts in ID order, as transfers do, before looking at
+		// the balance.
+		ids := []int64{arg.AccountID}
+		if arg.SweepToAccountID != 0 {
+			if arg.SweepToAccountID < arg.AccountID {
+				ids = []int64{arg.SweepToAccountID, arg.AccountID}
+			} else {
+				ids = append(ids, arg.SweepToAccountID)
+			}
+		}
+		var account Account
+		for _, id := range ids {
+			locked, err := q.GetAccountForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if id == arg.AccountID {
+				account = locked
+			}
+		}
+
+		if err := checkAccountsActive(account); err != nil {
+			return err
+		}
+		if account.Balance < 0 || (account.Balance > 0 && arg.SweepToAccountID == 0) {
+			return ErrAccountNotEmpty
+		}
+		potsTotal, err := q.SumPotBalances(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+		if potsTotal > 0 {
+			return ErrPotsNotEmpty
+		}
+
+		if account.Balance > 0 {
+			sweep, err := transferTx(ctx, q, TransferTxParams{
+				FromAccountID: account.ID,
+				ToAccountID:   arg.SweepToAccountID,
+				Amount:        account.Balance,
+				Memo:          closureMemo,
+			})
+			if err != nil {
+				return err
+			}
+			result.Sweep = &sweep
+		}
+
+		field, err := lookupStandingField(StandingDataAccount, "status")
+		if err != nil {
+			return err
+		}
+		_, err = applyStandingDataChange(ctx, q, field, CreateStandingDataChangeParams{
+			EntityType: StandingDataAccount,
+			EntityID:   AccountEntityID(account.ID),
+			Field:      "status",
+			NewValue:   AccountClosed,
+			ChangedBy:  arg.ClosedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.GetAccount(ctx, account.ID)
+		return err
+	})
+	if err == nil && result.Sweep != nil {
+		store.publishTransfers(ctx, *result.Sweep)
+	}
+
+	return result, err
+}
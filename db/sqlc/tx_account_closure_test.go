@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseAccountTx(t *testing.T) {
+	account := createRandomAccount(t)
+	sweepTo, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Balance:  10,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.CloseAccountTx(context.Background(), CloseAccountTxParams{
+		AccountID: account.ID,
+		ClosedBy:  account.Owner,
+	})
+	require.ErrorIs(t, err, ErrAccountNotEmpty)
+
+	result, err := testStore.CloseAccountTx(context.Background(), CloseAccountTxParams{
+		AccountID:        account.ID,
+		SweepToAccountID: sweepTo.ID,
+		ClosedBy:         account.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountClosed, result.Account.Status)
+	require.Zero(t, result.Account.Balance)
+	require.NotNil(t, result.Sweep)
+	require.Equal(t, account.Balance, result.Sweep.Transfer.Amount)
+	require.Equal(t, sweepTo.Balance+account.Balance, result.Sweep.ToAccount.Balance)
+
+	// Nothing moves on a closed account any more.
+	_, err = testStore.DepositTx(context.Background(), DepositTxParams{AccountID: account.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountClosed)
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{FromAccountID: sweepTo.ID, ToAccountID: account.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountClosed)
+	_, err = testStore.CloseAccountTx(context.Background(), CloseAccountTxParams{AccountID: account.ID, ClosedBy: account.Owner})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	// The owner can open a new account in the same currency.
+	reopened, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+
+	found, err := testStore.GetAccountByOwnerAndCurrency(context.Background(), GetAccountByOwnerAndCurrencyParams{
+		Owner:    account.Owner,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+	require.Equal(t, reopened.ID, found.ID)
+}
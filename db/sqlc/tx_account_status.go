@@ -0,0 +1,33 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Statuses of an account.
+const (
+	AccountActive = "active"
+	AccountFrozen = "frozen"
+	AccountClosed = "closed"
+)
+
+var (
+	ErrAccountFrozen = errors.New("account is frozen")
+	ErrAccountClosed = errors.New("account is closed")
+)
+
+// checkAccountsActive is called with accounts as returned by the balance
+// updates of a transaction. Those hold the row locks, so a freeze or closure
+// that commits first is always seen.
+func checkAccountsActive(accounts ...Account) error {
+	for _, account := range accounts {
+		switch account.Status {
+		case AccountFrozen:
+			return fmt.Errorf("%w: account %d", ErrAccountFrozen, account.ID)
+		case AccountClosed:
+			return fmt.Errorf("%w: account %d", ErrAccountClosed, account.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrozenAccountRejectsMoneyMovement(t *testing.T) {
+	frozen := createRandomAccount(t)
+	other := createRandomAccount(t)
+
+	change, err := testStore.UpdateStandingDataTx(context.Background(), UpdateStandingDataTxParams{
+		EntityType: StandingDataAccount,
+		EntityID:   AccountEntityID(frozen.ID),
+		Field:      "status",
+		NewValue:   AccountFrozen,
+		ChangedBy:  frozen.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountActive, change.OldValue)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{FromAccountID: frozen.ID, ToAccountID: other.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{FromAccountID: other.ID, ToAccountID: frozen.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+	_, err = testStore.DepositTx(context.Background(), DepositTxParams{AccountID: frozen.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+	_, err = testStore.WithdrawTx(context.Background(), WithdrawTxParams{AccountID: frozen.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// Nothing moved.
+	account, err := testStore.GetAccount(context.Background(), frozen.ID)
+	require.NoError(t, err)
+	require.Equal(t, frozen.Balance, account.Balance)
+
+	_, err = testStore.RevertStandingDataChangeTx(context.Background(), RevertStandingDataChangeTxParams{
+		ChangeID:  change.ID,
+		ChangedBy: "admin",
+	})
+	require.NoError(t, err)
+	_, err = testStore.DepositTx(context.Background(), DepositTxParams{AccountID: frozen.ID, Amount: 1})
+	require.NoError(t, err)
+}
@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrPeriodClosed    = errors.New("accounting period is closed for posting")
+	ErrPeriodNotEnded  = errors.New("accounting period has not ended yet")
+	ErrPeriodNotClosed = errors.New("accounting period is still open, post a regular entry instead")
+)
+
+// PeriodStart returns the first day of the month containing t, which is how
+// periods are keyed in accounting_periods.
+func PeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PeriodReport is the period-end pack: the trial balance of the house
+// accounts, entry totals per currency and kind (which covers interest and
+// fees), and any adjustments posted later against the period.
+type PeriodReport struct {
+	Period       time.Time                 `json:"period"`
+	TrialBalance []GetHouseTrialBalanceRow `json:"trial_balance"`
+	Totals       []SumEntriesByKindRow     `json:"totals"`
+	Adjustments  []Entry                   `json:"adjustments"`
+}
+
+func buildPeriodReport(ctx context.Context, q *Queries, period time.Time) (PeriodReport, error) {
+	report := PeriodReport{Period: PeriodStart(period)}
+	start := report.Period
+	end := start.AddDate(0, 1, 0)
+
+	var err error
+	report.TrialBalance, err = q.GetHouseTrialBalance(ctx, GetHouseTrialBalanceParams{
+		PeriodStart: start,
+		PeriodEnd:   end,
+	})
+	if err != nil {
+		return report, err
+	}
+
+	report.Totals, err = q.SumEntriesByKind(ctx, SumEntriesByKindParams{
+		PeriodStart: start,
+		PeriodEnd:   end,
+	})
+	if err != nil {
+		return report, err
+	}
+
+	report.Adjustments, err = q.ListAdjustingEntries(ctx, sql.NullTime{Time: start, Valid: true})
+	return report, err
+}
+
+// GetPeriodReport regenerates the report of a period. Once the period is
+// closed its entries are frozen, so the result only changes by adjustments.
+func (store *SQLStore) GetPeriodReport(ctx context.Context, period time.Time) (PeriodReport, error) {
+	return buildPeriodReport(ctx, store.Queries, period)
+}
+
+// checkPeriodOpen refuses posting when the current period has been closed.
+func checkPeriodOpen(ctx context.Context, q *Queries) error {
+	closed, err := q.IsCurrentPeriodClosed(ctx)
+	if err != nil {
+		return err
+	}
+	if closed {
+		return ErrPeriodClosed
+	}
+	return nil
+}
+
+type ClosePeriodTxParams struct {
+	Period   time.Time `json:"period"`
+	ClosedBy string    `json:"closed_by"`
+}
+
+type ClosePeriodTxResult struct {
+	Period AccountingPeriod `json:"period"`
+	Report PeriodReport     `json:"report"`
+}
+
+// ClosePeriodTx closes a month that has already ended and generates its report
+// in the same transaction. The report is read from a single snapshot, so its
+// totals agree with each other even while entries are being posted.
+func (store *SQLStore) ClosePeriodTx(ctx context.Context, arg ClosePeriodTxParams) (ClosePeriodTxResult, error) {
+	var result ClosePeriodTxResult
+
+	period := PeriodStart(arg.Period)
+	if !period.Before(PeriodStart(time.Now())) {
+		return result, ErrPeriodNotEnded
+	}
+
+	err := store.execTxWith(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead}, func(q *Queries) error {
+		var err error
+
+		result.Period, err = q.CloseAccountingPeriod(ctx, CloseAccountingPeriodParams{
+			Period:   period,
+			ClosedBy: arg.ClosedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Report, err = buildPeriodReport(ctx, q, period)
+		return err
+	})
+
+	return result, err
+}
+
+type PostAdjustmentTxParams struct {
+	Period    time.Time `json:"period"`
+	AccountID int64     `json:"account_id"`
+	Amount    int64     `json:"amount"`
+}
+
+type PostAdjustmentTxResult struct {
+	Entry   Entry   `json:"entry"`
+	Account Account `json:"account"`
+}
+
+// PostAdjustmentTx corrects a closed period. The entry is posted in the open
+// period and linked to the closed one, so the closed period itself never
+// changes.
+func (store *SQLStore) PostAdjustmentTx(ctx context.Context, arg PostAdjustmentTxParams) (PostAdjustmentTxResult, error) {
+	var result PostAdjustmentTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		period, err := q.GetAccountingPeriod(ctx, PeriodStart(arg.Period))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrPeriodNotClosed
+			}
+			return err
+		}
+
+		if err := checkPeriodOpen(ctx, q); err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     arg.AccountID,
+			Amount: arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Entry, err = q.CreateAdjustingEntry(ctx, CreateAdjustingEntryParams{
+			AccountID:     arg.AccountID,
+			Amount:        arg.Amount,
+			AdjustsPeriod: sql.NullTime{Time: period.Period, Valid: true},
+			BalanceAfter:  result.Account.Balance,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodStart(t *testing.T) {
+	ts := time.Date(2024, time.March, 17, 23, 10, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), PeriodStart(ts))
+}
+
+func TestClosePeriodAndAdjustTx(t *testing.T) {
+	admin := createRandomUser(t)
+	account := createRandomAccount(t)
+
+	// A random month long ago, so reruns against the same database don't
+	// collide with an earlier close.
+	period := time.Date(int(util.RandomInt(1900, 1999)), time.Month(util.RandomInt(1, 12)), 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := testStore.PostAdjustmentTx(context.Background(), PostAdjustmentTxParams{
+		Period:    period,
+		AccountID: account.ID,
+		Amount:    10,
+	})
+	require.ErrorIs(t, err, ErrPeriodNotClosed)
+
+	result, err := testStore.ClosePeriodTx(context.Background(), ClosePeriodTxParams{
+		Period:   period.AddDate(0, 0, 14),
+		ClosedBy: admin.Username,
+	})
+	require.NoError(t, err)
+	require.True(t, period.Equal(result.Period.Period))
+	require.Equal(t, admin.Username, result.Period.ClosedBy)
+	require.Empty(t, result.Report.Adjustments)
+
+	adjustment, err := testStore.PostAdjustmentTx(context.Background(), PostAdjustmentTxParams{
+		Period:    period,
+		AccountID: account.ID,
+		Amount:    -10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "adjustment", adjustment.Entry.Kind)
+	require.True(t, period.Equal(adjustment.Entry.AdjustsPeriod.Time))
+	require.Equal(t, account.Balance-10, adjustment.Account.Balance)
+
+	report, err := testStore.GetPeriodReport(context.Background(), period)
+	require.NoError(t, err)
+	require.Len(t, report.Adjustments, 1)
+	require.Equal(t, adjustment.Entry.ID, report.Adjustments[0].ID)
+}
+
+func TestClosePeriodTxNotEnded(t *testing.T) {
+	_, err := testStore.ClosePeriodTx(context.Background(), ClosePeriodTxParams{
+		Period:   time.Now(),
+		ClosedBy: util.RandomOwner(),
+	})
+	require.ErrorIs(t, err, ErrPeriodNotEnded)
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+)
+
+// EntryKindAdjustment marks entries that correct a balance outside of the
+// normal money movements.
+const EntryKindAdjustment = "adjustment"
+
+type AdjustBalanceTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Amount is added to the balance; negative to take money off.
+	Amount     int64  `json:"amount"`
+	Reason     string `json:"reason"`
+	AdjustedBy string `json:"adjusted_by"`
+}
+
+type AdjustBalanceTxResult struct {
+	Account    Account           `json:"account"`
+	Entry      Entry             `json:"entry"`
+	Adjustment BalanceAdjustment `json:"adjustment"`
+}
+
+// AdjustBalanceTx lets an admin correct a balance. The entry and the record
+// of who made it and why are written with the balance change, so a balance
+// never moves without its explanation. Frozen accounts can be adjusted, closed
+// ones can't, and the balance can't go below zero.
+func (store *SQLStore) AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error) {
+	var result AdjustBalanceTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := checkPeriodOpen(ctx, q); err != nil {
+			return err
+		}
+
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Status == AccountClosed {
+			return ErrAccountClosed
+		}
+		if account.Balance+arg.Amount < 0 {
+			return ErrInsufficientFunds
+		}
+
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     arg.AccountID,
+			Amount: arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Entry, err = q.CreateEntryOfKind(ctx, CreateEntryOfKindParams{
+			AccountID:    arg.AccountID,
+			Amount:       arg.Amount,
+			Kind:         EntryKindAdjustment,
+			BalanceAfter: result.Account.Balance,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Adjustment, err = q.CreateBalanceAdjustment(ctx, CreateBalanceAdjustmentParams{
+			AccountID:  arg.AccountID,
+			EntryID:    result.Entry.ID,
+			Amount:     arg.Amount,
+			Reason:     arg.Reason,
+			AdjustedBy: arg.AdjustedBy,
+		})
+		return err
+	})
+
+	return result, err
+}
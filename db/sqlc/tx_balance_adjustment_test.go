@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdjustBalanceTx(t *testing.T) {
+	account := createRandomAccount(t)
+	admin := createRandomUser(t)
+
+	result, err := testStore.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  account.ID,
+		Amount:     -account.Balance,
+		Reason:     "reverse duplicate deposit",
+		AdjustedBy: admin.Username,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Account.Balance)
+	require.Equal(t, EntryKindAdjustment, result.Entry.Kind)
+	require.Equal(t, -account.Balance, result.Entry.Amount)
+	require.Equal(t, result.Entry.ID, result.Adjustment.EntryID)
+	require.Equal(t, "reverse duplicate deposit", result.Adjustment.Reason)
+	require.Equal(t, admin.Username, result.Adjustment.AdjustedBy)
+
+	_, err = testStore.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  account.ID,
+		Amount:     -1,
+		Reason:     "overdraw",
+		AdjustedBy: admin.Username,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	adjustments, err := testStore.ListBalanceAdjustments(context.Background(), ListBalanceAdjustmentsParams{
+		AccountID: account.ID,
+		Limit:     5,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []BalanceAdjustment{result.Adjustment}, adjustments)
+}
+
+func TestSuspendUserIsVersioned(t *testing.T) {
+	user := createRandomUser(t)
+	admin := createRandomUser(t)
+	require.Equal(t, UserActive, user.Status)
+
+	change, err := testStore.UpdateStandingDataTx(context.Background(), UpdateStandingDataTxParams{
+		EntityType: StandingDataUser,
+		EntityID:   user.Username,
+		Field:      "status",
+		NewValue:   UserSuspended,
+		ChangedBy:  admin.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, UserActive, change.OldValue)
+
+	users, err := testStore.SearchUsers(context.Background(), SearchUsersParams{
+		Search:    user.Username,
+		Status:    UserSuspended,
+		PageLimit: 5,
+	})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, UserSuspended, users[0].Status)
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+type BatchTransferTxParams struct {
+	Transfers []TransferTxParams `json:"transfers"`
+	// With Partial, a transfer that fails is rolled back on its own and the
+	// others are still made.
+	Partial bool `json:"partial"`
+}
+
+type BatchTransferTxResult struct {
+	// Transfers holds the result of every transfer, in the order they were
+	// given. With Partial, the transfers that failed have an empty result.
+	Transfers []TransferTxResult `json:"transfers"`
+	// Errors holds, with Partial, why each transfer failed, nil for the ones
+	// that were made.
+	Errors []error `json:"-"`
+}
+
+// BatchTransferTx makes every transfer or none of them, or with Partial
+// every transfer that succeeds. Each transfer gets the checks TransferTx
+// makes, and the daily limit counts the transfers made earlier in the batch.
+// Errors name the index of the failing transfer.
+func (store *SQLStore) BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) (BatchTransferTxResult, error) {
+	var result BatchTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// A retry of the transaction starts the batch over.
+		result.Transfers = make([]TransferTxResult, len(arg.Transfers))
+		result.Errors = nil
+		if arg.Partial {
+			result.Errors = make([]error, len(arg.Transfers))
+		}
+		for i, transfer := range arg.Transfers {
+			if !arg.Partial {
+				transferResult, err := transferTx(ctx, q, transfer)
+				if err != nil {
+					return fmt.Errorf("transfer %d: %w", i, err)
+				}
+				result.Transfers[i] = transferResult
+				continue
+			}
+
+			failed, err := inSavepoint(ctx, q, func(q *Queries) error {
+				var err error
+				result.Transfers[i], err = transferTx(ctx, q, transfer)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("transfer %d: %w", i, err)
+			}
+			if failed != nil {
+				result.Transfers[i] = TransferTxResult{}
+				result.Errors[i] = failed
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		store.publishTransfers(ctx, result.made()...)
+	}
+
+	return result, err
+}
+
+// made returns the results of the transfers that were made.
+func (result BatchTransferTxResult) made() []TransferTxResult {
+	if result.Errors == nil {
+		return result.Transfers
+	}
+	made := make([]TransferTxResult, 0, len(result.Transfers))
+	for i, transfer := range result.Transfers {
+		if result.Errors[i] == nil {
+			made = append(made, transfer)
+		}
+	}
+	return made
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTransferTx(t *testing.T) {
+	from := createRandomAccount(t)
+	to1 := createRandomAccount(t)
+	to2 := createRandomAccount(t)
+
+	result, err := testStore.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		Transfers: []TransferTxParams{
+			{FromAccountID: from.ID, ToAccountID: to1.ID, Amount: 10},
+			{FromAccountID: from.ID, ToAccountID: to2.ID, Amount: 5},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Transfers, 2)
+	require.Equal(t, from.Balance-15, result.Transfers[1].FromAccount.Balance)
+	require.Equal(t, to1.Balance+10, result.Transfers[0].ToAccount.Balance)
+	require.Equal(t, to2.Balance+5, result.Transfers[1].ToAccount.Balance)
+}
+
+func TestBatchTransferTxAllOrNothing(t *testing.T) {
+	from := createRandomAccount(t)
+	to := createRandomAccount(t)
+
+	_, err := testStore.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		Transfers: []TransferTxParams{
+			{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 10},
+			{FromAccountID: from.ID, ToAccountID: to.ID + 1_000_000, Amount: 5},
+		},
+	})
+	require.ErrorContains(t, err, "transfer 1")
+
+	// The first transfer was rolled back with the second.
+	account, err := testStore.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance, account.Balance)
+	account, err = testStore.GetAccount(context.Background(), to.ID)
+	require.NoError(t, err)
+	require.Equal(t, to.Balance, account.Balance)
+}
+
+func TestBatchTransferTxPartial(t *testing.T) {
+	from := createRandomAccount(t)
+	to := createRandomAccount(t)
+
+	result, err := testStore.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		Transfers: []TransferTxParams{
+			{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 10},
+			{FromAccountID: from.ID, ToAccountID: to.ID + 1_000_000, Amount: 5},
+			{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 1},
+		},
+		Partial: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Transfers, 3)
+	require.NoError(t, result.Errors[0])
+	require.Error(t, result.Errors[1])
+	require.Empty(t, result.Transfers[1].Transfer.ID)
+	require.NoError(t, result.Errors[2])
+
+	// Only the failed transfer was rolled back.
+	account, err := testStore.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance-11, account.Balance)
+	account, err = testStore.GetAccount(context.Background(), to.ID)
+	require.NoError(t, err)
+	require.Equal(t, to.Balance+11, account.Balance)
+}
+
+func TestInSavepointNested(t *testing.T) {
+	account := createRandomAccount(t)
+
+	err := testStore.(*SQLStore).execTx(context.Background(), func(q *Queries) error {
+		failed, err := inSavepoint(context.Background(), q, func(q *Queries) error {
+			if _, err := q.AddAccountBalance(context.Background(), AddAccountBalanceParams{ID: account.ID, Amount: 1}); err != nil {
+				return err
+			}
+			failed, err := inSavepoint(context.Background(), q, func(q *Queries) error {
+				if _, err := q.AddAccountBalance(context.Background(), AddAccountBalanceParams{ID: account.ID, Amount: 10}); err != nil {
+					return err
+				}
+				return errors.New("inner")
+			})
+			require.EqualError(t, failed, "inner")
+			return err
+		})
+		require.NoError(t, failed)
+		return err
+	})
+	require.NoError(t, err)
+
+	// The outer savepoint kept its change, the inner one's was undone.
+	got, err := testStore.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+1, got.Balance)
+}
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const (
+	// EntryKindDeposit marks entries for money paid into the bank.
+	EntryKindDeposit = "deposit"
+
+	// HouseRoleCash is the house account that holds the cash deposits are
+	// paid in with. There is at most one per currency.
+	HouseRoleCash = "cash"
+)
+
+// maxVersionConflicts is how many times addBalanceIfUnchanged reads an
+// account again after it changed under it before giving up.
+const maxVersionConflicts = 3
+
+var ErrVersionConflict = errors.New("account kept changing while it was updated, try again")
+
+type DepositTxParams struct {
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+}
+
+// DepositTxResult carries the cash side of the deposit only when a cash
+// account exists for the currency.
+type DepositTxResult struct {
+	Account     Account  `json:"account"`
+	Entry       Entry    `json:"entry"`
+	CashAccount *Account `json:"cash_account,omitempty"`
+	CashEntry   *Entry   `json:"cash_entry,omitempty"`
+}
+
+// DepositTx credits an account and records the entry. When a house cash
+// account exists for the account's currency it is debited by the same amount,
+// so deposits balance like transfers do.
+func (store *SQLStore) DepositTx(ctx context.Context, arg DepositTxParams) (DepositTxResult, error) {
+	var result DepositTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = depositTx(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// depositTx credits the account inside a transaction the caller owns.
+func depositTx(ctx context.Context, q *Queries, arg DepositTxParams) (DepositTxResult, error) {
+	var result DepositTxResult
+
+	if err := checkPeriodOpen(ctx, q); err != nil {
+		return result, err
+	}
+
+	account, err := q.GetAccount(ctx, arg.AccountID)
+	if err != nil {
+		return result, err
+	}
+
+	cash, err := q.GetHouseAccount(ctx, GetHouseAccountParams{
+		HouseRole: HouseRoleCash,
+		Currency:  account.Currency,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return result, err
+	}
+	hasCash := err == nil && cash.ID != account.ID
+
+	if !hasCash {
+		// Only the account changes, so it needn't be locked for the deposit.
+		result.Account, err = addBalanceIfUnchanged(ctx, q, account, arg.Amount)
+		if err != nil {
+			return result, err
+		}
+		if err := checkAccountsActive(result.Account); err != nil {
+			return result, err
+		}
+		result.Entry, err = createDepositEntry(ctx, q, result.Account, arg.Amount)
+		if err != nil {
+			return result, err
+		}
+		return result, enqueueDepositCompleted(ctx, q, result)
+	}
+
+	// Same lock order as TransferTx, so deposits can't deadlock with it.
+	var cashAccount Account
+	if arg.AccountID < cash.ID {
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{ID: arg.AccountID, Amount: arg.Amount})
+		if err != nil {
+			return result, err
+		}
+		cashAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{ID: cash.ID, Amount: -arg.Amount})
+	} else {
+		cashAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{ID: cash.ID, Amount: -arg.Amount})
+		if err != nil {
+			return result, err
+		}
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{ID: arg.AccountID, Amount: arg.Amount})
+	}
+	if err != nil {
+		return result, err
+	}
+	result.CashAccount = &cashAccount
+	if err := checkAccountsActive(result.Account); err != nil {
+		return result, err
+	}
+
+	result.Entry, err = createDepositEntry(ctx, q, result.Account, arg.Amount)
+	if err != nil {
+		return result, err
+	}
+	cashEntry, err := createDepositEntry(ctx, q, cashAccount, -arg.Amount)
+	if err != nil {
+		return result, err
+	}
+	result.CashEntry = &cashEntry
+	return result, enqueueDepositCompleted(ctx, q, result)
+}
+
+// addBalanceIfUnchanged adds amount to the balance of account, as read
+// without a lock, with AddAccountBalanceIfVersion. When the account changed
+// since, it is read again and the update retried, up to maxVersionConflicts
+// times, after which it fails with ErrVersionConflict.
+func addBalanceIfUnchanged(ctx context.Context, q *Queries, account Account, amount int64) (Account, error) {
+	for conflicts := 0; ; conflicts++ {
+		updated, err := q.AddAccountBalanceIfVersion(ctx, AddAccountBalanceIfVersionParams{
+			ID:      account.ID,
+			Amount:  amount,
+			Version: account.Version,
+		})
+		if err != sql.ErrNoRows {
+			return updated, err
+		}
+		if conflicts == maxVersionConflicts {
+			return Account{}, fmt.Errorf("%w: account %d", ErrVersionConflict, account.ID)
+		}
+		account, err = q.GetAccount(ctx, account.ID)
+		if err != nil {
+			return Account{}, err
+		}
+	}
+}
+
+// createDepositEntry books a deposit entry on an account whose balance has
+// already been changed by amount.
+func createDepositEntry(ctx context.Context, q *Queries, account Account, amount int64) (Entry, error) {
+	return q.CreateEntryOfKind(ctx, CreateEntryOfKindParams{
+		AccountID:    account.ID,
+		Amount:       amount,
+		Kind:         EntryKindDeposit,
+		BalanceAfter: account.Balance,
+	})
+}
+
+// enqueueDepositCompleted tells the owner's webhooks about the deposit. The
+// house cash side is internal and left out.
+func enqueueDepositCompleted(ctx context.Context, q *Queries, result DepositTxResult) error {
+	data := DepositTxResult{Account: result.Account, Entry: result.Entry}
+	return enqueueWebhookEvent(ctx, q, WebhookEventDepositCompleted, data, result.Account.Owner)
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositTx(t *testing.T) {
+	account := createRandomAccount(t)
+	amount := int64(10)
+
+	result, err := testStore.DepositTx(context.Background(), DepositTxParams{
+		AccountID: account.ID,
+		Amount:    amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+amount, result.Account.Balance)
+	require.Equal(t, account.ID, result.Entry.AccountID)
+	require.Equal(t, amount, result.Entry.Amount)
+	require.Equal(t, EntryKindDeposit, result.Entry.Kind)
+
+	// The cash side depends on whether this database has a cash account.
+	if result.CashEntry != nil {
+		require.Equal(t, -amount, result.CashEntry.Amount)
+		require.Equal(t, result.CashAccount.ID, result.CashEntry.AccountID)
+		require.Equal(t, HouseRoleCash, result.CashAccount.HouseRole)
+	}
+}
+
+func TestAddBalanceIfUnchanged(t *testing.T) {
+	account := createRandomAccount(t)
+
+	// Another write changes the account after it was read.
+	_, err := testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account.ID,
+		Amount: 5,
+	})
+	require.NoError(t, err)
+
+	updated, err := addBalanceIfUnchanged(context.Background(), testQueries, account, 10)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+15, updated.Balance)
+	require.Equal(t, account.Version+2, updated.Version)
+}
+
+// racingDB changes an account right before every compare-and-swap of its
+// balance, like a writer that always gets there first.
+type racingDB struct {
+	DBTX
+}
+
+func (db racingDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if query == addAccountBalanceIfVersion {
+		// args are amount, id and version.
+		_, _ = db.DBTX.ExecContext(ctx, addAccountBalance, 0, args[1])
+	}
+	return db.DBTX.QueryRowContext(ctx, query, args...)
+}
+
+func TestAddBalanceIfUnchangedGivesUp(t *testing.T) {
+	account := createRandomAccount(t)
+
+	_, err := addBalanceIfUnchanged(context.Background(), New(racingDB{testDB}), account, 10)
+	require.ErrorIs(t, err, ErrVersionConflict)
+
+	unchanged, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, unchanged.Balance)
+	require.Equal(t, account.Version+maxVersionConflicts+1, unchanged.Version)
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Kinds of email queued in email_jobs. Rate limits are counted per kind.
+const (
+	EmailKindStatement = "statement"
+)
+
+// Statuses of an email job.
+const (
+	EmailJobPending = "pending"
+	EmailJobSent    = "sent"
+	EmailJobFailed  = "failed"
+)
+
+var ErrEmailRateLimited = errors.New("too many emails requested, try again later")
+
+type EnqueueEmailTxParams struct {
+	Username string `json:"username"`
+	Kind     string `json:"kind"`
+	Subject  string `json:"subject"`
+	Body     string `json:"body"`
+	// At most Limit emails of this kind may be queued for the user since
+	// Since. A zero Limit disables the check.
+	Since time.Time `json:"since"`
+	Limit int64     `json:"limit"`
+}
+
+// EnqueueEmailTx queues an email to the address on file for the user. The
+// user row is locked while the rate limit is counted, so concurrent requests
+// cannot both squeeze in under it.
+func (store *SQLStore) EnqueueEmailTx(ctx context.Context, arg EnqueueEmailTxParams) (EmailJob, error) {
+	var job EmailJob
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		user, err := q.GetUserForUpdate(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		if arg.Limit > 0 {
+			sent, err := q.CountEmailJobsSince(ctx, CountEmailJobsSinceParams{
+				Username: arg.Username,
+				Kind:     arg.Kind,
+				Since:    arg.Since,
+			})
+			if err != nil {
+				return err
+			}
+			if sent >= arg.Limit {
+				return ErrEmailRateLimited
+			}
+		}
+
+		job, err = q.CreateEmailJob(ctx, CreateEmailJobParams{
+			Username:  arg.Username,
+			Kind:      arg.Kind,
+			Recipient: user.Email,
+			Subject:   arg.Subject,
+			Body:      arg.Body,
+		})
+		return err
+	})
+
+	return job, err
+}
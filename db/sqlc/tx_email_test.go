@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnqueueEmailTxRateLimit(t *testing.T) {
+	user := createRandomUser(t)
+	arg := EnqueueEmailTxParams{
+		Username: user.Username,
+		Kind:     EmailKindStatement,
+		Subject:  "Statement",
+		Body:     "body",
+		Since:    time.Now().Add(-time.Hour),
+		Limit:    2,
+	}
+
+	for i := 0; i < 2; i++ {
+		job, err := testStore.EnqueueEmailTx(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, user.Email, job.Recipient)
+		require.Equal(t, EmailJobPending, job.Status)
+	}
+
+	_, err := testStore.EnqueueEmailTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrEmailRateLimited)
+
+	// Older emails no longer count.
+	arg.Since = time.Now().Add(time.Minute)
+	_, err = testStore.EnqueueEmailTx(context.Background(), arg)
+	require.NoError(t, err)
+}
+
+func TestClaimEmailJobs(t *testing.T) {
+	user := createRandomUser(t)
+	job, err := testStore.EnqueueEmailTx(context.Background(), EnqueueEmailTxParams{
+		Username: user.Username,
+		Kind:     EmailKindStatement,
+		Subject:  "Statement",
+		Body:     "body",
+	})
+	require.NoError(t, err)
+
+	now := time.Now().Add(time.Second)
+	claimed, err := testStore.ClaimEmailJobs(context.Background(), ClaimEmailJobsParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+	require.Contains(t, emailJobIDs(claimed), job.ID)
+
+	// A claimed job is hidden until its lease runs out.
+	again, err := testStore.ClaimEmailJobs(context.Background(), ClaimEmailJobsParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+	require.NotContains(t, emailJobIDs(again), job.ID)
+
+	err = testStore.MarkEmailJobFailed(context.Background(), MarkEmailJobFailedParams{
+		Status:    EmailJobPending,
+		LastError: sql.NullString{String: "relay down", Valid: true},
+		RetryAt:   now,
+		ID:        job.ID,
+	})
+	require.NoError(t, err)
+
+	retried, err := testStore.ClaimEmailJobs(context.Background(), ClaimEmailJobsParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+	require.Contains(t, emailJobIDs(retried), job.ID)
+
+	require.NoError(t, testStore.MarkEmailJobSent(context.Background(), job.ID))
+}
+
+func emailJobIDs(jobs []EmailJob) []int64 {
+	ids := make([]int64, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.ID
+	}
+	return ids
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrDepositReferenceUsed = errors.New("bank reference was already used for another deposit")
+
+type ExternalDepositTxParams struct {
+	Reference string `json:"reference"`
+	AccountID int64  `json:"account_id"`
+	Amount    int64  `json:"amount"`
+}
+
+// ExternalDepositTxResult is the deposit as first credited. Replayed is true
+// when the reference had already been credited and nothing changed.
+type ExternalDepositTxResult struct {
+	Deposit  ExternalDeposit `json:"deposit"`
+	Account  Account         `json:"account"`
+	Entry    Entry           `json:"entry"`
+	Replayed bool            `json:"replayed"`
+}
+
+// ExternalDepositTx records an inbound wire by its bank reference and credits
+// the account like DepositTx. The reference is claimed before the money moves,
+// so however often a wire is reported it is credited once: a repeat with the
+// same account and amount returns the first deposit, anything else fails with
+// ErrDepositReferenceUsed.
+func (store *SQLStore) ExternalDepositTx(ctx context.Context, arg ExternalDepositTxParams) (ExternalDepositTxResult, error) {
+	var result ExternalDepositTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		deposit, err := q.CreateExternalDeposit(ctx, CreateExternalDepositParams{
+			Reference: arg.Reference,
+			AccountID: arg.AccountID,
+			Amount:    arg.Amount,
+		})
+		if err == sql.ErrNoRows {
+			return replayExternalDeposit(ctx, q, arg, &result)
+		}
+		if err != nil {
+			return err
+		}
+
+		credit, err := depositTx(ctx, q, DepositTxParams{
+			AccountID: arg.AccountID,
+			Amount:    arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+		result.Account = credit.Account
+		result.Entry = credit.Entry
+
+		result.Deposit, err = q.SetExternalDepositEntry(ctx, SetExternalDepositEntryParams{
+			ID:      deposit.ID,
+			EntryID: sql.NullInt64{Int64: credit.Entry.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+func replayExternalDeposit(ctx context.Context, q *Queries, arg ExternalDepositTxParams, result *ExternalDepositTxResult) error {
+	deposit, err := q.GetExternalDepositByReference(ctx, arg.Reference)
+	if err != nil {
+		return err
+	}
+	if deposit.AccountID != arg.AccountID || deposit.Amount != arg.Amount || !deposit.EntryID.Valid {
+		return ErrDepositReferenceUsed
+	}
+
+	result.Deposit = deposit
+	result.Replayed = true
+	result.Account, err = q.GetAccount(ctx, deposit.AccountID)
+	if err != nil {
+		return err
+	}
+	result.Entry, err = q.GetEntry(ctx, deposit.EntryID.Int64)
+	return err
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalDepositTx(t *testing.T) {
+	account := createRandomAccount(t)
+	arg := ExternalDepositTxParams{
+		Reference: "FT" + util.RandomString(10),
+		AccountID: account.ID,
+		Amount:    250,
+	}
+
+	first, err := testStore.ExternalDepositTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, first.Replayed)
+	require.Equal(t, account.Balance+arg.Amount, first.Account.Balance)
+	require.Equal(t, EntryKindDeposit, first.Entry.Kind)
+	require.True(t, first.Deposit.EntryID.Valid)
+	require.Equal(t, first.Entry.ID, first.Deposit.EntryID.Int64)
+
+	// Reported again: nothing is credited twice.
+	again, err := testStore.ExternalDepositTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, again.Replayed)
+	require.Equal(t, first.Deposit.ID, again.Deposit.ID)
+	require.Equal(t, first.Entry.ID, again.Entry.ID)
+	require.Equal(t, first.Account.Balance, again.Account.Balance)
+
+	// The same reference for another amount is not the same wire.
+	other := arg
+	other.Amount++
+	_, err = testStore.ExternalDepositTx(context.Background(), other)
+	require.ErrorIs(t, err, ErrDepositReferenceUsed)
+}
+
+func TestExternalDepositTxConcurrent(t *testing.T) {
+	account := createRandomAccount(t)
+	arg := ExternalDepositTxParams{
+		Reference: "FT" + util.RandomString(10),
+		AccountID: account.ID,
+		Amount:    10,
+	}
+
+	n := 5
+	results := make(chan ExternalDepositTxResult, n)
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := testStore.ExternalDepositTx(context.Background(), arg)
+			errs <- err
+			results <- result
+		}()
+	}
+
+	credited := 0
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+		if !(<-results).Replayed {
+			credited++
+		}
+	}
+	require.Equal(t, 1, credited)
+
+	updated, err := testStore.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+arg.Amount, updated.Balance)
+}
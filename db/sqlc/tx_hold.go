@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Statuses of a hold.
+const (
+	HoldActive   = "active"
+	HoldReleased = "released"
+	HoldCaptured = "captured"
+)
+
+var (
+	ErrHoldNotActive      = errors.New("hold has already been released or captured")
+	ErrCaptureExceedsHold = errors.New("capture is larger than the hold")
+)
+
+// AvailableBalance is the balance less what active holds set aside.
+func (account Account) AvailableBalance() int64 {
+	return account.Balance - account.Held
+}
+
+type PlaceHoldTxParams struct {
+	AccountID int64  `json:"account_id"`
+	Amount    int64  `json:"amount"`
+	Memo      string `json:"memo,omitempty"`
+}
+
+// HoldTxResult is a hold and its account after the hold was placed or
+// released.
+type HoldTxResult struct {
+	Hold    Hold    `json:"hold"`
+	Account Account `json:"account"`
+}
+
+// PlaceHoldTx sets money aside on an account until the hold is released or
+// captured. Like a debit, a hold can't take the available balance past the
+// overdraft, and the check runs under the row lock of the account.
+func (store *SQLStore) PlaceHoldTx(ctx context.Context, arg PlaceHoldTxParams) (HoldTxResult, error) {
+	var result HoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Account, err = q.AddAccountHeld(ctx, AddAccountHeldParams{
+			ID:     arg.AccountID,
+			Amount: arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+		if err := checkAccountsActive(result.Account); err != nil {
+			return err
+		}
+		if err := checkOverdraft(result.Account); err != nil {
+			return err
+		}
+
+		result.Hold, err = q.CreateHold(ctx, CreateHoldParams{
+			AccountID: arg.AccountID,
+			Amount:    arg.Amount,
+			Memo:      newMemo(arg.Memo),
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ReleaseHoldTx ends a hold without taking the money, which is available
+// again.
+func (store *SQLStore) ReleaseHoldTx(ctx context.Context, holdID int64) (HoldTxResult, error) {
+	var result HoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := lockActiveHold(ctx, q, holdID)
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.ResolveHold(ctx, ResolveHoldParams{
+			ID:     holdID,
+			Status: HoldReleased,
+		})
+		if err != nil {
+			return err
+		}
+		result.Account, err = q.AddAccountHeld(ctx, AddAccountHeldParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type CaptureHoldTxParams struct {
+	HoldID      int64 `json:"hold_id"`
+	ToAccountID int64 `json:"to_account_id"`
+	// Optional: the whole hold when zero. What isn't captured is released.
+	Amount int64  `json:"amount,omitempty"`
+	Memo   string `json:"memo,omitempty"`
+}
+
+type CaptureHoldTxResult struct {
+	Hold     Hold             `json:"hold"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// CaptureHoldTx takes the money of a hold by transferring it from the held
+// account. The hold is lifted first, so the transfer can spend what it set
+// aside, in the same transaction, so nothing else can spend it in between.
+// Lifting it locks the held account, so both accounts are locked in ID order
+// beforehand, as transfers lock them.
+func (store *SQLStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := lockActiveHold(ctx, q, arg.HoldID)
+		if err != nil {
+			return err
+		}
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount > hold.Amount {
+			return fmt.Errorf("%w: hold %d is %d", ErrCaptureExceedsHold, hold.ID, hold.Amount)
+		}
+
+		ids := []int64{hold.AccountID, arg.ToAccountID}
+		if arg.ToAccountID < hold.AccountID {
+			ids = []int64{arg.ToAccountID, hold.AccountID}
+		}
+		for _, id := range ids {
+			if _, err := q.GetAccountForUpdate(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		_, err = q.AddAccountHeld(ctx, AddAccountHeldParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = transferTx(ctx, q, TransferTxParams{
+			FromAccountID: hold.AccountID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        amount,
+			Memo:          arg.Memo,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.ResolveHold(ctx, ResolveHoldParams{
+			ID:         hold.ID,
+			Status:     HoldCaptured,
+			TransferID: sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+	if err == nil {
+		store.publishTransfers(ctx, result.Transfer)
+	}
+
+	return result, err
+}
+
+// lockActiveHold locks a hold that is still active. Holds are locked before
+// their account, as placing one only locks the account.
+func lockActiveHold(ctx context.Context, q *Queries, holdID int64) (Hold, error) {
+	hold, err := q.GetHoldForUpdate(ctx, holdID)
+	if err != nil {
+		return hold, err
+	}
+	if hold.Status != HoldActive {
+		return hold, fmt.Errorf("%w: hold %d is %s", ErrHoldNotActive, hold.ID, hold.Status)
+	}
+	return hold, nil
+}
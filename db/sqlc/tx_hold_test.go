@@ -0,0 +1,143 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceAndReleaseHoldTx(t *testing.T) {
+	account := createRandomAccount(t)
+
+	placed, err := testStore.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID: account.ID,
+		Amount:    account.Balance - 10,
+		Memo:      "card authorization",
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldActive, placed.Hold.Status)
+	require.Equal(t, account.Balance, placed.Account.Balance)
+	require.Equal(t, account.Balance-10, placed.Account.Held)
+	require.Equal(t, int64(10), placed.Account.AvailableBalance())
+
+	// Held money can't be held again, nor withdrawn.
+	_, err = testStore.PlaceHoldTx(context.Background(), PlaceHoldTxParams{AccountID: account.ID, Amount: 11})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+	_, err = testStore.WithdrawTx(context.Background(), WithdrawTxParams{AccountID: account.ID, Amount: 11})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	holds, err := testStore.ListActiveHolds(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Len(t, holds, 1)
+
+	released, err := testStore.ReleaseHoldTx(context.Background(), placed.Hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldReleased, released.Hold.Status)
+	require.True(t, released.Hold.ResolvedAt.Valid)
+	require.Zero(t, released.Account.Held)
+
+	_, err = testStore.ReleaseHoldTx(context.Background(), placed.Hold.ID)
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	_, err = testStore.WithdrawTx(context.Background(), WithdrawTxParams{AccountID: account.ID, Amount: 11})
+	require.NoError(t, err)
+}
+
+func TestTransferTxSpendsOnlyAvailable(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	_, err := testStore.PlaceHoldTx(context.Background(), PlaceHoldTxParams{AccountID: account1.ID, Amount: account1.Balance})
+	require.NoError(t, err)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	placed, err := testStore.PlaceHoldTx(context.Background(), PlaceHoldTxParams{AccountID: account1.ID, Amount: account1.Balance})
+	require.NoError(t, err)
+
+	_, err = testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID:      placed.Hold.ID,
+		ToAccountID: account2.ID,
+		Amount:      account1.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	// A partial capture spends the held money and releases the rest.
+	captured, err := testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID:      placed.Hold.ID,
+		ToAccountID: account2.ID,
+		Amount:      30,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldCaptured, captured.Hold.Status)
+	require.Equal(t, sql.NullInt64{Int64: captured.Transfer.Transfer.ID, Valid: true}, captured.Hold.TransferID)
+	require.Equal(t, int64(30), captured.Transfer.Transfer.Amount)
+	require.Equal(t, account1.Balance-30, captured.Transfer.FromAccount.Balance)
+	require.Zero(t, captured.Transfer.FromAccount.Held)
+	require.Equal(t, account2.Balance+30, captured.Transfer.ToAccount.Balance)
+
+	_, err = testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: placed.Hold.ID, ToAccountID: account2.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+// TestCaptureHoldTxDeadlock captures holds into an account with a lower ID
+// while transfers run the other way. Unless capturing locks the accounts in
+// the order transfers do, they deadlock, which execTx hides by retrying, so
+// the test fails on any retry it logs.
+func TestCaptureHoldTxDeadlock(t *testing.T) {
+	var output bytes.Buffer
+	store := NewStore(testDB, WithLogger(slog.New(slog.NewJSONHandler(&output, nil))))
+	to := createRandomAccount(t)
+	held := createRandomAccount(t)
+	require.Less(t, to.ID, held.ID)
+
+	n := 5
+	holds := make([]Hold, n)
+	for i := range holds {
+		placed, err := testStore.PlaceHoldTx(context.Background(), PlaceHoldTxParams{AccountID: held.ID, Amount: 10})
+		require.NoError(t, err)
+		holds[i] = placed.Hold
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*n)
+	for _, hold := range holds {
+		wg.Add(2)
+		go func(holdID int64) {
+			defer wg.Done()
+			_, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: holdID, ToAccountID: to.ID})
+			errs <- err
+		}(hold.ID)
+		go func() {
+			defer wg.Done()
+			_, err := store.TransferTx(context.Background(), TransferTxParams{FromAccountID: to.ID, ToAccountID: held.ID, Amount: 10})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.Empty(t, output.String())
+
+	updated, err := testStore.GetAccount(context.Background(), held.ID)
+	require.NoError(t, err)
+	require.Equal(t, held.Balance, updated.Balance)
+	require.Zero(t, updated.Held)
+}
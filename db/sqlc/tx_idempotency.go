@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+)
+
+var ErrIdempotencyKeyUsed = errors.New("idempotency key has already been used")
+
+// Statuses of an idempotency key.
+const (
+	IdempotencyInProgress = "in_progress"
+	IdempotencyCompleted  = "completed"
+)
+
+// claimIdempotency reserves the key for the running transaction. It is a
+// no-op without a key. Once the claiming transaction commits, any other claim
+// of the key fails, so a retried request can never move money twice.
+func claimIdempotency(ctx context.Context, q *Queries, arg ClaimIdempotencyKeyParams) error {
+	if arg.Key == "" {
+		return nil
+	}
+	_, err := q.ClaimIdempotencyKey(ctx, arg)
+	if err == sql.ErrNoRows {
+		return ErrIdempotencyKeyUsed
+	}
+	return err
+}
+
+// saveIdempotentResponse stores the response a retry of the request replays.
+func saveIdempotentResponse(ctx context.Context, q *Queries, arg ClaimIdempotencyKeyParams, response interface{}) error {
+	if arg.Key == "" {
+		return nil
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return q.SetIdempotencyKeyResponse(ctx, SetIdempotencyKeyResponseParams{
+		Username: arg.Username,
+		Key:      arg.Key,
+		Response: data,
+	})
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxIdempotency(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	arg := TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Idempotency: ClaimIdempotencyKeyParams{
+			Username:    account1.Owner,
+			Key:         util.RandomString(16),
+			RequestHash: util.RandomString(64),
+		},
+	}
+
+	result, err := testStore.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = testStore.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyUsed)
+
+	// Only the first transfer moved money.
+	from, err := testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-10, from.Balance)
+
+	stored, err := testStore.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: arg.Idempotency.Username,
+		Key:      arg.Idempotency.Key,
+	})
+	require.NoError(t, err)
+	require.Equal(t, arg.Idempotency.RequestHash, stored.RequestHash)
+	require.Equal(t, IdempotencyCompleted, stored.Status)
+	require.Equal(t, int32(200), stored.ResponseStatus)
+
+	var replayed TransferTxResult
+	require.NoError(t, json.Unmarshal(stored.Response, &replayed))
+	require.Equal(t, result.Transfer.ID, replayed.Transfer.ID)
+}
+
+func TestStartIdempotentRequest(t *testing.T) {
+	user := createRandomUser(t)
+	arg := StartIdempotentRequestParams{
+		Username:    user.Username,
+		Key:         util.RandomString(16),
+		RequestHash: util.RandomString(64),
+		StaleBefore: time.Now().Add(-time.Minute),
+	}
+	key := GetIdempotencyKeyParams{Username: arg.Username, Key: arg.Key}
+
+	claimed, err := testStore.StartIdempotentRequest(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, IdempotencyInProgress, claimed.Status)
+
+	// Taken while it runs.
+	_, err = testStore.StartIdempotentRequest(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Released after a failure, so it can be claimed again.
+	require.NoError(t, testStore.DeleteIdempotentRequest(context.Background(), DeleteIdempotentRequestParams{Username: arg.Username, Key: arg.Key}))
+	_, err = testStore.GetIdempotencyKey(context.Background(), key)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Left in progress by a server that died, then taken over by a retry.
+	_, err = testStore.StartIdempotentRequest(context.Background(), arg)
+	require.NoError(t, err)
+	arg.StaleBefore = time.Now().Add(time.Minute)
+	_, err = testStore.StartIdempotentRequest(context.Background(), arg)
+	require.NoError(t, err)
+
+	err = testStore.FinishIdempotentRequest(context.Background(), FinishIdempotentRequestParams{
+		Username:       arg.Username,
+		Key:            arg.Key,
+		ResponseStatus: 201,
+		Response:       json.RawMessage(`{"id":1}`),
+	})
+	require.NoError(t, err)
+
+	stored, err := testStore.GetIdempotencyKey(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, IdempotencyCompleted, stored.Status)
+	require.Equal(t, int32(201), stored.ResponseStatus)
+	require.JSONEq(t, `{"id":1}`, string(stored.Response))
+
+	// Completed keys are never taken over, nor released.
+	_, err = testStore.StartIdempotentRequest(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, testStore.DeleteIdempotentRequest(context.Background(), DeleteIdempotentRequestParams{Username: arg.Username, Key: arg.Key}))
+	_, err = testStore.GetIdempotencyKey(context.Background(), key)
+	require.NoError(t, err)
+}
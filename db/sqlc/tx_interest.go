@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const (
+	// EntryKindInterest marks entries for interest paid on an account.
+	EntryKindInterest = "interest"
+
+	// HouseRoleInterest is the house account interest is paid from. There
+	// is at most one per currency.
+	HouseRoleInterest = "interest"
+
+	// MicrosPerUnit is how many accrued micros make up one unit of balance.
+	MicrosPerUnit = 1_000_000
+)
+
+type PostInterestTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Only accruals of days before this date are posted.
+	Before time.Time `json:"before"`
+}
+
+// PostInterestTxResult has no entry when the accrued interest doesn't add up
+// to a whole unit yet; the accruals then stay unposted and carry over.
+type PostInterestTxResult struct {
+	Account         Account  `json:"account"`
+	Entry           *Entry   `json:"entry,omitempty"`
+	InterestAccount *Account `json:"interest_account,omitempty"`
+	InterestEntry   *Entry   `json:"interest_entry,omitempty"`
+}
+
+// PostInterestTx credits an account with the interest accrued before
+// arg.Before and marks those accruals posted. Fractions of a unit are
+// rounded down. When a house interest account exists for the currency it is
+// debited by the same amount, as DepositTx does with cash.
+func (store *SQLStore) PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error) {
+	var result PostInterestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := checkPeriodOpen(ctx, q); err != nil {
+			return err
+		}
+
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		house, err := q.GetHouseAccount(ctx, GetHouseAccountParams{
+			HouseRole: HouseRoleInterest,
+			Currency:  account.Currency,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		hasHouse := err == nil && house.ID != account.ID
+
+		// Both accounts are locked in ID order, like TransferTx does, before
+		// the accruals are read, so two runs can't post them twice.
+		ids := []int64{account.ID}
+		if hasHouse {
+			if house.ID < account.ID {
+				ids = []int64{house.ID, account.ID}
+			} else {
+				ids = append(ids, house.ID)
+			}
+		}
+		for _, id := range ids {
+			locked, err := q.GetAccountForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if id == account.ID {
+				account = locked
+			}
+		}
+		result.Account = account
+		if account.Status == AccountClosed {
+			return ErrAccountClosed
+		}
+
+		micros, err := q.SumUnpostedInterest(ctx, SumUnpostedInterestParams{
+			AccountID: account.ID,
+			Before:    arg.Before,
+		})
+		if err != nil {
+			return err
+		}
+		amount := micros / MicrosPerUnit
+		if amount == 0 {
+			return nil
+		}
+
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     account.ID,
+			Amount: amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		entry, err := q.CreateEntryOfKind(ctx, CreateEntryOfKindParams{
+			AccountID:    account.ID,
+			Amount:       amount,
+			Kind:         EntryKindInterest,
+			BalanceAfter: result.Account.Balance,
+		})
+		if err != nil {
+			return err
+		}
+		result.Entry = &entry
+
+		if hasHouse {
+			houseAccount, err := q.AddAccountBalance(ctx, AddAccountBalanceParams{
+				ID:     house.ID,
+				Amount: -amount,
+			})
+			if err != nil {
+				return err
+			}
+			result.InterestAccount = &houseAccount
+
+			houseEntry, err := q.CreateEntryOfKind(ctx, CreateEntryOfKindParams{
+				AccountID:    house.ID,
+				Amount:       -amount,
+				Kind:         EntryKindInterest,
+				BalanceAfter: houseAccount.Balance,
+			})
+			if err != nil {
+				return err
+			}
+			result.InterestEntry = &houseEntry
+		}
+
+		_, err = q.MarkInterestPosted(ctx, MarkInterestPostedParams{
+			EntryID:   sql.NullInt64{Int64: entry.ID, Valid: true},
+			AccountID: account.ID,
+			Before:    arg.Before,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostInterestTx(t *testing.T) {
+	account := createRandomAccount(t)
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, micros := range []int64{700_000, 800_000, 900_000} {
+		n, err := testStore.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+			AccountID:    account.ID,
+			AccrualDate:  march.AddDate(0, 0, i-2),
+			Balance:      account.Balance,
+			RateBps:      500,
+			AmountMicros: micros,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+	}
+
+	// The same day again is skipped.
+	n, err := testStore.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+		AccountID:    account.ID,
+		AccrualDate:  march,
+		AmountMicros: 1,
+	})
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// Only February is posted: 1.5 units, rounded down.
+	result, err := testStore.PostInterestTx(context.Background(), PostInterestTxParams{AccountID: account.ID, Before: march})
+	require.NoError(t, err)
+	require.NotNil(t, result.Entry)
+	require.Equal(t, int64(1), result.Entry.Amount)
+	require.Equal(t, EntryKindInterest, result.Entry.Kind)
+	require.Equal(t, account.Balance+1, result.Account.Balance)
+
+	unposted, err := testStore.ListUnpostedInterestAccruals(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Len(t, unposted, 1)
+	require.True(t, unposted[0].AccrualDate.Equal(march))
+
+	// Posting again finds nothing before March.
+	result, err = testStore.PostInterestTx(context.Background(), PostInterestTxParams{AccountID: account.ID, Before: march})
+	require.NoError(t, err)
+	require.Nil(t, result.Entry)
+	require.Equal(t, account.Balance+1, result.Account.Balance)
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ankurdas111111/simplebank/util"
+)
+
+// KYC document review states.
+const (
+	KycDocumentPending  = "pending"
+	KycDocumentApproved = "approved"
+	KycDocumentRejected = "rejected"
+)
+
+var ErrKycDocumentReviewed = errors.New("kyc document has already been reviewed")
+
+type ReviewKycDocumentTxParams struct {
+	DocumentID int64  `json:"document_id"`
+	Approve    bool   `json:"approve"`
+	ReviewedBy string `json:"reviewed_by"`
+	Note       string `json:"note"`
+}
+
+type ReviewKycDocumentTxResult struct {
+	Document ReviewKycDocumentRow `json:"document"`
+	User     User                 `json:"user"`
+}
+
+// ReviewKycDocumentTx records an admin decision on a pending document. An
+// approval raises the owner's KYC tier to the requested one (never lowers it),
+// and the tier change goes through the standing data history.
+func (store *SQLStore) ReviewKycDocumentTx(ctx context.Context, arg ReviewKycDocumentTxParams) (ReviewKycDocumentTxResult, error) {
+	var result ReviewKycDocumentTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		doc, err := q.GetKycDocumentForUpdate(ctx, arg.DocumentID)
+		if err != nil {
+			return err
+		}
+		if doc.Status != KycDocumentPending {
+			return ErrKycDocumentReviewed
+		}
+
+		status := KycDocumentRejected
+		if arg.Approve {
+			status = KycDocumentApproved
+		}
+		result.Document, err = q.ReviewKycDocument(ctx, ReviewKycDocumentParams{
+			ID:         arg.DocumentID,
+			Status:     status,
+			ReviewedBy: sql.NullString{String: arg.ReviewedBy, Valid: true},
+			ReviewNote: arg.Note,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.GetUserForUpdate(ctx, doc.Username)
+		if err != nil {
+			return err
+		}
+		if !arg.Approve || util.KYCTierAtLeast(result.User.KycTier, doc.RequestedTier) {
+			return nil
+		}
+
+		field, err := lookupStandingField(StandingDataUser, "kyc_tier")
+		if err != nil {
+			return err
+		}
+		_, err = applyStandingDataChange(ctx, q, field, CreateStandingDataChangeParams{
+			EntityType: StandingDataUser,
+			EntityID:   doc.Username,
+			Field:      "kyc_tier",
+			NewValue:   doc.RequestedTier,
+			ChangedBy:  arg.ReviewedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.GetUser(ctx, doc.Username)
+		return err
+	})
+
+	return result, err
+}
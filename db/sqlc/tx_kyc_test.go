@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewKycDocumentTx(t *testing.T) {
+	user := createRandomUser(t)
+	admin := createRandomUser(t)
+
+	doc, err := testStore.CreateKycDocument(context.Background(), CreateKycDocumentParams{
+		Username:      user.Username,
+		DocumentType:  "passport",
+		RequestedTier: util.KYCTierVerified,
+		FileName:      "passport.pdf",
+		ContentType:   "application/pdf",
+		Content:       []byte("%PDF-1.4"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, KycDocumentPending, doc.Status)
+
+	result, err := testStore.ReviewKycDocumentTx(context.Background(), ReviewKycDocumentTxParams{
+		DocumentID: doc.ID,
+		Approve:    true,
+		ReviewedBy: admin.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, KycDocumentApproved, result.Document.Status)
+	require.Equal(t, admin.Username, result.Document.ReviewedBy.String)
+	require.Equal(t, util.KYCTierVerified, result.User.KycTier)
+
+	history, err := testStore.ListStandingDataChanges(context.Background(), ListStandingDataChangesParams{
+		EntityType: StandingDataUser,
+		EntityID:   user.Username,
+		Limit:      10,
+		Offset:     0,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, "kyc_tier", history[0].Field)
+	require.Equal(t, util.KYCTierBasic, history[0].OldValue)
+
+	_, err = testStore.ReviewKycDocumentTx(context.Background(), ReviewKycDocumentTxParams{
+		DocumentID: doc.ID,
+		ReviewedBy: admin.Username,
+	})
+	require.ErrorIs(t, err, ErrKycDocumentReviewed)
+}
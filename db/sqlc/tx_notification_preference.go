@@ -0,0 +1,40 @@
+package db
+
+import "context"
+
+type NotificationPreferenceChange struct {
+	Channel   string `json:"channel"`
+	EventType string `json:"event_type"`
+	Enabled   bool   `json:"enabled"`
+}
+
+type UpdateNotificationPreferencesTxParams struct {
+	Username    string                         `json:"username"`
+	Preferences []NotificationPreferenceChange `json:"preferences"`
+}
+
+// UpdateNotificationPreferencesTx saves every change or none of them and
+// returns all preferences the user has stored.
+func (store *SQLStore) UpdateNotificationPreferencesTx(ctx context.Context, arg UpdateNotificationPreferencesTxParams) ([]NotificationPreference, error) {
+	var preferences []NotificationPreference
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, change := range arg.Preferences {
+			_, err := q.UpsertNotificationPreference(ctx, UpsertNotificationPreferenceParams{
+				Username:  arg.Username,
+				Channel:   change.Channel,
+				EventType: change.EventType,
+				Enabled:   change.Enabled,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		preferences, err = q.ListNotificationPreferences(ctx, arg.Username)
+		return err
+	})
+
+	return preferences, err
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateNotificationPreferencesTx(t *testing.T) {
+	user := createRandomUser(t)
+
+	_, err := testStore.GetNotificationPreference(context.Background(), GetNotificationPreferenceParams{
+		Username:  user.Username,
+		Channel:   "sms",
+		EventType: "transfer.large",
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	preferences, err := testStore.UpdateNotificationPreferencesTx(context.Background(), UpdateNotificationPreferencesTxParams{
+		Username: user.Username,
+		Preferences: []NotificationPreferenceChange{
+			{Channel: "sms", EventType: "transfer.large", Enabled: false},
+			{Channel: "in_app", EventType: "balance.low", Enabled: false},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, preferences, 2)
+
+	// Saving again updates the stored choice.
+	preferences, err = testStore.UpdateNotificationPreferencesTx(context.Background(), UpdateNotificationPreferencesTxParams{
+		Username: user.Username,
+		Preferences: []NotificationPreferenceChange{
+			{Channel: "sms", EventType: "transfer.large", Enabled: true},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, preferences, 2)
+
+	preference, err := testStore.GetNotificationPreference(context.Background(), GetNotificationPreferenceParams{
+		Username:  user.Username,
+		Channel:   "sms",
+		EventType: "transfer.large",
+	})
+	require.NoError(t, err)
+	require.True(t, preference.Enabled)
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Statuses of a payment request.
+const (
+	PaymentRequestPending  = "pending"
+	PaymentRequestAccepted = "accepted"
+	PaymentRequestDeclined = "declined"
+)
+
+var ErrPaymentRequestAnswered = errors.New("payment request has already been answered")
+
+type AcceptPaymentRequestTxParams struct {
+	ID            int64 `json:"id"`
+	FromAccountID int64 `json:"from_account_id"`
+}
+
+type AcceptPaymentRequestTxResult struct {
+	PaymentRequest PaymentRequest   `json:"payment_request"`
+	Transfer       TransferTxResult `json:"transfer"`
+}
+
+// AcceptPaymentRequestTx pays a pending request from the payer's account. The
+// request row stays locked until the transfer commits, so a request is paid
+// at most once and cannot be declined while it is being paid.
+func (store *SQLStore) AcceptPaymentRequestTx(ctx context.Context, arg AcceptPaymentRequestTxParams) (AcceptPaymentRequestTxResult, error) {
+	var result AcceptPaymentRequestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		request, err := q.GetPaymentRequestForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if request.Status != PaymentRequestPending {
+			return ErrPaymentRequestAnswered
+		}
+
+		result.Transfer, err = transferTx(ctx, q, TransferTxParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   request.ToAccountID,
+			Amount:        request.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.PaymentRequest, err = q.MarkPaymentRequestAccepted(ctx, MarkPaymentRequestAcceptedParams{
+			TransferID: sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true},
+			ID:         arg.ID,
+		})
+		return err
+	})
+	if err == nil {
+		store.publishTransfers(ctx, result.Transfer)
+	}
+
+	return result, err
+}
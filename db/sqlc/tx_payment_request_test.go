@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPaymentRequest(t *testing.T, to, from Account) PaymentRequest {
+	request, err := testStore.CreatePaymentRequest(context.Background(), CreatePaymentRequestParams{
+		Requester:   to.Owner,
+		Payer:       from.Owner,
+		ToAccountID: to.ID,
+		Amount:      10,
+		Note:        "dinner",
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestPending, request.Status)
+	return request
+}
+
+func TestAcceptPaymentRequestTx(t *testing.T) {
+	to := createRandomAccount(t)
+	from := createRandomAccount(t)
+	request := createRandomPaymentRequest(t, to, from)
+
+	result, err := testStore.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		ID:            request.ID,
+		FromAccountID: from.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestAccepted, result.PaymentRequest.Status)
+	require.Equal(t, sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true}, result.PaymentRequest.TransferID)
+	require.True(t, result.PaymentRequest.RespondedAt.Valid)
+	require.Equal(t, from.Balance-10, result.Transfer.FromAccount.Balance)
+	require.Equal(t, to.Balance+10, result.Transfer.ToAccount.Balance)
+
+	// A request is paid once, and can't be declined afterwards.
+	_, err = testStore.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		ID:            request.ID,
+		FromAccountID: from.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestAnswered)
+	_, err = testStore.DeclinePaymentRequest(context.Background(), request.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestDeclinePaymentRequest(t *testing.T) {
+	to := createRandomAccount(t)
+	from := createRandomAccount(t)
+	request := createRandomPaymentRequest(t, to, from)
+
+	declined, err := testStore.DeclinePaymentRequest(context.Background(), request.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestDeclined, declined.Status)
+
+	_, err = testStore.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		ID:            request.ID,
+		FromAccountID: from.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestAnswered)
+
+	account, err := testStore.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance, account.Balance)
+}
+
+func TestListPaymentRequests(t *testing.T) {
+	to := createRandomAccount(t)
+	from := createRandomAccount(t)
+	request := createRandomPaymentRequest(t, to, from)
+
+	incoming, err := testStore.ListIncomingPaymentRequests(context.Background(), ListIncomingPaymentRequestsParams{
+		Payer:     from.Owner,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, incoming, 1)
+	require.Equal(t, request.ID, incoming[0].ID)
+
+	outgoing, err := testStore.ListOutgoingPaymentRequests(context.Background(), ListOutgoingPaymentRequestsParams{
+		Requester: to.Owner,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, outgoing, 1)
+	require.Equal(t, request.ID, outgoing[0].ID)
+}
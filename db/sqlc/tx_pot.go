@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// EntryKindPot marks entries for money set aside in, or taken back from, a
+// pot of the account.
+const EntryKindPot = "pot"
+
+var (
+	ErrPotMoveToSelf = errors.New("money must move between two different pots")
+	ErrPotsNotEmpty  = errors.New("account has money in pots, move it back to the balance first")
+)
+
+type MovePotMoneyTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Zero stands for the balance of the account itself.
+	FromPotID int64 `json:"from_pot_id"`
+	ToPotID   int64 `json:"to_pot_id"`
+	Amount    int64 `json:"amount"`
+}
+
+type MovePotMoneyTxResult struct {
+	Account Account `json:"account"`
+	FromPot *Pot    `json:"from_pot,omitempty"`
+	ToPot   *Pot    `json:"to_pot,omitempty"`
+	// Set when the balance of the account changed.
+	Entry *Entry  `json:"entry,omitempty"`
+	Move  PotMove `json:"-"`
+}
+
+// MovePotMoneyTx moves money between the balance of an account and its pots,
+// or between two pots. The money never leaves the account: every change of
+// its balance gets an entry, and every move is recorded. The account row is
+// locked first, so moves on the same account are serialized.
+func (store *SQLStore) MovePotMoneyTx(ctx context.Context, arg MovePotMoneyTxParams) (MovePotMoneyTxResult, error) {
+	var result MovePotMoneyTxResult
+
+	if arg.FromPotID == arg.ToPotID {
+		return result, ErrPotMoveToSelf
+	}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := checkPeriodOpen(ctx, q); err != nil {
+			return err
+		}
+
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if err := checkAccountsActive(account); err != nil {
+			return err
+		}
+		result.Account = account
+
+		if arg.FromPotID != 0 {
+			result.FromPot, err = movePotBalance(ctx, q, arg.AccountID, arg.FromPotID, -arg.Amount)
+		} else {
+			result.Entry, err = moveAccountBalance(ctx, q, &result.Account, -arg.Amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		if arg.ToPotID != 0 {
+			result.ToPot, err = movePotBalance(ctx, q, arg.AccountID, arg.ToPotID, arg.Amount)
+		} else {
+			result.Entry, err = moveAccountBalance(ctx, q, &result.Account, arg.Amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Move, err = q.CreatePotMove(ctx, CreatePotMoveParams{
+			AccountID: arg.AccountID,
+			FromPotID: sql.NullInt64{Int64: arg.FromPotID, Valid: arg.FromPotID != 0},
+			ToPotID:   sql.NullInt64{Int64: arg.ToPotID, Valid: arg.ToPotID != 0},
+			Amount:    arg.Amount,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+func movePotBalance(ctx context.Context, q *Queries, accountID, potID, amount int64) (*Pot, error) {
+	pot, err := q.GetPotForUpdate(ctx, GetPotForUpdateParams{ID: potID, AccountID: accountID})
+	if err != nil {
+		return nil, err
+	}
+	if pot.Balance+amount < 0 {
+		return nil, ErrInsufficientFunds
+	}
+
+	pot, err = q.AddPotBalance(ctx, AddPotBalanceParams{ID: potID, Amount: amount})
+	return &pot, err
+}
+
+// moveAccountBalance changes the balance of a locked account by amount and
+// records the entry. Held money counts as spent, and pots aren't funded from
+// the overdraft, so money only goes into a pot out of the available balance.
+func moveAccountBalance(ctx context.Context, q *Queries, account *Account, amount int64) (*Entry, error) {
+	if account.AvailableBalance()+amount < 0 {
+		return nil, ErrInsufficientFunds
+	}
+
+	var err error
+	*account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     account.ID,
+		Amount: amount,
+	})
+	if err != nil {
+		return nil, balanceViolation(err)
+	}
+
+	entry, err := q.CreateEntryOfKind(ctx, CreateEntryOfKindParams{
+		AccountID:    account.ID,
+		Amount:       amount,
+		Kind:         EntryKindPot,
+		BalanceAfter: account.Balance,
+	})
+	return &entry, err
+}
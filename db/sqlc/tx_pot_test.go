@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovePotMoneyTx(t *testing.T) {
+	account, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Balance:  100,
+		Currency: "USD",
+	})
+	require.NoError(t, err)
+	holiday, err := testStore.CreatePot(context.Background(), CreatePotParams{AccountID: account.ID, Name: "holiday"})
+	require.NoError(t, err)
+	require.Zero(t, holiday.Balance)
+	rainyDay, err := testStore.CreatePot(context.Background(), CreatePotParams{AccountID: account.ID, Name: "rainy day"})
+	require.NoError(t, err)
+
+	_, err = testStore.CreatePot(context.Background(), CreatePotParams{AccountID: account.ID, Name: "holiday"})
+	require.Error(t, err)
+
+	// Into a pot: the balance goes down and an entry records it.
+	result, err := testStore.MovePotMoneyTx(context.Background(), MovePotMoneyTxParams{
+		AccountID: account.ID,
+		ToPotID:   holiday.ID,
+		Amount:    account.Balance,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Account.Balance)
+	require.Equal(t, account.Balance, result.ToPot.Balance)
+	require.NotNil(t, result.Entry)
+	require.Equal(t, -account.Balance, result.Entry.Amount)
+	require.Equal(t, EntryKindPot, result.Entry.Kind)
+
+	_, err = testStore.MovePotMoneyTx(context.Background(), MovePotMoneyTxParams{
+		AccountID: account.ID,
+		ToPotID:   holiday.ID,
+		Amount:    1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// Between pots: the account is left alone.
+	result, err = testStore.MovePotMoneyTx(context.Background(), MovePotMoneyTxParams{
+		AccountID: account.ID,
+		FromPotID: holiday.ID,
+		ToPotID:   rainyDay.ID,
+		Amount:    1,
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Entry)
+	require.Zero(t, result.Account.Balance)
+	require.Equal(t, account.Balance-1, result.FromPot.Balance)
+	require.Equal(t, int64(1), result.ToPot.Balance)
+
+	total, err := testStore.SumPotBalances(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, total)
+
+	// Money in pots keeps the account open.
+	_, err = testStore.CloseAccountTx(context.Background(), CloseAccountTxParams{AccountID: account.ID, ClosedBy: account.Owner})
+	require.ErrorIs(t, err, ErrPotsNotEmpty)
+
+	// Out of a pot, back to the balance.
+	result, err = testStore.MovePotMoneyTx(context.Background(), MovePotMoneyTxParams{
+		AccountID: account.ID,
+		FromPotID: rainyDay.ID,
+		Amount:    1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Account.Balance)
+	require.Zero(t, result.FromPot.Balance)
+
+	// Pots of another account can't be used.
+	other := createRandomAccount(t)
+	_, err = testStore.MovePotMoneyTx(context.Background(), MovePotMoneyTxParams{
+		AccountID: other.ID,
+		ToPotID:   holiday.ID,
+		Amount:    1,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = testStore.MovePotMoneyTx(context.Background(), MovePotMoneyTxParams{
+		AccountID: account.ID,
+		FromPotID: holiday.ID,
+		ToPotID:   holiday.ID,
+		Amount:    1,
+	})
+	require.ErrorIs(t, err, ErrPotMoveToSelf)
+}
+
+func TestMovePotMoneyTxLeavesHeldMoney(t *testing.T) {
+	account := createRandomAccount(t)
+	pot, err := testStore.CreatePot(context.Background(), CreatePotParams{AccountID: account.ID, Name: "savings"})
+	require.NoError(t, err)
+
+	_, err = testStore.PlaceHoldTx(context.Background(), PlaceHoldTxParams{AccountID: account.ID, Amount: account.Balance - 10})
+	require.NoError(t, err)
+
+	_, err = testStore.MovePotMoneyTx(context.Background(), MovePotMoneyTxParams{
+		AccountID: account.ID,
+		ToPotID:   pot.ID,
+		Amount:    11,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	result, err := testStore.MovePotMoneyTx(context.Background(), MovePotMoneyTxParams{
+		AccountID: account.ID,
+		ToPotID:   pot.ID,
+		Amount:    10,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Account.AvailableBalance())
+}
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Data sets that retention rules can be written for.
+const (
+	RetentionLoginEvents  = "login_events"
+	RetentionSessions     = "sessions"
+	RetentionKycDocuments = "kyc_documents"
+)
+
+// What a retention rule does to the rows past their retention period.
+const (
+	RetentionPurge     = "purge"
+	RetentionAnonymize = "anonymize"
+)
+
+var ErrUnknownRetentionTarget = errors.New("unknown retention target")
+
+// errRetentionDryRun rolls back a dry run once every rule has been applied.
+var errRetentionDryRun = errors.New("retention dry run")
+
+// retentionTarget removes or anonymizes the rows of one data set that are
+// older than the cutoff and reports how many rows it touched.
+type retentionTarget struct {
+	action string
+	apply  func(ctx context.Context, q *Queries, cutoff time.Time) (int64, error)
+}
+
+// retentionTargets lists every data set the purge job knows how to clean up.
+// New personal data must be registered here before a rule can cover it.
+var retentionTargets = map[string]retentionTarget{
+	RetentionLoginEvents: {
+		action: RetentionPurge,
+		apply: func(ctx context.Context, q *Queries, cutoff time.Time) (int64, error) {
+			return q.DeleteLoginEventsBefore(ctx, cutoff)
+		},
+	},
+	RetentionSessions: {
+		action: RetentionPurge,
+		apply: func(ctx context.Context, q *Queries, cutoff time.Time) (int64, error) {
+			return q.DeleteExpiredSessionsBefore(ctx, cutoff)
+		},
+	},
+	RetentionKycDocuments: {
+		action: RetentionAnonymize,
+		apply: func(ctx context.Context, q *Queries, cutoff time.Time) (int64, error) {
+			return q.AnonymizeKycDocumentsBefore(ctx, cutoff)
+		},
+	},
+}
+
+// IsRetentionTarget reports whether rules can be written for the data set.
+func IsRetentionTarget(target string) bool {
+	_, ok := retentionTargets[target]
+	return ok
+}
+
+type PurgeRetentionTxParams struct {
+	// Now is the reference time cutoffs are computed from.
+	Now    time.Time `json:"now"`
+	DryRun bool      `json:"dry_run"`
+	RunBy  string    `json:"run_by"`
+}
+
+// RetentionReport lists what a run removed, or would have removed for a dry
+// run, per rule.
+type RetentionReport struct {
+	Run   RetentionRun       `json:"run"`
+	Items []RetentionRunItem `json:"items"`
+}
+
+// PurgeRetentionTx applies every enabled retention rule in one transaction
+// and records the run. A dry run executes the same statements and then rolls
+// them back, so its counts match what a real run would remove; only the run
+// record is kept.
+func (store *SQLStore) PurgeRetentionTx(ctx context.Context, arg PurgeRetentionTxParams) (RetentionReport, error) {
+	var report RetentionReport
+	var items []CreateRetentionRunItemParams
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		rules, err := q.ListRetentionRules(ctx)
+		if err != nil {
+			return err
+		}
+
+		items = items[:0]
+		for _, rule := range rules {
+			if !rule.Enabled {
+				continue
+			}
+			target, ok := retentionTargets[rule.Target]
+			if !ok {
+				return ErrUnknownRetentionTarget
+			}
+
+			cutoff := arg.Now.AddDate(0, 0, -int(rule.RetainDays))
+			affected, err := target.apply(ctx, q, cutoff)
+			if err != nil {
+				return err
+			}
+			items = append(items, CreateRetentionRunItemParams{
+				Target:   rule.Target,
+				Action:   target.action,
+				Cutoff:   cutoff,
+				Affected: affected,
+			})
+		}
+
+		if arg.DryRun {
+			return errRetentionDryRun
+		}
+		report, err = recordRetentionRun(ctx, q, arg, items)
+		return err
+	})
+	if !errors.Is(err, errRetentionDryRun) {
+		return report, err
+	}
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		var err error
+		report, err = recordRetentionRun(ctx, q, arg, items)
+		return err
+	})
+	return report, err
+}
+
+func recordRetentionRun(ctx context.Context, q *Queries, arg PurgeRetentionTxParams, items []CreateRetentionRunItemParams) (RetentionReport, error) {
+	var report RetentionReport
+
+	var err error
+	report.Run, err = q.CreateRetentionRun(ctx, CreateRetentionRunParams{
+		DryRun: arg.DryRun,
+		RunBy:  arg.RunBy,
+	})
+	if err != nil {
+		return report, err
+	}
+
+	report.Items = []RetentionRunItem{}
+	for _, item := range items {
+		item.RunID = report.Run.ID
+		row, err := q.CreateRetentionRunItem(ctx, item)
+		if err != nil {
+			return report, err
+		}
+		report.Items = append(report.Items, row)
+	}
+	return report, nil
+}
+
+// GetRetentionReport loads a recorded run with its per-rule results.
+func (store *SQLStore) GetRetentionReport(ctx context.Context, runID int64) (RetentionReport, error) {
+	var report RetentionReport
+
+	var err error
+	report.Run, err = store.GetRetentionRun(ctx, runID)
+	if err != nil {
+		return report, err
+	}
+
+	report.Items, err = store.ListRetentionRunItems(ctx, runID)
+	return report, err
+}
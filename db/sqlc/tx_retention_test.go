@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func findRetentionItem(t *testing.T, report RetentionReport, target string) RetentionRunItem {
+	for _, item := range report.Items {
+		if item.Target == target {
+			return item
+		}
+	}
+	t.Fatalf("no report item for %s", target)
+	return RetentionRunItem{}
+}
+
+func TestPurgeRetentionTx(t *testing.T) {
+	user := createRandomUser(t)
+	_, err := testStore.CreateLoginEvent(context.Background(), CreateLoginEventParams{
+		Username:  user.Username,
+		UserAgent: "test",
+		ClientIp:  "127.0.0.1",
+	})
+	require.NoError(t, err)
+
+	// Two years from now every login event is past the one year rule.
+	now := time.Now().AddDate(2, 0, 0)
+
+	dryRun, err := testStore.PurgeRetentionTx(context.Background(), PurgeRetentionTxParams{
+		Now:    now,
+		DryRun: true,
+		RunBy:  user.Username,
+	})
+	require.NoError(t, err)
+	require.True(t, dryRun.Run.DryRun)
+	item := findRetentionItem(t, dryRun, RetentionLoginEvents)
+	require.Equal(t, RetentionPurge, item.Action)
+	require.Positive(t, item.Affected)
+
+	// The dry run removed nothing, so the real run finds the same rows.
+	run, err := testStore.PurgeRetentionTx(context.Background(), PurgeRetentionTxParams{
+		Now:   now,
+		RunBy: user.Username,
+	})
+	require.NoError(t, err)
+	require.False(t, run.Run.DryRun)
+	require.GreaterOrEqual(t, findRetentionItem(t, run, RetentionLoginEvents).Affected, item.Affected)
+
+	again, err := testStore.PurgeRetentionTx(context.Background(), PurgeRetentionTxParams{
+		Now:    now,
+		DryRun: true,
+		RunBy:  user.Username,
+	})
+	require.NoError(t, err)
+	require.Zero(t, findRetentionItem(t, again, RetentionLoginEvents).Affected)
+
+	stored, err := testStore.GetRetentionReport(context.Background(), run.Run.ID)
+	require.NoError(t, err)
+	require.Equal(t, run.Items, stored.Items)
+}
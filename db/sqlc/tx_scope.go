@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// txScope is the transaction InTx runs its function in. Store methods called
+// with the context of the function run in it: queries through scopedDB, and
+// the transactions of execTx as savepoints of it. It is a DBTX itself, so
+// that it knows whether a statement has run.
+type txScope struct {
+	store *SQLStore
+	db    DBTX
+	q     *Queries
+	level sql.IsolationLevel
+	// used is set once a statement has run. The isolation level can't change
+	// after that.
+	used bool
+	// afterCommit runs once the transaction has committed, e.g. to publish
+	// the transfers made in it.
+	afterCommit []func()
+}
+
+type txScopeKey struct{}
+
+// scope returns the transaction InTx runs ctx in, or nil outside InTx.
+func (store *SQLStore) scope(ctx context.Context) *txScope {
+	scope, _ := ctx.Value(txScopeKey{}).(*txScope)
+	if scope == nil || scope.store != store {
+		return nil
+	}
+	return scope
+}
+
+// InTx runs fn in a transaction, committing when it returns nil. The store
+// methods fn calls with the context it is given run in that transaction, so
+// their writes commit or roll back together. InTx called from fn runs in the
+// same transaction. A transaction that conflicts is run again the way execTx
+// does, so fn may be called more than once.
+func (store *SQLStore) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if store.scope(ctx) != nil {
+		return fn(ctx)
+	}
+
+	level := sql.LevelDefault
+	if store.txOptions != nil {
+		level = store.txOptions.Isolation
+	}
+	return store.retryTx(ctx, func(attempt int) error {
+		scope := &txScope{store: store, level: level}
+		err := store.runTx(ctx, attempt, store.txOptions, func(q *Queries) error {
+			scope.db = q.db
+			scope.q = New(scope)
+			return fn(context.WithValue(ctx, txScopeKey{}, scope))
+		})
+		if err != nil {
+			return err
+		}
+		for _, fn := range scope.afterCommit {
+			fn()
+		}
+		return nil
+	})
+}
+
+// runTx runs fn in a savepoint, for execTx called in the scope. A
+// transaction asking for an isolation level of its own gets it only if
+// nothing ran in the scope before it.
+func (scope *txScope) runTx(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	if opts != nil && opts.Isolation != sql.LevelDefault && opts.Isolation != scope.level {
+		if scope.used {
+			return fmt.Errorf("cannot run at %s in a transaction that already ran statements at %s", opts.Isolation, scope.level)
+		}
+		if _, err := scope.ExecContext(ctx, "SET TRANSACTION ISOLATION LEVEL "+strings.ToUpper(opts.Isolation.String())); err != nil {
+			return err
+		}
+		scope.level = opts.Isolation
+	}
+
+	failed, err := inSavepoint(ctx, scope.q, fn)
+	if err != nil {
+		return err
+	}
+	return failed
+}
+
+func (scope *txScope) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	scope.used = true
+	return scope.db.ExecContext(ctx, query, args...)
+}
+
+func (scope *txScope) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	scope.used = true
+	return scope.db.PrepareContext(ctx, query)
+}
+
+func (scope *txScope) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	scope.used = true
+	return scope.db.QueryContext(ctx, query, args...)
+}
+
+func (scope *txScope) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	scope.used = true
+	return scope.db.QueryRowContext(ctx, query, args...)
+}
+
+// scopedDB runs the queries of the store in the transaction of InTx when
+// their context is in one, and on db otherwise.
+type scopedDB struct {
+	store *SQLStore
+	db    DBTX
+}
+
+// unscoped returns what the queries of store run on outside InTx, e.g. the
+// replica router.
+func (store *SQLStore) unscoped() DBTX {
+	return store.Queries.db.(*scopedDB).db
+}
+
+func (scoped *scopedDB) route(ctx context.Context) DBTX {
+	if scope := scoped.store.scope(ctx); scope != nil {
+		return scope
+	}
+	return scoped.db
+}
+
+func (scoped *scopedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return scoped.route(ctx).ExecContext(ctx, query, args...)
+}
+
+func (scoped *scopedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return scoped.route(ctx).PrepareContext(ctx, query)
+}
+
+func (scoped *scopedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return scoped.route(ctx).QueryContext(ctx, query, args...)
+}
+
+func (scoped *scopedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return scoped.route(ctx).QueryRowContext(ctx, query, args...)
+}
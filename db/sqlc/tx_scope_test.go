@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPublisher struct {
+	transfers []TransferTxResult
+}
+
+func (publisher *recordingPublisher) PublishTransfer(result TransferTxResult) {
+	publisher.transfers = append(publisher.transfers, result)
+}
+
+func TestInTx(t *testing.T) {
+	publisher := &recordingPublisher{}
+	store := NewStore(testDB, WithTransferPublisher(publisher))
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	var transfer TransferTxResult
+	err := store.InTx(context.Background(), func(ctx context.Context) error {
+		var err error
+		transfer, err = store.TransferTx(ctx, TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        10,
+		})
+		if err != nil {
+			return err
+		}
+		// Not published before the transaction commits.
+		require.Empty(t, publisher.transfers)
+
+		_, err = store.AddAccountBalance(ctx, AddAccountBalanceParams{ID: account2.ID, Amount: 5})
+		return err
+	})
+	require.NoError(t, err)
+	require.Len(t, publisher.transfers, 1)
+	require.Equal(t, transfer.Transfer.ID, publisher.transfers[0].Transfer.ID)
+
+	updated, err := store.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance+15, updated.Balance)
+}
+
+func TestInTxRollsBack(t *testing.T) {
+	publisher := &recordingPublisher{}
+	store := NewStore(testDB, WithTransferPublisher(publisher))
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	failure := errors.New("failure")
+	var transfer TransferTxResult
+	err := store.InTx(context.Background(), func(ctx context.Context) error {
+		var err error
+		transfer, err = store.TransferTx(ctx, TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        10,
+		})
+		require.NoError(t, err)
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+	require.Empty(t, publisher.transfers)
+
+	_, err = store.GetTransfer(context.Background(), transfer.Transfer.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	unchanged, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, unchanged.Balance)
+}
+
+func TestInTxIsolation(t *testing.T) {
+	store := NewStore(testDB)
+
+	// ClosePeriodTx asks for repeatable read, which the transaction can
+	// still switch to before its first statement.
+	err := store.InTx(context.Background(), func(ctx context.Context) error {
+		return store.(*SQLStore).execTxWith(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead}, func(q *Queries) error {
+			var level string
+			if err := q.db.QueryRowContext(ctx, "SHOW transaction_isolation").Scan(&level); err != nil {
+				return err
+			}
+			require.Equal(t, "repeatable read", level)
+			return nil
+		})
+	})
+	require.NoError(t, err)
+
+	account := createRandomAccount(t)
+	err = store.InTx(context.Background(), func(ctx context.Context) error {
+		if _, err := store.GetAccount(ctx, account.ID); err != nil {
+			return err
+		}
+		return store.(*SQLStore).execTxWith(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead}, func(q *Queries) error {
+			return nil
+		})
+	})
+	require.ErrorContains(t, err, "cannot run at Repeatable Read")
+}
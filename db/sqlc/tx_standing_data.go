@@ -0,0 +1,235 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+)
+
+// Entity types whose standing data is versioned in standing_data_changes.
+const (
+	StandingDataUser    = "user"
+	StandingDataAccount = "account"
+)
+
+var (
+	ErrUnknownStandingField   = errors.New("unknown standing data field")
+	ErrStandingDataSuperseded = errors.New("standing data change was superseded by a later change")
+)
+
+// standingField reads (under a row lock) and writes a single tracked column.
+type standingField struct {
+	get func(ctx context.Context, q *Queries, entityID string) (string, error)
+	set func(ctx context.Context, q *Queries, entityID string, value string) error
+}
+
+// standingFields lists every column whose history is kept, keyed by entity type
+// and field name. New standing data must be registered here to be versioned.
+var standingFields = map[string]map[string]standingField{
+	StandingDataUser: {
+		"full_name": {
+			get: func(ctx context.Context, q *Queries, username string) (string, error) {
+				user, err := q.GetUserForUpdate(ctx, username)
+				return user.FullName, err
+			},
+			set: func(ctx context.Context, q *Queries, username string, value string) error {
+				_, err := q.UpdateUserFullName(ctx, UpdateUserFullNameParams{Username: username, FullName: value})
+				return err
+			},
+		},
+		"email": {
+			get: func(ctx context.Context, q *Queries, username string) (string, error) {
+				user, err := q.GetUserForUpdate(ctx, username)
+				return user.Email, err
+			},
+			set: func(ctx context.Context, q *Queries, username string, value string) error {
+				_, err := q.UpdateUserEmail(ctx, UpdateUserEmailParams{Username: username, Email: value})
+				return err
+			},
+		},
+		"phone_number": {
+			get: func(ctx context.Context, q *Queries, username string) (string, error) {
+				user, err := q.GetUserForUpdate(ctx, username)
+				return user.PhoneNumber, err
+			},
+			set: func(ctx context.Context, q *Queries, username string, value string) error {
+				_, err := q.UpdateUserPhoneNumber(ctx, UpdateUserPhoneNumberParams{Username: username, PhoneNumber: value})
+				return err
+			},
+		},
+		"kyc_tier": {
+			get: func(ctx context.Context, q *Queries, username string) (string, error) {
+				user, err := q.GetUserForUpdate(ctx, username)
+				return user.KycTier, err
+			},
+			set: func(ctx context.Context, q *Queries, username string, value string) error {
+				_, err := q.UpdateUserKycTier(ctx, UpdateUserKycTierParams{Username: username, KycTier: value})
+				return err
+			},
+		},
+		"status": {
+			get: func(ctx context.Context, q *Queries, username string) (string, error) {
+				user, err := q.GetUserForUpdate(ctx, username)
+				return user.Status, err
+			},
+			set: func(ctx context.Context, q *Queries, username string, value string) error {
+				_, err := q.UpdateUserStatus(ctx, UpdateUserStatusParams{Username: username, Status: value})
+				return err
+			},
+		},
+	},
+	StandingDataAccount: {
+		"status": {
+			get: func(ctx context.Context, q *Queries, entityID string) (string, error) {
+				id, err := strconv.ParseInt(entityID, 10, 64)
+				if err != nil {
+					return "", err
+				}
+				account, err := q.GetAccountForUpdate(ctx, id)
+				return account.Status, err
+			},
+			set: func(ctx context.Context, q *Queries, entityID string, value string) error {
+				id, err := strconv.ParseInt(entityID, 10, 64)
+				if err != nil {
+					return err
+				}
+				account, err := q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{ID: id, Status: value})
+				if err != nil || value != AccountFrozen {
+					return err
+				}
+				return enqueueWebhookEvent(ctx, q, WebhookEventAccountFrozen, account, account.Owner)
+			},
+		},
+		"overdraft_limit": {
+			get: func(ctx context.Context, q *Queries, entityID string) (string, error) {
+				id, err := strconv.ParseInt(entityID, 10, 64)
+				if err != nil {
+					return "", err
+				}
+				account, err := q.GetAccountForUpdate(ctx, id)
+				return strconv.FormatInt(account.OverdraftLimit, 10), err
+			},
+			set: func(ctx context.Context, q *Queries, entityID string, value string) error {
+				id, err := strconv.ParseInt(entityID, 10, 64)
+				if err != nil {
+					return err
+				}
+				limit, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return err
+				}
+				_, err = q.UpdateAccountOverdraftLimit(ctx, UpdateAccountOverdraftLimitParams{ID: id, OverdraftLimit: limit})
+				return err
+			},
+		},
+	},
+}
+
+func lookupStandingField(entityType, field string) (standingField, error) {
+	f, ok := standingFields[entityType][field]
+	if !ok {
+		return standingField{}, ErrUnknownStandingField
+	}
+	return f, nil
+}
+
+// AccountEntityID formats an account ID the way it is stored in standing_data_changes.
+func AccountEntityID(accountID int64) string {
+	return strconv.FormatInt(accountID, 10)
+}
+
+type UpdateStandingDataTxParams struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Field      string `json:"field"`
+	NewValue   string `json:"new_value"`
+	ChangedBy  string `json:"changed_by"`
+}
+
+// UpdateStandingDataTx changes one tracked field and records the old and new
+// values in the same transaction, so the history can never miss an update.
+func (store *SQLStore) UpdateStandingDataTx(ctx context.Context, arg UpdateStandingDataTxParams) (StandingDataChange, error) {
+	var change StandingDataChange
+
+	field, err := lookupStandingField(arg.EntityType, arg.Field)
+	if err != nil {
+		return change, err
+	}
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		var err error
+		change, err = applyStandingDataChange(ctx, q, field, CreateStandingDataChangeParams{
+			EntityType: arg.EntityType,
+			EntityID:   arg.EntityID,
+			Field:      arg.Field,
+			NewValue:   arg.NewValue,
+			ChangedBy:  arg.ChangedBy,
+		})
+		return err
+	})
+
+	return change, err
+}
+
+type RevertStandingDataChangeTxParams struct {
+	ChangeID  int64  `json:"change_id"`
+	ChangedBy string `json:"changed_by"`
+}
+
+// RevertStandingDataChangeTx restores the value a change replaced. The revert is
+// itself recorded as a new change pointing back at the original, so the
+// timeline stays append-only. A change can only be reverted while its value is
+// still current; otherwise later edits would be silently discarded.
+func (store *SQLStore) RevertStandingDataChangeTx(ctx context.Context, arg RevertStandingDataChangeTxParams) (StandingDataChange, error) {
+	var change StandingDataChange
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		original, err := q.GetStandingDataChange(ctx, arg.ChangeID)
+		if err != nil {
+			return err
+		}
+
+		field, err := lookupStandingField(original.EntityType, original.Field)
+		if err != nil {
+			return err
+		}
+
+		current, err := field.get(ctx, q, original.EntityID)
+		if err != nil {
+			return err
+		}
+		if current != original.NewValue {
+			return ErrStandingDataSuperseded
+		}
+
+		change, err = applyStandingDataChange(ctx, q, field, CreateStandingDataChangeParams{
+			EntityType:      original.EntityType,
+			EntityID:        original.EntityID,
+			Field:           original.Field,
+			NewValue:        original.OldValue,
+			ChangedBy:       arg.ChangedBy,
+			RevertsChangeID: sql.NullInt64{Int64: original.ID, Valid: true},
+		})
+		return err
+	})
+
+	return change, err
+}
+
+// applyStandingDataChange locks the entity, captures the current value as
+// OldValue, writes NewValue and appends the history row.
+func applyStandingDataChange(ctx context.Context, q *Queries, field standingField, arg CreateStandingDataChangeParams) (StandingDataChange, error) {
+	oldValue, err := field.get(ctx, q, arg.EntityID)
+	if err != nil {
+		return StandingDataChange{}, err
+	}
+
+	err = field.set(ctx, q, arg.EntityID, arg.NewValue)
+	if err != nil {
+		return StandingDataChange{}, err
+	}
+
+	arg.OldValue = oldValue
+	return q.CreateStandingDataChange(ctx, arg)
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateAndRevertStandingDataTx(t *testing.T) {
+	user := createRandomUser(t)
+	admin := createRandomUser(t)
+	newEmail := util.RandomEmail()
+
+	change, err := testStore.UpdateStandingDataTx(context.Background(), UpdateStandingDataTxParams{
+		EntityType: StandingDataUser,
+		EntityID:   user.Username,
+		Field:      "email",
+		NewValue:   newEmail,
+		ChangedBy:  admin.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.Email, change.OldValue)
+	require.Equal(t, newEmail, change.NewValue)
+	require.False(t, change.RevertsChangeID.Valid)
+
+	updated, err := testStore.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, newEmail, updated.Email)
+
+	revert, err := testStore.RevertStandingDataChangeTx(context.Background(), RevertStandingDataChangeTxParams{
+		ChangeID:  change.ID,
+		ChangedBy: admin.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, newEmail, revert.OldValue)
+	require.Equal(t, user.Email, revert.NewValue)
+	require.Equal(t, change.ID, revert.RevertsChangeID.Int64)
+
+	reverted, err := testStore.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, user.Email, reverted.Email)
+
+	// The original change is no longer current, so it can't be reverted twice.
+	_, err = testStore.RevertStandingDataChangeTx(context.Background(), RevertStandingDataChangeTxParams{
+		ChangeID:  change.ID,
+		ChangedBy: admin.Username,
+	})
+	require.ErrorIs(t, err, ErrStandingDataSuperseded)
+
+	history, err := testStore.ListStandingDataChanges(context.Background(), ListStandingDataChangesParams{
+		EntityType: StandingDataUser,
+		EntityID:   user.Username,
+		Limit:      10,
+		Offset:     0,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, revert.ID, history[0].ID)
+}
+
+func TestUpdateStandingDataTxUnknownField(t *testing.T) {
+	user := createRandomUser(t)
+
+	_, err := testStore.UpdateStandingDataTx(context.Background(), UpdateStandingDataTxParams{
+		EntityType: StandingDataUser,
+		EntityID:   user.Username,
+		Field:      "hashed_password",
+		NewValue:   "secret",
+		ChangedBy:  user.Username,
+	})
+	require.ErrorIs(t, err, ErrUnknownStandingField)
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+
+// transferLimitWindow is the rolling window max_daily_total applies to.
+const transferLimitWindow = 24 * time.Hour
+
+// checkTransferLimit enforces the sender's transfer_limits for the currency
+// of the source account. Users without a row have no limit. The sender's user
+// row is locked while the window is summed, so concurrent transfers cannot
+// both fit under the daily total.
+func checkTransferLimit(ctx context.Context, q *Queries, fromAccountID int64, amount int64) error {
+	account, err := q.GetAccount(ctx, fromAccountID)
+	if err != nil {
+		return err
+	}
+
+	limit, err := q.GetTransferLimit(ctx, GetTransferLimitParams{
+		Username: account.Owner,
+		Currency: account.Currency,
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if limit.MaxSingleTransfer > 0 && amount > limit.MaxSingleTransfer {
+		return fmt.Errorf("%w: a single transfer may not exceed %d %s", ErrTransferLimitExceeded, limit.MaxSingleTransfer, limit.Currency)
+	}
+	if limit.MaxDailyTotal == 0 {
+		return nil
+	}
+
+	if _, err := q.GetUserForUpdate(ctx, account.Owner); err != nil {
+		return err
+	}
+	sent, err := q.SumTransfersSince(ctx, SumTransfersSinceParams{
+		FromAccountID: fromAccountID,
+		Since:         time.Now().Add(-transferLimitWindow),
+	})
+	if err != nil {
+		return err
+	}
+	if sent+amount > limit.MaxDailyTotal {
+		return fmt.Errorf("%w: transfers in 24 hours may not exceed %d %s, %d already sent", ErrTransferLimitExceeded, limit.MaxDailyTotal, limit.Currency, sent)
+	}
+	return nil
+}
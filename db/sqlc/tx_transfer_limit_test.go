@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxLimits(t *testing.T) {
+	from := createRandomAccount(t)
+	to := createRandomAccount(t)
+
+	_, err := testStore.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Username:          from.Owner,
+		Currency:          from.Currency,
+		MaxSingleTransfer: 60,
+		MaxDailyTotal:     100,
+		UpdatedBy:         "admin",
+	})
+	require.NoError(t, err)
+
+	transfer := func(amount int64) error {
+		_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        amount,
+		})
+		return err
+	}
+
+	require.ErrorIs(t, transfer(61), ErrTransferLimitExceeded)
+	require.NoError(t, transfer(60))
+	require.ErrorIs(t, transfer(41), ErrTransferLimitExceeded)
+	require.NoError(t, transfer(40))
+
+	limits, err := testStore.ListTransferLimits(context.Background(), from.Owner)
+	require.NoError(t, err)
+	require.Len(t, limits, 1)
+	require.Equal(t, int64(100), limits[0].UsedLast24h)
+
+	// The recipient has no limits of its own.
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: to.ID,
+		ToAccountID:   from.ID,
+		Amount:        500,
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Statuses of a transfer. Transfers made in one go are created completed;
+// asynchronous flows create them pending and complete or fail them later.
+const (
+	TransferPending   = "pending"
+	TransferCompleted = "completed"
+	TransferFailed    = "failed"
+)
+
+var ErrTransferStatusTransition = errors.New("transfer can't move to that status")
+
+// transferStatusTransitions lists the statuses each status may move to.
+// Completed and failed are final.
+var transferStatusTransitions = map[string][]string{
+	TransferPending: {TransferCompleted, TransferFailed},
+}
+
+// CanTransitionTransfer returns true if a transfer in status from may move to
+// status to.
+func CanTransitionTransfer(from, to string) bool {
+	return slices.Contains(transferStatusTransitions[from], to)
+}
+
+type UpdateTransferStatusTxParams struct {
+	TransferID int64  `json:"transfer_id"`
+	Status     string `json:"status"`
+	// Optional: why, e.g. why the transfer failed.
+	Reason string `json:"reason"`
+}
+
+// TransferStatusEvent is the data of a transfer.status_changed webhook.
+type TransferStatusEvent struct {
+	Transfer   Transfer `json:"transfer"`
+	FromStatus string   `json:"from_status"`
+	ToStatus   string   `json:"to_status"`
+	Reason     string   `json:"reason"`
+}
+
+// UpdateTransferStatusTx moves a transfer to another status and records the
+// change. Completing a pending transfer moves its money, with the same checks
+// as TransferTx, so it fails while the sender can't cover it and the transfer
+// stays pending. Failing one only marks it, as it never moved money, and
+// works whatever state the accounts are in.
+func (store *SQLStore) UpdateTransferStatusTx(ctx context.Context, arg UpdateTransferStatusTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// Locking the transfer keeps two workers from completing it twice.
+		transfer, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		if !CanTransitionTransfer(transfer.Status, arg.Status) {
+			return fmt.Errorf("%w: %s to %s", ErrTransferStatusTransition, transfer.Status, arg.Status)
+		}
+
+		if arg.Status == TransferCompleted {
+			if err := checkPeriodOpen(ctx, q); err != nil {
+				return err
+			}
+			err = moveTransferMoney(ctx, q, &result, transfer.ID, transfer.FromAccountID, transfer.ToAccountID, transfer.Amount)
+		} else {
+			result.Transfer = transfer
+			err = loadTransferAccounts(ctx, q, &result)
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = q.UpdateTransferStatus(ctx, UpdateTransferStatusParams{
+			ID:     transfer.ID,
+			Status: arg.Status,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateTransferStatusChange(ctx, CreateTransferStatusChangeParams{
+			TransferID: transfer.ID,
+			FromStatus: transfer.Status,
+			ToStatus:   arg.Status,
+			Reason:     arg.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		return enqueueWebhookEvent(ctx, q, WebhookEventTransferStatusChanged, TransferStatusEvent{
+			Transfer:   result.Transfer,
+			FromStatus: transfer.Status,
+			ToStatus:   arg.Status,
+			Reason:     arg.Reason,
+		}, result.FromAccount.Owner, result.ToAccount.Owner)
+	})
+	if err == nil && arg.Status == TransferCompleted {
+		store.publishTransfers(ctx, result)
+	}
+
+	return result, err
+}
+
+// loadTransferAccounts fills in both accounts of a transfer that moves no
+// money.
+func loadTransferAccounts(ctx context.Context, q *Queries, result *TransferTxResult) error {
+	var err error
+	result.FromAccount, err = q.GetAccount(ctx, result.Transfer.FromAccountID)
+	if err != nil {
+		return err
+	}
+	result.ToAccount, err = q.GetAccount(ctx, result.Transfer.ToAccountID)
+	return err
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionTransfer(t *testing.T) {
+	require.True(t, CanTransitionTransfer(TransferPending, TransferCompleted))
+	require.True(t, CanTransitionTransfer(TransferPending, TransferFailed))
+	require.False(t, CanTransitionTransfer(TransferCompleted, TransferFailed))
+	require.False(t, CanTransitionTransfer(TransferFailed, TransferCompleted))
+	require.False(t, CanTransitionTransfer(TransferPending, TransferPending))
+}
+
+func TestUpdateTransferStatusTx(t *testing.T) {
+	from := createRandomAccount(t)
+	to := createRandomAccount(t)
+
+	// Pending: the transfer exists but no money moves.
+	pending, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        10,
+		Pending:       true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferPending, pending.Transfer.Status)
+	require.Zero(t, pending.FromEntry.ID)
+	require.Equal(t, from.Balance, pending.FromAccount.Balance)
+	require.Equal(t, to.Balance, pending.ToAccount.Balance)
+
+	completed, err := testStore.UpdateTransferStatusTx(context.Background(), UpdateTransferStatusTxParams{
+		TransferID: pending.Transfer.ID,
+		Status:     TransferCompleted,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferCompleted, completed.Transfer.Status)
+	require.Equal(t, from.Balance-10, completed.FromAccount.Balance)
+	require.Equal(t, to.Balance+10, completed.ToAccount.Balance)
+	require.Equal(t, int64(-10), completed.FromEntry.Amount)
+
+	// Completed is final.
+	_, err = testStore.UpdateTransferStatusTx(context.Background(), UpdateTransferStatusTxParams{
+		TransferID: pending.Transfer.ID,
+		Status:     TransferFailed,
+	})
+	require.ErrorIs(t, err, ErrTransferStatusTransition)
+
+	// Failing moves no money and keeps the reason.
+	other, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        5,
+		Pending:       true,
+	})
+	require.NoError(t, err)
+	failed, err := testStore.UpdateTransferStatusTx(context.Background(), UpdateTransferStatusTxParams{
+		TransferID: other.Transfer.ID,
+		Status:     TransferFailed,
+		Reason:     "screening rejected",
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferFailed, failed.Transfer.Status)
+	require.Equal(t, from.Balance-10, failed.FromAccount.Balance)
+
+	changes, err := testStore.ListTransferStatusChanges(context.Background(), []int64{pending.Transfer.ID, other.Transfer.ID})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, pending.Transfer.ID, changes[0].TransferID)
+	require.Equal(t, TransferPending, changes[0].FromStatus)
+	require.Equal(t, TransferCompleted, changes[0].ToStatus)
+	require.Equal(t, other.Transfer.ID, changes[1].TransferID)
+	require.Equal(t, TransferFailed, changes[1].ToStatus)
+	require.Equal(t, "screening rejected", changes[1].Reason)
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Statuses of a user.
+const (
+	UserActive    = "active"
+	UserSuspended = "suspended"
+)
+
+type UpdateUserTxParams struct {
+	Username string `json:"username"`
+	// Unset fields are left unchanged.
+	FullName       sql.NullString `json:"full_name"`
+	Email          sql.NullString `json:"email"`
+	PhoneNumber    sql.NullString `json:"phone_number"`
+	HashedPassword sql.NullString `json:"hashed_password"`
+	ChangedBy      string         `json:"changed_by"`
+}
+
+// UpdateUserTx applies a partial update of a user. Changes to versioned
+// standing data are recorded in standing_data_changes in the same
+// transaction, as UpdateStandingDataTx does for single fields.
+func (store *SQLStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		current, err := q.GetUserForUpdate(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range []struct {
+			field    string
+			oldValue string
+			newValue sql.NullString
+		}{
+			{"full_name", current.FullName, arg.FullName},
+			{"email", current.Email, arg.Email},
+			{"phone_number", current.PhoneNumber, arg.PhoneNumber},
+		} {
+			if !change.newValue.Valid || change.newValue.String == change.oldValue {
+				continue
+			}
+			_, err = q.CreateStandingDataChange(ctx, CreateStandingDataChangeParams{
+				EntityType: StandingDataUser,
+				EntityID:   arg.Username,
+				Field:      change.field,
+				OldValue:   change.oldValue,
+				NewValue:   change.newValue.String,
+				ChangedBy:  arg.ChangedBy,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		update := UpdateUserParams{
+			FullName:       arg.FullName,
+			Email:          arg.Email,
+			PhoneNumber:    arg.PhoneNumber,
+			HashedPassword: arg.HashedPassword,
+			Username:       arg.Username,
+		}
+		if arg.HashedPassword.Valid {
+			update.PasswordChangedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+		user, err = q.UpdateUser(ctx, update)
+		return err
+	})
+
+	return user, err
+}
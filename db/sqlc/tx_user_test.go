@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateUserTx(t *testing.T) {
+	user := createRandomUser(t)
+	newEmail := util.RandomEmail()
+
+	updated, err := testStore.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		Username:  user.Username,
+		Email:     sql.NullString{String: newEmail, Valid: true},
+		ChangedBy: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, newEmail, updated.Email)
+	require.Equal(t, user.FullName, updated.FullName)
+	require.Equal(t, user.HashedPassword, updated.HashedPassword)
+	require.Equal(t, user.PasswordChangedAt, updated.PasswordChangedAt)
+
+	changes, err := testStore.ListStandingDataChanges(context.Background(), ListStandingDataChangesParams{
+		EntityType: StandingDataUser,
+		EntityID:   user.Username,
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "email", changes[0].Field)
+	require.Equal(t, user.Email, changes[0].OldValue)
+	require.Equal(t, newEmail, changes[0].NewValue)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(8))
+	require.NoError(t, err)
+	updated, err = testStore.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		Username:       user.Username,
+		HashedPassword: sql.NullString{String: hashedPassword, Valid: true},
+		ChangedBy:      user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, updated.HashedPassword)
+	require.True(t, updated.PasswordChangedAt.After(user.PasswordChangedAt))
+	require.Equal(t, newEmail, updated.Email)
+}
+
+func TestUpdateUserTxPhoneNumber(t *testing.T) {
+	user := createRandomUser(t)
+
+	updated, err := testStore.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		Username:    user.Username,
+		PhoneNumber: sql.NullString{String: "+15551234567", Valid: true},
+		ChangedBy:   user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "+15551234567", updated.PhoneNumber)
+	require.Equal(t, user.Email, updated.Email)
+
+	changes, err := testStore.ListStandingDataChanges(context.Background(), ListStandingDataChangesParams{
+		EntityType: StandingDataUser,
+		EntityID:   user.Username,
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "phone_number", changes[0].Field)
+	require.Empty(t, changes[0].OldValue)
+
+	// The change can be reverted like any other standing data.
+	_, err = testStore.RevertStandingDataChangeTx(context.Background(), RevertStandingDataChangeTxParams{
+		ChangeID:  changes[0].ID,
+		ChangedBy: user.Username,
+	})
+	require.NoError(t, err)
+
+	reverted, err := testStore.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, reverted.PhoneNumber)
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// EntryKindWithdrawal marks entries for money taken out of the bank.
+const EntryKindWithdrawal = "withdrawal"
+
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// balanceConstraint is raised by the accounts trigger that refuses debits past
+// the overdraft limit, see migration 36.
+const balanceConstraint = "balance_within_overdraft"
+
+// Available is what can be taken out of the account: its balance not held
+// and the part of its overdraft not used yet.
+func (account Account) Available() int64 {
+	return account.AvailableBalance() + account.OverdraftLimit
+}
+
+// checkOverdraft is called with a debited account as returned by its balance
+// update, which holds the row lock, so concurrent debits can't together go
+// past the limit. Held money counts as spent. House accounts have no limit;
+// the cash account goes negative by design.
+func checkOverdraft(account Account) error {
+	if account.IsHouse || account.AvailableBalance() >= -account.OverdraftLimit {
+		return nil
+	}
+	return fmt.Errorf("%w: account %d", ErrInsufficientFunds, account.ID)
+}
+
+// balanceViolation turns a debit the database refused into ErrInsufficientFunds.
+// Transactions check balances themselves first, so this only happens when a
+// check is missed.
+func balanceViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Constraint == balanceConstraint {
+		return fmt.Errorf("%w: %s", ErrInsufficientFunds, pqErr.Detail)
+	}
+	return err
+}
+
+type WithdrawTxParams struct {
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+}
+
+type WithdrawTxResult struct {
+	Account Account `json:"account"`
+	Entry   Entry   `json:"entry"`
+}
+
+// WithdrawTx debits an account and records the matching negative entry. The
+// balance is checked under a row lock, so concurrent withdrawals can't
+// together take out more than the account holds and its overdraft allows.
+func (store *SQLStore) WithdrawTx(ctx context.Context, arg WithdrawTxParams) (WithdrawTxResult, error) {
+	var result WithdrawTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := checkPeriodOpen(ctx, q); err != nil {
+			return err
+		}
+
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if err := checkAccountsActive(account); err != nil {
+			return err
+		}
+		if account.Available() < arg.Amount {
+			return ErrInsufficientFunds
+		}
+
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     arg.AccountID,
+			Amount: -arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Entry, err = q.CreateEntryOfKind(ctx, CreateEntryOfKindParams{
+			AccountID:    arg.AccountID,
+			Amount:       -arg.Amount,
+			Kind:         EntryKindWithdrawal,
+			BalanceAfter: result.Account.Balance,
+		})
+		return err
+	})
+
+	return result, err
+}
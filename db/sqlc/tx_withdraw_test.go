@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithdrawTx(t *testing.T) {
+	account := createRandomAccount(t)
+
+	result, err := testStore.WithdrawTx(context.Background(), WithdrawTxParams{
+		AccountID: account.ID,
+		Amount:    account.Balance,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Account.Balance)
+	require.Equal(t, account.ID, result.Entry.AccountID)
+	require.Equal(t, -account.Balance, result.Entry.Amount)
+	require.Equal(t, EntryKindWithdrawal, result.Entry.Kind)
+	require.Zero(t, result.Entry.BalanceAfter)
+
+	_, err = testStore.WithdrawTx(context.Background(), WithdrawTxParams{
+		AccountID: account.ID,
+		Amount:    1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	stored, err := testStore.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, stored.Balance)
+}
+
+func TestOverdraft(t *testing.T) {
+	account := createRandomAccount(t)
+	other := createRandomAccount(t)
+
+	account, err := testStore.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account.ID,
+		OverdraftLimit: 100,
+	})
+	require.NoError(t, err)
+
+	result, err := testStore.WithdrawTx(context.Background(), WithdrawTxParams{
+		AccountID: account.ID,
+		Amount:    account.Balance + 60,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-60), result.Account.Balance)
+	require.Equal(t, int64(40), result.Account.Available())
+
+	// A transfer past the limit is rolled back.
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   other.ID,
+		Amount:        41,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	transfer, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   other.ID,
+		Amount:        40,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-100), transfer.FromAccount.Balance)
+
+	stored, err := testStore.GetAccount(context.Background(), other.ID)
+	require.NoError(t, err)
+	require.Equal(t, other.Balance+40, stored.Balance)
+}
+
+func TestBalanceTrigger(t *testing.T) {
+	account := createRandomAccount(t)
+
+	// A debit that skips the balance checks is refused by the database.
+	err := testStore.(*SQLStore).execTx(context.Background(), func(q *Queries) error {
+		_, err := q.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+			ID:     account.ID,
+			Amount: -account.Balance - 1,
+		})
+		return err
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = testStore.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account.ID,
+		OverdraftLimit: 100,
+	})
+	require.NoError(t, err)
+	_, err = testStore.WithdrawTx(context.Background(), WithdrawTxParams{
+		AccountID: account.ID,
+		Amount:    account.Balance + 100,
+	})
+	require.NoError(t, err)
+
+	// Lowering the limit below what is used only stops further debits.
+	_, err = testStore.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account.ID,
+		OverdraftLimit: 0,
+	})
+	require.NoError(t, err)
+	updated, err := testStore.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account.ID,
+		Amount: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-90), updated.Balance)
+}
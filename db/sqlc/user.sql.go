@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -17,7 +18,7 @@ INSERT INTO users (
     email    
 ) VALUES (
     $1, $2, $3, $4
-) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at
+) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, kyc_tier, totp_secret, tenant, phone_number, status, totp_last_step
 `
 
 type CreateUserParams struct {
@@ -42,12 +43,19 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.KycTier,
+		&i.TotpSecret,
+		&i.Tenant,
+		&i.PhoneNumber,
+		&i.Status,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM users
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, kyc_tier, totp_secret, tenant, phone_number, status, totp_last_step FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -63,6 +71,393 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.KycTier,
+		&i.TotpSecret,
+		&i.Tenant,
+		&i.PhoneNumber,
+		&i.Status,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
+
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, kyc_tier, totp_secret, tenant, phone_number, status, totp_last_step FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetUserForUpdate(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserForUpdate, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.KycTier,
+		&i.TotpSecret,
+		&i.Tenant,
+		&i.PhoneNumber,
+		&i.Status,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const rehashUserPassword = `-- name: RehashUserPassword :execrows
+UPDATE users
+SET hashed_password = $1
+WHERE username = $2
+  AND hashed_password = $3
+`
+
+type RehashUserPasswordParams struct {
+	NewHash  string `json:"new_hash"`
+	Username string `json:"username"`
+	OldHash  string `json:"old_hash"`
+}
+
+// Only replaces the hash it was computed from, so a concurrent password change wins
+func (q *Queries) RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, rehashUserPassword, arg.NewHash, arg.Username, arg.OldHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, kyc_tier, totp_secret, tenant, phone_number, status, totp_last_step FROM users
+WHERE ($1::varchar = ''
+    OR username ILIKE $1::varchar
+    OR email ILIKE $1::varchar
+    OR full_name ILIKE $1::varchar)
+  AND ($2::varchar = '' OR status = $2::varchar)
+ORDER BY username
+LIMIT $3::int
+OFFSET $4::int
+`
+
+type SearchUsersParams struct {
+	Search     string `json:"search"`
+	Status     string `json:"status"`
+	PageLimit  int32  `json:"page_limit"`
+	PageOffset int32  `json:"page_offset"`
+}
+
+// Admin lookup. A non-empty search matches the username, email or full name
+// as an ILIKE pattern, a non-empty status only users in it
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers,
+		arg.Search,
+		arg.Status,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.Username,
+			&i.HashedPassword,
+			&i.FullName,
+			&i.Email,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+			&i.Role,
+			&i.KycTier,
+			&i.TotpSecret,
+			&i.Tenant,
+			&i.PhoneNumber,
+			&i.Status,
+			&i.TotpLastStep,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET hashed_password = COALESCE($1, hashed_password),
+    password_changed_at = COALESCE($2, password_changed_at),
+    full_name = COALESCE($3, full_name),
+    email = COALESCE($4, email),
+    phone_number = COALESCE($5, phone_number)
+WHERE username = $6
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, kyc_tier, totp_secret, tenant, phone_number, status, totp_last_step
+`
+
+type UpdateUserParams struct {
+	HashedPassword    sql.NullString `json:"hashed_password"`
+	PasswordChangedAt sql.NullTime   `json:"password_changed_at"`
+	FullName          sql.NullString `json:"full_name"`
+	Email             sql.NullString `json:"email"`
+	PhoneNumber       sql.NullString `json:"phone_number"`
+	Username          string         `json:"username"`
+}
+
+// NULL params leave the column unchanged
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUser,
+		arg.HashedPassword,
+		arg.PasswordChangedAt,
+		arg.FullName,
+		arg.Email,
+		arg.PhoneNumber,
+		arg.Username,
+	)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.KycTier,
+		&i.TotpSecret,
+		&i.Tenant,
+		&i.PhoneNumber,
+		&i.Status,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserEmail = `-- name: UpdateUserEmail :one
+UPDATE users
+SET email = $2
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, kyc_tier, totp_secret, tenant, phone_number, status, totp_last_step
+`
+
+type UpdateUserEmailParams struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserEmail, arg.Username, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.KycTier,
+		&i.TotpSecret,
+		&i.Tenant,
+		&i.PhoneNumber,
+		&i.Status,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserFullName = `-- name: UpdateUserFullName :one
+UPDATE users
+SET full_name = $2
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, kyc_tier, totp_secret, tenant, phone_number, status, totp_last_step
+`
+
+type UpdateUserFullNameParams struct {
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+}
+
+func (q *Queries) UpdateUserFullName(ctx context.Context, arg UpdateUserFullNameParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserFullName, arg.Username, arg.FullName)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.KycTier,
+		&i.TotpSecret,
+		&i.Tenant,
+		&i.PhoneNumber,
+		&i.Status,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserKycTier = `-- name: UpdateUserKycTier :one
+UPDATE users
+SET kyc_tier = $2
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, kyc_tier, totp_secret, tenant, phone_number, status, totp_last_step
+`
+
+type UpdateUserKycTierParams struct {
+	Username string `json:"username"`
+	KycTier  string `json:"kyc_tier"`
+}
+
+func (q *Queries) UpdateUserKycTier(ctx context.Context, arg UpdateUserKycTierParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserKycTier, arg.Username, arg.KycTier)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.KycTier,
+		&i.TotpSecret,
+		&i.Tenant,
+		&i.PhoneNumber,
+		&i.Status,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserPhoneNumber = `-- name: UpdateUserPhoneNumber :one
+UPDATE users
+SET phone_number = $2
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, kyc_tier, totp_secret, tenant, phone_number, status, totp_last_step
+`
+
+type UpdateUserPhoneNumberParams struct {
+	Username    string `json:"username"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+func (q *Queries) UpdateUserPhoneNumber(ctx context.Context, arg UpdateUserPhoneNumberParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserPhoneNumber, arg.Username, arg.PhoneNumber)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.KycTier,
+		&i.TotpSecret,
+		&i.Tenant,
+		&i.PhoneNumber,
+		&i.Status,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE users
+SET status = $2
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, kyc_tier, totp_secret, tenant, phone_number, status, totp_last_step
+`
+
+type UpdateUserStatusParams struct {
+	Username string `json:"username"`
+	Status   string `json:"status"`
+}
+
+func (q *Queries) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.Username, arg.Status)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.KycTier,
+		&i.TotpSecret,
+		&i.Tenant,
+		&i.PhoneNumber,
+		&i.Status,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserTotpSecret = `-- name: UpdateUserTotpSecret :one
+UPDATE users
+SET totp_secret = $2
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, kyc_tier, totp_secret, tenant, phone_number, status, totp_last_step
+`
+
+type UpdateUserTotpSecretParams struct {
+	Username   string `json:"username"`
+	TotpSecret string `json:"totp_secret"`
+}
+
+func (q *Queries) UpdateUserTotpSecret(ctx context.Context, arg UpdateUserTotpSecretParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTotpSecret, arg.Username, arg.TotpSecret)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.KycTier,
+		&i.TotpSecret,
+		&i.Tenant,
+		&i.PhoneNumber,
+		&i.Status,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const useUserTotpStep = `-- name: UseUserTotpStep :execrows
+UPDATE users
+SET totp_last_step = $1
+WHERE username = $2
+  AND totp_last_step < $1
+`
+
+type UseUserTotpStepParams struct {
+	Step     int64  `json:"step"`
+	Username string `json:"username"`
+}
+
+// Only moves forward, so a code is accepted once however many requests race with it
+func (q *Queries) UseUserTotpStep(ctx context.Context, arg UseUserTotpStepParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useUserTotpStep, arg.Step, arg.Username)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
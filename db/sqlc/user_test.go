@@ -9,7 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func createRandomTestUser(t *testing.T) User {
+func createRandomUser(t testing.TB) User {
 
 	hashed_password, err := util.HashPassword(util.RandomString(6))
 	require.NoError(t, err)
@@ -43,7 +43,7 @@ func TestCreateUser(t *testing.T) {
 }
 
 func TestGetUser(t *testing.T) {
-	user1 := createRandomTestUser(t)
+	user1 := createRandomUser(t)
 	user2, err := testStore.GetUser(context.Background(), user1.Username)
 	require.NoError(t, err)
 	require.NotEmpty(t, user2)
@@ -54,4 +54,21 @@ func TestGetUser(t *testing.T) {
 	require.Equal(t, user1.Email, user2.Email)
 	require.WithinDuration(t, user1.PasswordChangedAt, user2.PasswordChangedAt, time.Second)
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
-}
\ No newline at end of file
+}
+func TestUseUserTotpStep(t *testing.T) {
+	user := createRandomUser(t)
+	use := func(step int64) int64 {
+		used, err := testStore.UseUserTotpStep(context.Background(), UseUserTotpStepParams{
+			Username: user.Username,
+			Step:     step,
+		})
+		require.NoError(t, err)
+		return used
+	}
+
+	require.Equal(t, int64(1), use(100))
+	// A step is used once, and the steps before it can't be used after it.
+	require.Zero(t, use(100))
+	require.Zero(t, use(99))
+	require.Equal(t, int64(1), use(101))
+}
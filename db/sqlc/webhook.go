@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Events webhooks can subscribe to.
+const (
+	WebhookEventTransferCreated       = "transfer.created"
+	WebhookEventTransferStatusChanged = "transfer.status_changed"
+	WebhookEventDepositCompleted      = "deposit.completed"
+	WebhookEventAccountFrozen         = "account.frozen"
+)
+
+// Statuses of a webhook delivery.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookEvents lists every event type a subscription may ask for.
+func WebhookEvents() []string {
+	return []string{
+		WebhookEventTransferCreated,
+		WebhookEventTransferStatusChanged,
+		WebhookEventDepositCompleted,
+		WebhookEventAccountFrozen,
+	}
+}
+
+// WebhookPayload is the JSON body POSTed to subscribers.
+type WebhookPayload struct {
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// enqueueWebhookEvent queues a delivery of the event to the subscriptions of
+// every owner. It runs in the transaction that makes the change, so an event
+// is queued if and only if the change commits.
+func enqueueWebhookEvent(ctx context.Context, q *Queries, event string, data interface{}, owners ...string) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(WebhookPayload{
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      raw,
+	})
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(owners))
+	for _, owner := range owners {
+		if seen[owner] {
+			continue
+		}
+		seen[owner] = true
+
+		_, err := q.EnqueueWebhookDeliveries(ctx, EnqueueWebhookDeliveriesParams{
+			EventType: event,
+			Payload:   payload,
+			Owner:     owner,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
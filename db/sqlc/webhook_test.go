@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferEnqueuesWebhookDelivery(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	subscription, err := testStore.CreateWebhookSubscription(context.Background(), CreateWebhookSubscriptionParams{
+		Owner:      account1.Owner,
+		Url:        "https://example.com/hooks",
+		Secret:     "whsec_test",
+		EventTypes: []string{WebhookEventTransferCreated},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{WebhookEventTransferCreated}, subscription.EventTypes)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	deliveries, err := testStore.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		PageLimit:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, WebhookEventTransferCreated, deliveries[0].EventType)
+	require.Equal(t, WebhookDeliveryPending, deliveries[0].Status)
+
+	var payload struct {
+		Event string           `json:"event"`
+		Data  TransferTxResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(deliveries[0].Payload, &payload))
+	require.Equal(t, WebhookEventTransferCreated, payload.Event)
+	require.Equal(t, result.Transfer.ID, payload.Data.Transfer.ID)
+
+	// The claimed delivery carries what the worker needs to sign and send it.
+	now := time.Now().Add(time.Second)
+	claimed, err := testStore.ClaimWebhookDeliveries(context.Background(), ClaimWebhookDeliveriesParams{
+		LockedUntil: now.Add(time.Minute),
+		Now:         now,
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+
+	var found *ClaimWebhookDeliveriesRow
+	for i := range claimed {
+		if claimed[i].ID == deliveries[0].ID {
+			found = &claimed[i]
+		}
+	}
+	require.NotNil(t, found)
+	require.Equal(t, subscription.Url, found.Url)
+	require.Equal(t, subscription.Secret, found.Secret)
+	require.Equal(t, int32(1), found.Attempts)
+
+	// Unsubscribing drops the queued deliveries.
+	rows, err := testStore.DeleteWebhookSubscription(context.Background(), DeleteWebhookSubscriptionParams{
+		ID:    subscription.ID,
+		Owner: account1.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	deliveries, err = testStore.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		PageLimit:      10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
@@ -0,0 +1,134 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// client calls the test server as one user.
+type client struct {
+	t           *testing.T
+	accessToken string
+}
+
+func newClient(t *testing.T) *client {
+	return &client{t: t}
+}
+
+// do sends a JSON request and decodes the JSON response into a generic value
+// so the schema can be asserted independently of the server's Go types.
+func (c *client) do(method, path string, body interface{}, wantStatus int) interface{} {
+	c.t.Helper()
+
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		require.NoError(c.t, err)
+	}
+	return c.send(method, path, "application/json", bytes.NewReader(data), wantStatus)
+}
+
+// upload posts a multipart form with one file field named "file".
+func (c *client) upload(path string, fields map[string]string, file []byte, wantStatus int) interface{} {
+	c.t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		require.NoError(c.t, writer.WriteField(name, value))
+	}
+	part, err := writer.CreateFormFile("file", "document")
+	require.NoError(c.t, err)
+	_, err = part.Write(file)
+	require.NoError(c.t, err)
+	require.NoError(c.t, writer.Close())
+
+	return c.send(http.MethodPost, path, writer.FormDataContentType(), &body, wantStatus)
+}
+
+func (c *client) send(method, path, contentType string, body io.Reader, wantStatus int) interface{} {
+	c.t.Helper()
+
+	request, err := http.NewRequest(method, testServer.URL+path, body)
+	require.NoError(c.t, err)
+	request.Header.Set("Content-Type", contentType)
+	if c.accessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+
+	response, err := testServer.Client().Do(request)
+	require.NoError(c.t, err)
+	defer response.Body.Close()
+
+	var decoded interface{}
+	if response.StatusCode != http.StatusNoContent {
+		require.NoError(c.t, json.NewDecoder(response.Body).Decode(&decoded))
+	}
+	require.Equal(c.t, wantStatus, response.StatusCode, "%s %s: %v", method, path, decoded)
+	return decoded
+}
+
+// Schema kinds, named after JSON types.
+const (
+	kindString = "string"
+	kindNumber = "number"
+	kindBool   = "bool"
+	kindObject = "object"
+	kindArray  = "array"
+)
+
+// requireObject asserts that value is a JSON object with (at least) the
+// given fields of the given kinds, and returns it.
+func requireObject(t *testing.T, value interface{}, schema map[string]string) map[string]interface{} {
+	t.Helper()
+
+	object, ok := value.(map[string]interface{})
+	require.True(t, ok, "expected an object, got %T", value)
+	for field, kind := range schema {
+		fieldValue, ok := object[field]
+		require.True(t, ok, "missing field %q in %v", field, object)
+		requireKind(t, field, fieldValue, kind)
+	}
+	return object
+}
+
+// requireArray asserts that value is a JSON array whose items all match the
+// schema, and returns it.
+func requireArray(t *testing.T, value interface{}, schema map[string]string) []interface{} {
+	t.Helper()
+
+	items, ok := value.([]interface{})
+	require.True(t, ok, "expected an array, got %T", value)
+	for _, item := range items {
+		requireObject(t, item, schema)
+	}
+	return items
+}
+
+func requireKind(t *testing.T, field string, value interface{}, kind string) {
+	t.Helper()
+
+	var ok bool
+	switch kind {
+	case kindString:
+		_, ok = value.(string)
+	case kindNumber:
+		_, ok = value.(float64)
+	case kindBool:
+		_, ok = value.(bool)
+	case kindObject:
+		_, ok = value.(map[string]interface{})
+	case kindArray:
+		_, ok = value.([]interface{})
+	}
+	require.True(t, ok, "field %q should be a %s, got %T", field, kind, value)
+}
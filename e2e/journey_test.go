@@ -0,0 +1,209 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	userSchema = map[string]string{
+		"username":            kindString,
+		"full_name":           kindString,
+		"email":               kindString,
+		"kyc_tier":            kindString,
+		"tenant":              kindString,
+		"password_changed_at": kindString,
+		"created_at":          kindString,
+	}
+	accountSchema = map[string]string{
+		"id":         kindNumber,
+		"owner":      kindString,
+		"balance":    kindNumber,
+		"currency":   kindString,
+		"status":     kindString,
+		"created_at": kindString,
+	}
+	transferSchema = map[string]string{
+		"id":              kindNumber,
+		"from_account_id": kindNumber,
+		"to_account_id":   kindNumber,
+		"amount":          kindNumber,
+		"created_at":      kindString,
+	}
+	entrySchema = map[string]string{
+		"id":         kindNumber,
+		"account_id": kindNumber,
+		"amount":     kindNumber,
+		"kind":       kindString,
+		"created_at": kindString,
+	}
+	transferHistorySchema = map[string]string{
+		"id":              kindNumber,
+		"from_account_id": kindNumber,
+		"to_account_id":   kindNumber,
+		"amount":          kindNumber,
+		"from_currency":   kindString,
+		"to_currency":     kindString,
+		"created_at":      kindString,
+	}
+)
+
+// signUp creates a user and logs them in, returning a client holding the
+// access token.
+func signUp(t *testing.T) (*client, map[string]interface{}) {
+	c := newClient(t)
+	username := util.RandomOwner() + util.RandomString(6)
+	// Strong enough for any password policy the config may set.
+	password := "Str0ng-" + util.RandomString(12)
+
+	created := c.do(http.MethodPost, "/api/users", map[string]interface{}{
+		"username":  username,
+		"password":  password,
+		"full_name": util.RandomOwner(),
+		"email":     util.RandomEmail(),
+	}, http.StatusOK)
+	user := requireObject(t, created, userSchema)
+	require.NotContains(t, user, "hashed_password")
+
+	login := requireObject(t, c.do(http.MethodPost, "/api/users/login", map[string]interface{}{
+		"username": username,
+		"password": password,
+	}, http.StatusOK), map[string]string{
+		"access_token": kindString,
+		"user":         kindObject,
+	})
+	requireObject(t, login["user"], userSchema)
+	c.accessToken = login["access_token"].(string)
+	return c, user
+}
+
+// makeAdmin grants the admin role, which has no API of its own.
+func makeAdmin(t *testing.T, user map[string]interface{}) {
+	_, err := testDB.Exec(`UPDATE users SET role = $1 WHERE username = $2`, util.AdminRole, user["username"])
+	require.NoError(t, err)
+}
+
+// verify raises the user's KYC tier the way a customer would: by uploading a
+// document that an admin then approves.
+func verify(t *testing.T, c *client, admin *client) {
+	// A PNG signature is enough for the content type check.
+	png := append([]byte("\x89PNG\r\n\x1a\n"), []byte(util.RandomString(32))...)
+	doc := requireObject(t, c.upload("/api/kyc/documents", map[string]string{
+		"document_type":  "passport",
+		"requested_tier": util.KYCTierVerified,
+	}, png, http.StatusOK), map[string]string{
+		"id":     kindNumber,
+		"status": kindString,
+	})
+	require.Equal(t, "pending", doc["status"])
+
+	path := fmt.Sprintf("/api/admin/kyc/documents/%d/review", int64(doc["id"].(float64)))
+	review := requireObject(t, admin.do(http.MethodPost, path, map[string]interface{}{
+		"decision": "approve",
+	}, http.StatusOK), map[string]string{
+		"document": kindObject,
+		"user":     kindObject,
+	})
+	require.Equal(t, util.KYCTierVerified, requireObject(t, review["user"], userSchema)["kyc_tier"])
+}
+
+func createAccount(t *testing.T, c *client, currency string) map[string]interface{} {
+	return requireObject(t, c.do(http.MethodPost, "/api/accounts", map[string]interface{}{
+		"currency": currency,
+	}, http.StatusOK), accountSchema)
+}
+
+func accountPath(account map[string]interface{}) string {
+	return fmt.Sprintf("/api/accounts/%d", int64(account["id"].(float64)))
+}
+
+func TestUserJourney(t *testing.T) {
+	alice, aliceUser := signUp(t)
+	bob, _ := signUp(t)
+	admin, adminUser := signUp(t)
+	makeAdmin(t, adminUser)
+
+	// New users start at the basic KYC tier.
+	require.Equal(t, util.KYCTierBasic, aliceUser["kyc_tier"])
+
+	aliceAccount := createAccount(t, alice, util.INR)
+	bobAccount := createAccount(t, bob, util.INR)
+	require.Zero(t, aliceAccount["balance"])
+
+	// Creating a second account in the same currency is refused.
+	alice.do(http.MethodPost, "/api/accounts", map[string]interface{}{"currency": util.INR}, http.StatusConflict)
+
+	deposited := requireObject(t, alice.do(http.MethodPost, accountPath(aliceAccount)+"/deposit", map[string]interface{}{
+		"amount": 1000,
+	}, http.StatusOK), map[string]string{"account": kindObject, "entry": kindObject})
+	require.EqualValues(t, 1000, requireObject(t, deposited["account"], accountSchema)["balance"])
+	require.Equal(t, "deposit", requireObject(t, deposited["entry"], entrySchema)["kind"])
+
+	// Bob can't read Alice's account.
+	bob.do(http.MethodGet, accountPath(aliceAccount), nil, http.StatusUnauthorized)
+
+	// Basic users can't pay other people until they are verified.
+	alice.do(http.MethodPost, "/api/transfers", map[string]interface{}{
+		"from_account_id": aliceAccount["id"],
+		"to_account_id":   bobAccount["id"],
+		"amount":          250,
+	}, http.StatusForbidden)
+	verify(t, alice, admin)
+
+	transfer := requireObject(t, alice.do(http.MethodPost, "/api/transfers", map[string]interface{}{
+		"from_account_id": aliceAccount["id"],
+		"to_account_id":   bobAccount["id"],
+		"amount":          250,
+		"currency":        util.INR,
+	}, http.StatusOK), map[string]string{
+		"transfer":     kindObject,
+		"from_account": kindObject,
+		"to_account":   kindObject,
+		"from_entry":   kindObject,
+		"to_entry":     kindObject,
+	})
+	requireObject(t, transfer["transfer"], transferSchema)
+	require.EqualValues(t, 750, requireObject(t, transfer["from_account"], accountSchema)["balance"])
+
+	// Alice can't move money out of Bob's account.
+	alice.do(http.MethodPost, "/api/transfers", map[string]interface{}{
+		"from_account_id": bobAccount["id"],
+		"to_account_id":   aliceAccount["id"],
+		"amount":          100,
+	}, http.StatusUnauthorized)
+
+	aliceAfter := requireObject(t, alice.do(http.MethodGet, accountPath(aliceAccount), nil, http.StatusOK), accountSchema)
+	require.EqualValues(t, 750, aliceAfter["balance"])
+	bobAfter := requireObject(t, bob.do(http.MethodGet, accountPath(bobAccount), nil, http.StatusOK), accountSchema)
+	require.EqualValues(t, 250, bobAfter["balance"])
+
+	// Both sides see the transfer in their statement.
+	for _, c := range []*client{alice, bob} {
+		page := requireObject(t, c.do(http.MethodGet, "/api/transfers?limit=10", nil, http.StatusOK), map[string]string{"items": kindArray, "page": kindObject})
+		require.NotContains(t, page, "next_cursor")
+		history := requireArray(t, page["items"], transferHistorySchema)
+		require.Len(t, history, 1)
+		require.EqualValues(t, 250, history[0].(map[string]interface{})["amount"])
+	}
+
+	page := requireObject(t, alice.do(http.MethodGet, "/api/accounts?limit=5", nil, http.StatusOK), map[string]string{"items": kindArray, "page": kindObject})
+	accounts := requireArray(t, page["items"], accountSchema)
+	require.Len(t, accounts, 1)
+}
+
+func TestUnauthenticatedJourney(t *testing.T) {
+	c := newClient(t)
+
+	c.do(http.MethodGet, "/api/accounts?limit=5", nil, http.StatusUnauthorized)
+	c.do(http.MethodPost, "/api/transfers", map[string]interface{}{}, http.StatusUnauthorized)
+	c.do(http.MethodPost, "/api/users/login", map[string]interface{}{
+		"username": util.RandomOwner() + util.RandomString(6),
+		"password": "Str0ng-" + util.RandomString(12),
+	}, http.StatusNotFound)
+}
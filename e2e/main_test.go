@@ -0,0 +1,50 @@
+//go:build e2e
+
+// Package e2e boots the full server against a migrated database and walks
+// through complete user journeys over HTTP. Run it with `make e2e`.
+package e2e
+
+import (
+	"database/sql"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ankurdas111111/simplebank/api"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/gin-gonic/gin"
+	_ "github.com/lib/pq"
+)
+
+var (
+	testServer *httptest.Server
+	// testDB is only used for setup the API can't do, such as granting the
+	// admin role.
+	testDB *sql.DB
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+
+	config, err := util.LoadConfig("..")
+	if err != nil {
+		log.Fatal("cannot load config:", err)
+	}
+
+	testDB, err = sql.Open(config.DBdriver, config.DBsource)
+	if err != nil {
+		log.Fatal("cannot connect to db:", err)
+	}
+
+	server, err := api.NewServer(config, db.NewStore(testDB))
+	if err != nil {
+		log.Fatal("cannot create server:", err)
+	}
+
+	testServer = httptest.NewServer(server.Handler())
+	code := m.Run()
+	testServer.Close()
+	os.Exit(code)
+}
@@ -0,0 +1,113 @@
+// Package events fans committed balance changes out to the clients streaming
+// them, within a single server process.
+package events
+
+import (
+	"sync"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+)
+
+// Event types.
+const (
+	BalanceChanged   = "balance.changed"
+	TransferReceived = "transfer.received"
+)
+
+// subscriberBuffer is how many events a slow subscriber may fall behind by
+// before further events to it are dropped.
+const subscriberBuffer = 16
+
+// Event is something that happened to one account.
+type Event struct {
+	Type      string `json:"type"`
+	AccountID int64  `json:"account_id"`
+	// Balance is the account balance after the change.
+	Balance int64 `json:"balance"`
+	// Amount is the signed change to the balance.
+	Amount                int64     `json:"amount"`
+	TransferID            int64     `json:"transfer_id"`
+	CounterpartyAccountID int64     `json:"counterparty_account_id"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+// Broker is an in-process pub/sub keyed by account. Publishing never blocks:
+// a subscriber that does not keep up misses events rather than holding up
+// the transaction that published them.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[chan Event]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[int64]map[chan Event]struct{})}
+}
+
+// Subscribe returns the events of an account and a function that ends the
+// subscription and closes the channel.
+func (broker *Broker) Subscribe(accountID int64) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	broker.mu.Lock()
+	if broker.subscribers[accountID] == nil {
+		broker.subscribers[accountID] = make(map[chan Event]struct{})
+	}
+	broker.subscribers[accountID][ch] = struct{}{}
+	broker.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			broker.mu.Lock()
+			defer broker.mu.Unlock()
+			delete(broker.subscribers[accountID], ch)
+			if len(broker.subscribers[accountID]) == 0 {
+				delete(broker.subscribers, accountID)
+			}
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+// Publish sends the event to every subscriber of its account.
+func (broker *Broker) Publish(event Event) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	for ch := range broker.subscribers[event.AccountID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// PublishTransfer implements db.TransferPublisher. Both accounts see their
+// balance change; the receiving account also sees the incoming transfer.
+func (broker *Broker) PublishTransfer(result db.TransferTxResult) {
+	transfer := result.Transfer
+	broker.Publish(Event{
+		Type:                  BalanceChanged,
+		AccountID:             result.FromAccount.ID,
+		Balance:               result.FromAccount.Balance,
+		Amount:                result.FromEntry.Amount,
+		TransferID:            transfer.ID,
+		CounterpartyAccountID: result.ToAccount.ID,
+		CreatedAt:             transfer.CreatedAt,
+	})
+
+	received := Event{
+		Type:                  BalanceChanged,
+		AccountID:             result.ToAccount.ID,
+		Balance:               result.ToAccount.Balance,
+		Amount:                result.ToEntry.Amount,
+		TransferID:            transfer.ID,
+		CounterpartyAccountID: result.FromAccount.ID,
+		CreatedAt:             transfer.CreatedAt,
+	}
+	broker.Publish(received)
+	received.Type = TransferReceived
+	broker.Publish(received)
+}
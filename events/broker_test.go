@@ -0,0 +1,68 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishTransfer(t *testing.T) {
+	broker := NewBroker()
+	from, cancelFrom := broker.Subscribe(1)
+	defer cancelFrom()
+	to, cancelTo := broker.Subscribe(2)
+	defer cancelTo()
+	other, cancelOther := broker.Subscribe(3)
+	defer cancelOther()
+
+	createdAt := time.Now().UTC()
+	broker.PublishTransfer(db.TransferTxResult{
+		Transfer:    db.Transfer{ID: 9, FromAccountID: 1, ToAccountID: 2, Amount: 30, CreatedAt: createdAt},
+		FromAccount: db.Account{ID: 1, Balance: 70},
+		ToAccount:   db.Account{ID: 2, Balance: 130},
+		FromEntry:   db.Entry{AccountID: 1, Amount: -30},
+		ToEntry:     db.Entry{AccountID: 2, Amount: 30},
+	})
+
+	require.Equal(t, Event{
+		Type:                  BalanceChanged,
+		AccountID:             1,
+		Balance:               70,
+		Amount:                -30,
+		TransferID:            9,
+		CounterpartyAccountID: 2,
+		CreatedAt:             createdAt,
+	}, <-from)
+	require.Empty(t, from)
+
+	changed := <-to
+	require.Equal(t, BalanceChanged, changed.Type)
+	require.Equal(t, int64(130), changed.Balance)
+	require.Equal(t, int64(30), changed.Amount)
+	require.Equal(t, int64(1), changed.CounterpartyAccountID)
+	received := <-to
+	require.Equal(t, TransferReceived, received.Type)
+	require.Equal(t, int64(9), received.TransferID)
+
+	require.Empty(t, other)
+}
+
+func TestSlowSubscriberDropsEvents(t *testing.T) {
+	broker := NewBroker()
+	events, cancel := broker.Subscribe(1)
+
+	for i := 0; i < subscriberBuffer+5; i++ {
+		broker.Publish(Event{Type: BalanceChanged, AccountID: 1, Balance: int64(i)})
+	}
+	require.Len(t, events, subscriberBuffer)
+
+	// Cancelling closes the channel and is safe to repeat.
+	cancel()
+	cancel()
+	for range events {
+	}
+	broker.Publish(Event{Type: BalanceChanged, AccountID: 1})
+	require.Empty(t, broker.subscribers)
+}
@@ -0,0 +1,128 @@
+// Package fx converts amounts between currencies at the rates kept in the
+// fx_rates table. A rate applies from its valid_from until the next rate of
+// its pair, so rates are changed by adding one, not by editing it.
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+)
+
+// ErrNoRate is returned for a pair of currencies no rate converts between.
+var ErrNoRate = errors.New("no exchange rate")
+
+// Source loads the rates in force at a time.
+type Source interface {
+	ListFxRates(ctx context.Context, at time.Time) ([]db.FxRate, error)
+}
+
+// defaultTTL bounds how stale a converter can be when another instance
+// added a rate.
+const defaultTTL = time.Minute
+
+type pair struct {
+	base  string
+	quote string
+}
+
+// Converter caches the current rates and converts with them. It is safe for
+// concurrent use.
+type Converter struct {
+	source Source
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	rates    map[pair]float64
+	loadedAt time.Time
+}
+
+// NewConverter creates a converter reading rates from source.
+func NewConverter(source Source) *Converter {
+	return &Converter{source: source, ttl: defaultTTL, now: time.Now}
+}
+
+// Invalidate forces the next conversion to reload the rates.
+func (converter *Converter) Invalidate() {
+	converter.mu.Lock()
+	converter.loadedAt = time.Time{}
+	converter.mu.Unlock()
+}
+
+func (converter *Converter) snapshot(ctx context.Context) (map[pair]float64, error) {
+	converter.mu.RLock()
+	rates, loadedAt := converter.rates, converter.loadedAt
+	converter.mu.RUnlock()
+	if !loadedAt.IsZero() && time.Since(loadedAt) < converter.ttl {
+		return rates, nil
+	}
+
+	rows, err := converter.source.ListFxRates(ctx, converter.now())
+	if err != nil {
+		return nil, err
+	}
+	rates = make(map[pair]float64, 2*len(rows))
+	for _, row := range rows {
+		rates[pair{row.Base, row.Quote}] = row.Rate
+	}
+	// A pair converts both ways, unless its inverse has a rate of its own.
+	for _, row := range rows {
+		inverse := pair{row.Quote, row.Base}
+		if _, ok := rates[inverse]; !ok {
+			rates[inverse] = 1 / row.Rate
+		}
+	}
+
+	converter.mu.Lock()
+	converter.rates, converter.loadedAt = rates, time.Now()
+	converter.mu.Unlock()
+	return rates, nil
+}
+
+// Rate returns how many units of to one unit of from is worth. Without a
+// rate between them, currencies are converted through a third one both have
+// a rate with, e.g. USD to EUR through INR.
+func (converter *Converter) Rate(ctx context.Context, from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	rates, err := converter.snapshot(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if rate, ok := rates[pair{from, to}]; ok {
+		return rate, nil
+	}
+	// Thirds are tried in order so a conversion always takes the same path.
+	var thirds []string
+	for p := range rates {
+		if p.base == from {
+			if _, ok := rates[pair{p.quote, to}]; ok {
+				thirds = append(thirds, p.quote)
+			}
+		}
+	}
+	if len(thirds) > 0 {
+		sort.Strings(thirds)
+		return rates[pair{from, thirds[0]}] * rates[pair{thirds[0], to}], nil
+	}
+	return 0, fmt.Errorf("%w from %s to %s", ErrNoRate, from, to)
+}
+
+// Convert converts amount, in minor units of from, to minor units of to,
+// rounded to the nearest unit, and returns the rate it used.
+func (converter *Converter) Convert(ctx context.Context, amount int64, from, to string) (int64, float64, error) {
+	rate, err := converter.Rate(ctx, from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int64(math.Round(float64(amount) * rate)), rate, nil
+}
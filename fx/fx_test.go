@@ -0,0 +1,71 @@
+package fx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	rates []db.FxRate
+	loads int
+}
+
+func (source *fakeSource) ListFxRates(ctx context.Context, at time.Time) ([]db.FxRate, error) {
+	source.loads++
+	return source.rates, nil
+}
+
+func TestConvert(t *testing.T) {
+	converter := NewConverter(&fakeSource{rates: []db.FxRate{
+		{Base: util.USD, Quote: util.INR, Rate: 83},
+		{Base: util.EUR, Quote: util.INR, Rate: 90},
+	}})
+
+	testCases := []struct {
+		name      string
+		amount    int64
+		from, to  string
+		converted int64
+	}{
+		{name: "Direct", amount: 100, from: util.USD, to: util.INR, converted: 8300},
+		{name: "Inverse", amount: 9000, from: util.INR, to: util.EUR, converted: 100},
+		{name: "Cross", amount: 9000, from: util.EUR, to: util.USD, converted: 9759},
+		{name: "Same", amount: 42, from: util.USD, to: util.USD, converted: 42},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			converted, _, err := converter.Convert(context.Background(), tc.amount, tc.from, tc.to)
+			require.NoError(t, err)
+			require.Equal(t, tc.converted, converted)
+		})
+	}
+
+	_, _, err := converter.Convert(context.Background(), 100, util.USD, "GBP")
+	require.ErrorIs(t, err, ErrNoRate)
+}
+
+func TestConverterCachesRates(t *testing.T) {
+	source := &fakeSource{rates: []db.FxRate{{Base: util.USD, Quote: util.INR, Rate: 83}}}
+	converter := NewConverter(source)
+
+	for i := 0; i < 3; i++ {
+		_, err := converter.Rate(context.Background(), util.USD, util.INR)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, source.loads)
+
+	source.rates = []db.FxRate{{Base: util.USD, Quote: util.INR, Rate: 84}}
+	converter.Invalidate()
+	rate, err := converter.Rate(context.Background(), util.USD, util.INR)
+	require.NoError(t, err)
+	require.Equal(t, 84.0, rate)
+	require.Equal(t, 2, source.loads)
+}
@@ -0,0 +1,132 @@
+// Package ledger checks that the books agree with themselves on a schedule.
+package ledger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+)
+
+// batchSize is how many mismatches one query of a run reads.
+const batchSize = 100
+
+// Store is the part of db.Store the job needs.
+type Store interface {
+	ListAccountBalanceMismatches(ctx context.Context, arg db.ListAccountBalanceMismatchesParams) ([]db.ListAccountBalanceMismatchesRow, error)
+	ListTransferEntryMismatches(ctx context.Context, arg db.ListTransferEntryMismatchesParams) ([]db.ListTransferEntryMismatchesRow, error)
+}
+
+// Report lists what a run found wrong.
+type Report struct {
+	// Accounts whose balance isn't the sum of their entries.
+	Accounts []db.ListAccountBalanceMismatchesRow `json:"accounts"`
+	// Transfers that didn't book exactly a debit and a matching credit.
+	Transfers []db.ListTransferEntryMismatchesRow `json:"transfers"`
+}
+
+// OK returns true if the run found nothing wrong.
+func (report Report) OK() bool {
+	return len(report.Accounts) == 0 && len(report.Transfers) == 0
+}
+
+// Job recomputes every balance from its entries and checks the entries of
+// every transfer every interval. It only reports mismatches, it never fixes
+// them.
+type Job struct {
+	store    Store
+	interval time.Duration
+}
+
+func NewJob(store Store, interval time.Duration) *Job {
+	return &Job{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// RunOnce checks the whole ledger a single time and logs every mismatch.
+// Each query reads a whole table, so the store's timeouts don't apply.
+func (job *Job) RunOnce(ctx context.Context) (Report, error) {
+	ctx = db.WithoutTimeouts(ctx)
+	var report Report
+	var err error
+
+	report.Accounts, err = job.accountMismatches(ctx)
+	if err != nil {
+		return report, err
+	}
+	for _, account := range report.Accounts {
+		slog.Error("ledger mismatch: account balance doesn't match its entries",
+			"account_id", account.ID, "balance", account.Balance, "currency", account.Currency, "entry_total", account.EntryTotal)
+	}
+
+	report.Transfers, err = job.transferMismatches(ctx)
+	if err != nil {
+		return report, err
+	}
+	for _, transfer := range report.Transfers {
+		slog.Error("ledger mismatch: transfer entries don't balance",
+			"transfer_id", transfer.ID, "status", transfer.Status, "entry_count", transfer.EntryCount, "entry_total", transfer.EntryTotal)
+	}
+	return report, nil
+}
+
+func (job *Job) accountMismatches(ctx context.Context) ([]db.ListAccountBalanceMismatchesRow, error) {
+	var mismatches []db.ListAccountBalanceMismatchesRow
+	var afterID int64
+	for {
+		accounts, err := job.store.ListAccountBalanceMismatches(ctx, db.ListAccountBalanceMismatchesParams{
+			AfterID:   afterID,
+			PageLimit: batchSize,
+		})
+		if err != nil {
+			return mismatches, err
+		}
+		mismatches = append(mismatches, accounts...)
+
+		if len(accounts) < batchSize {
+			return mismatches, nil
+		}
+		afterID = accounts[len(accounts)-1].ID
+	}
+}
+
+func (job *Job) transferMismatches(ctx context.Context) ([]db.ListTransferEntryMismatchesRow, error) {
+	var mismatches []db.ListTransferEntryMismatchesRow
+	var afterID int64
+	for {
+		transfers, err := job.store.ListTransferEntryMismatches(ctx, db.ListTransferEntryMismatchesParams{
+			AfterID:   afterID,
+			PageLimit: batchSize,
+		})
+		if err != nil {
+			return mismatches, err
+		}
+		mismatches = append(mismatches, transfers...)
+
+		if len(transfers) < batchSize {
+			return mismatches, nil
+		}
+		afterID = transfers[len(transfers)-1].ID
+	}
+}
+
+// Start runs the job every interval until ctx is done. Failed runs are
+// logged and retried at the next tick.
+func (job *Job) Start(ctx context.Context) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := job.RunOnce(ctx); err != nil {
+				slog.Error("ledger check failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A full page of accounts makes the job read the next one.
+	fullPage := make([]db.ListAccountBalanceMismatchesRow, batchSize)
+	for i := range fullPage {
+		fullPage[i] = db.ListAccountBalanceMismatchesRow{ID: int64(i + 1), Currency: "USD", Balance: 10, EntryTotal: 0}
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().ListAccountBalanceMismatches(gomock.Any(), gomock.Eq(db.ListAccountBalanceMismatchesParams{
+			AfterID:   0,
+			PageLimit: batchSize,
+		})).
+			Times(1).
+			Return(fullPage, nil),
+		store.EXPECT().ListAccountBalanceMismatches(gomock.Any(), gomock.Eq(db.ListAccountBalanceMismatchesParams{
+			AfterID:   batchSize,
+			PageLimit: batchSize,
+		})).
+			Times(1).
+			Return([]db.ListAccountBalanceMismatchesRow{}, nil),
+	)
+	store.EXPECT().ListTransferEntryMismatches(gomock.Any(), gomock.Eq(db.ListTransferEntryMismatchesParams{
+		AfterID:   0,
+		PageLimit: batchSize,
+	})).
+		Times(1).
+		Return([]db.ListTransferEntryMismatchesRow{
+			{ID: 7, Status: db.TransferCompleted, EntryCount: 1, EntryTotal: -10},
+		}, nil)
+
+	report, err := NewJob(store, time.Hour).RunOnce(context.Background())
+	require.NoError(t, err)
+	require.False(t, report.OK())
+	require.Len(t, report.Accounts, batchSize)
+	require.Len(t, report.Transfers, 1)
+	require.Equal(t, int64(7), report.Transfers[0].ID)
+}
+
+func TestRunOnceConsistent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListAccountBalanceMismatches(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.ListAccountBalanceMismatchesRow{}, nil)
+	store.EXPECT().ListTransferEntryMismatches(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.ListTransferEntryMismatchesRow{}, nil)
+
+	report, err := NewJob(store, time.Hour).RunOnce(context.Background())
+	require.NoError(t, err)
+	require.True(t, report.OK())
+}
+
+func TestRunOnceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListAccountBalanceMismatches(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, errors.New("connection refused"))
+	store.EXPECT().ListTransferEntryMismatches(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	_, err := NewJob(store, time.Hour).RunOnce(context.Background())
+	require.Error(t, err)
+}
+
+func TestStartStopsWithContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListAccountBalanceMismatches(gomock.Any(), gomock.Any()).
+		MinTimes(1).
+		Return([]db.ListAccountBalanceMismatchesRow{}, nil)
+	store.EXPECT().ListTransferEntryMismatches(gomock.Any(), gomock.Any()).
+		MinTimes(1).
+		Return([]db.ListTransferEntryMismatchesRow{}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		NewJob(store, 5*time.Millisecond).Start(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after the context was cancelled")
+	}
+}
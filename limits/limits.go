@@ -0,0 +1,131 @@
+package limits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ankurdas111111/simplebank/settings"
+	"github.com/ankurdas111111/simplebank/util"
+)
+
+// ErrNotAllowed is wrapped by every error returned when a KYC tier does not
+// permit an operation, so callers can map them all to one status code.
+var ErrNotAllowed = errors.New("not allowed for kyc tier")
+
+// Policy describes what a single KYC tier is allowed to do.
+type Policy struct {
+	// MaxTransferINR caps a single transfer, converted to INR. Zero means no cap.
+	MaxTransferINR int64
+	// AllowFX permits transfers between accounts of different currencies.
+	AllowFX bool
+	// AllowExternal permits transfers to accounts owned by another user.
+	AllowExternal bool
+}
+
+// DefaultPolicies apply unless a setting overrides them.
+var DefaultPolicies = map[string]Policy{
+	util.KYCTierBasic: {
+		MaxTransferINR: 10_000,
+		AllowFX:        false,
+		AllowExternal:  false,
+	},
+	util.KYCTierVerified: {
+		MaxTransferINR: 200_000,
+		AllowFX:        true,
+		AllowExternal:  true,
+	},
+	util.KYCTierFull: {
+		MaxTransferINR: 0,
+		AllowFX:        true,
+		AllowExternal:  true,
+	},
+}
+
+// Transfer is the information the engine needs to check a transfer.
+type Transfer struct {
+	Tier     string
+	Tenant   string
+	Amount   int64
+	Currency string
+	// FX is true when the destination account uses another currency.
+	FX bool
+	// External is true when the destination account has another owner.
+	External bool
+}
+
+// Settings resolves per-tenant and per-currency overrides. It is implemented
+// by *settings.Resolver.
+type Settings interface {
+	Int64(ctx context.Context, tenant, currency, key string, def int64) (int64, error)
+	Bool(ctx context.Context, tenant, currency, key string, def bool) (bool, error)
+}
+
+// Rates converts amounts to INR to compare them with the limits. It is
+// implemented by *fx.Converter.
+type Rates interface {
+	Convert(ctx context.Context, amount int64, from, to string) (int64, float64, error)
+}
+
+// Engine checks operations against the policy of the user's KYC tier.
+type Engine struct {
+	settings Settings
+	rates    Rates
+}
+
+// NewEngine creates an engine that layers settings over DefaultPolicies. A nil
+// settings uses the defaults only.
+func NewEngine(settings Settings, rates Rates) *Engine {
+	return &Engine{settings: settings, rates: rates}
+}
+
+// Policy returns the policy of a tier for a tenant and currency.
+func (engine *Engine) Policy(ctx context.Context, tier, tenant, currency string) (Policy, error) {
+	policy, ok := DefaultPolicies[tier]
+	if !ok {
+		return policy, fmt.Errorf("unknown kyc tier %q", tier)
+	}
+	if engine.settings == nil {
+		return policy, nil
+	}
+
+	var err error
+	policy.MaxTransferINR, err = engine.settings.Int64(ctx, tenant, currency, settings.LimitKey(tier, settings.LimitMaxTransferINR), policy.MaxTransferINR)
+	if err != nil {
+		return policy, err
+	}
+	policy.AllowFX, err = engine.settings.Bool(ctx, tenant, currency, settings.LimitKey(tier, settings.LimitAllowFX), policy.AllowFX)
+	if err != nil {
+		return policy, err
+	}
+	policy.AllowExternal, err = engine.settings.Bool(ctx, tenant, currency, settings.LimitKey(tier, settings.LimitAllowExternal), policy.AllowExternal)
+	return policy, err
+}
+
+// CheckTransfer returns an error wrapping ErrNotAllowed if the tier may not
+// make the transfer.
+func (engine *Engine) CheckTransfer(ctx context.Context, transfer Transfer) error {
+	policy, err := engine.Policy(ctx, transfer.Tier, transfer.Tenant, transfer.Currency)
+	if err != nil {
+		return err
+	}
+
+	if transfer.External && !policy.AllowExternal {
+		return fmt.Errorf("%w: transfers to other users require a higher kyc tier than %s", ErrNotAllowed, transfer.Tier)
+	}
+	if transfer.FX && !policy.AllowFX {
+		return fmt.Errorf("%w: cross-currency transfers require a higher kyc tier than %s", ErrNotAllowed, transfer.Tier)
+	}
+
+	if policy.MaxTransferINR > 0 {
+		amountINR, _, err := engine.rates.Convert(ctx, transfer.Amount, transfer.Currency, util.INR)
+		if err != nil {
+			return err
+		}
+		if amountINR > policy.MaxTransferINR {
+			return fmt.Errorf("%w: amount exceeds the %s tier limit of %d INR", ErrNotAllowed, transfer.Tier, policy.MaxTransferINR)
+		}
+	}
+
+	return nil
+}
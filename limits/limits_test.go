@@ -0,0 +1,115 @@
+package limits
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/fx"
+	"github.com/ankurdas111111/simplebank/settings"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTransfer(t *testing.T) {
+	engine := NewEngine(nil, testRates)
+
+	testCases := []struct {
+		name     string
+		transfer Transfer
+		allowed  bool
+	}{
+		{
+			name:     "BasicWithinLimit",
+			transfer: Transfer{Tier: util.KYCTierBasic, Amount: 10_000, Currency: util.INR},
+			allowed:  true,
+		},
+		{
+			name:     "BasicOverLimit",
+			transfer: Transfer{Tier: util.KYCTierBasic, Amount: 10_001, Currency: util.INR},
+		},
+		{
+			name:     "BasicOverLimitAfterConversion",
+			transfer: Transfer{Tier: util.KYCTierBasic, Amount: 200, Currency: util.USD},
+		},
+		{
+			name:     "BasicFX",
+			transfer: Transfer{Tier: util.KYCTierBasic, Amount: 10, Currency: util.INR, FX: true},
+		},
+		{
+			name:     "BasicExternal",
+			transfer: Transfer{Tier: util.KYCTierBasic, Amount: 10, Currency: util.INR, External: true},
+		},
+		{
+			name:     "VerifiedExternalFX",
+			transfer: Transfer{Tier: util.KYCTierVerified, Amount: 1_000, Currency: util.USD, FX: true, External: true},
+			allowed:  true,
+		},
+		{
+			name:     "FullUnlimited",
+			transfer: Transfer{Tier: util.KYCTierFull, Amount: 10_000_000, Currency: util.EUR, External: true},
+			allowed:  true,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := engine.CheckTransfer(context.Background(), tc.transfer)
+			if tc.allowed {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrNotAllowed)
+		})
+	}
+}
+
+func TestCheckTransferUnknownTier(t *testing.T) {
+	err := NewEngine(nil, testRates).CheckTransfer(context.Background(), Transfer{Tier: "gold", Amount: 1, Currency: util.INR})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNotAllowed)
+}
+
+type fixedRates []db.FxRate
+
+func (f fixedRates) ListFxRates(ctx context.Context, at time.Time) ([]db.FxRate, error) {
+	return f, nil
+}
+
+var testRates = fx.NewConverter(fixedRates{
+	{Base: util.USD, Quote: util.INR, Rate: 83},
+	{Base: util.EUR, Quote: util.INR, Rate: 90},
+})
+
+type fakeSettings map[string]string
+
+func (f fakeSettings) Int64(ctx context.Context, tenant, currency, key string, def int64) (int64, error) {
+	if v, ok := f[tenant+"/"+currency+"/"+key]; ok {
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return def, nil
+}
+
+func (f fakeSettings) Bool(ctx context.Context, tenant, currency, key string, def bool) (bool, error) {
+	if v, ok := f[tenant+"/"+currency+"/"+key]; ok {
+		return strconv.ParseBool(v)
+	}
+	return def, nil
+}
+
+func TestCheckTransferOverrides(t *testing.T) {
+	engine := NewEngine(fakeSettings{
+		"branch-a/INR/" + settings.LimitKey(util.KYCTierBasic, settings.LimitMaxTransferINR): "50000",
+		"branch-a/INR/" + settings.LimitKey(util.KYCTierBasic, settings.LimitAllowExternal):  "true",
+	}, testRates)
+
+	transfer := Transfer{Tier: util.KYCTierBasic, Tenant: "branch-a", Amount: 20_000, Currency: util.INR, External: true}
+	require.NoError(t, engine.CheckTransfer(context.Background(), transfer))
+
+	transfer.Tenant = "branch-b"
+	require.ErrorIs(t, engine.CheckTransfer(context.Background(), transfer), ErrNotAllowed)
+}
@@ -0,0 +1,34 @@
+// Package logging builds the structured logger shared by the server, the
+// store and the background jobs. It logs JSON lines, one event per line,
+// through zap. Callers get a *slog.Logger, so the packages that log only
+// depend on the standard library's logging API and not on zap.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/exp/zapslog"
+	"go.uber.org/zap/zapcore"
+)
+
+// New returns a logger writing to w from level up: debug, info, warn or
+// error. An empty level is info.
+func New(w io.Writer, level string) (*slog.Logger, error) {
+	minLevel := zapcore.InfoLevel
+	if level != "" {
+		if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+		}
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "time"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	// INFO, WARN..., the way slog names the levels.
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(w), minLevel)
+	return slog.New(zapslog.NewHandler(core)), nil
+}
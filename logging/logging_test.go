@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	var output bytes.Buffer
+	logger, err := New(&output, "warn")
+	require.NoError(t, err)
+
+	logger.Info("dropped")
+	logger.Warn("kept", "account_id", 7)
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(output.Bytes(), &line))
+	require.Equal(t, "WARN", line["level"])
+	require.Equal(t, "kept", line["msg"])
+	require.Equal(t, float64(7), line["account_id"])
+}
+
+func TestNewInvalidLevel(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, "loud")
+	require.Error(t, err)
+}
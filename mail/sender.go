@@ -0,0 +1,93 @@
+// Package mail delivers plain text email.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Message is a plain text email to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers messages. Implementations must be safe for concurrent use.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPSender sends through an SMTP relay, authenticating with PLAIN when a
+// username is set.
+type SMTPSender struct {
+	address  string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPSender creates a sender for the relay at address (host:port).
+func NewSMTPSender(address, username, password, from string) *SMTPSender {
+	return &SMTPSender{
+		address:  address,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (sender *SMTPSender) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if sender.username != "" {
+		host, _, err := net.SplitHostPort(sender.address)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", sender.username, sender.password, host)
+	}
+
+	// net/smtp takes no context; give up on our side when it is done.
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(sender.address, auth, sender.from, []string{msg.To}, sender.format(msg))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (sender *SMTPSender) format(msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", sender.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// headerValue stops a value from starting a header of its own.
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
+
+// LogSender only logs messages. It is used in development when no SMTP relay
+// is configured.
+type LogSender struct{}
+
+func (LogSender) Send(ctx context.Context, msg Message) error {
+	slog.Info("email", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
@@ -0,0 +1,23 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPSenderFormat(t *testing.T) {
+	sender := NewSMTPSender("localhost:25", "", "", "bank@example.com")
+
+	data := string(sender.format(Message{
+		To:      "alice@example.com",
+		Subject: "Statement\r\nBcc: mallory@example.com",
+		Body:    "line one\nline two",
+	}))
+
+	require.Contains(t, data, "From: bank@example.com\r\n")
+	require.Contains(t, data, "To: alice@example.com\r\n")
+	require.Contains(t, data, "Subject: Statement  Bcc: mallory@example.com\r\n")
+	require.NotContains(t, data, "\r\nBcc:")
+	require.Contains(t, data, "\r\n\r\nline one\r\nline two")
+}
@@ -1,13 +1,38 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/ankurdas111111/simplebank/api"
+	"github.com/ankurdas111111/simplebank/archive"
+	"github.com/ankurdas111111/simplebank/chaos"
+	"github.com/ankurdas111111/simplebank/events"
 	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/ledger"
+	"github.com/ankurdas111111/simplebank/logging"
+	"github.com/ankurdas111111/simplebank/mail"
+	"github.com/ankurdas111111/simplebank/metrics"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/retention"
+	"github.com/ankurdas111111/simplebank/settings"
+	"github.com/ankurdas111111/simplebank/sms"
+	"github.com/ankurdas111111/simplebank/tracing"
 	"github.com/ankurdas111111/simplebank/util"
+	"github.com/ankurdas111111/simplebank/worker"
 	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 
@@ -17,18 +42,243 @@ func main(){
 	if err != nil{
 		log.Fatal("Can not load config:",err)
 	}
+	logger, err := logging.New(os.Stdout, config.LogLevel)
+	if err != nil{
+		log.Fatal("Can not create logger:", err)
+	}
+	// Background jobs log through the default logger, and so does anything
+	// still using the log package.
+	slog.SetDefault(logger)
 
 	conn, err := sql.Open(config.DBdriver, config.DBsource)
 	if err != nil {
-		log.Fatal("cannot connect to db:", err)
+		fatal("cannot connect to db", err)
+	}
+	// SIGTERM, e.g. from docker stop, or Ctrl-C stops the server and the
+	// background jobs, which are waited for before the DB pool is closed.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var jobs sync.WaitGroup
+	background := func(start func(ctx context.Context)) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			start(ctx)
+		}()
+	}
+	dbs := []*sql.DB{conn}
+	broker := events.NewBroker()
+	hub := notify.NewHub(config.LowBalanceThreshold)
+	transferMetrics := metrics.NewTransferMetrics()
+	storeOpts := []db.StoreOption{db.WithLogger(logger), db.WithTransferPublisher(broker), db.WithTransferPublisher(hub), db.WithTransferPublisher(transferMetrics)}
+	chaosConfig := chaos.Config{
+		ErrorRate:      config.ChaosErrorRate,
+		Latency:        config.ChaosLatency,
+		DropCommitRate: config.ChaosDropCommitRate,
+	}
+	if chaosConfig.Enabled() {
+		if config.Environment != util.DevelopmentEnvironment {
+			fatal("fault injection is only allowed in development", nil)
+		}
+		slog.Warn("fault injection enabled", "config", chaosConfig)
+		storeOpts = append(storeOpts, db.WithFaultInjector(chaos.NewInjector(chaosConfig)))
+	}
+	if config.DBReplicaSource != "" {
+		replica, err := sql.Open(config.DBdriver, config.DBReplicaSource)
+		if err != nil {
+			fatal("cannot connect to replica db", err)
+		}
+		dbs = append(dbs, replica)
+		interval := config.ReplicaHealthInterval
+		if interval <= 0 {
+			interval = 5 * time.Second
+		}
+		health := db.NewReplicaHealth(conn, replica, config.ReplicaMaxLagBytes)
+		background(func(ctx context.Context) { health.Start(ctx, interval) })
+		storeOpts = append(storeOpts, db.WithReadReplica(replica, health))
+	}
+	isolation, err := db.ParseIsolationLevel(config.DBTxIsolation)
+	if err != nil {
+		fatal("cannot parse DB_TX_ISOLATION", err)
+	}
+	if isolation != sql.LevelDefault {
+		storeOpts = append(storeOpts, db.WithTxIsolation(isolation))
+	}
+	var serverOpts []api.ServerOption
+	var tracer db.Tracer
+	var traceProvider *sdktrace.TracerProvider
+	switch {
+	case config.OTLPEndpoint != "":
+		traceProvider, err = tracing.NewOTLPProvider(config.OTLPEndpoint, "simplebank")
+		if err != nil {
+			fatal("cannot create OTLP exporter", err)
+		}
+		tracer = tracing.NewTracer(traceProvider)
+		serverOpts = append(serverOpts, api.WithTracer(tracer))
+	case config.TraceSlowThreshold > 0:
+		traceProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(tracing.NewSlowLogProcessor(config.TraceSlowThreshold)))
+		tracer = tracing.NewTracer(traceProvider)
+	}
+	if tracer != nil {
+		storeOpts = append(storeOpts, db.WithTracer(tracer))
+	}
+	storeMetrics := metrics.NewStoreMetrics()
+	store := db.NewTimeoutStore(db.NewStore(conn, storeOpts...), db.Timeouts{
+		Query: config.DBQueryTimeout,
+		Tx:    config.DBTxTimeout,
+	})
+	store = db.NewInstrumentedStore(store, storeMetrics)
+	if tracer != nil {
+		store = db.NewTracedStore(store, tracer)
+	}
+	store = db.NewAuditedStore(store)
+	hub.SetOutbox(store)
+	if len(os.Args) > 1 && os.Args[1] == "verify-ledger" {
+		verifyLedger(store)
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "snapshot-balances" {
+		snapshotBalances(store, os.Args[2:])
+		return
+	}
+	var metricsServer *http.Server
+	if config.MetricsAddress != "" {
+		httpMetrics := metrics.NewHTTPMetrics()
+		serverOpts = append(serverOpts, api.WithHTTPMetrics(httpMetrics))
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(storeMetrics.Collectors()...)
+		registry.MustRegister(httpMetrics.Collectors()...)
+		registry.MustRegister(transferMetrics.Collectors()...)
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		metricsServer = &http.Server{Addr: config.MetricsAddress, Handler: mux}
+		go func() {
+			if err := metricsServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				fatal("cannot serve metrics", err)
+			}
+		}()
+	}
+	if config.RetentionInterval > 0 {
+		job := retention.NewJob(store, config.RetentionInterval, config.RetentionDryRun)
+		background(job.Start)
+	}
+	if config.EmailWorkerInterval > 0 {
+		var sender mail.Sender = mail.LogSender{}
+		if config.SMTPAddress != "" {
+			sender = mail.NewSMTPSender(config.SMTPAddress, config.SMTPUsername, config.SMTPPassword, config.EmailFrom)
+		}
+		processor := worker.NewEmailProcessor(store, sender, config.EmailWorkerInterval)
+		background(processor.Start)
+	}
+	if config.ScheduledTransferInterval > 0 {
+		scheduler := worker.NewScheduledTransferProcessor(store, config.ScheduledTransferInterval)
+		background(scheduler.Start)
+	}
+	if config.WebhookWorkerInterval > 0 {
+		processor := worker.NewWebhookProcessor(store, config.WebhookWorkerInterval)
+		background(processor.Start)
 	}
-	store := db.NewStore(conn)
-	server, err := api.NewServer(config, store)
+	if config.InterestInterval > 0 {
+		processor := worker.NewInterestProcessor(store, settings.NewResolver(store), config.InterestInterval)
+		background(processor.Start)
+	}
+	if config.BalanceSnapshotInterval > 0 {
+		snapshotter := worker.NewBalanceSnapshotter(store, config.BalanceSnapshotInterval)
+		background(snapshotter.Start)
+	}
+	// Transfers and entries can't be written without the partition of
+	// their month, so this job always runs.
+	background(archive.NewJob(store, 24*time.Hour, config.LedgerArchiveMonths).Start)
+	if config.LedgerCheckInterval > 0 {
+		job := ledger.NewJob(store, config.LedgerCheckInterval)
+		background(job.Start)
+	}
+	var smsSender sms.Sender = sms.LogSender{}
+	if config.TwilioAccountSID != "" {
+		smsSender = sms.NewTwilioSender(config.TwilioAccountSID, config.TwilioAuthToken, config.SMSFrom)
+	}
+	if config.NotificationWorkerInterval > 0 {
+		processor := worker.NewNotificationProcessor(store, smsSender, config.NotificationWorkerInterval)
+		background(processor.Start)
+	}
+	serverOpts = append(serverOpts, api.WithEventBroker(broker), api.WithNotificationHub(hub), api.WithSMSSender(smsSender), api.WithLogger(logger))
+	server, err := api.NewServer(config, store, serverOpts...)
 	if err != nil{
-		log.Fatal("Can not create server:", err)
+		fatal("cannot create server", err)
 	}
-	err = server.Start(config.ServerAddress)
+	err = server.Start(ctx, config.ServerAddress)
 	if err != nil{
-		log.Fatal("Can not start the server:", err)
+		fatal("cannot start the server", err)
+	}
+	stop()
+	if metricsServer != nil {
+		// Scrapes are quick, so this only waits for the one in flight.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			slog.Error("cannot shut down metrics server", "error", err)
+		}
+		cancel()
+	}
+	jobs.Wait()
+	if traceProvider != nil {
+		// Exports the spans of the requests drained and the jobs stopped
+		// since the last export.
+		exportCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := traceProvider.Shutdown(exportCtx); err != nil {
+			slog.Error("cannot export spans", "error", err)
+		}
+		cancel()
+	}
+	for _, conn := range dbs {
+		if err := conn.Close(); err != nil {
+			slog.Error("cannot close db", "error", err)
+		}
+	}
+	slog.Info("server stopped")
+}
+
+// verifyLedger checks the ledger once and exits with a failure if anything
+// doesn't add up. The mismatches are logged by the job.
+func verifyLedger(store db.Store) {
+	report, err := ledger.NewJob(store, 0).RunOnce(context.Background())
+	if err != nil {
+		fatal("cannot verify ledger", err)
+	}
+	if !report.OK() {
+		slog.Error("ledger is inconsistent", "account_mismatches", len(report.Accounts), "transfer_mismatches", len(report.Transfers))
+		os.Exit(1)
+	}
+	slog.Info("ledger is consistent")
+}
+
+// snapshotBalances snapshots the closing balances of the day given as
+// YYYY-MM-DD, or of yesterday when no day is given.
+func snapshotBalances(store db.Store, args []string) {
+	snapshotter := worker.NewBalanceSnapshotter(store, 0)
+	ctx := context.Background()
+	var n int
+	var err error
+	if len(args) > 0 {
+		day, parseErr := time.Parse("2006-01-02", args[0])
+		if parseErr != nil {
+			fatal("cannot parse day", parseErr)
+		}
+		n, err = snapshotter.SnapshotDay(ctx, day)
+	} else {
+		n, err = snapshotter.RunOnce(ctx)
+	}
+	if err != nil {
+		fatal("cannot snapshot balances", err)
+	}
+	slog.Info("snapshotted balances", "accounts", n)
+}
+
+// fatal logs msg with err, if there is one, and exits.
+func fatal(msg string, err error) {
+	if err != nil {
+		slog.Error(msg, "error", err)
+	} else {
+		slog.Error(msg)
 	}
-}
\ No newline at end of file
+	os.Exit(1)
+}
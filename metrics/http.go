@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HTTPBuckets suit API requests, from 5 milliseconds to 10 seconds.
+var HTTPBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// HTTPMetrics records the requests the API serves. Requests are labelled by
+// route, e.g. /accounts/:id, rather than by path, so the number of series
+// stays bounded.
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+}
+
+func NewHTTPMetrics() *HTTPMetrics {
+	return &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "simplebank_http_requests_total",
+			Help: "Requests served by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "simplebank_http_request_duration_seconds",
+			Help:    "Duration of requests by route, method and status.",
+			Buckets: HTTPBuckets,
+		}, []string{"route", "method", "status"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "simplebank_http_requests_in_flight",
+			Help: "Requests being served by route and method.",
+		}, []string{"route", "method"}),
+	}
+}
+
+// Start records a request to route as in flight. The returned function
+// records it as served with status.
+func (metrics *HTTPMetrics) Start(route, method string) func(status int) {
+	start := time.Now()
+	inFlight := metrics.inFlight.WithLabelValues(route, method)
+	inFlight.Inc()
+	return func(status int) {
+		code := strconv.Itoa(status)
+		inFlight.Dec()
+		metrics.requests.WithLabelValues(route, method, code).Inc()
+		metrics.duration.WithLabelValues(route, method, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Collectors returns the metrics to register.
+func (metrics *HTTPMetrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{metrics.requests, metrics.duration, metrics.inFlight}
+}
@@ -0,0 +1,5 @@
+// Package metrics records what the server does as Prometheus metrics, with
+// the Prometheus client library. Each kind of metrics returns its
+// collectors, for main to register with the registry it serves for
+// scraping.
+package metrics
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/require"
+)
+
+// scrape registers collectors the way main does and returns what Prometheus
+// reads from them. Labels come in alphabetical order.
+func scrape(t *testing.T, collectors ...prometheus.Collector) string {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors...)
+
+	recorder := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).
+		ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	return recorder.Body.String()
+}
+
+func TestHTTPMetrics(t *testing.T) {
+	httpMetrics := NewHTTPMetrics()
+	done := httpMetrics.Start("/accounts/:id", http.MethodGet)
+	require.Contains(t, scrape(t, httpMetrics.inFlight), `simplebank_http_requests_in_flight{method="GET",route="/accounts/:id"} 1`)
+
+	done(http.StatusNotFound)
+	out := scrape(t, httpMetrics.Collectors()...)
+	require.Contains(t, out, `simplebank_http_requests_in_flight{method="GET",route="/accounts/:id"} 0`)
+	require.Contains(t, out, `simplebank_http_requests_total{method="GET",route="/accounts/:id",status="404"} 1`)
+	require.Contains(t, out, `simplebank_http_request_duration_seconds_count{method="GET",route="/accounts/:id",status="404"} 1`)
+}
+
+func TestTransferMetrics(t *testing.T) {
+	transferMetrics := NewTransferMetrics()
+	transferMetrics.PublishTransfer(db.TransferTxResult{
+		Transfer:    db.Transfer{ID: 1, Amount: 250},
+		FromAccount: db.Account{ID: 1, Currency: "USD"},
+	})
+	transferMetrics.PublishTransfer(db.TransferTxResult{
+		Transfer:    db.Transfer{ID: 2, Amount: 100},
+		FromAccount: db.Account{ID: 1, Currency: "USD"},
+	})
+
+	out := scrape(t, transferMetrics.Collectors()...)
+	require.Contains(t, out, `simplebank_transfers_created_total{currency="USD"} 2`)
+	require.Contains(t, out, `simplebank_transfer_amount_sum{currency="USD"} 350`)
+}
+
+func TestInstrumentedStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inner := mockdb.NewMockStore(ctrl)
+	inner.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.Account{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+	inner.EXPECT().GetAccount(gomock.Any(), int64(4)).
+		Times(1).
+		Return(db.Account{}, sql.ErrNoRows)
+	inner.EXPECT().Ping(gomock.Any()).
+		Times(1).
+		Return(errors.New("connection refused"))
+
+	storeMetrics := NewStoreMetrics()
+	store := db.NewInstrumentedStore(inner, storeMetrics)
+
+	accounts, err := store.ListAccounts(context.Background(), db.ListAccountsParams{})
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+	_, err = store.GetAccount(context.Background(), 4)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.Error(t, store.Ping(context.Background()))
+
+	out := scrape(t, storeMetrics.Collectors()...)
+	require.Contains(t, out, `simplebank_store_query_duration_seconds_count{method="ListAccounts",result="ok"} 1`)
+	require.Contains(t, out, `simplebank_store_query_duration_seconds_count{method="GetAccount",result="no_rows"} 1`)
+	require.Contains(t, out, `simplebank_store_query_duration_seconds_count{method="Ping",result="error"} 1`)
+	require.Contains(t, out, `simplebank_store_query_rows_sum{method="ListAccounts"} 3`)
+	// Failed calls return no rows to count.
+	require.NotContains(t, out, `simplebank_store_query_rows_count{method="GetAccount"}`)
+}
+
+func TestStoreMetricsDuration(t *testing.T) {
+	storeMetrics := NewStoreMetrics()
+	storeMetrics.ObserveQuery("TransferTx", 30*time.Millisecond, 1, nil)
+
+	out := scrape(t, storeMetrics.duration)
+	require.Contains(t, out, `simplebank_store_query_duration_seconds_bucket{method="TransferTx",result="ok",le="0.025"} 0`)
+	require.Contains(t, out, `simplebank_store_query_duration_seconds_bucket{method="TransferTx",result="ok",le="0.05"} 1`)
+}
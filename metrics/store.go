@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Outcomes a store call is labelled with. Finding nothing is not a failure,
+// so it is kept apart from errors.
+const (
+	resultOK     = "ok"
+	resultNoRows = "no_rows"
+	resultError  = "error"
+)
+
+var (
+	// DurationBuckets suit database calls, from a millisecond to 10 seconds.
+	DurationBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// RowBuckets suit the sizes of pages the API reads.
+	RowBuckets = []float64{0, 1, 5, 10, 25, 50, 100, 250, 500, 1000}
+)
+
+// StoreMetrics records the calls of an instrumented db.Store, see
+// db.NewInstrumentedStore. The error rate of a method is its error count
+// over its total count in the duration histogram.
+type StoreMetrics struct {
+	duration *prometheus.HistogramVec
+	rows     *prometheus.HistogramVec
+}
+
+func NewStoreMetrics() *StoreMetrics {
+	return &StoreMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "simplebank_store_query_duration_seconds",
+			Help:    "Duration of calls to the store by method and result.",
+			Buckets: DurationBuckets,
+		}, []string{"method", "result"}),
+		rows: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "simplebank_store_query_rows",
+			Help:    "Rows returned by successful calls to the store by method.",
+			Buckets: RowBuckets,
+		}, []string{"method"}),
+	}
+}
+
+// ObserveQuery implements db.QueryObserver.
+func (metrics *StoreMetrics) ObserveQuery(method string, duration time.Duration, rows int, err error) {
+	result := resultOK
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		result = resultNoRows
+	case err != nil:
+		result = resultError
+	}
+
+	metrics.duration.WithLabelValues(method, result).Observe(duration.Seconds())
+	if err == nil {
+		metrics.rows.WithLabelValues(method).Observe(float64(rows))
+	}
+}
+
+// Collectors returns the histograms to register.
+func (metrics *StoreMetrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{metrics.duration, metrics.rows}
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TransferMetrics counts the transfers that moved money. It is given to the
+// store as a db.TransferPublisher, so it only sees committed transfers:
+// pending transfers are counted once they complete.
+type TransferMetrics struct {
+	created *prometheus.CounterVec
+	amount  *prometheus.CounterVec
+}
+
+func NewTransferMetrics() *TransferMetrics {
+	return &TransferMetrics{
+		created: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "simplebank_transfers_created_total",
+			Help: "Transfers that moved money, by currency of the sending account.",
+		}, []string{"currency"}),
+		amount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "simplebank_transfer_amount_sum",
+			Help: "Money moved by transfers in minor units, by currency of the sending account.",
+		}, []string{"currency"}),
+	}
+}
+
+// PublishTransfer implements db.TransferPublisher.
+func (metrics *TransferMetrics) PublishTransfer(result db.TransferTxResult) {
+	currency := result.FromAccount.Currency
+	metrics.created.WithLabelValues(currency).Inc()
+	metrics.amount.WithLabelValues(currency).Add(float64(result.Transfer.Amount))
+}
+
+// Collectors returns the counters to register.
+func (metrics *TransferMetrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{metrics.created, metrics.amount}
+}
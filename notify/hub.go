@@ -0,0 +1,243 @@
+// Package notify delivers real-time notifications to the connected clients of
+// a user, within a single server process.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+)
+
+// Notification types.
+const (
+	TransferReceived = "transfer.received"
+	LowBalance       = "balance.low"
+	SecurityAlert    = "security.alert"
+	LargeTransfer    = "transfer.large"
+)
+
+// Channels notifications are delivered on.
+const (
+	ChannelInApp = "in_app"
+	ChannelSMS   = "sms"
+)
+
+// channelTypes lists the notification types each channel delivers. Users can
+// turn each of them off.
+var channelTypes = map[string][]string{
+	ChannelInApp: {TransferReceived, LowBalance, SecurityAlert},
+	ChannelSMS:   {SecurityAlert, LargeTransfer},
+}
+
+// Channels returns every channel, in a stable order.
+func Channels() []string {
+	return []string{ChannelInApp, ChannelSMS}
+}
+
+// ChannelTypes returns the notification types a channel delivers.
+func ChannelTypes(channel string) []string {
+	return append([]string(nil), channelTypes[channel]...)
+}
+
+// Delivers reports whether channel delivers notifications of the given type.
+func Delivers(channel, kind string) bool {
+	for _, t := range channelTypes[channel] {
+		if t == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Security events reported in a SecurityAlert.
+const (
+	SecurityLogin        = "login"
+	SecurityLoginFailed  = "login_failed"
+	SecurityPasskeyAdded = "passkey_added"
+	SecurityTotpEnrolled = "totp_enrolled"
+)
+
+// clientBuffer is how many notifications a slow client may fall behind by
+// before further notifications to it are dropped.
+const clientBuffer = 32
+
+// Notification is the JSON message sent to clients.
+type Notification struct {
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// TransferReceivedData is the data of a TransferReceived notification.
+type TransferReceivedData struct {
+	TransferID    int64  `json:"transfer_id"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	Balance       int64  `json:"balance"`
+}
+
+// LowBalanceData is the data of a LowBalance notification.
+type LowBalanceData struct {
+	AccountID int64  `json:"account_id"`
+	Balance   int64  `json:"balance"`
+	Currency  string `json:"currency"`
+	Threshold int64  `json:"threshold"`
+}
+
+// SecurityAlertData is the data of a SecurityAlert notification.
+type SecurityAlertData struct {
+	Event     string `json:"event"`
+	ClientIP  string `json:"client_ip"`
+	UserAgent string `json:"user_agent"`
+}
+
+// outboxTimeout bounds how long recording one notification may take.
+const outboxTimeout = 5 * time.Second
+
+// Outbox records notifications, so users can read the ones they missed.
+type Outbox interface {
+	CreateNotification(ctx context.Context, arg db.CreateNotificationParams) (db.Notification, error)
+}
+
+// Hub fans notifications out to every connection of a user. Notifying never
+// blocks: a client that does not keep up misses notifications rather than
+// holding up the request that caused them.
+type Hub struct {
+	mu      sync.Mutex
+	clients map[string]map[chan Notification]struct{}
+	// lowBalanceThreshold is the balance below which owners are warned.
+	// Zero disables the warning.
+	lowBalanceThreshold int64
+	outbox              Outbox
+	now                 func() time.Time
+}
+
+func NewHub(lowBalanceThreshold int64) *Hub {
+	return &Hub{
+		clients:             make(map[string]map[chan Notification]struct{}),
+		lowBalanceThreshold: lowBalanceThreshold,
+		now:                 time.Now,
+	}
+}
+
+// SetOutbox makes the hub record every notification in outbox as well. The
+// store is built after the hub, since it publishes transfers to it, so the
+// outbox can't be passed to NewHub.
+func (hub *Hub) SetOutbox(outbox Outbox) {
+	hub.mu.Lock()
+	hub.outbox = outbox
+	hub.mu.Unlock()
+}
+
+// Subscribe registers a connection of username. It returns the notifications
+// for the connection and a function that unregisters it and closes the
+// channel.
+func (hub *Hub) Subscribe(username string) (<-chan Notification, func()) {
+	ch := make(chan Notification, clientBuffer)
+
+	hub.mu.Lock()
+	if hub.clients[username] == nil {
+		hub.clients[username] = make(map[chan Notification]struct{})
+	}
+	hub.clients[username][ch] = struct{}{}
+	hub.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			hub.mu.Lock()
+			defer hub.mu.Unlock()
+			delete(hub.clients[username], ch)
+			if len(hub.clients[username]) == 0 {
+				delete(hub.clients, username)
+			}
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+// Notify sends a notification of the given type to every connection of
+// username, and records it in the outbox in the background.
+func (hub *Hub) Notify(username, kind string, data interface{}) {
+	notification := Notification{
+		Type:      kind,
+		CreatedAt: hub.now().UTC(),
+		Data:      data,
+	}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for ch := range hub.clients[username] {
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+	if hub.outbox != nil {
+		go hub.record(hub.outbox, username, notification)
+	}
+}
+
+// record stores an in-app notification. It was delivered to whoever was
+// connected, so it is stored sent; failures are only logged.
+func (hub *Hub) record(outbox Outbox, username string, notification Notification) {
+	payload, err := json.Marshal(notification.Data)
+	if err != nil {
+		slog.Error("cannot encode notification", "type", notification.Type, "user", username, "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), outboxTimeout)
+	defer cancel()
+	_, err = outbox.CreateNotification(ctx, db.CreateNotificationParams{
+		Username:    username,
+		Channel:     ChannelInApp,
+		Type:        notification.Type,
+		Payload:     payload,
+		Status:      db.NotificationSent,
+		LockedUntil: notification.CreatedAt,
+	})
+	if err != nil {
+		slog.Warn("cannot record notification", "type", notification.Type, "user", username, "error", err)
+	}
+}
+
+// Alert reports a security event on the user's account.
+func (hub *Hub) Alert(username string, data SecurityAlertData) {
+	hub.Notify(username, SecurityAlert, data)
+}
+
+// PublishTransfer implements db.TransferPublisher. The receiver is told about
+// the incoming transfer, and the sender is warned when the transfer takes
+// their balance below the threshold.
+func (hub *Hub) PublishTransfer(result db.TransferTxResult) {
+	hub.Notify(result.ToAccount.Owner, TransferReceived, TransferReceivedData{
+		TransferID:    result.Transfer.ID,
+		FromAccountID: result.FromAccount.ID,
+		ToAccountID:   result.ToAccount.ID,
+		Amount:        result.ToEntry.Amount,
+		Currency:      result.ToAccount.Currency,
+		Balance:       result.ToAccount.Balance,
+	})
+
+	// Only warn when the balance crosses the threshold, not on every
+	// transfer made while it is low.
+	from := result.FromAccount
+	before := from.Balance - result.FromEntry.Amount
+	if hub.lowBalanceThreshold > 0 && from.Balance < hub.lowBalanceThreshold && before >= hub.lowBalanceThreshold {
+		hub.Notify(from.Owner, LowBalance, LowBalanceData{
+			AccountID: from.ID,
+			Balance:   from.Balance,
+			Currency:  from.Currency,
+			Threshold: hub.lowBalanceThreshold,
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func transferResult(fromBalance, amount int64) db.TransferTxResult {
+	return db.TransferTxResult{
+		Transfer:    db.Transfer{ID: 9, FromAccountID: 1, ToAccountID: 2, Amount: amount},
+		FromAccount: db.Account{ID: 1, Owner: "alice", Balance: fromBalance, Currency: "INR"},
+		ToAccount:   db.Account{ID: 2, Owner: "bob", Balance: 500, Currency: "INR"},
+		FromEntry:   db.Entry{AccountID: 1, Amount: -amount},
+		ToEntry:     db.Entry{AccountID: 2, Amount: amount},
+	}
+}
+
+func TestPublishTransfer(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	hub := NewHub(100)
+	hub.now = func() time.Time { return now }
+
+	alice, cancelAlice := hub.Subscribe("alice")
+	defer cancelAlice()
+	bob, cancelBob := hub.Subscribe("bob")
+	defer cancelBob()
+
+	// 120 -> 90 crosses the threshold.
+	hub.PublishTransfer(transferResult(90, 30))
+
+	require.Equal(t, Notification{
+		Type:      TransferReceived,
+		CreatedAt: now,
+		Data: TransferReceivedData{
+			TransferID:    9,
+			FromAccountID: 1,
+			ToAccountID:   2,
+			Amount:        30,
+			Currency:      "INR",
+			Balance:       500,
+		},
+	}, <-bob)
+	require.Empty(t, bob)
+
+	require.Equal(t, Notification{
+		Type:      LowBalance,
+		CreatedAt: now,
+		Data:      LowBalanceData{AccountID: 1, Balance: 90, Currency: "INR", Threshold: 100},
+	}, <-alice)
+
+	// 90 -> 60 was already low.
+	hub.PublishTransfer(transferResult(60, 30))
+	require.Empty(t, alice)
+}
+
+func TestLowBalanceDisabled(t *testing.T) {
+	hub := NewHub(0)
+	alice, cancel := hub.Subscribe("alice")
+	defer cancel()
+
+	hub.PublishTransfer(transferResult(-10, 30))
+	require.Empty(t, alice)
+}
+
+func TestHubFansOutToEveryConnection(t *testing.T) {
+	hub := NewHub(0)
+	first, cancelFirst := hub.Subscribe("alice")
+	second, cancelSecond := hub.Subscribe("alice")
+	defer cancelSecond()
+
+	hub.Alert("alice", SecurityAlertData{Event: SecurityLogin, ClientIP: "10.0.0.1"})
+	require.Equal(t, SecurityAlert, (<-first).Type)
+	require.Equal(t, SecurityAlert, (<-second).Type)
+
+	// A closed connection gets nothing more, the others still do.
+	cancelFirst()
+	cancelFirst()
+	hub.Alert("alice", SecurityAlertData{Event: SecurityLoginFailed})
+	_, open := <-first
+	require.False(t, open)
+	require.Equal(t, SecurityLoginFailed, (<-second).Data.(SecurityAlertData).Event)
+}
+
+type fakeOutbox chan db.CreateNotificationParams
+
+func (outbox fakeOutbox) CreateNotification(ctx context.Context, arg db.CreateNotificationParams) (db.Notification, error) {
+	outbox <- arg
+	return db.Notification{}, nil
+}
+
+func TestNotifyRecordsInOutbox(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	outbox := make(fakeOutbox, 1)
+	hub := NewHub(0)
+	hub.now = func() time.Time { return now }
+	hub.SetOutbox(outbox)
+
+	// Notifications are recorded whether or not the user is connected.
+	hub.Alert("alice", SecurityAlertData{Event: SecurityLogin, ClientIP: "10.0.0.1"})
+
+	select {
+	case arg := <-outbox:
+		require.Equal(t, "alice", arg.Username)
+		require.Equal(t, ChannelInApp, arg.Channel)
+		require.Equal(t, SecurityAlert, arg.Type)
+		require.Equal(t, db.NotificationSent, arg.Status)
+		require.Equal(t, now, arg.LockedUntil)
+		require.JSONEq(t, `{"event":"login","client_ip":"10.0.0.1","user_agent":""}`, string(arg.Payload))
+	case <-time.After(time.Second):
+		require.FailNow(t, "notification not recorded")
+	}
+}
@@ -0,0 +1,80 @@
+// Package retention runs the data retention rules on a schedule.
+package retention
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+)
+
+// jobUser is recorded as the actor of scheduled runs.
+const jobUser = "retention-job"
+
+// RunTimeout bounds a run. A run deletes everything past retention in one
+// transaction, which takes far longer than the store's timeouts allow on
+// any real backlog, so they don't apply to it.
+const RunTimeout = time.Hour
+
+// Store is the part of db.Store the job needs.
+type Store interface {
+	PurgeRetentionTx(ctx context.Context, arg db.PurgeRetentionTxParams) (db.RetentionReport, error)
+}
+
+// Job applies the retention rules every interval. In dry-run mode it only
+// reports what would be removed.
+type Job struct {
+	store    Store
+	interval time.Duration
+	dryRun   bool
+	now      func() time.Time
+}
+
+func NewJob(store Store, interval time.Duration, dryRun bool) *Job {
+	return &Job{
+		store:    store,
+		interval: interval,
+		dryRun:   dryRun,
+		now:      time.Now,
+	}
+}
+
+// RunOnce applies the rules a single time and logs the report.
+func (job *Job) RunOnce(ctx context.Context) (db.RetentionReport, error) {
+	ctx, cancel := context.WithTimeout(db.WithoutTimeouts(ctx), RunTimeout)
+	defer cancel()
+
+	report, err := job.store.PurgeRetentionTx(ctx, db.PurgeRetentionTxParams{
+		Now:    job.now(),
+		DryRun: job.dryRun,
+		RunBy:  jobUser,
+	})
+	if err != nil {
+		return report, err
+	}
+
+	for _, item := range report.Items {
+		slog.Info("retention run",
+			"run_id", report.Run.ID, "dry_run", report.Run.DryRun, "action", item.Action, "affected", item.Affected, "target", item.Target, "cutoff", item.Cutoff.Format(time.RFC3339))
+	}
+	return report, nil
+}
+
+// Start runs the job every interval until ctx is done. Failed runs are
+// logged and retried at the next tick.
+func (job *Job) Start(ctx context.Context) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := job.RunOnce(ctx); err != nil {
+				slog.Error("retention run failed", "error", err)
+			}
+		}
+	}
+}
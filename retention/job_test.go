@@ -0,0 +1,85 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().PurgeRetentionTx(gomock.Any(), gomock.Eq(db.PurgeRetentionTxParams{
+		Now:    now,
+		DryRun: true,
+		RunBy:  jobUser,
+	})).
+		Times(1).
+		Return(db.RetentionReport{
+			Run: db.RetentionRun{ID: 1, DryRun: true, RunBy: jobUser},
+			Items: []db.RetentionRunItem{
+				{RunID: 1, Target: db.RetentionLoginEvents, Action: db.RetentionPurge, Cutoff: now.AddDate(-1, 0, 0), Affected: 3},
+			},
+		}, nil)
+
+	job := NewJob(store, time.Hour, true)
+	job.now = func() time.Time { return now }
+
+	report, err := job.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.True(t, report.Run.DryRun)
+	require.Len(t, report.Items, 1)
+	require.Equal(t, int64(3), report.Items[0].Affected)
+}
+
+func TestStartStopsWithContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().PurgeRetentionTx(gomock.Any(), gomock.Any()).
+		MinTimes(1).
+		Return(db.RetentionReport{}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		NewJob(store, 5*time.Millisecond, false).Start(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after the context was cancelled")
+	}
+}
+
+func TestRunOnceIgnoresStoreTimeouts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := mockdb.NewMockStore(ctrl)
+	mock.EXPECT().PurgeRetentionTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, _ db.PurgeRetentionTxParams) (db.RetentionReport, error) {
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			require.WithinDuration(t, time.Now().Add(RunTimeout), deadline, time.Minute)
+			return db.RetentionReport{}, nil
+		})
+	store := db.NewTimeoutStore(mock, db.Timeouts{Query: time.Second, Tx: time.Second})
+
+	_, err := NewJob(store, time.Hour, false).RunOnce(context.Background())
+	require.NoError(t, err)
+}
@@ -0,0 +1,248 @@
+// Package settings resolves business-rule settings through layered overrides.
+// A value can be set for everyone, for one currency, for one tenant (branch),
+// or for one tenant and currency; the most specific layer wins and code
+// defaults apply when nothing is set.
+package settings
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+)
+
+// Setting keys. Limits are per KYC tier, see LimitKey.
+const (
+	KeySupportedCurrencies = "currencies.supported"
+	KeyTransferFeeBps      = "fees.transfer_bps"
+	KeyFXFeeBps            = "fees.fx_bps"
+	KeyInterestRateBps     = "interest.rate_bps"
+	// Maintenance is announced on the public status page. Only the global
+	// layer is read.
+	KeyMaintenanceEnabled = "maintenance.enabled"
+	KeyMaintenanceMessage = "maintenance.message"
+)
+
+// maxMaintenanceMessage bounds the text shown on the status page.
+const maxMaintenanceMessage = 500
+
+// Limit fields, combined with a tier by LimitKey.
+const (
+	LimitMaxTransferINR = "max_transfer_inr"
+	LimitAllowFX        = "allow_fx"
+	LimitAllowExternal  = "allow_external"
+)
+
+// LimitKey returns the key of a limit field for a KYC tier.
+func LimitKey(tier, field string) string {
+	return "limits." + tier + "." + field
+}
+
+// Validate returns an error if key is not a setting that some code reads or
+// value can't be parsed as that setting.
+func Validate(key, value string) error {
+	switch key {
+	case KeySupportedCurrencies:
+		for _, c := range strings.Split(value, ",") {
+			if !util.IsSupportedCurrency(strings.TrimSpace(c)) {
+				return fmt.Errorf("unsupported currency %q", c)
+			}
+		}
+		return nil
+	case KeyTransferFeeBps, KeyFXFeeBps, KeyInterestRateBps:
+		return validateAmount(key, value)
+	case KeyMaintenanceEnabled:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("setting %s: %w", key, err)
+		}
+		return nil
+	case KeyMaintenanceMessage:
+		if len(value) > maxMaintenanceMessage {
+			return fmt.Errorf("setting %s is longer than %d bytes", key, maxMaintenanceMessage)
+		}
+		return nil
+	}
+
+	for _, tier := range []string{util.KYCTierBasic, util.KYCTierVerified, util.KYCTierFull} {
+		switch key {
+		case LimitKey(tier, LimitMaxTransferINR):
+			return validateAmount(key, value)
+		case LimitKey(tier, LimitAllowFX), LimitKey(tier, LimitAllowExternal):
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("setting %s: %w", key, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown setting %q", key)
+}
+
+func validateAmount(key, value string) error {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("setting %s: %w", key, err)
+	}
+	if n < 0 {
+		return fmt.Errorf("setting %s must not be negative", key)
+	}
+	return nil
+}
+
+// Source loads the stored overrides.
+type Source interface {
+	ListSettings(ctx context.Context) ([]db.Setting, error)
+}
+
+// defaultTTL bounds how stale a resolver can be when another instance
+// changed a setting.
+const defaultTTL = time.Minute
+
+type scope struct {
+	tenant   string
+	currency string
+	key      string
+}
+
+// Resolver caches the overrides and answers lookups. It is safe for
+// concurrent use.
+type Resolver struct {
+	source Source
+	ttl    time.Duration
+
+	mu       sync.RWMutex
+	values   map[scope]string
+	loadedAt time.Time
+}
+
+// NewResolver creates a resolver reading overrides from source.
+func NewResolver(source Source) *Resolver {
+	return &Resolver{source: source, ttl: defaultTTL}
+}
+
+// Invalidate forces the next lookup to reload the overrides.
+func (resolver *Resolver) Invalidate() {
+	resolver.mu.Lock()
+	resolver.loadedAt = time.Time{}
+	resolver.mu.Unlock()
+}
+
+func (resolver *Resolver) snapshot(ctx context.Context) (map[scope]string, error) {
+	resolver.mu.RLock()
+	values, loadedAt := resolver.values, resolver.loadedAt
+	resolver.mu.RUnlock()
+	if !loadedAt.IsZero() && time.Since(loadedAt) < resolver.ttl {
+		return values, nil
+	}
+
+	rows, err := resolver.source.ListSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values = make(map[scope]string, len(rows))
+	for _, row := range rows {
+		values[scope{row.Tenant, row.Currency, row.Key}] = row.Value
+	}
+
+	resolver.mu.Lock()
+	resolver.values, resolver.loadedAt = values, time.Now()
+	resolver.mu.Unlock()
+	return values, nil
+}
+
+// Lookup returns the most specific override of key for the tenant and
+// currency. Either may be empty to skip that layer. ok is false if no layer
+// sets the key, in which case the caller's default applies.
+func (resolver *Resolver) Lookup(ctx context.Context, tenant, currency, key string) (value string, ok bool, err error) {
+	values, err := resolver.snapshot(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	// Most specific first.
+	var layers []scope
+	if tenant != "" && currency != "" {
+		layers = append(layers, scope{tenant, currency, key})
+	}
+	if tenant != "" {
+		layers = append(layers, scope{tenant, "", key})
+	}
+	if currency != "" {
+		layers = append(layers, scope{"", currency, key})
+	}
+	layers = append(layers, scope{"", "", key})
+
+	for _, layer := range layers {
+		if value, ok := values[layer]; ok {
+			return value, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// Int64 resolves key as an integer, returning def when it is not set.
+func (resolver *Resolver) Int64(ctx context.Context, tenant, currency, key string, def int64) (int64, error) {
+	value, ok, err := resolver.Lookup(ctx, tenant, currency, key)
+	if err != nil || !ok {
+		return def, err
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return def, fmt.Errorf("setting %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// Bool resolves key as a boolean, returning def when it is not set.
+func (resolver *Resolver) Bool(ctx context.Context, tenant, currency, key string, def bool) (bool, error) {
+	value, ok, err := resolver.Lookup(ctx, tenant, currency, key)
+	if err != nil || !ok {
+		return def, err
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return def, fmt.Errorf("setting %s: %w", key, err)
+	}
+	return b, nil
+}
+
+// CurrencySupported returns true if the tenant may open accounts in currency.
+// Without an override every currency known to util is supported.
+func (resolver *Resolver) CurrencySupported(ctx context.Context, tenant, currency string) (bool, error) {
+	value, ok, err := resolver.Lookup(ctx, tenant, "", KeySupportedCurrencies)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return util.IsSupportedCurrency(currency), nil
+	}
+	for _, c := range strings.Split(value, ",") {
+		if strings.TrimSpace(c) == currency {
+			return util.IsSupportedCurrency(currency), nil
+		}
+	}
+	return false, nil
+}
+
+// Maintenance is the announced maintenance state.
+type Maintenance struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty"`
+}
+
+// Maintenance returns the global maintenance state. It is off unless set.
+func (resolver *Resolver) Maintenance(ctx context.Context) (Maintenance, error) {
+	enabled, err := resolver.Bool(ctx, "", "", KeyMaintenanceEnabled, false)
+	if err != nil {
+		return Maintenance{}, err
+	}
+	message, _, err := resolver.Lookup(ctx, "", "", KeyMaintenanceMessage)
+	if err != nil {
+		return Maintenance{}, err
+	}
+	return Maintenance{Enabled: enabled, Message: message}, nil
+}
@@ -0,0 +1,110 @@
+package settings
+
+import (
+	"context"
+	"testing"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	rows  []db.Setting
+	loads int
+}
+
+func (f *fakeSource) ListSettings(ctx context.Context) ([]db.Setting, error) {
+	f.loads++
+	return f.rows, nil
+}
+
+func TestLookupLayers(t *testing.T) {
+	source := &fakeSource{rows: []db.Setting{
+		{Key: KeyInterestRateBps, Value: "100"},
+		{Currency: util.USD, Key: KeyInterestRateBps, Value: "200"},
+		{Tenant: "branch-a", Key: KeyInterestRateBps, Value: "300"},
+		{Tenant: "branch-a", Currency: util.USD, Key: KeyInterestRateBps, Value: "400"},
+	}}
+	resolver := NewResolver(source)
+	ctx := context.Background()
+
+	testCases := []struct {
+		tenant   string
+		currency string
+		want     int64
+	}{
+		{"branch-a", util.USD, 400},
+		{"branch-a", util.EUR, 300},
+		{"branch-b", util.USD, 200},
+		{"branch-b", util.EUR, 100},
+		{"", "", 100},
+	}
+	for _, tc := range testCases {
+		got, err := resolver.Int64(ctx, tc.tenant, tc.currency, KeyInterestRateBps, 0)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, got, "%s/%s", tc.tenant, tc.currency)
+	}
+
+	got, err := resolver.Int64(ctx, "branch-a", util.USD, KeyFXFeeBps, 42)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), got)
+
+	// The snapshot is cached until invalidated.
+	require.Equal(t, 1, source.loads)
+	resolver.Invalidate()
+	_, _, err = resolver.Lookup(ctx, "", "", KeyFXFeeBps)
+	require.NoError(t, err)
+	require.Equal(t, 2, source.loads)
+}
+
+func TestCurrencySupported(t *testing.T) {
+	resolver := NewResolver(&fakeSource{rows: []db.Setting{
+		{Tenant: "branch-a", Key: KeySupportedCurrencies, Value: "INR, USD"},
+	}})
+	ctx := context.Background()
+
+	ok, err := resolver.CurrencySupported(ctx, "branch-a", util.USD)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = resolver.CurrencySupported(ctx, "branch-a", util.EUR)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = resolver.CurrencySupported(ctx, "branch-b", util.EUR)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(KeySupportedCurrencies, "INR,USD"))
+	require.NoError(t, Validate(LimitKey(util.KYCTierBasic, LimitAllowFX), "true"))
+	require.NoError(t, Validate(LimitKey(util.KYCTierFull, LimitMaxTransferINR), "100000"))
+
+	require.Error(t, Validate(KeySupportedCurrencies, "INR,XYZ"))
+	require.Error(t, Validate(LimitKey(util.KYCTierBasic, LimitAllowFX), "maybe"))
+	require.Error(t, Validate(KeyTransferFeeBps, "-1"))
+	require.Error(t, Validate("limits.gold.allow_fx", "true"))
+}
+
+func TestMaintenance(t *testing.T) {
+	ctx := context.Background()
+
+	maintenance, err := NewResolver(&fakeSource{}).Maintenance(ctx)
+	require.NoError(t, err)
+	require.False(t, maintenance.Enabled)
+
+	source := &fakeSource{rows: []db.Setting{
+		{Key: KeyMaintenanceEnabled, Value: "true"},
+		{Key: KeyMaintenanceMessage, Value: "Back at 02:00 UTC"},
+		// Tenant layers do not apply to the global maintenance state.
+		{Tenant: "branch-a", Key: KeyMaintenanceEnabled, Value: "false"},
+	}}
+	maintenance, err = NewResolver(source).Maintenance(ctx)
+	require.NoError(t, err)
+	require.Equal(t, Maintenance{Enabled: true, Message: "Back at 02:00 UTC"}, maintenance)
+
+	require.NoError(t, Validate(KeyMaintenanceEnabled, "false"))
+	require.Error(t, Validate(KeyMaintenanceEnabled, "soon"))
+}
@@ -0,0 +1,95 @@
+// Package sms delivers text messages.
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// twilioBaseURL is the REST API the Twilio sender posts to.
+const twilioBaseURL = "https://api.twilio.com/2010-04-01"
+
+// Message is a text message to a single phone number in E.164 format.
+type Message struct {
+	To   string
+	Body string
+}
+
+//go:generate mockgen -package mocksms -destination mock/sender.go -build_flags=-mod=mod github.com/ankurdas111111/simplebank/sms Sender
+
+// Sender delivers messages. Implementations must be safe for concurrent use.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// TwilioSender sends through the Twilio Messages API, or any provider that
+// speaks it.
+type TwilioSender struct {
+	baseURL    string
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+// NewTwilioSender creates a sender for the account, sending from the given
+// number.
+func NewTwilioSender(accountSID, authToken, from string) *TwilioSender {
+	return &TwilioSender{
+		baseURL:    twilioBaseURL,
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (sender *TwilioSender) Send(ctx context.Context, msg Message) error {
+	form := url.Values{}
+	form.Set("To", msg.To)
+	form.Set("From", sender.from)
+	form.Set("Body", msg.Body)
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", sender.baseURL, url.PathEscape(sender.accountSID))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(sender.accountSID, sender.authToken)
+
+	response, err := sender.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		// The API explains errors in a JSON body.
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(io.LimitReader(response.Body, 4096)).Decode(&apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("sms provider answered %s: %d %s", response.Status, apiErr.Code, apiErr.Message)
+		}
+		return fmt.Errorf("sms provider answered %s", response.Status)
+	}
+	return nil
+}
+
+// LogSender only logs messages. It is used in development when no provider
+// is configured.
+type LogSender struct{}
+
+func (LogSender) Send(ctx context.Context, msg Message) error {
+	slog.Info("sms", "to", msg.To, "body", msg.Body)
+	return nil
+}
@@ -0,0 +1,50 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwilioSender(t *testing.T) {
+	var received *http.Request
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		received = r
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer provider.Close()
+
+	sender := NewTwilioSender("AC123", "secret", "+15550000000")
+	sender.baseURL = provider.URL
+
+	err := sender.Send(context.Background(), Message{To: "+15551234567", Body: "Your password was changed"})
+	require.NoError(t, err)
+
+	require.Equal(t, "/Accounts/AC123/Messages.json", received.URL.Path)
+	username, password, ok := received.BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "AC123", username)
+	require.Equal(t, "secret", password)
+	require.Equal(t, "+15551234567", received.PostForm.Get("To"))
+	require.Equal(t, "+15550000000", received.PostForm.Get("From"))
+	require.Equal(t, "Your password was changed", received.PostForm.Get("Body"))
+}
+
+func TestTwilioSenderError(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":21211,"message":"The 'To' number is not a valid phone number."}`))
+	}))
+	defer provider.Close()
+
+	sender := NewTwilioSender("AC123", "secret", "+15550000000")
+	sender.baseURL = provider.URL
+
+	err := sender.Send(context.Background(), Message{To: "+1", Body: "hi"})
+	require.EqualError(t, err, "sms provider answered 400 Bad Request: 21211 The 'To' number is not a valid phone number.")
+}
@@ -0,0 +1,107 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 in points.
+const (
+	pageWidth  = 595
+	pageHeight = 842
+)
+
+// Fonts every PDF reader has, so nothing needs embedding.
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+// page is the content stream of one page.
+type page struct {
+	content bytes.Buffer
+}
+
+// text writes s with its baseline starting at (x, y).
+func (p *page) text(font string, size float64, x, y float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, size, x, y, escapeText(s))
+}
+
+// textRight writes s so that it ends at x. Widths are estimated, which is
+// close enough for the digits and short words it's used for.
+func (p *page) textRight(font string, size float64, x, y float64, s string) {
+	p.text(font, size, x-textWidth(s, size), y, s)
+}
+
+// color sets the fill color used for text and rectangles.
+func (p *page) color(r, g, b float64) {
+	fmt.Fprintf(&p.content, "%.3f %.3f %.3f rg\n", r, g, b)
+}
+
+func (p *page) rect(x, y, width, height float64) {
+	fmt.Fprintf(&p.content, "%.1f %.1f %.1f %.1f re f\n", x, y, width, height)
+}
+
+// textWidth estimates the width of s in Helvetica: digits and most letters
+// are about half an em wide.
+func textWidth(s string, size float64) float64 {
+	return float64(len(s)) * size * 0.5
+}
+
+// escapeText makes s safe inside a PDF string literal. The standard fonts
+// only cover Latin-1 reliably, so anything else becomes '?'.
+func escapeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// writePDF lays out the objects of the document and the cross-reference
+// table readers use to find them.
+func writePDF(pages []*page) []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// Objects 1 to 4 are fixed, then every page takes two: the page and its
+	// content stream.
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, p := range pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, 6+2*i,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
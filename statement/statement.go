@@ -0,0 +1,183 @@
+// Package statement renders monthly account statements as PDF.
+package statement
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+)
+
+// Layout, in points from the bottom left corner.
+const (
+	marginLeft   = 40
+	marginRight  = pageWidth - 40
+	marginBottom = 60
+	headerHeight = 70
+	lineHeight   = 16
+
+	// maxMemoLength keeps memos inside their column.
+	maxMemoLength = 28
+)
+
+// Statement is what a monthly statement shows. Amounts are in the minor
+// units the account stores.
+type Statement struct {
+	Account db.Account
+	// Month is the first instant of the month, in UTC.
+	Month          time.Time
+	OpeningBalance int64
+	ClosingBalance int64
+	// Entries of the month, oldest first.
+	Entries []db.Entry
+	// Transfers from or to the account in the month, oldest first.
+	Transfers   []db.ListOwnerTransfersRow
+	GeneratedAt time.Time
+}
+
+// Render lays the statement out on as many A4 pages as it needs.
+func Render(s Statement) []byte {
+	l := &layout{}
+	l.newPage()
+
+	p := l.current()
+	p.text(fontBold, 14, marginLeft, l.y, fmt.Sprintf("Statement for %s", s.Month.Format("January 2006")))
+	l.y -= lineHeight * 1.5
+	p.text(fontRegular, 10, marginLeft, l.y, fmt.Sprintf("Account %d, held by %s, in %s", s.Account.ID, s.Account.Owner, s.Account.Currency))
+	l.y -= lineHeight * 2
+
+	l.summary("Opening balance", s.OpeningBalance, s.Account.Currency)
+	l.summary("Closing balance", s.ClosingBalance, s.Account.Currency)
+	l.y -= lineHeight
+
+	l.heading("Entries", []cell{{"Date", marginLeft, false}, {"Kind", 160, false}, {"Amount", 420, true}, {"Balance", marginRight, true}})
+	if len(s.Entries) == 0 {
+		l.row([]cell{{"No entries this month.", marginLeft, false}})
+	}
+	for _, entry := range s.Entries {
+		l.row([]cell{
+			{entry.CreatedAt.UTC().Format("2006-01-02"), marginLeft, false},
+			{entry.Kind, 160, false},
+			{strconv.FormatInt(entry.Amount, 10), 420, true},
+			{strconv.FormatInt(entry.BalanceAfter, 10), marginRight, true},
+		})
+	}
+	l.y -= lineHeight
+
+	l.heading("Transfers", []cell{{"Date", marginLeft, false}, {"From", 130, false}, {"To", 210, false}, {"Amount", 380, true}, {"Memo", 395, false}})
+	if len(s.Transfers) == 0 {
+		l.row([]cell{{"No transfers this month.", marginLeft, false}})
+	}
+	for _, transfer := range s.Transfers {
+		// Each side sees the amount in its own currency, as its entry has it.
+		amount := transfer.ToAmount
+		if transfer.FromAccountID == s.Account.ID {
+			amount = -transfer.Amount
+		}
+		l.row([]cell{
+			{transfer.CreatedAt.UTC().Format("2006-01-02"), marginLeft, false},
+			{strconv.FormatInt(transfer.FromAccountID, 10), 130, false},
+			{strconv.FormatInt(transfer.ToAccountID, 10), 210, false},
+			{strconv.FormatInt(amount, 10), 380, true},
+			{truncate(transfer.Memo, maxMemoLength), 395, false},
+		})
+	}
+
+	// Footers go on last, once the number of pages is known.
+	generated := "Generated " + s.GeneratedAt.UTC().Format("2006-01-02 15:04 MST")
+	for i, p := range l.pages {
+		p.color(0.4, 0.4, 0.4)
+		p.text(fontRegular, 8, marginLeft, 30, generated)
+		p.textRight(fontRegular, 8, marginRight, 30, fmt.Sprintf("Page %d of %d", i+1, len(l.pages)))
+	}
+	return writePDF(l.pages)
+}
+
+// cell is text in a table row, aligned left or right on x.
+type cell struct {
+	text  string
+	x     float64
+	right bool
+}
+
+// layout fills pages from the top down, starting a new one when the
+// current one is full.
+type layout struct {
+	pages []*page
+	y     float64
+}
+
+func (l *layout) current() *page {
+	return l.pages[len(l.pages)-1]
+}
+
+// newPage starts a page with the branded header band.
+func (l *layout) newPage() {
+	p := &page{}
+	l.pages = append(l.pages, p)
+
+	p.color(0.08, 0.30, 0.60)
+	p.rect(0, pageHeight-headerHeight, pageWidth, headerHeight)
+	p.color(1, 1, 1)
+	p.text(fontBold, 22, marginLeft, pageHeight-45, "SimpleBank")
+	p.textRight(fontRegular, 11, marginRight, pageHeight-42, "Monthly statement")
+	p.color(0, 0, 0)
+
+	l.y = pageHeight - headerHeight - 30
+}
+
+// reserve starts a new page unless height fits on the current one.
+func (l *layout) reserve(height float64) {
+	if l.y-height < marginBottom {
+		l.newPage()
+	}
+}
+
+func (l *layout) summary(label string, amount int64, currency string) {
+	l.reserve(lineHeight)
+	p := l.current()
+	p.text(fontRegular, 10, marginLeft, l.y, label)
+	p.textRight(fontBold, 10, 300, l.y, fmt.Sprintf("%d %s", amount, currency))
+	l.y -= lineHeight
+}
+
+// heading writes a section title and its column titles. It keeps them with
+// at least one row.
+func (l *layout) heading(title string, columns []cell) {
+	l.reserve(lineHeight*3 + 4)
+	p := l.current()
+	p.text(fontBold, 12, marginLeft, l.y, title)
+	l.y -= lineHeight * 1.25
+
+	p.color(0.92, 0.94, 0.97)
+	p.rect(marginLeft-4, l.y-4, marginRight-marginLeft+8, lineHeight)
+	p.color(0, 0, 0)
+	l.writeCells(fontBold, columns)
+	l.y -= lineHeight + 4
+}
+
+func (l *layout) row(cells []cell) {
+	l.reserve(lineHeight)
+	l.writeCells(fontRegular, cells)
+	l.y -= lineHeight
+}
+
+func (l *layout) writeCells(font string, cells []cell) {
+	p := l.current()
+	for _, c := range cells {
+		if c.right {
+			p.textRight(font, 9, c.x, l.y, c.text)
+		} else {
+			p.text(font, 9, c.x, l.y, c.text)
+		}
+	}
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
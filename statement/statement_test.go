@@ -0,0 +1,77 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	account := db.Account{ID: 7, Owner: "alice", Currency: "USD", Balance: 1_000}
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Enough entries to need more than one page.
+	var entries []db.Entry
+	for i := 0; i < 80; i++ {
+		entries = append(entries, db.Entry{ID: int64(i + 1), AccountID: account.ID, Amount: 10, BalanceAfter: 200 + int64(i+1)*10, Kind: "deposit", CreatedAt: month})
+	}
+
+	pdf := Render(Statement{
+		Account:        account,
+		Month:          month,
+		OpeningBalance: 200,
+		ClosingBalance: 1_000,
+		Entries:        entries,
+		Transfers: []db.ListOwnerTransfersRow{
+			{ID: 1, FromAccountID: account.ID, ToAccountID: 8, Amount: 5, Memo: "rent (march)", CreatedAt: month},
+		},
+		GeneratedAt: month.AddDate(0, 1, 0),
+	})
+
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	require.Contains(t, string(pdf), "(Statement for March 2024)")
+	require.Contains(t, string(pdf), `(rent \(march\))`)
+	// The running balance of the last entry is the closing balance.
+	require.Contains(t, string(pdf), "(1000)")
+	require.Regexp(t, `\(Page 2 of [2-9]\)`, string(pdf))
+
+	// Every offset in the cross-reference table points at its object.
+	xref := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf, -1)
+	require.NotEmpty(t, xref)
+	for i, match := range xref {
+		offset, err := strconv.Atoi(string(match[1]))
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(pdf[offset:], []byte(fmt.Sprintf("%d 0 obj", i+1))))
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	require.Equal(t, `a\(b\)c\\`, escapeText(`a(b)c\`))
+	require.Equal(t, "caf?", escapeText("café"))
+	require.Equal(t, "a?b", escapeText("a\nb"))
+}
+
+func TestRenderReceivedFX(t *testing.T) {
+	account := db.Account{ID: 8, Owner: "bob", Currency: "INR"}
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	pdf := Render(Statement{
+		Account: account,
+		Month:   month,
+		Transfers: []db.ListOwnerTransfersRow{
+			{ID: 1, FromAccountID: 7, ToAccountID: account.ID, Amount: 100, ToAmount: 8_250, CreatedAt: month},
+		},
+		GeneratedAt: month.AddDate(0, 1, 0),
+	})
+
+	// The recipient sees what it was credited, in its own currency.
+	require.Contains(t, string(pdf), "(8250)")
+	require.NotContains(t, string(pdf), "(100)")
+}
@@ -0,0 +1,148 @@
+package token
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// AsymmetricJWTMaker signs tokens with an ECDSA P-256 private key (ES256).
+// Unlike the symmetric makers, other services can verify its tokens with the
+// public key alone, which it publishes as a JSON Web Key Set.
+type AsymmetricJWTMaker struct {
+	privateKey *ecdsa.PrivateKey
+	keyID      string
+	claims     issuerClaims
+}
+
+// KeySetProvider is implemented by makers whose tokens can be verified with
+// published public keys.
+type KeySetProvider interface {
+	JWKS() JWKSet
+}
+
+// JWK is the public half of a signing key, as described in RFC 7517.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// JWKSet is served at /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewAsymmetricJWTMaker creates a maker from a PEM encoded EC private key on
+// the P-256 curve.
+func NewAsymmetricJWTMaker(privateKeyPEM []byte, opts ...MakerOption) (*AsymmetricJWTMaker, error) {
+	privateKey, err := jwt.ParseECPrivateKeyFromPEM(privateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse private key: %w", err)
+	}
+	if privateKey.Curve != elliptic.P256() {
+		return nil, errors.New("private key must use the P-256 curve")
+	}
+
+	maker := &AsymmetricJWTMaker{
+		privateKey: privateKey,
+		claims:     newIssuerClaims(opts),
+	}
+	maker.keyID, err = thumbprint(maker.publicJWK())
+	if err != nil {
+		return nil, err
+	}
+	return maker, nil
+}
+
+// CreateToken creates a new token for a specific username and duration
+func (maker *AsymmetricJWTMaker) CreateToken(username string, duration time.Duration, opts ...PayloadOption) (string, error) {
+	payload, err := NewPayload(username, duration, opts...)
+	if err != nil {
+		return "", err
+	}
+	maker.claims.stamp(payload)
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodES256, payload)
+	jwtToken.Header["kid"] = maker.keyID
+	return jwtToken.SignedString(maker.privateKey)
+}
+
+// VerifyToken checks if the token is valid or not
+func (maker *AsymmetricJWTMaker) VerifyToken(token string) (*Payload, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodES256 {
+			return nil, ErrInvalidToken
+		}
+		return &maker.privateKey.PublicKey, nil
+	}
+
+	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc)
+	if err != nil {
+		verr, ok := err.(*jwt.ValidationError)
+		if ok && errors.Is(verr.Inner, ErrExpiredToken) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	payload, ok := jwtToken.Claims.(*Payload)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	if err := maker.claims.check(payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// JWKS returns the public key tokens are verified with.
+func (maker *AsymmetricJWTMaker) JWKS() JWKSet {
+	return JWKSet{Keys: []JWK{maker.publicJWK()}}
+}
+
+func (maker *AsymmetricJWTMaker) publicJWK() JWK {
+	// Coordinates are left-padded to the curve size, as RFC 7518 requires.
+	size := (maker.privateKey.Curve.Params().BitSize + 7) / 8
+	x := make([]byte, size)
+	y := make([]byte, size)
+	maker.privateKey.X.FillBytes(x)
+	maker.privateKey.Y.FillBytes(y)
+
+	return JWK{
+		KeyType:   "EC",
+		Curve:     "P-256",
+		X:         base64.RawURLEncoding.EncodeToString(x),
+		Y:         base64.RawURLEncoding.EncodeToString(y),
+		Use:       "sig",
+		Algorithm: jwt.SigningMethodES256.Alg(),
+		KeyID:     maker.keyID,
+	}
+}
+
+// thumbprint is the RFC 7638 key ID: the hash of the required members of the
+// key, in lexicographic order, so verifiers can compute it too.
+func thumbprint(key JWK) (string, error) {
+	data, err := json.Marshal(struct {
+		Curve   string `json:"crv"`
+		KeyType string `json:"kty"`
+		X       string `json:"x"`
+		Y       string `json:"y"`
+	}{key.Curve, key.KeyType, key.X, key.Y})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,105 @@
+package token
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ankurdas111111/simplebank/util"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+)
+
+func randomECPrivateKeyPEM(t *testing.T, curve elliptic.Curve) []byte {
+	privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func TestAsymmetricJWTMaker(t *testing.T) {
+	maker, err := NewAsymmetricJWTMaker(randomECPrivateKeyPEM(t, elliptic.P256()))
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	duration := time.Minute
+
+	token, err := maker.CreateToken(username, duration)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+	require.WithinDuration(t, time.Now().Add(duration), payload.ExpiredAt, time.Second)
+}
+
+func TestAsymmetricJWTMakerJWKS(t *testing.T) {
+	maker, err := NewAsymmetricJWTMaker(randomECPrivateKeyPEM(t, elliptic.P256()))
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(util.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+
+	jwks := maker.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	key := jwks.Keys[0]
+	require.Equal(t, "EC", key.KeyType)
+	require.Equal(t, "ES256", key.Algorithm)
+
+	// Another service only has the published key.
+	x, err := base64.RawURLEncoding.DecodeString(key.X)
+	require.NoError(t, err)
+	y, err := base64.RawURLEncoding.DecodeString(key.Y)
+	require.NoError(t, err)
+	publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+
+	parsed, err := jwt.ParseWithClaims(token, &Payload{}, func(token *jwt.Token) (interface{}, error) {
+		require.Equal(t, key.KeyID, token.Header["kid"])
+		return publicKey, nil
+	})
+	require.NoError(t, err)
+	require.True(t, parsed.Valid)
+}
+
+func TestAsymmetricJWTMakerRejectsOtherKeys(t *testing.T) {
+	maker, err := NewAsymmetricJWTMaker(randomECPrivateKeyPEM(t, elliptic.P256()))
+	require.NoError(t, err)
+	other, err := NewAsymmetricJWTMaker(randomECPrivateKeyPEM(t, elliptic.P256()))
+	require.NoError(t, err)
+
+	token, err := other.CreateToken(util.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// An HMAC token must not be accepted, whatever its key.
+	symmetric, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+	token, err = symmetric.CreateToken(util.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = NewAsymmetricJWTMaker(randomECPrivateKeyPEM(t, elliptic.P384()))
+	require.Error(t, err)
+}
+
+func TestExpiredAsymmetricJWTToken(t *testing.T) {
+	maker, err := NewAsymmetricJWTMaker(randomECPrivateKeyPEM(t, elliptic.P256()))
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.ErrorIs(t, err, ErrExpiredToken)
+	require.Nil(t, payload)
+}
@@ -13,22 +13,24 @@ const minSecretKeySize =  32
 //JWT maker is a JSON web based token maker 
 type JWTMaker struct{
      secretKey string
+     claims issuerClaims
 }
 
 // NewJWTMaker creates a new JWTMaker
-func NewJWTMaker(secretKey string) (Maker, error){
+func NewJWTMaker(secretKey string, opts ...MakerOption) (Maker, error){
 	if(len(secretKey) < minSecretKeySize){
 		return nil, fmt.Errorf("Invalid Key size: Must be atleast %d 32 characters", minSecretKeySize)
 	}
-	return &JWTMaker{secretKey}, nil
+	return &JWTMaker{secretKey: secretKey, claims: newIssuerClaims(opts)}, nil
 }
 
 // CreateToken creates a new token for a specific username and duration
-func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, error){
-	payload,err := NewPayload(username, duration)
+func (maker *JWTMaker) CreateToken(username string, duration time.Duration, opts ...PayloadOption) (string, error){
+	payload,err := NewPayload(username, duration, opts...)
 	if err != nil{
 		return "",err
 	}
+	maker.claims.stamp(payload)
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
 	return jwtToken.SignedString([]byte(maker.secretKey))
 }
@@ -53,5 +55,8 @@ func (maker *JWTMaker) VerifyToken(token string) (*Payload, error){
 	if !ok{
 		return nil, ErrInvalidToken
 	}
+	if err := maker.claims.check(payload); err != nil{
+		return nil, err
+	}
 	return payload, nil
 }
\ No newline at end of file
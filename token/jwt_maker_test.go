@@ -65,4 +65,22 @@ func TestInvalidJWTokenAlgNone(t *testing.T){
 	require.Error(t, err)
 	require.EqualError(t, err, ErrInvalidToken.Error())
 	require.Nil(t, payload)
-}
\ No newline at end of file
+}
+func TestJWTMakerIssuerAudience(t *testing.T){
+	key := util.RandomString(32)
+	staging, err := NewJWTMaker(key, WithIssuer("staging"), WithAudience("staging"))
+	require.NoError(t, err)
+	prod, err := NewJWTMaker(key, WithIssuer("prod"), WithAudience("staging"))
+	require.NoError(t, err)
+
+	token, err := staging.CreateToken(util.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+
+	payload, err := staging.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, "staging", payload.Issuer)
+
+	payload, err = prod.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
@@ -5,7 +5,52 @@ import "time"
 // maker is a interface for managing tokens
 type Maker interface{
 	// CreateToken creates a new token for a specific username and duration
-	CreateToken(username string, duration time.Duration) (string, error)
+	CreateToken(username string, duration time.Duration, opts ...PayloadOption) (string, error)
 	// VerifyToken checks if the token is valid or not
 	VerifyToken(token string) (*Payload, error)
-}
\ No newline at end of file
+}
+
+// MakerOption configures the issuer and audience a Maker stamps on new tokens
+// and requires on verified ones.
+type MakerOption func(claims *issuerClaims)
+
+// WithIssuer sets the iss claim, e.g. the deployment that mints the tokens.
+func WithIssuer(issuer string) MakerOption {
+	return func(claims *issuerClaims) {
+		claims.issuer = issuer
+	}
+}
+
+// WithAudience sets the aud claim, e.g. the deployment the tokens are for.
+func WithAudience(audience string) MakerOption {
+	return func(claims *issuerClaims) {
+		claims.audience = audience
+	}
+}
+
+// issuerClaims keeps tokens minted by one environment (say staging) from
+// being replayed against another (say prod) that shares the key.
+type issuerClaims struct {
+	issuer   string
+	audience string
+}
+
+func newIssuerClaims(opts []MakerOption) issuerClaims {
+	var claims issuerClaims
+	for _, opt := range opts {
+		opt(&claims)
+	}
+	return claims
+}
+
+func (claims issuerClaims) stamp(payload *Payload) {
+	payload.Issuer = claims.issuer
+	payload.Audience = claims.audience
+}
+
+func (claims issuerClaims) check(payload *Payload) error {
+	if payload.Issuer != claims.issuer || payload.Audience != claims.audience {
+		return ErrInvalidToken
+	}
+	return nil
+}
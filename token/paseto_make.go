@@ -12,26 +12,29 @@ import (
 type PasetoMaker struct{
 	paseto *paseto.V2
 	symetricKey []byte
+	claims issuerClaims
 }
 
 // NewPasetoMaker creates a new PasetoMaker
-func NewPasetoMaker(secretKey string) (Maker, error){
+func NewPasetoMaker(secretKey string, opts ...MakerOption) (Maker, error){
 	if len(secretKey) < minSecretKeySize{
 		return nil, fmt.Errorf("Invalid Key size: Must be exactly %d characters", chacha20poly1305.KeySize)
 	}
 	maker := &PasetoMaker{
 		paseto: paseto.NewV2(),
 		symetricKey: []byte(secretKey),
+		claims: newIssuerClaims(opts),
 	}
 	return maker, nil
 }
 
 // CreateToken creates a new token for a specific username and duration
-func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, error){
-	payload, err := NewPayload(username, duration)
+func (maker *PasetoMaker) CreateToken(username string, duration time.Duration, opts ...PayloadOption) (string, error){
+	payload, err := NewPayload(username, duration, opts...)
 	if err != nil{
 		return "", err
 	}
+	maker.claims.stamp(payload)
 
 	token, err := maker.paseto.Encrypt(maker.symetricKey, payload, nil)
 	if err != nil{
@@ -53,6 +56,11 @@ func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error){
 	if err != nil{
 		return nil, err
 	}
+
+	err = maker.claims.check(payload)
+	if err != nil{
+		return nil, err
+	}
 	return payload, nil
 }	
 
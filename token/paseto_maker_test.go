@@ -61,4 +61,72 @@ func TestInvalidPasetoToken(t *testing.T){
 	payload, err := maker.VerifyToken(token + "invalid")
 	require.Error(t, err)
 	require.Nil(t, payload)
-}
\ No newline at end of file
+}
+func TestPasetoMakerScopes(t *testing.T){
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(util.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, AllScopes(), payload.Scopes)
+
+	token, err = maker.CreateToken(util.RandomOwner(), time.Minute, WithScopes(ScopeAccountsRead))
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.True(t, payload.HasScope(ScopeAccountsRead))
+	require.False(t, payload.HasScope(ScopeTransfersWrite))
+}
+
+func TestPasetoMakerDevice(t *testing.T){
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(util.RandomOwner(), time.Minute, WithDevice("device-1"))
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotContains(t, payload.DeviceHash, "device-1")
+	require.True(t, payload.MatchesDevice("device-1"))
+	require.False(t, payload.MatchesDevice("device-2"))
+	require.False(t, payload.MatchesDevice(""))
+}
+
+func TestPasetoMakerImpersonation(t *testing.T){
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	admin := util.RandomOwner()
+	token, err := maker.CreateToken(util.RandomOwner(), time.Minute, WithImpersonator(admin))
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.True(t, payload.IsImpersonation())
+	require.Equal(t, admin, payload.ImpersonatedBy)
+}
+
+func TestPasetoMakerIssuerAudience(t *testing.T){
+	key := util.RandomString(32)
+	staging, err := NewPasetoMaker(key, WithIssuer("staging"), WithAudience("staging"))
+	require.NoError(t, err)
+	prod, err := NewPasetoMaker(key, WithIssuer("prod"), WithAudience("prod"))
+	require.NoError(t, err)
+
+	token, err := staging.CreateToken(util.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+
+	payload, err := staging.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, "staging", payload.Issuer)
+	require.Equal(t, "staging", payload.Audience)
+
+	payload, err = prod.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
@@ -1,6 +1,9 @@
 package token
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -11,6 +14,7 @@ import (
 var (
 	ErrExpiredToken =  errors.New("Token has expired")
 	ErrInvalidToken = errors.New("Token is invalid")
+	ErrDeviceMismatch = errors.New("Token was issued to another device")
 )
 
 type Payload struct{
@@ -18,9 +22,21 @@ type Payload struct{
 	Username string `json:"username"`
 	IssuedAt time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
+	Issuer string `json:"iss"`
+	Audience string `json:"aud"`
+	Scopes []string `json:"scopes"`
+	// DeviceHash binds the token to the device it was issued to. Empty for
+	// tokens that are not bound, such as integration tokens.
+	DeviceHash string `json:"device_hash,omitempty"`
+	// Elevated is set on short-lived tokens issued after the user re-entered
+	// their password or a TOTP code. Large transfers require it.
+	Elevated bool `json:"elevated,omitempty"`
+	// ImpersonatedBy is the admin who minted the token to act as Username.
+	// Everything done with such a token is written to the audit log.
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
 }
 // NewPayload creates a new token and payload with a specific username and duration
-func NewPayload(username string, duration time.Duration) (*Payload, error){
+func NewPayload(username string, duration time.Duration, opts ...PayloadOption) (*Payload, error){
 	tokenID, err := uuid.NewRandom()
 
 	if err!=nil{
@@ -32,6 +48,10 @@ func NewPayload(username string, duration time.Duration) (*Payload, error){
 		Username: username,
 		IssuedAt: time.Now(),
 		ExpiredAt: time.Now().Add(duration),
+		Scopes: AllScopes(),
+	}
+	for _, opt := range opts {
+		opt(payload)
 	}
 
 	return payload,nil
@@ -43,4 +63,56 @@ if time.Now().After(payload.ExpiredAt){
 	return ErrExpiredToken
 }
 	return nil
-}
\ No newline at end of file
+}
+
+// HasScope returns true if the token was granted the scope
+func (payload *Payload) HasScope(scope string) bool {
+	for _, s := range payload.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesDevice returns true if the token is unbound or was issued to the
+// device with this fingerprint
+func (payload *Payload) MatchesDevice(fingerprint string) bool {
+	if payload.DeviceHash == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(payload.DeviceHash), []byte(HashDevice(fingerprint))) == 1
+}
+
+// HashDevice hashes a client device fingerprint so the raw value never ends up
+// in a token
+func HashDevice(fingerprint string) string {
+	sum := sha256.Sum256([]byte(fingerprint))
+	return hex.EncodeToString(sum[:])
+}
+
+// WithDevice binds the token to a device fingerprint. Only its hash is stored.
+func WithDevice(fingerprint string) PayloadOption {
+	return func(payload *Payload) {
+		payload.DeviceHash = HashDevice(fingerprint)
+	}
+}
+
+// WithElevation marks the token as obtained through step-up authentication.
+func WithElevation() PayloadOption {
+	return func(payload *Payload) {
+		payload.Elevated = true
+	}
+}
+
+// WithImpersonator marks the token as used by an admin acting as the user.
+func WithImpersonator(admin string) PayloadOption {
+	return func(payload *Payload) {
+		payload.ImpersonatedBy = admin
+	}
+}
+
+// IsImpersonation returns true if an admin is acting through the token
+func (payload *Payload) IsImpersonation() bool {
+	return payload.ImpersonatedBy != ""
+}
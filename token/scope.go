@@ -0,0 +1,48 @@
+package token
+
+// Scopes that can be granted to a token. A token may only call the endpoints
+// whose scope it carries.
+const (
+	ScopeAccountsRead   = "accounts:read"
+	ScopeAccountsWrite  = "accounts:write"
+	ScopeTransfersRead  = "transfers:read"
+	ScopeTransfersWrite = "transfers:write"
+	ScopeKYCRead        = "kyc:read"
+	ScopeKYCWrite       = "kyc:write"
+	ScopeTokensWrite    = "tokens:write"
+	ScopeAdmin          = "admin"
+)
+
+// AllScopes returns every scope. Tokens get these unless WithScopes is used.
+func AllScopes() []string {
+	return []string{
+		ScopeAccountsRead,
+		ScopeAccountsWrite,
+		ScopeTransfersRead,
+		ScopeTransfersWrite,
+		ScopeKYCRead,
+		ScopeKYCWrite,
+		ScopeTokensWrite,
+		ScopeAdmin,
+	}
+}
+
+// IsValidScope returns true if the scope is known
+func IsValidScope(scope string) bool {
+	for _, s := range AllScopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PayloadOption customizes a payload created by NewPayload.
+type PayloadOption func(payload *Payload)
+
+// WithScopes restricts the token to the given scopes.
+func WithScopes(scopes ...string) PayloadOption {
+	return func(payload *Payload) {
+		payload.Scopes = append([]string{}, scopes...)
+	}
+}
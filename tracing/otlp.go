@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// NewOTLPProvider returns a tracer provider exporting spans to the
+// OpenTelemetry collector at endpoint, e.g. http://otel-collector:4318,
+// over OTLP/HTTP, with its spans attributed to service. Ended spans are
+// exported in batches every few seconds, so tracing never waits on the
+// collector. Shutdown exports the last batch.
+func NewOTLPProvider(endpoint, service string, opts ...sdktrace.TracerProviderOption) (*sdktrace.TracerProvider, error) {
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(strings.TrimSuffix(endpoint, "/")+"/v1/traces"))
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(service))),
+	}, opts...)
+	return sdktrace.NewTracerProvider(opts...), nil
+}
@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestCollector returns a collector that keeps the spans exported to it.
+func newTestCollector(t *testing.T) (*httptest.Server, *[]*tracepb.Span) {
+	var spans []*tracepb.Span
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/traces", r.URL.Path)
+		require.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var req collectortrace.ExportTraceServiceRequest
+		require.NoError(t, proto.Unmarshal(body, &req))
+		require.Len(t, req.ResourceSpans, 1)
+		attributes := req.ResourceSpans[0].Resource.Attributes
+		require.Equal(t, "service.name", attributes[0].Key)
+		require.Equal(t, "simplebank", attributes[0].Value.GetStringValue())
+		for _, scopeSpans := range req.ResourceSpans[0].ScopeSpans {
+			spans = append(spans, scopeSpans.Spans...)
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(collector.Close)
+	return collector, &spans
+}
+
+func TestOTLPExport(t *testing.T) {
+	collector, spans := newTestCollector(t)
+	provider, err := NewOTLPProvider(collector.URL+"/", "simplebank")
+	require.NoError(t, err)
+	tracer := NewTracer(provider)
+
+	ctx, root := tracer.Start(context.Background(), "TransferTx")
+	_, tx := tracer.Start(ctx, "tx")
+	tx.SetAttribute("tx.attempt", 1)
+	tx.SetAttribute("tx.outcome", "conflict")
+	tx.End(errors.New("deadlock detected"))
+	root.End(nil)
+
+	require.NoError(t, provider.Shutdown(context.Background()))
+	require.Len(t, *spans, 2)
+	exportedTx, exportedRoot := (*spans)[0], (*spans)[1]
+
+	require.Equal(t, "TransferTx", exportedRoot.Name)
+	require.Len(t, exportedRoot.TraceId, 16)
+	require.Len(t, exportedRoot.SpanId, 8)
+	require.Empty(t, exportedRoot.ParentSpanId)
+	require.Equal(t, tracepb.Status_STATUS_CODE_UNSET, exportedRoot.Status.GetCode())
+
+	require.Equal(t, "tx", exportedTx.Name)
+	require.Equal(t, exportedRoot.TraceId, exportedTx.TraceId)
+	require.Equal(t, exportedRoot.SpanId, exportedTx.ParentSpanId)
+	require.Equal(t, int64(1), exportedTx.Attributes[0].Value.GetIntValue())
+	require.Equal(t, "conflict", exportedTx.Attributes[1].Value.GetStringValue())
+	require.Equal(t, tracepb.Status_STATUS_CODE_ERROR, exportedTx.Status.GetCode())
+	require.Equal(t, "deadlock detected", exportedTx.Status.GetMessage())
+}
+
+func TestOTLPContinuesRemoteTrace(t *testing.T) {
+	collector, spans := newTestCollector(t)
+	provider, err := NewOTLPProvider(collector.URL, "simplebank")
+	require.NoError(t, err)
+	tracer := NewTracer(provider)
+
+	ctx := Extract(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, span := tracer.Start(ctx, "GET /accounts/:id")
+	span.End(nil)
+
+	require.NoError(t, provider.Shutdown(context.Background()))
+	require.Len(t, *spans, 1)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", hex.EncodeToString((*spans)[0].TraceId))
+	require.Equal(t, "00f067aa0ba902b7", hex.EncodeToString((*spans)[0].ParentSpanId))
+}
+
+func TestOTLPSkipsUnsampledTrace(t *testing.T) {
+	collector, spans := newTestCollector(t)
+	provider, err := NewOTLPProvider(collector.URL, "simplebank")
+	require.NoError(t, err)
+	tracer := NewTracer(provider)
+
+	ctx := Extract(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	ctx, span := tracer.Start(ctx, "GET /accounts/:id")
+	_, child := tracer.Start(ctx, "GetAccount")
+	child.End(nil)
+	span.End(nil)
+
+	require.NoError(t, provider.Shutdown(context.Background()))
+	require.Empty(t, *spans)
+}
+
+func TestMalformedTraceparentStartsTrace(t *testing.T) {
+	for _, header := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+	} {
+		ctx := Extract(context.Background(), header)
+		require.Equal(t, context.Background(), ctx, header)
+	}
+}
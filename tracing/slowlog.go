@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SlowLogProcessor keeps the spans of a trace in memory until its root span
+// ends and logs the trace if it took at least threshold. A root span is one
+// without a parent in this process, so the trace of a request is logged
+// apart from that of its caller.
+type SlowLogProcessor struct {
+	threshold time.Duration
+	logf      func(format string, args ...any)
+
+	mu sync.Mutex
+	// traces finds the spans of a trace from the ID of any of them.
+	traces map[trace.SpanID]*slowTrace
+}
+
+type slowTrace struct {
+	spans []sdktrace.ReadOnlySpan
+}
+
+func NewSlowLogProcessor(threshold time.Duration) *SlowLogProcessor {
+	return &SlowLogProcessor{
+		threshold: threshold,
+		logf:      log.Printf,
+		traces:    make(map[trace.SpanID]*slowTrace),
+	}
+}
+
+// OnStart implements sdktrace.SpanProcessor.
+func (processor *SlowLogProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+
+	t, ok := processor.traces[s.Parent().SpanID()]
+	if isRoot(s) {
+		t = &slowTrace{}
+	} else if !ok {
+		// A span started after its root ended isn't in any trace.
+		return
+	}
+	t.spans = append(t.spans, s)
+	processor.traces[s.SpanContext().SpanID()] = t
+}
+
+// OnEnd implements sdktrace.SpanProcessor.
+func (processor *SlowLogProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !isRoot(s) {
+		return
+	}
+	processor.mu.Lock()
+	t := processor.traces[s.SpanContext().SpanID()]
+	if t == nil {
+		processor.mu.Unlock()
+		return
+	}
+	for _, span := range t.spans {
+		delete(processor.traces, span.SpanContext().SpanID())
+	}
+	processor.mu.Unlock()
+
+	if s.EndTime().Sub(s.StartTime()) >= processor.threshold {
+		var b strings.Builder
+		format(&b, s, t.spans, 0)
+		processor.logf("slow trace:\n%s", b.String())
+	}
+}
+
+func (processor *SlowLogProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (processor *SlowLogProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func isRoot(s sdktrace.ReadOnlySpan) bool {
+	return !s.Parent().IsValid() || s.Parent().IsRemote()
+}
+
+// format writes a span and its children among spans, one per line and
+// indented by depth. Spans still running when the trace ended have no
+// duration.
+func format(b *strings.Builder, s sdktrace.ReadOnlySpan, spans []sdktrace.ReadOnlySpan, depth int) {
+	fmt.Fprintf(b, "%s%s", strings.Repeat("  ", depth), s.Name())
+	if s.EndTime().IsZero() {
+		b.WriteString(" (not ended)")
+	} else {
+		fmt.Fprintf(b, " %s", s.EndTime().Sub(s.StartTime()))
+	}
+	for _, attribute := range s.Attributes() {
+		fmt.Fprintf(b, " %s=%s", attribute.Key, attribute.Value.Emit())
+	}
+	if s.Status().Description != "" {
+		fmt.Fprintf(b, " error=%q", s.Status().Description)
+	}
+	b.WriteString("\n")
+	for _, child := range spans {
+		if child.Parent().SpanID() == s.SpanContext().SpanID() && !isRoot(child) {
+			format(b, child, spans, depth+1)
+		}
+	}
+}
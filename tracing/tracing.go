@@ -0,0 +1,100 @@
+// Package tracing records traces of requests and store calls with the
+// OpenTelemetry SDK. Tracer adapts an OpenTelemetry tracer to db.Tracer, and
+// what happens to the spans is up to the processors of its provider:
+// SlowLogProcessor logs slow traces with every span they are made of, so a
+// slow TransferTx shows which statement it spent its time on, and
+// NewOTLPProvider exports them all to an OpenTelemetry collector.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer spans are made with.
+const instrumentationName = "github.com/ankurdas111111/simplebank/tracing"
+
+// SpanKey is the context key of the current span. It is a string, like
+// db.AuditInfoKey, so that a span stored among the keys of a gin context is
+// found by the store calls handlers make with it. OpenTelemetry's own key
+// isn't: a gin context only looks up string keys.
+const SpanKey = "trace_span"
+
+// Tracer starts OpenTelemetry spans for db.Tracer.
+type Tracer struct {
+	tracer trace.Tracer
+	now    func() time.Time
+}
+
+// NewTracer returns a tracer whose spans go to the processors of provider.
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	return &Tracer{
+		tracer: provider.Tracer(instrumentationName),
+		now:    time.Now,
+	}
+}
+
+// Start implements db.Tracer. A span without a parent in ctx continues the
+// trace Extract put there, if any.
+func (tracer *Tracer) Start(ctx context.Context, name string) (context.Context, db.Span) {
+	if parent, ok := ctx.Value(SpanKey).(trace.Span); ok {
+		ctx = trace.ContextWithSpan(ctx, parent)
+	}
+	ctx, s := tracer.tracer.Start(ctx, name, trace.WithTimestamp(tracer.now()))
+	return context.WithValue(ctx, SpanKey, s), &span{span: s, now: tracer.now}
+}
+
+type span struct {
+	span trace.Span
+	now  func() time.Time
+}
+
+func (s *span) SetAttribute(key string, value any) {
+	s.span.SetAttributes(newAttribute(key, value))
+}
+
+// End implements db.Span. Spans only end once.
+func (s *span) End(err error) {
+	if err != nil {
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End(trace.WithTimestamp(s.now()))
+}
+
+func newAttribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	}
+	return attribute.String(key, fmt.Sprint(value))
+}
+
+// TraceparentHeader carries the caller's span, in the W3C Trace Context
+// format.
+const TraceparentHeader = "traceparent"
+
+// Extract returns a copy of ctx in which the span of a traceparent header is
+// the parent of the next root span, so that it continues the caller's trace.
+// A missing or malformed header leaves ctx as it is and the next span starts
+// a trace of its own.
+func Extract(ctx context.Context, traceparent string) context.Context {
+	carrier := propagation.MapCarrier{TraceparentHeader: traceparent}
+	return propagation.TraceContext{}.Extract(ctx, carrier)
+}
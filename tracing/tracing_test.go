@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// newTestTracer returns a tracer logging slow traces, whose clock moves by a
+// millisecond every time it is read, and the traces it logged.
+func newTestTracer(threshold time.Duration) (*Tracer, *[]string) {
+	var logged []string
+	processor := NewSlowLogProcessor(threshold)
+	processor.logf = func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+	tracer := NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor)))
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracer.now = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+	return tracer, &logged
+}
+
+func TestSlowTraceIsLogged(t *testing.T) {
+	tracer, logged := newTestTracer(5 * time.Millisecond)
+
+	ctx, root := tracer.Start(context.Background(), "TransferTx")
+	txCtx, tx := tracer.Start(ctx, "tx")
+	tx.SetAttribute("tx.attempt", 1)
+	_, statement := tracer.Start(txCtx, "CreateTransfer")
+	statement.End(nil)
+	_, statement = tracer.Start(txCtx, "AddAccountBalance")
+	statement.End(errors.New("deadlock detected"))
+	tx.SetAttribute("tx.outcome", "conflict")
+	tx.End(errors.New("deadlock detected"))
+	root.End(nil)
+
+	require.Equal(t, []string{`slow trace:
+TransferTx 7ms
+  tx 5ms tx.attempt=1 tx.outcome=conflict error="deadlock detected"
+    CreateTransfer 1ms
+    AddAccountBalance 1ms error="deadlock detected"
+`}, *logged)
+}
+
+func TestFastTraceIsNotLogged(t *testing.T) {
+	tracer, logged := newTestTracer(time.Second)
+
+	ctx, root := tracer.Start(context.Background(), "GetAccount")
+	_, child := tracer.Start(ctx, "GetAccount")
+	child.End(nil)
+	root.End(nil)
+
+	require.Empty(t, *logged)
+}
+
+func TestUnendedSpan(t *testing.T) {
+	tracer, logged := newTestTracer(0)
+
+	ctx, root := tracer.Start(context.Background(), "TransferTx")
+	tracer.Start(ctx, "tx")
+	root.End(nil)
+	// Ending twice logs once.
+	root.End(nil)
+
+	require.Len(t, *logged, 1)
+	require.Contains(t, (*logged)[0], "  tx (not ended)\n")
+}
+
+func TestRemoteParentStartsLocalTrace(t *testing.T) {
+	tracer, logged := newTestTracer(0)
+
+	ctx := Extract(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, root := tracer.Start(ctx, "GET /accounts/:id")
+	_, child := tracer.Start(ctx, "GetAccount")
+	child.End(nil)
+	root.End(nil)
+
+	require.Equal(t, []string{`slow trace:
+GET /accounts/:id 3ms
+  GetAccount 1ms
+`}, *logged)
+}
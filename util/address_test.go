@@ -0,0 +1,35 @@
+package util
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"93.184.215.14", "8.8.8.8", "2606:4700::1111"} {
+		require.True(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{
+		"127.0.0.1",
+		"10.1.2.3",
+		"172.16.0.1",
+		"192.168.1.1",
+		"169.254.169.254",
+		"100.64.0.1",
+		"0.0.0.0",
+		"255.255.255.255",
+		"224.0.0.1",
+		"::1",
+		"::",
+		"fe80::1",
+		"fd00::1",
+		"::ffff:127.0.0.1",
+		"::ffff:169.254.169.254",
+		"64:ff9b::a00:1",
+	} {
+		require.False(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+	require.False(t, IsPublicIP(nil))
+}
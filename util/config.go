@@ -11,11 +11,116 @@ import (
 //this config file stores all the configurations of the application
 //The values are read by viper from a config file or environment variable
 type Config struct {
+	Environment string `mapstructure:"ENVIRONMENT"`
 	DBdriver string `mapstructure:"DB_DRIVER"`
 	DBsource string `mapstructure:"DB_SOURCE"`
+	// Optional read replica. Reads fall back to the primary while it lags more
+	// than ReplicaMaxLagBytes of WAL or its health check fails.
+	DBReplicaSource string `mapstructure:"DB_REPLICA_SOURCE"`
+	ReplicaMaxLagBytes int64 `mapstructure:"REPLICA_MAX_LAG_BYTES"`
+	ReplicaHealthInterval time.Duration `mapstructure:"REPLICA_HEALTH_INTERVAL"`
 	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	// How long in-flight requests may take to finish once the server is
+	// told to stop, before their connections are closed.
+	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	// Requests are logged from this level up: debug, info, warn or error.
+	// Successful requests are info and health probes debug.
+	LogLevel string `mapstructure:"LOG_LEVEL"`
 	TokenSymmetricKey string `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	// PEM file with a P-256 EC private key. When set, tokens are signed with it
+	// instead of the symmetric key and its public half is served as a JWKS.
+	TokenPrivateKeyFile string `mapstructure:"TOKEN_PRIVATE_KEY_FILE"`
+	// Tokens carry these as iss/aud and are rejected by deployments configured differently.
+	TokenIssuer string `mapstructure:"TOKEN_ISSUER"`
+	TokenAudience string `mapstructure:"TOKEN_AUDIENCE"`
 	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	// bcrypt or argon2id. Existing hashes are upgraded on the user's next login.
+	PasswordHashAlgorithm string `mapstructure:"PASSWORD_HASH_ALGORITHM"`
+	// Policy for new passwords, see PasswordPolicy. Common passwords are always rejected.
+	PasswordMinLength int `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordRequireMixedCase bool `mapstructure:"PASSWORD_REQUIRE_MIXED_CASE"`
+	PasswordRequireDigit bool `mapstructure:"PASSWORD_REQUIRE_DIGIT"`
+	PasswordRequireSymbol bool `mapstructure:"PASSWORD_REQUIRE_SYMBOL"`
+	// Transfers above this INR-equivalent amount need an elevated token. Zero disables step-up.
+	ElevatedTransferThreshold int64 `mapstructure:"ELEVATED_TRANSFER_THRESHOLD"`
+	ElevatedTokenDuration time.Duration `mapstructure:"ELEVATED_TOKEN_DURATION"`
+	// Fault injection, only honoured when Environment is "development".
+	ChaosErrorRate float64 `mapstructure:"CHAOS_ERROR_RATE"`
+	ChaosLatency time.Duration `mapstructure:"CHAOS_LATENCY"`
+	ChaosDropCommitRate float64 `mapstructure:"CHAOS_DROP_COMMIT_RATE"`
+	// How often the retention rules are applied. Zero disables the job.
+	RetentionInterval time.Duration `mapstructure:"RETENTION_INTERVAL"`
+	RetentionDryRun bool `mapstructure:"RETENTION_DRY_RUN"`
+	// Outgoing email. Without an SMTP address emails are only logged.
+	SMTPAddress string `mapstructure:"SMTP_ADDRESS"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	EmailFrom string `mapstructure:"EMAIL_FROM"`
+	// Security alerts by text message. Without an account SID texts are only logged.
+	TwilioAccountSID string `mapstructure:"TWILIO_ACCOUNT_SID"`
+	TwilioAuthToken string `mapstructure:"TWILIO_AUTH_TOKEN"`
+	SMSFrom string `mapstructure:"SMS_FROM"`
+	// Outgoing transfers worth at least this much in INR are texted to the
+	// sender. Zero disables the alert.
+	SMSTransferThreshold int64 `mapstructure:"SMS_TRANSFER_THRESHOLD"`
+	// How often the worker polls for queued emails. Zero disables the worker.
+	EmailWorkerInterval time.Duration `mapstructure:"EMAIL_WORKER_INTERVAL"`
+	// How often failed text alerts are retried. Zero disables the worker.
+	NotificationWorkerInterval time.Duration `mapstructure:"NOTIFICATION_WORKER_INTERVAL"`
+	// How often due scheduled transfers are executed. Zero disables the scheduler.
+	ScheduledTransferInterval time.Duration `mapstructure:"SCHEDULED_TRANSFER_INTERVAL"`
+	// How often the worker polls for queued webhook deliveries. Zero disables the worker.
+	WebhookWorkerInterval time.Duration `mapstructure:"WEBHOOK_WORKER_INTERVAL"`
+	// How often interest is accrued for the previous day and completed months
+	// are posted. Runs are idempotent, so this can be shorter than a day. Zero
+	// disables interest.
+	InterestInterval time.Duration `mapstructure:"INTEREST_INTERVAL"`
+	// How often the closing balances of the previous day are snapshotted.
+	// Runs are idempotent, so this can be shorter than a day. Zero disables
+	// the job, which can still be run by hand with the snapshot-balances
+	// command.
+	BalanceSnapshotInterval time.Duration `mapstructure:"BALANCE_SNAPSHOT_INTERVAL"`
+	// Transfers and entries of months that ended more than this many months
+	// ago are archived, and statements no longer reach them. Zero keeps
+	// everything.
+	LedgerArchiveMonths int `mapstructure:"LEDGER_ARCHIVE_MONTHS"`
+	// How often every balance is recomputed from its entries and the entries
+	// of every transfer are checked. Zero disables the check, which can still
+	// be run by hand with the verify-ledger command.
+	LedgerCheckInterval time.Duration `mapstructure:"LEDGER_CHECK_INTERVAL"`
+	// Prometheus metrics are served at /metrics on this address, apart from
+	// the API so they aren't public. Empty disables them.
+	MetricsAddress string `mapstructure:"METRICS_ADDRESS"`
+	// Store calls are traced, and logged with their transactions and
+	// statements when they take at least this long. Zero disables tracing.
+	TraceSlowThreshold time.Duration `mapstructure:"TRACE_SLOW_THRESHOLD"`
+	// Requests and store calls are traced, continuing the traces of callers
+	// that send a W3C traceparent header, and exported to the OpenTelemetry
+	// collector at this OTLP/HTTP endpoint, e.g. http://otel-collector:4318.
+	// It takes over from TRACE_SLOW_THRESHOLD. Empty disables it.
+	OTLPEndpoint string `mapstructure:"OTLP_ENDPOINT"`
+	// How long a single query and a call that runs several statements, e.g. a
+	// transfer, may take before it is cancelled. Zero leaves them unbounded.
+	DBQueryTimeout time.Duration `mapstructure:"DB_QUERY_TIMEOUT"`
+	DBTxTimeout time.Duration `mapstructure:"DB_TX_TIMEOUT"`
+	// Isolation level transactions begin at, e.g. "repeatable read", unless
+	// they need a level of their own. Empty is the default of the database.
+	DBTxIsolation string `mapstructure:"DB_TX_ISOLATION"`
+	// Owners are notified when a transfer takes an account below this balance,
+	// in minor units. Zero disables the notification.
+	LowBalanceThreshold int64 `mapstructure:"LOW_BALANCE_THRESHOLD"`
+	// A user may request at most StatementEmailLimit statement emails per StatementEmailWindow.
+	StatementEmailLimit int64 `mapstructure:"STATEMENT_EMAIL_LIMIT"`
+	StatementEmailWindow time.Duration `mapstructure:"STATEMENT_EMAIL_WINDOW"`
+	// Passkeys are bound to this domain and only accepted from this origin.
+	// Passkey routes are disabled while the RP ID is empty.
+	WebAuthnRPID string `mapstructure:"WEBAUTHN_RP_ID"`
+	WebAuthnRPName string `mapstructure:"WEBAUTHN_RP_NAME"`
+	WebAuthnOrigin string `mapstructure:"WEBAUTHN_ORIGIN"`
+	// Each user, or client IP before login, may make RateLimitRequests requests
+	// per RateLimitWindow. Zero disables rate limiting.
+	RateLimitRequests int64 `mapstructure:"RATE_LIMIT_REQUESTS"`
+	RateLimitWindow time.Duration `mapstructure:"RATE_LIMIT_WINDOW"`
 }
 
 func LoadConfig(path string) (config Config,err  error){
@@ -25,11 +130,53 @@ func LoadConfig(path string) (config Config,err  error){
 	
 	viper.AutomaticEnv()
 	// Ensure env-only values are included when unmarshalling into Config.
+	_ = viper.BindEnv("ENVIRONMENT")
 	_ = viper.BindEnv("DB_DRIVER")
 	_ = viper.BindEnv("DB_SOURCE")
+	_ = viper.BindEnv("DB_REPLICA_SOURCE")
+	_ = viper.BindEnv("REPLICA_MAX_LAG_BYTES")
+	_ = viper.BindEnv("REPLICA_HEALTH_INTERVAL")
 	_ = viper.BindEnv("SERVER_ADDRESS")
+	_ = viper.BindEnv("SHUTDOWN_TIMEOUT")
+	_ = viper.BindEnv("LOG_LEVEL")
 	_ = viper.BindEnv("TOKEN_SYMMETRIC_KEY")
+	_ = viper.BindEnv("TOKEN_PRIVATE_KEY_FILE")
+	_ = viper.BindEnv("TOKEN_ISSUER")
+	_ = viper.BindEnv("TOKEN_AUDIENCE")
 	_ = viper.BindEnv("ACCESS_TOKEN_DURATION")
+	_ = viper.BindEnv("PASSWORD_HASH_ALGORITHM")
+	_ = viper.BindEnv("PASSWORD_MIN_LENGTH")
+	_ = viper.BindEnv("PASSWORD_REQUIRE_MIXED_CASE")
+	_ = viper.BindEnv("PASSWORD_REQUIRE_DIGIT")
+	_ = viper.BindEnv("PASSWORD_REQUIRE_SYMBOL")
+	_ = viper.BindEnv("ELEVATED_TRANSFER_THRESHOLD")
+	_ = viper.BindEnv("ELEVATED_TOKEN_DURATION")
+	_ = viper.BindEnv("CHAOS_ERROR_RATE")
+	_ = viper.BindEnv("CHAOS_LATENCY")
+	_ = viper.BindEnv("CHAOS_DROP_COMMIT_RATE")
+	_ = viper.BindEnv("RETENTION_INTERVAL")
+	_ = viper.BindEnv("RETENTION_DRY_RUN")
+	_ = viper.BindEnv("SMTP_ADDRESS")
+	_ = viper.BindEnv("SMTP_USERNAME")
+	_ = viper.BindEnv("SMTP_PASSWORD")
+	_ = viper.BindEnv("EMAIL_FROM")
+	_ = viper.BindEnv("TWILIO_ACCOUNT_SID")
+	_ = viper.BindEnv("TWILIO_AUTH_TOKEN")
+	_ = viper.BindEnv("SMS_FROM")
+	_ = viper.BindEnv("SMS_TRANSFER_THRESHOLD")
+	_ = viper.BindEnv("EMAIL_WORKER_INTERVAL")
+	_ = viper.BindEnv("NOTIFICATION_WORKER_INTERVAL")
+	_ = viper.BindEnv("SCHEDULED_TRANSFER_INTERVAL")
+	_ = viper.BindEnv("WEBHOOK_WORKER_INTERVAL")
+	_ = viper.BindEnv("INTEREST_INTERVAL")
+	_ = viper.BindEnv("LOW_BALANCE_THRESHOLD")
+	_ = viper.BindEnv("STATEMENT_EMAIL_LIMIT")
+	_ = viper.BindEnv("STATEMENT_EMAIL_WINDOW")
+	_ = viper.BindEnv("WEBAUTHN_RP_ID")
+	_ = viper.BindEnv("WEBAUTHN_RP_NAME")
+	_ = viper.BindEnv("WEBAUTHN_ORIGIN")
+	_ = viper.BindEnv("RATE_LIMIT_REQUESTS")
+	_ = viper.BindEnv("RATE_LIMIT_WINDOW")
 	_ = viper.BindEnv("PORT")
 
 	err = viper.ReadInConfig()
@@ -56,4 +203,8 @@ func LoadConfig(path string) (config Config,err  error){
 	}
 	return 
 
-}
\ No newline at end of file
+}
+
+// DevelopmentEnvironment is the ENVIRONMENT value of a local setup. Dev-only
+// features refuse to start anywhere else.
+const DevelopmentEnvironment = "development"
@@ -0,0 +1,25 @@
+package util
+
+// constants for all KYC tiers, from least to most verified
+const (
+	KYCTierBasic    = "basic"
+	KYCTierVerified = "verified"
+	KYCTierFull     = "full"
+)
+
+var kycTierRank = map[string]int{
+	KYCTierBasic:    1,
+	KYCTierVerified: 2,
+	KYCTierFull:     3,
+}
+
+// IsSupportedKYCTier returns true if the tier is known
+func IsSupportedKYCTier(tier string) bool {
+	_, ok := kycTierRank[tier]
+	return ok
+}
+
+// KYCTierAtLeast returns true if tier is the same as or above min
+func KYCTierAtLeast(tier, min string) bool {
+	return kycTierRank[tier] >= kycTierRank[min] && kycTierRank[tier] > 0
+}
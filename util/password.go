@@ -1,21 +1,161 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// hashPassword returns the bcrypt hash of the password
+// Supported password hashing algorithms. The algorithm is recoverable from the
+// hash string itself, so users hashed with different algorithms can coexist.
+const (
+	PasswordBcrypt   = "bcrypt"
+	PasswordArgon2id = "argon2id"
+)
+
+// DefaultPasswordAlgorithm is used when none is configured.
+const DefaultPasswordAlgorithm = PasswordBcrypt
+
+var (
+	// ErrPasswordMismatch is returned by CheckPassword for a wrong password,
+	// whatever the algorithm of the stored hash.
+	ErrPasswordMismatch         = bcrypt.ErrMismatchedHashAndPassword
+	ErrUnknownPasswordAlgorithm = errors.New("unknown password hashing algorithm")
+	errMalformedArgon2idHash    = errors.New("malformed argon2id hash")
+)
+
+// Argon2id parameters, following the RFC 9106 second recommended option.
+const (
+	argon2idTime    = 3
+	argon2idMemory  = 64 * 1024
+	argon2idThreads = 4
+	argon2idKeyLen  = 32
+	argon2idSaltLen = 16
+)
+
+// hashPassword returns the hash of the password with the default algorithm
 func HashPassword(password string) (string, error){
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return HashPasswordWith(DefaultPasswordAlgorithm, password)
+}
+
+// HashPasswordWith hashes the password with the given algorithm.
+func HashPasswordWith(algorithm string, password string) (string, error){
+	var hashedPassword string
+	var err error
+	switch algorithm {
+	case PasswordBcrypt:
+		var hash []byte
+		hash, err = bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		hashedPassword = string(hash)
+	case PasswordArgon2id:
+		hashedPassword, err = hashArgon2id(password)
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownPasswordAlgorithm, algorithm)
+	}
 	if err != nil{
 		return "",fmt.Errorf("Failed to hash password: %w", err)
 	}
-	return string(hashedPassword),nil
+	return hashedPassword,nil
 }
 
 // will check if the input password is same
 func CheckPassword(password string, hashedPassword string) error{
+	algorithm, err := PasswordAlgorithm(hashedPassword)
+	if err != nil {
+		return err
+	}
+	if algorithm == PasswordArgon2id {
+		return checkArgon2id(password, hashedPassword)
+	}
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword),[]byte(password))
-}
\ No newline at end of file
+}
+
+// IsPasswordAlgorithm reports whether passwords can be hashed with algorithm.
+func IsPasswordAlgorithm(algorithm string) bool {
+	return algorithm == PasswordBcrypt || algorithm == PasswordArgon2id
+}
+
+// PasswordAlgorithm tells which algorithm produced a stored hash.
+func PasswordAlgorithm(hashedPassword string) (string, error) {
+	switch {
+	case strings.HasPrefix(hashedPassword, "$argon2id$"):
+		return PasswordArgon2id, nil
+	case strings.HasPrefix(hashedPassword, "$2a$"), strings.HasPrefix(hashedPassword, "$2b$"), strings.HasPrefix(hashedPassword, "$2y$"):
+		return PasswordBcrypt, nil
+	}
+	return "", ErrUnknownPasswordAlgorithm
+}
+
+// NeedsRehash reports whether a hash was made with a different algorithm, or
+// weaker parameters, than the one configured now. Call it after a successful
+// CheckPassword, while the plaintext is still at hand.
+func NeedsRehash(hashedPassword string, algorithm string) bool {
+	current, err := PasswordAlgorithm(hashedPassword)
+	if err != nil || current != algorithm {
+		return true
+	}
+	if algorithm == PasswordBcrypt {
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err != nil || cost < bcrypt.DefaultCost
+	}
+	params, _, _, err := decodeArgon2id(hashedPassword)
+	return err != nil || params != fmt.Sprintf("m=%d,t=%d,p=%d", argon2idMemory, argon2idTime, argon2idThreads)
+}
+
+// hashArgon2id encodes the hash in the PHC string format used by the
+// reference implementation: $argon2id$v=19$m=..,t=..,p=..$salt$key
+func hashArgon2id(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, argon2idTime, argon2idMemory, argon2idThreads, argon2idKeyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argon2idMemory, argon2idTime, argon2idThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func checkArgon2id(password string, hashedPassword string) error {
+	params, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	var memory uint32
+	var iterations uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(params, "m=%d,t=%d,p=%d", &memory, &iterations, &threads); err != nil {
+		return errMalformedArgon2idHash
+	}
+
+	other := argon2.IDKey([]byte(password), salt, iterations, memory, threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+func decodeArgon2id(hashedPassword string) (params string, salt, key []byte, err error) {
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[2] != fmt.Sprintf("v=%d", argon2.Version) {
+		return "", nil, nil, errMalformedArgon2idHash
+	}
+
+	salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return "", nil, nil, errMalformedArgon2idHash
+	}
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return "", nil, nil, errMalformedArgon2idHash
+	}
+	return parts[3], salt, key, nil
+}
@@ -0,0 +1,89 @@
+package util
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+	"unicode"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+// commonPasswords is the built-in deny-list, stored lower-cased.
+var commonPasswords = loadCommonPasswords(commonPasswordList)
+
+func loadCommonPasswords(list string) map[string]struct{} {
+	passwords := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			passwords[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return passwords
+}
+
+// defaultPasswordMinLength matches what signup accepted before policies were
+// configurable.
+const defaultPasswordMinLength = 6
+
+// PasswordPolicy is what a new password must satisfy. Existing passwords are
+// not re-checked at login.
+type PasswordPolicy struct {
+	MinLength        int
+	RequireMixedCase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+}
+
+// PasswordPolicy returns the configured policy, falling back to the historic
+// minimum length when none is set.
+func (config Config) PasswordPolicy() PasswordPolicy {
+	policy := PasswordPolicy{
+		MinLength:        config.PasswordMinLength,
+		RequireMixedCase: config.PasswordRequireMixedCase,
+		RequireDigit:     config.PasswordRequireDigit,
+		RequireSymbol:    config.PasswordRequireSymbol,
+	}
+	if policy.MinLength <= 0 {
+		policy.MinLength = defaultPasswordMinLength
+	}
+	return policy
+}
+
+// Allows reports whether the password satisfies the policy. Common passwords
+// are always rejected, whatever else the policy requires.
+func (policy PasswordPolicy) Allows(password string) bool {
+	if len([]rune(password)) < policy.MinLength {
+		return false
+	}
+	if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+		return false
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if policy.RequireMixedCase && !(upper && lower) {
+		return false
+	}
+	if policy.RequireDigit && !digit {
+		return false
+	}
+	if policy.RequireSymbol && !symbol {
+		return false
+	}
+	return true
+}
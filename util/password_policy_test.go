@@ -0,0 +1,40 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	strict := PasswordPolicy{
+		MinLength:        10,
+		RequireMixedCase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+	}
+
+	testCases := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		allowed  bool
+	}{
+		{"DefaultLength", Config{}.PasswordPolicy(), "abcxyz", true},
+		{"DefaultTooShort", Config{}.PasswordPolicy(), "abcxy", false},
+		{"DenyListed", Config{}.PasswordPolicy(), "password", false},
+		{"DenyListedIgnoresCase", Config{}.PasswordPolicy(), "PassWord1", false},
+		{"StrictOK", strict, "Correct-Horse7", true},
+		{"StrictTooShort", strict, "Sh0rt!", false},
+		{"StrictNoUpper", strict, "correct-horse7", false},
+		{"StrictNoDigit", strict, "Correct-Horse", false},
+		{"StrictNoSymbol", strict, "CorrectHorse7", false},
+		{"LengthCountsRunes", PasswordPolicy{MinLength: 4}, "ééé", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.allowed, tc.policy.Allows(tc.password))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -25,3 +26,47 @@ func TestPassword(t *testing.T){
 	 require.NotEqual(t, HashedPassword1,HashedPassword2)
 }
 
+
+
+func TestArgon2idPassword(t *testing.T) {
+	password := RandomString(6)
+	hashedPassword, err := HashPasswordWith(PasswordArgon2id, password)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hashedPassword, "$argon2id$v=19$"))
+
+	algorithm, err := PasswordAlgorithm(hashedPassword)
+	require.NoError(t, err)
+	require.Equal(t, PasswordArgon2id, algorithm)
+
+	require.NoError(t, CheckPassword(password, hashedPassword))
+	require.ErrorIs(t, CheckPassword(RandomString(6), hashedPassword), ErrPasswordMismatch)
+
+	other, err := HashPasswordWith(PasswordArgon2id, password)
+	require.NoError(t, err)
+	require.NotEqual(t, hashedPassword, other)
+}
+
+func TestNeedsRehash(t *testing.T) {
+	password := RandomString(6)
+	bcryptHash, err := HashPasswordWith(PasswordBcrypt, password)
+	require.NoError(t, err)
+	argon2idHash, err := HashPasswordWith(PasswordArgon2id, password)
+	require.NoError(t, err)
+
+	require.False(t, NeedsRehash(bcryptHash, PasswordBcrypt))
+	require.True(t, NeedsRehash(bcryptHash, PasswordArgon2id))
+	require.False(t, NeedsRehash(argon2idHash, PasswordArgon2id))
+	require.True(t, NeedsRehash(argon2idHash, PasswordBcrypt))
+
+	// Weaker parameters than the current ones are upgraded too.
+	weak := strings.Replace(argon2idHash, "t=3", "t=1", 1)
+	require.True(t, NeedsRehash(weak, PasswordArgon2id))
+}
+
+func TestUnknownPasswordAlgorithm(t *testing.T) {
+	_, err := HashPasswordWith("md5", RandomString(6))
+	require.ErrorIs(t, err, ErrUnknownPasswordAlgorithm)
+
+	err = CheckPassword(RandomString(6), "5f4dcc3b5aa765d61d8327deb882cf99")
+	require.ErrorIs(t, err, ErrUnknownPasswordAlgorithm)
+}
@@ -0,0 +1,7 @@
+package util
+
+// constants for all user roles
+const (
+	CustomerRole = "customer"
+	AdminRole    = "admin"
+)
@@ -1,5 +1,6 @@
 const STORAGE_KEY = "simplebank_access_token";
 const LAST_ACTION_KEY = "simplebank_last_action";
+const DEVICE_KEY = "simplebank_device_id";
 
 function $(id) { return document.getElementById(id); }
 function has(id) { return !!$(id); }
@@ -10,6 +11,17 @@ function setToken(token) {
   else localStorage.setItem(STORAGE_KEY, token);
 }
 
+// Tokens are bound to the device that logged in, so every request must send
+// the same id.
+function getDeviceId() {
+  let id = localStorage.getItem(DEVICE_KEY);
+  if (!id) {
+    id = crypto.randomUUID();
+    localStorage.setItem(DEVICE_KEY, id);
+  }
+  return id;
+}
+
 function setLastAction(text) {
   if (!text) return;
   localStorage.setItem(LAST_ACTION_KEY, text);
@@ -47,7 +59,7 @@ function toast(message) {
 }
 
 async function apiFetch(path, { method = "GET", body } = {}) {
-  const headers = { "Content-Type": "application/json" };
+  const headers = { "Content-Type": "application/json", "X-Device-ID": getDeviceId() };
   const token = getToken();
   if (token) headers["Authorization"] = `Bearer ${token}`;
 
@@ -67,13 +79,13 @@ async function apiFetch(path, { method = "GET", body } = {}) {
 
   if (!res.ok) {
     const msg =
-      (data && data.error) ||
       (data && data.message) ||
       (typeof data === "string" ? data : "") ||
       res.statusText ||
       "Request failed";
     const err = new Error(msg);
     err.status = res.status;
+    err.code = data && data.code;
     err.body = data;
     throw err;
   }
@@ -122,8 +134,9 @@ function bindLogout() {
   });
 }
 
-async function loadAccounts({ pageId = 1, pageSize = 10 } = {}) {
-  return await apiFetch(`/accounts?page_id=${pageId}&page_size=${pageSize}`);
+async function loadAccounts({ limit = 10 } = {}) {
+  const page = await apiFetch(`/accounts?limit=${limit}`);
+  return page.items;
 }
 
 function renderAccountsTable(tbody, accounts, { onView } = {}) {
@@ -205,7 +218,7 @@ async function initDashboard() {
     try {
       clearBanner();
       tbody.innerHTML = `<tr><td colspan="3" class="muted">Loading…</td></tr>`;
-      const accounts = await loadAccounts({ pageId: 1, pageSize: 10 });
+      const accounts = await loadAccounts({ limit: 10 });
       const count = accounts.length;
       const totalsByCurrency = new Map();
       for (const a of accounts) {
@@ -289,7 +302,7 @@ async function initAccounts() {
     try {
       clearBanner();
       tbody.innerHTML = `<tr><td colspan="4" class="muted">Loading…</td></tr>`;
-      const accounts = await loadAccounts({ pageId: 1, pageSize: 10 });
+      const accounts = await loadAccounts({ limit: 10 });
       renderAccountsTable(tbody, accounts, { onView: viewAccount });
     } catch (err) {
       showBanner("error", err.message || "Failed to load accounts");
@@ -389,7 +402,7 @@ async function initTransfers() {
     if (!transfersTbody) return;
     transfersTbody.innerHTML = "";
     if (!items || items.length === 0) {
-      transfersTbody.innerHTML = `<tr><td colspan="5" class="muted">No transfers yet.</td></tr>`;
+      transfersTbody.innerHTML = `<tr><td colspan="6" class="muted">No transfers yet.</td></tr>`;
       return;
     }
 
@@ -413,8 +426,11 @@ async function initTransfers() {
         <td>${type}</td>
         <td>Acct ${t.from_account_id} <span class="muted small">${t.from_currency || ""}</span></td>
         <td>Acct ${t.to_account_id} <span class="muted small">${t.to_currency || ""}</span></td>
+        <td class="memo"></td>
         <td class="right">${amountText}</td>
       `;
+      // The memo is free text from the sender; never parse it as HTML.
+      tr.querySelector(".memo").textContent = t.memo || "";
       transfersTbody.appendChild(tr);
     }
   };
@@ -422,11 +438,11 @@ async function initTransfers() {
   const loadTransfers = async () => {
     if (!transfersTbody) return;
     try {
-      transfersTbody.innerHTML = `<tr><td colspan="5" class="muted">Loading…</td></tr>`;
-      const items = await apiFetch("/transfers?page_id=1&page_size=10");
-      renderTransfers(items);
+      transfersTbody.innerHTML = `<tr><td colspan="6" class="muted">Loading…</td></tr>`;
+      const page = await apiFetch("/transfers?limit=10");
+      renderTransfers(page.items);
     } catch (err) {
-      transfersTbody.innerHTML = `<tr><td colspan="5" class="muted">Failed to load transfers.</td></tr>`;
+      transfersTbody.innerHTML = `<tr><td colspan="6" class="muted">Failed to load transfers.</td></tr>`;
     }
   };
 
@@ -494,7 +510,7 @@ async function initTransfers() {
     try {
       clearBanner();
       fromSelect.innerHTML = `<option value="">Loading…</option>`;
-      accounts = await loadAccounts({ pageId: 1, pageSize: 10 });
+      accounts = await loadAccounts({ limit: 10 });
       fromSelect.innerHTML = `<option value="">Select an account</option>`;
       for (const a of accounts) {
         const opt = document.createElement("option");
@@ -618,7 +634,9 @@ async function initTransfers() {
         toUsername = (body.to_username || "").trim();
       }
 
-      if (!fromId || !toId || !amount) {
+      // Other users can be paid by username alone, into their account in the
+      // source currency.
+      if (!fromId || (!toId && !toUsername) || !amount) {
         showBanner("error", "Please fill in all fields.");
         return;
       }
@@ -628,7 +646,7 @@ async function initTransfers() {
       }
 
       // If transferring to others and a username was provided, require lookup match.
-      if (transferMode === "other" && toUsername) {
+      if (transferMode === "other" && toUsername && toId) {
         if (!recipientMeta || Number(recipientMeta.id) !== toId) {
           showBanner("error", "Please verify the recipient account ID (leave the field to validate).");
           return;
@@ -641,10 +659,12 @@ async function initTransfers() {
       try {
         const payload = {
           from_account_id: fromId,
-          to_account_id: toId,
           amount: Math.round(amount),
         };
+        if (toId) payload.to_account_id = toId;
         if (transferMode === "other" && toUsername) payload.to_username = toUsername;
+        if (!toId) payload.to_currency = body.currency;
+        if (body.memo && body.memo.trim()) payload.memo = body.memo.trim();
         await apiFetch("/transfers", { method: "POST", body: payload });
         setLastAction("Transfer sent");
         toast("Transfer sent");
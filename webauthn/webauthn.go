@@ -0,0 +1,219 @@
+// Package webauthn verifies passkey registrations and assertions (WebAuthn
+// Level 2) for a single relying party. Only the "none" attestation format is
+// accepted: the bank trusts the user's choice of authenticator.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ChallengeSize is the number of random bytes in a challenge.
+const ChallengeSize = 32
+
+var (
+	ErrInvalidClientData    = errors.New("invalid client data")
+	ErrInvalidAuthenticator = errors.New("invalid authenticator data")
+	ErrUnsupportedFormat    = errors.New("unsupported attestation format")
+	ErrInvalidSignature     = errors.New("invalid assertion signature")
+	// ErrCloneDetected means the signature counter went backwards, which
+	// happens when a credential's private key was copied.
+	ErrCloneDetected = errors.New("authenticator signature counter did not increase")
+)
+
+// Authenticator data flags.
+const (
+	flagUserPresent      = 0x01
+	flagUserVerified     = 0x04
+	flagAttestedCredData = 0x40
+)
+
+// RelyingParty is the site credentials are scoped to.
+type RelyingParty struct {
+	// ID is the domain credentials are bound to, e.g. "bank.example.com".
+	ID string
+	// Name is shown by the authenticator when creating a credential.
+	Name string
+	// Origin is the exact origin the browser reports, e.g. "https://bank.example.com".
+	Origin string
+}
+
+// Credential is what has to be stored to verify later assertions.
+type Credential struct {
+	ID        []byte
+	PublicKey []byte
+	SignCount uint32
+}
+
+// NewChallenge returns a random challenge for one ceremony.
+func NewChallenge() ([]byte, error) {
+	challenge := make([]byte, ChallengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+func (rp *RelyingParty) verifyClientData(clientDataJSON []byte, ceremony string, challenge []byte) error {
+	var data clientData
+	if err := json.Unmarshal(clientDataJSON, &data); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidClientData, err)
+	}
+	if data.Type != ceremony {
+		return fmt.Errorf("%w: type %q", ErrInvalidClientData, data.Type)
+	}
+	got, err := base64.RawURLEncoding.DecodeString(data.Challenge)
+	if err != nil || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", ErrInvalidClientData)
+	}
+	if data.Origin != rp.Origin {
+		return fmt.Errorf("%w: origin %q", ErrInvalidClientData, data.Origin)
+	}
+	return nil
+}
+
+type authenticatorData struct {
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+	// Only present during registration.
+	credentialID []byte
+	publicKey    []byte
+}
+
+func parseAuthenticatorData(data []byte) (authenticatorData, error) {
+	if len(data) < 37 {
+		return authenticatorData{}, fmt.Errorf("%w: too short", ErrInvalidAuthenticator)
+	}
+	auth := authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	if auth.flags&flagAttestedCredData == 0 {
+		return auth, nil
+	}
+
+	// aaguid (16) | credential ID length (2) | credential ID | COSE key
+	rest := data[37:]
+	if len(rest) < 18 {
+		return auth, fmt.Errorf("%w: truncated credential data", ErrInvalidAuthenticator)
+	}
+	idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if len(rest) < idLen {
+		return auth, fmt.Errorf("%w: truncated credential ID", ErrInvalidAuthenticator)
+	}
+	auth.credentialID = rest[:idLen]
+	rest = rest[idLen:]
+
+	// Extensions may follow the key, so its length comes from decoding it.
+	_, n, err := decodeCBOR(rest)
+	if err != nil {
+		return auth, fmt.Errorf("%w: credential public key: %v", ErrInvalidAuthenticator, err)
+	}
+	auth.publicKey = rest[:n]
+	return auth, nil
+}
+
+func (rp *RelyingParty) verifyAuthenticatorData(auth authenticatorData) error {
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(auth.rpIDHash, rpIDHash[:]) {
+		return fmt.Errorf("%w: credential is for another relying party", ErrInvalidAuthenticator)
+	}
+	if auth.flags&flagUserPresent == 0 {
+		return fmt.Errorf("%w: user was not present", ErrInvalidAuthenticator)
+	}
+	// Passkeys replace the password, so the authenticator must have checked
+	// the user with a PIN or biometric.
+	if auth.flags&flagUserVerified == 0 {
+		return fmt.Errorf("%w: user was not verified", ErrInvalidAuthenticator)
+	}
+	return nil
+}
+
+// VerifyRegistration checks the response to navigator.credentials.create()
+// and returns the new credential.
+func (rp *RelyingParty) VerifyRegistration(challenge, clientDataJSON, attestationObject []byte) (Credential, error) {
+	if err := rp.verifyClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return Credential{}, err
+	}
+
+	v, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return Credential{}, err
+	}
+	attestation, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return Credential{}, errMalformedCBOR
+	}
+	if format, _ := attestation["fmt"].(string); format != "none" {
+		return Credential{}, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	authData, _ := attestation["authData"].([]byte)
+
+	auth, err := parseAuthenticatorData(authData)
+	if err != nil {
+		return Credential{}, err
+	}
+	if err := rp.verifyAuthenticatorData(auth); err != nil {
+		return Credential{}, err
+	}
+	if auth.credentialID == nil {
+		return Credential{}, fmt.Errorf("%w: no attested credential", ErrInvalidAuthenticator)
+	}
+	if _, err := parsePublicKey(auth.publicKey); err != nil {
+		return Credential{}, err
+	}
+
+	return Credential{
+		ID:        auth.credentialID,
+		PublicKey: auth.publicKey,
+		SignCount: auth.signCount,
+	}, nil
+}
+
+// VerifyAssertion checks the response to navigator.credentials.get() against
+// a stored credential and returns the signature counter to store next.
+func (rp *RelyingParty) VerifyAssertion(challenge []byte, credential Credential, clientDataJSON, authenticatorDataBytes, signature []byte) (uint32, error) {
+	if err := rp.verifyClientData(clientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+
+	auth, err := parseAuthenticatorData(authenticatorDataBytes)
+	if err != nil {
+		return 0, err
+	}
+	if err := rp.verifyAuthenticatorData(auth); err != nil {
+		return 0, err
+	}
+
+	key, err := parsePublicKey(credential.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), authenticatorDataBytes...), clientDataHash[:]...)
+	if !key.verify(signed, signature) {
+		return 0, ErrInvalidSignature
+	}
+
+	// Authenticators that do not count always report zero.
+	if (auth.signCount != 0 || credential.SignCount != 0) && auth.signCount <= credential.SignCount {
+		return 0, ErrCloneDetected
+	}
+	return auth.signCount, nil
+}
@@ -0,0 +1,108 @@
+package webauthn
+
+import (
+	"testing"
+
+	"github.com/ankurdas111111/simplebank/webauthn/webauthntest"
+	"github.com/stretchr/testify/require"
+)
+
+var testRP = &RelyingParty{ID: "bank.example.com", Name: "SimpleBank", Origin: "https://bank.example.com"}
+
+func registerTestCredential(t *testing.T) (*webauthntest.Authenticator, Credential) {
+	authenticator, err := webauthntest.NewAuthenticator(testRP.ID, testRP.Origin)
+	require.NoError(t, err)
+
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+	clientDataJSON, attestationObject := authenticator.Register(challenge)
+
+	credential, err := testRP.VerifyRegistration(challenge, clientDataJSON, attestationObject)
+	require.NoError(t, err)
+	require.Equal(t, authenticator.CredentialID, credential.ID)
+	return authenticator, credential
+}
+
+func TestRegisterAndAssert(t *testing.T) {
+	authenticator, credential := registerTestCredential(t)
+
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+	clientDataJSON, authData, signature, err := authenticator.Assert(challenge)
+	require.NoError(t, err)
+
+	signCount, err := testRP.VerifyAssertion(challenge, credential, clientDataJSON, authData, signature)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), signCount)
+
+	// A replay of the same assertion carries the old counter.
+	credential.SignCount = signCount
+	_, err = testRP.VerifyAssertion(challenge, credential, clientDataJSON, authData, signature)
+	require.ErrorIs(t, err, ErrCloneDetected)
+}
+
+func TestVerifyRegistrationRejects(t *testing.T) {
+	authenticator, err := webauthntest.NewAuthenticator(testRP.ID, testRP.Origin)
+	require.NoError(t, err)
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+
+	t.Run("WrongChallenge", func(t *testing.T) {
+		clientDataJSON, attestationObject := authenticator.Register(challenge)
+		other, err := NewChallenge()
+		require.NoError(t, err)
+		_, err = testRP.VerifyRegistration(other, clientDataJSON, attestationObject)
+		require.ErrorIs(t, err, ErrInvalidClientData)
+	})
+
+	t.Run("WrongOrigin", func(t *testing.T) {
+		phishing, err := webauthntest.NewAuthenticator(testRP.ID, "https://bank.example.com.evil.test")
+		require.NoError(t, err)
+		clientDataJSON, attestationObject := phishing.Register(challenge)
+		_, err = testRP.VerifyRegistration(challenge, clientDataJSON, attestationObject)
+		require.ErrorIs(t, err, ErrInvalidClientData)
+	})
+
+	t.Run("WrongRelyingParty", func(t *testing.T) {
+		other, err := webauthntest.NewAuthenticator("evil.test", testRP.Origin)
+		require.NoError(t, err)
+		clientDataJSON, attestationObject := other.Register(challenge)
+		_, err = testRP.VerifyRegistration(challenge, clientDataJSON, attestationObject)
+		require.ErrorIs(t, err, ErrInvalidAuthenticator)
+	})
+
+	t.Run("UserNotVerified", func(t *testing.T) {
+		presentOnly, err := webauthntest.NewAuthenticator(testRP.ID, testRP.Origin)
+		require.NoError(t, err)
+		presentOnly.Flags = 0x01
+		clientDataJSON, attestationObject := presentOnly.Register(challenge)
+		_, err = testRP.VerifyRegistration(challenge, clientDataJSON, attestationObject)
+		require.ErrorIs(t, err, ErrInvalidAuthenticator)
+	})
+}
+
+func TestVerifyAssertionBadSignature(t *testing.T) {
+	authenticator, credential := registerTestCredential(t)
+
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+	clientDataJSON, authData, signature, err := authenticator.Assert(challenge)
+	require.NoError(t, err)
+	signature[len(signature)-1] ^= 0xff
+
+	_, err = testRP.VerifyAssertion(challenge, credential, clientDataJSON, authData, signature)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestDecodeCBORMalformed(t *testing.T) {
+	for _, data := range [][]byte{
+		{},
+		{0x5f},             // indefinite length byte string
+		{0x45, 0x01},       // byte string shorter than its length
+		{0xa1, 0x41, 0x00}, // map with a byte string key
+		{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, // huge array
+	} {
+		_, _, err := decodeCBOR(data)
+		require.ErrorIs(t, err, errMalformedCBOR, "%x", data)
+	}
+}
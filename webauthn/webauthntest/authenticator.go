@@ -0,0 +1,171 @@
+// Package webauthntest provides a software authenticator for tests of code
+// that registers and verifies passkeys.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+)
+
+// Authenticator holds a single ES256 credential and signs like a platform
+// authenticator that verified the user.
+type Authenticator struct {
+	RPID   string
+	Origin string
+	// CredentialID identifies the credential once Register was called.
+	CredentialID []byte
+	// SignCount is the counter last reported. Assert increments it first.
+	SignCount uint32
+	// Flags overrides the authenticator data flags when non-zero.
+	Flags byte
+
+	key *ecdsa.PrivateKey
+}
+
+// NewAuthenticator creates an authenticator for a relying party.
+func NewAuthenticator(rpID, origin string) (*Authenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		return nil, err
+	}
+	return &Authenticator{
+		RPID:         rpID,
+		Origin:       origin,
+		CredentialID: credentialID,
+		key:          key,
+	}, nil
+}
+
+// Register answers a registration challenge with a "none" attestation.
+func (a *Authenticator) Register(challenge []byte) (clientDataJSON, attestationObject []byte) {
+	clientDataJSON = a.clientData("webauthn.create", challenge)
+
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+	coseKey := encodeMap([]pair{
+		{int64(1), int64(2)},
+		{int64(3), int64(-7)},
+		{int64(-1), int64(1)},
+		{int64(-2), x},
+		{int64(-3), y},
+	})
+
+	authData := a.authData(0x40)
+	authData = append(authData, make([]byte, 16)...) // aaguid
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.CredentialID)))
+	authData = append(authData, a.CredentialID...)
+	authData = append(authData, coseKey...)
+
+	attestationObject = encodeMap([]pair{
+		{"fmt", "none"},
+		{"attStmt", []pair{}},
+		{"authData", authData},
+	})
+	return clientDataJSON, attestationObject
+}
+
+// Assert answers a login challenge and increments the signature counter.
+func (a *Authenticator) Assert(challenge []byte) (clientDataJSON, authenticatorData, signature []byte, err error) {
+	a.SignCount++
+	clientDataJSON = a.clientData("webauthn.get", challenge)
+	authenticatorData = a.authData(0)
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte(nil), authenticatorData...), clientDataHash[:]...))
+	signature, err = ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return clientDataJSON, authenticatorData, signature, nil
+}
+
+func (a *Authenticator) clientData(ceremony string, challenge []byte) []byte {
+	data, _ := json.Marshal(map[string]interface{}{
+		"type":        ceremony,
+		"challenge":   base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+	return data
+}
+
+func (a *Authenticator) authData(extraFlags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.RPID))
+	flags := a.Flags
+	if flags == 0 {
+		flags = 0x01 | 0x04 // user present and verified
+	}
+	data := append([]byte(nil), rpIDHash[:]...)
+	data = append(data, flags|extraFlags)
+	return binary.BigEndian.AppendUint32(data, a.SignCount)
+}
+
+type pair struct {
+	key   interface{}
+	value interface{}
+}
+
+// encodeMap writes a CBOR map in the canonical key order authenticators use.
+func encodeMap(pairs []pair) []byte {
+	encoded := make([][2][]byte, len(pairs))
+	for i, p := range pairs {
+		encoded[i] = [2][]byte{encode(p.key), encode(p.value)}
+	}
+	sort.Slice(encoded, func(i, j int) bool {
+		ki, kj := encoded[i][0], encoded[j][0]
+		if len(ki) != len(kj) {
+			return len(ki) < len(kj)
+		}
+		return string(ki) < string(kj)
+	})
+
+	out := head(5, uint64(len(pairs)))
+	for _, kv := range encoded {
+		out = append(out, kv[0]...)
+		out = append(out, kv[1]...)
+	}
+	return out
+}
+
+func encode(v interface{}) []byte {
+	switch v := v.(type) {
+	case int64:
+		if v < 0 {
+			return head(1, uint64(-1-v))
+		}
+		return head(0, uint64(v))
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case []pair:
+		return encodeMap(v)
+	}
+	panic("webauthntest: cannot encode value")
+}
+
+func head(major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return []byte{major<<5 | byte(arg)}
+	case arg <= 0xff:
+		return []byte{major<<5 | 24, byte(arg)}
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(arg))
+	case arg <= 0xffffffff:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(arg))
+	}
+	return binary.BigEndian.AppendUint64([]byte{major<<5 | 27}, arg)
+}
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+)
+
+// balanceSnapshotBatchSize is how many accounts one query of a run snapshots.
+const balanceSnapshotBatchSize = 100
+
+// BalanceSnapshotStore is the part of db.Store the snapshotter needs.
+type BalanceSnapshotStore interface {
+	CreateBalanceSnapshots(ctx context.Context, arg db.CreateBalanceSnapshotsParams) ([]int64, error)
+}
+
+// BalanceSnapshotter records the closing balance of every account once a day
+// is over, so statements, balance history and interest only read the entries
+// made since the last snapshot.
+type BalanceSnapshotter struct {
+	store    BalanceSnapshotStore
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewBalanceSnapshotter(store BalanceSnapshotStore, interval time.Duration) *BalanceSnapshotter {
+	return &BalanceSnapshotter{
+		store:    store,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// RunOnce snapshots yesterday, in UTC. Taking a day again replaces its
+// snapshots, so it is safe to run more than once a day.
+func (snapshotter *BalanceSnapshotter) RunOnce(ctx context.Context) (int, error) {
+	today := snapshotter.now().UTC().Truncate(24 * time.Hour)
+	return snapshotter.SnapshotDay(ctx, today.AddDate(0, 0, -1))
+}
+
+// SnapshotDay snapshots the closing balances of day and returns how many
+// accounts it snapshotted. Any day that is over can be taken, e.g. to
+// backfill the days before snapshots were taken.
+func (snapshotter *BalanceSnapshotter) SnapshotDay(ctx context.Context, day time.Time) (int, error) {
+	snapshotted := 0
+	var afterID int64
+	for {
+		accountIDs, err := snapshotter.store.CreateBalanceSnapshots(ctx, db.CreateBalanceSnapshotsParams{
+			Day:       day,
+			AfterID:   afterID,
+			PageLimit: balanceSnapshotBatchSize,
+		})
+		if err != nil {
+			return snapshotted, err
+		}
+		snapshotted += len(accountIDs)
+
+		if len(accountIDs) < balanceSnapshotBatchSize {
+			return snapshotted, nil
+		}
+		// Inserted rows are returned in no particular order.
+		for _, id := range accountIDs {
+			afterID = max(afterID, id)
+		}
+	}
+}
+
+// Start runs the snapshotter every interval until ctx is done.
+func (snapshotter *BalanceSnapshotter) Start(ctx context.Context) {
+	ticker := time.NewTicker(snapshotter.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := snapshotter.RunOnce(ctx); err != nil {
+				slog.Error("balance snapshot failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceSnapshotterRunOnce(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 30, 0, 0, time.UTC)
+	yesterday := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fullPage := make([]int64, balanceSnapshotBatchSize)
+	for i := range fullPage {
+		fullPage[i] = int64(balanceSnapshotBatchSize - i)
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().CreateBalanceSnapshots(gomock.Any(), gomock.Eq(db.CreateBalanceSnapshotsParams{
+			Day:       yesterday,
+			PageLimit: balanceSnapshotBatchSize,
+		})).Times(1).Return(fullPage, nil),
+		// The next page starts after the highest ID, whatever order the IDs
+		// came back in.
+		store.EXPECT().CreateBalanceSnapshots(gomock.Any(), gomock.Eq(db.CreateBalanceSnapshotsParams{
+			Day:       yesterday,
+			AfterID:   balanceSnapshotBatchSize,
+			PageLimit: balanceSnapshotBatchSize,
+		})).Times(1).Return([]int64{101, 102}, nil),
+	)
+
+	snapshotter := NewBalanceSnapshotter(store, time.Hour)
+	snapshotter.now = func() time.Time { return now }
+
+	n, err := snapshotter.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, balanceSnapshotBatchSize+2, n)
+}
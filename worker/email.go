@@ -0,0 +1,119 @@
+// Package worker runs background jobs queued in the database.
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/mail"
+)
+
+const (
+	// emailBatchSize is how many jobs one poll claims.
+	emailBatchSize = 10
+	// emailLease is how long a claimed job is hidden from other workers. A
+	// worker that dies mid-send leaves the job to be retried after it.
+	emailLease = 2 * time.Minute
+	// emailMaxAttempts is how often a job is tried before it is marked failed.
+	emailMaxAttempts = 5
+	// emailRetryBase is the first retry delay; it doubles on every attempt.
+	emailRetryBase = 30 * time.Second
+)
+
+// Store is the part of db.Store the email processor needs.
+type Store interface {
+	ClaimEmailJobs(ctx context.Context, arg db.ClaimEmailJobsParams) ([]db.EmailJob, error)
+	MarkEmailJobSent(ctx context.Context, id int64) error
+	MarkEmailJobFailed(ctx context.Context, arg db.MarkEmailJobFailedParams) error
+}
+
+// EmailProcessor delivers queued emails, retrying failures with exponential
+// backoff.
+type EmailProcessor struct {
+	store    Store
+	sender   mail.Sender
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewEmailProcessor(store Store, sender mail.Sender, interval time.Duration) *EmailProcessor {
+	return &EmailProcessor{
+		store:    store,
+		sender:   sender,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// RunOnce claims a batch of due jobs and tries to send each of them. It
+// returns how many were sent.
+func (processor *EmailProcessor) RunOnce(ctx context.Context) (int, error) {
+	now := processor.now()
+	jobs, err := processor.store.ClaimEmailJobs(ctx, db.ClaimEmailJobsParams{
+		LockedUntil: now.Add(emailLease),
+		Now:         now,
+		BatchSize:   emailBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, job := range jobs {
+		err := processor.sender.Send(ctx, mail.Message{
+			To:      job.Recipient,
+			Subject: job.Subject,
+			Body:    job.Body,
+		})
+		if err != nil {
+			processor.fail(ctx, job, err)
+			continue
+		}
+
+		if err := processor.store.MarkEmailJobSent(ctx, job.ID); err != nil {
+			// The email went out; at worst it is sent again after the lease.
+			slog.Warn("cannot mark email job sent", "email_job_id", job.ID, "error", err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (processor *EmailProcessor) fail(ctx context.Context, job db.EmailJob, sendErr error) {
+	status := db.EmailJobPending
+	if job.Attempts >= emailMaxAttempts {
+		status = db.EmailJobFailed
+	}
+	slog.Warn("email job failed", "email_job_id", job.ID, "attempt", job.Attempts, "status", status, "error", sendErr)
+
+	err := processor.store.MarkEmailJobFailed(ctx, db.MarkEmailJobFailedParams{
+		Status:    status,
+		LastError: sql.NullString{String: sendErr.Error(), Valid: true},
+		RetryAt:   processor.now().Add(emailRetryBase << (job.Attempts - 1)),
+		ID:        job.ID,
+	})
+	if err != nil {
+		slog.Error("cannot record failure of email job", "email_job_id", job.ID, "error", err)
+	}
+}
+
+// Start polls for jobs every interval until ctx is done.
+func (processor *EmailProcessor) Start(ctx context.Context) {
+	ticker := time.NewTicker(processor.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := processor.RunOnce(ctx); err != nil {
+				slog.Error("email worker poll failed", "error", err)
+			}
+		}
+	}
+}
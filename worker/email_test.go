@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/mail"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSender struct {
+	mu   sync.Mutex
+	sent []mail.Message
+	err  error
+}
+
+func (sender *fakeSender) Send(ctx context.Context, msg mail.Message) error {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	if sender.err != nil {
+		return sender.err
+	}
+	sender.sent = append(sender.sent, msg)
+	return nil
+}
+
+func TestEmailProcessorRunOnce(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	job := db.EmailJob{
+		ID:        7,
+		Recipient: "alice@example.com",
+		Subject:   "Statement",
+		Body:      "body",
+		Attempts:  1,
+	}
+
+	testCases := []struct {
+		name       string
+		attempts   int32
+		sendErr    error
+		buildStubs func(store *mockdb.MockStore)
+		checkSent  func(t *testing.T, sent int, sender *fakeSender)
+	}{
+		{
+			name:     "Sent",
+			attempts: 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkEmailJobSent(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(nil)
+				store.EXPECT().MarkEmailJobFailed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkSent: func(t *testing.T, sent int, sender *fakeSender) {
+				require.Equal(t, 1, sent)
+				require.Equal(t, []mail.Message{{To: job.Recipient, Subject: job.Subject, Body: job.Body}}, sender.sent)
+			},
+		},
+		{
+			name:     "RetryLater",
+			attempts: 2,
+			sendErr:  errors.New("relay unavailable"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkEmailJobSent(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().MarkEmailJobFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkEmailJobFailedParams) error {
+						require.Equal(t, db.EmailJobPending, arg.Status)
+						require.Equal(t, "relay unavailable", arg.LastError.String)
+						require.Equal(t, now.Add(2*emailRetryBase), arg.RetryAt)
+						return nil
+					})
+			},
+			checkSent: func(t *testing.T, sent int, sender *fakeSender) {
+				require.Zero(t, sent)
+			},
+		},
+		{
+			name:     "GiveUp",
+			attempts: emailMaxAttempts,
+			sendErr:  errors.New("mailbox unavailable"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkEmailJobFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkEmailJobFailedParams) error {
+						require.Equal(t, db.EmailJobFailed, arg.Status)
+						return nil
+					})
+			},
+			checkSent: func(t *testing.T, sent int, sender *fakeSender) {
+				require.Zero(t, sent)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			claimed := job
+			claimed.Attempts = tc.attempts
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().ClaimEmailJobs(gomock.Any(), gomock.Eq(db.ClaimEmailJobsParams{
+				LockedUntil: now.Add(emailLease),
+				Now:         now,
+				BatchSize:   emailBatchSize,
+			})).
+				Times(1).
+				Return([]db.EmailJob{claimed}, nil)
+			tc.buildStubs(store)
+
+			sender := &fakeSender{err: tc.sendErr}
+			processor := NewEmailProcessor(store, sender, time.Minute)
+			processor.now = func() time.Time { return now }
+
+			sent, err := processor.RunOnce(context.Background())
+			require.NoError(t, err)
+			tc.checkSent(t, sent, sender)
+		})
+	}
+}
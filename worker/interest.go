@@ -0,0 +1,186 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/settings"
+)
+
+// interestBatchSize is how many accounts one query of a run reads.
+const interestBatchSize = 100
+
+// InterestStore is the part of db.Store the interest processor needs.
+type InterestStore interface {
+	ListInterestEligibleAccounts(ctx context.Context, arg db.ListInterestEligibleAccountsParams) ([]db.ListInterestEligibleAccountsRow, error)
+	CreateInterestAccrual(ctx context.Context, arg db.CreateInterestAccrualParams) (int64, error)
+	ListAccountsWithUnpostedInterest(ctx context.Context, arg db.ListAccountsWithUnpostedInterestParams) ([]int64, error)
+	PostInterestTx(ctx context.Context, arg db.PostInterestTxParams) (db.PostInterestTxResult, error)
+}
+
+// InterestRates resolves the yearly interest rate of a tenant and currency,
+// see settings.KeyInterestRateBps.
+type InterestRates interface {
+	Int64(ctx context.Context, tenant, currency, key string, def int64) (int64, error)
+}
+
+// InterestProcessor accrues interest every day on the balance of each account
+// and posts what accrued in a month once the month is over.
+type InterestProcessor struct {
+	store    InterestStore
+	rates    InterestRates
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewInterestProcessor(store InterestStore, rates InterestRates, interval time.Duration) *InterestProcessor {
+	return &InterestProcessor{
+		store:    store,
+		rates:    rates,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// InterestRun counts what one run did.
+type InterestRun struct {
+	Accrued int
+	Posted  int
+}
+
+// RunOnce accrues interest for yesterday, in UTC, on the closing balance of
+// accounts when yesterday has been snapshotted and on the balance they have
+// now otherwise, and posts the interest of months before the current one.
+// Days already accrued and accruals already posted are skipped, so it is safe
+// to run more than once a day. A day the processor doesn't run on is not
+// accrued later.
+func (processor *InterestProcessor) RunOnce(ctx context.Context) (InterestRun, error) {
+	var run InterestRun
+
+	today := processor.now().UTC().Truncate(24 * time.Hour)
+	accrued, err := processor.accrue(ctx, today.AddDate(0, 0, -1))
+	run.Accrued = accrued
+	if err != nil {
+		return run, err
+	}
+
+	monthStart := today.AddDate(0, 0, 1-today.Day())
+	run.Posted, err = processor.post(ctx, monthStart)
+	return run, err
+}
+
+func (processor *InterestProcessor) accrue(ctx context.Context, day time.Time) (int, error) {
+	accrued := 0
+	var afterID int64
+	for {
+		accounts, err := processor.store.ListInterestEligibleAccounts(ctx, db.ListInterestEligibleAccountsParams{
+			Day:       day,
+			AfterID:   afterID,
+			PageLimit: interestBatchSize,
+		})
+		if err != nil {
+			return accrued, err
+		}
+
+		for _, account := range accounts {
+			afterID = account.ID
+			if account.Balance <= 0 {
+				continue
+			}
+			rate, err := processor.rates.Int64(ctx, account.Tenant, account.Currency, settings.KeyInterestRateBps, 0)
+			if err != nil {
+				return accrued, err
+			}
+			if rate <= 0 {
+				continue
+			}
+
+			n, err := processor.store.CreateInterestAccrual(ctx, db.CreateInterestAccrualParams{
+				AccountID:    account.ID,
+				AccrualDate:  day,
+				Balance:      account.Balance,
+				RateBps:      rate,
+				AmountMicros: dailyInterestMicros(account.Balance, rate, day.Year()),
+			})
+			if err != nil {
+				slog.Error("cannot accrue interest", "account_id", account.ID, "day", day.Format("2006-01-02"), "error", err)
+				continue
+			}
+			accrued += int(n)
+		}
+
+		if len(accounts) < interestBatchSize {
+			return accrued, nil
+		}
+	}
+}
+
+func (processor *InterestProcessor) post(ctx context.Context, before time.Time) (int, error) {
+	posted := 0
+	var afterID int64
+	for {
+		accountIDs, err := processor.store.ListAccountsWithUnpostedInterest(ctx, db.ListAccountsWithUnpostedInterestParams{
+			Before:    before,
+			AfterID:   afterID,
+			PageLimit: interestBatchSize,
+		})
+		if err != nil {
+			return posted, err
+		}
+
+		for _, accountID := range accountIDs {
+			afterID = accountID
+			result, err := processor.store.PostInterestTx(ctx, db.PostInterestTxParams{
+				AccountID: accountID,
+				Before:    before,
+			})
+			if err != nil {
+				// Closed accounts keep their accruals, and a closed period
+				// holds everything back until it is reopened.
+				if errors.Is(err, db.ErrPeriodClosed) {
+					return posted, err
+				}
+				slog.Error("cannot post interest", "account_id", accountID, "error", err)
+				continue
+			}
+			if result.Entry != nil {
+				posted++
+			}
+		}
+
+		if len(accountIDs) < interestBatchSize {
+			return posted, nil
+		}
+	}
+}
+
+// dailyInterestMicros is the interest one day earns on balance at a yearly
+// rate in basis points, in millionths of a unit and rounded down.
+func dailyInterestMicros(balance, rateBps int64, year int) int64 {
+	days := int64(365)
+	if time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay() == 366 {
+		days = 366
+	}
+	// A basis point is a ten-thousandth, so rate/10_000*1_000_000 = rate*100.
+	return balance * rateBps * (db.MicrosPerUnit / 10_000) / days
+}
+
+// Start runs the processor every interval until ctx is done.
+func (processor *InterestProcessor) Start(ctx context.Context) {
+	ticker := time.NewTicker(processor.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := processor.RunOnce(ctx); err != nil {
+				slog.Error("interest run failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedRates gives every account the rate of its currency.
+type fixedRates map[string]int64
+
+func (rates fixedRates) Int64(_ context.Context, _, currency, _ string, def int64) (int64, error) {
+	if rate, ok := rates[currency]; ok {
+		return rate, nil
+	}
+	return def, nil
+}
+
+func TestInterestProcessorRunOnce(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 30, 0, 0, time.UTC)
+	yesterday := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListInterestEligibleAccounts(gomock.Any(), gomock.Eq(db.ListInterestEligibleAccountsParams{
+		Day:       yesterday,
+		PageLimit: interestBatchSize,
+	})).Times(1).Return([]db.ListInterestEligibleAccountsRow{
+		{ID: 1, Currency: "USD", Balance: 366_000},
+		{ID: 2, Currency: "USD", Balance: 0},
+		{ID: 3, Currency: "INR", Balance: 500},
+	}, nil)
+	// 2024 is a leap year: 366000 at 5% earns 50 a day.
+	store.EXPECT().CreateInterestAccrual(gomock.Any(), gomock.Eq(db.CreateInterestAccrualParams{
+		AccountID:    1,
+		AccrualDate:  yesterday,
+		Balance:      366_000,
+		RateBps:      500,
+		AmountMicros: 50 * db.MicrosPerUnit,
+	})).Times(1).Return(int64(1), nil)
+
+	store.EXPECT().ListAccountsWithUnpostedInterest(gomock.Any(), gomock.Eq(db.ListAccountsWithUnpostedInterestParams{
+		Before:    monthStart,
+		PageLimit: interestBatchSize,
+	})).Times(1).Return([]int64{1, 4}, nil)
+	store.EXPECT().PostInterestTx(gomock.Any(), gomock.Eq(db.PostInterestTxParams{AccountID: 1, Before: monthStart})).
+		Times(1).
+		Return(db.PostInterestTxResult{Entry: &db.Entry{AccountID: 1, Amount: 1450}}, nil)
+	// Less than a unit accrued: nothing is posted yet.
+	store.EXPECT().PostInterestTx(gomock.Any(), gomock.Eq(db.PostInterestTxParams{AccountID: 4, Before: monthStart})).
+		Times(1).
+		Return(db.PostInterestTxResult{}, nil)
+
+	processor := NewInterestProcessor(store, fixedRates{"USD": 500}, time.Hour)
+	processor.now = func() time.Time { return now }
+
+	run, err := processor.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, InterestRun{Accrued: 1, Posted: 1}, run)
+}
+
+func TestDailyInterestMicros(t *testing.T) {
+	require.Equal(t, int64(50*1_000_000), dailyInterestMicros(365_000, 500, 2023))
+	require.Equal(t, int64(49_863_013), dailyInterestMicros(364_000, 500, 2023))
+	require.Equal(t, int64(136), dailyInterestMicros(1, 500, 2023))
+}
@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/sms"
+)
+
+const (
+	// notificationBatchSize is how many notifications one poll claims.
+	notificationBatchSize = 10
+	// notificationLease is how long a claimed notification is hidden from
+	// other workers.
+	notificationLease = 2 * time.Minute
+	// notificationMaxAttempts is how often a notification is tried, counting
+	// the attempt made when it was generated, before it is marked failed.
+	notificationMaxAttempts = 5
+	// notificationRetryBase is the first retry delay; it doubles on every
+	// attempt.
+	notificationRetryBase = 30 * time.Second
+)
+
+// NotificationStore is the part of db.Store the notification processor needs.
+type NotificationStore interface {
+	ClaimNotifications(ctx context.Context, arg db.ClaimNotificationsParams) ([]db.Notification, error)
+	MarkNotificationSent(ctx context.Context, id int64) error
+	MarkNotificationFailed(ctx context.Context, arg db.MarkNotificationFailedParams) error
+}
+
+// NotificationProcessor retries the deliveries of notifications that could
+// not be sent when they were generated, with exponential backoff.
+type NotificationProcessor struct {
+	store    NotificationStore
+	sms      sms.Sender
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewNotificationProcessor(store NotificationStore, sender sms.Sender, interval time.Duration) *NotificationProcessor {
+	return &NotificationProcessor{
+		store:    store,
+		sms:      sender,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// RunOnce claims a batch of due notifications and tries to deliver each of
+// them. It returns how many were sent.
+func (processor *NotificationProcessor) RunOnce(ctx context.Context) (int, error) {
+	now := processor.now()
+	notifications, err := processor.store.ClaimNotifications(ctx, db.ClaimNotificationsParams{
+		LockedUntil: now.Add(notificationLease),
+		Now:         now,
+		BatchSize:   notificationBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, notification := range notifications {
+		if err := processor.deliver(ctx, notification); err != nil {
+			processor.fail(ctx, notification, err)
+			continue
+		}
+
+		if err := processor.store.MarkNotificationSent(ctx, notification.ID); err != nil {
+			// The notification went out; at worst it is sent again after the
+			// lease.
+			slog.Warn("cannot mark notification sent", "notification_id", notification.ID, "error", err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (processor *NotificationProcessor) deliver(ctx context.Context, notification db.Notification) error {
+	switch notification.Channel {
+	case notify.ChannelSMS:
+		var payload db.SMSNotificationPayload
+		if err := json.Unmarshal(notification.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		return processor.sms.Send(ctx, sms.Message{To: payload.To, Body: payload.Body})
+	default:
+		return fmt.Errorf("cannot deliver on channel %q", notification.Channel)
+	}
+}
+
+func (processor *NotificationProcessor) fail(ctx context.Context, notification db.Notification, sendErr error) {
+	status := db.NotificationPending
+	if notification.Attempts >= notificationMaxAttempts {
+		status = db.NotificationFailed
+	}
+	slog.Warn("notification delivery failed", "notification_id", notification.ID, "attempt", notification.Attempts, "status", status, "error", sendErr)
+
+	err := processor.store.MarkNotificationFailed(ctx, db.MarkNotificationFailedParams{
+		Status:    status,
+		LastError: sql.NullString{String: sendErr.Error(), Valid: true},
+		RetryAt:   processor.now().Add(notificationRetryBase << (notification.Attempts - 1)),
+		ID:        notification.ID,
+	})
+	if err != nil {
+		slog.Error("cannot record failure of notification", "notification_id", notification.ID, "error", err)
+	}
+}
+
+// Start polls for notifications every interval until ctx is done.
+func (processor *NotificationProcessor) Start(ctx context.Context) {
+	ticker := time.NewTicker(processor.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := processor.RunOnce(ctx); err != nil {
+				slog.Error("notification worker poll failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/notify"
+	"github.com/ankurdas111111/simplebank/sms"
+	mocksms "github.com/ankurdas111111/simplebank/sms/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationProcessorRunOnce(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	notification := db.Notification{
+		ID:      4,
+		Channel: notify.ChannelSMS,
+		Type:    notify.SecurityAlert,
+		Payload: []byte(`{"to":"+15551234567","body":"alert"}`),
+	}
+
+	testCases := []struct {
+		name       string
+		attempts   int32
+		sendErr    error
+		buildStubs func(store *mockdb.MockStore)
+		wantSent   int
+	}{
+		{
+			name:     "Sent",
+			attempts: 2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationSent(gomock.Any(), gomock.Eq(notification.ID)).Times(1).Return(nil)
+				store.EXPECT().MarkNotificationFailed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantSent: 1,
+		},
+		{
+			name:     "RetryLater",
+			attempts: 3,
+			sendErr:  errors.New("provider unavailable"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationSent(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().MarkNotificationFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkNotificationFailedParams) error {
+						require.Equal(t, db.NotificationPending, arg.Status)
+						require.Equal(t, "provider unavailable", arg.LastError.String)
+						require.Equal(t, now.Add(4*notificationRetryBase), arg.RetryAt)
+						return nil
+					})
+			},
+		},
+		{
+			name:     "GiveUp",
+			attempts: notificationMaxAttempts,
+			sendErr:  errors.New("provider unavailable"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkNotificationFailedParams) error {
+						require.Equal(t, db.NotificationFailed, arg.Status)
+						return nil
+					})
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			claimed := notification
+			claimed.Attempts = tc.attempts
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().ClaimNotifications(gomock.Any(), gomock.Eq(db.ClaimNotificationsParams{
+				LockedUntil: now.Add(notificationLease),
+				Now:         now,
+				BatchSize:   notificationBatchSize,
+			})).
+				Times(1).
+				Return([]db.Notification{claimed}, nil)
+			tc.buildStubs(store)
+
+			sender := mocksms.NewMockSender(ctrl)
+			sender.EXPECT().Send(gomock.Any(), gomock.Eq(sms.Message{To: "+15551234567", Body: "alert"})).
+				Times(1).
+				Return(tc.sendErr)
+
+			processor := NewNotificationProcessor(store, sender, time.Minute)
+			processor.now = func() time.Time { return now }
+
+			sent, err := processor.RunOnce(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.wantSent, sent)
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+)
+
+const (
+	// scheduledTransferBatchSize is how many due transfers one poll claims.
+	scheduledTransferBatchSize = 20
+	// scheduledTransferLease is how long a claimed transfer is hidden from
+	// other schedulers, and from cancellation.
+	scheduledTransferLease = time.Minute
+	// scheduledTransferMaxAttempts is how often a transfer that keeps hitting
+	// transient errors is tried before it is marked failed.
+	scheduledTransferMaxAttempts = 5
+	// scheduledTransferRetryBase is the first retry delay; it doubles on every
+	// attempt.
+	scheduledTransferRetryBase = 30 * time.Second
+)
+
+var errScheduledTransferChanged = errors.New("accounts changed since the transfer was scheduled")
+
+// ScheduledTransferStore is the part of db.Store the scheduler needs.
+type ScheduledTransferStore interface {
+	ClaimDueScheduledTransfers(ctx context.Context, arg db.ClaimDueScheduledTransfersParams) ([]db.ScheduledTransfer, error)
+	MarkScheduledTransferSucceeded(ctx context.Context, arg db.MarkScheduledTransferSucceededParams) error
+	MarkScheduledTransferFailed(ctx context.Context, arg db.MarkScheduledTransferFailedParams) error
+	GetAccount(ctx context.Context, id int64) (db.Account, error)
+	GetIdempotencyKey(ctx context.Context, arg db.GetIdempotencyKeyParams) (db.IdempotencyKey, error)
+	TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error)
+}
+
+// ScheduledTransferProcessor executes scheduled transfers once they are due.
+type ScheduledTransferProcessor struct {
+	store    ScheduledTransferStore
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewScheduledTransferProcessor(store ScheduledTransferStore, interval time.Duration) *ScheduledTransferProcessor {
+	return &ScheduledTransferProcessor{
+		store:    store,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// permanentError marks a run that cannot succeed by retrying it.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+
+func (e permanentError) Unwrap() error { return e.err }
+
+// RunOnce claims a batch of due transfers and executes each of them. It
+// returns how many succeeded.
+func (processor *ScheduledTransferProcessor) RunOnce(ctx context.Context) (int, error) {
+	now := processor.now()
+	due, err := processor.store.ClaimDueScheduledTransfers(ctx, db.ClaimDueScheduledTransfersParams{
+		LockedUntil: now.Add(scheduledTransferLease),
+		Now:         now,
+		BatchSize:   scheduledTransferBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	succeeded := 0
+	for _, scheduled := range due {
+		transfer, err := processor.execute(ctx, scheduled)
+		if err != nil {
+			processor.fail(ctx, scheduled, err)
+			continue
+		}
+
+		err = processor.store.MarkScheduledTransferSucceeded(ctx, db.MarkScheduledTransferSucceededParams{
+			TransferID: sql.NullInt64{Int64: transfer.ID, Valid: true},
+			ID:         scheduled.ID,
+		})
+		if err != nil {
+			// The money moved; the next attempt finds the idempotency key
+			// used and only records the outcome.
+			slog.Warn("cannot mark scheduled transfer succeeded", "scheduled_transfer_id", scheduled.ID, "error", err)
+			continue
+		}
+		succeeded++
+	}
+	return succeeded, nil
+}
+
+// execute checks the accounts again, since they may have changed since the
+// transfer was scheduled, and moves the money.
+func (processor *ScheduledTransferProcessor) execute(ctx context.Context, scheduled db.ScheduledTransfer) (db.Transfer, error) {
+	fromAccount, err := processor.store.GetAccount(ctx, scheduled.FromAccountID)
+	if err != nil {
+		return db.Transfer{}, err
+	}
+	toAccount, err := processor.store.GetAccount(ctx, scheduled.ToAccountID)
+	if err != nil {
+		return db.Transfer{}, err
+	}
+	if fromAccount.Owner != scheduled.Owner || fromAccount.Currency != toAccount.Currency {
+		return db.Transfer{}, permanentError{errScheduledTransferChanged}
+	}
+	if fromAccount.Available() < scheduled.Amount {
+		return db.Transfer{}, permanentError{db.ErrInsufficientFunds}
+	}
+
+	idempotency := db.ScheduledTransferIdempotency(scheduled)
+	result, err := processor.store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID: scheduled.FromAccountID,
+		ToAccountID:   scheduled.ToAccountID,
+		Amount:        scheduled.Amount,
+		Idempotency:   idempotency,
+	})
+	switch {
+	case err == nil:
+		return result.Transfer, nil
+	case errors.Is(err, db.ErrIdempotencyKeyUsed):
+		return processor.previousTransfer(ctx, idempotency)
+	case errors.Is(err, db.ErrPeriodClosed), errors.Is(err, db.ErrTransferLimitExceeded), errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrAccountClosed), errors.Is(err, db.ErrInsufficientFunds):
+		return db.Transfer{}, permanentError{err}
+	}
+	return db.Transfer{}, err
+}
+
+// previousTransfer recovers the transfer an earlier attempt made but could
+// not record.
+func (processor *ScheduledTransferProcessor) previousTransfer(ctx context.Context, idempotency db.ClaimIdempotencyKeyParams) (db.Transfer, error) {
+	key, err := processor.store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Username: idempotency.Username,
+		Key:      idempotency.Key,
+	})
+	if err != nil {
+		return db.Transfer{}, err
+	}
+	if key.RequestHash != idempotency.RequestHash {
+		return db.Transfer{}, permanentError{fmt.Errorf("%w by another request", db.ErrIdempotencyKeyUsed)}
+	}
+
+	var result db.TransferTxResult
+	if err := json.Unmarshal(key.Response, &result); err != nil {
+		return db.Transfer{}, err
+	}
+	return result.Transfer, nil
+}
+
+func (processor *ScheduledTransferProcessor) fail(ctx context.Context, scheduled db.ScheduledTransfer, runErr error) {
+	status := db.ScheduledTransferPending
+	var permanent permanentError
+	if errors.As(runErr, &permanent) || scheduled.Attempts >= scheduledTransferMaxAttempts {
+		status = db.ScheduledTransferFailed
+	}
+	slog.Warn("scheduled transfer failed", "scheduled_transfer_id", scheduled.ID, "attempt", scheduled.Attempts, "status", status, "error", runErr)
+
+	err := processor.store.MarkScheduledTransferFailed(ctx, db.MarkScheduledTransferFailedParams{
+		Status:    status,
+		LastError: sql.NullString{String: runErr.Error(), Valid: true},
+		RetryAt:   processor.now().Add(scheduledTransferRetryBase << (scheduled.Attempts - 1)),
+		ID:        scheduled.ID,
+	})
+	if err != nil {
+		slog.Error("cannot record failure of scheduled transfer", "scheduled_transfer_id", scheduled.ID, "error", err)
+	}
+}
+
+// Start polls for due transfers every interval until ctx is done.
+func (processor *ScheduledTransferProcessor) Start(ctx context.Context) {
+	ticker := time.NewTicker(processor.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := processor.RunOnce(ctx); err != nil {
+				slog.Error("scheduled transfer poll failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledTransferProcessorRunOnce(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	scheduled := db.ScheduledTransfer{
+		ID:            7,
+		Owner:         "alice",
+		FromAccountID: 1,
+		ToAccountID:   2,
+		Amount:        50,
+		Status:        db.ScheduledTransferPending,
+	}
+	fromAccount := db.Account{ID: 1, Owner: "alice", Balance: 100, Currency: "INR"}
+	toAccount := db.Account{ID: 2, Owner: "bob", Currency: "INR"}
+	idempotency := db.ScheduledTransferIdempotency(scheduled)
+	transfer := db.Transfer{ID: 21, FromAccountID: 1, ToAccountID: 2, Amount: 50}
+
+	accountStubs := func(store *mockdb.MockStore, from db.Account) {
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	}
+	expectFailed := func(store *mockdb.MockStore, status string, lastErr string) {
+		store.EXPECT().MarkScheduledTransferSucceeded(gomock.Any(), gomock.Any()).Times(0)
+		store.EXPECT().MarkScheduledTransferFailed(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, arg db.MarkScheduledTransferFailedParams) error {
+				require.Equal(t, scheduled.ID, arg.ID)
+				require.Equal(t, status, arg.Status)
+				require.Contains(t, arg.LastError.String, lastErr)
+				return nil
+			})
+	}
+
+	testCases := []struct {
+		name          string
+		attempts      int32
+		buildStubs    func(store *mockdb.MockStore)
+		wantSucceeded int
+	}{
+		{
+			name:     "Executed",
+			attempts: 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				accountStubs(store, fromAccount)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(db.TransferTxParams{
+					FromAccountID: scheduled.FromAccountID,
+					ToAccountID:   scheduled.ToAccountID,
+					Amount:        scheduled.Amount,
+					Idempotency:   idempotency,
+				})).
+					Times(1).
+					Return(db.TransferTxResult{Transfer: transfer}, nil)
+				store.EXPECT().MarkScheduledTransferSucceeded(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkScheduledTransferSucceededParams) error {
+						require.Equal(t, scheduled.ID, arg.ID)
+						require.Equal(t, transfer.ID, arg.TransferID.Int64)
+						return nil
+					})
+			},
+			wantSucceeded: 1,
+		},
+		{
+			name:     "AlreadyExecuted",
+			attempts: 2,
+			buildStubs: func(store *mockdb.MockStore) {
+				accountStubs(store, fromAccount)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrIdempotencyKeyUsed)
+
+				response, err := json.Marshal(db.TransferTxResult{Transfer: transfer})
+				require.NoError(t, err)
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(db.GetIdempotencyKeyParams{
+					Username: idempotency.Username,
+					Key:      idempotency.Key,
+				})).
+					Times(1).
+					Return(db.IdempotencyKey{RequestHash: idempotency.RequestHash, Response: response}, nil)
+				store.EXPECT().MarkScheduledTransferSucceeded(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkScheduledTransferSucceededParams) error {
+						require.Equal(t, transfer.ID, arg.TransferID.Int64)
+						return nil
+					})
+			},
+			wantSucceeded: 1,
+		},
+		{
+			name:     "InsufficientFunds",
+			attempts: 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				poor := fromAccount
+				poor.Balance = 10
+				accountStubs(store, poor)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+				expectFailed(store, db.ScheduledTransferFailed, db.ErrInsufficientFunds.Error())
+			},
+		},
+		{
+			name:     "OwnerChanged",
+			attempts: 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				moved := fromAccount
+				moved.Owner = "mallory"
+				accountStubs(store, moved)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+				expectFailed(store, db.ScheduledTransferFailed, errScheduledTransferChanged.Error())
+			},
+		},
+		{
+			name:     "PeriodClosed",
+			attempts: 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				accountStubs(store, fromAccount)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrPeriodClosed)
+				expectFailed(store, db.ScheduledTransferFailed, db.ErrPeriodClosed.Error())
+			},
+		},
+		{
+			name:     "RetryLater",
+			attempts: 2,
+			buildStubs: func(store *mockdb.MockStore) {
+				accountStubs(store, fromAccount)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, errors.New("connection reset"))
+				store.EXPECT().MarkScheduledTransferFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkScheduledTransferFailedParams) error {
+						require.Equal(t, db.ScheduledTransferPending, arg.Status)
+						require.Equal(t, now.Add(2*scheduledTransferRetryBase), arg.RetryAt)
+						return nil
+					})
+			},
+		},
+		{
+			name:     "GiveUp",
+			attempts: scheduledTransferMaxAttempts,
+			buildStubs: func(store *mockdb.MockStore) {
+				accountStubs(store, fromAccount)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, errors.New("connection reset"))
+				expectFailed(store, db.ScheduledTransferFailed, "connection reset")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			claimed := scheduled
+			claimed.Attempts = tc.attempts
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().ClaimDueScheduledTransfers(gomock.Any(), gomock.Eq(db.ClaimDueScheduledTransfersParams{
+				LockedUntil: now.Add(scheduledTransferLease),
+				Now:         now,
+				BatchSize:   scheduledTransferBatchSize,
+			})).
+				Times(1).
+				Return([]db.ScheduledTransfer{claimed}, nil)
+			tc.buildStubs(store)
+
+			processor := NewScheduledTransferProcessor(store, time.Minute)
+			processor.now = func() time.Time { return now }
+
+			succeeded, err := processor.RunOnce(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.wantSucceeded, succeeded)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/ankurdas111111/simplebank/util"
+)
+
+const (
+	// webhookBatchSize is how many deliveries one poll claims.
+	webhookBatchSize = 20
+	// webhookTimeout bounds how long a subscriber may take to answer.
+	webhookTimeout = 10 * time.Second
+	// webhookLease is how long a claimed delivery is hidden from other
+	// workers. A batch is posted one delivery after another, so the lease
+	// must outlast every delivery of the batch timing out, or another worker
+	// claims the ones not tried yet and subscribers get them twice.
+	webhookLease = webhookBatchSize*webhookTimeout + time.Minute
+	// webhookMaxAttempts is how often a delivery is tried before it is marked
+	// failed. With the backoff below the last try is about eight hours after
+	// the first.
+	webhookMaxAttempts = 10
+	// webhookRetryBase is the first retry delay; it doubles on every attempt.
+	webhookRetryBase = time.Minute
+)
+
+// Headers sent with every delivery.
+const (
+	WebhookEventHeader     = "X-SimpleBank-Event"
+	WebhookDeliveryHeader  = "X-SimpleBank-Delivery"
+	WebhookSignatureHeader = "X-SimpleBank-Signature"
+)
+
+// WebhookStore is the part of db.Store the webhook processor needs.
+type WebhookStore interface {
+	ClaimWebhookDeliveries(ctx context.Context, arg db.ClaimWebhookDeliveriesParams) ([]db.ClaimWebhookDeliveriesRow, error)
+	MarkWebhookDeliveryDelivered(ctx context.Context, arg db.MarkWebhookDeliveryDeliveredParams) error
+	MarkWebhookDeliveryFailed(ctx context.Context, arg db.MarkWebhookDeliveryFailedParams) error
+}
+
+// WebhookProcessor POSTs queued events to subscribers, retrying failures with
+// exponential backoff. Any 2xx answer counts as delivered.
+type WebhookProcessor struct {
+	store    WebhookStore
+	client   *http.Client
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewWebhookProcessor(store WebhookStore, interval time.Duration) *WebhookProcessor {
+	return &WebhookProcessor{
+		store:    store,
+		client:   newWebhookClient(),
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// errWebhookAddress is returned for deliveries to a host that resolves to an
+// address that isn't public.
+var errWebhookAddress = errors.New("webhook host is not a public address")
+
+// newWebhookClient returns a client that only connects to public addresses.
+// Subscriptions are checked when they are created, but the check is made
+// again on the address actually dialed, as the host may resolve differently
+// by now. Proxies are ignored, since they would be the address dialed.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !util.IsPublicIP(net.ParseIP(host)) {
+				return errWebhookAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookTimeout,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// SignWebhook returns the signature header value for a payload sent at
+// timestamp: the hex HMAC-SHA256 of "timestamp.payload" with the
+// subscription secret. Signing the timestamp lets subscribers reject
+// replayed deliveries.
+func SignWebhook(secret string, timestamp time.Time, payload []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// RunOnce claims a batch of due deliveries and tries each of them. It
+// returns how many were delivered.
+func (processor *WebhookProcessor) RunOnce(ctx context.Context) (int, error) {
+	now := processor.now()
+	deliveries, err := processor.store.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+		LockedUntil: now.Add(webhookLease),
+		Now:         now,
+		BatchSize:   webhookBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		status, err := processor.post(ctx, delivery)
+		if err != nil {
+			processor.fail(ctx, delivery, status, err)
+			continue
+		}
+
+		err = processor.store.MarkWebhookDeliveryDelivered(ctx, db.MarkWebhookDeliveryDeliveredParams{
+			ResponseStatus: int32(status),
+			ID:             delivery.ID,
+		})
+		if err != nil {
+			// The event went out; at worst it is sent again after the lease.
+			slog.Warn("cannot mark webhook delivery delivered", "webhook_delivery_id", delivery.ID, "error", err)
+			continue
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// post sends one delivery. It returns the response status, or 0 when there
+// was no response.
+func (processor *WebhookProcessor) post(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(WebhookEventHeader, delivery.EventType)
+	request.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set(WebhookSignatureHeader, SignWebhook(delivery.Secret, processor.now(), delivery.Payload))
+
+	response, err := processor.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// Drain a little of the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 4096))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("subscriber answered %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
+
+func (processor *WebhookProcessor) fail(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow, responseStatus int, postErr error) {
+	status := db.WebhookDeliveryPending
+	if delivery.Attempts >= webhookMaxAttempts {
+		status = db.WebhookDeliveryFailed
+	}
+	slog.Warn("webhook delivery failed", "webhook_delivery_id", delivery.ID, "attempt", delivery.Attempts, "status", status, "error", postErr)
+
+	err := processor.store.MarkWebhookDeliveryFailed(ctx, db.MarkWebhookDeliveryFailedParams{
+		Status:         status,
+		ResponseStatus: sql.NullInt32{Int32: int32(responseStatus), Valid: responseStatus != 0},
+		LastError:      sql.NullString{String: postErr.Error(), Valid: true},
+		RetryAt:        processor.now().Add(webhookRetryBase << (delivery.Attempts - 1)),
+		ID:             delivery.ID,
+	})
+	if err != nil {
+		slog.Error("cannot record failure of webhook delivery", "webhook_delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// Start polls for deliveries every interval until ctx is done.
+func (processor *WebhookProcessor) Start(ctx context.Context) {
+	ticker := time.NewTicker(processor.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := processor.RunOnce(ctx); err != nil {
+				slog.Error("webhook worker poll failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ankurdas111111/simplebank/db/mock"
+	db "github.com/ankurdas111111/simplebank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWebhook(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	signature := SignWebhook("whsec_test", at, []byte(`{"event":"transfer.created"}`))
+	require.Regexp(t, `^t=1700000000,v1=[0-9a-f]{64}$`, signature)
+
+	require.Equal(t, signature, SignWebhook("whsec_test", at, []byte(`{"event":"transfer.created"}`)))
+	require.NotEqual(t, signature, SignWebhook("whsec_other", at, []byte(`{"event":"transfer.created"}`)))
+	require.NotEqual(t, signature, SignWebhook("whsec_test", at.Add(time.Second), []byte(`{"event":"transfer.created"}`)))
+}
+
+func TestWebhookProcessorRunOnce(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	payload := []byte(`{"event":"transfer.created","data":{}}`)
+
+	testCases := []struct {
+		name          string
+		attempts      int32
+		status        int
+		buildStubs    func(store *mockdb.MockStore)
+		wantDelivered int
+	}{
+		{
+			name:     "Delivered",
+			attempts: 1,
+			status:   http.StatusNoContent,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkWebhookDeliveryDelivered(gomock.Any(), gomock.Eq(db.MarkWebhookDeliveryDeliveredParams{
+					ResponseStatus: http.StatusNoContent,
+					ID:             7,
+				})).Times(1).Return(nil)
+				store.EXPECT().MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantDelivered: 1,
+		},
+		{
+			name:     "RetryLater",
+			attempts: 3,
+			status:   http.StatusInternalServerError,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkWebhookDeliveryDelivered(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) error {
+						require.Equal(t, db.WebhookDeliveryPending, arg.Status)
+						require.Equal(t, int32(http.StatusInternalServerError), arg.ResponseStatus.Int32)
+						require.True(t, arg.LastError.Valid)
+						require.Equal(t, now.Add(4*webhookRetryBase), arg.RetryAt)
+						return nil
+					})
+			},
+		},
+		{
+			name:     "GiveUp",
+			attempts: webhookMaxAttempts,
+			status:   http.StatusBadGateway,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) error {
+						require.Equal(t, db.WebhookDeliveryFailed, arg.Status)
+						return nil
+					})
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var received *http.Request
+			var body []byte
+			subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tc.status)
+			}))
+			defer subscriber.Close()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Eq(db.ClaimWebhookDeliveriesParams{
+				LockedUntil: now.Add(webhookLease),
+				Now:         now,
+				BatchSize:   webhookBatchSize,
+			})).
+				Times(1).
+				Return([]db.ClaimWebhookDeliveriesRow{{
+					ID:        7,
+					EventType: db.WebhookEventTransferCreated,
+					Payload:   payload,
+					Attempts:  tc.attempts,
+					Url:       subscriber.URL,
+					Secret:    "whsec_test",
+				}}, nil)
+			tc.buildStubs(store)
+
+			processor := NewWebhookProcessor(store, time.Minute)
+			processor.now = func() time.Time { return now }
+			// The subscriber listens on loopback, which the processor's
+			// own client refuses.
+			processor.client = subscriber.Client()
+
+			delivered, err := processor.RunOnce(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.wantDelivered, delivered)
+
+			require.NotNil(t, received)
+			require.Equal(t, http.MethodPost, received.Method)
+			require.Equal(t, payload, body)
+			require.Equal(t, db.WebhookEventTransferCreated, received.Header.Get(WebhookEventHeader))
+			require.Equal(t, "7", received.Header.Get(WebhookDeliveryHeader))
+			require.Equal(t, SignWebhook("whsec_test", now, payload), received.Header.Get(WebhookSignatureHeader))
+		})
+	}
+}
+
+func TestWebhookProcessorRefusesPrivateAddress(t *testing.T) {
+	received := false
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer subscriber.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.ClaimWebhookDeliveriesRow{{ID: 7, Attempts: 1, Url: subscriber.URL, Secret: "whsec_test"}}, nil)
+	store.EXPECT().MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) error {
+			require.Contains(t, arg.LastError.String, errWebhookAddress.Error())
+			require.False(t, arg.ResponseStatus.Valid)
+			return nil
+		})
+
+	delivered, err := NewWebhookProcessor(store, time.Minute).RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, delivered)
+	require.False(t, received)
+}